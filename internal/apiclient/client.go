@@ -106,7 +106,7 @@ func (c *Client) doWithHeaders(method, path string, body interface{}, extra map[
 		fmt.Fprintf(os.Stderr, "%s %s\n", method, url)
 	}
 
-	resp, err := c.http.Do(req)
+	resp, err := Do(c.http, req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -177,7 +177,7 @@ func ValidateToken(baseURL, token string) error {
 	req.Header.Set("Accept", "application/json")
 
 	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := Do(client, req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
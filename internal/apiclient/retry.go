@@ -0,0 +1,187 @@
+package apiclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+)
+
+// IdempotencyKeyHeader carries a key the API uses to apply a POST only
+// once, however many times it arrives.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultRetries is how many times a transient failure is retried when
+// neither DIBBLA_RETRIES nor a command flag says otherwise.
+const DefaultRetries = 3
+
+// Backoff bounds and the progress writer are variables so tests can run
+// without real sleeps or stderr noise.
+var (
+	retryBaseDelay           = 500 * time.Millisecond
+	retryMaxDelay            = 8 * time.Second
+	retryOut       io.Writer = os.Stderr
+)
+
+// Retries returns the retry budget from DIBBLA_RETRIES, falling back to
+// DefaultRetries when it is unset or not a non-negative integer. 0 disables
+// retries.
+func Retries() int {
+	if v := strings.TrimSpace(os.Getenv("DIBBLA_RETRIES")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return DefaultRetries
+}
+
+// Do sends req with the default retry budget. See DoWithRetries.
+func Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	return DoWithRetries(client, req, Retries())
+}
+
+// SetIdempotencyKey gives req a random idempotency key, which opts a POST
+// or PATCH into the retries of DoWithRetries. Only use it for endpoints
+// that honor IdempotencyKeyHeader.
+func SetIdempotencyKey(req *http.Request) {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	req.Header.Set(IdempotencyKeyHeader, hex.EncodeToString(b))
+}
+
+// DoWithRetries sends req and retries connection resets, timeouts and
+// 502/503/504 responses up to retries times with jittered exponential
+// backoff, printing "retrying (n/max)…" to stderr before each attempt. Only
+// failures before response headers arrive are retried, so a streamed body
+// that breaks midway is never replayed. Requests whose body can't be
+// rewound (no GetBody) are sent once.
+//
+// A failed attempt may still have taken effect on the server, so only
+// idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE) and requests with an
+// idempotency key (see SetIdempotencyKey) are retried after any of those.
+// Other requests are retried only when the connection was refused, i.e.
+// they never reached the server.
+//
+// After Ctrl-C (see package interrupt) a failed request, or a failed read
+// of its body, blocks until the interrupt handler exits the process
+// instead of surfacing "context canceled" to the command.
+func DoWithRetries(client *http.Client, req *http.Request, retries int) (*http.Response, error) {
+	resp, err := doWithRetries(client, req, retries)
+	if err != nil {
+		interrupt.Wait()
+		return nil, err
+	}
+	resp.Body = interruptibleBody{resp.Body}
+	return resp, nil
+}
+
+// interruptibleBody defers to the interrupt handler when a read fails
+// after Ctrl-C, e.g. mid-stream of a deploy or database dump.
+type interruptibleBody struct {
+	io.ReadCloser
+}
+
+func (b interruptibleBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		interrupt.Wait()
+	}
+	return n, err
+}
+
+func doWithRetries(client *http.Client, req *http.Request, retries int) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err := client.Do(req)
+		reason := transientReason(resp, err)
+		if reason == "" || attempt >= retries || (req.Body != nil && req.GetBody == nil) || !canRetry(req, err) {
+			return resp, err
+		}
+		wait := retryDelay(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		fmt.Fprintf(retryOut, "%s %s: %s, retrying (%d/%d) in %s…\n",
+			req.Method, req.URL.Path, reason, attempt+1, retries, wait.Round(100*time.Millisecond))
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// canRetry reports whether req may be sent again after an attempt that
+// failed with err (nil for a 502/503/504 response).
+func canRetry(req *http.Request, err error) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// transientReason returns a short description of why the attempt is worth
+// retrying, or "" when it succeeded or failed for good.
+func transientReason(resp *http.Response, err error) string {
+	if err != nil {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, context.Canceled):
+			return ""
+		case errors.Is(err, syscall.ECONNRESET):
+			return "connection reset"
+		case errors.Is(err, syscall.ECONNREFUSED):
+			return "connection refused"
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return "connection closed"
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return "timed out"
+		}
+		return ""
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+	return ""
+}
+
+// retryDelay doubles the base delay per attempt up to retryMaxDelay and
+// picks a random point in its upper half. A Retry-After header in seconds
+// takes precedence, capped at the same maximum.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, retryMaxDelay)
+		}
+	}
+	d := retryBaseDelay << attempt
+	if d <= 0 || d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	half := d / 2
+	return half + time.Duration(mathrand.Int63n(int64(half)+1))
+}
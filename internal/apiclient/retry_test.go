@@ -0,0 +1,160 @@
+package apiclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetries(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	base, max, out := retryBaseDelay, retryMaxDelay, retryOut
+	var buf bytes.Buffer
+	retryBaseDelay, retryMaxDelay, retryOut = time.Millisecond, 2*time.Millisecond, &buf
+	t.Cleanup(func() { retryBaseDelay, retryMaxDelay, retryOut = base, max, out })
+	return &buf
+}
+
+func TestDoWithRetriesRecoversFromGatewayErrors(t *testing.T) {
+	out := fastRetries(t)
+	var calls int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest("POST", srv.URL+"/api/deploy/deployments", strings.NewReader("payload"))
+	SetIdempotencyKey(req)
+	resp, err := DoWithRetries(&http.Client{}, req, 5)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("status: want 201, got %d", resp.StatusCode)
+	}
+	for i, b := range bodies {
+		if b != "payload" {
+			t.Errorf("attempt %d body: want payload, got %q", i+1, b)
+		}
+	}
+	if !strings.Contains(out.String(), "HTTP 502, retrying (2/5)") {
+		t.Errorf("expected retry progress, got %q", out.String())
+	}
+}
+
+func TestDoWithRetriesSendsPostOnce(t *testing.T) {
+	fastRetries(t)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer srv.Close()
+
+	// Without an idempotency key the first attempt may have taken effect.
+	req, _ := http.NewRequest("POST", srv.URL+"/api/jobs/run", strings.NewReader("payload"))
+	resp, err := DoWithRetries(&http.Client{}, req, 3)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 1 || resp.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("want 1 attempt ending in 504, got %d ending in %d", calls, resp.StatusCode)
+	}
+}
+
+func TestDoWithRetriesGivesUpAfterBudget(t *testing.T) {
+	fastRetries(t)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := DoWithRetries(&http.Client{}, req, 2)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status: want 503, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("attempts: want 3, got %d", calls)
+	}
+}
+
+func TestDoWithRetriesLeavesPermanentErrorsAlone(t *testing.T) {
+	out := fastRetries(t)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := DoWithRetries(&http.Client{}, req, 3)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 1 {
+		t.Errorf("500 must not be retried, got %d attempts", calls)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected retry output %q", out.String())
+	}
+}
+
+func TestDoWithRetriesRetriesTimeouts(t *testing.T) {
+	fastRetries(t)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := DoWithRetries(&http.Client{Timeout: 50 * time.Millisecond}, req, 1)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 2 {
+		t.Errorf("attempts: want 2, got %d", calls)
+	}
+}
+
+func TestRetriesFromEnv(t *testing.T) {
+	t.Setenv("DIBBLA_RETRIES", "")
+	if got := Retries(); got != DefaultRetries {
+		t.Errorf("unset: want %d, got %d", DefaultRetries, got)
+	}
+	t.Setenv("DIBBLA_RETRIES", "0")
+	if got := Retries(); got != 0 {
+		t.Errorf("0: want 0, got %d", got)
+	}
+	t.Setenv("DIBBLA_RETRIES", "nope")
+	if got := Retries(); got != DefaultRetries {
+		t.Errorf("invalid: want default, got %d", got)
+	}
+}
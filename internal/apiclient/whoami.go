@@ -0,0 +1,105 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const whoamiPath = "/api/auth/v1/whoami"
+
+// ErrWhoamiUnsupported is returned by Whoami when the server has no
+// identity endpoint (older self-hosted versions); ValidateToken still
+// works there.
+var ErrWhoamiUnsupported = errors.New("the server doesn't report token details")
+
+// Identity is who an API token belongs to, as returned by GET
+// /api/auth/v1/whoami.
+type Identity struct {
+	Account        string `json:"account"`
+	Name           string `json:"name,omitempty"`
+	Organization   string `json:"organization"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	TokenName      string `json:"token_name,omitempty"`
+	// Scopes is empty for a token with full access.
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpiresAt is nil for a token that doesn't expire.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// tokenErrorMessages explain the error codes the API uses for tokens it
+// no longer accepts.
+var tokenErrorMessages = map[string]string{
+	"token_expired": "the API token has expired",
+	"token_revoked": "the API token was revoked",
+	"token_invalid": "the API token is not valid",
+}
+
+// Whoami returns the identity of token. A token the API rejects gives an
+// *APIError (401 or 403) saying why, such as expiry or revocation.
+func Whoami(ctx context.Context, baseURL, token string) (*Identity, error) {
+	url := strings.TrimSuffix(baseURL, "/") + whoamiPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		var id Identity
+		if err := json.Unmarshal(body, &id); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &id, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrWhoamiUnsupported
+	}
+
+	var errResp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	msg := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error.Code != "" {
+		msg = errResp.Error.Message
+		if m, ok := tokenErrorMessages[errResp.Error.Code]; ok {
+			msg = m
+		}
+	}
+	if msg == "" {
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			msg = "invalid or expired token"
+		case http.StatusForbidden:
+			msg = "access denied"
+		default:
+			msg = fmt.Sprintf("request failed (HTTP %d)", resp.StatusCode)
+		}
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		if hint := AuthShadowHint(); hint != "" {
+			msg = msg + "\n  " + hint
+		}
+	}
+	return nil, &APIError{StatusCode: resp.StatusCode, Message: msg, Headers: resp.Header}
+}
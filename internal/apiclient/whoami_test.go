@@ -0,0 +1,64 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWhoami(t *testing.T) {
+	t.Setenv("DIBBLA_API_TOKEN", "")
+	cases := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+		want    *Identity
+	}{
+		{
+			name:   "ok",
+			status: 200,
+			body:   `{"account":"ada@example.com","organization":"Acme","token_name":"laptop","scopes":["deploy"],"expires_at":"2026-11-01T00:00:00Z"}`,
+			want:   &Identity{Account: "ada@example.com", Organization: "Acme", TokenName: "laptop", Scopes: []string{"deploy"}},
+		},
+		{name: "expired", status: 401, body: `{"error":{"code":"token_expired","message":"expired"}}`, wantErr: "API error 401: the API token has expired"},
+		{name: "revoked", status: 401, body: `{"error":{"code":"token_revoked","message":"revoked"}}`, wantErr: "API error 401: the API token was revoked"},
+		{name: "empty 401", status: 401, wantErr: "API error 401: invalid or expired token"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != whoamiPath || r.Header.Get("Authorization") != "Bearer tok" {
+					t.Errorf("request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			id, err := Whoami(context.Background(), srv.URL+"/", "tok")
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("err = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id.Account != tc.want.Account || id.TokenName != tc.want.TokenName || len(id.Scopes) != 1 || id.ExpiresAt == nil {
+				t.Errorf("identity = %+v", id)
+			}
+		})
+	}
+}
+
+func TestWhoamiUnsupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	if _, err := Whoami(context.Background(), srv.URL, "tok"); !errors.Is(err, ErrWhoamiUnsupported) {
+		t.Errorf("err = %v, want ErrWhoamiUnsupported", err)
+	}
+}
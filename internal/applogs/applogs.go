@@ -13,6 +13,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
 )
 
 // Entry is a single log line as the server emits it. Mirrors
@@ -74,7 +76,7 @@ func Stream(ctx context.Context, apiURL, apiToken, alias string, opts Options) (
 
 	// Long timeout for follow; for range it's bounded by the server's own work.
 	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("logs request: %w", err)
 	}
@@ -124,7 +126,7 @@ func StreamPodService(ctx context.Context, apiURL, apiToken, alias, service stri
 	req.Header.Set("Accept", "text/plain")
 
 	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("logs request: %w", err)
 	}
@@ -183,7 +185,7 @@ func StreamRun(ctx context.Context, apiURL, apiToken, runID string, opts RunOpti
 	req.Header.Set("Accept", "application/x-ndjson")
 
 	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("logs request: %w", err)
 	}
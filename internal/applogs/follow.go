@@ -0,0 +1,113 @@
+package applogs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Reconnect backoff bounds for Follow. Variables so tests can run without
+// real sleeps.
+var (
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = 30 * time.Second
+)
+
+// followOverlap is how far before the newest entry seen a reconnect
+// resumes, and how long entries are remembered to drop the ones read
+// twice. With several replicas, entries arrive out of timestamp order, so
+// one can be older than the newest already emitted.
+const followOverlap = 30 * time.Second
+
+// Follow streams alias's logs like Stream with opts.Follow set, calling emit
+// with each NDJSON row, and reopens the stream whenever the connection
+// drops. A reconnect resumes followOverlap before the newest entry seen and
+// skips rows already emitted (the same timestamp, line and labels), so
+// lines are neither lost nor repeated.
+// Reconnect attempts are reported on notice.
+//
+// Follow returns nil once ctx is cancelled. It returns the error if the
+// first connection fails, or if a reconnect is refused with an HTTP error
+// (the app was deleted, the token revoked, ...).
+func Follow(ctx context.Context, apiURL, apiToken, alias string, opts Options, emit func(line []byte), notice io.Writer) error {
+	opts.Follow = true
+	var (
+		newest    time.Time
+		pruned    time.Time
+		seen      = map[string]time.Time{}
+		attempt   int
+		connected bool
+	)
+	for {
+		body, err := Stream(ctx, apiURL, apiToken, alias, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var httpErr *HTTPError
+			if !connected || errors.As(err, &httpErr) {
+				return err
+			}
+		} else {
+			connected = true
+			scanner := bufio.NewScanner(body)
+			scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				line := scanner.Bytes()
+				if len(line) == 0 {
+					continue
+				}
+				if e, ok, _ := DecodeLine(line); ok && !e.Timestamp.IsZero() {
+					key := string(line)
+					if _, dup := seen[key]; dup {
+						continue
+					}
+					seen[key] = e.Timestamp
+					if e.Timestamp.After(newest) {
+						newest = e.Timestamp
+					}
+					if newest.Sub(pruned) > time.Second {
+						for k, ts := range seen {
+							if newest.Sub(ts) > followOverlap {
+								delete(seen, k)
+							}
+						}
+						pruned = newest
+					}
+				}
+				attempt = 0
+				emit(line)
+			}
+			err = scanner.Err()
+			_ = body.Close()
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err == nil {
+				err = io.EOF
+			}
+		}
+
+		delay := min(reconnectBaseDelay<<attempt, reconnectMaxDelay)
+		if delay < reconnectMaxDelay {
+			attempt++
+		}
+		fmt.Fprintf(notice, "logs: stream dropped (%v), reconnecting in %s…\n", err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil
+		}
+
+		// Resume a little before the newest entry, to catch late rows of
+		// other replicas; rows seen already are filtered above. Before any
+		// entry arrived, repeat the original window.
+		if !newest.IsZero() {
+			opts.Tail = 0
+			opts.Since = time.Since(newest) + followOverlap
+		}
+	}
+}
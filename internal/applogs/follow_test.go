@@ -0,0 +1,130 @@
+package applogs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFollow_ReconnectsWithoutRepeatingLines(t *testing.T) {
+	defer func(base time.Duration) { reconnectBaseDelay = base }(reconnectBaseDelay)
+	reconnectBaseDelay = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		n := len(queries)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		switch n {
+		case 1:
+			// Drops after two lines.
+			w.Write([]byte(`{"ts":"2026-01-01T00:00:01Z","line":"one"}` + "\n"))
+			w.Write([]byte(`{"ts":"2026-01-01T00:00:02Z","line":"two"}` + "\n"))
+		default:
+			// The resumed window overlaps the last line seen.
+			w.Write([]byte(`{"ts":"2026-01-01T00:00:02Z","line":"two"}` + "\n"))
+			w.Write([]byte(`{"ts":"2026-01-01T00:00:03Z","line":"three"}` + "\n"))
+		}
+	}))
+	defer srv.Close()
+
+	var got []string
+	var notice bytes.Buffer
+	err := Follow(ctx, srv.URL, "tok", "myapp", Options{Tail: 200}, func(line []byte) {
+		e, _, _ := DecodeLine(line)
+		got = append(got, e.Line)
+		if e.Line == "three" {
+			cancel()
+		}
+	}, &notice)
+	if err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if strings.Join(got, ",") != "one,two,three" {
+		t.Errorf("lines = %v, want one,two,three", got)
+	}
+	if !strings.Contains(notice.String(), "reconnecting") {
+		t.Errorf("no reconnect notice: %q", notice.String())
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(queries) < 2 {
+		t.Fatalf("want a reconnect, got %d request(s)", len(queries))
+	}
+	if !strings.Contains(queries[0], "tail=200") || strings.Contains(queries[1], "tail=") {
+		t.Errorf("tail should only apply to the first request: %q", queries)
+	}
+	if !strings.Contains(queries[1], "since=") {
+		t.Errorf("reconnect should resume with since: %q", queries[1])
+	}
+}
+
+func TestFollow_KeepsLateReplicaLines(t *testing.T) {
+	defer func(base time.Duration) { reconnectBaseDelay = base }(reconnectBaseDelay)
+	reconnectBaseDelay = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		a := `{"ts":"2026-01-01T00:00:05Z","line":"a","labels":{"pod":"web-1"}}` + "\n"
+		b := `{"ts":"2026-01-01T00:00:03Z","line":"b","labels":{"pod":"web-2"}}` + "\n"
+		c := `{"ts":"2026-01-01T00:00:04Z","line":"c","labels":{"pod":"web-2"}}` + "\n"
+		switch n {
+		case 1:
+			// web-2's line arrives after web-1's newer one.
+			w.Write([]byte(a + b))
+		default:
+			// The resumed window repeats both and adds a late web-2 line.
+			w.Write([]byte(b + c + a))
+			w.Write([]byte(`{"ts":"2026-01-01T00:00:06Z","line":"end"}` + "\n"))
+		}
+	}))
+	defer srv.Close()
+
+	var got []string
+	err := Follow(ctx, srv.URL, "tok", "myapp", Options{}, func(line []byte) {
+		e, _, _ := DecodeLine(line)
+		got = append(got, e.Line)
+		if e.Line == "end" {
+			cancel()
+		}
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if strings.Join(got, ",") != "a,b,c,end" {
+		t.Errorf("lines = %v, want a,b,c,end", got)
+	}
+}
+
+func TestFollow_FirstConnectErrorReturned(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such app", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	err := Follow(context.Background(), srv.URL, "tok", "missing", Options{}, func([]byte) {}, &bytes.Buffer{})
+	if he, ok := err.(*HTTPError); !ok || he.Status != http.StatusNotFound {
+		t.Errorf("err = %v, want HTTP 404", err)
+	}
+}
@@ -1,12 +1,15 @@
 package apps
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
 )
 
 // DeploymentsListResponse represents the API response for listing deployments.
@@ -17,22 +20,31 @@ type DeploymentsListResponse struct {
 
 // Deployment represents a single application deployment.
 type Deployment struct {
-	ID              string           `json:"id"`
-	Alias           string           `json:"alias"`
-	URL             string           `json:"url"`
-	Status          DeploymentStatus `json:"status"`
-	ContainerID     string           `json:"container_id"`
-	ImageID         string           `json:"image_id"`
-	ProjectPath     string           `json:"project_path"`
-	CreatedAt       time.Time        `json:"created_at"`
-	UpdatedAt       time.Time        `json:"updated_at"`
-	DeployedAt      *time.Time       `json:"deployed_at"`
-	Error           string           `json:"error"`
-	HealthCheck     *HealthCheckInfo `json:"health_check"`
-	RequireLogin    bool             `json:"require_login"`
-	AppAccessPolicy string           `json:"app_access_policy,omitempty"`
-	GoogleScopes    []string         `json:"google_scopes,omitempty"`
-	MicrosoftScopes []string         `json:"microsoft_scopes,omitempty"`
+	ID              string            `json:"id"`
+	Alias           string            `json:"alias"`
+	URL             string            `json:"url"`
+	Status          DeploymentStatus  `json:"status"`
+	ContainerID     string            `json:"container_id"`
+	ImageID         string            `json:"image_id"`
+	ProjectPath     string            `json:"project_path"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	DeployedAt      *time.Time        `json:"deployed_at"`
+	Error           string            `json:"error"`
+	HealthCheck     *HealthCheckInfo  `json:"health_check"`
+	RequireLogin    bool              `json:"require_login"`
+	AppAccessPolicy string            `json:"app_access_policy,omitempty"`
+	GoogleScopes    []string          `json:"google_scopes,omitempty"`
+	MicrosoftScopes []string          `json:"microsoft_scopes,omitempty"`
+	Preview         bool              `json:"preview,omitempty"`
+	PreviewBranch   string            `json:"preview_branch,omitempty"`
+	PreviewOf       string            `json:"preview_of,omitempty"`
+	Region          string            `json:"region,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	CPU             string            `json:"cpu,omitempty"`
+	Memory          string            `json:"memory,omitempty"`
+	Replicas        int               `json:"replicas"`
+	ReadyReplicas   int               `json:"ready_replicas"`
 }
 
 // DeploymentStatus represents the status of a deployment.
@@ -50,6 +62,7 @@ const (
 	DeploymentStatusDeleting    DeploymentStatus = "deleting"
 	DeploymentStatusDeleted     DeploymentStatus = "deleted"
 	DeploymentStatusFailed      DeploymentStatus = "failed"
+	DeploymentStatusStopped     DeploymentStatus = "stopped"
 )
 
 // HealthCheckInfo represents health check information for a deployment.
@@ -103,13 +116,91 @@ type UpdateDeploymentRequest struct {
 	AppAccessPolicy      *string           `json:"app_access_policy,omitempty"`
 	GoogleScopes         []string          `json:"google_scopes,omitempty"`
 	MicrosoftScopes      []string          `json:"microsoft_scopes,omitempty"`
+	Region               string            `json:"region,omitempty"`
+	// RemoveEnvironmentVariables deletes these keys; EnvironmentVariables
+	// can only add or overwrite.
+	RemoveEnvironmentVariables []string `json:"remove_environment_variables,omitempty"`
+	// Autoscaling turns on or adjusts horizontal autoscaling.
+	Autoscaling *AutoscalingConfig `json:"autoscaling,omitempty"`
 }
 
-// ListApps makes an API call to list all deployed applications.
-func ListApps(apiURL, apiToken string) (*DeploymentsListResponse, error) {
+// listPageSize is how many deployments ListApps asks for per request when
+// walking every page. A variable so tests can use small pages.
+var listPageSize = 100
+
+// ListApps makes an API call to list deployed applications matching opts.
+// Without opts.Page it walks the limit/offset pages until it has every
+// match, or opts.Limit of them; with opts.Page it fetches just that page.
+func ListApps(ctx context.Context, apiURL, apiToken string, opts ListOptions) (*DeploymentsListResponse, error) {
 	client := &http.Client{Timeout: 10 * time.Second}
-	apiURL = strings.TrimSuffix(apiURL, "/")
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/deploy/deployments", apiURL), nil)
+	listURL := strings.TrimSuffix(apiURL, "/") + "/api/deploy/deployments"
+
+	pageSize := listPageSize
+	if opts.Page > 0 && opts.Limit > 0 {
+		pageSize = opts.Limit
+	}
+	offset := 0
+	if opts.Page > 0 {
+		offset = (opts.Page - 1) * pageSize
+	}
+
+	var out DeploymentsListResponse
+	filtered := false
+	seen := map[string]bool{}
+	for {
+		q := opts.query()
+		q.Set("limit", fmt.Sprint(pageSize))
+		q.Set("offset", fmt.Sprint(offset))
+		page, err := listAppsPage(ctx, client, listURL+"?"+q.Encode(), apiToken)
+		if err != nil {
+			return nil, err
+		}
+		out.Total = page.Total
+
+		// Servers that don't support a filter return everything; filter
+		// here too so the result is the same either way.
+		fresh := false
+		for _, d := range page.Deployments {
+			if seen[d.Alias] {
+				continue
+			}
+			seen[d.Alias] = true
+			fresh = true
+			if !opts.Match(d) {
+				filtered = true
+				continue
+			}
+			if opts.Limit > 0 && len(out.Deployments) >= opts.Limit {
+				break
+			}
+			out.Deployments = append(out.Deployments, d)
+		}
+
+		offset += len(page.Deployments)
+		// A short page is the last one; so is an oversized one, from a
+		// server that ignores limit/offset and returns everything, and one
+		// with nothing new, from a server that keeps repeating a page.
+		done := opts.Page > 0 || !fresh ||
+			(opts.Limit > 0 && len(out.Deployments) >= opts.Limit) ||
+			len(page.Deployments) != pageSize ||
+			(page.Total > 0 && offset >= page.Total)
+		if done {
+			break
+		}
+	}
+
+	if filtered && opts.Page == 0 && opts.Limit == 0 {
+		out.Total = len(out.Deployments)
+	}
+	if out.Deployments == nil {
+		out.Deployments = []Deployment{}
+	}
+	return &out, nil
+}
+
+// listAppsPage fetches one page of GET /deployments.
+func listAppsPage(ctx context.Context, client *http.Client, pageURL, apiToken string) (*DeploymentsListResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -117,7 +208,7 @@ func ListApps(apiURL, apiToken string) (*DeploymentsListResponse, error) {
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", apiToken))
 	req.Header.Add("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -140,15 +231,14 @@ func ListApps(apiURL, apiToken string) (*DeploymentsListResponse, error) {
 	if err := json.Unmarshal(body, &deployments); err != nil {
 		return nil, fmt.Errorf("failed to parse API response: %w", err)
 	}
-
 	return &deployments, nil
 }
 
 // DeleteApp makes an API call to delete a specific application by alias.
-func DeleteApp(apiURL, apiToken, alias string) (*DeleteResponse, error) {
+func DeleteApp(ctx context.Context, apiURL, apiToken, alias string) (*DeleteResponse, error) {
 	client := &http.Client{Timeout: 10 * time.Second}
 	apiURL = strings.TrimSuffix(apiURL, "/")
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/deploy/deployments/%s", apiURL, alias), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/deploy/deployments/%s", apiURL, alias), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -156,7 +246,7 @@ func DeleteApp(apiURL, apiToken, alias string) (*DeleteResponse, error) {
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", apiToken))
 	req.Header.Add("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -184,7 +274,7 @@ func DeleteApp(apiURL, apiToken, alias string) (*DeleteResponse, error) {
 }
 
 // UpdateApp updates an existing deployment by alias (PUT /deployments/{alias}).
-func UpdateApp(apiURL, apiToken, alias string, req UpdateDeploymentRequest) (*Deployment, error) {
+func UpdateApp(ctx context.Context, apiURL, apiToken, alias string, req UpdateDeploymentRequest) (*Deployment, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode request: %w", err)
@@ -192,7 +282,7 @@ func UpdateApp(apiURL, apiToken, alias string, req UpdateDeploymentRequest) (*De
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	apiURL = strings.TrimSuffix(apiURL, "/")
-	httpReq, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/deploy/deployments/%s", apiURL, alias), strings.NewReader(string(body)))
+	httpReq, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/api/deploy/deployments/%s", apiURL, alias), strings.NewReader(string(body)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -201,7 +291,7 @@ func UpdateApp(apiURL, apiToken, alias string, req UpdateDeploymentRequest) (*De
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(httpReq)
+	resp, err := apiclient.Do(client, httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
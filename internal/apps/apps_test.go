@@ -0,0 +1,143 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// pagedServer serves n deployments (app-0 … app-<n-1>) honoring
+// limit/offset, and counts requests.
+func pagedServer(t *testing.T, n int, requests *int) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var items []string
+		for i := offset; i < n && i < offset+limit; i++ {
+			items = append(items, fmt.Sprintf(`{"alias":"app-%d","status":"running"}`, i))
+		}
+		fmt.Fprintf(w, `{"total":%d,"deployments":[`, n)
+		for i, it := range items {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprint(w, it)
+		}
+		fmt.Fprint(w, "]}")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func withPageSize(t *testing.T, n int) {
+	old := listPageSize
+	listPageSize = n
+	t.Cleanup(func() { listPageSize = old })
+}
+
+func TestListApps_WalksAllPages(t *testing.T) {
+	withPageSize(t, 10)
+	var requests int
+	srv := pagedServer(t, 25, &requests)
+
+	resp, err := ListApps(context.Background(), srv.URL, "tok", ListOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(resp.Deployments) != 25 || resp.Total != 25 || requests != 3 {
+		t.Errorf("got %d deployments (total %d) in %d requests", len(resp.Deployments), resp.Total, requests)
+	}
+	if resp.Deployments[24].Alias != "app-24" {
+		t.Errorf("last = %q", resp.Deployments[24].Alias)
+	}
+}
+
+func TestListApps_LimitStopsEarly(t *testing.T) {
+	withPageSize(t, 10)
+	var requests int
+	srv := pagedServer(t, 50, &requests)
+
+	resp, err := ListApps(context.Background(), srv.URL, "tok", ListOptions{Limit: 15})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(resp.Deployments) != 15 || resp.Total != 50 || requests != 2 {
+		t.Errorf("got %d deployments (total %d) in %d requests", len(resp.Deployments), resp.Total, requests)
+	}
+}
+
+func TestListApps_SinglePage(t *testing.T) {
+	var requests int
+	srv := pagedServer(t, 50, &requests)
+
+	resp, err := ListApps(context.Background(), srv.URL, "tok", ListOptions{Limit: 20, Page: 2})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(resp.Deployments) != 20 || resp.Deployments[0].Alias != "app-20" || requests != 1 {
+		t.Errorf("got %d deployments starting at %q in %d requests", len(resp.Deployments), resp.Deployments[0].Alias, requests)
+	}
+}
+
+func TestListApps_ServerIgnoresPagination(t *testing.T) {
+	withPageSize(t, 2)
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"total":3,"deployments":[{"alias":"a"},{"alias":"b"},{"alias":"c"}]}`))
+	}))
+	defer srv.Close()
+
+	resp, err := ListApps(context.Background(), srv.URL, "tok", ListOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(resp.Deployments) != 3 || requests != 1 {
+		t.Errorf("got %d deployments in %d requests", len(resp.Deployments), requests)
+	}
+}
+
+func TestListApps_StopsWhenPageRepeats(t *testing.T) {
+	withPageSize(t, 2)
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Ignores offset and reports no total: every page is the first.
+		w.Write([]byte(`{"deployments":[{"alias":"a"},{"alias":"b"}]}`))
+	}))
+	defer srv.Close()
+
+	resp, err := ListApps(context.Background(), srv.URL, "tok", ListOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(resp.Deployments) != 2 || requests != 2 {
+		t.Errorf("got %d deployments in %d requests", len(resp.Deployments), requests)
+	}
+}
+
+func TestUpdateApp_SendsRemovedEnv(t *testing.T) {
+	var sawBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&sawBody)
+		w.Write([]byte(`{"alias":"shop","status":"running"}`))
+	}))
+	defer srv.Close()
+
+	_, err := UpdateApp(context.Background(), srv.URL, "tok", "shop", UpdateDeploymentRequest{RemoveEnvironmentVariables: []string{"DEBUG"}})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	removed, _ := sawBody["remove_environment_variables"].([]any)
+	if len(removed) != 1 || removed[0] != "DEBUG" {
+		t.Errorf("body = %v", sawBody)
+	}
+	if _, ok := sawBody["environment_variables"]; ok {
+		t.Errorf("environment_variables should be omitted: %v", sawBody)
+	}
+}
@@ -0,0 +1,49 @@
+package apps
+
+import (
+	"errors"
+	"time"
+)
+
+// AutoscalingConfig configures horizontal autoscaling in
+// UpdateDeploymentRequest. Nil fields keep their current value, so e.g.
+// only MaxReplicas can be raised.
+type AutoscalingConfig struct {
+	MinReplicas *int32 `json:"min_replicas,omitempty"`
+	MaxReplicas *int32 `json:"max_replicas,omitempty"`
+	// TargetCPUPercent is the average CPU utilization, as a percentage of
+	// the CPU request, the autoscaler keeps replicas at.
+	TargetCPUPercent *int `json:"target_cpu_percent,omitempty"`
+}
+
+// Validate checks the fields that are set. MaxReplicas is compared with
+// MinReplicas only when both are given; the server checks the merged
+// result.
+func (c AutoscalingConfig) Validate() error {
+	if c.MinReplicas != nil && *c.MinReplicas < 1 {
+		return errors.New("min replicas must be at least 1")
+	}
+	if c.MaxReplicas != nil && *c.MaxReplicas < 1 {
+		return errors.New("max replicas must be at least 1")
+	}
+	if c.MinReplicas != nil && c.MaxReplicas != nil && *c.MaxReplicas < *c.MinReplicas {
+		return errors.New("max replicas must not be below min replicas")
+	}
+	if c.TargetCPUPercent != nil && (*c.TargetCPUPercent < 1 || *c.TargetCPUPercent > 100) {
+		return errors.New("target CPU must be between 1 and 100 (percent)")
+	}
+	return nil
+}
+
+// AutoscalerState is the autoscaler's configuration and latest decision,
+// as reported in DeploymentDetail.
+type AutoscalerState struct {
+	Enabled           bool       `json:"enabled"`
+	MinReplicas       int32      `json:"min_replicas"`
+	MaxReplicas       int32      `json:"max_replicas"`
+	TargetCPUPercent  int        `json:"target_cpu_percent"`
+	CurrentCPUPercent *int       `json:"current_cpu_percent,omitempty"`
+	CurrentReplicas   int32      `json:"current_replicas"`
+	DesiredReplicas   int32      `json:"desired_replicas"`
+	LastScaleTime     *time.Time `json:"last_scale_time,omitempty"`
+}
@@ -0,0 +1,26 @@
+package apps
+
+import "testing"
+
+func TestAutoscalingConfigValidate(t *testing.T) {
+	i32 := func(n int32) *int32 { return &n }
+	pct := func(n int) *int { return &n }
+	cases := []struct {
+		name string
+		cfg  AutoscalingConfig
+		ok   bool
+	}{
+		{"full", AutoscalingConfig{MinReplicas: i32(2), MaxReplicas: i32(10), TargetCPUPercent: pct(70)}, true},
+		{"max only", AutoscalingConfig{MaxReplicas: i32(5)}, true},
+		{"min equals max", AutoscalingConfig{MinReplicas: i32(3), MaxReplicas: i32(3)}, true},
+		{"zero min", AutoscalingConfig{MinReplicas: i32(0)}, false},
+		{"max below min", AutoscalingConfig{MinReplicas: i32(4), MaxReplicas: i32(2)}, false},
+		{"target over 100", AutoscalingConfig{TargetCPUPercent: pct(150)}, false},
+		{"target zero", AutoscalingConfig{TargetCPUPercent: pct(0)}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.cfg.Validate(); (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// CanaryResponse is the payload returned by POST
+// /deployments/{alias}/canary/{promote,abort} on success.
+type CanaryResponse struct {
+	Alias          string `json:"alias"`
+	Status         string `json:"status"`
+	Message        string `json:"message"`
+	StableRevision string `json:"stable_revision,omitempty"`
+	CanaryRevision string `json:"canary_revision,omitempty"`
+}
+
+// PromoteCanary shifts all traffic for alias to its canary revision and
+// retires the previous stable revision.
+func PromoteCanary(ctx context.Context, apiURL, apiToken, alias string) (*CanaryResponse, error) {
+	return canaryAction(ctx, apiURL, apiToken, alias, "promote")
+}
+
+// AbortCanary sends all traffic for alias back to the stable revision and
+// tears the canary down.
+func AbortCanary(ctx context.Context, apiURL, apiToken, alias string) (*CanaryResponse, error) {
+	return canaryAction(ctx, apiURL, apiToken, alias, "abort")
+}
+
+func canaryAction(ctx context.Context, apiURL, apiToken, alias, action string) (*CanaryResponse, error) {
+	url := fmt.Sprintf("%s/api/deploy/deployments/%s/canary/%s",
+		strings.TrimSuffix(apiURL, "/"), alias, action)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var out CanaryResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,46 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCanaryActions(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		paths = append(paths, r.URL.Path)
+		_ = json.NewEncoder(w).Encode(CanaryResponse{Alias: "shop", Status: "promoted"})
+	}))
+	defer srv.Close()
+
+	if _, err := PromoteCanary(context.Background(), srv.URL, "tok", "shop"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AbortCanary(context.Background(), srv.URL, "tok", "shop"); err != nil {
+		t.Fatal(err)
+	}
+	want := "/api/deploy/deployments/shop/canary/promote,/api/deploy/deployments/shop/canary/abort"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("paths = %s", got)
+	}
+}
+
+func TestCanaryActionSurfacesErrorCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"status":"error","error":{"code":"NO_CANARY","message":"no canary in progress"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := PromoteCanary(context.Background(), srv.URL, "tok", "shop")
+	if err == nil || !strings.Contains(err.Error(), "NO_CANARY") {
+		t.Fatalf("expected NO_CANARY error, got %v", err)
+	}
+}
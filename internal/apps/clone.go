@@ -0,0 +1,86 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// CloneRequest is the body of POST /deployments/{alias}/clone.
+type CloneRequest struct {
+	NewAlias string `json:"new_alias"`
+	// IncludeImage also runs the source's current image on the clone.
+	// Without it the clone gets the configuration only and waits for a
+	// deploy.
+	IncludeImage bool `json:"include_image,omitempty"`
+	// EnvironmentVariables override or add to the copied variables.
+	EnvironmentVariables map[string]string `json:"environment_variables,omitempty"`
+}
+
+// CloneResponse is the payload returned by a successful clone. Copied lists
+// what was taken from the source (e.g. "env", "resources", "port", "image").
+type CloneResponse struct {
+	Alias   string           `json:"alias"`
+	Source  string           `json:"source"`
+	URL     string           `json:"url,omitempty"`
+	Status  DeploymentStatus `json:"status"`
+	Copied  []string         `json:"copied,omitempty"`
+	Message string           `json:"message,omitempty"`
+}
+
+// CloneApp creates req.NewAlias with alias's env vars, resources and port,
+// and with its current image when req.IncludeImage is set.
+func CloneApp(ctx context.Context, apiURL, apiToken, alias string, req CloneRequest) (*CloneResponse, error) {
+	if !AliasRe.MatchString(req.NewAlias) {
+		return nil, fmt.Errorf("invalid alias %q: use lowercase letters, digits and hyphens (max 63, no leading or trailing hyphen)", req.NewAlias)
+	}
+	if req.NewAlias == alias {
+		return nil, fmt.Errorf("new alias must differ from %q", alias)
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/deploy/deployments/%s/clone", strings.TrimSuffix(apiURL, "/"), alias)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+apiToken)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := apiclient.Do(client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+		var out CloneResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,59 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCloneApp(t *testing.T) {
+	var path string
+	var got CloneRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"alias":"shop-staging","source":"shop","status":"building","copied":["env","resources","port","image"]}`))
+	}))
+	defer srv.Close()
+
+	out, err := CloneApp(context.Background(), srv.URL, "tok", "shop", CloneRequest{
+		NewAlias: "shop-staging", IncludeImage: true, EnvironmentVariables: map[string]string{"STAGE": "staging"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "POST /api/deploy/deployments/shop/clone" {
+		t.Errorf("request = %s", path)
+	}
+	if got.NewAlias != "shop-staging" || !got.IncludeImage || got.EnvironmentVariables["STAGE"] != "staging" {
+		t.Errorf("body = %+v", got)
+	}
+	if out.Alias != "shop-staging" || len(out.Copied) != 4 {
+		t.Errorf("out = %+v", out)
+	}
+}
+
+func TestCloneApp_InvalidAlias(t *testing.T) {
+	for _, alias := range []string{"Shop_Staging", "shop"} {
+		if _, err := CloneApp(context.Background(), "http://unused", "tok", "shop", CloneRequest{NewAlias: alias}); err == nil {
+			t.Errorf("%q: expected error", alias)
+		}
+	}
+}
+
+func TestCloneApp_Conflict(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"status":"error","error":{"code":"ALIAS_TAKEN","message":"alias already in use"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := CloneApp(context.Background(), srv.URL, "tok", "shop", CloneRequest{NewAlias: "shop-staging"})
+	if err == nil || !strings.Contains(err.Error(), "ALIAS_TAKEN") {
+		t.Errorf("err = %v", err)
+	}
+}
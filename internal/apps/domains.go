@@ -0,0 +1,82 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// DomainRe matches a fully-qualified hostname with at least two labels.
+// Wildcards and apex-only checks are left to the server.
+var DomainRe = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// DNSRecord is one record the user must create at their DNS provider.
+type DNSRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	TTL   int    `json:"ttl,omitempty"`
+}
+
+// DomainBinding is the payload returned by POST
+// /deployments/{alias}/domains. Status is "pending_dns" until the records
+// resolve, then "verified" once the certificate has been issued.
+type DomainBinding struct {
+	Alias      string      `json:"alias"`
+	Domain     string      `json:"domain"`
+	Status     string      `json:"status"`
+	DNSRecords []DNSRecord `json:"dns_records"`
+	Message    string      `json:"message,omitempty"`
+}
+
+// AttachDomain binds a custom domain to alias's deployment. The platform
+// issues a TLS certificate once the returned DNS records are live.
+func AttachDomain(ctx context.Context, apiURL, apiToken, alias, domain string) (*DomainBinding, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if !DomainRe.MatchString(domain) {
+		return nil, fmt.Errorf("invalid domain %q", domain)
+	}
+	payload, _ := json.Marshal(map[string]string{"domain": domain})
+	url := fmt.Sprintf("%s/api/deploy/deployments/%s/domains", strings.TrimSuffix(apiURL, "/"), alias)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+		var out DomainBinding
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
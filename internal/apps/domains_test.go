@@ -0,0 +1,81 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDomainRe(t *testing.T) {
+	cases := []struct {
+		domain string
+		ok     bool
+	}{
+		{"api.example.com", true},
+		{"a.b.example.co", true},
+		{"example", false},
+		{"-bad.example.com", false},
+		{"*.example.com", false},
+		{"api.example.com/path", false},
+	}
+	for _, c := range cases {
+		if got := DomainRe.MatchString(c.domain); got != c.ok {
+			t.Errorf("%q: got %v, want %v", c.domain, got, c.ok)
+		}
+	}
+}
+
+func TestAttachDomain_HappyPath(t *testing.T) {
+	var sawPath string
+	var sawBody map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&sawBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"alias": "myapp", "domain": "api.example.com", "status": "pending_dns",
+			"dns_records": []map[string]any{{"type": "CNAME", "name": "api.example.com", "value": "ingress.dibbla.com"}},
+		})
+	}))
+	defer srv.Close()
+
+	b, err := AttachDomain(context.Background(), srv.URL, "tok", "myapp", "API.example.com.")
+	if err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if sawPath != "/api/deploy/deployments/myapp/domains" {
+		t.Errorf("path: %q", sawPath)
+	}
+	if sawBody["domain"] != "api.example.com" {
+		t.Errorf("domain should be normalized, got %q", sawBody["domain"])
+	}
+	if b.Status != "pending_dns" || len(b.DNSRecords) != 1 || b.DNSRecords[0].Type != "CNAME" {
+		t.Errorf("unexpected binding: %+v", b)
+	}
+}
+
+func TestAttachDomain_Conflict(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "error",
+			"error":  map[string]any{"code": "DOMAIN_IN_USE", "message": "domain already bound"},
+		})
+	}))
+	defer srv.Close()
+
+	_, err := AttachDomain(context.Background(), srv.URL, "tok", "myapp", "api.example.com")
+	if err == nil || !strings.Contains(err.Error(), "DOMAIN_IN_USE") {
+		t.Fatalf("expected DOMAIN_IN_USE error, got %v", err)
+	}
+}
+
+func TestAttachDomain_RejectsInvalidLocally(t *testing.T) {
+	if _, err := AttachDomain(context.Background(), "http://unused", "tok", "myapp", "not a domain"); err == nil {
+		t.Fatal("expected local validation error")
+	}
+}
@@ -0,0 +1,176 @@
+package apps
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// EditSpec is the part of a deployment `apps edit` lets the user change,
+// laid out as the YAML the user edits.
+type EditSpec struct {
+	Replicas        int32             `yaml:"replicas"`
+	CPU             string            `yaml:"cpu,omitempty"`
+	Memory          string            `yaml:"memory,omitempty"`
+	Port            int               `yaml:"port,omitempty"`
+	Region          string            `yaml:"region,omitempty"`
+	RequireLogin    bool              `yaml:"require_login"`
+	AppAccessPolicy string            `yaml:"app_access_policy,omitempty"`
+	GoogleScopes    []string          `yaml:"google_scopes,omitempty"`
+	MicrosoftScopes []string          `yaml:"microsoft_scopes,omitempty"`
+	Autoscaling     *EditAutoscaling  `yaml:"autoscaling,omitempty"`
+	Env             map[string]string `yaml:"env,omitempty"`
+}
+
+// EditAutoscaling is the autoscaling block of an EditSpec.
+type EditAutoscaling struct {
+	MinReplicas      int32 `yaml:"min_replicas"`
+	MaxReplicas      int32 `yaml:"max_replicas"`
+	TargetCPUPercent int   `yaml:"target_cpu_percent"`
+}
+
+// NewEditSpec builds the editable spec of d; env supplies the variable
+// values (ListEnv with reveal).
+func NewEditSpec(d *DeploymentDetail, env []EnvVar) EditSpec {
+	spec := EditSpec{
+		Replicas:        int32(d.Replicas),
+		CPU:             d.CPU,
+		Memory:          d.Memory,
+		Port:            d.Port,
+		Region:          d.Region,
+		RequireLogin:    d.RequireLogin,
+		AppAccessPolicy: d.AppAccessPolicy,
+		GoogleScopes:    d.GoogleScopes,
+		MicrosoftScopes: d.MicrosoftScopes,
+	}
+	if a := d.Autoscaler; a != nil && a.Enabled {
+		spec.Autoscaling = &EditAutoscaling{MinReplicas: a.MinReplicas, MaxReplicas: a.MaxReplicas, TargetCPUPercent: a.TargetCPUPercent}
+	}
+	if len(env) > 0 {
+		spec.Env = make(map[string]string, len(env))
+		for _, v := range env {
+			spec.Env[v.Name] = v.Value
+		}
+	}
+	return spec
+}
+
+// Diff returns the update that turns old into s, holding only the fields
+// that changed, and a human-readable line per change. Env values are not
+// echoed in the change lines. An error means s can't be applied.
+func (s EditSpec) Diff(old EditSpec) (UpdateDeploymentRequest, []string, error) {
+	var req UpdateDeploymentRequest
+	var changes []string
+	changed := func(field string, from, to any) {
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", field, from, to))
+	}
+
+	if s.Replicas != old.Replicas {
+		if s.Autoscaling != nil {
+			return req, nil, errors.New("replicas is set by the autoscaler; change the autoscaling block instead")
+		}
+		if s.Replicas < 0 {
+			return req, nil, errors.New("replicas must not be negative")
+		}
+		r := s.Replicas
+		req.Replicas = &r
+		changed("replicas", old.Replicas, s.Replicas)
+	}
+	if s.CPU != old.CPU {
+		req.CPU = s.CPU
+		changed("cpu", old.CPU, s.CPU)
+	}
+	if s.Memory != old.Memory {
+		req.Memory = s.Memory
+		changed("memory", old.Memory, s.Memory)
+	}
+	if s.Port != old.Port {
+		if s.Port < 1 || s.Port > 65535 {
+			return req, nil, errors.New("port must be between 1 and 65535")
+		}
+		p := s.Port
+		req.Port = &p
+		changed("port", old.Port, s.Port)
+	}
+	if s.Region != old.Region {
+		if !RegionRe.MatchString(s.Region) {
+			return req, nil, fmt.Errorf("invalid region %q", s.Region)
+		}
+		req.Region = s.Region
+		changed("region", old.Region, s.Region)
+	}
+	if s.RequireLogin != old.RequireLogin {
+		v := s.RequireLogin
+		req.RequireLogin = &v
+		changed("require_login", old.RequireLogin, s.RequireLogin)
+	}
+	if s.AppAccessPolicy != old.AppAccessPolicy {
+		v := s.AppAccessPolicy
+		req.AppAccessPolicy = &v
+		changed("app_access_policy", old.AppAccessPolicy, s.AppAccessPolicy)
+	}
+	if !slices.Equal(s.GoogleScopes, old.GoogleScopes) {
+		// An empty, non-nil list clears the scopes on the server.
+		req.GoogleScopes = append([]string{}, s.GoogleScopes...)
+		changed("google_scopes", old.GoogleScopes, s.GoogleScopes)
+	}
+	if !slices.Equal(s.MicrosoftScopes, old.MicrosoftScopes) {
+		req.MicrosoftScopes = append([]string{}, s.MicrosoftScopes...)
+		changed("microsoft_scopes", old.MicrosoftScopes, s.MicrosoftScopes)
+	}
+
+	switch {
+	case s.Autoscaling == nil && old.Autoscaling != nil:
+		return req, nil, errors.New("autoscaling can't be turned off here; keep the block and set min_replicas equal to max_replicas to pin the count")
+	case s.Autoscaling != nil && (old.Autoscaling == nil || *s.Autoscaling != *old.Autoscaling):
+		a := *s.Autoscaling
+		cfg := AutoscalingConfig{MinReplicas: &a.MinReplicas, MaxReplicas: &a.MaxReplicas, TargetCPUPercent: &a.TargetCPUPercent}
+		if err := cfg.Validate(); err != nil {
+			return req, nil, err
+		}
+		req.Autoscaling = &cfg
+		from := "off"
+		if old.Autoscaling != nil {
+			from = fmt.Sprintf("%+v", *old.Autoscaling)
+		}
+		changed("autoscaling", from, fmt.Sprintf("%+v", a))
+	}
+
+	var keys []string
+	for k := range s.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := s.Env[k]
+		if !EnvKeyRe.MatchString(k) {
+			return req, nil, fmt.Errorf("invalid env var name %q", k)
+		}
+		oldV, ok := old.Env[k]
+		if ok && oldV == v {
+			continue
+		}
+		if req.EnvironmentVariables == nil {
+			req.EnvironmentVariables = map[string]string{}
+		}
+		req.EnvironmentVariables[k] = v
+		if ok {
+			changes = append(changes, fmt.Sprintf("env %s: changed", k))
+		} else {
+			changes = append(changes, fmt.Sprintf("env %s: added", k))
+		}
+	}
+	keys = keys[:0]
+	for k := range old.Env {
+		if _, ok := s.Env[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		req.RemoveEnvironmentVariables = append(req.RemoveEnvironmentVariables, k)
+		changes = append(changes, fmt.Sprintf("env %s: removed", k))
+	}
+	return req, changes, nil
+}
@@ -0,0 +1,90 @@
+package apps
+
+import (
+	"strings"
+	"testing"
+)
+
+func editBase() EditSpec {
+	return EditSpec{
+		Replicas: 2, CPU: "500m", Memory: "512Mi", Port: 3000, Region: "eu-west",
+		Env: map[string]string{"DEBUG": "1", "NODE_ENV": "production"},
+	}
+}
+
+func TestEditSpecDiff_NoChanges(t *testing.T) {
+	req, changes, err := editBase().Diff(editBase())
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("changes = %v, err = %v", changes, err)
+	}
+	if req.Replicas != nil || req.CPU != "" || req.EnvironmentVariables != nil || req.RemoveEnvironmentVariables != nil {
+		t.Errorf("req = %+v", req)
+	}
+}
+
+func TestEditSpecDiff_OnlyChangedFields(t *testing.T) {
+	edited := editBase()
+	edited.Memory = "1Gi"
+	edited.Env = map[string]string{"NODE_ENV": "staging", "NEW": "x"}
+	req, changes, err := edited.Diff(editBase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Memory != "1Gi" || req.CPU != "" || req.Replicas != nil || req.Port != nil {
+		t.Errorf("req = %+v", req)
+	}
+	if req.EnvironmentVariables["NODE_ENV"] != "staging" || req.EnvironmentVariables["NEW"] != "x" || len(req.EnvironmentVariables) != 2 {
+		t.Errorf("env = %v", req.EnvironmentVariables)
+	}
+	if strings.Join(req.RemoveEnvironmentVariables, ",") != "DEBUG" {
+		t.Errorf("remove = %v", req.RemoveEnvironmentVariables)
+	}
+	got := strings.Join(changes, "\n")
+	for _, want := range []string{"memory: 512Mi -> 1Gi", "env NEW: added", "env NODE_ENV: changed", "env DEBUG: removed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("changes missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "staging") {
+		t.Errorf("env values leaked into changes:\n%s", got)
+	}
+}
+
+func TestEditSpecDiff_Autoscaling(t *testing.T) {
+	edited := editBase()
+	edited.Autoscaling = &EditAutoscaling{MinReplicas: 2, MaxReplicas: 6, TargetCPUPercent: 70}
+	req, _, err := edited.Diff(editBase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Autoscaling == nil || *req.Autoscaling.MaxReplicas != 6 {
+		t.Errorf("autoscaling = %+v", req.Autoscaling)
+	}
+
+	edited.Replicas = 5
+	if _, _, err := edited.Diff(editBase()); err == nil {
+		t.Error("expected error changing replicas under autoscaling")
+	}
+
+	old := editBase()
+	old.Autoscaling = &EditAutoscaling{MinReplicas: 2, MaxReplicas: 6, TargetCPUPercent: 70}
+	if _, _, err := editBase().Diff(old); err == nil {
+		t.Error("expected error removing the autoscaling block")
+	}
+}
+
+func TestEditSpecDiff_Invalid(t *testing.T) {
+	for name, mutate := range map[string]func(*EditSpec){
+		"port":     func(s *EditSpec) { s.Port = 70000 },
+		"region":   func(s *EditSpec) { s.Region = "Mars" },
+		"env name": func(s *EditSpec) { s.Env["BAD-NAME"] = "x" },
+		"replicas": func(s *EditSpec) { s.Replicas = -1 },
+	} {
+		edited := editBase()
+		edited.Env = map[string]string{"DEBUG": "1", "NODE_ENV": "production"}
+		mutate(&edited)
+		if _, _, err := edited.Diff(editBase()); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// EnvKeyRe matches a valid environment variable name.
+var EnvKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvVar is one environment variable of a deployment. Value is empty
+// unless the values were asked for.
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+// EnvResponse is the payload of GET and PATCH /deployments/{alias}/env.
+type EnvResponse struct {
+	Alias     string   `json:"alias"`
+	Variables []EnvVar `json:"variables"`
+	Message   string   `json:"message,omitempty"`
+}
+
+// EnvPatch is the body of PATCH /deployments/{alias}/env: Set is merged
+// into the existing variables, then the Unset names are removed.
+type EnvPatch struct {
+	Set   map[string]string `json:"set,omitempty"`
+	Unset []string          `json:"unset,omitempty"`
+}
+
+// ListEnv returns the environment variables of alias. Values are only
+// included when reveal is true.
+func ListEnv(ctx context.Context, apiURL, apiToken, alias string, reveal bool) (*EnvResponse, error) {
+	url := fmt.Sprintf("%s/api/deploy/deployments/%s/env", strings.TrimSuffix(apiURL, "/"), alias)
+	if reveal {
+		url += "?reveal=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	return doEnvRequest(req, apiToken)
+}
+
+// PatchEnv merges patch.Set into alias's environment and removes the
+// patch.Unset names. The deployment restarts to pick the change up.
+func PatchEnv(ctx context.Context, apiURL, apiToken, alias string, patch EnvPatch) (*EnvResponse, error) {
+	for k := range patch.Set {
+		if !EnvKeyRe.MatchString(k) {
+			return nil, fmt.Errorf("invalid variable name %q", k)
+		}
+	}
+	for _, k := range patch.Unset {
+		if !EnvKeyRe.MatchString(k) {
+			return nil, fmt.Errorf("invalid variable name %q", k)
+		}
+	}
+	payload, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	url := fmt.Sprintf("%s/api/deploy/deployments/%s/env", strings.TrimSuffix(apiURL, "/"), alias)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doEnvRequest(req, apiToken)
+}
+
+func doEnvRequest(req *http.Request, apiToken string) (*EnvResponse, error) {
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var out EnvResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
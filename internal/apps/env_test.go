@@ -0,0 +1,56 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListEnv_Reveal(t *testing.T) {
+	var sawURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawURL = r.URL.String()
+		w.Write([]byte(`{"alias":"shop","variables":[{"name":"NODE_ENV","value":"production"}]}`))
+	}))
+	defer srv.Close()
+
+	out, err := ListEnv(context.Background(), srv.URL, "tok", "shop", true)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if sawURL != "/api/deploy/deployments/shop/env?reveal=true" {
+		t.Errorf("url = %q", sawURL)
+	}
+	if len(out.Variables) != 1 || out.Variables[0].Value != "production" {
+		t.Errorf("unexpected: %+v", out)
+	}
+}
+
+func TestPatchEnv(t *testing.T) {
+	var sawMethod string
+	var sawBody EnvPatch
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawMethod = r.Method
+		_ = json.NewDecoder(r.Body).Decode(&sawBody)
+		w.Write([]byte(`{"alias":"shop","variables":[{"name":"NODE_ENV"}]}`))
+	}))
+	defer srv.Close()
+
+	_, err := PatchEnv(context.Background(), srv.URL, "tok", "shop", EnvPatch{Set: map[string]string{"NODE_ENV": "production"}, Unset: []string{"DEBUG"}})
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if sawMethod != http.MethodPatch || sawBody.Set["NODE_ENV"] != "production" || len(sawBody.Unset) != 1 || sawBody.Unset[0] != "DEBUG" {
+		t.Errorf("request: %s %+v", sawMethod, sawBody)
+	}
+}
+
+func TestPatchEnv_RejectsInvalidName(t *testing.T) {
+	_, err := PatchEnv(context.Background(), "http://unused", "tok", "shop", EnvPatch{Unset: []string{"BAD-NAME"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid variable name") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/tunnel"
+	"github.com/gorilla/websocket"
+)
+
+// Exec stream channels. Every WebSocket message is binary and starts with
+// one channel byte, followed by the payload (the Kubernetes exec framing).
+const (
+	ExecChannelStdin  byte = 0
+	ExecChannelStdout byte = 1
+	ExecChannelStderr byte = 2
+	// ExecChannelStatus carries one JSON ExecStatus when the command ends.
+	ExecChannelStatus byte = 3
+	// ExecChannelResize carries a JSON TermSize from the client.
+	ExecChannelResize byte = 4
+)
+
+// TermSize is a terminal size in character cells.
+type TermSize struct {
+	Cols uint16 `json:"cols"`
+	Rows uint16 `json:"rows"`
+}
+
+// ExecStatus is the final message of an exec session.
+type ExecStatus struct {
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ExecOptions configures an exec session in a running container.
+type ExecOptions struct {
+	Command []string
+	// Service picks the container in a multi-service deployment; empty
+	// means the public service.
+	Service string
+	// TTY asks for a pseudo-terminal: stdout and stderr are merged and the
+	// remote side handles line editing and signals (Ctrl-C arrives as a
+	// byte on Stdin).
+	TTY bool
+	// Stdin, when non-nil, is forwarded to the command. EOF on it closes
+	// the remote stdin.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Resize, when non-nil, delivers terminal size changes (TTY only).
+	Resize <-chan TermSize
+}
+
+// Exec runs opts.Command in alias's running container over the
+// /deployments/{alias}/exec WebSocket and returns the command's exit code.
+// The error is non-nil only when the session could not be set up or broke
+// before the command finished.
+func Exec(ctx context.Context, apiURL, apiToken, alias string, opts ExecOptions) (int, error) {
+	if len(opts.Command) == 0 {
+		return 0, errors.New("no command given")
+	}
+	conn, err := tunnel.Dial(ctx, apiURL, "/deployments/"+alias+"/exec", execQuery(opts), apiToken)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the reader when ctx is cancelled.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// gorilla/websocket allows one concurrent writer.
+	var writeMu sync.Mutex
+	send := func(channel byte, payload []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(websocket.BinaryMessage, append([]byte{channel}, payload...))
+	}
+
+	if opts.Stdin != nil {
+		go func() {
+			buf := make([]byte, 32*1024)
+			for {
+				n, rerr := opts.Stdin.Read(buf)
+				if n > 0 {
+					if send(ExecChannelStdin, buf[:n]) != nil {
+						return
+					}
+				}
+				if rerr != nil {
+					// An empty stdin message signals EOF to the server.
+					_ = send(ExecChannelStdin, nil)
+					return
+				}
+			}
+		}()
+	}
+	if opts.Resize != nil {
+		go func() {
+			for size := range opts.Resize {
+				payload, _ := json.Marshal(size)
+				if send(ExecChannelResize, payload) != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			return 0, fmt.Errorf("exec stream closed before the command finished: %w", err)
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		payload := msg[1:]
+		switch msg[0] {
+		case ExecChannelStdout:
+			if opts.Stdout != nil {
+				_, _ = opts.Stdout.Write(payload)
+			}
+		case ExecChannelStderr:
+			if opts.Stderr != nil {
+				_, _ = opts.Stderr.Write(payload)
+			}
+		case ExecChannelStatus:
+			var st ExecStatus
+			if err := json.Unmarshal(payload, &st); err != nil {
+				return 0, fmt.Errorf("decode exec status: %w", err)
+			}
+			if st.Error != "" {
+				return st.ExitCode, errors.New(st.Error)
+			}
+			return st.ExitCode, nil
+		}
+	}
+}
+
+// execQuery encodes the exec session options as query parameters.
+func execQuery(opts ExecOptions) url.Values {
+	q := url.Values{}
+	for _, arg := range opts.Command {
+		q.Add("command", arg)
+	}
+	if opts.Service != "" {
+		q.Set("service", opts.Service)
+	}
+	if opts.TTY {
+		q.Set("tty", "true")
+	}
+	if opts.Stdin != nil {
+		q.Set("stdin", "true")
+	}
+	return q
+}
@@ -0,0 +1,106 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestExec(t *testing.T) {
+	var (
+		sawPath    string
+		sawAuth    string
+		sawCommand []string
+		sawTTY     string
+		sawStdin   []byte
+	)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		sawAuth = r.Header.Get("Authorization")
+		sawCommand = r.URL.Query()["command"]
+		sawTTY = r.URL.Query().Get("tty")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// Read until stdin EOF (an empty stdin frame), then answer.
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if len(msg) == 1 {
+				break
+			}
+			sawStdin = append(sawStdin, msg[1:]...)
+		}
+		conn.WriteMessage(websocket.BinaryMessage, append([]byte{ExecChannelStdout}, "hello\n"...))
+		conn.WriteMessage(websocket.BinaryMessage, append([]byte{ExecChannelStderr}, "oops\n"...))
+		conn.WriteMessage(websocket.BinaryMessage, append([]byte{ExecChannelStatus}, `{"exit_code":3}`...))
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	code, err := Exec(context.Background(), srv.URL, "tok", "shop", ExecOptions{
+		Command: []string{"ls", "-la"},
+		TTY:     true,
+		Stdin:   strings.NewReader("input"),
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+	})
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if code != 3 {
+		t.Errorf("exit code = %d, want 3", code)
+	}
+	if sawPath != "/api/deploy/deployments/shop/exec" || sawAuth != "Bearer tok" || sawTTY != "true" {
+		t.Errorf("request: path=%q auth=%q tty=%q", sawPath, sawAuth, sawTTY)
+	}
+	if strings.Join(sawCommand, " ") != "ls -la" {
+		t.Errorf("command = %q", sawCommand)
+	}
+	if string(sawStdin) != "input" {
+		t.Errorf("stdin = %q", sawStdin)
+	}
+	if stdout.String() != "hello\n" || stderr.String() != "oops\n" {
+		t.Errorf("stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+}
+
+func TestExec_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":"error","error":{"code":"NOT_FOUND","message":"deployment not found"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := Exec(context.Background(), srv.URL, "tok", "nope", ExecOptions{Command: []string{"ls"}})
+	if err == nil || !strings.Contains(err.Error(), "NOT_FOUND") {
+		t.Errorf("err = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestExec_StreamClosedEarly(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	defer srv.Close()
+
+	_, err := Exec(context.Background(), srv.URL, "tok", "shop", ExecOptions{Command: []string{"ls"}})
+	if err == nil || !strings.Contains(err.Error(), "closed before the command finished") {
+		t.Errorf("err = %v", err)
+	}
+}
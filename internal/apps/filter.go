@@ -0,0 +1,112 @@
+package apps
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ListOptions filters and pages ListApps. Filters are sent to the API as query
+// parameters and applied again to the response, so they also work against
+// servers that ignore them.
+type ListOptions struct {
+	// Status keeps only deployments in this status.
+	Status DeploymentStatus
+	// NameContains keeps aliases containing this substring (case-insensitive).
+	NameContains string
+	// Labels keeps deployments carrying every label. An empty value
+	// matches any value of that key.
+	Labels map[string]string
+
+	// Limit caps the number of deployments returned (0 = all). With Page
+	// it is also the page size.
+	Limit int
+	// Page fetches only this 1-based page instead of every page.
+	Page int
+}
+
+// ListStatuses are the statuses accepted by ListOptions.Status.
+var ListStatuses = []DeploymentStatus{
+	DeploymentStatusRunning,
+	DeploymentStatusFailed,
+	DeploymentStatusUnhealthy,
+	DeploymentStatusBuilding,
+	DeploymentStatusStarting,
+	DeploymentStatusDeleting,
+	DeploymentStatusStopped,
+}
+
+// ParseStatusFilter validates a --status value.
+func ParseStatusFilter(s string) (DeploymentStatus, error) {
+	if s == "" {
+		return "", nil
+	}
+	names := make([]string, len(ListStatuses))
+	for i, st := range ListStatuses {
+		if string(st) == s {
+			return st, nil
+		}
+		names[i] = string(st)
+	}
+	return "", fmt.Errorf("unknown status %q (expected one of: %s)", s, strings.Join(names, ", "))
+}
+
+// ParseLabelFilters parses repeatable --label values of the form key=value
+// or key (any value).
+func ParseLabelFilters(specs []string) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		key, value, _ := strings.Cut(spec, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid --label %q (expected key=value or key)", spec)
+		}
+		labels[key] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+// query encodes the filters as API query parameters.
+func (o ListOptions) query() url.Values {
+	q := url.Values{}
+	if o.Status != "" {
+		q.Set("status", string(o.Status))
+	}
+	if o.NameContains != "" {
+		q.Set("name_contains", o.NameContains)
+	}
+	keys := make([]string, 0, len(o.Labels))
+	for k := range o.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if v := o.Labels[k]; v != "" {
+			q.Add("label", k+"="+v)
+		} else {
+			q.Add("label", k)
+		}
+	}
+	return q
+}
+
+// Match reports whether d passes every filter in o.
+func (o ListOptions) Match(d Deployment) bool {
+	if o.Status != "" && d.Status != o.Status {
+		return false
+	}
+	if o.NameContains != "" && !strings.Contains(strings.ToLower(d.Alias), strings.ToLower(o.NameContains)) {
+		return false
+	}
+	for k, want := range o.Labels {
+		got, ok := d.Labels[k]
+		if !ok || (want != "" && got != want) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package apps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseLabelFilters(t *testing.T) {
+	got, err := ParseLabelFilters([]string{"team=payments", "tier"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got["team"] != "payments" || got["tier"] != "" || len(got) != 2 {
+		t.Errorf("labels = %v", got)
+	}
+	if _, err := ParseLabelFilters([]string{"=x"}); err == nil {
+		t.Error("expected error for empty key")
+	}
+}
+
+func TestParseStatusFilter(t *testing.T) {
+	if st, err := ParseStatusFilter("unhealthy"); err != nil || st != DeploymentStatusUnhealthy {
+		t.Errorf("unhealthy: %v %v", st, err)
+	}
+	if _, err := ParseStatusFilter("sleeping"); err == nil {
+		t.Error("expected error for unknown status")
+	}
+}
+
+func TestListApps_FiltersPushedDownAndAppliedLocally(t *testing.T) {
+	var sawQuery string
+	// This server ignores the filters and returns everything.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"total":3,"deployments":[
+			{"alias":"shop-api","status":"running","labels":{"team":"payments"}},
+			{"alias":"shop-web","status":"failed","labels":{"team":"payments"}},
+			{"alias":"blog","status":"running"}]}`))
+	}))
+	defer srv.Close()
+
+	opts := ListOptions{Status: DeploymentStatusRunning, NameContains: "SHOP", Labels: map[string]string{"team": "payments"}}
+	resp, err := ListApps(context.Background(), srv.URL, "tok", opts)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if sawQuery != "label=team%3Dpayments&limit=100&name_contains=SHOP&offset=0&status=running" {
+		t.Errorf("query = %q", sawQuery)
+	}
+	if len(resp.Deployments) != 1 || resp.Deployments[0].Alias != "shop-api" || resp.Total != 1 {
+		t.Errorf("unexpected result: %+v", resp)
+	}
+}
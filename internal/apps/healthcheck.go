@@ -0,0 +1,67 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// HealthCheckResponse is the payload returned by POST
+// /deployments/{alias}/health-check: the probe that just ran and the
+// deployment status after it.
+type HealthCheckResponse struct {
+	Alias       string           `json:"alias"`
+	Status      DeploymentStatus `json:"status"`
+	HealthCheck HealthCheckInfo  `json:"health_check"`
+}
+
+// Healthy reports whether the probe passed.
+func (r *HealthCheckResponse) Healthy() bool {
+	return r.HealthCheck.Status == "healthy"
+}
+
+// CheckHealth asks the platform to run alias's health probe now instead of
+// waiting for the next scheduled one. The probe itself can take a while,
+// hence the longer timeout.
+func CheckHealth(ctx context.Context, apiURL, apiToken, alias string) (*HealthCheckResponse, error) {
+	url := fmt.Sprintf("%s/api/deploy/deployments/%s/health-check",
+		strings.TrimSuffix(apiURL, "/"), alias)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var out HealthCheckResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,42 @@
+package apps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckHealth(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Method + " " + r.URL.Path
+		w.Write([]byte(`{"alias":"shop","status":"unhealthy","health_check":{"status":"unhealthy","response_time_ms":5000,"failure_count":3,"last_error":"timeout"}}`))
+	}))
+	defer srv.Close()
+
+	out, err := CheckHealth(context.Background(), srv.URL, "tok", "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "POST /api/deploy/deployments/shop/health-check" {
+		t.Errorf("request = %s", got)
+	}
+	if out.Healthy() || out.HealthCheck.LastError != "timeout" || out.HealthCheck.ResponseTimeMs != 5000 {
+		t.Errorf("out = %+v", out)
+	}
+}
+
+func TestCheckHealth_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"status":"error","error":{"code":"NOT_RUNNING","message":"deployment is stopped"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := CheckHealth(context.Background(), srv.URL, "tok", "shop")
+	if err == nil || !strings.Contains(err.Error(), "NOT_RUNNING") {
+		t.Errorf("err = %v", err)
+	}
+}
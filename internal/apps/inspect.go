@@ -0,0 +1,100 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// DeploymentDetail is the full record returned by GET /deployments/{alias}:
+// the list fields plus the port, service names, env var names (never
+// values), health history and recent errors.
+type DeploymentDetail struct {
+	Deployment
+	Port int `json:"port,omitempty"`
+	// Services names the services of a multi-service deployment.
+	Services      []string          `json:"services,omitempty"`
+	EnvVarNames   []string          `json:"env_var_names,omitempty"`
+	HealthHistory []HealthCheckInfo `json:"health_history,omitempty"`
+	RecentErrors  []DeploymentError `json:"recent_errors,omitempty"`
+	Autoscaler    *AutoscalerState  `json:"autoscaler,omitempty"`
+}
+
+// DeploymentError is one failure recorded against a deployment (build,
+// start-up or health check), newest first in DeploymentDetail.
+type DeploymentError struct {
+	At           time.Time `json:"at"`
+	Code         string    `json:"code"`
+	Message      string    `json:"message"`
+	DeploymentID string    `json:"deployment_id,omitempty"`
+}
+
+// GetApp fetches the full record of the deployment with the given alias.
+func GetApp(ctx context.Context, apiURL, apiToken, alias string) (*DeploymentDetail, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	apiURL = strings.TrimSuffix(apiURL, "/")
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/deploy/deployments/%s", apiURL, alias), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+			return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var detail DeploymentDetail
+	if err := json.Unmarshal(body, &detail); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+	return &detail, nil
+}
+
+// AppExists reports whether a deployment with the given alias exists.
+func AppExists(ctx context.Context, apiURL, apiToken, alias string) (bool, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	apiURL = strings.TrimSuffix(apiURL, "/")
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/deploy/deployments/%s", apiURL, alias), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return false, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return false, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
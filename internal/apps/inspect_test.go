@@ -0,0 +1,72 @@
+package apps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetApp(t *testing.T) {
+	var sawPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		w.Write([]byte(`{"id":"dep_1","alias":"shop","status":"running","image_id":"sha256:abc",
+			"cpu":"500m","memory":"512Mi","port":3000,"replicas":2,"ready_replicas":1,
+			"env_var_names":["DATABASE_URL","NODE_ENV"],
+			"health_history":[{"status":"healthy","response_time_ms":12}],
+			"recent_errors":[{"at":"2026-01-01T00:00:00Z","code":"HEALTH_CHECK_FAILED","message":"timeout"}]}`))
+	}))
+	defer srv.Close()
+
+	d, err := GetApp(context.Background(), srv.URL, "tok", "shop")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if sawPath != "/api/deploy/deployments/shop" {
+		t.Errorf("path: %q", sawPath)
+	}
+	if d.Alias != "shop" || d.ImageID != "sha256:abc" || d.Port != 3000 || d.ReadyReplicas != 1 {
+		t.Errorf("unexpected detail: %+v", d)
+	}
+	if len(d.EnvVarNames) != 2 || len(d.HealthHistory) != 1 || d.RecentErrors[0].Code != "HEALTH_CHECK_FAILED" {
+		t.Errorf("unexpected lists: %+v", d)
+	}
+}
+
+func TestGetApp_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":"error","error":{"code":"NOT_FOUND","message":"deployment not found"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := GetApp(context.Background(), srv.URL, "tok", "nope")
+	if err == nil || !strings.Contains(err.Error(), "NOT_FOUND") {
+		t.Errorf("err = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestAppExists(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/deploy/deployments/shop":
+			w.Write([]byte(`{"alias":"shop"}`))
+		case "/api/deploy/deployments/new":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	for alias, want := range map[string]bool{"shop": true, "new": false} {
+		if got, err := AppExists(context.Background(), srv.URL, "tok", alias); got != want || err != nil {
+			t.Errorf("AppExists(%s) = %v, %v; want %v", alias, got, err, want)
+		}
+	}
+	if _, err := AppExists(context.Background(), srv.URL, "tok", "broken"); err == nil {
+		t.Error("want an error for HTTP 500")
+	}
+}
@@ -0,0 +1,142 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// Job is one scheduled (cron) job, as returned by GET /jobs. Name is the
+// jobs: key from dibbla.yaml; single-app `deploy --schedule` jobs are
+// named after the alias.
+type Job struct {
+	Alias         string     `json:"alias"`
+	Name          string     `json:"name"`
+	Schedule      string     `json:"schedule"`
+	Status        string     `json:"status"`
+	LastRunAt     *time.Time `json:"last_run_at"`
+	LastRunStatus string     `json:"last_run_status,omitempty"`
+	NextRunAt     *time.Time `json:"next_run_at"`
+}
+
+// JobsListResponse is the payload returned by GET /jobs.
+type JobsListResponse struct {
+	Jobs  []Job `json:"jobs"`
+	Total int   `json:"total"`
+}
+
+// JobRun is a single execution of a job. Trigger is "schedule" for cron
+// ticks and "manual" for runs started with TriggerJob.
+type JobRun struct {
+	ID         string     `json:"id"`
+	Alias      string     `json:"alias"`
+	Job        string     `json:"job"`
+	Status     string     `json:"status"`
+	Trigger    string     `json:"trigger"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	ExitCode   *int       `json:"exit_code,omitempty"`
+}
+
+// JobRunsResponse is the payload returned by GET /jobs/{alias}/runs.
+type JobRunsResponse struct {
+	Runs []JobRun `json:"runs"`
+}
+
+// ListJobs returns every scheduled job in the caller's organization.
+func ListJobs(ctx context.Context, apiURL, apiToken string) (*JobsListResponse, error) {
+	var out JobsListResponse
+	u := strings.TrimSuffix(apiURL, "/") + "/api/deploy/jobs"
+	if err := doJobsRequest(ctx, http.MethodGet, u, apiToken, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListJobRuns returns the most recent runs of alias's jobs, newest first.
+// job narrows a deployment with several jobs to one of them; limit <= 0
+// leaves the page size to the server.
+func ListJobRuns(ctx context.Context, apiURL, apiToken, alias, job string, limit int) (*JobRunsResponse, error) {
+	q := url.Values{}
+	if job != "" {
+		q.Set("job", job)
+	}
+	if limit > 0 {
+		q.Set("limit", fmt.Sprint(limit))
+	}
+	u := fmt.Sprintf("%s/api/deploy/jobs/%s/runs", strings.TrimSuffix(apiURL, "/"), alias)
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	var out JobRunsResponse
+	if err := doJobsRequest(ctx, http.MethodGet, u, apiToken, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TriggerJob starts a run of alias's job immediately, outside its
+// schedule. job is required only when the deployment declares more than
+// one job.
+func TriggerJob(ctx context.Context, apiURL, apiToken, alias, job string) (*JobRun, error) {
+	var payload []byte
+	if job != "" {
+		payload, _ = json.Marshal(map[string]string{"job": job})
+	}
+	u := fmt.Sprintf("%s/api/deploy/jobs/%s/runs", strings.TrimSuffix(apiURL, "/"), alias)
+	var out JobRun
+	if err := doJobsRequest(ctx, http.MethodPost, u, apiToken, payload, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// doJobsRequest issues a jobs API call and decodes a 2xx body into out.
+func doJobsRequest(ctx context.Context, method, u, apiToken string, payload []byte, out any) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("decode response: %w (body=%s)", err, string(respBody))
+		}
+		return nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error.Code != "" {
+		return fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+}
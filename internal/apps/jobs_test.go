@@ -0,0 +1,71 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListJobRuns_Query(t *testing.T) {
+	var sawPath, sawQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath, sawQuery = r.URL.Path, r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"runs": []map[string]any{{"id": "run-1", "status": "succeeded", "trigger": "schedule", "exit_code": 0}},
+		})
+	}))
+	defer srv.Close()
+
+	out, err := ListJobRuns(context.Background(), srv.URL, "tok", "nightly", "report", 5)
+	if err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	if sawPath != "/api/deploy/jobs/nightly/runs" {
+		t.Errorf("path: %q", sawPath)
+	}
+	if sawQuery != "job=report&limit=5" {
+		t.Errorf("query: %q", sawQuery)
+	}
+	if len(out.Runs) != 1 || out.Runs[0].ExitCode == nil || *out.Runs[0].ExitCode != 0 {
+		t.Errorf("unexpected runs: %+v", out.Runs)
+	}
+}
+
+func TestTriggerJob_HappyPath(t *testing.T) {
+	var sawMethod string
+	var sawBody map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawMethod = r.Method
+		_ = json.NewDecoder(r.Body).Decode(&sawBody)
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "run-2", "alias": "nightly", "status": "pending", "trigger": "manual"})
+	}))
+	defer srv.Close()
+
+	run, err := TriggerJob(context.Background(), srv.URL, "tok", "nightly", "report")
+	if err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	if sawMethod != http.MethodPost || sawBody["job"] != "report" {
+		t.Errorf("method=%s body=%v", sawMethod, sawBody)
+	}
+	if run.ID != "run-2" || run.Trigger != "manual" {
+		t.Errorf("unexpected run: %+v", run)
+	}
+}
+
+func TestTriggerJob_NotAJob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":"error","error":{"code":"JOB_NOT_FOUND","message":"nightly has no scheduled jobs"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := TriggerJob(context.Background(), srv.URL, "tok", "nightly", "")
+	if err == nil || !strings.Contains(err.Error(), "JOB_NOT_FOUND") {
+		t.Fatalf("want JOB_NOT_FOUND error, got %v", err)
+	}
+}
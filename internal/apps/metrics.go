@@ -0,0 +1,93 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// MetricsSample is one point of resource usage for a deployment. Limits
+// are zero when the deployment has none set.
+type MetricsSample struct {
+	At                 time.Time `json:"at"`
+	CPUMillicores      float64   `json:"cpu_millicores"`
+	CPULimitMillicores float64   `json:"cpu_limit_millicores,omitempty"`
+	MemoryBytes        int64     `json:"memory_bytes"`
+	MemoryLimitBytes   int64     `json:"memory_limit_bytes,omitempty"`
+	Restarts           int       `json:"restarts"`
+	RequestsPerSecond  float64   `json:"requests_per_second"`
+	P95LatencyMs       float64   `json:"p95_latency_ms"`
+}
+
+// MetricsResponse is the payload of GET /deployments/{alias}/metrics:
+// the latest sample plus a series covering the requested window, oldest
+// first, one point every StepSeconds.
+type MetricsResponse struct {
+	Alias       string          `json:"alias"`
+	Service     string          `json:"service,omitempty"`
+	Current     MetricsSample   `json:"current"`
+	Series      []MetricsSample `json:"series"`
+	StepSeconds int             `json:"step_seconds,omitempty"`
+}
+
+// MetricsOptions narrows a metrics query. A zero Window leaves the range
+// to the server; an empty Service means the whole deployment.
+type MetricsOptions struct {
+	Window  time.Duration
+	Service string
+}
+
+// GetMetrics returns current and recent resource usage for alias.
+func GetMetrics(ctx context.Context, apiURL, apiToken, alias string, opts MetricsOptions) (*MetricsResponse, error) {
+	q := url.Values{}
+	if opts.Window > 0 {
+		q.Set("window_seconds", strconv.Itoa(int(opts.Window/time.Second)))
+	}
+	if opts.Service != "" {
+		q.Set("service", opts.Service)
+	}
+	u := strings.TrimSuffix(apiURL, "/") + "/api/deploy/deployments/" + alias + "/metrics"
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var out MetricsResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
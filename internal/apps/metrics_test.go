@@ -0,0 +1,46 @@
+package apps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetMetrics(t *testing.T) {
+	var sawPath, sawQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		sawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"alias":"shop","step_seconds":60,
+			"current":{"cpu_millicores":120,"memory_bytes":104857600,"restarts":1,"requests_per_second":4.5,"p95_latency_ms":87},
+			"series":[{"at":"2026-01-01T00:00:00Z","cpu_millicores":100},{"at":"2026-01-01T00:01:00Z","cpu_millicores":120}]}`))
+	}))
+	defer srv.Close()
+
+	m, err := GetMetrics(context.Background(), srv.URL, "tok", "shop", MetricsOptions{Window: time.Hour, Service: "web"})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if sawPath != "/api/deploy/deployments/shop/metrics" || sawQuery != "service=web&window_seconds=3600" {
+		t.Errorf("request: path=%q query=%q", sawPath, sawQuery)
+	}
+	if m.Current.CPUMillicores != 120 || m.Current.P95LatencyMs != 87 || len(m.Series) != 2 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestGetMetrics_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":"error","error":{"code":"NOT_FOUND","message":"deployment not found"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := GetMetrics(context.Background(), srv.URL, "tok", "nope", MetricsOptions{})
+	if err == nil || !strings.Contains(err.Error(), "NOT_FOUND") {
+		t.Errorf("err = %v, want NOT_FOUND", err)
+	}
+}
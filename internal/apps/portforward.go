@@ -0,0 +1,61 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/tunnel"
+)
+
+// PortForwardOptions picks the container port to reach.
+type PortForwardOptions struct {
+	// Port is the container port connections are forwarded to.
+	Port int
+	// Service picks the container in a multi-service deployment; empty
+	// means the public service.
+	Service string
+}
+
+// ParsePortSpec parses a port-forward spec: "8080:3000" forwards local
+// port 8080 to container port 3000, and "3000" uses the same port on both
+// ends. A local port of 0 picks a free one.
+func ParsePortSpec(spec string) (local, remote int, err error) {
+	localStr, remoteStr, found := strings.Cut(spec, ":")
+	if !found {
+		remoteStr = localStr
+	}
+	if local, err = parsePort(localStr, true); err == nil {
+		remote, err = parsePort(remoteStr, false)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port spec %q: %w (expected [local:]remote, e.g. 8080:3000)", spec, err)
+	}
+	return local, remote, nil
+}
+
+func parsePort(s string, allowZero bool) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 65535 || (n == 0 && !allowZero) {
+		return 0, fmt.Errorf("bad port %q", s)
+	}
+	return n, nil
+}
+
+// PortForward accepts connections on ln until ctx is cancelled and tunnels
+// each one to opts.Port in alias's running container over its own
+// WebSocket (/deployments/{alias}/port-forward). Per-connection failures
+// are reported on notice and don't stop the listener. ln is closed on
+// return.
+func PortForward(ctx context.Context, apiURL, apiToken, alias string, opts PortForwardOptions, ln net.Listener, notice io.Writer) error {
+	q := url.Values{}
+	q.Set("port", strconv.Itoa(opts.Port))
+	if opts.Service != "" {
+		q.Set("service", opts.Service)
+	}
+	return tunnel.Forward(ctx, apiURL, apiToken, "/deployments/"+alias+"/port-forward", q, ln, notice)
+}
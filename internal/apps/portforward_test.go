@@ -0,0 +1,130 @@
+package apps
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestParsePortSpec(t *testing.T) {
+	cases := []struct {
+		spec          string
+		local, remote int
+	}{
+		{"8080:3000", 8080, 3000},
+		{"3000", 3000, 3000},
+		{"0:5432", 0, 5432},
+	}
+	for _, c := range cases {
+		l, r, err := ParsePortSpec(c.spec)
+		if err != nil || l != c.local || r != c.remote {
+			t.Errorf("%q: got %d:%d, %v", c.spec, l, r, err)
+		}
+	}
+	for _, bad := range []string{"", "abc", "8080:", "8080:0", "70000:80", "0"} {
+		if _, _, err := ParsePortSpec(bad); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestPortForward_EchoesThroughTunnel(t *testing.T) {
+	var sawPort, sawPath string
+	upgrader := websocket.Upgrader{}
+	// The fake server echoes every binary message back, upper-cased.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		sawPort = r.URL.Query().Get("port")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(websocket.BinaryMessage, []byte(strings.ToUpper(string(msg))))
+		}
+	}))
+	defer srv.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- PortForward(ctx, srv.URL, "tok", "shop", PortForwardOptions{Port: 3000}, ln, io.Discard)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	conn.Write([]byte("ping\n"))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "PING\n" {
+		t.Errorf("read %q, %v", line, err)
+	}
+	conn.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("PortForward: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("PortForward did not stop after cancel")
+	}
+	if sawPath != "/api/deploy/deployments/shop/port-forward" || sawPort != "3000" {
+		t.Errorf("request: path=%q port=%q", sawPath, sawPort)
+	}
+}
+
+func TestPortForward_ReportsRefusedTunnel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":"error","error":{"code":"NOT_FOUND","message":"deployment not found"}}`))
+	}))
+	defer srv.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var notice bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- PortForward(ctx, srv.URL, "tok", "nope", PortForwardOptions{Port: 3000}, ln, &notice)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	// The tunnel is refused, so the local connection is closed.
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Error("expected the local connection to be closed")
+	}
+	cancel()
+	<-done
+	if !strings.Contains(notice.String(), "NOT_FOUND") {
+		t.Errorf("notice = %q", notice.String())
+	}
+}
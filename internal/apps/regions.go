@@ -0,0 +1,69 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// RegionRe matches a region ID such as eu-west or us-east. Whether the
+// region exists is left to the server (see ListRegions).
+var RegionRe = regexp.MustCompile(`^[a-z]+(-[a-z0-9]+)+$`)
+
+// Region is one location deployments can run in. Default marks the region
+// used when a deploy doesn't name one.
+type Region struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Location string `json:"location,omitempty"`
+	Default  bool   `json:"default,omitempty"`
+}
+
+// RegionsListResponse is the payload returned by GET /regions.
+type RegionsListResponse struct {
+	Regions []Region `json:"regions"`
+}
+
+// ListRegions returns the regions available to the caller's organization.
+func ListRegions(ctx context.Context, apiURL, apiToken string) (*RegionsListResponse, error) {
+	url := strings.TrimSuffix(apiURL, "/") + "/api/deploy/regions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var out RegionsListResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,48 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegionRe(t *testing.T) {
+	for _, ok := range []string{"eu-west", "us-east", "ap-southeast-2"} {
+		if !RegionRe.MatchString(ok) {
+			t.Errorf("%q rejected", ok)
+		}
+	}
+	for _, bad := range []string{"", "eu", "EU-West", "eu_west", "eu-", "-west"} {
+		if RegionRe.MatchString(bad) {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
+
+func TestListRegions(t *testing.T) {
+	var sawPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"regions": []map[string]any{
+				{"id": "eu-west", "name": "EU West", "location": "Dublin", "default": true},
+				{"id": "us-east", "name": "US East", "location": "Virginia"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	out, err := ListRegions(context.Background(), srv.URL, "tok")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if sawPath != "/api/deploy/regions" {
+		t.Errorf("path: %q", sawPath)
+	}
+	if len(out.Regions) != 2 || out.Regions[0].ID != "eu-west" || !out.Regions[0].Default {
+		t.Errorf("unexpected regions: %+v", out.Regions)
+	}
+}
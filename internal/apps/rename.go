@@ -0,0 +1,84 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// AliasRe matches a valid deployment alias: a DNS label of lowercase
+// letters, digits and hyphens, not starting or ending with a hyphen.
+var AliasRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// RenameRequest is the body of POST /deployments/{alias}/rename.
+type RenameRequest struct {
+	NewAlias     string `json:"new_alias"`
+	KeepRedirect bool   `json:"keep_redirect,omitempty"`
+}
+
+// RenameResponse is the payload returned by a successful rename. Redirect
+// reports whether the old URL now redirects to the new one; it is false
+// when the platform doesn't support redirects even if one was requested.
+type RenameResponse struct {
+	Alias    string `json:"alias"`
+	OldAlias string `json:"old_alias"`
+	URL      string `json:"url"`
+	OldURL   string `json:"old_url,omitempty"`
+	Redirect bool   `json:"redirect"`
+	Message  string `json:"message,omitempty"`
+}
+
+// RenameApp changes alias to newAlias. The deployment's public URL follows
+// the alias; keepRedirect asks the platform to redirect the old URL.
+func RenameApp(ctx context.Context, apiURL, apiToken, alias, newAlias string, keepRedirect bool) (*RenameResponse, error) {
+	if !AliasRe.MatchString(newAlias) {
+		return nil, fmt.Errorf("invalid alias %q: use lowercase letters, digits and hyphens (max 63, no leading or trailing hyphen)", newAlias)
+	}
+	payload, err := json.Marshal(RenameRequest{NewAlias: newAlias, KeepRedirect: keepRedirect})
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/deploy/deployments/%s/rename", strings.TrimSuffix(apiURL, "/"), alias)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var out RenameResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,65 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAliasRe(t *testing.T) {
+	for _, ok := range []string{"shop", "shop-v2", "a", "9lives"} {
+		if !AliasRe.MatchString(ok) {
+			t.Errorf("%q should be valid", ok)
+		}
+	}
+	for _, bad := range []string{"", "-shop", "shop-", "Shop", "shop_v2", strings.Repeat("a", 64)} {
+		if AliasRe.MatchString(bad) {
+			t.Errorf("%q should be invalid", bad)
+		}
+	}
+}
+
+func TestRenameApp(t *testing.T) {
+	var sawPath string
+	var sawBody RenameRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&sawBody)
+		w.Write([]byte(`{"alias":"store","old_alias":"shop","url":"https://store.dibbla.com","redirect":true}`))
+	}))
+	defer srv.Close()
+
+	out, err := RenameApp(context.Background(), srv.URL, "tok", "shop", "store", true)
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if sawPath != "/api/deploy/deployments/shop/rename" || sawBody.NewAlias != "store" || !sawBody.KeepRedirect {
+		t.Errorf("request: path=%q body=%+v", sawPath, sawBody)
+	}
+	if out.Alias != "store" || !out.Redirect {
+		t.Errorf("unexpected response: %+v", out)
+	}
+}
+
+func TestRenameApp_RejectsInvalidAliasLocally(t *testing.T) {
+	_, err := RenameApp(context.Background(), "http://unused", "tok", "shop", "My_Shop", false)
+	if err == nil || !strings.Contains(err.Error(), "invalid alias") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRenameApp_Conflict(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"status":"error","error":{"code":"ALIAS_TAKEN","message":"alias store is already in use"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := RenameApp(context.Background(), srv.URL, "tok", "shop", "store", false)
+	if err == nil || !strings.Contains(err.Error(), "ALIAS_TAKEN") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package apps
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
 )
 
 // ServiceNameRe is the canonical service-name pattern enforced both client-
@@ -27,13 +30,24 @@ type RestartServiceResponse struct {
 // RestartService triggers a K8s rolling restart of the named service inside
 // alias's deployment. Returns the parsed response on 200, or a wrapped error
 // (with the server error code preserved in the message) for any other status.
-func RestartService(apiURL, apiToken, alias, service string) (*RestartServiceResponse, error) {
+func RestartService(ctx context.Context, apiURL, apiToken, alias, service string) (*RestartServiceResponse, error) {
 	if !ServiceNameRe.MatchString(service) {
 		return nil, fmt.Errorf("service name %q does not match %s", service, ServiceNameRe.String())
 	}
-	url := fmt.Sprintf("%s/api/deploy/deployments/%s/services/%s/restart",
-		strings.TrimSuffix(apiURL, "/"), alias, service)
-	req, err := http.NewRequest(http.MethodPost, url, nil)
+	return postRestart(ctx, fmt.Sprintf("%s/api/deploy/deployments/%s/services/%s/restart",
+		strings.TrimSuffix(apiURL, "/"), alias, service), apiToken)
+}
+
+// RestartApp triggers a rolling restart of every service in alias's
+// deployment, e.g. so it picks up changed secrets. Errors are reported as
+// for RestartService.
+func RestartApp(ctx context.Context, apiURL, apiToken, alias string) (*RestartServiceResponse, error) {
+	return postRestart(ctx, fmt.Sprintf("%s/api/deploy/deployments/%s/restart",
+		strings.TrimSuffix(apiURL, "/"), alias), apiToken)
+}
+
+func postRestart(ctx context.Context, url, apiToken string) (*RestartServiceResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -41,7 +55,7 @@ func RestartService(apiURL, apiToken, alias, service string) (*RestartServiceRes
 	req.Header.Set("Accept", "application/json")
 
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
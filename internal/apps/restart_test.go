@@ -1,6 +1,7 @@
 package apps
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -49,7 +50,7 @@ func TestRestartService_HappyPath(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	resp, err := RestartService(srv.URL, "tok", "myapp", "worker")
+	resp, err := RestartService(context.Background(), srv.URL, "tok", "myapp", "worker")
 	if err != nil {
 		t.Fatalf("restart: %v", err)
 	}
@@ -80,7 +81,7 @@ func TestRestartService_404(t *testing.T) {
 		})
 	}))
 	defer srv.Close()
-	_, err := RestartService(srv.URL, "tok", "myapp", "worker")
+	_, err := RestartService(context.Background(), srv.URL, "tok", "myapp", "worker")
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -90,7 +91,7 @@ func TestRestartService_404(t *testing.T) {
 }
 
 func TestRestartService_RejectsBadServiceName(t *testing.T) {
-	_, err := RestartService("http://api", "tok", "myapp", "Web")
+	_, err := RestartService(context.Background(), "http://api", "tok", "myapp", "Web")
 	if err == nil {
 		t.Fatal("expected error for bad service name")
 	}
@@ -109,7 +110,7 @@ func TestRestartService_500(t *testing.T) {
 		})
 	}))
 	defer srv.Close()
-	_, err := RestartService(srv.URL, "tok", "myapp", "worker")
+	_, err := RestartService(context.Background(), srv.URL, "tok", "myapp", "worker")
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -117,3 +118,23 @@ func TestRestartService_500(t *testing.T) {
 		t.Errorf("missing code: %v", err)
 	}
 }
+
+func TestRestartApp(t *testing.T) {
+	var sawPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		_ = json.NewEncoder(w).Encode(map[string]any{"alias": "myapp", "status": "restarted"})
+	}))
+	defer srv.Close()
+
+	resp, err := RestartApp(context.Background(), srv.URL, "tok", "myapp")
+	if err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	if sawPath != "/api/deploy/deployments/myapp/restart" {
+		t.Errorf("path: %q", sawPath)
+	}
+	if resp.Alias != "myapp" || resp.Service != "" {
+		t.Errorf("response: %+v", resp)
+	}
+}
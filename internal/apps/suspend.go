@@ -0,0 +1,75 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// SuspendResponse is the payload returned by POST
+// /deployments/{alias}/{stop,start} on success. Replicas is the count the
+// deployment now runs; PreviousReplicas is what stop saved and start
+// restores.
+type SuspendResponse struct {
+	Alias            string           `json:"alias"`
+	Status           DeploymentStatus `json:"status"`
+	Replicas         int32            `json:"replicas"`
+	PreviousReplicas int32            `json:"previous_replicas,omitempty"`
+	URL              string           `json:"url,omitempty"`
+	Message          string           `json:"message,omitempty"`
+}
+
+// StopApp scales alias to zero replicas, keeping its configuration and URL.
+// The platform remembers the replica count for StartApp.
+func StopApp(ctx context.Context, apiURL, apiToken, alias string) (*SuspendResponse, error) {
+	return suspendAction(ctx, apiURL, apiToken, alias, "stop")
+}
+
+// StartApp scales a stopped alias back to the replica count it had before
+// StopApp.
+func StartApp(ctx context.Context, apiURL, apiToken, alias string) (*SuspendResponse, error) {
+	return suspendAction(ctx, apiURL, apiToken, alias, "start")
+}
+
+func suspendAction(ctx context.Context, apiURL, apiToken, alias, action string) (*SuspendResponse, error) {
+	url := fmt.Sprintf("%s/api/deploy/deployments/%s/%s",
+		strings.TrimSuffix(apiURL, "/"), alias, action)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var out SuspendResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,54 @@
+package apps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStopAndStartApp(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/stop") {
+			w.Write([]byte(`{"alias":"staging","status":"stopped","replicas":0,"previous_replicas":3}`))
+			return
+		}
+		w.Write([]byte(`{"alias":"staging","status":"starting","replicas":3}`))
+	}))
+	defer srv.Close()
+
+	stopped, err := StopApp(context.Background(), srv.URL, "tok", "staging")
+	if err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if stopped.Status != DeploymentStatusStopped || stopped.PreviousReplicas != 3 {
+		t.Errorf("stop: %+v", stopped)
+	}
+	started, err := StartApp(context.Background(), srv.URL, "tok", "staging")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if started.Replicas != 3 {
+		t.Errorf("start: %+v", started)
+	}
+	want := []string{"POST /api/deploy/deployments/staging/stop", "POST /api/deploy/deployments/staging/start"}
+	if strings.Join(paths, "|") != strings.Join(want, "|") {
+		t.Errorf("paths = %v", paths)
+	}
+}
+
+func TestStartApp_NotStopped(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"status":"error","error":{"code":"NOT_STOPPED","message":"deployment is already running"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := StartApp(context.Background(), srv.URL, "tok", "staging")
+	if err == nil || !strings.Contains(err.Error(), "NOT_STOPPED") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// TrafficTarget is the share of an alias's traffic routed to one revision.
+type TrafficTarget struct {
+	Revision string `json:"revision"`
+	Percent  int    `json:"percent"`
+	// Image and CreatedAt describe the revision; only set in responses.
+	Image     string     `json:"image,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// TrafficResponse is the payload of GET and PUT /deployments/{alias}/traffic.
+type TrafficResponse struct {
+	Alias   string          `json:"alias"`
+	Targets []TrafficTarget `json:"targets"`
+	Message string          `json:"message,omitempty"`
+}
+
+// TrafficRequest is the body of PUT /deployments/{alias}/traffic.
+type TrafficRequest struct {
+	Targets []TrafficTarget `json:"targets"`
+}
+
+// ParseTrafficSplit parses "v42=90,v43=10" into targets. Every revision
+// appears once and the percentages add up to 100.
+func ParseTrafficSplit(spec string) ([]TrafficTarget, error) {
+	var targets []TrafficTarget
+	seen := map[string]bool{}
+	total := 0
+	for _, part := range strings.Split(spec, ",") {
+		rev, pct, ok := strings.Cut(strings.TrimSpace(part), "=")
+		rev = strings.TrimSpace(rev)
+		if !ok || rev == "" {
+			return nil, fmt.Errorf("invalid split entry %q (expected REVISION=PERCENT)", part)
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(pct), "%"))
+		if err != nil || n < 0 || n > 100 {
+			return nil, fmt.Errorf("invalid percentage in %q (expected 0-100)", part)
+		}
+		if seen[rev] {
+			return nil, fmt.Errorf("revision %q listed twice", rev)
+		}
+		seen[rev] = true
+		total += n
+		targets = append(targets, TrafficTarget{Revision: rev, Percent: n})
+	}
+	if total != 100 {
+		return nil, fmt.Errorf("percentages add up to %d, not 100", total)
+	}
+	return targets, nil
+}
+
+// GetTraffic returns how alias's traffic is split between revisions.
+func GetTraffic(ctx context.Context, apiURL, apiToken, alias string) (*TrafficResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, trafficURL(apiURL, alias), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	return doTrafficRequest(req, apiToken)
+}
+
+// SetTraffic routes alias's traffic to the given revisions by weight.
+func SetTraffic(ctx context.Context, apiURL, apiToken, alias string, targets []TrafficTarget) (*TrafficResponse, error) {
+	payload, err := json.Marshal(TrafficRequest{Targets: targets})
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, trafficURL(apiURL, alias), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doTrafficRequest(req, apiToken)
+}
+
+func trafficURL(apiURL, alias string) string {
+	return fmt.Sprintf("%s/api/deploy/deployments/%s/traffic", strings.TrimSuffix(apiURL, "/"), alias)
+}
+
+func doTrafficRequest(req *http.Request, apiToken string) (*TrafficResponse, error) {
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var out TrafficResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,50 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTrafficSplit(t *testing.T) {
+	got, err := ParseTrafficSplit("v42=90, v43=10%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != (TrafficTarget{Revision: "v42", Percent: 90}) || got[1] != (TrafficTarget{Revision: "v43", Percent: 10}) {
+		t.Errorf("got %+v", got)
+	}
+	if got, err := ParseTrafficSplit("v43=100"); err != nil || len(got) != 1 {
+		t.Errorf("single revision: %+v, %v", got, err)
+	}
+
+	for _, bad := range []string{"", "v42", "=50,v43=50", "v42=90,v43=20", "v42=abc", "v42=150,v43=-50", "v42=50,v42=50"} {
+		if _, err := ParseTrafficSplit(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestSetTraffic(t *testing.T) {
+	var got TrafficRequest
+	var method string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method + " " + r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"alias":"shop","targets":[{"revision":"v42","percent":90},{"revision":"v43","percent":10}]}`))
+	}))
+	defer srv.Close()
+
+	out, err := SetTraffic(context.Background(), srv.URL, "tok", "shop", []TrafficTarget{{Revision: "v42", Percent: 90}, {Revision: "v43", Percent: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "PUT /api/deploy/deployments/shop/traffic" || len(got.Targets) != 2 || got.Targets[1].Percent != 10 {
+		t.Errorf("request %s %+v", method, got)
+	}
+	if len(out.Targets) != 2 {
+		t.Errorf("out = %+v", out)
+	}
+}
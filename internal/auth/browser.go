@@ -216,48 +216,91 @@ func CopyToClipboard(text string) error {
 	return cmd.Run()
 }
 
+// APIToken is an API token issued by ExchangeJWTForAPIToken or
+// RefreshAPIToken.
+type APIToken struct {
+	Token string
+	// ExpiresAt is nil for a token that doesn't expire.
+	ExpiresAt *time.Time
+	// RefreshToken is set when the server issues one: RefreshAPIToken
+	// exchanges it for a new token before this one expires.
+	RefreshToken string
+}
+
 // ExchangeJWTForAPIToken uses a short-lived JWT to create a long-lived API token.
 // It calls POST /api/auth/v1/tokens with the JWT as a Bearer token.
-func ExchangeJWTForAPIToken(apiBaseURL, jwt string) (string, error) {
+func ExchangeJWTForAPIToken(apiBaseURL, jwt string) (*APIToken, error) {
 	apiBaseURL = strings.TrimSuffix(apiBaseURL, "/")
 	reqURL := apiBaseURL + "/api/auth/v1/tokens"
 
 	req, err := http.NewRequest("POST", reqURL, strings.NewReader("{}"))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+jwt)
+	return issueAPIToken(req, "token creation")
+}
+
+// RefreshAPIToken exchanges a refresh token for a new API token (and
+// usually a new refresh token). It calls POST /api/auth/v1/tokens/refresh.
+func RefreshAPIToken(apiBaseURL, refreshToken string) (*APIToken, error) {
+	apiBaseURL = strings.TrimSuffix(apiBaseURL, "/")
+	reqURL := apiBaseURL + "/api/auth/v1/tokens/refresh"
+
+	body, err := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", reqURL, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return issueAPIToken(req, "token refresh")
+}
+
+// issueAPIToken sends req and reads the api_token object of the response.
+// what names the request in errors.
+func issueAPIToken(req *http.Request, what string) (*APIToken, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("token creation failed (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		return nil, fmt.Errorf("%s failed (HTTP %d): %s", what, resp.StatusCode, strings.TrimSpace(string(body)))
 	}
 
 	var result struct {
 		APIToken struct {
 			Token string `json:"token"`
+			// Unix seconds; 0 for a token that doesn't expire.
+			ExpiresAt    int64  `json:"expires_at"`
+			RefreshToken string `json:"refresh_token"`
 		} `json:"api_token"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 	if result.APIToken.Token == "" {
-		return "", fmt.Errorf("server returned empty API token")
+		return nil, fmt.Errorf("server returned empty API token")
 	}
 
-	return result.APIToken.Token, nil
+	token := &APIToken{Token: result.APIToken.Token, RefreshToken: result.APIToken.RefreshToken}
+	if result.APIToken.ExpiresAt > 0 {
+		at := time.Unix(result.APIToken.ExpiresAt, 0).UTC()
+		token.ExpiresAt = &at
+	}
+	return token, nil
 }
 
 // DeriveAppURL attempts to derive the app URL from an API URL by replacing "api." with "app.".
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -336,8 +337,32 @@ func TestExchangeJWTForAPIToken(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ExchangeJWTForAPIToken() error: %v", err)
 	}
-	if token != "ak_abc123" {
-		t.Errorf("token = %q, want %q", token, "ak_abc123")
+	if token.Token != "ak_abc123" || token.ExpiresAt != nil || token.RefreshToken != "" {
+		t.Errorf("token = %+v, want ak_abc123 without expiry or refresh token", token)
+	}
+}
+
+func TestRefreshAPIToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/auth/v1/tokens/refresh" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RefreshToken != "rt_old" {
+			t.Errorf("body = %+v, %v", body, err)
+		}
+		fmt.Fprint(w, `{"api_token":{"token":"ak_new","expires_at":1792065600,"refresh_token":"rt_new"}}`)
+	}))
+	defer server.Close()
+
+	token, err := RefreshAPIToken(server.URL+"/", "rt_old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "ak_new" || token.RefreshToken != "rt_new" || token.ExpiresAt == nil || token.ExpiresAt.Unix() != 1792065600 {
+		t.Errorf("token = %+v", token)
 	}
 }
 
@@ -58,7 +58,11 @@ func runReconcile(stdout, stderr io.Writer) int {
 		return 1
 	}
 
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
 	url := strings.TrimSuffix(cfg.APIURL, "/") + "/api/deploy/admin/reconcile"
 
 	req, err := http.NewRequest(http.MethodPost, url, nil)
@@ -73,7 +73,14 @@ func resolveGatewayURL() resolveResult {
 		}
 	}
 
-	apiURL := config.Load().APIURL
+	cfg, err := config.Load()
+	if err != nil {
+		return resolveResult{
+			URL:    "",
+			Source: fmt.Sprintf("could not load the configuration: %v", err),
+		}
+	}
+	apiURL := cfg.APIURL
 	derived, err := deriveFromAPIURL(apiURL)
 	if err != nil {
 		return resolveResult{
@@ -57,7 +57,11 @@ func runEnv(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
 	token := cfg.APIToken
 	if !envNoToken && token == "" {
 		fmt.Fprintf(os.Stderr, "%s no Dibbla API token configured — run `dibbla login` first, or pass --no-token\n",
@@ -63,7 +63,11 @@ func runTest(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("Token:   %s %v\n", bad, err)
+		os.Exit(1)
+	}
 	if cfg.APIToken == "" {
 		fmt.Printf("Token:   %s no Dibbla API token configured — run `dibbla login`\n", bad)
 		os.Exit(1)
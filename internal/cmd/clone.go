@@ -11,7 +11,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
-	"github.com/dibbla-agents/dibbla-cli/internal/config"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 	"github.com/dibbla-agents/dibbla-cli/internal/vcs"
 )
@@ -59,7 +58,7 @@ func runClone(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	cfg := config.Load()
+	cfg := loadConfig()
 	if !cfg.HasToken() {
 		fmt.Printf("%s Error: API token is required. Run 'dibbla login' or set DIBBLA_API_TOKEN.\n", platform.Icon("❌", "[X]"))
 		os.Exit(1)
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage CLI settings and profiles",
+	Long: `Read and change the settings in ~/.dibbla/config.yaml (YAML), as an alternative
+to environment variables:
+
+  dibbla config list
+  dibbla config set api_url https://dibbla.example.com
+  dibbla config set default_deployment myapp
+  dibbla config get default_deployment
+  dibbla config unset default_deployment
+
+Profiles in the same file hold the API URL, token and default region of
+a Dibbla instance, for working against both Dibbla Cloud and a self-hosted
+server, say. Create one by logging in to it:
+
+  dibbla login --profile selfhosted https://dibbla.example.com
+
+or by adding it to the file:
+
+  profiles:
+    selfhosted:
+      api_url: https://dibbla.example.com
+      region: eu-west
+
+Commands use the profile picked with --context (or --profile, except on
+deploy, preview and manifest validate, whose --profile selects manifest
+profiles), else DIBBLA_PROFILE, else the one set with 'dibbla config
+use-context'. DIBBLA_API_URL still takes precedence over the profile's URL;
+with DIBBLA_API_TOKEN set no profile is used. Without a profile the
+credentials from 'dibbla login' are used.`,
+}
+
+// configSetting is a top-level key of ~/.dibbla/config.yaml that 'config get'
+// and 'config set' work on.
+type configSetting struct {
+	key         string
+	description string
+	get         func(uc *config.UserConfig) string
+	// normalize checks a value given to 'config set' and returns the form
+	// to store.
+	normalize func(value string) (string, error)
+}
+
+var configSettings = []configSetting{
+	{
+		key:         "api_url",
+		description: "API used without a login, profile or DIBBLA_API_URL; default of 'dibbla login'",
+		get:         func(uc *config.UserConfig) string { return uc.APIURL },
+		normalize: func(value string) (string, error) {
+			u, err := url.Parse(normalizeAPIURL(value))
+			if err != nil || u.Host == "" {
+				return "", fmt.Errorf("invalid API URL %q", value)
+			}
+			return strings.TrimSuffix(u.String(), "/"), nil
+		},
+	},
+	{
+		key:         "default_deployment",
+		description: "App alias of logs and apps inspect/metrics/open/check when none is given",
+		get:         func(uc *config.UserConfig) string { return uc.DefaultDeployment },
+		normalize: func(value string) (string, error) {
+			if !apps.AliasRe.MatchString(value) {
+				return "", fmt.Errorf("invalid alias %q (lowercase letters, digits and '-')", value)
+			}
+			return value, nil
+		},
+	},
+	{
+		key:         "token_expiry_warning_days",
+		description: fmt.Sprintf("Warn this many days before the API token expires (default %d, 0 = never)", config.DefaultTokenExpiryWarningDays),
+		get: func(uc *config.UserConfig) string {
+			if uc.TokenExpiryWarningDays == nil {
+				return ""
+			}
+			return strconv.Itoa(*uc.TokenExpiryWarningDays)
+		},
+		normalize: func(value string) (string, error) {
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 0 {
+				return "", fmt.Errorf("invalid number of days %q", value)
+			}
+			return strconv.Itoa(days), nil
+		},
+	},
+	{
+		key:         "current_context",
+		description: "Profile used by default (same as 'config use-context')",
+		get:         func(uc *config.UserConfig) string { return uc.CurrentContext },
+		normalize: func(value string) (string, error) {
+			uc, err := config.LoadUserConfig()
+			if err != nil {
+				return "", err
+			}
+			if _, ok := uc.Profiles[value]; !ok {
+				return "", fmt.Errorf("profile %q not found (see 'dibbla config get-contexts')", value)
+			}
+			return value, nil
+		},
+	},
+}
+
+// lookupConfigSetting returns the setting named key.
+func lookupConfigSetting(key string) (*configSetting, error) {
+	keys := make([]string, len(configSettings))
+	for i := range configSettings {
+		if configSettings[i].key == key {
+			return &configSettings[i], nil
+		}
+		keys[i] = configSettings[i].key
+	}
+	return nil, fmt.Errorf("unknown setting %q (valid: %s)", key, strings.Join(keys, ", "))
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting",
+	Long: `Print the value of a setting in ~/.dibbla/config.yaml. Exits with status 1,
+printing nothing, when it isn't set. See 'dibbla config list' for the keys.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Long: `Change a setting in ~/.dibbla/config.yaml, keeping the rest of the file (and
+its comments) as it is. See 'dibbla config list' for the keys.
+
+Examples:
+  dibbla config set api_url https://dibbla.example.com
+  dibbla config set default_deployment myapp`,
+	Args: cobra.ExactArgs(2),
+	Run:  runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigUnset,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the settings and their values",
+	Args:  cobra.NoArgs,
+	Run:   runConfigList,
+}
+
+var configUseContextNone bool
+
+var configUseContextCmd = &cobra.Command{
+	Use:   "use-context [name]",
+	Short: "Set the profile commands use by default",
+	Long: `Set the profile commands use when neither --profile nor DIBBLA_PROFILE picks
+one. --none goes back to the credentials from 'dibbla login'.
+
+Examples:
+  dibbla config use-context selfhosted
+  dibbla config use-context --none`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runConfigUseContext,
+}
+
+var configGetContextsCmd = &cobra.Command{
+	Use:   "get-contexts",
+	Short: "List the profiles",
+	Long:  `List the profiles in ~/.dibbla/config.yaml, marking the one in use with *.`,
+	Args:  cobra.NoArgs,
+	Run:   runConfigGetContexts,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configUseContextCmd)
+	configCmd.AddCommand(configGetContextsCmd)
+	configUseContextCmd.Flags().BoolVar(&configUseContextNone, "none", false, "Stop using a profile by default")
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) {
+	setting, err := lookupConfigSetting(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	uc, err := config.LoadUserConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	value := setting.get(uc)
+	if value == "" {
+		os.Exit(1)
+	}
+	fmt.Println(value)
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) {
+	setting, err := lookupConfigSetting(args[0])
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	value, err := setting.normalize(strings.TrimSpace(args[1]))
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	if err := config.SetUserSetting(setting.key, value); err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s %s set to %s\n", platform.Icon("✅", "[OK]"), setting.key, value)
+	if setting.key == "api_url" {
+		fmt.Println("   'dibbla login' now logs in to it; an existing login keeps its own URL.")
+	}
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) {
+	setting, err := lookupConfigSetting(args[0])
+	if err == nil {
+		err = config.UnsetUserSetting(setting.key)
+	}
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s %s unset\n", platform.Icon("✅", "[OK]"), setting.key)
+}
+
+func runConfigList(cmd *cobra.Command, args []string) {
+	uc, err := config.LoadUserConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	printConfigSettings(os.Stdout, uc)
+}
+
+// printConfigSettings lists the settings in uc, with "-" for unset ones.
+func printConfigSettings(w io.Writer, uc *config.UserConfig) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tDESCRIPTION")
+	for _, s := range configSettings {
+		value := s.get(uc)
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.key, value, s.description)
+	}
+	tw.Flush()
+}
+
+func runConfigUseContext(cmd *cobra.Command, args []string) {
+	if (len(args) == 0) == !configUseContextNone {
+		fmt.Printf("%s Error: give a profile name or --none\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if err := config.UseContext(name); err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	if name == "" {
+		fmt.Printf("%s Not using a profile by default\n", platform.Icon("✅", "[OK]"))
+		return
+	}
+	fmt.Printf("%s Switched to profile %s\n", platform.Icon("✅", "[OK]"), name)
+}
+
+func runConfigGetContexts(cmd *cobra.Command, args []string) {
+	uc, err := config.LoadUserConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	printContexts(os.Stdout, uc, config.ProfileName(uc))
+}
+
+// printContexts lists the profiles in uc, marking active.
+func printContexts(w io.Writer, uc *config.UserConfig, active string) {
+	names := uc.ProfileNames()
+	if len(names) == 0 {
+		fmt.Fprintln(w, "No profiles. Create one with 'dibbla login --profile <name> <api_url>'.")
+		return
+	}
+	fmt.Fprintf(w, "%-8s %-16s %-36s %-12s %s\n", "CURRENT", "NAME", "API URL", "REGION", "TOKEN")
+	for _, name := range names {
+		p := uc.Profiles[name]
+		current := ""
+		if name == active {
+			current = "*"
+		}
+		apiURL := p.APIURL
+		if apiURL == "" {
+			apiURL = config.DefaultAPIURL
+		}
+		region, token := p.Region, "set"
+		if region == "" {
+			region = "-"
+		}
+		if p.Token == "" {
+			token = "-"
+		}
+		fmt.Fprintf(w, "%-8s %-16s %-36s %-12s %s\n", current, name, apiURL, region, token)
+	}
+}
+
+// loadConfig is config.Load, exiting if the configuration can't be used.
+func loadConfig() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	return cfg
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+)
+
+func TestPrintContexts(t *testing.T) {
+	uc := &config.UserConfig{Profiles: map[string]config.Profile{
+		"selfhosted": {APIURL: "https://dibbla.example.com", Token: "ak_x"},
+		"prod":       {Region: "eu-west"},
+	}}
+	var out bytes.Buffer
+	printContexts(&out, uc, "selfhosted")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), out.String())
+	}
+	if f := strings.Fields(lines[1]); strings.Join(f, " ") != "prod https://api.dibbla.com eu-west -" {
+		t.Errorf("prod line = %q", lines[1])
+	}
+	if f := strings.Fields(lines[2]); strings.Join(f, " ") != "* selfhosted https://dibbla.example.com - set" {
+		t.Errorf("selfhosted line = %q", lines[2])
+	}
+	if strings.Contains(out.String(), "ak_x") {
+		t.Error("token printed")
+	}
+
+	out.Reset()
+	printContexts(&out, &config.UserConfig{}, "")
+	if !strings.Contains(out.String(), "No profiles") {
+		t.Errorf("empty list = %q", out.String())
+	}
+}
+
+func TestRootFlag_ContextExists(t *testing.T) {
+	if rootCmd.PersistentFlags().Lookup("context") == nil {
+		t.Error("--context flag missing")
+	}
+	// --profile is an alias (see TestProfileFlagAlias), not a second flag.
+	if rootCmd.PersistentFlags().Lookup("profile") != nil {
+		t.Error("--profile is a flag of its own")
+	}
+}
+
+func TestConfigSettingNormalize(t *testing.T) {
+	cases := []struct {
+		key, value, want string
+		wantErr          bool
+	}{
+		{"api_url", "dibbla.example.com", "https://dibbla.example.com", false},
+		{"api_url", "http://localhost:8080/", "http://localhost:8080", false},
+		{"api_url", "https://", "", true},
+		{"default_deployment", "my-app", "my-app", false},
+		{"default_deployment", "My_App", "", true},
+		{"token_expiry_warning_days", "14", "14", false},
+		{"token_expiry_warning_days", "-1", "", true},
+	}
+	for _, tc := range cases {
+		s, err := lookupConfigSetting(tc.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.normalize(tc.value)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("%s %q: got %q, %v", tc.key, tc.value, got, err)
+		}
+	}
+	if _, err := lookupConfigSetting("region"); err == nil || !strings.Contains(err.Error(), "valid: api_url, default_deployment, token_expiry_warning_days, current_context") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestPrintConfigSettings(t *testing.T) {
+	var out bytes.Buffer
+	printConfigSettings(&out, &config.UserConfig{DefaultDeployment: "myapp"})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines:\n%s", len(lines), out.String())
+	}
+	if f := strings.Fields(lines[1]); f[0] != "api_url" || f[1] != "-" {
+		t.Errorf("api_url line = %q", lines[1])
+	}
+	if f := strings.Fields(lines[2]); f[0] != "default_deployment" || f[1] != "myapp" {
+		t.Errorf("default_deployment line = %q", lines[2])
+	}
+}
+
+func TestPrintConfigSettings_ColumnsLineUp(t *testing.T) {
+	days := 14
+	var out bytes.Buffer
+	printConfigSettings(&out, &config.UserConfig{APIURL: "https://dibbla.example.com", TokenExpiryWarningDays: &days})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	valueCol := strings.Index(lines[0], "VALUE")
+	descCol := strings.Index(lines[0], "DESCRIPTION")
+	for i, s := range configSettings {
+		line := lines[i+1]
+		if !strings.HasPrefix(line, s.key+" ") {
+			t.Fatalf("line %d = %q, want key %s", i+1, line, s.key)
+		}
+		if len(line) <= descCol || line[valueCol-1] != ' ' || line[valueCol] == ' ' || line[descCol-1] != ' ' || line[descCol] == ' ' {
+			t.Errorf("columns of %q don't line up with the header %q", line, lines[0])
+		}
+		if !strings.HasSuffix(line, s.description) {
+			t.Errorf("line %q doesn't end with the description", line)
+		}
+	}
+}
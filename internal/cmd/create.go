@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/dibbla-agents/dibbla-cli/internal/cmd/template"
 	"github.com/dibbla-agents/dibbla-cli/internal/create"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 	"github.com/dibbla-agents/dibbla-cli/internal/preflight"
@@ -15,6 +19,108 @@ import (
 func init() {
 	rootCmd.AddCommand(createCmd)
 	createCmd.AddCommand(goWorkerCmd)
+	createCmd.AddCommand(nodeWorkerCmd)
+	createCmd.AddCommand(fromTemplateCmd)
+	createCmd.AddCommand(upgradeCmd)
+	createCmd.AddCommand(createAddCmd)
+
+	listTemplatesCmd := template.NewListCmd()
+	listTemplatesCmd.Short = "List available templates (same as 'dibbla template list')"
+	createCmd.AddCommand(listTemplatesCmd)
+
+	fromTemplateCmd.Flags().StringVar(&fromTemplateRepo, "template", "", "Git URL of the template repository (required)")
+	fromTemplateCmd.Flags().StringArrayVar(&fromTemplateFeatures, "feature", nil, "Turn a template feature on (name) or off (name=false); repeatable")
+	fromTemplateCmd.MarkFlagRequired("template")
+
+	upgradeCmd.Flags().StringVar(&upgradeRef, "ref", "", "Template branch, tag or commit to upgrade to (default: its default branch)")
+	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "Show what would change without changing anything")
+	upgradeCmd.Flags().BoolVar(&upgradePatch, "patch", false, "Print the template's changes as a diff (implies --dry-run)")
+	upgradeCmd.Flags().BoolVarP(&upgradeYes, "yes", "y", false, "Apply without asking")
+
+	for _, c := range []*cobra.Command{goWorkerCmd, nodeWorkerCmd, fromTemplateCmd} {
+		addCreateFlags(c)
+	}
+	for _, c := range []*cobra.Command{goWorkerCmd, nodeWorkerCmd} {
+		c.Flags().BoolVar(&createOpts.offline, "offline", false, "Use the template cached by an earlier create instead of fetching it")
+	}
+	goWorkerCmd.Flags().BoolVar(&createOpts.withDB, "with-db", false, "Add a database client (DATABASE_URL) and a migrations folder")
+	goWorkerCmd.Flags().BoolVar(&createOpts.withCron, "with-cron", false, "Add a cron scheduler for jobs on CRON_SCHEDULE")
+	goWorkerCmd.Flags().BoolVar(&createOpts.withMetrics, "with-metrics", false, "Add a Prometheus metrics endpoint on METRICS_ADDR")
+}
+
+// goExtras are the extras offered by 'create go-worker'.
+var goExtras = []prompt.Extra{
+	{Name: create.ExtraDB, Description: "Database client and migrations (--with-db)"},
+	{Name: create.ExtraCron, Description: "Cron scheduler (--with-cron)"},
+	{Name: create.ExtraMetrics, Description: "Prometheus metrics (--with-metrics)"},
+}
+
+// addCreateFlags adds the flags every project template takes.
+func addCreateFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.BoolVarP(&createOpts.yes, "yes", "y", false, "Don't prompt: take values from flags and defaults, failing if a required one is missing")
+	f.BoolVar(&createOpts.frontend, "frontend", false, "Include the starter frontend")
+	f.BoolVar(&createOpts.noFrontend, "no-frontend", false, "Leave the starter frontend out")
+	f.StringVar(&createOpts.token, "token", "", "API token to write to .env")
+	f.BoolVar(&createOpts.selfHosted, "self-hosted", false, "Connect to a self-hosted Dibbla server instead of Dibbla Cloud")
+	f.StringVar(&createOpts.grpcAddress, "grpc-address", "", "gRPC address of the self-hosted server (with --self-hosted)")
+	f.BoolVar(&createOpts.tls, "tls", false, "Use TLS for the self-hosted gRPC connection (with --self-hosted)")
+	f.BoolVar(&createOpts.skipInstall, "skip-install", false, "Don't install dependencies (go mod tidy, npm install)")
+	f.BoolVar(&createOpts.forceExisting, "force-existing", false, "Create the project in a non-empty existing directory, after listing the files that would be overwritten")
+	f.BoolVar(&createOpts.noGit, "no-git", false, "Don't initialize a git repository with an initial commit")
+	f.StringVar(&createOpts.ref, "ref", "", "Template branch, tag or commit to use, for a reproducible scaffold (default: its default branch)")
+	f.BoolVar(&createOpts.dryRun, "dry-run", false, "Show the files that would be generated, with go.mod/package.json and .env (secrets masked), without writing anything")
+	f.BoolVar(&createOpts.keepPartial, "keep-partial", false, "If creating fails after files are written, keep them (to finish with --resume) instead of undoing the changes")
+	f.BoolVar(&createOpts.resume, "resume", false, "Finish a project kept by --keep-partial: install dependencies and make the initial commit")
+	f.BoolVar(&createOpts.withCompose, "with-compose", false, "Add a docker-compose.yaml running the project with Postgres for local development")
+}
+
+// createFlags holds the create flags. Each one set skips its prompt; with
+// yes, nothing is prompted for.
+type createFlags struct {
+	yes           bool
+	frontend      bool
+	noFrontend    bool
+	token         string
+	selfHosted    bool
+	grpcAddress   string
+	tls           bool
+	skipInstall   bool
+	offline       bool
+	ref           string
+	noGit         bool
+	forceExisting bool
+	withDB        bool
+	withCron      bool
+	withMetrics   bool
+	withCompose   bool
+	dryRun        bool
+	keepPartial   bool
+	resume        bool
+}
+
+var createOpts createFlags
+
+// validate reports flag combinations that can't work, before anything is
+// asked or created.
+func (f createFlags) validate(args []string) error {
+	switch {
+	case f.frontend && f.noFrontend:
+		return errors.New("--frontend and --no-frontend can't be used together")
+	case !f.selfHosted && (f.grpcAddress != "" || f.tls):
+		return errors.New("--grpc-address and --tls require --self-hosted")
+	case f.yes && len(args) == 0:
+		return errors.New("a project name argument is required with --yes")
+	case f.yes && f.selfHosted && f.grpcAddress == "":
+		return errors.New("--grpc-address is required with --yes --self-hosted")
+	case f.resume && len(args) == 0:
+		return errors.New("--resume needs the name (or .) of the project to finish")
+	case f.resume && f.dryRun:
+		return errors.New("--resume and --dry-run can't be used together")
+	case f.resume && f.ref != "":
+		return errors.New("--resume uses the template version in .dibbla/template.lock; drop --ref")
+	}
+	return nil
 }
 
 var createCmd = &cobra.Command{
@@ -28,14 +134,138 @@ var goWorkerCmd = &cobra.Command{
 	Short: "Create a new Go worker project",
 	Long: `Create a new Dibbla Go worker project from the starter template.
 
+Use --yes with the flags below to create a project without any prompts,
+e.g. in CI.
+
+Pass "." as the name to create the project in the current directory,
+such as a freshly cloned repository. A directory holding anything besides
+.git needs --force-existing; the files that would be overwritten are
+listed and confirmed first.
+
+The template is cloned with git, or downloaded if git isn't installed, and
+cached in ~/.dibbla/templates. --offline (or a failed fetch) uses that
+cached copy instead. --ref pins the template version; the version used is
+recorded in .dibbla/template.lock for 'dibbla create upgrade'.
+
+--with-db, --with-cron and --with-metrics add a database client with a
+migrations folder, a cron scheduler and a Prometheus metrics endpoint,
+with their settings in .env. They are features of the template, recorded
+in the lock file. Without --yes or any of them, you're asked.
+
 Examples:
   dibbla create go-worker my-worker
-  dibbla create go-worker`,
+  dibbla create go-worker
+  dibbla create go-worker my-worker --ref v1.4.0
+  dibbla create go-worker my-worker --dry-run  # preview the files, write nothing
+  dibbla create go-worker my-worker --with-db --with-cron
+  dibbla create go-worker .                  # in the current directory, e.g. a clone
+  dibbla create go-worker my-worker --yes --token "$DIBBLA_API_TOKEN" --no-frontend`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runGoWorker,
 }
 
+var nodeWorkerCmd = &cobra.Command{
+	Use:   "node-worker [name]",
+	Short: "Create a new Node.js worker project",
+	Long: `Create a new Dibbla Node.js worker project from the starter template.
+
+Use --yes with the flags below to create a project without any prompts,
+e.g. in CI.
+
+The template is cloned with git, or downloaded if git isn't installed, and
+cached in ~/.dibbla/templates. --offline (or a failed fetch) uses that
+cached copy instead. --ref pins the template version; the version used is
+recorded in .dibbla/template.lock for 'dibbla create upgrade'.
+
+Examples:
+  dibbla create node-worker my-worker
+  dibbla create node-worker
+  dibbla create node-worker my-worker --yes --frontend --skip-install`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runNodeWorker,
+}
+
+var fromTemplateCmd = &cobra.Command{
+	Use:   "from-template [name]",
+	Short: "Create a new project from your own template repository",
+	Long: `Create a new Dibbla project from any git repository with a
+dibbla-template.yaml manifest at its root, so teams can maintain their own
+starters. The manifest says which module path to replace with the project
+name and in which files, which example env file .env is made from, which
+paths make up the optional frontend and other features, which files are
+rendered with Go's text/template, what to remove, and which commands to
+run once the files are in place (post_gen; listed before they run, skipped
+with --skip-install). Only use templates you trust. The template version
+and features used are recorded in .dibbla/template.lock for
+'dibbla create upgrade'.
+
+  name: acme-worker
+  module: github.com/acme/worker-template
+  files: [go.mod, "*.go"]
+  render: ["*.tmpl"]
+  env: env.example
+  frontend: [frontend]
+  features:
+    - name: metrics
+      description: Prometheus metrics endpoint
+      default: true
+      paths: [internal/metrics]
+  post_gen:
+    - run: go mod tidy
+    - run: npm install
+      dir: frontend
+      when: frontend
+  start: go run ./cmd/worker
+
+Rendered files see {{.Name}}, {{.Module}} and {{.Features.<name>}}, e.g.
+{{if .Features.metrics}}...{{end}}; a .tmpl suffix is dropped. Features
+are asked for, or set with --feature.
+
+Examples:
+  dibbla create from-template my-worker --template https://github.com/acme/worker-template.git
+  dibbla create from-template my-worker --template https://github.com/acme/worker-template.git --ref v2 --yes
+  dibbla create from-template my-worker --template https://github.com/acme/worker-template.git --feature metrics=false`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runFromTemplate,
+}
+
+var (
+	fromTemplateRepo     string
+	fromTemplateFeatures []string
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [dir]",
+	Short: "Bring template changes into a project made with dibbla create",
+	Long: `Compares the template version recorded in the project's
+.dibbla/template.lock with a newer one (--ref, or the template's default
+branch) and lists the files the template changed. Files you haven't
+edited are updated, added or deleted; files changed both in the template
+and in the project are conflicts: they are left alone and the template's
+new version is written next to them as <file>.template-new to merge by
+hand. .env, go.sum, package-lock.json and node_modules are not touched.
+The lock file then records the new version. A lock file without a commit
+(the template was downloaded without git) can't be upgraded.
+
+Commit your work first so the upgrade is easy to review and undo.
+
+Examples:
+  dibbla create upgrade
+  dibbla create upgrade my-worker --ref v1.5.0
+  dibbla create upgrade --patch`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runUpgrade,
+}
+
+var (
+	upgradeRef    string
+	upgradeDryRun bool
+	upgradePatch  bool
+	upgradeYes    bool
+)
+
 func runGoWorker(cmd *cobra.Command, args []string) {
+	checkCreateFlags(args)
 	fmt.Printf("%s Dibbla Go Worker Generator\n", platform.Icon("🚀", ">>"))
 	fmt.Println()
 
@@ -44,77 +274,469 @@ func runGoWorker(cmd *cobra.Command, args []string) {
 	preflight.CheckGo()
 	fmt.Println()
 
+	config := projectSettings(args, true, true)
+	if err := create.GoWorker(config); err != nil {
+		exitCreateError(err)
+	}
+
+	printReady(config, []string{"go mod tidy"}, "go run ./cmd/worker", true)
+	if config.Features[create.ExtraDB] {
+		fmt.Println()
+		fmt.Println("   Database (set DATABASE_URL in .env to run locally):")
+		fmt.Println("   dibbla db create <database>")
+		fmt.Println("   dibbla db migrate up <database>")
+	}
+}
+
+func runNodeWorker(cmd *cobra.Command, args []string) {
+	checkCreateFlags(args)
+	fmt.Printf("%s Dibbla Node.js Worker Generator\n", platform.Icon("🚀", ">>"))
+	fmt.Println()
+
+	// Run pre-flight checks
+	fmt.Println("Checking prerequisites...")
+	preflight.CheckNode()
+	fmt.Println()
+
+	config := projectSettings(args, true, false)
+	if err := create.NodeWorker(config); err != nil {
+		exitCreateError(err)
+	}
+
+	printReady(config, []string{"npm install"}, "npm run dev", true)
+}
+
+func runFromTemplate(cmd *cobra.Command, args []string) {
+	checkCreateFlags(args)
+	fmt.Printf("%s Dibbla Template Generator\n", platform.Icon("🚀", ">>"))
+	fmt.Println()
+
+	var location create.ProjectConfig
+	var manifest *create.TemplateManifest
+	var err error
+	if createOpts.resume {
+		manifest, err = create.CloneLockedTemplate(args[0], fromTemplateRepo)
+	} else {
+		location = askProjectLocation(args)
+		manifest, err = create.CloneTemplate(fromTemplateRepo, createOpts.ref)
+	}
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	fmt.Printf("\nUsing template %s\n\n", manifest.Name)
+
+	// The template's commands are listed and confirmed before they run.
+	runSteps := !createOpts.skipInstall && !createOpts.dryRun
+	if runSteps && len(manifest.PostGen) > 0 {
+		create.PrintPostGenSteps(manifest)
+		runSteps = createOpts.yes || prompt.AskConfirm("Run them?")
+	}
+
+	features, err := featureFlags(manifest, fromTemplateFeatures)
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	for _, f := range manifest.Features {
+		if _, set := features[f.Name]; !set && f.Name != create.FrontendFeature && !createOpts.yes && !createOpts.resume {
+			features[f.Name] = prompt.AskFeature(featureDescription(f), f.Default)
+		}
+	}
+
+	config := resumeConfig(args)
+	if !createOpts.resume {
+		config = askProjectSettings(location, manifest.HasFrontend(), false)
+	}
+	config.SkipInstall = !runSteps
+	config.Features = features
+	if err := create.FromTemplate(config, manifest); err != nil {
+		exitCreateError(err)
+	}
+
+	var steps []string
+	for _, step := range manifest.StepsFor(manifest.FeatureValues(config.IncludeFrontend, features)) {
+		steps = append(steps, step.String())
+	}
+	printReady(config, steps, manifest.Start, false)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	fmt.Println("Comparing template versions...")
+	u, err := create.PlanUpgrade(dir, upgradeRef)
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	defer u.Close()
+	fmt.Println()
+
+	if upgradePatch {
+		if err := u.WritePatch(os.Stdout); err != nil {
+			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			u.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("Template %s: %s -> %s\n", u.From.Repo, u.From.Version(), u.To.Version())
+	if len(u.Changes) == 0 {
+		fmt.Printf("%s Already up to date\n", platform.Icon("✅", "[OK]"))
+		return
+	}
+	printTemplateChanges(u.Changes)
+	if upgradeDryRun {
+		return
+	}
+	if !upgradeYes && !prompt.AskConfirm("Apply these changes?") {
+		fmt.Println("Cancelled.")
+		return
+	}
+
+	if err := u.Apply(); err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		u.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("%s Upgraded to %s\n", platform.Icon("✅", "[OK]"), u.To.Version())
+	printConflictHint(u)
+	fmt.Println("  Reinstall dependencies (go mod tidy / npm install) if the template changed them.")
+}
+
+// printTemplateChanges lists the files an Upgrade changes.
+func printTemplateChanges(changes []create.UpgradeChange) {
+	for _, c := range changes {
+		switch c.Action {
+		case create.UpgradeAdd:
+			fmt.Printf("  + %s\n", c.Path)
+		case create.UpgradeUpdate:
+			fmt.Printf("  ~ %s\n", c.Path)
+		case create.UpgradeDelete:
+			fmt.Printf("  - %s\n", c.Path)
+		case create.UpgradeConflict:
+			fmt.Printf("  ! %s (changed in the project too)\n", c.Path)
+		}
+	}
+	fmt.Println()
+}
+
+// printConflictHint says how to resolve the conflicts an applied Upgrade
+// left, if any.
+func printConflictHint(u *create.Upgrade) {
+	if n := u.Conflicts(); n > 0 {
+		fmt.Printf("  %s %d conflict(s): merge each <file>%s into <file> by hand, then delete it.\n",
+			platform.Icon("⚠️", "[!]"), n, create.ConflictSuffix)
+	}
+}
+
+// featureFlags parses --feature values (name, name=true or name=false)
+// against the features m declares. The frontend has its own flags.
+func featureFlags(m *create.TemplateManifest, values []string) (map[string]bool, error) {
+	features := map[string]bool{}
+	for _, v := range values {
+		name, value, hasValue := strings.Cut(v, "=")
+		on := true
+		if hasValue {
+			var err error
+			if on, err = strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("--feature %s: value must be true or false", v)
+			}
+		}
+		if name == create.FrontendFeature {
+			return nil, errors.New("use --frontend or --no-frontend for the frontend")
+		}
+		if m.Feature(name) == nil {
+			var names []string
+			for _, f := range m.Features {
+				if f.Name != create.FrontendFeature {
+					names = append(names, f.Name)
+				}
+			}
+			if len(names) == 0 {
+				return nil, fmt.Errorf("--feature %s: this template has no features", name)
+			}
+			return nil, fmt.Errorf("--feature %s: unknown feature (this template has: %s)", name, strings.Join(names, ", "))
+		}
+		features[name] = on
+	}
+	return features, nil
+}
+
+// featureDescription is how a feature is asked about.
+func featureDescription(f create.TemplateFeature) string {
+	if f.Description != "" {
+		return f.Description + " (" + f.Name + ")"
+	}
+	return f.Name
+}
+
+// checkCreateFlags exits if the create flags can't work together.
+func checkCreateFlags(args []string) {
+	if err := createOpts.validate(args); err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+}
+
+// exitCreateError reports a failed create and exits.
+func exitCreateError(err error) {
+	if errors.Is(err, create.ErrOverwriteDeclined) {
+		fmt.Println("Cancelled. No files were changed.")
+		os.Exit(0)
+	}
+	fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+	var partial *create.PartialError
+	if errors.As(err, &partial) {
+		switch {
+		case partial.Resumable:
+			fmt.Printf("   Kept %s. Fix the problem, then run the same command with --resume to finish.\n", partial.Dir)
+		case partial.Kept:
+			fmt.Printf("   Kept %s as it was left.\n", partial.Dir)
+		default:
+			fmt.Printf("   Undid the changes to %s. Pass --keep-partial to keep them and finish later with --resume.\n", partial.Dir)
+		}
+	}
+	os.Exit(1)
+}
+
+// projectSettings asks for the project's location and settings, or with
+// --resume takes them from the project being finished.
+func projectSettings(args []string, hasFrontend, extras bool) create.ProjectConfig {
+	if createOpts.resume {
+		return resumeConfig(args)
+	}
+	return askProjectSettings(askProjectLocation(args), hasFrontend, extras)
+}
+
+// resumeConfig is the config for --resume: the existing project named by
+// args, with the flags for the steps left and the settings recorded in its
+// lock (if readable; creating reports it otherwise). Without --resume it
+// is empty.
+func resumeConfig(args []string) create.ProjectConfig {
+	if !createOpts.resume {
+		return create.ProjectConfig{}
+	}
+	config := create.ProjectConfig{
+		Name:        args[0],
+		Dir:         args[0],
+		SkipInstall: createOpts.skipInstall,
+		NoGit:       createOpts.noGit,
+		Resume:      true,
+	}
+	if lock, err := create.ReadLock(config.Dir); err == nil {
+		config.Name = lock.Name
+		config.IncludeFrontend = lock.Frontend
+	}
+	return config
+}
+
+// checkProjectName is create.CheckProjectName, also accepting "." for the
+// current directory.
+func checkProjectName(name string) (string, error) {
+	if name == "." {
+		return "", nil
+	}
+	return create.CheckProjectName(name)
+}
+
+// askProjectLocation gets the project name (from arg or prompt) and
+// confirms where the project goes: a new directory of that name, or the
+// current directory for ".". An existing directory must be empty (apart
+// from .git) unless --force-existing. Exits if it can't be used or the
+// user cancels.
+func askProjectLocation(args []string) create.ProjectConfig {
 	// Get project name (from arg or prompt)
 	var projectName string
 	if len(args) > 0 {
 		projectName = args[0]
+		if suggestion, err := checkProjectName(projectName); err != nil {
+			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			switch {
+			case suggestion != "" && !createOpts.yes && prompt.AskConfirm(fmt.Sprintf("Use %q instead?", suggestion)):
+				projectName = suggestion
+			case suggestion != "":
+				fmt.Printf("   Try %q.\n", suggestion)
+				os.Exit(1)
+			default:
+				os.Exit(1)
+			}
+		}
 	} else {
-		projectName = prompt.AskProjectName()
+		projectName = prompt.AskProjectName(checkProjectName)
 	}
 
-	// Check if directory exists
-	if preflight.DirectoryExists(projectName) {
-		fmt.Printf("%s Error: Directory '%s' already exists\n", platform.Icon("❌", "[X]"), projectName)
-		os.Exit(1)
+	location := create.ProjectConfig{Name: projectName}
+	if projectName == "." {
+		wd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+		location = create.ProjectConfig{Name: filepath.Base(wd), Dir: "."}
+		// The directory is there already; only warn that deploying it
+		// needs an alias.
+		if suggestion, err := create.CheckProjectName(location.Name); err != nil {
+			fmt.Printf("%s Warning: %v\n", platform.Icon("⚠️", "[!]"), err)
+			if suggestion != "" {
+				fmt.Printf("   Deploy it with 'dibbla deploy --alias %s'.\n", suggestion)
+			}
+		}
 	}
+	dir := location.ProjectDir()
+	fullPath, _ := filepath.Abs(dir)
 
-	// Show full path and confirm
-	fullPath, _ := filepath.Abs(projectName)
-	fmt.Printf("\n%s Project will be created at:\n   %s\n\n", platform.Icon("📁", "[DIR]"), fullPath)
+	// Check if directory exists
+	if preflight.DirectoryExists(dir) {
+		empty, err := create.EmptyDir(dir)
+		if err != nil {
+			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+		if !empty && !createOpts.forceExisting {
+			fmt.Printf("%s Error: Directory '%s' is not empty; pass --force-existing to create the project in it\n", platform.Icon("❌", "[X]"), dir)
+			os.Exit(1)
+		}
+		fmt.Printf("\n%s Project '%s' will be created in the existing directory:\n   %s\n\n", platform.Icon("📁", "[DIR]"), location.Name, fullPath)
+	} else {
+		fmt.Printf("\n%s Project will be created at:\n   %s\n\n", platform.Icon("📁", "[DIR]"), fullPath)
+	}
 
-	if !prompt.AskConfirm("Continue?") {
+	if !createOpts.yes && !createOpts.dryRun && !prompt.AskConfirm("Continue?") {
 		fmt.Println("Cancelled.")
 		os.Exit(0)
 	}
+	return location
+}
+
+// confirmOverwrite lists the existing files the template would overwrite
+// and asks before doing so, unless --yes.
+func confirmOverwrite(paths []string) bool {
+	fmt.Printf("\n  %s These existing files will be overwritten:\n", platform.Icon("⚠️", "[!]"))
+	for _, p := range paths {
+		fmt.Printf("     %s\n", p)
+	}
+	return createOpts.yes || prompt.AskConfirm("Overwrite them?")
+}
+
+// askProjectSettings fills in location, from askProjectLocation, with the
+// settings shared by every project template: hosting, API token and, if
+// the template has one, frontend, and with extras the Go worker extras,
+// prompting only for what the flags leave open.
+func askProjectSettings(location create.ProjectConfig, hasFrontend, extras bool) create.ProjectConfig {
+	opts := createOpts
 
 	// Get hosting type
-	hostingType := prompt.AskHostingType()
-	isSelfHosted := hostingType == prompt.HostingSelfHosted
+	isSelfHosted := opts.selfHosted
+	if !isSelfHosted && !opts.yes {
+		isSelfHosted = prompt.AskHostingType() == prompt.HostingSelfHosted
+	}
 
 	// Self-hosted configuration
-	var grpcAddress string
-	var useTLS bool
-	if isSelfHosted {
-		grpcAddress = prompt.AskGrpcAddress()
-		useTLS = prompt.AskUseTLS()
+	grpcAddress := opts.grpcAddress
+	useTLS := opts.tls
+	if isSelfHosted && !opts.yes {
+		if grpcAddress == "" {
+			grpcAddress = prompt.AskGrpcAddress()
+		}
+		if !useTLS {
+			useTLS = prompt.AskUseTLS()
+		}
 	}
 
 	// Get API token (with context-aware message)
-	apiToken := prompt.AskAPIToken(isSelfHosted)
+	apiToken := opts.token
+	switch {
+	case apiToken == "" && !opts.yes:
+		apiToken = prompt.AskAPIToken(isSelfHosted)
+	case apiToken == "":
+		fmt.Printf("  %s Warning: No token provided. Add SERVER_API_TOKEN to .env before running.\n", platform.Icon("⚠️", "[!]"))
+	case !preflight.ValidateToken(apiToken):
+		fmt.Printf("  %s Warning: Token should start with 'ak_'. Using as-is.\n", platform.Icon("⚠️", "[!]"))
+	}
 
 	// Get frontend preference
-	includeFrontend := prompt.AskIncludeFrontend()
+	includeFrontend := hasFrontend && opts.frontend
+	if hasFrontend && !opts.frontend && !opts.noFrontend && !opts.yes {
+		includeFrontend = prompt.AskIncludeFrontend()
+	}
+
+	// Get extras, the Go worker starter's features
+	var features map[string]bool
+	if extras {
+		features = map[string]bool{create.ExtraDB: opts.withDB, create.ExtraCron: opts.withCron, create.ExtraMetrics: opts.withMetrics}
+		if !opts.withDB && !opts.withCron && !opts.withMetrics && !opts.yes {
+			for _, name := range prompt.AskExtras(goExtras) {
+				features[name] = true
+			}
+		}
+	}
 
 	fmt.Println()
 	fmt.Println("Creating project...")
 
-	// Create the project
-	config := create.ProjectConfig{
-		Name:            projectName,
-		Token:           apiToken,
-		IncludeFrontend: includeFrontend,
-		SelfHosted:      isSelfHosted,
-		GrpcAddress:     grpcAddress,
-		UseTLS:          useTLS,
-	}
-
-	if err := create.GoWorker(config); err != nil {
-		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
-		os.Exit(1)
+	return create.ProjectConfig{
+		Name:             location.Name,
+		Dir:              location.Dir,
+		Token:            apiToken,
+		IncludeFrontend:  includeFrontend,
+		SelfHosted:       isSelfHosted,
+		GrpcAddress:      grpcAddress,
+		UseTLS:           useTLS,
+		SkipInstall:      opts.skipInstall,
+		Offline:          opts.offline,
+		Ref:              opts.ref,
+		NoGit:            opts.noGit,
+		Features:         features,
+		WithCompose:      opts.withCompose,
+		DryRun:           opts.dryRun,
+		KeepPartial:      opts.keepPartial,
+		ConfirmOverwrite: confirmOverwrite,
 	}
+}
 
-	// Success message
+// printReady prints the success message with runCmd, the command that
+// starts the project (if known), preceded by installCmds if dependencies
+// were not installed. frontendHint adds how to start the starter frontend.
+// After a dry run it only says how to create the project.
+func printReady(config create.ProjectConfig, installCmds []string, runCmd string, frontendHint bool) {
 	fmt.Println()
+	if config.DryRun {
+		fmt.Println("Run the same command without --dry-run to create the project.")
+		return
+	}
 	fmt.Printf("%s Ready! Run your worker:\n", platform.Icon("🎉", "[*]"))
-	fmt.Printf("   cd %s\n", projectName)
-	if apiToken == "" {
+	if dir := config.ProjectDir(); dir != "." {
+		fmt.Printf("   cd %s\n", dir)
+	}
+	if config.Token == "" && !config.Resume {
 		fmt.Println("   # Don't forget to add your API token to .env first!")
 	}
-	fmt.Println("   go run ./cmd/worker")
+	if config.SkipInstall {
+		for _, c := range installCmds {
+			fmt.Printf("   %s\n", c)
+		}
+	}
+	if runCmd != "" {
+		fmt.Printf("   %s\n", runCmd)
+	}
+	if config.WithCompose {
+		fmt.Println("   # or, with a local Postgres, in Docker:")
+		fmt.Println("   docker compose up")
+	}
 
-	if includeFrontend {
+	if config.IncludeFrontend && frontendHint {
 		fmt.Println()
 		fmt.Println("   Frontend (in a separate terminal):")
-		fmt.Printf("   cd %s/frontend && npm run dev\n", projectName)
+		if config.SkipInstall {
+			fmt.Printf("   cd %s && npm install && npm run dev\n", filepath.Join(config.ProjectDir(), "frontend"))
+		} else {
+			fmt.Printf("   cd %s && npm run dev\n", filepath.Join(config.ProjectDir(), "frontend"))
+		}
 	}
 }
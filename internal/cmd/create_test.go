@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/create"
+)
+
+func TestCreateFlagsValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		flags   createFlags
+		args    []string
+		wantErr string
+	}{
+		{"interactive", createFlags{}, nil, ""},
+		{"yes with everything", createFlags{yes: true, token: "ak_1", noFrontend: true}, []string{"w"}, ""},
+		{"yes self-hosted", createFlags{yes: true, selfHosted: true, grpcAddress: "grpc:9090", tls: true}, []string{"w"}, ""},
+		{"both frontend flags", createFlags{frontend: true, noFrontend: true}, nil, "can't be used together"},
+		{"grpc address without self-hosted", createFlags{grpcAddress: "grpc:9090"}, nil, "require --self-hosted"},
+		{"tls without self-hosted", createFlags{tls: true}, nil, "require --self-hosted"},
+		{"yes without a name", createFlags{yes: true}, nil, "project name argument is required"},
+		{"yes self-hosted without an address", createFlags{yes: true, selfHosted: true}, []string{"w"}, "--grpc-address is required"},
+		{"resume with a ref", createFlags{resume: true, ref: "v2"}, []string{"w"}, "drop --ref"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.flags.validate(tc.args)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeatureFlags(t *testing.T) {
+	m, err := create.ParseTemplateManifest([]byte("frontend: [web]\nfeatures:\n  - name: metrics\n  - name: tracing\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name    string
+		values  []string
+		want    map[string]bool
+		wantErr string
+	}{
+		{"none", nil, map[string]bool{}, ""},
+		{"on and off", []string{"metrics", "tracing=false"}, map[string]bool{"metrics": true, "tracing": false}, ""},
+		{"bad value", []string{"metrics=maybe"}, nil, "true or false"},
+		{"unknown", []string{"queue"}, nil, "this template has: metrics, tracing"},
+		{"frontend", []string{"frontend"}, nil, "--no-frontend"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := featureFlags(m, tc.values)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("featureFlags = %v, %v; want %v", got, err, tc.want)
+			}
+		})
+	}
+}
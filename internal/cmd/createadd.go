@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/create"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var createAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a component to a project made with dibbla create",
+	Long: `Add a component to an existing project, so what was left out when
+it was created isn't a permanent decision. Run it in the project
+directory, or pass --dir.
+
+Examples:
+  dibbla create add frontend
+  dibbla create add db
+  dibbla create add http-handler orders --dir my-worker`,
+}
+
+var createAddFrontendCmd = &cobra.Command{
+	Use:   "frontend",
+	Short: "Add the template's starter frontend",
+	Long: `Add the starter frontend to a project created without it, from the
+template version recorded in .dibbla/template.lock. The files the
+frontend adds or changes are listed and confirmed first. Files you have
+edited since (such as main.go) are left alone; the frontend version is
+written next to them as <file>.template-new to merge by hand.`,
+	Args: cobra.NoArgs,
+	Run:  runCreateAddFrontend,
+}
+
+var createAddDBCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Add a database client and migrations folder to a Go worker",
+	Long: `Turn on the Go worker's db feature, as 'create go-worker --with-db'
+does: internal/database, a database/sql client on DATABASE_URL, and a
+migrations folder. Like 'add frontend', the template is rendered at the
+version in .dibbla/template.lock and the changes are listed and confirmed
+first. The feature is recorded in the lock file, DATABASE_URL is added to
+.env, and go mod tidy fetches the database driver.`,
+	Args: cobra.NoArgs,
+	Run:  runCreateAddDB,
+}
+
+var createAddHTTPHandlerCmd = &cobra.Command{
+	Use:   "http-handler <name>",
+	Short: "Add an HTTP handler package to a Go worker",
+	Long: `Add internal/http_handlers/<name>, a package http<name> whose
+Register(router) serves /api/<name>. It is registered in the worker's
+main.go after the handlers already registered there (such as
+httpgreeting); if main.go registers none, the lines to add are printed.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runCreateAddHTTPHandler,
+}
+
+var (
+	createAddDir string
+	createAddYes bool
+)
+
+func init() {
+	createAddCmd.AddCommand(createAddFrontendCmd)
+	createAddCmd.AddCommand(createAddDBCmd)
+	createAddCmd.AddCommand(createAddHTTPHandlerCmd)
+	createAddCmd.PersistentFlags().StringVar(&createAddDir, "dir", ".", "Project directory")
+	createAddFrontendCmd.Flags().BoolVarP(&createAddYes, "yes", "y", false, "Add without asking")
+	createAddDBCmd.Flags().BoolVarP(&createAddYes, "yes", "y", false, "Add without asking")
+}
+
+func runCreateAddFrontend(cmd *cobra.Command, args []string) {
+	fmt.Println("Rendering the template with the frontend...")
+	u, err := create.AddFrontend(createAddDir)
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	defer u.Close()
+	fmt.Println()
+
+	printTemplateChanges(u.Changes)
+	if !createAddYes && !prompt.AskConfirm("Apply these changes?") {
+		fmt.Println("Cancelled.")
+		return
+	}
+	if err := u.Apply(); err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		u.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("%s Frontend added\n", platform.Icon("✅", "[OK]"))
+	printConflictHint(u)
+	fmt.Printf("  Install its dependencies: cd %s && npm install\n", path.Join(createAddDir, "frontend"))
+}
+
+func runCreateAddDB(cmd *cobra.Command, args []string) {
+	fmt.Println("Rendering the template with the database client...")
+	u, err := create.AddFeature(createAddDir, create.ExtraDB)
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	defer u.Close()
+	fmt.Println()
+
+	printTemplateChanges(u.Changes)
+	if !createAddYes && !prompt.AskConfirm("Apply these changes?") {
+		fmt.Println("Cancelled.")
+		return
+	}
+	if err := u.Apply(); err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		u.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("%s Database client added in internal/database\n", platform.Icon("✅", "[OK]"))
+	printConflictHint(u)
+
+	fmt.Println("  Running go mod tidy...")
+	if err := create.RunGoModTidy(createAddDir, os.Stdout); err != nil {
+		fmt.Printf("  %s Warning: go mod tidy failed (%v); run it yourself.\n", platform.Icon("⚠️", "[!]"), err)
+	}
+	fmt.Println("  Create and migrate a database:")
+	fmt.Println("   dibbla db create <database>")
+	fmt.Println("   dibbla db migrate up <database>")
+}
+
+func runCreateAddHTTPHandler(cmd *cobra.Command, args []string) {
+	name := args[0]
+	h, err := create.AddHTTPHandler(createAddDir, name)
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s Handler added in %s/%s\n", platform.Icon("✅", "[OK]"), create.HandlerDir, name)
+	if h.RegisteredIn != "" {
+		fmt.Printf("  Registered with the router in %s\n", h.RegisteredIn)
+		return
+	}
+	fmt.Println("  Register it with the worker's router in main.go:")
+	fmt.Printf("   import \"%s\"\n", h.ImportPath)
+	fmt.Printf("   http%s.Register(router)\n", name)
+}
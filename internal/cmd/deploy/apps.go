@@ -1,14 +1,17 @@
 package deploy
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/dibbla-agents/dibbla-cli/internal/apps"
-	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/cmd/logs"
+	"github.com/dibbla-agents/dibbla-cli/internal/env"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 	"github.com/dibbla-agents/dibbla-cli/internal/spinner"
 	"github.com/spf13/cobra"
@@ -38,7 +41,17 @@ var appsDeleteCmd = &cobra.Command{
 var appsUpdateCmd = &cobra.Command{
 	Use:   "update <alias>",
 	Short: "Update a deployment",
-	Long:  `Updates an existing deployment (env vars, replicas, cpu, memory, port).`,
+	Long:  `Updates an existing deployment (env vars, replicas, autoscaling, cpu, memory, port, region).
+
+-e and --env-file add or overwrite variables; --remove-env KEY deletes one.
+
+--min-replicas, --max-replicas and --target-cpu turn on horizontal
+autoscaling (or adjust it): replicas are added or removed to keep average
+CPU near the target percentage of the CPU request. Flags left out keep
+their current value. Not combinable with --replicas.
+
+--region moves the deployment to another region (see dibbla regions list);
+the platform redeploys it there and switches traffic once it is healthy.`,
 	Args:  cobra.ExactArgs(1),
 	Run:   runAppsUpdate,
 }
@@ -65,8 +78,20 @@ Examples:
 }
 
 var (
+	listPreviews          bool
+	listStatus            string
+	listNameContains      string
+	listLabels            []string
+	listLimit             int
+	listPage              int
+	listWatch             bool
+	listInterval          time.Duration
+	listColumns           string
+	listSort              string
 	deleteYes             bool
 	updateEnv             []string
+	updateEnvFiles        []string
+	updateRemoveEnv       []string
 	updateReplicas        int
 	updateCPU             string
 	updateMemory          string
@@ -76,9 +101,14 @@ var (
 	updateAccessPolicy    string
 	updateGoogleScopes    []string
 	updateMicrosoftScopes []string
+	updateRegion          string
+	updateMinReplicas     int
+	updateMaxReplicas     int
+	updateTargetCPU       int
 	restartService        string
 	restartQuiet          bool
 	restartJSON           bool
+	appsOutput            string
 )
 
 func init() {
@@ -86,6 +116,20 @@ func init() {
 	appsCmd.AddCommand(appsDeleteCmd)
 	appsCmd.AddCommand(appsUpdateCmd)
 	appsCmd.AddCommand(appsRestartCmd)
+	appsCmd.AddCommand(appsCleanupPreviewsCmd)
+	appsCmd.AddCommand(logs.NewAppsLogsCmd())
+	appsCmd.PersistentFlags().StringVarP(&appsOutput, "output", "o", "table", "Output format: table/json/yaml")
+	appsListCmd.Flags().BoolVar(&listPreviews, "previews", false, "List branch preview deployments (from 'deploy --preview') instead of apps")
+	appsListCmd.Flags().StringVar(&listStatus, "status", "", "Only deployments in this status: running, failed, unhealthy, building, starting, deleting, stopped")
+	appsListCmd.Flags().StringVar(&listNameContains, "name-contains", "", "Only aliases containing this text (case-insensitive)")
+	appsListCmd.Flags().StringVar(&listColumns, "columns", defaultAppsColumns, "Comma-separated columns: alias, url, region, status, replicas, cpu, memory, deployed_at")
+	appsListCmd.Flags().StringVar(&listSort, "sort", "", "Sort by alias, status or deployed_at (newest first); default is API order")
+	appsListCmd.Flags().BoolVarP(&listWatch, "watch", "w", false, "Refresh the table until Ctrl-C, highlighting status changes")
+	appsListCmd.Flags().DurationVar(&listInterval, "interval", 5*time.Second, "Refresh interval for --watch")
+	appsListCmd.MarkFlagsMutuallyExclusive("watch", "previews")
+	appsListCmd.Flags().IntVar(&listLimit, "limit", 0, "Show at most this many deployments (0 = all); the page size with --page")
+	appsListCmd.Flags().IntVar(&listPage, "page", 0, "Show only this page (1-based) of --limit deployments (default page size 100)")
+	appsListCmd.Flags().StringArrayVar(&listLabels, "label", nil, "Only deployments with this label, key=value or key (repeatable, all must match)")
 	appsDeleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Skip confirmation prompt")
 	appsRestartCmd.Flags().StringVarP(&restartService, "service", "s", "",
 		"Service to restart (required); regex ^[a-z][a-z0-9-]{0,29}$")
@@ -96,7 +140,12 @@ func init() {
 	_ = appsRestartCmd.MarkFlagRequired("service")
 	appsRestartCmd.MarkFlagsMutuallyExclusive("quiet", "json")
 	appsUpdateCmd.Flags().StringArrayVarP(&updateEnv, "env", "e", nil, "Set env var KEY=value (repeatable)")
+	appsUpdateCmd.Flags().StringArrayVar(&updateEnvFiles, "env-file", nil, "Read env vars from a dotenv file (repeatable; -e overrides)")
+	appsUpdateCmd.Flags().StringArrayVar(&updateRemoveEnv, "remove-env", nil, "Remove env var KEY from the deployment (repeatable)")
 	appsUpdateCmd.Flags().IntVar(&updateReplicas, "replicas", -1, "Desired number of replicas")
+	appsUpdateCmd.Flags().IntVar(&updateMinReplicas, "min-replicas", 0, "Autoscaling: fewest replicas to run")
+	appsUpdateCmd.Flags().IntVar(&updateMaxReplicas, "max-replicas", 0, "Autoscaling: most replicas to run")
+	appsUpdateCmd.Flags().IntVar(&updateTargetCPU, "target-cpu", 0, "Autoscaling: target average CPU utilization in percent (e.g. 70)")
 	appsUpdateCmd.Flags().StringVar(&updateCPU, "cpu", "", "CPU request/limit (e.g. 500m, 1)")
 	appsUpdateCmd.Flags().StringVar(&updateMemory, "memory", "", "Memory request/limit (e.g. 256Mi, 512Mi)")
 	appsUpdateCmd.Flags().IntVar(&updatePort, "port", -1, "Container port (1-65535)")
@@ -104,48 +153,196 @@ func init() {
 	appsUpdateCmd.Flags().StringVar(&updateRequireLogin, "require-login", "", "Require login: true or false")
 	appsUpdateCmd.Flags().StringVar(&updateAccessPolicy, "access-policy", "", "Access policy: all_members, invite_only, or \"\" to clear")
 	appsUpdateCmd.Flags().StringArrayVar(&updateGoogleScopes, "google-scopes", nil, "Google OAuth scope URL (repeatable, use \"\" to clear)")
+	appsUpdateCmd.Flags().StringVar(&updateRegion, "region", "", "Move the deployment to this region, e.g. eu-west (see 'dibbla regions list')")
 	appsUpdateCmd.Flags().StringArrayVar(&updateMicrosoftScopes, "microsoft-scopes", nil, "Microsoft Graph scope, e.g. Files.ReadWrite (repeatable, use \"\" to clear)")
 }
 
 func runAppsList(cmd *cobra.Command, args []string) {
-	fmt.Printf("%s Retrieving Dibbla applications...\n", platform.Icon("🌱", "[>]"))
-	fmt.Println()
+	status, err := apps.ParseStatusFilter(listStatus)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	labels, err := apps.ParseLabelFilters(listLabels)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	if listLimit < 0 || listPage < 0 {
+		fmt.Fprintf(os.Stderr, "%s --limit and --page must not be negative\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+	filter := apps.ListOptions{Status: status, NameContains: listNameContains, Labels: labels, Limit: listLimit, Page: listPage}
 
-	cfg := config.Load()
+	table, err := newAppsTable(listColumns, listSort, terminalWidth())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	if listWatch && appsOutput != "table" {
+		fmt.Fprintf(os.Stderr, "%s --watch only works with table output\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+	if listWatch && listInterval < time.Second {
+		fmt.Fprintf(os.Stderr, "%s --interval must be at least 1s\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+
+	cfg := loadConfig()
 	requireToken(cfg)
+	if listWatch {
+		os.Exit(runAppsListWatch(cmd.Context(), cfg.APIURL, cfg.APIToken, filter, table, listInterval))
+	}
+	os.Exit(runAppsListCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, filter, table, listPreviews, appsOutput))
+}
+
+// runAppsListCore is the testable inner implementation of `apps list`.
+// With -o json or yaml it prints the raw list response (previews
+// included) and nothing else. Returns the exit code.
+func runAppsListCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken string, filter apps.ListOptions, table appsTable, previews bool, format string) int {
+	if err := validateAppsOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if format == "table" {
+		fmt.Fprintf(stdout, "%s Retrieving Dibbla applications...\n", platform.Icon("🌱", "[>]"))
+		fmt.Fprintln(stdout)
+	}
 
-	deployments, err := apps.ListApps(cfg.APIURL, cfg.APIToken)
+	deployments, err := apps.ListApps(ctx, apiURL, apiToken, filter)
 	if err != nil {
-		fmt.Printf("%s Failed to list applications: %v\n", platform.Icon("❌", "[X]"), err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "%s Failed to list applications: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if format != "table" {
+		return writeAppsOutput(stdout, stderr, format, deployments)
+	}
+
+	var listed []apps.Deployment
+	hidden := 0
+	for _, dep := range deployments.Deployments {
+		if dep.Preview == previews {
+			listed = append(listed, dep)
+		} else if dep.Preview {
+			hidden++
+		}
+	}
+
+	if previews {
+		printPreviewList(stdout, listed)
+		return 0
+	}
+
+	if len(listed) == 0 {
+		if filter.Match(apps.Deployment{}) {
+			fmt.Fprintln(stdout, "No applications deployed yet.")
+		} else {
+			fmt.Fprintln(stdout, "No applications match the filters.")
+		}
+		printHiddenPreviews(stdout, hidden)
+		return 0
 	}
 
-	if len(deployments.Deployments) == 0 {
-		fmt.Println("No applications deployed yet.")
+	fmt.Fprintf(stdout, "Found %d applications:\n", len(listed))
+	fmt.Fprintln(stdout)
+	table.print(stdout, listed, nil, false)
+	printHiddenPreviews(stdout, hidden)
+	printTruncated(stdout, filter, deployments)
+	return 0
+}
+
+func printPreviewList(w io.Writer, previews []apps.Deployment) {
+	if len(previews) == 0 {
+		fmt.Fprintln(w, "No preview deployments.")
 		return
 	}
 
-	fmt.Printf("Found %d applications:\n", deployments.Total)
-	fmt.Println()
+	fmt.Fprintf(w, "Found %d preview deployments:\n", len(previews))
+	fmt.Fprintln(w)
 
-	fmt.Printf("%-20s %-40s %-15s %s\n", "ALIAS", "URL", "STATUS", "LAST DEPLOYED")
-	fmt.Printf("%-20s %-40s %-15s %s\n", "-----", "---", "------", "-------------")
+	fmt.Fprintf(w, "%-30s %-20s %-30s %-15s %s\n", "ALIAS", "APP", "BRANCH", "STATUS", "LAST DEPLOYED")
+	fmt.Fprintf(w, "%-30s %-20s %-30s %-15s %s\n", "-----", "---", "------", "------", "-------------")
 
-	for _, dep := range deployments.Deployments {
-		deployedAt := "N/A"
-		if dep.DeployedAt != nil {
-			deployedAt = dep.DeployedAt.Local().Format("2006-01-02 15:04:05")
+	for _, dep := range previews {
+		fmt.Fprintf(w, "%-30s %-20s %-30s %-15s %s\n", dep.Alias, dep.PreviewOf, dep.PreviewBranch, dep.Status, formatDeployedAt(dep))
+	}
+}
+
+func printHiddenPreviews(w io.Writer, n int) {
+	if n > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "(%d preview deployment(s) not shown; use --previews)\n", n)
+	}
+}
+
+// printTruncated notes when --limit or --page left deployments out.
+func printTruncated(w io.Writer, filter apps.ListOptions, resp *apps.DeploymentsListResponse) {
+	if (filter.Limit == 0 && filter.Page == 0) || resp.Total <= len(resp.Deployments) {
+		return
+	}
+	fmt.Fprintln(w)
+	if filter.Page > 0 {
+		fmt.Fprintf(w, "(page %d: %d of %d deployments; use --page to see others)\n", filter.Page, len(resp.Deployments), resp.Total)
+		return
+	}
+	fmt.Fprintf(w, "(%d of %d deployments shown; raise --limit to see more)\n", len(resp.Deployments), resp.Total)
+}
+
+// validateAppsOutput checks the apps group's --output value.
+func validateAppsOutput(format string) error {
+	switch format {
+	case "table", "json", "yaml":
+		return nil
+	}
+	return fmt.Errorf("invalid --output %q (expected table, json or yaml)", format)
+}
+
+// writeAppsOutput prints v as indented JSON or as YAML with the same keys.
+func writeAppsOutput(stdout, stderr io.Writer, format string, v any) int {
+	if format == "yaml" {
+		if err := writeYAML(stdout, v); err != nil {
+			fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
 		}
-		fmt.Printf("%-20s %-40s %-15s %s\n", dep.Alias, dep.URL, dep.Status, deployedAt)
+		return 0
 	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+	return 0
+}
+
+func formatRegion(dep apps.Deployment) string {
+	if dep.Region == "" {
+		return "-"
+	}
+	return dep.Region
+}
+
+func formatDeployedAt(dep apps.Deployment) string {
+	if dep.DeployedAt == nil {
+		return "N/A"
+	}
+	return dep.DeployedAt.Local().Format("2006-01-02 15:04:05")
 }
 
 func runAppsDelete(cmd *cobra.Command, args []string) {
 	alias := args[0]
-	fmt.Printf("%s Attempting to delete application '%s'...\n", platform.Icon("🗑️", "[DEL]"), alias)
-	fmt.Println()
+	if err := validateAppsOutput(appsOutput); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	structured := appsOutput != "table"
+	if structured && !deleteYes {
+		fmt.Fprintf(os.Stderr, "%s -o %s needs --yes (there is no prompt in machine-readable mode)\n", platform.Icon("❌", "[X]"), appsOutput)
+		os.Exit(1)
+	}
+	if !structured {
+		fmt.Printf("%s Attempting to delete application '%s'...\n", platform.Icon("🗑️", "[DEL]"), alias)
+		fmt.Println()
+	}
 
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
 
 	if !deleteYes {
@@ -155,9 +352,18 @@ func runAppsDelete(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if structured {
+		deleteResponse, err := apps.DeleteApp(cmd.Context(), cfg.APIURL, cfg.APIToken, alias)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to delete application '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+			os.Exit(1)
+		}
+		os.Exit(writeAppsOutput(os.Stdout, os.Stderr, appsOutput, deleteResponse))
+	}
+
 	stop := spinner.Start("Deleting", "\033[31m")
 
-	deleteResponse, err := apps.DeleteApp(cfg.APIURL, cfg.APIToken, alias)
+	deleteResponse, err := apps.DeleteApp(cmd.Context(), cfg.APIURL, cfg.APIToken, alias)
 	stop()
 	if err != nil {
 		fmt.Printf("\r%s Failed to delete application '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
@@ -169,10 +375,19 @@ func runAppsDelete(cmd *cobra.Command, args []string) {
 
 func runAppsUpdate(cmd *cobra.Command, args []string) {
 	alias := args[0]
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
 
-	envMap := envPairsToMap(updateEnv)
+	envPairs := updateEnv
+	if len(updateEnvFiles) > 0 {
+		filePairs, err := env.ReadEnvFilePairs(updateEnvFiles)
+		if err != nil {
+			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+		envPairs = append(filePairs, updateEnv...)
+	}
+	envMap := envPairsToMap(envPairs)
 	var replicas *int32
 	if updateReplicas >= 0 {
 		r := int32(updateReplicas)
@@ -212,19 +427,63 @@ func runAppsUpdate(cmd *cobra.Command, args []string) {
 		microsoftScopes = updateMicrosoftScopes
 	}
 
-	hasUpdate := len(envMap) > 0 || replicas != nil || updateCPU != "" || updateMemory != "" || port != nil || faviconURL != nil || requireLogin != nil || accessPolicy != nil || googleScopes != nil || microsoftScopes != nil
+	for _, k := range updateRemoveEnv {
+		if !apps.EnvKeyRe.MatchString(k) {
+			fmt.Printf("%s Error: invalid --remove-env %q (expected a variable name)\n", platform.Icon("❌", "[X]"), k)
+			os.Exit(1)
+		}
+		if _, ok := envMap[k]; ok {
+			fmt.Printf("%s Error: %s is both set and removed; drop one of -e/--env-file or --remove-env\n", platform.Icon("❌", "[X]"), k)
+			os.Exit(1)
+		}
+	}
+
+	var autoscaling *apps.AutoscalingConfig
+	if cmd.Flags().Changed("min-replicas") || cmd.Flags().Changed("max-replicas") || cmd.Flags().Changed("target-cpu") {
+		if replicas != nil {
+			fmt.Printf("%s Error: --replicas sets a fixed count; drop it when using --min-replicas/--max-replicas/--target-cpu\n", platform.Icon("❌", "[X]"))
+			os.Exit(1)
+		}
+		autoscaling = &apps.AutoscalingConfig{}
+		if cmd.Flags().Changed("min-replicas") {
+			n := int32(updateMinReplicas)
+			autoscaling.MinReplicas = &n
+		}
+		if cmd.Flags().Changed("max-replicas") {
+			n := int32(updateMaxReplicas)
+			autoscaling.MaxReplicas = &n
+		}
+		if cmd.Flags().Changed("target-cpu") {
+			autoscaling.TargetCPUPercent = &updateTargetCPU
+		}
+		if err := autoscaling.Validate(); err != nil {
+			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+	}
+
+	if updateRegion != "" && !apps.RegionRe.MatchString(updateRegion) {
+		fmt.Printf("%s Error: invalid --region %q (expected an ID like eu-west; see 'dibbla regions list')\n", platform.Icon("❌", "[X]"), updateRegion)
+		os.Exit(1)
+	}
+
+	hasUpdate := len(envMap) > 0 || len(updateRemoveEnv) > 0 || replicas != nil || updateCPU != "" || updateMemory != "" || port != nil || faviconURL != nil || requireLogin != nil || accessPolicy != nil || googleScopes != nil || microsoftScopes != nil || updateRegion != "" || autoscaling != nil
 	if !hasUpdate {
-		fmt.Printf("%s Error: specify at least one of --env (-e), --replicas, --cpu, --memory, --port, --favicon, --require-login, --access-policy, --google-scopes, or --microsoft-scopes\n", platform.Icon("❌", "[X]"))
+		fmt.Printf("%s Error: specify at least one of --env (-e), --env-file, --remove-env, --replicas, --min-replicas, --max-replicas, --target-cpu, --cpu, --memory, --port, --favicon, --require-login, --access-policy, --google-scopes, --microsoft-scopes, or --region\n", platform.Icon("❌", "[X]"))
 		fmt.Println()
 		fmt.Println("Examples:")
 		fmt.Println("  dibbla apps update myapp -e NODE_ENV=production")
+		fmt.Println("  dibbla apps update myapp --env-file .env.production")
+		fmt.Println("  dibbla apps update myapp --remove-env DEBUG")
 		fmt.Println("  dibbla apps update myapp --replicas 3")
+		fmt.Println("  dibbla apps update myapp --min-replicas 2 --max-replicas 10 --target-cpu 70")
 		fmt.Println("  dibbla apps update myapp --cpu 500m --memory 512Mi --port 3000")
 		fmt.Println("  dibbla apps update myapp --favicon https://example.com/favicon.ico")
 		fmt.Println("  dibbla apps update myapp --require-login true")
 		fmt.Println("  dibbla apps update myapp --access-policy invite_only")
 		fmt.Println("  dibbla apps update myapp --google-scopes https://www.googleapis.com/auth/drive.readonly")
 		fmt.Println("  dibbla apps update myapp --microsoft-scopes Files.ReadWrite")
+		fmt.Println("  dibbla apps update myapp --region us-east")
 		os.Exit(1)
 	}
 
@@ -239,12 +498,16 @@ func runAppsUpdate(cmd *cobra.Command, args []string) {
 		AppAccessPolicy:      accessPolicy,
 		GoogleScopes:         googleScopes,
 		MicrosoftScopes:      microsoftScopes,
+		Region:               updateRegion,
+
+		RemoveEnvironmentVariables: updateRemoveEnv,
+		Autoscaling:                autoscaling,
 	}
 
 	fmt.Printf("%s Updating deployment '%s'...\n", platform.Icon("✏️", "[UPDATE]"), alias)
 	fmt.Println()
 
-	dep, err := apps.UpdateApp(cfg.APIURL, cfg.APIToken, alias, req)
+	dep, err := apps.UpdateApp(cmd.Context(), cfg.APIURL, cfg.APIToken, alias, req)
 	if err != nil {
 		fmt.Printf("%s Update failed: %v\n", platform.Icon("❌", "[X]"), err)
 		os.Exit(1)
@@ -255,28 +518,31 @@ func runAppsUpdate(cmd *cobra.Command, args []string) {
 	fmt.Printf("   Alias:  %s\n", dep.Alias)
 	fmt.Printf("   URL:    %s\n", dep.URL)
 	fmt.Printf("   Status: %s\n", dep.Status)
+	if dep.Region != "" {
+		fmt.Printf("   Region: %s\n", dep.Region)
+	}
 	if dep.HealthCheck != nil {
 		fmt.Printf("   Health: %s (%dms)\n", dep.HealthCheck.Status, dep.HealthCheck.ResponseTimeMs)
 	}
 }
 
 func runAppsRestart(cmd *cobra.Command, args []string) {
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
-	os.Exit(runAppsRestartCore(os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], restartService, restartQuiet, restartJSON))
+	os.Exit(runAppsRestartCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], restartService, restartQuiet, restartJSON))
 }
 
 // runAppsRestartCore is the testable inner implementation of `apps restart`.
 // Returns the exit code. Side-effect-free apart from writing to the given
 // writers and one HTTP POST.
-func runAppsRestartCore(stdout, stderr io.Writer, apiURL, apiToken, alias, service string, quiet, jsonOut bool) int {
+func runAppsRestartCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias, service string, quiet, jsonOut bool) int {
 	if !apps.ServiceNameRe.MatchString(service) {
 		fmt.Fprintf(stderr, "%s service name %q does not match %s\n",
 			platform.Icon("❌", "[X]"), service, apps.ServiceNameRe.String())
 		return 1
 	}
 
-	out, err := apps.RestartService(apiURL, apiToken, alias, service)
+	out, err := apps.RestartService(ctx, apiURL, apiToken, alias, service)
 	if err != nil {
 		// Distinguish 404 from generic failures so users get a focused hint.
 		if strings.Contains(err.Error(), "NOT_FOUND") || strings.Contains(strings.ToLower(err.Error()), "not found") {
@@ -0,0 +1,90 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func listServer(t *testing.T) string {
+	return newRestartServer(t, http.StatusOK, map[string]any{
+		"deployments": []map[string]any{
+			{"alias": "shop", "url": "https://shop.dibbla.com", "status": "running", "region": "eu-west"},
+			{"alias": "shop-feature-x", "status": "running", "preview": true, "preview_of": "shop", "preview_branch": "feature-x"},
+		},
+		"total": 2,
+	}).URL
+}
+
+func TestRunAppsListCore_Table(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runAppsListCore(context.Background(), &stdout, &stderr, listServer(t), "tok", apps.ListOptions{}, defaultTable(t), false, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "shop.dibbla.com") || !strings.Contains(out, "1 preview deployment(s) not shown") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+}
+
+func TestRunAppsListCore_JSONIsRawResponse(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runAppsListCore(context.Background(), &stdout, &stderr, listServer(t), "tok", apps.ListOptions{}, defaultTable(t), false, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var resp apps.DeploymentsListResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout.String())
+	}
+	if len(resp.Deployments) != 2 || resp.Deployments[0].Region != "eu-west" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestRunAppsListCore_YAML(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runAppsListCore(context.Background(), &stdout, &stderr, listServer(t), "tok", apps.ListOptions{}, defaultTable(t), false, "yaml"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "alias: shop") || strings.Contains(stdout.String(), "Retrieving") {
+		t.Errorf("unexpected yaml:\n%s", stdout.String())
+	}
+}
+
+func TestRunAppsListCore_BadOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runAppsListCore(context.Background(), &stdout, &stderr, "http://unused", "tok", apps.ListOptions{}, defaultTable(t), false, "xml"); code != 1 {
+		t.Errorf("exit %d, want 1", code)
+	}
+}
+
+func TestRunAppsListCore_NoMatches(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	filter := apps.ListOptions{NameContains: "nothing-like-this"}
+	if code := runAppsListCore(context.Background(), &stdout, &stderr, listServer(t), "tok", filter, defaultTable(t), false, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "No applications match the filters.") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestRunAppsListCore_LimitNotesTruncation(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"deployments": []map[string]any{{"alias": "a", "status": "running"}, {"alias": "b", "status": "running"}},
+		"total":       7,
+	})
+	var stdout, stderr bytes.Buffer
+	code := runAppsListCore(context.Background(), &stdout, &stderr, srv.URL, "tok", apps.ListOptions{Limit: 2}, defaultTable(t), false, "table")
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "(2 of 7 deployments shown; raise --limit to see more)") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+}
@@ -2,6 +2,7 @@ package deploy
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -27,7 +28,7 @@ func TestRunAppsRestartCore_HappyHumanOutput(t *testing.T) {
 	})
 
 	var stdout, stderr bytes.Buffer
-	if code := runAppsRestartCore(&stdout, &stderr, srv.URL, "tok", "myapp", "worker", false, false); code != 0 {
+	if code := runAppsRestartCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "worker", false, false); code != 0 {
 		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
 	}
 	out := stdout.String()
@@ -45,7 +46,7 @@ func TestRunAppsRestartCore_QuietPrintsAlias(t *testing.T) {
 		"message": "ok",
 	})
 	var stdout, stderr bytes.Buffer
-	if code := runAppsRestartCore(&stdout, &stderr, srv.URL, "tok", "myapp", "worker", true, false); code != 0 {
+	if code := runAppsRestartCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "worker", true, false); code != 0 {
 		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
 	}
 	if got := strings.TrimSpace(stdout.String()); got != "myapp" {
@@ -58,7 +59,7 @@ func TestRunAppsRestartCore_JSON(t *testing.T) {
 		"alias": "myapp", "service": "web", "status": "restarted", "message": "ok",
 	})
 	var stdout, stderr bytes.Buffer
-	if code := runAppsRestartCore(&stdout, &stderr, srv.URL, "tok", "myapp", "web", false, true); code != 0 {
+	if code := runAppsRestartCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "web", false, true); code != 0 {
 		t.Fatalf("exit %d", code)
 	}
 	var got map[string]string
@@ -76,7 +77,7 @@ func TestRunAppsRestartCore_ServiceNotFound(t *testing.T) {
 		"error":  map[string]any{"code": "NOT_FOUND", "message": "service worker not found"},
 	})
 	var stdout, stderr bytes.Buffer
-	if code := runAppsRestartCore(&stdout, &stderr, srv.URL, "tok", "myapp", "worker", false, false); code != 1 {
+	if code := runAppsRestartCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "worker", false, false); code != 1 {
 		t.Fatalf("exit %d", code)
 	}
 	if !strings.Contains(stderr.String(), "service not found") {
@@ -95,7 +96,7 @@ func TestRunAppsRestartCore_BadServiceNameRejectedLocally(t *testing.T) {
 	defer srv.Close()
 
 	var stdout, stderr bytes.Buffer
-	if code := runAppsRestartCore(&stdout, &stderr, srv.URL, "tok", "myapp", "Web", false, false); code != 1 {
+	if code := runAppsRestartCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "Web", false, false); code != 1 {
 		t.Fatalf("exit %d", code)
 	}
 	if !strings.Contains(stderr.String(), "does not match") {
@@ -112,7 +113,7 @@ func TestRunAppsRestartCore_GenericFailure(t *testing.T) {
 		"error":  map[string]any{"code": "INTERNAL_ERROR", "message": "kube went away"},
 	})
 	var stdout, stderr bytes.Buffer
-	if code := runAppsRestartCore(&stdout, &stderr, srv.URL, "tok", "myapp", "worker", false, false); code != 1 {
+	if code := runAppsRestartCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "worker", false, false); code != 1 {
 		t.Fatalf("exit %d", code)
 	}
 	if !strings.Contains(stderr.String(), "restart failed") {
@@ -0,0 +1,81 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	deploypkg "github.com/dibbla-agents/dibbla-cli/internal/deploy"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var buildersCmd = &cobra.Command{
+	Use:   "builders",
+	Short: "Show how deploys can be built",
+	Long: `Commands for the build strategies the platform supports.
+
+dibbla deploy builds the Dockerfile at the deploy root, or, when there is
+none, builds the source with Cloud Native Buildpacks. Pick one explicitly
+with dibbla deploy --builder dockerfile|buildpacks.`,
+}
+
+var buildersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List builders and buildpack stacks",
+	Long: `List the builders the platform supports and, for buildpacks, the stacks
+and the languages their buildpacks detect.
+
+Examples:
+  dibbla builders list
+  dibbla builders list --json`,
+	Args: cobra.NoArgs,
+	Run:  runBuildersList,
+}
+
+var buildersListJSON bool
+
+func init() {
+	buildersCmd.AddCommand(buildersListCmd)
+	buildersListCmd.Flags().BoolVar(&buildersListJSON, "json", false, "Print the JSON response body")
+}
+
+func runBuildersList(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runBuildersListCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, buildersListJSON))
+}
+
+// runBuildersListCore is the testable inner implementation of
+// `builders list`. Returns the exit code.
+func runBuildersListCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken string, jsonOut bool) int {
+	out, err := deploypkg.ListBuilders(ctx, apiURL, apiToken)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to list builders: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if jsonOut {
+		_ = json.NewEncoder(stdout).Encode(out)
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "%-12s %-20s %s\n", "BUILDER", "STACK", "LANGUAGES")
+	fmt.Fprintf(stdout, "%-12s %-20s %s\n", "-------", "-----", "---------")
+	for _, b := range out.Builders {
+		if len(b.Stacks) == 0 {
+			fmt.Fprintf(stdout, "%-12s %-20s %s\n", b.Name, "-", b.Description)
+			continue
+		}
+		for _, s := range b.Stacks {
+			stack := s.ID
+			if s.Default {
+				stack += " (default)"
+			}
+			fmt.Fprintf(stdout, "%-12s %-20s %s\n", b.Name, stack, strings.Join(s.Languages, ", "))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRunBuildersListCore(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"builders": []map[string]any{
+			{"name": "dockerfile", "description": "Build the Dockerfile at the deploy root"},
+			{"name": "buildpacks", "description": "Cloud Native Buildpacks", "stacks": []map[string]any{
+				{"id": "heroku-24", "languages": []string{"node", "python", "go"}, "default": true},
+			}},
+		},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runBuildersListCore(context.Background(), &stdout, &stderr, srv.URL, "tok", false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Build the Dockerfile", "heroku-24 (default)", "node, python, go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunBuildersListCore_Error(t *testing.T) {
+	srv := newRestartServer(t, http.StatusInternalServerError, map[string]any{
+		"status": "error", "error": map[string]any{"code": "INTERNAL", "message": "boom"},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runBuildersListCore(context.Background(), &stdout, &stderr, srv.URL, "tok", false); code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "INTERNAL") {
+		t.Errorf("stderr: %q", stderr.String())
+	}
+}
@@ -0,0 +1,97 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	deploypkg "github.com/dibbla-agents/dibbla-cli/internal/deploy"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsPromoteCmd = &cobra.Command{
+	Use:   "promote <alias>",
+	Short: "Send all traffic to the canary revision",
+	Long: `Finish a canary rollout started with 'dibbla deploy --strategy canary':
+all traffic moves to the canary revision and the previous revision is
+retired.
+
+Examples:
+  dibbla apps promote myapp
+  dibbla apps promote myapp --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAppsPromote,
+}
+
+var appsAbortCanaryCmd = &cobra.Command{
+	Use:   "abort-canary <alias>",
+	Short: "Roll back a canary and restore the stable revision",
+	Long: `Abort a canary rollout started with 'dibbla deploy --strategy canary':
+all traffic returns to the stable revision and the canary is removed.
+
+Examples:
+  dibbla apps abort-canary myapp`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAppsAbortCanary,
+}
+
+var canaryJSON bool
+
+func init() {
+	appsCmd.AddCommand(appsPromoteCmd)
+	appsCmd.AddCommand(appsAbortCanaryCmd)
+	appsPromoteCmd.Flags().BoolVar(&canaryJSON, "json", false, "Print the JSON response body")
+	appsAbortCanaryCmd.Flags().BoolVar(&canaryJSON, "json", false, "Print the JSON response body")
+}
+
+func runAppsPromote(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runCanaryActionCore(cmd.Context(), os.Stdout, os.Stderr, apps.PromoteCanary, cfg.APIURL, cfg.APIToken, args[0], "promote", canaryJSON))
+}
+
+func runAppsAbortCanary(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runCanaryActionCore(cmd.Context(), os.Stdout, os.Stderr, apps.AbortCanary, cfg.APIURL, cfg.APIToken, args[0], "abort", canaryJSON))
+}
+
+// runCanaryActionCore runs one canary action (apps.PromoteCanary or
+// apps.AbortCanary) and reports it. Returns the exit code.
+func runCanaryActionCore(ctx context.Context, stdout, stderr io.Writer, action func(ctx context.Context, apiURL, apiToken, alias string) (*apps.CanaryResponse, error), apiURL, apiToken, alias, verb string, jsonOut bool) int {
+	out, err := action(ctx, apiURL, apiToken, alias)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s canary %s failed: %v\n", platform.Icon("❌", "[X]"), verb, err)
+		return 1
+	}
+
+	if jsonOut {
+		_ = json.NewEncoder(stdout).Encode(out)
+		return 0
+	}
+	switch verb {
+	case "promote":
+		fmt.Fprintf(stdout, "%s %s: canary promoted, now serving all traffic\n", platform.Icon("✓", "[OK]"), out.Alias)
+	default:
+		fmt.Fprintf(stdout, "%s %s: canary aborted, traffic restored to the stable revision\n", platform.Icon("✓", "[OK]"), out.Alias)
+	}
+	if out.Message != "" {
+		fmt.Fprintf(stdout, "  %s\n", out.Message)
+	}
+	return 0
+}
+
+// printCanaryNext tells the user how to finish a canary deploy.
+func printCanaryNext(w io.Writer, alias string, weight int) {
+	if weight == 0 {
+		weight = deploypkg.DefaultCanaryWeight
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Canary live on %d%% of traffic for %s.\n", weight, alias)
+	fmt.Fprintf(w, "  promote:  dibbla apps promote %s\n", alias)
+	fmt.Fprintf(w, "  rollback: dibbla apps abort-canary %s\n", alias)
+}
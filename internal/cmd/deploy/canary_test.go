@@ -0,0 +1,38 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func TestRunCanaryActionCore_Promote(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{"alias": "myapp", "status": "promoted"})
+
+	var stdout, stderr bytes.Buffer
+	if code := runCanaryActionCore(context.Background(), &stdout, &stderr, apps.PromoteCanary, srv.URL, "tok", "myapp", "promote", false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "myapp: canary promoted") {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+}
+
+func TestRunCanaryActionCore_AbortError(t *testing.T) {
+	srv := newRestartServer(t, http.StatusConflict, map[string]any{
+		"status": "error",
+		"error":  map[string]any{"code": "NO_CANARY", "message": "no canary in progress"},
+	})
+
+	var stdout, stderr bytes.Buffer
+	if code := runCanaryActionCore(context.Background(), &stdout, &stderr, apps.AbortCanary, srv.URL, "tok", "myapp", "abort", false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "canary abort failed") || !strings.Contains(stderr.String(), "NO_CANARY") {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+}
@@ -0,0 +1,92 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsCloneCmd = &cobra.Command{
+	Use:   "clone <source-alias> <new-alias>",
+	Short: "Create a new deployment with another app's configuration",
+	Long: `Create a new deployment that copies the env vars, CPU/memory, replicas and
+port of an existing one. With --with-image the new deployment also runs
+the source's current image; otherwise it waits for
+'dibbla deploy --alias <new-alias>'.
+
+Use -e to override copied variables, e.g. to point a staging copy at its
+own database.
+
+Examples:
+  dibbla apps clone shop shop-staging --with-image
+  dibbla apps clone shop shop-staging -e DATABASE_URL=postgres://staging`,
+	Args: cobra.ExactArgs(2),
+	Run:  runAppsClone,
+}
+
+var (
+	cloneWithImage bool
+	cloneEnv       []string
+)
+
+func init() {
+	appsCmd.AddCommand(appsCloneCmd)
+	appsCloneCmd.Flags().BoolVar(&cloneWithImage, "with-image", false, "Also run the source's current image on the new deployment")
+	appsCloneCmd.Flags().StringArrayVarP(&cloneEnv, "env", "e", nil, "Override a copied env var: KEY=value (repeatable)")
+}
+
+func runAppsClone(cmd *cobra.Command, args []string) {
+	env, err := parseEnvAssignments(cloneEnv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	req := apps.CloneRequest{NewAlias: args[1], IncludeImage: cloneWithImage}
+	if len(env) > 0 {
+		req.EnvironmentVariables = env
+	}
+	os.Exit(runAppsCloneCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], req, appsOutput))
+}
+
+// runAppsCloneCore is the testable inner implementation of `apps clone`.
+// Returns the exit code.
+func runAppsCloneCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, source string, req apps.CloneRequest, format string) int {
+	if err := validateAppsOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	out, err := apps.CloneApp(ctx, apiURL, apiToken, source, req)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to clone '%s': %v\n", platform.Icon("❌", "[X]"), source, err)
+		return 1
+	}
+	if format != "table" {
+		return writeAppsOutput(stdout, stderr, format, out)
+	}
+
+	fmt.Fprintf(stdout, "%s Cloned '%s' to '%s'\n", platform.Icon("✅", "[OK]"), source, out.Alias)
+	if len(out.Copied) > 0 {
+		fmt.Fprintf(stdout, "   Copied: %s\n", strings.Join(out.Copied, ", "))
+	}
+	if out.URL != "" {
+		fmt.Fprintf(stdout, "   URL: %s\n", out.URL)
+	}
+	if out.Status != "" {
+		fmt.Fprintf(stdout, "   Status: %s\n", out.Status)
+	}
+	if !req.IncludeImage {
+		fmt.Fprintf(stdout, "   No code yet: run 'dibbla deploy --alias %s' to deploy it.\n", out.Alias)
+	}
+	if out.Message != "" {
+		fmt.Fprintf(stdout, "   %s\n", out.Message)
+	}
+	return 0
+}
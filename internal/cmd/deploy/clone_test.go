@@ -0,0 +1,37 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func TestRunAppsCloneCore(t *testing.T) {
+	srv := newRestartServer(t, http.StatusCreated, map[string]any{
+		"alias": "shop-staging", "source": "shop", "status": "received",
+		"url": "https://shop-staging.dibbla.com", "copied": []string{"env", "resources", "port"},
+	})
+	var stdout, stderr bytes.Buffer
+	code := runAppsCloneCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", apps.CloneRequest{NewAlias: "shop-staging"}, "table")
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Cloned 'shop' to 'shop-staging'", "Copied: env, resources, port", "dibbla deploy --alias shop-staging"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunAppsCloneCore_InvalidAlias(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runAppsCloneCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "shop", apps.CloneRequest{NewAlias: "Bad_Alias"}, "table")
+	if code != 1 || !strings.Contains(stderr.String(), "invalid alias") {
+		t.Errorf("exit %d stderr=%q", code, stderr.String())
+	}
+}
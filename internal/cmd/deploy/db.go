@@ -1,73 +1,150 @@
 package deploy
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
+	"os/exec"
+	"slices"
 	"strings"
+	"text/tabwriter"
 	"time"
 
-	"github.com/dibbla-agents/dibbla-cli/internal/config"
 	"github.com/dibbla-agents/dibbla-cli/internal/db"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 	"github.com/dibbla-agents/dibbla-cli/internal/spinner"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 var dbCmd = &cobra.Command{
 	Use:   "db",
 	Short: "Manage Dibbla databases",
-	Long:  `Provides commands to list, create, delete, dump, and restore managed databases.`,
+	Long:  `Provides commands to list, create, inspect, delete, dump, and restore managed databases.`,
 }
 
 var dbListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all managed databases",
-	Long:  `Fetches and displays a list of all databases managed by the Dibbla platform.`,
-	Run:   runDbList,
+	Long: `Fetches and displays all databases managed by the Dibbla platform, with
+their status, engine version, size and creation time.`,
+	Run: runDbList,
 }
 
 var dbCreateCmd = &cobra.Command{
 	Use:   "create [name]",
 	Short: "Create a new database",
-	Long:  `Creates a new managed database. Provide the name as an argument or via --name.`,
-	Args:  cobra.MaximumNArgs(1),
-	Run:   runDbCreate,
+	Long: `Creates a new managed database. Provide the name as an argument or via --name.
+
+--engine picks the datastore: postgres (default), mysql or redis.
+--version picks the engine's major version, --tier the instance size
+(small, medium or large) and --region where it runs; left out, the
+server picks its defaults. The configuration created is printed.
+
+Examples:
+  dibbla db create mydb
+  dibbla db create mydb --version 16 --tier medium --region eu-west-1
+  dibbla db create cache --engine redis --tier small`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runDbCreate,
 }
 
 var dbDeleteCmd = &cobra.Command{
-	Use:   "delete <name>",
+	Use:   "delete <name> | --match <pattern>",
 	Short: "Delete a database",
-	Long:  `Deletes a specific database by name. This action cannot be undone.`,
-	Args:  cobra.ExactArgs(1),
-	Run:   runDbDelete,
+	Long: `Deletes a specific database by name. This action cannot be undone.
+
+--match deletes every database whose name matches a glob pattern (* ? and
+[...]), e.g. the ones CI left behind. The matching databases are listed
+and confirmed once, then deleted one by one with a result for each.
+
+Each database is backed up before it is deleted and the snapshot ID
+printed, so a mistake can be undone with 'dibbla db backups restore';
+--no-snapshot skips this.
+
+Examples:
+  dibbla db delete mydb
+  dibbla db delete --match 'test-*'
+  dibbla db delete --match 'ci-pr-*' --yes`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("match") {
+			if dbDeleteMatch == "" {
+				return fmt.Errorf("--match needs a pattern")
+			}
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: runDbDelete,
 }
 
 var dbRestoreCmd = &cobra.Command{
 	Use:   "restore <name>",
 	Short: "Restore a database from a dump file",
-	Long:  `Restores a database from an uploaded dump file (e.g. custom-format pg_dump archive).`,
-	Args:  cobra.ExactArgs(1),
-	Run:   runDbRestore,
+	Long: `Restores a database from an uploaded dump file (e.g. custom-format pg_dump archive).
+
+The dump is read from --file (- for stdin) or downloaded from --from-url,
+and streamed to the API as it is read, so nothing is buffered or saved
+locally. It is restored by a background job on the server; the command
+follows the job and shows its progress until it finishes. With --no-wait
+it returns once the upload is done and prints the job ID, which --job
+follows later. Ctrl-C stops following, not the restore.
+
+--clean drops the objects in the dump before recreating them, so a dump
+can be restored over an existing schema; add --if-exists to skip objects
+that don't exist yet (Postgres only). Before a --clean restore the
+database is backed up, and the snapshot ID printed, unless --no-snapshot.
+
+Examples:
+  dibbla db restore mydb --file backup.dump
+  dibbla db restore mydb --file backup.dump --clean --if-exists
+  dibbla db restore mydb --file backup.dump --no-wait
+  dibbla db dump prod --output - | dibbla db restore staging --file -
+  dibbla db restore mydb --from-url "https://bucket.s3.amazonaws.com/mydb.dump?X-Amz-..."
+  dibbla db restore mydb --job rst_123`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDbRestore,
 }
 
 var dbDumpCmd = &cobra.Command{
 	Use:   "dump <name> [--output file.dump]",
 	Short: "Dump a database",
-	Long:  `Downloads a database dump as an application/octet-stream (custom-format pg_dump archive).`,
-	Args:  cobra.ExactArgs(1),
-	Run:   runDbDump,
+	Long: `Downloads a database dump, showing the bytes received on a terminal.
+
+--format picks the pg_dump format: custom (default; compressed archive for
+pg_restore), plain (a readable .sql script for psql) or tar (an archive
+pg_restore can restore selectively). MySQL databases dump as a plain
+mysqldump script (.sql) and Redis databases as an RDB snapshot (.rdb).
+
+The dump is written to a temporary file next to the output and renamed
+into place only once complete, so a failed or interrupted dump never
+leaves a truncated file (or clobbers an earlier dump). Use --output - to
+stream it to stdout instead.
+
+Examples:
+  dibbla db dump mydb
+  dibbla db dump mydb --output backups/mydb.dump
+  dibbla db dump mydb --format plain          # writes mydb.sql
+  dibbla db dump mydb --output - | pg_restore --list`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDbDump,
 }
 
 var dbConnectCmd = &cobra.Command{
 	Use:   "connect <name>",
-	Short: "Print the connection string for a database",
-	Long: `Prints a psql-compatible connection string for connecting to a database
-via the Dibbla database proxy.
+	Short: "Open a SQL shell on a database",
+	Long: `Opens an interactive SQL session on a database. Runs the local psql with
+the right credentials when it is installed; otherwise starts a minimal
+built-in SQL shell that runs statements through the Dibbla API.
+
+With --print, or when not run in a terminal, prints a psql-compatible
+connection string for the Dibbla database proxy instead.
 
 The proxy host and TLS mode are derived from DIBBLA_API_URL:
   api.dibbla.com  → db.dibbla.com  (sslmode=require)
@@ -78,6 +155,8 @@ Uses your current API token as the password.
 
 Examples:
   dibbla db connect myapp
+  dibbla db connect myapp --builtin
+  dibbla db connect myapp --print
   psql $(dibbla db connect myapp --quiet)
   export DATABASE_URL=$(dibbla db connect myapp -q)`,
 	Args: cobra.ExactArgs(1),
@@ -87,12 +166,34 @@ Examples:
 var (
 	dbDeleteYes        bool
 	dbDeleteQuiet      bool
+	dbDeleteOutput     string
+	dbDeleteMatch      string
+	dbDeleteSnapshot   bool
+	dbDeleteNoSnapshot bool
 	dbListQuiet        bool
+	dbListOutput       string
 	dbConnectQuiet     bool
+	dbConnectPrint     bool
+	dbConnectBuiltin   bool
 	dbCreateName       string
 	dbCreateDeployment string
+	dbCreateEngine     string
+	dbCreateVersion    string
+	dbCreateTier       string
+	dbCreateRegion     string
+	dbCreateOutput     string
 	dbRestoreFile      string
+	dbRestoreJob       string
+	dbRestoreFromURL   string
+	dbRestoreNoWait    bool
+	dbRestoreOutput    string
+	dbRestoreClean     bool
+	dbRestoreIfExists  bool
+	dbRestoreSnapshot  bool
+	dbRestoreNoSnap    bool
 	dbDumpOutput       string
+	dbDumpFormat       string
+	dbDumpJSON         bool
 )
 
 func init() {
@@ -105,49 +206,128 @@ func init() {
 
 	dbDeleteCmd.Flags().BoolVarP(&dbDeleteYes, "yes", "y", false, "Skip confirmation prompt")
 	dbDeleteCmd.Flags().BoolVarP(&dbDeleteQuiet, "quiet", "q", false, "Suppress progress and success output (errors only)")
+	dbDeleteCmd.Flags().StringVarP(&dbDeleteOutput, "output", "o", "text", "Output format: text or json")
+	dbDeleteCmd.MarkFlagsMutuallyExclusive("quiet", "output")
+	dbDeleteCmd.Flags().StringVar(&dbDeleteMatch, "match", "", "Delete all databases whose name matches this glob pattern (e.g. 'test-*')")
+	dbDeleteCmd.Flags().BoolVar(&dbDeleteSnapshot, "snapshot-first", true, "Back up the database before deleting it")
+	dbDeleteCmd.Flags().BoolVar(&dbDeleteNoSnapshot, "no-snapshot", false, "Delete without a safety snapshot")
+	dbDeleteCmd.MarkFlagsMutuallyExclusive("snapshot-first", "no-snapshot")
 	dbListCmd.Flags().BoolVarP(&dbListQuiet, "quiet", "q", false, "Only print database names, one per line (for scripting)")
+	dbListCmd.Flags().StringVarP(&dbListOutput, "output", "o", "table", "Output format: table or json")
+	dbListCmd.MarkFlagsMutuallyExclusive("quiet", "output")
 	dbCreateCmd.Flags().StringVar(&dbCreateName, "name", "", "Name of the database to create")
 	dbCreateCmd.Flags().StringVar(&dbCreateDeployment, "deployment", "", "Scope the database and its DATABASE_URL secret to a specific deployment")
-	dbRestoreCmd.Flags().StringVarP(&dbRestoreFile, "file", "f", "", "Path to the dump file to restore (required)")
-	dbRestoreCmd.MarkFlagRequired("file")
-	dbDumpCmd.Flags().StringVarP(&dbDumpOutput, "output", "o", "", "Output file path (default: <name>.dump)")
+	dbCreateCmd.Flags().StringVar(&dbCreateEngine, "engine", db.EnginePostgres, "Database engine: postgres, mysql or redis")
+	dbCreateCmd.Flags().StringVar(&dbCreateVersion, "version", "", "Engine major version, e.g. 16 (default: the server's current default)")
+	dbCreateCmd.Flags().StringVar(&dbCreateTier, "tier", "", "Instance size: small, medium or large (default: the server's default)")
+	dbCreateCmd.Flags().StringVar(&dbCreateRegion, "region", "", "Region to create the database in, e.g. eu-west-1 (default: the project's or profile's region, else the server's default)")
+	dbCreateCmd.Flags().StringVarP(&dbCreateOutput, "output", "o", "text", "Output format: text or json")
+	dbRestoreCmd.Flags().StringVarP(&dbRestoreFile, "file", "f", "", "Path to the dump file to restore, or - for stdin")
+	dbRestoreCmd.Flags().StringVar(&dbRestoreFromURL, "from-url", "", "Stream the dump from an http(s) URL (e.g. a presigned object storage link)")
+	dbRestoreCmd.Flags().StringVar(&dbRestoreJob, "job", "", "Follow an existing restore job instead of uploading a file")
+	dbRestoreCmd.Flags().BoolVar(&dbRestoreNoWait, "no-wait", false, "Return once the dump is uploaded instead of waiting for the restore")
+	dbRestoreCmd.MarkFlagsOneRequired("file", "from-url", "job")
+	dbRestoreCmd.MarkFlagsMutuallyExclusive("file", "from-url", "job")
+	dbRestoreCmd.MarkFlagsMutuallyExclusive("job", "no-wait")
+	dbRestoreCmd.Flags().BoolVar(&dbRestoreClean, "clean", false, "Drop existing objects before recreating them (pg_restore --clean)")
+	dbRestoreCmd.Flags().BoolVar(&dbRestoreIfExists, "if-exists", false, "With --clean, don't fail on objects that don't exist yet (pg_restore --if-exists)")
+	dbRestoreCmd.Flags().BoolVar(&dbRestoreSnapshot, "snapshot-first", true, "With --clean, back up the database before restoring over it")
+	dbRestoreCmd.Flags().BoolVar(&dbRestoreNoSnap, "no-snapshot", false, "Restore with --clean without a safety snapshot")
+	dbRestoreCmd.MarkFlagsMutuallyExclusive("snapshot-first", "no-snapshot")
+	dbRestoreCmd.MarkFlagsMutuallyExclusive("job", "clean")
+	dbRestoreCmd.MarkFlagsMutuallyExclusive("job", "if-exists")
+	dbRestoreCmd.Flags().StringVarP(&dbRestoreOutput, "output", "o", "text", "Output format: text or json (the final job, or the submitted one with --no-wait)")
+	dbDumpCmd.Flags().StringVarP(&dbDumpOutput, "output", "o", "", "Output file path, or - for stdout (default: <name>.dump, .sql or .tar by format)")
+	dbDumpCmd.Flags().StringVar(&dbDumpFormat, "format", "", "Dump format: custom (pg_restore archive, the Postgres default), plain (SQL script) or tar; mysql: plain; redis: rdb")
+	dbDumpCmd.Flags().BoolVar(&dbDumpJSON, "json", false, "Print a JSON summary of the saved dump on stdout (messages go to stderr)")
 	dbConnectCmd.Flags().BoolVarP(&dbConnectQuiet, "quiet", "q", false, "Only print the connection string (for scripting)")
+	dbConnectCmd.Flags().BoolVar(&dbConnectPrint, "print", false, "Print the connection string and tips instead of opening a shell")
+	dbConnectCmd.Flags().BoolVar(&dbConnectBuiltin, "builtin", false, "Use the built-in SQL shell even if psql is installed")
+	dbConnectCmd.MarkFlagsMutuallyExclusive("quiet", "print", "builtin")
 }
 
 func runDbList(cmd *cobra.Command, args []string) {
-	if !dbListQuiet {
-		fmt.Printf("%s Retrieving databases...\n", platform.Icon("🌱", "[>]"))
-		fmt.Println()
-	}
-
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
+	os.Exit(runDbListCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, dbListQuiet, dbListOutput))
+}
+
+// runDbListCore is the testable inner implementation of `db list`.
+// Returns the exit code.
+func runDbListCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken string, quiet bool, format string) int {
+	if format != "table" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected table or json)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+	if !quiet && format == "table" {
+		fmt.Fprintf(stdout, "%s Retrieving databases...\n", platform.Icon("🌱", "[>]"))
+		fmt.Fprintln(stdout)
+	}
 
-	list, err := db.ListDatabases(cfg.APIURL, cfg.APIToken)
+	list, err := db.ListDatabases(ctx, apiURL, apiToken)
 	if err != nil {
-		fmt.Printf("%s Failed to list databases: %v\n", platform.Icon("❌", "[X]"), err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "%s Failed to list databases: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
 	}
 
-	if list.Total == 0 {
-		if !dbListQuiet {
-			fmt.Println("No databases found.")
+	if quiet {
+		for _, d := range list.Databases {
+			fmt.Fprintln(stdout, d.Name)
 		}
-		return
+		return 0
+	}
+	if format == "json" {
+		if list.Databases == nil {
+			list.Databases = []db.Database{}
+		}
+		return writeDbJSON(stdout, stderr, list)
 	}
 
-	if dbListQuiet {
-		for _, name := range list.Databases {
-			fmt.Println(name)
+	if len(list.Databases) == 0 {
+		fmt.Fprintln(stdout, "No databases found.")
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "Found %d database(s):\n", list.Total)
+	fmt.Fprintln(stdout)
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS\tENGINE\tSIZE\tCREATED")
+	for _, d := range list.Databases {
+		size, created := "-", "-"
+		if d.SizeBytes > 0 {
+			size = formatBytes(float64(d.SizeBytes))
 		}
-		return
+		if d.CreatedAt != nil {
+			created = d.CreatedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		engine := d.EngineVersion
+		if engine == "" {
+			engine = d.Engine
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", d.Name, orDash(d.Status), orDash(engine), size, created)
 	}
+	tw.Flush()
+	return 0
+}
 
-	fmt.Printf("Found %d database(s):\n", list.Total)
-	fmt.Println()
-	for _, name := range list.Databases {
-		fmt.Println("  ", name)
+// writeDbJSON prints v as indented JSON for the db commands' --output json.
+func writeDbJSON(stdout, stderr io.Writer, v any) int {
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(stderr, "%s Failed to encode JSON: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	return 0
+}
+
+// validateDbOutput checks the --output value of db commands that print
+// text by default.
+func validateDbOutput(format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid --output %q (expected text or json)", format)
 	}
+	return nil
 }
 
 func runDbCreate(cmd *cobra.Command, args []string) {
@@ -155,158 +335,267 @@ func runDbCreate(cmd *cobra.Command, args []string) {
 	if len(args) > 0 {
 		name = args[0]
 	}
-	if name == "" {
-		fmt.Printf("%s Error: database name is required (use argument or --name)\n", platform.Icon("❌", "[X]"))
-		os.Exit(1)
+	cfg := loadConfig()
+	requireToken(cfg)
+	opts := db.CreateOptions{
+		Deployment: dbCreateDeployment,
+		Engine:     dbCreateEngine,
+		Version:    dbCreateVersion,
+		Tier:       dbCreateTier,
+		Region:     dbCreateRegion,
 	}
+	if opts.Region == "" {
+		opts.Region = cfg.Region // the project's or profile's default region, if any
+	}
+	os.Exit(runDbCreateCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, name, opts, dbCreateOutput))
+}
 
-	if dbCreateDeployment != "" {
-		fmt.Printf("%s Creating database '%s' (scoped to deployment '%s')...\n", platform.Icon("🌱", "[>]"), name, dbCreateDeployment)
-	} else {
-		fmt.Printf("%s Creating database '%s'...\n", platform.Icon("🌱", "[>]"), name)
+// runDbCreateCore is the testable inner implementation of `db create`.
+// Returns the exit code.
+func runDbCreateCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name string, opts db.CreateOptions, format string) int {
+	if err := validateDbOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if name == "" {
+		fmt.Fprintf(stderr, "%s Error: database name is required (use argument or --name)\n", platform.Icon("❌", "[X]"))
+		return 1
+	}
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintf(stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
 	}
-	fmt.Println()
 
-	cfg := config.Load()
-	requireToken(cfg)
+	if format == "text" {
+		if opts.Deployment != "" {
+			fmt.Fprintf(stdout, "%s Creating database '%s' (scoped to deployment '%s')...\n", platform.Icon("🌱", "[>]"), name, opts.Deployment)
+		} else {
+			fmt.Fprintf(stdout, "%s Creating database '%s'...\n", platform.Icon("🌱", "[>]"), name)
+		}
+		fmt.Fprintln(stdout)
+	}
 
-	created, err := db.CreateDatabase(cfg.APIURL, cfg.APIToken, name, dbCreateDeployment)
+	created, err := db.CreateDatabase(ctx, apiURL, apiToken, name, opts)
 	if err != nil {
-		fmt.Printf("%s Failed to create database: %v\n", platform.Icon("❌", "[X]"), err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "%s Failed to create database: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if format == "json" {
+		return writeDbJSON(stdout, stderr, created)
 	}
 
-	fmt.Printf("%s %s\n", platform.Icon("✅", "[OK]"), created.Message)
-	fmt.Printf("  Database: %s\n", created.Database)
+	fmt.Fprintf(stdout, "%s %s\n", platform.Icon("✅", "[OK]"), created.Message)
+	fmt.Fprintf(stdout, "  Database: %s\n", created.Database)
+	// Older servers don't echo the configuration; show what was asked for.
+	engine, version := cmp.Or(created.Engine, opts.Engine), cmp.Or(created.EngineVersion, opts.Version)
+	if engine != "" {
+		if version != "" {
+			engine += " " + version
+		}
+		fmt.Fprintf(stdout, "  Engine:   %s\n", engine)
+	}
+	if tier := cmp.Or(created.Tier, opts.Tier); tier != "" {
+		fmt.Fprintf(stdout, "  Tier:     %s\n", tier)
+	}
+	if region := cmp.Or(created.Region, opts.Region); region != "" {
+		fmt.Fprintf(stdout, "  Region:   %s\n", region)
+	}
 	if created.SecretName != "" {
-		fmt.Printf("  Secret:   %s (auto-created)\n", created.SecretName)
-		if dbCreateDeployment != "" {
-			fmt.Printf("\n  The secret is scoped to deployment '%s'.\n", dbCreateDeployment)
-			fmt.Println("  It will be injected automatically when that deployment starts.")
+		fmt.Fprintf(stdout, "  Secret:   %s (auto-created)\n", created.SecretName)
+		if opts.Deployment != "" {
+			fmt.Fprintf(stdout, "\n  The secret is scoped to deployment '%s'.\n", opts.Deployment)
+			fmt.Fprintln(stdout, "  It will be injected automatically when that deployment starts.")
 		} else {
-			fmt.Println("\n  This is a global secret available to all deployments in your org.")
-			fmt.Println("  It will be injected automatically on every deploy.")
+			fmt.Fprintln(stdout, "\n  This is a global secret available to all deployments in your org.")
+			fmt.Fprintln(stdout, "  It will be injected automatically on every deploy.")
 		}
 	}
+	return 0
 }
 
 func runDbDelete(cmd *cobra.Command, args []string) {
+	quiet := dbDeleteQuiet || dbDeleteOutput == "json"
+	// With -q or JSON output stdout is for scripts; prompt on stderr.
+	confirm := askConfirm
+	if quiet {
+		confirm = askConfirmStderr
+	}
+	if dbDeleteYes {
+		confirm = nil
+	}
+	if cmd.Flags().Changed("match") {
+		cfg := loadConfig()
+		requireToken(cfg)
+		os.Exit(runDbDeleteMatchCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, dbDeleteMatch, confirm, dbDeleteSnapshot && !dbDeleteNoSnapshot, dbDeleteQuiet, dbDeleteOutput))
+	}
 	name := args[0]
-	if !dbDeleteQuiet {
+	if !quiet {
 		fmt.Printf("%s Attempting to delete database '%s'...\n", platform.Icon("🗑️", "[DEL]"), name)
 		fmt.Println()
 	}
 
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
 
-	if !dbDeleteYes {
-		if !askConfirm(fmt.Sprintf("Are you sure you want to delete database '%s'? This action cannot be undone.", name)) {
-			if !dbDeleteQuiet {
+	if confirm != nil {
+		if !confirm(fmt.Sprintf("Are you sure you want to delete database '%s'? This action cannot be undone.", name)) {
+			if !quiet {
 				fmt.Println("Deletion cancelled.")
 			}
 			os.Exit(0)
 		}
 	}
+	os.Exit(runDbDeleteCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, name, dbDeleteSnapshot && !dbDeleteNoSnapshot, dbDeleteQuiet, dbDeleteOutput))
+}
 
+// runDbDeleteCore is the testable inner implementation of `db delete`,
+// after confirmation. snapshot backs the database up first. quiet
+// suppresses the spinner and success line. Returns the exit code.
+func runDbDeleteCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name string, snapshot, quiet bool, format string) int {
+	if err := validateDbOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	human := !quiet && format == "text"
+	var snapshotID string
+	if snapshot {
+		// Keep the snapshot ID visible even when stdout is quiet or JSON.
+		msg := stdout
+		if !human {
+			msg = stderr
+		}
+		if snapshotID = takeSafetySnapshot(ctx, msg, stderr, apiURL, apiToken, name, true); snapshotID == "" {
+			return 1
+		}
+	}
 	stop := func() {}
-	if !dbDeleteQuiet {
+	if human {
 		stop = spinner.Start("Deleting", "\033[31m")
 	}
 
-	del, err := db.DeleteDatabase(cfg.APIURL, cfg.APIToken, name)
+	del, err := db.DeleteDatabase(ctx, apiURL, apiToken, name)
 	stop()
 	if err != nil {
-		if !dbDeleteQuiet {
-			fmt.Printf("\r")
+		if human {
+			fmt.Fprintf(stdout, "\r")
 		}
-		fmt.Printf("%s Failed to delete database '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "%s Failed to delete database '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
 	}
 
-	if !dbDeleteQuiet {
-		fmt.Printf("\r%s %s\n", platform.Icon("✅", "[OK]"), del.Message)
-	}
+	switch {
+	case format == "json":
+		return writeDbJSON(stdout, stderr, struct {
+			*db.DeleteResponse
+			Snapshot string `json:"snapshot,omitempty"`
+		}{del, snapshotID})
+	case human:
+		fmt.Fprintf(stdout, "\r%s %s\n", platform.Icon("✅", "[OK]"), del.Message)
+	}
+	return 0
 }
 
 func runDbRestore(cmd *cobra.Command, args []string) {
-	name := args[0]
-	fmt.Printf("%s Restoring database '%s' from %s...\n", platform.Icon("🌱", "[>]"), name, dbRestoreFile)
-	fmt.Println()
-
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
 
-	stop := spinner.Start("Restoring", "")
-
-	res, err := db.RestoreDatabase(cfg.APIURL, cfg.APIToken, name, dbRestoreFile)
-	stop()
-	if err != nil {
-		fmt.Printf("\r%s Failed to restore database: %v\n", platform.Icon("❌", "[X]"), err)
-		os.Exit(1)
-	}
-
-	fmt.Printf("\r%s %s\n", platform.Icon("✅", "[OK]"), res.Message)
+	opts := dbRestoreOptions{
+		Source:   dumpSource{File: dbRestoreFile, URL: dbRestoreFromURL},
+		Restore:  db.RestoreOptions{Clean: dbRestoreClean, IfExists: dbRestoreIfExists},
+		Snapshot: dbRestoreSnapshot && !dbRestoreNoSnap,
+		JobID:    dbRestoreJob,
+		Wait:     !dbRestoreNoWait,
+		Interval: restorePollInterval,
+		Progress: isatty.IsTerminal(os.Stdout.Fd()) && !platform.IsCI(),
+		Output:   dbRestoreOutput,
+	}
+	os.Exit(runDbRestoreCore(cmd.Context(), os.Stdin, os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], opts))
 }
 
 func runDbDump(cmd *cobra.Command, args []string) {
 	name := args[0]
-	outPath := dbDumpOutput
-	if outPath == "" {
-		outPath = name + ".dump"
-	}
-
-	fmt.Printf("%s Dumping database '%s' to %s...\n", platform.Icon("🌱", "[>]"), name, outPath)
-	fmt.Println()
-
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
 
-	f, err := os.Create(outPath)
+	engine, err := lookupDatabaseEngine(cmd.Context(), cfg.APIURL, cfg.APIToken, name)
 	if err != nil {
-		fmt.Printf("%s Failed to create output file: %v\n", platform.Icon("❌", "[X]"), err)
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
 		os.Exit(1)
 	}
-	defer f.Close()
-
-	stop := spinner.Start("Dumping", "")
+	formats := db.DumpFormatsFor(engine)
+	format := dbDumpFormat
+	if format == "" {
+		format = formats[0]
+	}
+	if !slices.Contains(formats, format) {
+		fmt.Printf("%s Error: invalid --format %q for a %s database (expected one of: %s)\n", platform.Icon("❌", "[X]"), format, engine, strings.Join(formats, ", "))
+		os.Exit(1)
+	}
+	outPath := dbDumpOutput
+	if outPath == "" {
+		outPath = name + db.DumpExtension(format)
+	}
 
-	err = db.DumpDatabase(cfg.APIURL, cfg.APIToken, name, f)
-	stop()
-	if err != nil {
-		f.Close()
-		os.Remove(outPath)
-		fmt.Printf("\r%s Failed to dump database: %v\n", platform.Icon("❌", "[X]"), err)
+	if dbDumpJSON && outPath == "-" {
+		fmt.Fprintf(os.Stderr, "%s Error: --json needs a dump file; with --output - stdout carries the dump\n", platform.Icon("❌", "[X]"))
 		os.Exit(1)
 	}
 
-	abs, _ := filepath.Abs(outPath)
-	fmt.Printf("\r%s Dump saved to %s\n", platform.Icon("✅", "[OK]"), abs)
+	// Messages go to stderr when stdout carries the dump or the JSON summary.
+	msg := os.Stdout
+	if outPath == "-" || dbDumpJSON {
+		msg = os.Stderr
+	}
+	showProgress := isatty.IsTerminal(msg.Fd()) && !platform.IsCI()
+	os.Exit(runDbDumpCore(cmd.Context(), os.Stdout, msg, cfg.APIURL, cfg.APIToken, name, format, outPath, showProgress, dbDumpJSON))
 }
 
 func runDbConnect(cmd *cobra.Command, args []string) {
 	name := args[0]
 
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
 
 	host, port, sslmode := dbProxyEndpoint(cfg.APIURL, cfg.APIToken, os.Getenv)
-	connStr := fmt.Sprintf("postgres://dibbla:%s@%s:%s/%s?sslmode=%s", cfg.APIToken, host, port, name, sslmode)
+	engine, err := lookupDatabaseEngine(cmd.Context(), cfg.APIURL, cfg.APIToken, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	proxy := dbProxyInfo{Host: host, Port: port, SSLMode: sslmode}.forEngine(engine)
+	connStr := dbConnectionString(engine, cfg.APIToken, proxy.Host, proxy.Port, name, proxy.SSLMode)
 
 	if dbConnectQuiet {
 		fmt.Print(connStr)
 		return
 	}
 
+	interactive := isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+	if dbConnectBuiltin && !dbHasSQL(engine) {
+		fmt.Fprintf(os.Stderr, "%s The built-in shell runs SQL; '%s' is a %s database\n", platform.Icon("❌", "[X]"), name, engine)
+		os.Exit(1)
+	}
+	if !dbConnectPrint && (interactive || dbConnectBuiltin) {
+		if !dbConnectBuiltin {
+			client, clientArgs, passwordEnv := dbClientCommand(engine, proxy.Host, proxy.Port, name, proxy.SSLMode)
+			if path, err := exec.LookPath(client); err == nil {
+				os.Exit(runDbClient(path, clientArgs, []string{passwordEnv + "=" + cfg.APIToken}, os.Stderr))
+			}
+		}
+		if dbHasSQL(engine) {
+			os.Exit(runDbShell(cmd.Context(), cfg.APIURL, cfg.APIToken, name, interactive))
+		}
+	}
+
 	fmt.Printf("%s Connection string for database '%s':\n", platform.Icon("🔗", "[>]"), name)
 	fmt.Println()
 	fmt.Printf("  %s\n", connStr)
 	fmt.Println()
 	fmt.Println("Quick connect:")
-	fmt.Printf("  psql $(dibbla db connect %s -q)\n", name)
+	fmt.Printf("  %s\n", dbQuickConnect(engine, name))
 	fmt.Println()
 	fmt.Println("Or export as an environment variable:")
-	fmt.Printf("  export DATABASE_URL=$(dibbla db connect %s -q)\n", name)
+	fmt.Printf("  export %s=$(dibbla db connect %s -q)\n", dbEnvVar(engine), name)
 }
 
 // dbProxyInfo is the shape of GET /db/proxy-info.
@@ -0,0 +1,104 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+func TestRunDbCreateCore_JSON(t *testing.T) {
+	srv := newRestartServer(t, http.StatusCreated, db.DatabaseCreateResponse{
+		Status: "success", Message: "Database created", Database: "mydb", Engine: "postgres", SecretName: "DATABASE_URL",
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runDbCreateCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", db.CreateOptions{Engine: db.EnginePostgres}, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var got db.DatabaseCreateResponse
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout.String())
+	}
+	if got.Database != "mydb" || got.SecretName != "DATABASE_URL" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestRunDbCreateCore_Invalid(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		opts   db.CreateOptions
+		format string
+	}{
+		{"", db.CreateOptions{Engine: db.EnginePostgres}, "text"},
+		{"mydb", db.CreateOptions{Engine: "oracle"}, "text"},
+		{"mydb", db.CreateOptions{Engine: db.EnginePostgres}, "yaml"},
+		{"mydb", db.CreateOptions{Version: "latest"}, "text"},
+		{"mydb", db.CreateOptions{Tier: "huge"}, "text"},
+		{"mydb", db.CreateOptions{Region: "EU West"}, "text"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := runDbCreateCore(context.Background(), &stdout, &stderr, "http://unused", "tok", c.name, c.opts, c.format); code != 1 {
+			t.Errorf("%+v: exit %d, want 1", c, code)
+		}
+	}
+}
+
+func TestRunDbDeleteCore_JSON(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, db.DeleteResponse{Status: "success", Message: "Database 'mydb' deleted"})
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", false, false, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var got db.DeleteResponse
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || got.Status != "success" {
+		t.Errorf("stdout = %q (%v)", stdout.String(), err)
+	}
+}
+
+func TestRunDbDeleteCore_ErrorToStderr(t *testing.T) {
+	srv := newRestartServer(t, http.StatusNotFound, map[string]any{
+		"status": "error", "error": map[string]any{"code": "NOT_FOUND", "message": "database not found"},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", false, true, "text"); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if stdout.Len() != 0 || !strings.Contains(stderr.String(), "database not found") {
+		t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestRunDbCreateCore_InstanceOptions(t *testing.T) {
+	var sent map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(http.StatusCreated)
+		// The server fills in the tier default but doesn't echo the region.
+		json.NewEncoder(w).Encode(db.DatabaseCreateResponse{
+			Status: "success", Message: "Database created", Database: "mydb", Engine: "postgres", EngineVersion: "16", Tier: "small",
+		})
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	opts := db.CreateOptions{Engine: db.EnginePostgres, Version: "16", Region: "eu-west-1"}
+	if code := runDbCreateCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", opts, "text"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	want := map[string]string{"name": "mydb", "engine": "postgres", "engine_version": "16", "region": "eu-west-1"}
+	if !maps.Equal(sent, want) {
+		t.Errorf("sent %v, want %v", sent, want)
+	}
+	for _, line := range []string{"Engine:   postgres 16", "Tier:     small", "Region:   eu-west-1"} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("output missing %q:\n%s", line, stdout.String())
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+func richDBListServer(t *testing.T) string {
+	return newRestartServer(t, http.StatusOK, map[string]any{
+		"databases": []map[string]any{
+			{"name": "shop", "status": "ready", "engine_version": "PostgreSQL 16.2", "size_bytes": 52428800, "created_at": "2026-01-02T03:04:05Z"},
+			{"name": "blog"},
+		},
+		"total": 2,
+	}).URL
+}
+
+func TestRunDbListCore_Table(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runDbListCore(context.Background(), &stdout, &stderr, richDBListServer(t), "tok", false, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"NAME", "ENGINE", "shop", "ready", "PostgreSQL 16.2", "50.0 MiB", "blog"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDbListCore_QuietAndJSON(t *testing.T) {
+	url := richDBListServer(t)
+	var stdout, stderr bytes.Buffer
+	if code := runDbListCore(context.Background(), &stdout, &stderr, url, "tok", true, "table"); code != 0 {
+		t.Fatalf("exit %d", code)
+	}
+	if stdout.String() != "shop\nblog\n" {
+		t.Errorf("quiet output = %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runDbListCore(context.Background(), &stdout, &stderr, url, "tok", false, "json"); code != 0 {
+		t.Fatalf("exit %d", code)
+	}
+	var list db.DatabasesListResponse
+	if err := json.Unmarshal(stdout.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Databases) != 2 || list.Databases[0].SizeBytes != 52428800 {
+		t.Errorf("list = %+v", list)
+	}
+}
+
+func TestRunDbListCore_LegacyNames(t *testing.T) {
+	// Older servers list bare names.
+	url := newRestartServer(t, http.StatusOK, map[string]any{"databases": []string{"mydb"}, "total": 1}).URL
+	var stdout, stderr bytes.Buffer
+	if code := runDbListCore(context.Background(), &stdout, &stderr, url, "tok", true, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if stdout.String() != "mydb\n" {
+		t.Errorf("output = %q", stdout.String())
+	}
+}
@@ -0,0 +1,302 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var dbBackupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "Manage automatic and manual database backups",
+	Long: `List, create and restore managed backups of a database, and schedule
+automatic daily backups. Backups are kept on the platform; no dump files
+pass through your machine.
+
+Examples:
+  dibbla db backups schedule mydb --enable --time 03:00 --retention 14
+  dibbla db backups list mydb
+  dibbla db backups create mydb
+  dibbla db backups restore mydb bkp_20261014`,
+}
+
+var dbBackupsListCmd = &cobra.Command{
+	Use:   "list <name>",
+	Short: "List retained backups and the backup schedule",
+	Args:  cobra.ExactArgs(1),
+	Run:   runDbBackupsList,
+}
+
+var dbBackupsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Take a backup now",
+	Args:  cobra.ExactArgs(1),
+	Run:   runDbBackupsCreate,
+}
+
+var dbBackupsRestoreCmd = &cobra.Command{
+	Use:   "restore <name> <backup-id>",
+	Short: "Restore a database to one of its backups",
+	Long: `Restores a database to the state of one of its backups (see
+'dibbla db backups list'), replacing its current contents. The restore runs
+as a background job on the server; the command follows it like
+'dibbla db restore' does.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDbBackupsRestore,
+}
+
+var dbBackupsScheduleCmd = &cobra.Command{
+	Use:   "schedule <name>",
+	Short: "Show or change the automatic backup schedule",
+	Long: `Shows the automatic backup schedule of a database, or changes it when
+any flag is given. Backups run daily at --time (UTC) and are kept for
+--retention days.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDbBackupsSchedule,
+}
+
+var (
+	dbBackupsOutput    string
+	dbBackupsYes       bool
+	dbBackupsNoWait    bool
+	dbBackupsEnable    bool
+	dbBackupsDisable   bool
+	dbBackupsTime      string
+	dbBackupsRetention int
+)
+
+func init() {
+	dbCmd.AddCommand(dbBackupsCmd)
+	dbBackupsCmd.AddCommand(dbBackupsListCmd)
+	dbBackupsCmd.AddCommand(dbBackupsCreateCmd)
+	dbBackupsCmd.AddCommand(dbBackupsRestoreCmd)
+	dbBackupsCmd.AddCommand(dbBackupsScheduleCmd)
+	dbBackupsListCmd.Flags().StringVarP(&dbBackupsOutput, "output", "o", "table", "Output format: table or json")
+	dbBackupsRestoreCmd.Flags().BoolVarP(&dbBackupsYes, "yes", "y", false, "Skip confirmation prompt")
+	dbBackupsRestoreCmd.Flags().BoolVar(&dbBackupsNoWait, "no-wait", false, "Return once the restore has started")
+	dbBackupsScheduleCmd.Flags().BoolVar(&dbBackupsEnable, "enable", false, "Turn automatic daily backups on")
+	dbBackupsScheduleCmd.Flags().BoolVar(&dbBackupsDisable, "disable", false, "Turn automatic daily backups off")
+	dbBackupsScheduleCmd.Flags().StringVar(&dbBackupsTime, "time", "", "Daily backup time, HH:MM in UTC")
+	dbBackupsScheduleCmd.Flags().IntVar(&dbBackupsRetention, "retention", 0, "Days to keep automatic backups")
+	dbBackupsScheduleCmd.MarkFlagsMutuallyExclusive("enable", "disable")
+}
+
+func runDbBackupsList(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runDbBackupsListCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], dbBackupsOutput))
+}
+
+func runDbBackupsCreate(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runDbBackupsCreateCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0]))
+}
+
+func runDbBackupsRestore(cmd *cobra.Command, args []string) {
+	name, id := args[0], args[1]
+	if !dbBackupsYes {
+		if !askConfirm(fmt.Sprintf("Restore database '%s' to backup %s? Its current contents will be replaced.", name, id)) {
+			fmt.Println("Restore cancelled.")
+			os.Exit(0)
+		}
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	showProgress := isatty.IsTerminal(os.Stdout.Fd()) && !platform.IsCI()
+	os.Exit(runDbBackupsRestoreCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, name, id, !dbBackupsNoWait, restorePollInterval, showProgress))
+}
+
+func runDbBackupsSchedule(cmd *cobra.Command, args []string) {
+	var update db.BackupScheduleUpdate
+	flags := cmd.Flags()
+	if dbBackupsEnable || dbBackupsDisable {
+		enabled := dbBackupsEnable
+		update.Enabled = &enabled
+	}
+	if flags.Changed("time") {
+		update.Time = &dbBackupsTime
+	}
+	if flags.Changed("retention") {
+		update.RetentionDays = &dbBackupsRetention
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runDbBackupsScheduleCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], update))
+}
+
+// runDbBackupsListCore is the testable inner implementation of
+// `db backups list`. Returns the exit code.
+func runDbBackupsListCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, format string) int {
+	if format != "table" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected table or json)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+	list, err := db.ListBackups(ctx, apiURL, apiToken, name)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to list backups of '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(list); err != nil {
+			fmt.Fprintf(stderr, "%s Failed to encode JSON: %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(list.Backups) == 0 {
+		fmt.Fprintf(stdout, "No backups of '%s'.\n", name)
+	} else {
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tCREATED\tKIND\tSTATUS\tSIZE")
+		for _, b := range list.Backups {
+			size, created := "-", "-"
+			if b.SizeBytes > 0 {
+				size = formatBytes(float64(b.SizeBytes))
+			}
+			if b.CreatedAt != nil {
+				created = b.CreatedAt.UTC().Format(time.DateTime) + " UTC"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", b.ID, created, orDash(b.Kind), orDash(b.Status), size)
+		}
+		tw.Flush()
+	}
+	fmt.Fprintln(stdout)
+	printBackupSchedule(stdout, list.Schedule)
+	return 0
+}
+
+// runDbBackupsCreateCore is the testable inner implementation of
+// `db backups create`. Returns the exit code.
+func runDbBackupsCreateCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name string) int {
+	b, err := db.CreateBackup(ctx, apiURL, apiToken, name)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to back up '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	if b.Status == "completed" {
+		fmt.Fprintf(stdout, "%s Backup %s of '%s' created\n", platform.Icon("✅", "[OK]"), b.ID, name)
+	} else {
+		fmt.Fprintf(stdout, "%s Backup %s of '%s' started\n", platform.Icon("✅", "[OK]"), b.ID, name)
+		fmt.Fprintf(stdout, "   Check on it with: dibbla db backups list %s\n", name)
+	}
+	return 0
+}
+
+// runDbBackupsRestoreCore is the testable inner implementation of
+// `db backups restore`. Returns the exit code.
+func runDbBackupsRestoreCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, backupID string, wait bool, interval time.Duration, showProgress bool) int {
+	fmt.Fprintf(stdout, "%s Restoring database '%s' from backup %s...\n", platform.Icon("🌱", "[>]"), name, backupID)
+	fmt.Fprintln(stdout)
+
+	res, err := db.RestoreBackup(ctx, apiURL, apiToken, name, backupID)
+	if err != nil {
+		interrupt.Wait()
+		fmt.Fprintf(stderr, "%s Failed to restore database: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if res.JobID == "" {
+		fmt.Fprintf(stdout, "%s %s\n", platform.Icon("✅", "[OK]"), res.Message)
+		return 0
+	}
+	if !wait {
+		fmt.Fprintf(stdout, "%s Restore job %s started\n", platform.Icon("✅", "[OK]"), res.JobID)
+		fmt.Fprintf(stdout, "   Follow it with: dibbla db restore %s --job %s\n", name, res.JobID)
+		return 0
+	}
+	_, code := followRestoreJob(ctx, stdout, stderr, apiURL, apiToken, name, res.JobID, interval, showProgress)
+	return code
+}
+
+// runDbBackupsScheduleCore is the testable inner implementation of
+// `db backups schedule`. An empty update shows the current schedule.
+// Returns the exit code.
+func runDbBackupsScheduleCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name string, update db.BackupScheduleUpdate) int {
+	if update == (db.BackupScheduleUpdate{}) {
+		list, err := db.ListBackups(ctx, apiURL, apiToken, name)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s Failed to get the backup schedule of '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+			return 1
+		}
+		printBackupSchedule(stdout, list.Schedule)
+		return 0
+	}
+
+	if err := update.Validate(); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	sched, err := db.UpdateBackupSchedule(ctx, apiURL, apiToken, name, update)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to update the backup schedule of '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Backup schedule of '%s' updated\n", platform.Icon("✅", "[OK]"), name)
+	printBackupSchedule(stdout, sched)
+	return 0
+}
+
+// snapshotPollInterval is how often a safety snapshot is checked on.
+const snapshotPollInterval = 2 * time.Second
+
+// snapshotWaitLimit bounds how long a safety snapshot may take before the
+// command gives up on it. A variable so tests can shorten it.
+var snapshotWaitLimit = 30 * time.Minute
+
+// takeSafetySnapshot backs up database name before a destructive command
+// and waits for the backup to complete, so the command can be undone with
+// `db backups restore`. deleting says the database itself goes away, so
+// recovering means creating it again first. Progress goes to out, errors
+// to stderr. Returns the backup ID, or "" when the command should not go
+// ahead.
+func takeSafetySnapshot(ctx context.Context, out, stderr io.Writer, apiURL, apiToken, name string, deleting bool) string {
+	fmt.Fprintf(out, "%s Taking a safety snapshot of '%s'...\n", platform.Icon("📸", "[>]"), name)
+	b, err := db.CreateBackup(ctx, apiURL, apiToken, name)
+	if err == nil && b.Status != "completed" {
+		b, err = db.WaitBackup(ctx, apiURL, apiToken, name, b.ID, snapshotPollInterval, snapshotWaitLimit)
+	}
+	if err == nil && b.Status == "failed" {
+		err = fmt.Errorf("backup %s failed", b.ID)
+	}
+	if err != nil {
+		interrupt.Wait()
+		fmt.Fprintf(stderr, "%s Failed to take a safety snapshot of '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		fmt.Fprintln(stderr, "  Nothing was changed. Pass --no-snapshot to go ahead without one.")
+		return ""
+	}
+	if deleting {
+		fmt.Fprintf(out, "%s Snapshot %s saved; to recover, create '%s' again with the same --engine, then run: dibbla db backups restore %s %s\n", platform.Icon("✅", "[OK]"), b.ID, name, name, b.ID)
+	} else {
+		fmt.Fprintf(out, "%s Snapshot %s saved; recover with: dibbla db backups restore %s %s\n", platform.Icon("✅", "[OK]"), b.ID, name, b.ID)
+	}
+	return b.ID
+}
+
+// printBackupSchedule describes s in one line.
+func printBackupSchedule(w io.Writer, s *db.BackupSchedule) {
+	if s == nil || !s.Enabled {
+		fmt.Fprintln(w, "Automatic backups: off (enable with 'dibbla db backups schedule <name> --enable')")
+		return
+	}
+	line := "Automatic backups: daily"
+	if s.Time != "" {
+		line += " at " + s.Time + " UTC"
+	}
+	if s.RetentionDays > 0 {
+		line += fmt.Sprintf(", kept %d days", s.RetentionDays)
+	}
+	fmt.Fprintln(w, line)
+}
@@ -0,0 +1,100 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+func TestRunDbBackupsListCore(t *testing.T) {
+	created := time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/deploy/databases/mydb/backups" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(db.BackupsListResponse{
+			Database: "mydb",
+			Backups:  []db.Backup{{ID: "bkp_1", Kind: "scheduled", Status: "completed", SizeBytes: 3 << 20, CreatedAt: &created}},
+			Schedule: &db.BackupSchedule{Enabled: true, Time: "03:00", RetentionDays: 14},
+		})
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := runDbBackupsListCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"bkp_1", "2026-10-14 03:00:00 UTC", "scheduled", "3.0 MiB", "daily at 03:00 UTC, kept 14 days"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDbBackupsScheduleCore_Update(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/deploy/databases/mydb/backups/schedule" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(db.BackupSchedule{Enabled: true, Time: "02:30", RetentionDays: 7})
+	}))
+	defer srv.Close()
+
+	enabled, at := true, "02:30"
+	var stdout, stderr bytes.Buffer
+	if code := runDbBackupsScheduleCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", db.BackupScheduleUpdate{Enabled: &enabled, Time: &at}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if got["enabled"] != true || got["time"] != "02:30" {
+		t.Errorf("body = %v", got)
+	}
+	if _, ok := got["retention_days"]; ok {
+		t.Errorf("unchanged retention was sent: %v", got)
+	}
+	if !strings.Contains(stdout.String(), "daily at 02:30 UTC, kept 7 days") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunDbBackupsScheduleCore_Invalid(t *testing.T) {
+	at, days := "3am", 0
+	for _, update := range []db.BackupScheduleUpdate{{Time: &at}, {RetentionDays: &days}} {
+		var stdout, stderr bytes.Buffer
+		if code := runDbBackupsScheduleCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "mydb", update); code != 1 {
+			t.Errorf("exit %d for %+v, want 1", code, update)
+		}
+	}
+}
+
+func TestRunDbBackupsRestoreCore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/deploy/databases/mydb/backups/bkp_1/restore":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(db.DatabaseRestoreResponse{Status: "accepted", JobID: "rst_9"})
+		case "/api/deploy/databases/mydb/restore/rst_9":
+			json.NewEncoder(w).Encode(db.RestoreJob{ID: "rst_9", Status: db.RestoreSucceeded, RowsRestored: 42})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := runDbBackupsRestoreCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", "bkp_1", true, time.Millisecond, false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Restored database 'mydb' (42 rows)") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
@@ -0,0 +1,92 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/spinner"
+	"github.com/spf13/cobra"
+)
+
+var dbCloneCmd = &cobra.Command{
+	Use:   "clone <source> <target>",
+	Short: "Copy a database into a new one",
+	Long: `Creates a new managed database holding a copy of source, schema and data.
+The copy is made on the server, so nothing is downloaded.
+
+Like 'db create', the new database gets its own DATABASE_URL secret;
+--deployment scopes both to one deployment.
+
+Examples:
+  dibbla db clone prod-db staging-db
+  dibbla db clone prod-db staging-db --deployment shop-staging`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDbClone,
+}
+
+var dbCloneDeployment string
+
+func init() {
+	dbCmd.AddCommand(dbCloneCmd)
+	dbCloneCmd.Flags().StringVar(&dbCloneDeployment, "deployment", "", "Scope the new database and its DATABASE_URL secret to a specific deployment")
+}
+
+func runDbClone(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	host, port, sslmode := dbProxyEndpoint(cfg.APIURL, cfg.APIToken, os.Getenv)
+	proxy := dbProxyInfo{Host: host, Port: port, SSLMode: sslmode}
+	req := db.CloneRequest{Name: args[1], DeploymentAlias: dbCloneDeployment}
+	os.Exit(runDbCloneCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], req, proxy, true))
+}
+
+// runDbCloneCore is the testable inner implementation of `db clone`. It
+// prints the new database's connection details (password masked) once the
+// copy is done; spin shows a spinner meanwhile. Returns the exit code.
+func runDbCloneCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, source string, req db.CloneRequest, proxy dbProxyInfo, spin bool) int {
+	if source == req.Name {
+		fmt.Fprintf(stderr, "%s Source and target are both '%s'\n", platform.Icon("❌", "[X]"), source)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Cloning database '%s' to '%s'...\n", platform.Icon("🌱", "[>]"), source, req.Name)
+	fmt.Fprintln(stdout)
+
+	stop := func() {}
+	if spin {
+		stop = spinner.Start("Cloning", "")
+	}
+	out, err := db.CloneDatabase(ctx, apiURL, apiToken, source, req)
+	stop()
+	if err != nil {
+		interrupt.Wait()
+		fmt.Fprintf(stderr, "%s Failed to clone database '%s': %v\n", platform.Icon("❌", "[X]"), source, err)
+		return 1
+	}
+
+	name := out.Database
+	if name == "" {
+		name = req.Name
+	}
+	msg := out.Message
+	if msg == "" {
+		msg = fmt.Sprintf("Cloned '%s' to '%s'", source, name)
+	}
+	if out.SizeBytes > 0 {
+		msg += " (" + formatBytes(float64(out.SizeBytes)) + ")"
+	}
+	fmt.Fprintf(stdout, "%s %s\n", platform.Icon("✅", "[OK]"), msg)
+	if out.SecretName != "" {
+		fmt.Fprintf(stdout, "  Secret:   %s (auto-created)\n", out.SecretName)
+	}
+	fmt.Fprintln(stdout)
+	engine := db.Database{Engine: out.Engine}.EngineOrDefault()
+	printDbInfo(stdout, newDbInfo(name, engine, proxy.forEngine(engine), maskedPassword))
+	fmt.Fprintln(stdout)
+	fmt.Fprintf(stdout, "Connect with 'dibbla db connect %s'; the password is your API token.\n", name)
+	return 0
+}
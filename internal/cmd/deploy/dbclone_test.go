@@ -0,0 +1,67 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+func TestRunDbCloneCore(t *testing.T) {
+	var got db.CloneRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/deploy/databases/prod/clone" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(db.CloneResponse{Status: "success", Database: "staging", Source: "prod", SecretName: "STAGING_DATABASE_URL", SizeBytes: 2048})
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	req := db.CloneRequest{Name: "staging", DeploymentAlias: "shop-staging"}
+	if code := runDbCloneCore(context.Background(), &stdout, &stderr, srv.URL, "secret-token", "prod", req, testProxy, false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if got != req {
+		t.Errorf("request = %+v, want %+v", got, req)
+	}
+	out := stdout.String()
+	for _, want := range []string{"Cloned 'prod' to 'staging' (2.0 KiB)", "STAGING_DATABASE_URL", "Database: staging", "postgres://dibbla:" + maskedPassword + "@"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "secret-token") {
+		t.Error("output leaks the API token")
+	}
+}
+
+func TestRunDbCloneCore_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"status":"error","error":{"code":"DATABASE_EXISTS","message":"database staging already exists"}}`))
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := runDbCloneCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "prod", db.CloneRequest{Name: "staging"}, testProxy, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "already exists") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunDbCloneCore_SameName(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runDbCloneCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "prod", db.CloneRequest{Name: "prod"}, testProxy, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+}
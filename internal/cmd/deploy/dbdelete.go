@@ -0,0 +1,126 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// dbDeleteResult is the outcome of deleting one database with
+// `db delete --match`.
+type dbDeleteResult struct {
+	Database string `json:"database"`
+	Deleted  bool   `json:"deleted"`
+	Snapshot string `json:"snapshot,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// matchDatabases returns the names in list that match the glob pattern.
+func matchDatabases(list *db.DatabasesListResponse, pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid --match pattern %q: %w", pattern, err)
+	}
+	var names []string
+	for _, d := range list.Databases {
+		if ok, _ := path.Match(pattern, d.Name); ok {
+			names = append(names, d.Name)
+		}
+	}
+	return names, nil
+}
+
+// runDbDeleteMatchCore is the testable inner implementation of
+// `db delete --match`: it deletes every database matching pattern after
+// one confirmation (nil confirm skips it), each after a safety snapshot
+// when snapshot is set, and reports each result. quiet prints only
+// failures. Returns the exit code, 1 if any delete failed.
+func runDbDeleteMatchCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, pattern string, confirm func(string) bool, snapshot, quiet bool, format string) int {
+	if err := validateDbOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	list, err := db.ListDatabases(ctx, apiURL, apiToken)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to list databases: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	names, err := matchDatabases(list, pattern)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	human := !quiet && format == "text"
+	if len(names) == 0 {
+		if format == "json" {
+			return writeDbJSON(stdout, stderr, []dbDeleteResult{})
+		}
+		if human {
+			fmt.Fprintf(stdout, "No databases match '%s'.\n", pattern)
+		}
+		return 0
+	}
+
+	// The confirmation and snapshot IDs stay visible even when stdout is
+	// quiet or JSON.
+	msg := stdout
+	if !human {
+		msg = stderr
+	}
+	if confirm != nil {
+		fmt.Fprintf(msg, "%d database(s) match '%s':\n", len(names), pattern)
+		for _, n := range names {
+			fmt.Fprintf(msg, "  %s\n", n)
+		}
+		fmt.Fprintln(msg)
+		if !confirm(fmt.Sprintf("Delete these %d databases? This action cannot be undone.", len(names))) {
+			fmt.Fprintln(msg, "Deletion cancelled.")
+			return 0
+		}
+	}
+
+	results := make([]dbDeleteResult, 0, len(names))
+	failed := 0
+	for _, n := range names {
+		res := dbDeleteResult{Database: n}
+		if snapshot {
+			if res.Snapshot = takeSafetySnapshot(ctx, msg, stderr, apiURL, apiToken, n, true); res.Snapshot == "" {
+				res.Error = "safety snapshot failed; not deleted"
+				failed++
+				results = append(results, res)
+				continue
+			}
+		}
+		del, err := db.DeleteDatabase(ctx, apiURL, apiToken, n)
+		if err != nil {
+			res.Error = err.Error()
+			failed++
+			if format == "text" {
+				fmt.Fprintf(stderr, "%s %s: %v\n", platform.Icon("❌", "[X]"), n, err)
+			}
+		} else {
+			res.Deleted, res.Message = true, del.Message
+			if human {
+				fmt.Fprintf(stdout, "%s %s deleted\n", platform.Icon("✅", "[OK]"), n)
+			}
+		}
+		results = append(results, res)
+	}
+
+	if format == "json" {
+		if writeDbJSON(stdout, stderr, results) != 0 {
+			return 1
+		}
+	} else if human {
+		fmt.Fprintln(stdout)
+		fmt.Fprintf(stdout, "Deleted %d of %d database(s).\n", len(names)-failed, len(names))
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
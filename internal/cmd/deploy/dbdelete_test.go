@@ -0,0 +1,200 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+// deleteMatchServer lists test-1, test-2, prod and ci-3 and deletes any of
+// them except test-2, which fails. Deleted names are recorded.
+func deleteMatchServer(t *testing.T) (url string, deleted *[]string) {
+	deleted = new([]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]any{"databases": []string{"test-1", "test-2", "prod", "ci-3"}})
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/api/deploy/databases/")
+		if name == "test-2" {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]any{"status": "error", "error": map[string]any{"code": "IN_USE", "message": "database has open connections"}})
+			return
+		}
+		*deleted = append(*deleted, name)
+		json.NewEncoder(w).Encode(map[string]any{"status": "success", "message": "Database '" + name + "' deleted"})
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, deleted
+}
+
+func TestRunDbDeleteMatchCore(t *testing.T) {
+	url, deleted := deleteMatchServer(t)
+	var asked string
+	confirm := func(msg string) bool { asked = msg; return true }
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteMatchCore(context.Background(), &stdout, &stderr, url, "tok", "test-*", confirm, false, false, "text"); code != 1 {
+		t.Fatalf("exit %d, want 1 for the failed delete", code)
+	}
+	if strings.Join(*deleted, ",") != "test-1" {
+		t.Errorf("deleted = %q", *deleted)
+	}
+	out := stdout.String()
+	for _, want := range []string{"2 database(s) match 'test-*'", "  test-1\n  test-2\n", "test-1 deleted", "Deleted 1 of 2 database(s)."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("stdout missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(asked, "Delete these 2 databases?") {
+		t.Errorf("confirmation = %q", asked)
+	}
+	if !strings.Contains(stderr.String(), "test-2: IN_USE: database has open connections") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunDbDeleteMatchCore_Cancelled(t *testing.T) {
+	url, deleted := deleteMatchServer(t)
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteMatchCore(context.Background(), &stdout, &stderr, url, "tok", "*", func(string) bool { return false }, false, false, "text"); code != 0 {
+		t.Fatalf("exit %d", code)
+	}
+	if len(*deleted) != 0 || !strings.Contains(stdout.String(), "Deletion cancelled.") {
+		t.Errorf("deleted = %q, stdout = %q", *deleted, stdout.String())
+	}
+}
+
+func TestRunDbDeleteMatchCore_JSON(t *testing.T) {
+	url, _ := deleteMatchServer(t)
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteMatchCore(context.Background(), &stdout, &stderr, url, "tok", "ci-?", nil, false, false, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var results []dbDeleteResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout.String())
+	}
+	if len(results) != 1 || results[0].Database != "ci-3" || !results[0].Deleted {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestRunDbDeleteMatchCore_JSONConfirmsOnStderr(t *testing.T) {
+	url, _ := deleteMatchServer(t)
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteMatchCore(context.Background(), &stdout, &stderr, url, "tok", "ci-?", func(string) bool { return true }, false, false, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var results []dbDeleteResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "1 database(s) match 'ci-?'") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestDbDeleteArgs_EmptyMatch(t *testing.T) {
+	t.Cleanup(func() {
+		dbDeleteMatch = ""
+		dbDeleteCmd.Flags().Lookup("match").Changed = false
+	})
+	if err := dbDeleteCmd.Flags().Set("match", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbDeleteCmd.Args(dbDeleteCmd, nil); err == nil || !strings.Contains(err.Error(), "--match needs a pattern") {
+		t.Errorf("err = %v", err)
+	}
+	if err := dbDeleteCmd.Args(dbDeleteCmd, []string{"mydb"}); err == nil {
+		t.Error("expected an error for a name together with --match")
+	}
+}
+
+func TestRunDbDeleteMatchCore_BadPattern(t *testing.T) {
+	url, _ := deleteMatchServer(t)
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteMatchCore(context.Background(), &stdout, &stderr, url, "tok", "test-[", nil, false, false, "text"); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "invalid --match pattern") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+// snapshotServer backs mydb up (pending, then listed with status) and
+// deletes it. Calls are recorded in order.
+func snapshotServer(t *testing.T, status string) (url string, calls *[]string) {
+	calls = new([]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls = append(*calls, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/deploy/databases":
+			json.NewEncoder(w).Encode(map[string]any{"databases": []string{"mydb"}, "total": 1})
+		case "POST /api/deploy/databases/mydb/backups":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(db.Backup{ID: "bkp_9", Status: "pending"})
+		case "GET /api/deploy/databases/mydb/backups":
+			json.NewEncoder(w).Encode(db.BackupsListResponse{Backups: []db.Backup{{ID: "bkp_8", Status: "completed"}, {ID: "bkp_9", Status: status}}})
+		case "DELETE /api/deploy/databases/mydb":
+			json.NewEncoder(w).Encode(db.DeleteResponse{Status: "success", Message: "Database 'mydb' deleted"})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, calls
+}
+
+func TestRunDbDeleteCore_SnapshotFirst(t *testing.T) {
+	url, calls := snapshotServer(t, "completed")
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", true, false, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if (*calls)[len(*calls)-1] != "DELETE /api/deploy/databases/mydb" {
+		t.Errorf("calls = %q", *calls)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || got["snapshot"] != "bkp_9" || got["status"] != "success" {
+		t.Errorf("stdout = %q (%v)", stdout.String(), err)
+	}
+	if !strings.Contains(stderr.String(), "create 'mydb' again") || !strings.Contains(stderr.String(), "dibbla db backups restore mydb bkp_9") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunDbDeleteCore_FailedSnapshotKeepsDatabase(t *testing.T) {
+	url, calls := snapshotServer(t, "failed")
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", true, true, "text"); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	for _, c := range *calls {
+		if strings.HasPrefix(c, "DELETE") {
+			t.Errorf("deleted despite the failed snapshot: %q", *calls)
+		}
+	}
+	if !strings.Contains(stderr.String(), "backup bkp_9 failed") || !strings.Contains(stderr.String(), "--no-snapshot") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunDbDeleteCore_UnknownSnapshotStatusKeepsDatabase(t *testing.T) {
+	url, calls := snapshotServer(t, "archiving")
+	var stdout, stderr bytes.Buffer
+	if code := runDbDeleteCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", true, true, "text"); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if (*calls)[len(*calls)-1] == "DELETE /api/deploy/databases/mydb" {
+		t.Errorf("deleted despite the unknown snapshot status: %q", *calls)
+	}
+	if !strings.Contains(stderr.String(), `unknown status "archiving"`) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
@@ -0,0 +1,148 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// dumpResult is the summary `db dump --json` prints once a dump is saved.
+type dumpResult struct {
+	Database  string `json:"database"`
+	Format    string `json:"format"`
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// runDbDumpCore downloads database name in the given dump format to
+// outPath ("-" for stdout). A file
+// is written to a temp file in the same directory, synced and renamed into
+// place, so it only ever appears complete. Messages, and the progress line
+// when showProgress, go to msg; jsonOut also prints a dumpResult on stdout.
+// Returns the exit code.
+func runDbDumpCore(ctx context.Context, stdout, msg io.Writer, apiURL, apiToken, name, format, outPath string, showProgress, jsonOut bool) int {
+	var progress func(written, total int64)
+	var clear func()
+	if showProgress {
+		progress, clear = dumpProgress(msg)
+	}
+	// Resume notices go on their own line, above the progress line.
+	notice := writerFunc(func(b []byte) (int, error) {
+		if clear != nil {
+			clear()
+		}
+		fmt.Fprintf(msg, "%s %s", platform.Icon("⚠️", "[!]"), b)
+		return len(b), nil
+	})
+
+	if outPath == "-" {
+		err := db.DumpDatabase(ctx, apiURL, apiToken, name, format, stdout, progress, notice)
+		if clear != nil {
+			clear()
+		}
+		if err != nil {
+			interrupt.Wait()
+			fmt.Fprintf(msg, "%s Failed to dump database: %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(msg, "%s Dumping database '%s' to %s...\n", platform.Icon("🌱", "[>]"), name, outPath)
+	fmt.Fprintln(msg)
+
+	f, err := os.CreateTemp(filepath.Dir(outPath), "."+filepath.Base(outPath)+".*.partial")
+	if err != nil {
+		fmt.Fprintf(msg, "%s Failed to create output file: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	tmp := f.Name()
+	// A dump cut short by Ctrl-C is useless; don't leave it behind.
+	removePartial := interrupt.OnCleanup(func() {
+		f.Close()
+		os.Remove(tmp)
+	})
+	defer removePartial()
+
+	err = db.DumpDatabase(ctx, apiURL, apiToken, name, format, f, progress, notice)
+	if clear != nil {
+		clear()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, outPath)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		interrupt.Wait()
+		fmt.Fprintf(msg, "%s Failed to dump database: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+
+	abs, _ := filepath.Abs(outPath)
+	res := dumpResult{Database: name, Format: format, Path: abs}
+	size := ""
+	if fi, err := os.Stat(outPath); err == nil {
+		res.SizeBytes = fi.Size()
+		size = " (" + formatBytes(float64(fi.Size())) + ")"
+	}
+	fmt.Fprintf(msg, "%s Dump saved to %s%s\n", platform.Icon("✅", "[OK]"), abs, size)
+	if jsonOut {
+		return writeDbJSON(stdout, msg, res)
+	}
+	return 0
+}
+
+// dumpProgress returns a progress callback that redraws one status line on
+// w at most every 100ms, with the average transfer speed, and a func that
+// erases the line.
+func dumpProgress(w io.Writer) (progress func(written, total int64), clear func()) {
+	var mu sync.Mutex
+	var last time.Time
+	start := time.Now()
+	drawn := false
+	progress = func(written, total int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		if time.Since(last) < 100*time.Millisecond && written != total {
+			return
+		}
+		last = time.Now()
+		line := "Downloading " + formatBytes(float64(written))
+		if total > 0 {
+			line += fmt.Sprintf(" of %s (%d%%)", formatBytes(float64(total)), written*100/total)
+		}
+		if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+			line += " at " + formatBytes(float64(written)/elapsed) + "/s"
+		}
+		fmt.Fprintf(w, "\r\033[K%s", line)
+		drawn = true
+	}
+	clear = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if drawn {
+			fmt.Fprint(w, "\r\033[K")
+			drawn = false
+		}
+	}
+	return progress, clear
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(b []byte) (int, error) { return f(b) }
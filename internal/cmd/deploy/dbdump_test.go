@@ -0,0 +1,172 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+func dumpServer(t *testing.T, status int, body string) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/deploy/databases/mydb/dump" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		// Echo the format so tests can check it was forwarded.
+		if f := r.URL.Query().Get("format"); f != "custom" {
+			body = f + ":" + body
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestRunDbDumpCore_File(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "mydb.dump")
+	var stdout, msg bytes.Buffer
+	if code := runDbDumpCore(context.Background(), &stdout, &msg, dumpServer(t, 200, "PGDMP-data"), "tok", "mydb", "custom", out, true, false); code != 0 {
+		t.Fatalf("exit %d (msg=%q)", code, msg.String())
+	}
+	got, err := os.ReadFile(out)
+	if err != nil || string(got) != "PGDMP-data" {
+		t.Fatalf("file = %q, %v", got, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("leftover files: %v", entries)
+	}
+	if !strings.Contains(msg.String(), "Downloading 10 B of 10 B (100%)") || !strings.Contains(msg.String(), "Dump saved to") {
+		t.Errorf("msg = %q", msg.String())
+	}
+}
+
+func TestRunDbDumpCore_FailureKeepsPreviousDump(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "mydb.dump")
+	os.WriteFile(out, []byte("old dump"), 0o644)
+	var stdout, msg bytes.Buffer
+	url := dumpServer(t, 500, `{"status":"error","error":{"code":"DUMP_FAILED","message":"pg_dump exited 1"}}`)
+	if code := runDbDumpCore(context.Background(), &stdout, &msg, url, "tok", "mydb", "custom", out, false, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if got, _ := os.ReadFile(out); string(got) != "old dump" {
+		t.Errorf("previous dump overwritten: %q", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("partial file left behind: %v", entries)
+	}
+	if !strings.Contains(msg.String(), "DUMP_FAILED") {
+		t.Errorf("msg = %q", msg.String())
+	}
+}
+
+func TestRunDbDumpCore_Stdout(t *testing.T) {
+	var stdout, msg bytes.Buffer
+	if code := runDbDumpCore(context.Background(), &stdout, &msg, dumpServer(t, 200, "PGDMP-data"), "tok", "mydb", "custom", "-", false, false); code != 0 {
+		t.Fatalf("exit %d (msg=%q)", code, msg.String())
+	}
+	if stdout.String() != "PGDMP-data" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+	if msg.Len() != 0 {
+		t.Errorf("unexpected messages: %q", msg.String())
+	}
+}
+
+func TestRunDbDumpCore_ForwardsFormat(t *testing.T) {
+	var stdout, msg bytes.Buffer
+	if code := runDbDumpCore(context.Background(), &stdout, &msg, dumpServer(t, 200, "SQL"), "tok", "mydb", "plain", "-", false, false); code != 0 {
+		t.Fatalf("exit %d (msg=%q)", code, msg.String())
+	}
+	if stdout.String() != "plain:SQL" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunDbDumpCore_JSON(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "mydb.sql")
+	var stdout, msg bytes.Buffer
+	if code := runDbDumpCore(context.Background(), &stdout, &msg, dumpServer(t, 200, "SQL"), "tok", "mydb", "plain", out, false, true); code != 0 {
+		t.Fatalf("exit %d (msg=%q)", code, msg.String())
+	}
+	var res dumpResult
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout.String())
+	}
+	if res.Database != "mydb" || res.Format != "plain" || res.Path != out || res.SizeBytes != int64(len("plain:SQL")) {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestRunDbDumpCore_ResumesBrokenDownload(t *testing.T) {
+	const dump = "PGDMP-0123456789"
+	sum := sha256.Sum256([]byte(dump))
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range")+"|"+r.Header.Get("If-Range"))
+		w.Header().Set("ETag", `"d1"`)
+		w.Header().Set(db.DumpChecksumHeader, hex.EncodeToString(sum[:]))
+		if r.Header.Get("Range") == "" {
+			// Send the first 6 bytes, then drop the connection.
+			w.Header().Set("Content-Length", strconv.Itoa(len(dump)))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(dump[:6]))
+			w.(http.Flusher).Flush()
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 6-%d/%d", len(dump)-1, len(dump)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte(dump[6:]))
+	}))
+	t.Cleanup(srv.Close)
+
+	out := filepath.Join(t.TempDir(), "mydb.dump")
+	var stdout, msg bytes.Buffer
+	if code := runDbDumpCore(context.Background(), &stdout, &msg, srv.URL, "tok", "mydb", "custom", out, false, false); code != 0 {
+		t.Fatalf("exit %d (msg=%q)", code, msg.String())
+	}
+	if got, _ := os.ReadFile(out); string(got) != dump {
+		t.Errorf("file = %q", got)
+	}
+	if want := []string{"|", `bytes=6-|"d1"`}; strings.Join(ranges, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %q, want %q", ranges, want)
+	}
+	if !strings.Contains(msg.String(), "Download broke after 6 bytes") {
+		t.Errorf("msg = %q", msg.String())
+	}
+}
+
+func TestRunDbDumpCore_ChecksumMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(db.DumpChecksumHeader, strings.Repeat("0", 64))
+		w.Write([]byte("PGDMP-data"))
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	var stdout, msg bytes.Buffer
+	if code := runDbDumpCore(context.Background(), &stdout, &msg, srv.URL, "tok", "mydb", "custom", filepath.Join(dir, "mydb.dump"), false, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("corrupt dump left behind: %v", entries)
+	}
+	if !strings.Contains(msg.String(), "dump checksum mismatch") {
+		t.Errorf("msg = %q", msg.String())
+	}
+}
@@ -0,0 +1,173 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// dbEngineProxyPorts are the database proxy's ports for engines other than
+// Postgres, which uses the port dbProxyEndpoint resolves.
+var dbEngineProxyPorts = map[string]string{
+	db.EngineMySQL: "30306",
+	db.EngineRedis: "30379",
+}
+
+// dbLocalPorts are the ports engines' clients default to, which `db proxy`
+// listens on unless told otherwise.
+var dbLocalPorts = map[string]int{
+	db.EnginePostgres: 5432,
+	db.EngineMySQL:    3306,
+	db.EngineRedis:    6379,
+}
+
+// forEngine returns the proxy endpoint for databases of engine.
+func (p dbProxyInfo) forEngine(engine string) dbProxyInfo {
+	if port, ok := dbEngineProxyPorts[engine]; ok {
+		p.Port = port
+	}
+	return p
+}
+
+// dbUser is the user name the proxy expects for database name. Redis has
+// no database names to route by, so the proxy takes the name as the user.
+func dbUser(engine, name string) string {
+	if engine == db.EngineRedis {
+		return name
+	}
+	return dbProxyUser
+}
+
+// dbConnectionString is the connection URL for database name of engine
+// behind the proxy at host:port. sslmode is Postgres's; other engines
+// only distinguish "disable" from everything else. An empty password is
+// left out of the URL.
+func dbConnectionString(engine, password, host, port, name, sslmode string) string {
+	return dbLoginString(engine, dbUser(engine, name), password, host, port, name, sslmode)
+}
+
+// dbLoginString is dbConnectionString for a login other than the proxy's
+// default user, such as one minted by db.CreateCredential.
+func dbLoginString(engine, user, password, host, port, name, sslmode string) string {
+	if password != "" {
+		user += ":" + password
+	}
+	switch engine {
+	case db.EngineMySQL:
+		mode := "REQUIRED"
+		if sslmode == "disable" {
+			mode = "DISABLED"
+		}
+		return fmt.Sprintf("mysql://%s@%s:%s/%s?ssl-mode=%s", user, host, port, name, mode)
+	case db.EngineRedis:
+		scheme := "rediss"
+		if sslmode == "disable" {
+			scheme = "redis"
+		}
+		return fmt.Sprintf("%s://%s@%s:%s", scheme, user, host, port)
+	default:
+		return fmt.Sprintf("postgres://%s@%s:%s/%s?sslmode=%s", user, host, port, name, sslmode)
+	}
+}
+
+// dbEnvVar is the environment variable apps conventionally read an
+// engine's connection URL from.
+func dbEnvVar(engine string) string {
+	if engine == db.EngineRedis {
+		return "REDIS_URL"
+	}
+	return "DATABASE_URL"
+}
+
+// dbClient is the command-line client `db connect` launches for engine:
+// the program name and the arguments that precede the connection string.
+func dbClient(engine string) (name string, args []string) {
+	switch engine {
+	case db.EngineMySQL:
+		return "mysqlsh", []string{"--sql", "--uri"}
+	case db.EngineRedis:
+		return "redis-cli", []string{"-u"}
+	default:
+		return "psql", nil
+	}
+}
+
+// dbHasSQL reports whether engine speaks SQL, i.e. whether the built-in
+// shell of db connect and the commands that check requireSQLDatabase work
+// with it.
+func dbHasSQL(engine string) bool {
+	return engine != db.EngineRedis
+}
+
+// checkSQLDatabase returns the engine of database name, or an error when
+// it doesn't speak SQL and so can't run command.
+func checkSQLDatabase(ctx context.Context, apiURL, apiToken, name, command string) (string, error) {
+	engine, err := lookupDatabaseEngine(ctx, apiURL, apiToken, name)
+	if err != nil {
+		return "", err
+	}
+	if !dbHasSQL(engine) {
+		return "", fmt.Errorf("%s runs SQL; '%s' is a %s database", command, name, engine)
+	}
+	return engine, nil
+}
+
+// requireSQLDatabase is checkSQLDatabase for the SQL-only commands (db
+// query, db seed, db reset, db stats): it returns the engine, exiting
+// before they send SQL the database can't run. db migrate has
+// requireMigrateDatabase.
+func requireSQLDatabase(ctx context.Context, cfg *config.Config, name, command string) string {
+	engine, err := checkSQLDatabase(ctx, cfg.APIURL, cfg.APIToken, name, command)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	return engine
+}
+
+// lookupDatabaseEngine returns the engine of database name. Guessing on a
+// failed lookup would pick the wrong client, dump format or env var for
+// non-Postgres databases, so errors are returned instead.
+func lookupDatabaseEngine(ctx context.Context, apiURL, apiToken, name string) (string, error) {
+	list, err := db.ListDatabases(ctx, apiURL, apiToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up database '%s': %w", name, err)
+	}
+	d, ok := list.Get(name)
+	if !ok {
+		return "", fmt.Errorf("database '%s' not found", name)
+	}
+	return d.EngineOrDefault(), nil
+}
+
+// dbClientCommand is how `db connect` launches engine's client for
+// database name behind the proxy at host:port: the program, its arguments
+// and the environment variable the client reads the password from. The
+// password stays out of the arguments, where ps would show it.
+func dbClientCommand(engine, host, port, name, sslmode string) (prog string, args []string, passwordEnv string) {
+	switch engine {
+	case db.EngineMySQL:
+		// mysqlsh ignores MYSQL_PWD, so the classic client is launched.
+		mode := "REQUIRED"
+		if sslmode == "disable" {
+			mode = "DISABLED"
+		}
+		return "mysql", []string{"--host=" + host, "--port=" + port, "--user=" + dbUser(engine, name), "--ssl-mode=" + mode, name}, "MYSQL_PWD"
+	case db.EngineRedis:
+		return "redis-cli", []string{"-u", dbConnectionString(engine, "", host, port, name, sslmode)}, "REDISCLI_AUTH"
+	default:
+		return "psql", []string{dbConnectionString(engine, "", host, port, name, sslmode)}, "PGPASSWORD"
+	}
+}
+
+// dbQuickConnect is the shell one-liner that connects engine's client to
+// database name.
+func dbQuickConnect(engine, name string) string {
+	client, args := dbClient(engine)
+	return strings.Join(append([]string{client}, args...), " ") + " $(dibbla db connect " + name + " -q)"
+}
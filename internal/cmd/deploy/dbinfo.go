@@ -0,0 +1,151 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var dbInfoCmd = &cobra.Command{
+	Use:   "info <name>",
+	Short: "Show connection details for a database",
+	Long: `Shows host, port, database, user and a ready-to-paste connection string
+for a managed database, via the Dibbla database proxy (resolved as for
+'dibbla db connect'). The password, your API token, is masked unless
+--show-password is given.
+
+--env-format prints a single DATABASE_URL=... line, password included, for
+appending to a .env file.
+
+Examples:
+  dibbla db info mydb
+  dibbla db info mydb --output json --show-password
+  dibbla db info mydb --env-format >> .env`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDbInfo,
+}
+
+var (
+	dbInfoShowPassword bool
+	dbInfoOutput       string
+	dbInfoEnvFormat    bool
+)
+
+func init() {
+	dbCmd.AddCommand(dbInfoCmd)
+	dbInfoCmd.Flags().BoolVar(&dbInfoShowPassword, "show-password", false, "Show the password instead of masking it")
+	dbInfoCmd.Flags().StringVarP(&dbInfoOutput, "output", "o", "text", "Output format: text or json")
+	dbInfoCmd.Flags().BoolVar(&dbInfoEnvFormat, "env-format", false, "Print DATABASE_URL=<connection string> (password included) for .env files")
+	dbInfoCmd.MarkFlagsMutuallyExclusive("output", "env-format")
+}
+
+// dbInfo is the connection detail of a database, as printed by `db info`.
+type dbInfo struct {
+	Database         string `json:"database"`
+	Engine           string `json:"engine"`
+	Host             string `json:"host"`
+	Port             string `json:"port"`
+	User             string `json:"user"`
+	Password         string `json:"password"`
+	SSLMode          string `json:"sslmode"`
+	ConnectionString string `json:"connection_string"`
+}
+
+// dbProxyUser is the user name the database proxy expects; the password is
+// the caller's API token.
+const dbProxyUser = "dibbla"
+
+// maskedPassword stands in for the API token when it isn't shown.
+const maskedPassword = "********"
+
+func runDbInfo(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	host, port, sslmode := dbProxyEndpoint(cfg.APIURL, cfg.APIToken, os.Getenv)
+	proxy := dbProxyInfo{Host: host, Port: port, SSLMode: sslmode}
+	os.Exit(runDbInfoCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], proxy, dbInfoShowPassword, dbInfoOutput, dbInfoEnvFormat))
+}
+
+// runDbInfoCore checks that database name exists and prints how to reach it
+// through proxy. Returns the exit code.
+func runDbInfoCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name string, proxy dbProxyInfo, showPassword bool, format string, envFormat bool) int {
+	if format != "text" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected text or json)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+	list, err := db.ListDatabases(ctx, apiURL, apiToken)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to look up database '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	d, ok := list.Get(name)
+	if !ok {
+		fmt.Fprintf(stderr, "%s Database '%s' not found (see 'dibbla db list')\n", platform.Icon("❌", "[X]"), name)
+		return 1
+	}
+	engine := d.EngineOrDefault()
+	proxy = proxy.forEngine(engine)
+
+	if envFormat {
+		fmt.Fprintf(stdout, "%s=%s\n", dbEnvVar(engine), dbConnectionString(engine, apiToken, proxy.Host, proxy.Port, name, proxy.SSLMode))
+		return 0
+	}
+
+	password := maskedPassword
+	if showPassword {
+		password = apiToken
+	}
+	info := newDbInfo(name, engine, proxy, password)
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(info); err != nil {
+			fmt.Fprintf(stderr, "%s Failed to encode JSON: %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "%s Database '%s'\n\n", platform.Icon("🔗", "[>]"), name)
+	printDbInfo(stdout, info)
+	if !showPassword {
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "The password is your API token; pass --show-password to print it.")
+	}
+	return 0
+}
+
+// newDbInfo is the connection detail of database name of engine behind
+// proxy (already adjusted with forEngine).
+func newDbInfo(name, engine string, proxy dbProxyInfo, password string) dbInfo {
+	return dbInfo{
+		Database:         name,
+		Engine:           engine,
+		Host:             proxy.Host,
+		Port:             proxy.Port,
+		User:             dbUser(engine, name),
+		Password:         password,
+		SSLMode:          proxy.SSLMode,
+		ConnectionString: dbConnectionString(engine, password, proxy.Host, proxy.Port, name, proxy.SSLMode),
+	}
+}
+
+// printDbInfo writes info as indented fields followed by the connection
+// string.
+func printDbInfo(w io.Writer, info dbInfo) {
+	fmt.Fprintf(w, "  Host:     %s\n", info.Host)
+	fmt.Fprintf(w, "  Port:     %s\n", info.Port)
+	fmt.Fprintf(w, "  Database: %s\n", info.Database)
+	fmt.Fprintf(w, "  Engine:   %s\n", info.Engine)
+	fmt.Fprintf(w, "  User:     %s\n", info.User)
+	fmt.Fprintf(w, "  Password: %s\n", info.Password)
+	fmt.Fprintf(w, "  SSL mode: %s\n", info.SSLMode)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", info.ConnectionString)
+}
@@ -0,0 +1,257 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var dbLinkCmd = &cobra.Command{
+	Use:   "link <name> <app-alias>",
+	Short: "Give an app its database's connection string",
+	Long: `Stores the connection string of a database (as printed by 'dibbla db info')
+on an app and restarts the app so it picks it up. Running it again updates
+the value.
+
+By default the string goes into a deployment-scoped secret named
+DATABASE_URL (REDIS_URL for Redis); --var picks another name, --service
+scopes the secret to one service and --as env stores a plain environment
+variable instead. The string carries a login minted for this app alone,
+not your API token; 'dibbla db unlink' revokes it.
+
+Examples:
+  dibbla db link mydb myapp
+  dibbla db link cache myapp --var CACHE_URL
+  dibbla db link mydb myapp --service worker
+  dibbla db link mydb myapp --as env`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDbLink,
+}
+
+var dbUnlinkCmd = &cobra.Command{
+	Use:   "unlink <name> <app-alias>",
+	Short: "Remove a database's connection string from an app",
+	Long: `Removes what 'dibbla db link' stored on an app and restarts it. Pass the
+same --var, --service and --as as when linking.
+
+Examples:
+  dibbla db unlink mydb myapp
+  dibbla db unlink cache myapp --var CACHE_URL`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDbUnlink,
+}
+
+// dbLinkOptions says where `db link` and `db unlink` keep the connection
+// string: a secret (optionally per service) or an env var named Var.
+type dbLinkOptions struct {
+	As      string
+	Var     string
+	Service string
+	Restart bool
+}
+
+var (
+	dbLinkAs        string
+	dbLinkVar       string
+	dbLinkService   string
+	dbLinkNoRestart bool
+)
+
+func init() {
+	dbCmd.AddCommand(dbLinkCmd)
+	dbCmd.AddCommand(dbUnlinkCmd)
+	for _, c := range []*cobra.Command{dbLinkCmd, dbUnlinkCmd} {
+		c.Flags().StringVar(&dbLinkAs, "as", "secret", "Store the connection string as a secret or an env var: secret or env")
+		c.Flags().StringVar(&dbLinkVar, "var", "", "Variable name (default DATABASE_URL, or REDIS_URL for Redis)")
+		c.Flags().StringVarP(&dbLinkService, "service", "s", "", "Scope the secret to a single service, and restart only that one")
+		c.Flags().BoolVar(&dbLinkNoRestart, "no-restart", false, "Don't restart the app (secrets only; env var changes always restart it)")
+	}
+}
+
+func dbLinkFlags() dbLinkOptions {
+	return dbLinkOptions{As: dbLinkAs, Var: dbLinkVar, Service: dbLinkService, Restart: !dbLinkNoRestart}
+}
+
+func runDbLink(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	host, port, sslmode := dbProxyEndpoint(cfg.APIURL, cfg.APIToken, os.Getenv)
+	proxy := dbProxyInfo{Host: host, Port: port, SSLMode: sslmode}
+	os.Exit(runDbLinkCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], args[1], dbLinkFlags(), proxy))
+}
+
+func runDbUnlink(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runDbUnlinkCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], args[1], dbLinkFlags()))
+}
+
+// validate checks the option combinations that make no sense before
+// anything is changed.
+func (o dbLinkOptions) validate() error {
+	switch o.As {
+	case "secret":
+		if o.Service != "" && !apps.ServiceNameRe.MatchString(o.Service) {
+			return fmt.Errorf("service name %q does not match %s", o.Service, apps.ServiceNameRe.String())
+		}
+	case "env":
+		if o.Service != "" {
+			return fmt.Errorf("--service only applies to --as secret; env vars are deployment-wide")
+		}
+		if !o.Restart {
+			return fmt.Errorf("--no-restart only applies to --as secret; env var changes always restart the app")
+		}
+	default:
+		return fmt.Errorf("invalid --as %q (expected secret or env)", o.As)
+	}
+	if o.Var != "" && !apps.EnvKeyRe.MatchString(o.Var) {
+		return fmt.Errorf("invalid variable name %q", o.Var)
+	}
+	return nil
+}
+
+// varFor is the variable name to use for a database of engine.
+func (o dbLinkOptions) varFor(engine string) string {
+	if o.Var != "" {
+		return o.Var
+	}
+	return dbEnvVar(engine)
+}
+
+// runDbLinkCore is the testable inner implementation of `db link`. The
+// connection string goes through proxy, like `db info` prints it.
+// Returns the exit code.
+func runDbLinkCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, alias string, opts dbLinkOptions, proxy dbProxyInfo) int {
+	if err := opts.validate(); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	list, err := db.ListDatabases(ctx, apiURL, apiToken)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to look up database '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	d, ok := list.Get(name)
+	if !ok {
+		fmt.Fprintf(stderr, "%s Database '%s' not found (see 'dibbla db list')\n", platform.Icon("❌", "[X]"), name)
+		return 1
+	}
+	engine := d.EngineOrDefault()
+	proxy = proxy.forEngine(engine)
+	key := opts.varFor(engine)
+
+	fmt.Fprintf(stdout, "%s Linking database '%s' to '%s'...\n", platform.Icon("🌱", "[>]"), name, alias)
+	fmt.Fprintln(stdout)
+
+	cred, err := db.CreateCredential(ctx, apiURL, apiToken, name, alias)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to create a login to '%s' for '%s': %v\n", platform.Icon("❌", "[X]"), name, alias, err)
+		return 1
+	}
+	conn := dbLoginString(engine, cred.Username, cred.Password, proxy.Host, proxy.Port, name, proxy.SSLMode)
+
+	if opts.As == "env" {
+		out, err := apps.PatchEnv(ctx, apiURL, apiToken, alias, apps.EnvPatch{Set: map[string]string{key: conn}})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s Failed to set %s on '%s': %v\n", platform.Icon("❌", "[X]"), key, alias, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%s Set env var %s on '%s'\n", platform.Icon("✅", "[OK]"), key, alias)
+		if out.Message != "" {
+			fmt.Fprintf(stdout, "   %s\n", out.Message)
+		}
+		return 0
+	}
+
+	if _, err := secrets.CreateSecret(ctx, apiURL, apiToken, key, conn, alias, opts.Service); err != nil {
+		fmt.Fprintf(stderr, "%s Failed to set secret %s on '%s': %v\n", platform.Icon("❌", "[X]"), key, alias, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Set secret %s (%s)\n", platform.Icon("✅", "[OK]"), key, scopeLabel(alias, opts.Service))
+	return restartLinkedApp(ctx, stdout, stderr, apiURL, apiToken, alias, opts)
+}
+
+// runDbUnlinkCore is the testable inner implementation of `db unlink`.
+// The app's login is revoked once its connection string is gone.
+// Returns the exit code.
+func runDbUnlinkCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, alias string, opts dbLinkOptions) int {
+	if err := opts.validate(); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	key := opts.Var
+	if key == "" {
+		engine, err := lookupDatabaseEngine(ctx, apiURL, apiToken, name)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		key = dbEnvVar(engine)
+	}
+
+	if opts.As == "env" {
+		out, err := apps.PatchEnv(ctx, apiURL, apiToken, alias, apps.EnvPatch{Unset: []string{key}})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s Failed to remove %s from '%s': %v\n", platform.Icon("❌", "[X]"), key, alias, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%s Removed env var %s from '%s'\n", platform.Icon("✅", "[OK]"), key, alias)
+		if out.Message != "" {
+			fmt.Fprintf(stdout, "   %s\n", out.Message)
+		}
+		revokeLinkedLogin(ctx, stdout, stderr, apiURL, apiToken, name, alias)
+		return 0
+	}
+
+	if _, err := secrets.DeleteSecret(ctx, apiURL, apiToken, key, alias, opts.Service); err != nil {
+		fmt.Fprintf(stderr, "%s Failed to delete secret %s from '%s': %v\n", platform.Icon("❌", "[X]"), key, alias, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Deleted secret %s (%s)\n", platform.Icon("✅", "[OK]"), key, scopeLabel(alias, opts.Service))
+	code := restartLinkedApp(ctx, stdout, stderr, apiURL, apiToken, alias, opts)
+	revokeLinkedLogin(ctx, stdout, stderr, apiURL, apiToken, name, alias)
+	return code
+}
+
+// revokeLinkedLogin deletes the login `db link` minted for alias. A failure
+// only warns: the connection string is already gone from the app.
+func revokeLinkedLogin(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, alias string) {
+	if err := db.RevokeCredential(ctx, apiURL, apiToken, name, alias); err != nil {
+		fmt.Fprintf(stderr, "%s Failed to revoke the login '%s' has to '%s': %v\n", platform.Icon("⚠️", "[!]"), alias, name, err)
+		return
+	}
+	fmt.Fprintf(stdout, "%s Revoked the login '%s' had to '%s'\n", platform.Icon("✅", "[OK]"), alias, name)
+}
+
+// restartLinkedApp restarts alias, or just opts.Service, so a changed
+// secret takes effect. Returns the exit code.
+func restartLinkedApp(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias string, opts dbLinkOptions) int {
+	if !opts.Restart {
+		service := opts.Service
+		if service == "" {
+			service = "<service>"
+		}
+		fmt.Fprintf(stdout, "   It takes effect when the app restarts: dibbla apps restart %s --service %s\n", alias, service)
+		return 0
+	}
+	var err error
+	if opts.Service != "" {
+		_, err = apps.RestartService(ctx, apiURL, apiToken, alias, opts.Service)
+	} else {
+		_, err = apps.RestartApp(ctx, apiURL, apiToken, alias)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to restart '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		fmt.Fprintln(stderr, "  The secret is saved; it takes effect the next time the app restarts.")
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Rolling restart of %s triggered\n", platform.Icon("✅", "[OK]"), scopeLabel(alias, opts.Service))
+	return 0
+}
@@ -0,0 +1,213 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/migrate"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage SQL schema migrations",
+	Long: `Manage a directory of timestamped SQL migrations and apply them to a
+managed database through the API.
+
+A migration is a pair of files, <version>_<name>.up.sql and
+<version>_<name>.down.sql, created by 'dibbla db migrate new'. Applied
+versions are recorded in a schema_migrations table in the database; each
+migration runs in one transaction together with its record. Postgres
+databases only: MySQL commits DDL implicitly, so a failed migration
+couldn't be rolled back with its record.
+
+Examples:
+  dibbla db migrate new add_users
+  dibbla db migrate status mydb
+  dibbla db migrate up mydb
+  dibbla db migrate down mydb --steps 2`,
+}
+
+var dbMigrateUpCmd = &cobra.Command{
+	Use:   "up <database>",
+	Short: "Apply pending migrations",
+	Args:  cobra.ExactArgs(1),
+	Run:   runDbMigrateUp,
+}
+
+var dbMigrateDownCmd = &cobra.Command{
+	Use:   "down <database>",
+	Short: "Revert the latest applied migrations (one by default)",
+	Args:  cobra.ExactArgs(1),
+	Run:   runDbMigrateDown,
+}
+
+var dbMigrateStatusCmd = &cobra.Command{
+	Use:   "status <database>",
+	Short: "Show which migrations are applied",
+	Args:  cobra.ExactArgs(1),
+	Run:   runDbMigrateStatus,
+}
+
+var dbMigrateNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create empty up and down files for a new migration",
+	Args:  cobra.ExactArgs(1),
+	Run:   runDbMigrateNew,
+}
+
+var (
+	dbMigrateDir       string
+	dbMigrateUpSteps   int
+	dbMigrateDownSteps int
+	dbMigrateDownAll   bool
+)
+
+func init() {
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbMigrateCmd.AddCommand(dbMigrateUpCmd)
+	dbMigrateCmd.AddCommand(dbMigrateDownCmd)
+	dbMigrateCmd.AddCommand(dbMigrateStatusCmd)
+	dbMigrateCmd.AddCommand(dbMigrateNewCmd)
+	dbMigrateCmd.PersistentFlags().StringVar(&dbMigrateDir, "dir", migrate.DefaultDir, "Migrations directory")
+	dbMigrateUpCmd.Flags().IntVar(&dbMigrateUpSteps, "steps", 0, "Apply at most this many migrations (0: all pending)")
+	dbMigrateDownCmd.Flags().IntVar(&dbMigrateDownSteps, "steps", 1, "Number of migrations to revert")
+	dbMigrateDownCmd.Flags().BoolVar(&dbMigrateDownAll, "all", false, "Revert every applied migration")
+	dbMigrateDownCmd.MarkFlagsMutuallyExclusive("steps", "all")
+}
+
+func runDbMigrateUp(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	requireMigrateDatabase(cmd.Context(), cfg, args[0])
+	os.Exit(runDbMigrateApplyCore(cmd.Context(), os.Stdout, os.Stderr, dbQueryFunc(cfg.APIURL, cfg.APIToken, args[0]), dbMigrateDir, false, dbMigrateUpSteps))
+}
+
+func runDbMigrateDown(cmd *cobra.Command, args []string) {
+	steps := dbMigrateDownSteps
+	if dbMigrateDownAll {
+		steps = 0
+	} else if steps < 1 {
+		fmt.Fprintf(os.Stderr, "%s --steps must be at least 1 (use --all to revert everything)\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	requireMigrateDatabase(cmd.Context(), cfg, args[0])
+	os.Exit(runDbMigrateApplyCore(cmd.Context(), os.Stdout, os.Stderr, dbQueryFunc(cfg.APIURL, cfg.APIToken, args[0]), dbMigrateDir, true, steps))
+}
+
+func runDbMigrateStatus(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	requireMigrateDatabase(cmd.Context(), cfg, args[0])
+	os.Exit(runDbMigrateStatusCore(cmd.Context(), os.Stdout, os.Stderr, dbQueryFunc(cfg.APIURL, cfg.APIToken, args[0]), dbMigrateDir))
+}
+
+func runDbMigrateNew(cmd *cobra.Command, args []string) {
+	m, err := migrate.Create(dbMigrateDir, args[0], time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to create migration: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s Created migration %s_%s\n", platform.Icon("✅", "[OK]"), m.Version, m.Name)
+	fmt.Printf("   %s\n", m.UpPath)
+	fmt.Printf("   %s\n", m.DownPath)
+}
+
+// checkMigrateDatabase returns an error unless database name is a Postgres
+// database, the only engine whose DDL the schema_migrations table and the
+// per-migration transactions are written for.
+func checkMigrateDatabase(ctx context.Context, apiURL, apiToken, name string) error {
+	engine, err := lookupDatabaseEngine(ctx, apiURL, apiToken, name)
+	if err != nil {
+		return err
+	}
+	if engine != db.EnginePostgres {
+		return fmt.Errorf("db migrate supports Postgres databases only; '%s' is a %s database", name, engine)
+	}
+	return nil
+}
+
+// requireMigrateDatabase exits unless checkMigrateDatabase accepts
+// database name.
+func requireMigrateDatabase(ctx context.Context, cfg *config.Config, name string) {
+	if err := checkMigrateDatabase(ctx, cfg.APIURL, cfg.APIToken, name); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+}
+
+// dbQueryFunc runs SQL on database name through the API.
+func dbQueryFunc(apiURL, apiToken, name string) migrate.QueryFunc {
+	return func(ctx context.Context, sql string) (*db.QueryResult, error) {
+		return db.Query(ctx, apiURL, apiToken, name, sql)
+	}
+}
+
+// runDbMigrateApplyCore is the testable inner implementation of
+// `db migrate up` and `db migrate down`. steps 0 means all. Returns the
+// exit code.
+func runDbMigrateApplyCore(ctx context.Context, stdout, stderr io.Writer, query migrate.QueryFunc, dir string, down bool, steps int) int {
+	apply, verb := migrate.Up, "Applied"
+	if down {
+		apply, verb = migrate.Down, "Reverted"
+	}
+	n := 0
+	err := apply(ctx, query, dir, steps, func(m migrate.Migration) {
+		fmt.Fprintf(stdout, "%s %s %s_%s\n", platform.Icon("✅", "[OK]"), verb, m.Version, m.Name)
+		n++
+	})
+	if err != nil {
+		interrupt.Wait()
+		fmt.Fprintf(stderr, "%s Migration failed: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if n == 0 {
+		if down {
+			fmt.Fprintln(stdout, "No applied migrations to revert.")
+		} else {
+			fmt.Fprintln(stdout, "Database is up to date.")
+		}
+	}
+	return 0
+}
+
+// runDbMigrateStatusCore is the testable inner implementation of
+// `db migrate status`. Returns the exit code.
+func runDbMigrateStatusCore(ctx context.Context, stdout, stderr io.Writer, query migrate.QueryFunc, dir string) int {
+	st, err := migrate.GetStatus(ctx, query, dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to read migration status: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if len(st) == 0 {
+		fmt.Fprintf(stdout, "No migrations in %s (create one with 'dibbla db migrate new <name>').\n", dir)
+		return 0
+	}
+	pending := 0
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS")
+	for _, s := range st {
+		state := "applied"
+		switch {
+		case s.Missing:
+			state = "applied (file missing)"
+		case !s.Applied:
+			state = "pending"
+			pending++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Version, orDash(s.Name), state)
+	}
+	tw.Flush()
+	fmt.Fprintf(stdout, "\n%d pending\n", pending)
+	return 0
+}
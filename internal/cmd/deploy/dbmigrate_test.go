@@ -0,0 +1,112 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+// migrateServer answers the query endpoint of mydb: applied lists the
+// versions in schema_migrations, and every other statement succeeds and is
+// appended to *ran.
+func migrateServer(t *testing.T, applied ...string) (url string, ran *[]string) {
+	ran = new([]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/deploy/databases/mydb/query" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body struct{ SQL string }
+		json.NewDecoder(r.Body).Decode(&body)
+		res := db.QueryResult{}
+		switch {
+		case strings.HasPrefix(body.SQL, "SELECT version"):
+			res.Columns = []string{"version"}
+			for _, v := range applied {
+				res.Rows = append(res.Rows, []any{v})
+			}
+		case strings.HasPrefix(body.SQL, "BEGIN;"):
+			*ran = append(*ran, body.SQL)
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, ran
+}
+
+func migrationsDir(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"20260101000000_users.up.sql":   "CREATE TABLE users (id int)",
+		"20260101000000_users.down.sql": "DROP TABLE users",
+		"20260102000000_posts.up.sql":   "CREATE TABLE posts (id int)",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRunDbMigrateApplyCore_Up(t *testing.T) {
+	url, ran := migrateServer(t, "20260101000000")
+	var stdout, stderr bytes.Buffer
+	if code := runDbMigrateApplyCore(context.Background(), &stdout, &stderr, dbQueryFunc(url, "tok", "mydb"), migrationsDir(t), false, 0); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(*ran) != 1 || !strings.Contains((*ran)[0], "CREATE TABLE posts") {
+		t.Errorf("ran = %q", *ran)
+	}
+	if !strings.Contains(stdout.String(), "Applied 20260102000000_posts") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunDbMigrateApplyCore_Down(t *testing.T) {
+	url, ran := migrateServer(t, "20260101000000")
+	var stdout, stderr bytes.Buffer
+	if code := runDbMigrateApplyCore(context.Background(), &stdout, &stderr, dbQueryFunc(url, "tok", "mydb"), migrationsDir(t), true, 1); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(*ran) != 1 || !strings.Contains((*ran)[0], "DROP TABLE users") {
+		t.Errorf("ran = %q", *ran)
+	}
+}
+
+func TestRunDbMigrateStatusCore(t *testing.T) {
+	url, _ := migrateServer(t, "20260101000000")
+	var stdout, stderr bytes.Buffer
+	if code := runDbMigrateStatusCore(context.Background(), &stdout, &stderr, dbQueryFunc(url, "tok", "mydb"), migrationsDir(t)); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"20260101000000  users  applied", "20260102000000  posts  pending", "1 pending"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCheckMigrateDatabase(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"databases": []map[string]any{{"name": "shop", "engine": "mysql"}, {"name": "cache", "engine": "redis"}, {"name": "app"}},
+		"total":     3,
+	})
+	for _, name := range []string{"shop", "cache"} {
+		err := checkMigrateDatabase(context.Background(), srv.URL, "tok", name)
+		if err == nil || !strings.Contains(err.Error(), "Postgres databases only") {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+	if err := checkMigrateDatabase(context.Background(), srv.URL, "tok", "app"); err != nil {
+		t.Errorf("app (postgres by default): %v", err)
+	}
+}
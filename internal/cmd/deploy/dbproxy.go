@@ -0,0 +1,107 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var dbProxyCmd = &cobra.Command{
+	Use:   "proxy <name>",
+	Short: "Tunnel a local port to a managed database",
+	Long: `Opens a local port that tunnels to a managed database over an
+authenticated WebSocket, so GUI clients, ORMs and other local tools can
+connect to localhost without going through the public database proxy.
+Each local connection gets its own tunnel. Runs until Ctrl-C.
+
+The port defaults to the engine's usual one (5432 for Postgres, 3306 for
+MySQL, 6379 for Redis); use 0 to pick a free port. The listener binds to
+127.0.0.1 unless --address says otherwise. Connect as for 'dibbla db info',
+with TLS off: the tunnel itself is encrypted.
+
+Examples:
+  dibbla db proxy mydb
+  dibbla db proxy mydb --port 5433
+  dibbla db proxy cache --port 0`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDbProxy,
+}
+
+var (
+	dbProxyPort    int
+	dbProxyAddress string
+)
+
+func init() {
+	dbCmd.AddCommand(dbProxyCmd)
+	dbProxyCmd.Flags().IntVarP(&dbProxyPort, "port", "p", 0, "Local port to listen on (default: the engine's usual port; 0 picks a free one)")
+	dbProxyCmd.Flags().StringVar(&dbProxyAddress, "address", "127.0.0.1", "Local address to listen on")
+}
+
+func runDbProxy(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	port := dbProxyPort
+	if !cmd.Flags().Changed("port") {
+		port = -1
+	}
+
+	// Ctrl-C stops the tunnel cleanly rather than exiting the process.
+	ctx, cancel := interrupt.GracefulContext(cmd.Context())
+	defer cancel()
+
+	os.Exit(runDbProxyCore(ctx, os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], dbProxyAddress, port))
+}
+
+// runDbProxyCore is the testable inner implementation of `db proxy`. A
+// negative port means the engine's usual one. It blocks until ctx is
+// cancelled. Returns the exit code.
+func runDbProxyCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, address string, port int) int {
+	if port > 65535 {
+		fmt.Fprintf(stderr, "%s invalid --port %d\n", platform.Icon("❌", "[X]"), port)
+		return 1
+	}
+	list, err := db.ListDatabases(ctx, apiURL, apiToken)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to look up database '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	d, ok := list.Get(name)
+	if !ok {
+		fmt.Fprintf(stderr, "%s Database '%s' not found (see 'dibbla db list')\n", platform.Icon("❌", "[X]"), name)
+		return 1
+	}
+	engine := d.EngineOrDefault()
+	if port < 0 {
+		port = dbLocalPorts[engine]
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to listen on %s: %v\n", platform.Icon("❌", "[X]"), net.JoinHostPort(address, strconv.Itoa(port)), err)
+		if port == dbLocalPorts[engine] {
+			fmt.Fprintln(stderr, "  hint: a local server may be using the port; pick another with --port.")
+		}
+		return 1
+	}
+	host, localPort, _ := net.SplitHostPort(ln.Addr().String())
+
+	fmt.Fprintf(stdout, "%s Proxying %s -> database '%s' (Ctrl-C to stop)\n", platform.Icon("🔌", "[>]"), ln.Addr(), name)
+	fmt.Fprintf(stdout, "   %s\n", dbConnectionString(engine, maskedPassword, host, localPort, name, "disable"))
+	fmt.Fprintln(stdout, "   The password is your API token.")
+
+	if err := db.Tunnel(ctx, apiURL, apiToken, name, ln, stderr); err != nil {
+		fmt.Fprintf(stderr, "%s Database proxy stopped: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	return 0
+}
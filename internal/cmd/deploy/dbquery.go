@@ -0,0 +1,177 @@
+package deploy
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var dbQueryCmd = &cobra.Command{
+	Use:   "query <name> [sql]",
+	Short: "Run SQL on a database and print the result",
+	Long: `Runs SQL on a managed database through the API and prints the result as a
+table, CSV or JSON. The SQL comes from the argument, from --file, or from
+stdin when neither is given and stdin isn't a terminal.
+
+Exits 1 if the statement fails, so it can gate scripts and CI jobs.
+
+Examples:
+  dibbla db query mydb "SELECT count(*) FROM users"
+  dibbla db query mydb --file report.sql -o csv > report.csv
+  echo "SELECT id, email FROM users LIMIT 5" | dibbla db query mydb -o json`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  runDbQuery,
+}
+
+var (
+	dbQueryFile   string
+	dbQueryOutput string
+)
+
+func init() {
+	dbCmd.AddCommand(dbQueryCmd)
+	dbQueryCmd.Flags().StringVarP(&dbQueryFile, "file", "f", "", "Read the SQL from a file (- for stdin)")
+	dbQueryCmd.Flags().StringVarP(&dbQueryOutput, "output", "o", "table", "Output format: table, csv or json")
+}
+
+func runDbQuery(cmd *cobra.Command, args []string) {
+	sql, err := readQuerySQL(args[1:], dbQueryFile, os.Stdin, isatty.IsTerminal(os.Stdin.Fd()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	requireSQLDatabase(cmd.Context(), cfg, args[0], "db query")
+	os.Exit(runDbQueryCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], sql, dbQueryOutput))
+}
+
+// readQuerySQL picks the SQL for `db query` from the argument, the --file
+// path ("-" for stdin), or stdin when it isn't a terminal.
+func readQuerySQL(args []string, file string, stdin io.Reader, stdinTTY bool) (string, error) {
+	var sql string
+	switch {
+	case len(args) > 0 && file != "":
+		return "", fmt.Errorf("pass the SQL as an argument or with --file, not both")
+	case len(args) > 0:
+		sql = args[0]
+	case file == "-" || (file == "" && !stdinTTY):
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read SQL from stdin: %w", err)
+		}
+		sql = string(b)
+	case file != "":
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read SQL: %w", err)
+		}
+		sql = string(b)
+	}
+	sql = strings.TrimSpace(sql)
+	if sql == "" {
+		return "", fmt.Errorf("no SQL given: pass it as an argument, with --file, or on stdin")
+	}
+	return sql, nil
+}
+
+// queryJSON is the `db query -o json` output: rows as objects keyed by
+// column name.
+type queryJSON struct {
+	Command      string           `json:"command"`
+	RowsAffected int64            `json:"rows_affected"`
+	Columns      []string         `json:"columns"`
+	Rows         []map[string]any `json:"rows"`
+}
+
+// runDbQueryCore is the testable inner implementation of `db query`.
+// Returns the exit code.
+func runDbQueryCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, sql, format string) int {
+	if format != "table" && format != "csv" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected table, csv or json)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+	res, err := db.Query(ctx, apiURL, apiToken, name, sql)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Query failed: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+
+	switch format {
+	case "csv":
+		if len(res.Columns) == 0 {
+			// Nothing to tabulate; keep stdout valid (empty) CSV.
+			fmt.Fprintln(stderr, res.Command)
+			return 0
+		}
+		cw := csv.NewWriter(stdout)
+		cw.Write(res.Columns)
+		for _, row := range res.Rows {
+			rec := make([]string, len(res.Columns))
+			for i := range rec {
+				if i < len(row) {
+					rec[i] = queryCell(row[i])
+				}
+			}
+			cw.Write(rec)
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			fmt.Fprintf(stderr, "%s Failed to write CSV: %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+	case "json":
+		out := queryJSON{Command: res.Command, RowsAffected: res.RowsAffected, Columns: res.Columns, Rows: []map[string]any{}}
+		if out.Columns == nil {
+			out.Columns = []string{}
+		}
+		for _, row := range res.Rows {
+			obj := make(map[string]any, len(res.Columns))
+			for i, c := range res.Columns {
+				if i < len(row) {
+					obj[c] = row[i]
+				}
+			}
+			out.Rows = append(out.Rows, obj)
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(stderr, "%s Failed to encode JSON: %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+	default:
+		printQueryResult(stdout, res)
+	}
+	return 0
+}
+
+// queryCell formats one result value. NULL is empty, whole numbers
+// (decoded from JSON as float64) print without an exponent, and json
+// columns print as JSON.
+func queryCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
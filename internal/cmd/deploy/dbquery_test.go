@@ -0,0 +1,117 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// oneQueryServer answers every query on mydb with status and body and
+// records the SQL it was sent.
+func oneQueryServer(t *testing.T, status int, body string) (url string, gotSQL *string) {
+	gotSQL = new(string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/deploy/databases/mydb/query" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var req struct{ SQL string }
+		json.NewDecoder(r.Body).Decode(&req)
+		*gotSQL = req.SQL
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, gotSQL
+}
+
+const usersResult = `{"columns":["id","email","meta"],"rows":[[1,"a@x.io",null],[1000000,"b,c@x.io",{"k":1}]],"command":"SELECT 2"}`
+
+func TestRunDbQueryCore_Table(t *testing.T) {
+	url, gotSQL := oneQueryServer(t, 200, usersResult)
+	var stdout, stderr bytes.Buffer
+	if code := runDbQueryCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", "SELECT * FROM users", "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if *gotSQL != "SELECT * FROM users" {
+		t.Errorf("sql = %q", *gotSQL)
+	}
+	for _, want := range []string{"1000000", `{"k":1}`, "(2 rows)"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestRunDbQueryCore_CSV(t *testing.T) {
+	url, _ := oneQueryServer(t, 200, usersResult)
+	var stdout, stderr bytes.Buffer
+	if code := runDbQueryCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", "SELECT 1", "csv"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	want := "id,email,meta\n1,a@x.io,\n1000000,\"b,c@x.io\",\"{\"\"k\"\":1}\"\n"
+	if stdout.String() != want {
+		t.Errorf("csv = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunDbQueryCore_JSON(t *testing.T) {
+	url, _ := oneQueryServer(t, 200, usersResult)
+	var stdout, stderr bytes.Buffer
+	if code := runDbQueryCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", "SELECT 1", "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var got queryJSON
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Rows) != 2 || got.Rows[1]["email"] != "b,c@x.io" || got.Command != "SELECT 2" {
+		t.Errorf("json = %+v", got)
+	}
+}
+
+func TestRunDbQueryCore_Error(t *testing.T) {
+	url, _ := oneQueryServer(t, 400, `{"status":"error","error":{"code":"QUERY_FAILED","message":"relation \"nope\" does not exist"}}`)
+	var stdout, stderr bytes.Buffer
+	if code := runDbQueryCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", "SELECT * FROM nope", "table"); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "does not exist") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestReadQuerySQL(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "q.sql")
+	os.WriteFile(file, []byte("SELECT 2;\n"), 0o644)
+
+	tests := []struct {
+		name     string
+		args     []string
+		file     string
+		stdin    string
+		stdinTTY bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "argument", args: []string{"SELECT 1"}, stdinTTY: true, want: "SELECT 1"},
+		{name: "file", file: file, stdinTTY: true, want: "SELECT 2;"},
+		{name: "piped stdin", stdin: " SELECT 3 \n", want: "SELECT 3"},
+		{name: "file dash", file: "-", stdin: "SELECT 4", stdinTTY: true, want: "SELECT 4"},
+		{name: "nothing", stdinTTY: true, wantErr: true},
+		{name: "both", args: []string{"SELECT 1"}, file: file, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readQuerySQL(tt.args, tt.file, strings.NewReader(tt.stdin), tt.stdinTTY)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("readQuerySQL = %q, %v", got, err)
+			}
+		})
+	}
+}
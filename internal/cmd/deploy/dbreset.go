@@ -0,0 +1,115 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/spinner"
+	"github.com/spf13/cobra"
+)
+
+var dbResetCmd = &cobra.Command{
+	Use:   "reset <name>",
+	Short: "Wipe a database and start over empty",
+	Long: `Drops a database and recreates it empty: every table, row and schema is
+gone, but the name, credentials and DATABASE_URL secret stay the same, so
+apps using it keep working against the empty database. Meant for resetting
+dev and staging databases.
+
+You are asked to type the database name to continue (skip with --yes).
+The database is backed up first and the snapshot ID printed, so a reset
+can be undone with 'dibbla db backups restore'; --no-snapshot skips this.
+
+Examples:
+  dibbla db reset staging-db
+  dibbla db reset dev-db --yes --no-snapshot`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDbReset,
+}
+
+var (
+	dbResetYes        bool
+	dbResetSnapshot   bool
+	dbResetNoSnapshot bool
+	dbResetOutput     string
+)
+
+func init() {
+	dbCmd.AddCommand(dbResetCmd)
+	dbResetCmd.Flags().BoolVarP(&dbResetYes, "yes", "y", false, "Skip typing the database name to confirm")
+	dbResetCmd.Flags().BoolVar(&dbResetSnapshot, "snapshot-first", true, "Back the database up before resetting it")
+	dbResetCmd.Flags().BoolVar(&dbResetNoSnapshot, "no-snapshot", false, "Don't take a safety snapshot first")
+	dbResetCmd.Flags().StringVarP(&dbResetOutput, "output", "o", "text", "Output format: text or json")
+	dbResetCmd.MarkFlagsMutuallyExclusive("snapshot-first", "no-snapshot")
+}
+
+func runDbReset(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	requireSQLDatabase(cmd.Context(), cfg, args[0], "db reset")
+	confirm := askTypedConfirm
+	if dbResetYes {
+		confirm = nil
+	}
+	os.Exit(runDbResetCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], confirm, dbResetSnapshot && !dbResetNoSnapshot, dbResetOutput))
+}
+
+// runDbResetCore is the testable inner implementation of `db reset`.
+// confirm asks the user to type the database name; nil skips it. snapshot
+// backs the database up first. Returns the exit code.
+func runDbResetCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name string, confirm func(msg, want string) bool, snapshot bool, format string) int {
+	if err := validateDbOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	human := format == "text"
+	// Keep progress and the snapshot ID visible when stdout is JSON.
+	msg := stdout
+	if !human {
+		msg = stderr
+	}
+	if confirm != nil {
+		if !confirm(fmt.Sprintf("This wipes every table and row in database '%s'.", name), name) {
+			fmt.Fprintln(msg, "Reset cancelled.")
+			return 0
+		}
+	}
+
+	var snapshotID string
+	if snapshot {
+		if snapshotID = takeSafetySnapshot(ctx, msg, stderr, apiURL, apiToken, name, false); snapshotID == "" {
+			return 1
+		}
+	}
+	stop := func() {}
+	if human {
+		stop = spinner.Start("Resetting", "\033[31m")
+	}
+	res, err := db.ResetDatabase(ctx, apiURL, apiToken, name)
+	stop()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to reset database '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		if snapshotID != "" {
+			fmt.Fprintf(stderr, "  If it was partly reset, recover with: dibbla db backups restore %s %s\n", name, snapshotID)
+		}
+		return 1
+	}
+
+	if !human {
+		return writeDbJSON(stdout, stderr, struct {
+			*db.ResetResponse
+			Snapshot string `json:"snapshot,omitempty"`
+		}{res, snapshotID})
+	}
+	message := res.Message
+	if message == "" {
+		message = fmt.Sprintf("Database '%s' reset", name)
+	}
+	fmt.Fprintf(stdout, "%s %s\n", platform.Icon("✅", "[OK]"), message)
+	fmt.Fprintln(stdout, "   Its connection string and secrets are unchanged.")
+	return 0
+}
@@ -0,0 +1,80 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+// resetServer snapshots and resets mydb, recording the calls in order.
+func resetServer(t *testing.T) (url string, calls *[]string) {
+	calls = new([]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls = append(*calls, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/deploy/databases/mydb/backups":
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(db.Backup{ID: "bkp_1", Status: "completed"})
+		case "POST /api/deploy/databases/mydb/reset":
+			json.NewEncoder(w).Encode(db.ResetResponse{Status: "success", Message: "Database 'mydb' reset", Database: "mydb"})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, calls
+}
+
+func TestRunDbResetCore_TypedConfirmation(t *testing.T) {
+	url, calls := resetServer(t)
+	var asked string
+	typed := func(answer string) func(string, string) bool {
+		return func(msg, want string) bool {
+			asked = want
+			return answer == want
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runDbResetCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", typed("mydb2"), false, "text"); code != 0 {
+		t.Fatalf("wrong name: exit %d, want 0", code)
+	}
+	if asked != "mydb" || len(*calls) != 0 || !strings.Contains(stdout.String(), "Reset cancelled") {
+		t.Errorf("asked %q, calls %q, stdout %q", asked, *calls, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runDbResetCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", typed("mydb"), false, "text"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if strings.Join(*calls, ",") != "POST /api/deploy/databases/mydb/reset" {
+		t.Errorf("calls = %q", *calls)
+	}
+	if !strings.Contains(stdout.String(), "Database 'mydb' reset") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunDbResetCore_SnapshotJSON(t *testing.T) {
+	url, calls := resetServer(t)
+	var stdout, stderr bytes.Buffer
+	if code := runDbResetCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", nil, true, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if want := "POST /api/deploy/databases/mydb/backups,POST /api/deploy/databases/mydb/reset"; strings.Join(*calls, ",") != want {
+		t.Errorf("calls = %q", *calls)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || got["snapshot"] != "bkp_1" || got["database"] != "mydb" {
+		t.Errorf("stdout = %q (%v)", stdout.String(), err)
+	}
+	if !strings.Contains(stderr.String(), "Snapshot bkp_1 saved") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
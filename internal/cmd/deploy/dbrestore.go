@@ -0,0 +1,281 @@
+package deploy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// restorePollInterval is how often `db restore` checks on its job.
+const restorePollInterval = 2 * time.Second
+
+// dumpSource is where `db restore` reads a dump from: File, a path or "-"
+// for stdin, or URL, fetched over HTTP(S).
+type dumpSource struct {
+	File string
+	URL  string
+}
+
+func (s dumpSource) String() string {
+	switch {
+	case s.URL != "":
+		return s.URL
+	case s.File == "-":
+		return "stdin"
+	default:
+		return s.File
+	}
+}
+
+// open returns the dump as a stream. A URL is fetched without the API
+// token, since it points at third-party storage (e.g. a presigned S3
+// link).
+func (s dumpSource) open(ctx context.Context, stdin io.Reader) (io.ReadCloser, error) {
+	switch {
+	case s.URL != "":
+		if !strings.HasPrefix(s.URL, "https://") && !strings.HasPrefix(s.URL, "http://") {
+			return nil, fmt.Errorf("--from-url must be an http:// or https:// URL")
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", s.URL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		resp, err := apiclient.Do(&http.Client{}, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download dump: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to download dump: %s", resp.Status)
+		}
+		return resp.Body, nil
+	case s.File == "-":
+		return io.NopCloser(stdin), nil
+	default:
+		f, err := os.Open(s.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dump file: %w", err)
+		}
+		return f, nil
+	}
+}
+
+// checkDump reads the start of dump to detect its format and checks that a
+// database of engine can restore it, so an empty, compressed or mismatched
+// file fails before it is uploaded. A plain dump that isn't UTF-8 is only
+// warned about on warn. The returned reader yields the whole dump,
+// including the bytes read to check it.
+func checkDump(dump io.Reader, engine string, warn io.Writer) (io.Reader, string, error) {
+	br := bufio.NewReaderSize(dump, db.DumpSniffLen)
+	head, err := br.Peek(db.DumpSniffLen)
+	if err != nil && err != io.EOF {
+		return nil, "", fmt.Errorf("failed to read dump: %w", err)
+	}
+	format, err := db.DetectDumpFormat(head)
+	if err != nil {
+		return nil, "", err
+	}
+	if accepted := db.DumpFormatsFor(engine); !slices.Contains(accepted, format) {
+		return nil, "", fmt.Errorf("it is a %s dump, but %s databases restore %s dumps", format, engine, strings.Join(accepted, ", "))
+	}
+	if format == db.DumpFormatPlain && !db.IsUTF8(head) {
+		fmt.Fprintf(warn, "%s The dump is not UTF-8; it is restored as is, so check that its encoding (e.g. a SET client_encoding line) matches the database's\n", platform.Icon("⚠️", "[!]"))
+	}
+	return br, format, nil
+}
+
+// dbRestoreOptions is one `db restore` run.
+type dbRestoreOptions struct {
+	// Source is the dump to upload; ignored when JobID is set.
+	Source dumpSource
+	// Restore is passed to the API; its Format is filled in from the dump.
+	Restore db.RestoreOptions
+	// Snapshot takes a safety snapshot first when Restore.Clean is set.
+	Snapshot bool
+	// JobID follows an already submitted restore job instead of uploading.
+	JobID string
+	// Wait follows the job until it finishes; otherwise the run returns as
+	// soon as the job is submitted.
+	Wait bool
+	// Interval is how often the job is polled.
+	Interval time.Duration
+	// Progress redraws one status line instead of printing a line per phase.
+	Progress bool
+	// Output is "text" or "json". With json the only output on stdout is
+	// the finished job, or the submitted restore when not waiting.
+	Output string
+}
+
+// runDbRestoreCore is the testable inner implementation of `db restore`.
+// It checks the dump from o.Source (see checkDump), streams it to the API
+// and follows the restore job, or follows o.JobID when set.
+// Returns the exit code.
+func runDbRestoreCore(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, apiURL, apiToken, name string, o dbRestoreOptions) int {
+	if err := validateDbOutput(o.Output); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if err := o.Restore.Validate(); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	jsonOut := o.Output == "json"
+	out := stdout
+	if jsonOut {
+		out, o.Progress = io.Discard, false
+	}
+	if o.JobID == "" {
+		dump, err := o.Source.open(ctx, stdin)
+		if err != nil {
+			interrupt.Wait()
+			fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		defer dump.Close()
+		engine, err := lookupDatabaseEngine(ctx, apiURL, apiToken, name)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		body, format, err := checkDump(dump, engine, stderr)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s Not restoring %s: %v\n", platform.Icon("❌", "[X]"), o.Source, err)
+			return 1
+		}
+		o.Restore.Format = format
+		if o.Restore.Clean && format == db.DumpFormatPlain && engine == db.EnginePostgres {
+			fmt.Fprintf(stderr, "%s --clean has no effect on a plain SQL dump; it only drops objects if it was made with pg_dump --clean\n", platform.Icon("⚠️", "[!]"))
+		}
+		if o.Restore.Clean && o.Snapshot {
+			msg := out
+			if jsonOut {
+				msg = stderr
+			}
+			if takeSafetySnapshot(ctx, msg, stderr, apiURL, apiToken, name, false) == "" {
+				return 1
+			}
+		}
+		fmt.Fprintf(out, "%s Restoring database '%s' from %s (%s dump)...\n", platform.Icon("🌱", "[>]"), name, o.Source, format)
+		fmt.Fprintln(out)
+
+		res, err := db.RestoreDatabase(ctx, apiURL, apiToken, name, body, o.Restore)
+		if err != nil {
+			interrupt.Wait()
+			fmt.Fprintf(stderr, "%s Failed to restore database: %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		if jsonOut && (res.JobID == "" || !o.Wait) {
+			return writeDbJSON(stdout, stderr, res)
+		}
+		if res.JobID == "" {
+			// Older servers restore before responding.
+			fmt.Fprintf(out, "%s %s\n", platform.Icon("✅", "[OK]"), res.Message)
+			return 0
+		}
+		o.JobID = res.JobID
+		if !o.Wait {
+			fmt.Fprintf(out, "%s Restore job %s started\n", platform.Icon("✅", "[OK]"), o.JobID)
+			fmt.Fprintf(out, "   Follow it with: dibbla db restore %s --job %s\n", name, o.JobID)
+			return 0
+		}
+	}
+	job, code := followRestoreJob(ctx, out, stderr, apiURL, apiToken, name, o.JobID, o.Interval, o.Progress)
+	if jsonOut && job != nil {
+		if writeDbJSON(stdout, stderr, job) != 0 {
+			return 1
+		}
+	}
+	return code
+}
+
+// restoreWaitLimit bounds how long followRestoreJob polls a job that never
+// finishes. A variable so tests can shorten it.
+var restoreWaitLimit = 6 * time.Hour
+
+// followRestoreJob polls restore job jobID on database name until it
+// finishes, showing its progress, and reports the outcome. Returns the
+// finished job (nil if it couldn't be checked) and the exit code.
+func followRestoreJob(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, jobID string, interval time.Duration, showProgress bool) (*db.RestoreJob, int) {
+	var drawn bool
+	clearLine := func() {
+		if drawn {
+			fmt.Fprint(stdout, "\r\033[K")
+			drawn = false
+		}
+	}
+	// Ctrl-C only stops the client; say how to pick the job up again.
+	removeHint := interrupt.OnCleanup(func() {
+		clearLine()
+		fmt.Fprintf(stdout, "Stopped waiting; the restore continues on the server.\n")
+		fmt.Fprintf(stdout, "   Follow it with: dibbla db restore %s --job %s\n", name, jobID)
+	})
+	defer removeHint()
+
+	waitCtx, cancel := context.WithTimeout(ctx, restoreWaitLimit)
+	defer cancel()
+	lastPhase := ""
+	job, err := db.WaitRestoreJob(waitCtx, apiURL, apiToken, name, jobID, interval, func(j *db.RestoreJob) {
+		if j.Done() {
+			return
+		}
+		if showProgress {
+			fmt.Fprintf(stdout, "\r\033[K%s", restoreProgressLine(j))
+			drawn = true
+		} else if j.Phase != lastPhase {
+			fmt.Fprintln(stdout, restoreProgressLine(j))
+		}
+		lastPhase = j.Phase
+	})
+	clearLine()
+	if err != nil {
+		interrupt.Wait()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			err = fmt.Errorf("still not finished after %s", restoreWaitLimit)
+		}
+		fmt.Fprintf(stderr, "%s Failed to check restore job %s: %v\n", platform.Icon("❌", "[X]"), jobID, err)
+		fmt.Fprintf(stderr, "   Follow it with: dibbla db restore %s --job %s\n", name, jobID)
+		return nil, 1
+	}
+
+	if !job.Succeeded() {
+		msg := job.Error
+		if msg == "" {
+			msg = job.Message
+		}
+		if msg == "" {
+			msg = fmt.Sprintf("the job ended with status %q", job.Status)
+		}
+		fmt.Fprintf(stderr, "%s Restore of '%s' failed: %s\n", platform.Icon("❌", "[X]"), name, msg)
+		return job, 1
+	}
+	fmt.Fprintf(stdout, "%s Restored database '%s' (%d rows)\n", platform.Icon("✅", "[OK]"), name, job.RowsRestored)
+	if job.Message != "" {
+		fmt.Fprintf(stdout, "   %s\n", job.Message)
+	}
+	return job, 0
+}
+
+// restoreProgressLine describes a running restore job in one line.
+func restoreProgressLine(j *db.RestoreJob) string {
+	phase := j.Phase
+	if phase == "" {
+		phase = j.Status
+	}
+	line := fmt.Sprintf("Restoring (%s): %d rows", phase, j.RowsRestored)
+	if j.TablesTotal > 0 {
+		line += fmt.Sprintf(", %d/%d tables", j.TablesDone, j.TablesTotal)
+	}
+	return line
+}
@@ -0,0 +1,372 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+// restoreStub records what a restoreServer saw: job polls, the dumps
+// uploaded and the format sent with each.
+type restoreStub struct {
+	URL     string
+	polls   int
+	uploads []string
+	formats []string
+}
+
+// restoreServer accepts an upload for mydb with the given status and body,
+// then answers job polls with jobs in order, repeating the last one.
+func restoreServer(t *testing.T, status int, body any, jobs ...db.RestoreJob) *restoreStub {
+	stub := &restoreStub{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/deploy/databases/mydb/restore":
+			f, _, err := r.FormFile("dump")
+			if err != nil {
+				t.Errorf("no dump in upload: %v", err)
+			} else {
+				b, _ := io.ReadAll(f)
+				stub.uploads = append(stub.uploads, string(b))
+				stub.formats = append(stub.formats, r.FormValue("format"))
+			}
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(body)
+		case r.Method == "GET" && r.URL.Path == "/api/deploy/databases":
+			json.NewEncoder(w).Encode(map[string]any{"databases": []string{"mydb"}, "total": 1})
+		case r.Method == "GET" && r.URL.Path == "/api/deploy/databases/mydb/restore/rst_1":
+			job := jobs[min(stub.polls, len(jobs)-1)]
+			stub.polls++
+			json.NewEncoder(w).Encode(job)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	stub.URL = srv.URL
+	return stub
+}
+
+func dumpFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "backup.dump")
+	if err := os.WriteFile(path, []byte("PGDMP"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+var acceptedRestore = db.DatabaseRestoreResponse{Status: "accepted", Database: "mydb", JobID: "rst_1"}
+
+func TestRunDbRestoreCore_WaitsForJob(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, acceptedRestore,
+		db.RestoreJob{ID: "rst_1", Status: db.RestoreRunning, Phase: "schema"},
+		db.RestoreJob{ID: "rst_1", Status: db.RestoreRunning, Phase: "data", RowsRestored: 500, TablesDone: 1, TablesTotal: 3},
+		db.RestoreJob{ID: "rst_1", Status: db.RestoreSucceeded, RowsRestored: 1200})
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Wait: true, Interval: time.Millisecond, Output: "text"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if srv.polls != 3 {
+		t.Errorf("polls = %d, want 3", srv.polls)
+	}
+	out := stdout.String()
+	for _, want := range []string{"Restoring (schema): 0 rows", "Restoring (data): 500 rows, 1/3 tables", "Restored database 'mydb' (1200 rows)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDbRestoreCore_JobFailed(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, acceptedRestore,
+		db.RestoreJob{ID: "rst_1", Status: db.RestoreFailed, Error: "relation \"users\" already exists"})
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Wait: true, Interval: time.Millisecond, Output: "text"}); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), `relation "users" already exists`) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestFollowRestoreJob_UnknownStatusFails(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, nil,
+		db.RestoreJob{ID: "rst_1", Status: "cancelled"})
+	var stdout, stderr bytes.Buffer
+	if _, code := followRestoreJob(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", "rst_1", time.Millisecond, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if srv.polls != 1 || !strings.Contains(stderr.String(), `the job ended with status "cancelled"`) {
+		t.Errorf("polls=%d stderr=%q", srv.polls, stderr.String())
+	}
+}
+
+func TestFollowRestoreJob_GivesUp(t *testing.T) {
+	defer func(d time.Duration) { restoreWaitLimit = d }(restoreWaitLimit)
+	restoreWaitLimit = 20 * time.Millisecond
+	srv := restoreServer(t, http.StatusAccepted, nil,
+		db.RestoreJob{ID: "rst_1", Status: db.RestoreRunning})
+	var stdout, stderr bytes.Buffer
+	if _, code := followRestoreJob(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", "rst_1", time.Millisecond, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	for _, want := range []string{"still not finished after 20ms", "dibbla db restore mydb --job rst_1"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr lacks %q: %q", want, stderr.String())
+		}
+	}
+}
+
+func TestRunDbRestoreCore_NoWait(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, acceptedRestore)
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Interval: time.Millisecond, Output: "text"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if srv.polls != 0 {
+		t.Errorf("polled %d times with --no-wait", srv.polls)
+	}
+	if !strings.Contains(stdout.String(), "dibbla db restore mydb --job rst_1") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunDbRestoreCore_FollowJob(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, nil,
+		db.RestoreJob{ID: "rst_1", Status: db.RestoreSucceeded, RowsRestored: 7})
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{}, JobID: "rst_1", Wait: true, Interval: time.Millisecond, Output: "text"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "from") || !strings.Contains(stdout.String(), "(7 rows)") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunDbRestoreCore_SynchronousServer(t *testing.T) {
+	srv := restoreServer(t, http.StatusOK, db.DatabaseRestoreResponse{Status: "success", Message: "Database restored"})
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Wait: true, Interval: time.Millisecond, Output: "text"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if srv.polls != 0 || !strings.Contains(stdout.String(), "Database restored") {
+		t.Errorf("polls = %d, stdout = %q", srv.polls, stdout.String())
+	}
+}
+
+func TestRunDbRestoreCore_Stdin(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, acceptedRestore)
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), strings.NewReader("PGDMP-stdin"), &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: "-"}, Interval: time.Millisecond, Output: "text"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(srv.uploads) != 1 || srv.uploads[0] != "PGDMP-stdin" {
+		t.Errorf("srv.uploads = %q", srv.uploads)
+	}
+	if !strings.Contains(stdout.String(), "from stdin") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunDbRestoreCore_FromURL(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("API token sent to the dump URL")
+		}
+		if r.URL.Path != "/mydb.dump" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("PGDMP-remote"))
+	}))
+	defer storage.Close()
+	srv := restoreServer(t, http.StatusAccepted, acceptedRestore)
+
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{URL: storage.URL + "/mydb.dump"}, Interval: time.Millisecond, Output: "text"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(srv.uploads) != 1 || srv.uploads[0] != "PGDMP-remote" {
+		t.Errorf("srv.uploads = %q", srv.uploads)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{URL: storage.URL + "/expired"}, Interval: time.Millisecond, Output: "text"}); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "403") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+	if len(srv.uploads) != 1 {
+		t.Errorf("a failed download was uploaded: %q", srv.uploads)
+	}
+}
+
+func TestRunDbRestoreCore_JSON(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, acceptedRestore,
+		db.RestoreJob{ID: "rst_1", Status: db.RestoreRunning, Phase: "data"},
+		db.RestoreJob{ID: "rst_1", Status: db.RestoreFailed, Error: "disk full"})
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Wait: true, Interval: time.Millisecond, Output: "json"}); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	var job db.RestoreJob
+	if err := json.Unmarshal(stdout.Bytes(), &job); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout.String())
+	}
+	if job.Status != db.RestoreFailed || job.Error != "disk full" {
+		t.Errorf("job = %+v", job)
+	}
+
+	stdout.Reset()
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Interval: time.Millisecond, Output: "json"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var res db.DatabaseRestoreResponse
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil || res.JobID != "rst_1" {
+		t.Errorf("stdout = %q (%v)", stdout.String(), err)
+	}
+}
+
+func TestRunDbRestoreCore_CleanOptions(t *testing.T) {
+	var parts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]any{"databases": []string{"mydb"}, "total": 1})
+			return
+		}
+		mr, err := r.MultipartReader()
+		if err != nil {
+			t.Fatalf("not multipart: %v", err)
+		}
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			b, _ := io.ReadAll(p)
+			parts = append(parts, p.FormName()+"="+string(b))
+		}
+		json.NewEncoder(w).Encode(db.DatabaseRestoreResponse{Status: "success", Message: "Database restored"})
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	opts := db.RestoreOptions{Clean: true, IfExists: true}
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Restore: opts, Wait: true, Interval: time.Millisecond, Output: "text"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	// The options come before the dump, so the server has them when it starts.
+	if want := "format=custom,clean=true,if_exists=true,dump=PGDMP"; strings.Join(parts, ",") != want {
+		t.Errorf("parts = %q, want %q", parts, want)
+	}
+
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Restore: db.RestoreOptions{IfExists: true}, Wait: true, Interval: time.Millisecond, Output: "text"}); code != 1 {
+		t.Errorf("--if-exists without --clean: exit %d, want 1", code)
+	}
+}
+
+func TestRunDbRestoreCore_CleanSnapshotsFirst(t *testing.T) {
+	url, calls := snapshotServer(t, "failed")
+	var stdout, stderr bytes.Buffer
+	opts := db.RestoreOptions{Clean: true}
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, url, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: dumpFile(t)}, Restore: opts, Snapshot: true, Wait: true, Interval: time.Millisecond, Output: "text"}); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if len(*calls) != 3 || !strings.Contains(stderr.String(), "Nothing was changed") {
+		t.Errorf("calls = %q, stderr = %q", *calls, stderr.String())
+	}
+}
+
+func TestCheckDump(t *testing.T) {
+	tarHead := make([]byte, 600)
+	copy(tarHead[257:], "ustar")
+	tests := []struct {
+		name, engine, dump, format, err, warn string
+	}{
+		{"custom", db.EnginePostgres, "PGDMP\x01\x0e", db.DumpFormatCustom, "", ""},
+		{"tar", db.EnginePostgres, string(tarHead), db.DumpFormatTar, "", ""},
+		{"plain", db.EnginePostgres, "-- PostgreSQL dump\nCREATE TABLE café (id int);\n", db.DumpFormatPlain, "", ""},
+		{"mysql", db.EngineMySQL, "-- MySQL dump 10.13\n", db.DumpFormatPlain, "", ""},
+		{"latin-1", db.EnginePostgres, "SET client_encoding = 'LATIN1';\nINSERT INTO t VALUES ('caf\xe9');\n", db.DumpFormatPlain, "", "not UTF-8"},
+		{"rdb", db.EngineRedis, "REDIS0011\xfa", db.DumpFormatRDB, "", ""},
+		{"empty", db.EnginePostgres, "", "", "the dump is empty", ""},
+		{"gzip", db.EnginePostgres, "\x1f\x8b\x08\x00", "", "gzip-compressed", ""},
+		{"binary", db.EnginePostgres, "\x00\x01\x02garbage", "", "not a recognized dump", ""},
+		{"wrong engine", db.EnginePostgres, "REDIS0011", "", "it is a rdb dump, but postgres databases restore custom, plain, tar dumps", ""},
+		{"custom into mysql", db.EngineMySQL, "PGDMP", "", "mysql databases restore plain dumps", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warn bytes.Buffer
+			r, format, err := checkDump(strings.NewReader(tt.dump), tt.engine, &warn)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.format {
+				t.Errorf("format = %q, want %q", format, tt.format)
+			}
+			if tt.warn == "" && warn.Len() > 0 || !strings.Contains(warn.String(), tt.warn) {
+				t.Errorf("warning = %q, want %q", warn.String(), tt.warn)
+			}
+			if b, _ := io.ReadAll(r); string(b) != tt.dump {
+				t.Errorf("reader lost data: got %d bytes, want %d", len(b), len(tt.dump))
+			}
+		})
+	}
+}
+
+func TestRunDbRestoreCore_RejectsBadDumpBeforeUpload(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, acceptedRestore, db.RestoreJob{ID: "rst_1", Status: db.RestoreSucceeded})
+	path := filepath.Join(t.TempDir(), "empty.dump")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: path}, Wait: true, Interval: time.Millisecond, Output: "text"}); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if len(srv.uploads) != 0 {
+		t.Errorf("uploaded %q", srv.uploads)
+	}
+	if !strings.Contains(stderr.String(), "the dump is empty") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunDbRestoreCore_SendsDetectedFormat(t *testing.T) {
+	srv := restoreServer(t, http.StatusAccepted, acceptedRestore, db.RestoreJob{ID: "rst_1", Status: db.RestoreSucceeded})
+	path := filepath.Join(t.TempDir(), "backup.sql")
+	if err := os.WriteFile(path, []byte("CREATE TABLE t (id int);\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	opts := db.RestoreOptions{Clean: true}
+	if code := runDbRestoreCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "mydb", dbRestoreOptions{Source: dumpSource{File: path}, Restore: opts, Wait: true, Interval: time.Millisecond, Output: "text"}); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(srv.formats) != 1 || srv.formats[0] != db.DumpFormatPlain {
+		t.Errorf("formats = %q", srv.formats)
+	}
+	if !strings.Contains(stdout.String(), "(plain dump)") || !strings.Contains(stderr.String(), "--clean has no effect") {
+		t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,152 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var dbSeedCmd = &cobra.Command{
+	Use:   "seed <name>",
+	Short: "Run a SQL seed script on a database",
+	Long: `Runs a local SQL script, statement by statement, on a managed database
+through the API, showing each statement's progress. By default the whole
+script runs in one transaction: if a statement fails, everything is rolled
+back and the database is left as it was. --no-transaction keeps the
+statements that succeeded before the failure. The request is never
+retried; if the connection breaks, check the database before rerunning.
+
+The script is read from seed.sql unless --file says otherwise (- for
+stdin). Don't put BEGIN/COMMIT in it unless you pass --no-transaction.
+
+Examples:
+  dibbla db seed mydb
+  dibbla db seed mydb --file fixtures/dev.sql
+  cat seed.sql | dibbla db seed mydb -f -`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDbSeed,
+}
+
+var (
+	dbSeedFile          string
+	dbSeedNoTransaction bool
+)
+
+func init() {
+	dbCmd.AddCommand(dbSeedCmd)
+	dbSeedCmd.Flags().StringVarP(&dbSeedFile, "file", "f", "seed.sql", "SQL script to run (- for stdin)")
+	dbSeedCmd.Flags().BoolVar(&dbSeedNoTransaction, "no-transaction", false, "Run each statement on its own; a failure keeps the ones before it")
+}
+
+func runDbSeed(cmd *cobra.Command, args []string) {
+	sql, err := readSeedSQL(dbSeedFile, os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	engine := requireSQLDatabase(cmd.Context(), cfg, args[0], "db seed")
+	showProgress := isatty.IsTerminal(os.Stdout.Fd()) && !platform.IsCI()
+	os.Exit(runDbSeedCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], engine, sql, !dbSeedNoTransaction, showProgress))
+}
+
+// readSeedSQL reads the seed script from file, or stdin for "-".
+func readSeedSQL(file string, stdin io.Reader) (string, error) {
+	var b []byte
+	var err error
+	if file == "-" {
+		b, err = io.ReadAll(stdin)
+	} else {
+		b, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read seed script: %w", err)
+	}
+	return string(b), nil
+}
+
+// runDbSeedCore is the testable inner implementation of `db seed`. It
+// splits sql into statements as engine's SQL and runs them on database
+// name, in one
+// transaction when transaction is set. showProgress redraws one status
+// line instead of printing a line per statement. Returns the exit code.
+func runDbSeedCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, engine, sql string, transaction, showProgress bool) int {
+	stmts := db.SplitStatements(sql, engine)
+	if len(stmts) == 0 {
+		fmt.Fprintf(stderr, "%s The seed script has no SQL statements\n", platform.Icon("❌", "[X]"))
+		return 1
+	}
+	mode := "in one transaction"
+	if !transaction {
+		mode = "one by one"
+	}
+	fmt.Fprintf(stdout, "%s Seeding database '%s': %d statements, %s...\n", platform.Icon("🌱", "[>]"), name, len(stmts), mode)
+	fmt.Fprintln(stdout)
+
+	var drawn bool
+	var rows int64
+	done := 0
+	err := db.Exec(ctx, apiURL, apiToken, name, db.ExecRequest{Statements: stmts, Transaction: transaction}, func(r db.ExecResult) {
+		done++
+		rows += r.RowsAffected
+		if showProgress {
+			fmt.Fprintf(stdout, "\r\033[KSeeding: %d/%d statements, %d rows", done, len(stmts), rows)
+			drawn = true
+			return
+		}
+		command := r.Command
+		if command == "" && r.Index < len(stmts) {
+			command = statementSummary(stmts[r.Index])
+		}
+		fmt.Fprintf(stdout, "  [%d/%d] %s\n", r.Index+1, len(stmts), command)
+	})
+	if drawn {
+		fmt.Fprint(stdout, "\r\033[K")
+	}
+	if err != nil {
+		interrupt.Wait()
+		var execErr *db.ExecError
+		failed := errors.As(err, &execErr)
+		if failed && execErr.Index >= 0 && execErr.Index < len(stmts) {
+			fmt.Fprintf(stderr, "%s Statement %d of %d failed: %s\n", platform.Icon("❌", "[X]"), execErr.Index+1, len(stmts), execErr.Message)
+			fmt.Fprintf(stderr, "   %s\n", statementSummary(stmts[execErr.Index]))
+		} else {
+			fmt.Fprintf(stderr, "%s Seeding failed: %v\n", platform.Icon("❌", "[X]"), err)
+		}
+		switch {
+		case !failed:
+			// The connection or stream broke: the server may have run more
+			// than was reported, or committed.
+			fmt.Fprintln(stderr, "   The outcome is unknown; check the database before running the script again.")
+		case transaction:
+			fmt.Fprintln(stderr, "   The transaction was rolled back; nothing was changed.")
+		case done > 0:
+			fmt.Fprintf(stderr, "   Statements 1-%d were applied.\n", done)
+		}
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Seeded '%s': %d statements, %d rows affected\n", platform.Icon("✅", "[OK]"), name, len(stmts), rows)
+	return 0
+}
+
+// statementSummary is the first line of stmt, shortened for messages.
+func statementSummary(stmt string) string {
+	line, _, more := strings.Cut(stmt, "\n")
+	if r := []rune(line); len(r) > 72 {
+		line, more = string(r[:72]), true
+	}
+	if more {
+		line += " …"
+	}
+	return line
+}
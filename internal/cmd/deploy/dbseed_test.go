@@ -0,0 +1,113 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+// execServer answers an exec batch on mydb with one NDJSON line per
+// statement, failing the one at index fail (-1 for none).
+func execServer(t *testing.T, fail int) (url string, got *db.ExecRequest) {
+	got = new(db.ExecRequest)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/deploy/databases/mydb/exec" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(got)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for i := range got.Statements {
+			if i == fail {
+				fmt.Fprintf(w, `{"index":%d,"error":"relation \"users\" does not exist"}`+"\n", i)
+				return
+			}
+			fmt.Fprintf(w, `{"index":%d,"command":"INSERT 0 2","rows_affected":2}`+"\n", i)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, got
+}
+
+func TestRunDbSeedCore(t *testing.T) {
+	url, got := execServer(t, -1)
+	var stdout, stderr bytes.Buffer
+	sql := "INSERT INTO users VALUES (1), (2);\nINSERT INTO posts VALUES (1), (2);\n"
+	if code := runDbSeedCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", db.EnginePostgres, sql, true, false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !got.Transaction || len(got.Statements) != 2 {
+		t.Errorf("request = %+v", got)
+	}
+	for _, want := range []string{"[1/2] INSERT 0 2", "[2/2] INSERT 0 2", "Seeded 'mydb': 2 statements, 4 rows affected"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestRunDbSeedCore_FailedStatement(t *testing.T) {
+	sql := "CREATE TABLE posts (id int);\nINSERT INTO users VALUES (1);\nSELECT 1;"
+	for _, transaction := range []bool{true, false} {
+		url, got := execServer(t, 1)
+		var stdout, stderr bytes.Buffer
+		if code := runDbSeedCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", db.EnginePostgres, sql, transaction, false); code != 1 {
+			t.Fatalf("exit %d, want 1", code)
+		}
+		if got.Transaction != transaction {
+			t.Errorf("transaction = %v, want %v", got.Transaction, transaction)
+		}
+		want := []string{`Statement 2 of 3 failed: relation "users" does not exist`, "INSERT INTO users VALUES (1)"}
+		if transaction {
+			want = append(want, "rolled back")
+		} else {
+			want = append(want, "Statements 1-1 were applied")
+		}
+		for _, w := range want {
+			if !strings.Contains(stderr.String(), w) {
+				t.Errorf("transaction=%v: stderr missing %q:\n%s", transaction, w, stderr.String())
+			}
+		}
+	}
+}
+
+func TestRunDbSeedCore_UnknownOutcome(t *testing.T) {
+	t.Setenv("DIBBLA_RETRIES", "3")
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		// The stream ends after the first statement.
+		fmt.Fprintln(w, `{"index":0,"command":"CREATE TABLE","rows_affected":0}`)
+	}))
+	defer srv.Close()
+	sql := "CREATE TABLE posts (id int);\nSELECT 1;"
+	for range 2 {
+		var stdout, stderr bytes.Buffer
+		if code := runDbSeedCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "mydb", db.EnginePostgres, sql, true, false); code != 1 {
+			t.Fatalf("exit %d, want 1", code)
+		}
+		if !strings.Contains(stderr.String(), "The outcome is unknown") || strings.Contains(stderr.String(), "rolled back") {
+			t.Errorf("stderr = %q", stderr.String())
+		}
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want one per run (no retries)", requests)
+	}
+}
+
+func TestRunDbSeedCore_NoStatements(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runDbSeedCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "mydb", db.EnginePostgres, "-- nothing yet\n", true, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+}
@@ -0,0 +1,200 @@
+package deploy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// runDbClient replaces the interactive session with a local database
+// client (psql, mysql, redis-cli) run with args, adding env ("KEY=value",
+// e.g. the password) to its environment. The clients handle Ctrl-C
+// themselves (cancelling the running query), so the CLI ignores SIGINT
+// while one runs instead of exiting under it.
+func runDbClient(path string, args, env []string, stderr io.Writer) int {
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	c := exec.Command(path, args...)
+	c.Env = append(os.Environ(), env...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(stderr, "%s Failed to run %s: %v\n", platform.Icon("❌", "[X]"), filepath.Base(path), err)
+		return 1
+	}
+	return 0
+}
+
+// runDbShell runs the built-in SQL shell until \q, EOF or Ctrl-C.
+func runDbShell(ctx context.Context, apiURL, apiToken, name string, interactive bool) int {
+	// Ctrl-C leaves the shell cleanly rather than exiting the process.
+	ctx, stop := interrupt.GracefulContext(ctx)
+	defer stop()
+	return runDbShellCore(ctx, os.Stdin, os.Stdout, os.Stderr, apiURL, apiToken, name, interactive)
+}
+
+const dbShellHelp = `Built-in SQL shell (statements run through the Dibbla API).
+  End a statement with ; to run it. Statements can span lines.
+  \dt       list tables
+  \q        quit (also Ctrl-D or Ctrl-C)
+  \?        this help
+`
+
+// dbListTablesSQL backs the \dt meta-command.
+const dbListTablesSQL = `SELECT table_schema AS schema, table_name AS name, table_type AS type
+FROM information_schema.tables
+WHERE table_schema NOT IN ('pg_catalog', 'information_schema', 'mysql', 'performance_schema', 'sys')
+ORDER BY 1, 2`
+
+// runDbShellCore is the testable inner implementation of the built-in SQL
+// shell: it reads statements from stdin, runs each with db.Query and prints
+// the results. Prompts are shown only when interactive. Returns the exit
+// code: 0, or 1 if the last statement failed when not interactive.
+func runDbShellCore(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, apiURL, apiToken, name string, interactive bool) int {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(stdin)
+		sc.Buffer(make([]byte, 64*1024), 1024*1024)
+		for sc.Scan() {
+			select {
+			case lines <- sc.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	if interactive {
+		fmt.Fprintf(stdout, "Connected to '%s' (built-in shell; install psql for the full client). Type \\? for help.\n", name)
+	}
+	run := func(sql string) bool {
+		res, err := db.Query(ctx, apiURL, apiToken, name, sql)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(stderr, "ERROR: %v\n", err)
+			}
+			return false
+		}
+		printQueryResult(stdout, res)
+		return true
+	}
+
+	var stmt strings.Builder
+	ok := true
+	for {
+		if interactive {
+			if stmt.Len() == 0 {
+				fmt.Fprintf(stdout, "%s=> ", name)
+			} else {
+				fmt.Fprintf(stdout, "%s-> ", name)
+			}
+		}
+		var line string
+		var more bool
+		select {
+		case line, more = <-lines:
+		case <-ctx.Done():
+		}
+		if !more {
+			if interactive {
+				fmt.Fprintln(stdout)
+			}
+			break
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if stmt.Len() == 0 {
+			switch trimmed {
+			case "":
+				continue
+			case `\q`, "quit", "exit":
+				return 0
+			case `\?`, "help":
+				fmt.Fprint(stdout, dbShellHelp)
+				continue
+			case `\dt`:
+				ok = run(dbListTablesSQL)
+				continue
+			}
+		}
+		stmt.WriteString(line)
+		stmt.WriteByte('\n')
+		if strings.HasSuffix(trimmed, ";") {
+			ok = run(strings.TrimSpace(stmt.String()))
+			stmt.Reset()
+		}
+	}
+	// Run a last statement that wasn't terminated with ;.
+	if rest := strings.TrimSpace(stmt.String()); rest != "" && ctx.Err() == nil {
+		ok = run(rest)
+	}
+	if !ok && !interactive {
+		return 1
+	}
+	return 0
+}
+
+// printQueryResult prints res the way psql does: an aligned table with a
+// row count, or just the command tag for statements without rows.
+func printQueryResult(w io.Writer, res *db.QueryResult) {
+	if len(res.Columns) == 0 {
+		if res.Command != "" {
+			fmt.Fprintln(w, res.Command)
+		}
+		return
+	}
+	cells := make([][]string, len(res.Rows))
+	widths := make([]int, len(res.Columns))
+	for i, c := range res.Columns {
+		widths[i] = utf8.RuneCountInString(c)
+	}
+	for r, row := range res.Rows {
+		cells[r] = make([]string, len(res.Columns))
+		for i := range res.Columns {
+			v := ""
+			if i < len(row) {
+				v = queryCell(row[i])
+			}
+			cells[r][i] = v
+			widths[i] = max(widths[i], utf8.RuneCountInString(v))
+		}
+	}
+	pad := func(s string, n int) string {
+		return s + strings.Repeat(" ", n-utf8.RuneCountInString(s))
+	}
+	var header, rule []string
+	for i, c := range res.Columns {
+		header = append(header, " "+pad(c, widths[i])+" ")
+		rule = append(rule, strings.Repeat("-", widths[i]+2))
+	}
+	fmt.Fprintln(w, strings.TrimRight(strings.Join(header, "|"), " "))
+	fmt.Fprintln(w, strings.Join(rule, "+"))
+	for _, row := range cells {
+		var parts []string
+		for i, v := range row {
+			parts = append(parts, " "+pad(v, widths[i])+" ")
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(parts, "|"), " "))
+	}
+	if len(res.Rows) == 1 {
+		fmt.Fprintln(w, "(1 row)")
+	} else {
+		fmt.Fprintf(w, "(%d rows)\n", len(res.Rows))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,81 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+// queryServer answers /databases/mydb/query, failing statements that
+// mention "boom", and records the SQL it was sent.
+func queryServer(t *testing.T, sent *[]string) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/deploy/databases/mydb/query" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body struct{ SQL string }
+		json.NewDecoder(r.Body).Decode(&body)
+		*sent = append(*sent, body.SQL)
+		switch {
+		case strings.Contains(body.SQL, "boom"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error","error":{"code":"SQL_ERROR","message":"syntax error at or near \"boom\""}}`))
+		case strings.HasPrefix(body.SQL, "UPDATE"):
+			w.Write([]byte(`{"command":"UPDATE 2","rows_affected":2}`))
+		default:
+			w.Write([]byte(`{"columns":["id","name"],"rows":[[1,"alice"],[2,null]],"command":"SELECT 2"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestRunDbShellCore(t *testing.T) {
+	var sent []string
+	url := queryServer(t, &sent)
+	in := strings.NewReader("SELECT id, name\n  FROM users;\n\\dt\nUPDATE users SET x = 1;\n\\q\nSELECT 'never';\n")
+	var stdout, stderr bytes.Buffer
+	if code := runDbShellCore(context.Background(), in, &stdout, &stderr, url, "tok", "mydb", false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(sent) != 3 || sent[0] != "SELECT id, name\n  FROM users;" || sent[1] != dbListTablesSQL || sent[2] != "UPDATE users SET x = 1;" {
+		t.Errorf("sent = %q", sent)
+	}
+	out := stdout.String()
+	for _, want := range []string{" id | name", "----+-------", " 1  | alice", "(2 rows)", "UPDATE 2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDbShellCore_ErrorAndUnterminated(t *testing.T) {
+	var sent []string
+	url := queryServer(t, &sent)
+	in := strings.NewReader("SELECT boom;\nSELECT 1")
+	var stdout, stderr bytes.Buffer
+	code := runDbShellCore(context.Background(), in, &stdout, &stderr, url, "tok", "mydb", false)
+	if code != 0 {
+		t.Errorf("exit %d: the last statement succeeded", code)
+	}
+	if len(sent) != 2 || sent[1] != "SELECT 1" {
+		t.Errorf("sent = %q", sent)
+	}
+	if !strings.Contains(stderr.String(), "ERROR: SQL_ERROR") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestPrintQueryResult_CommandOnly(t *testing.T) {
+	var buf bytes.Buffer
+	printQueryResult(&buf, &db.QueryResult{Command: "CREATE TABLE"})
+	if buf.String() != "CREATE TABLE\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
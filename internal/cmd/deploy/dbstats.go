@@ -0,0 +1,128 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var dbStatsCmd = &cobra.Command{
+	Use:   "stats <name>",
+	Short: "Show size and usage of a database",
+	Long: `Shows how big a database is and how it is used: total size, table count,
+the largest tables, open connections and the cache hit ratio. Size and
+connections are shown against the plan's limits when it has them, with a
+warning from 80% on.
+
+Examples:
+  dibbla db stats mydb
+  dibbla db stats mydb --top 20
+  dibbla db stats mydb -o json | jq .size_bytes`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDbStats,
+}
+
+var (
+	dbStatsTop    int
+	dbStatsOutput string
+)
+
+// dbStatsWarnPercent is the share of a plan limit from which `db stats`
+// warns.
+const dbStatsWarnPercent = 80
+
+func init() {
+	dbCmd.AddCommand(dbStatsCmd)
+	dbStatsCmd.Flags().IntVar(&dbStatsTop, "top", 5, "Number of largest tables to show")
+	dbStatsCmd.Flags().StringVarP(&dbStatsOutput, "output", "o", "text", "Output format: text or json")
+}
+
+func runDbStats(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	requireSQLDatabase(cmd.Context(), cfg, args[0], "db stats")
+	os.Exit(runDbStatsCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], dbStatsTop, dbStatsOutput))
+}
+
+// runDbStatsCore is the testable inner implementation of `db stats`.
+// Returns the exit code.
+func runDbStatsCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name string, top int, format string) int {
+	if err := validateDbOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if top < 1 {
+		fmt.Fprintf(stderr, "%s --top must be at least 1\n", platform.Icon("❌", "[X]"))
+		return 1
+	}
+	stats, err := db.GetDatabaseStats(ctx, apiURL, apiToken, name, top)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to get stats for '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	if len(stats.LargestTables) > top {
+		stats.LargestTables = stats.LargestTables[:top]
+	}
+	if format == "json" {
+		if stats.LargestTables == nil {
+			stats.LargestTables = []db.TableStats{}
+		}
+		return writeDbJSON(stdout, stderr, stats)
+	}
+
+	size := formatBytes(float64(stats.SizeBytes))
+	if stats.SizeLimitBytes > 0 {
+		size += fmt.Sprintf(" of %s (%d%%)", formatBytes(float64(stats.SizeLimitBytes)), percentOf(stats.SizeBytes, stats.SizeLimitBytes))
+	}
+	conns := strconv.Itoa(stats.Connections)
+	if stats.MaxConnections > 0 {
+		conns += fmt.Sprintf(" of %d", stats.MaxConnections)
+	}
+	cache := "-"
+	if stats.CacheHitRatio != nil {
+		cache = fmt.Sprintf("%.1f%%", *stats.CacheHitRatio*100)
+	}
+
+	fmt.Fprintf(stdout, "%s Database '%s'\n\n", platform.Icon("📊", "[>]"), name)
+	fmt.Fprintf(stdout, "  Size:         %s\n", size)
+	fmt.Fprintf(stdout, "  Tables:       %d\n", stats.TableCount)
+	fmt.Fprintf(stdout, "  Connections:  %s\n", conns)
+	fmt.Fprintf(stdout, "  Cache hits:   %s\n", cache)
+
+	if len(stats.LargestTables) > 0 {
+		fmt.Fprintln(stdout)
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TABLE\tSIZE\tROWS")
+		for _, t := range stats.LargestTables {
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", t.Name, formatBytes(float64(t.SizeBytes)), t.Rows)
+		}
+		tw.Flush()
+	}
+
+	var warnings []string
+	if stats.SizeLimitBytes > 0 && percentOf(stats.SizeBytes, stats.SizeLimitBytes) >= dbStatsWarnPercent {
+		warnings = append(warnings, fmt.Sprintf("'%s' uses %d%% of its storage limit", name, percentOf(stats.SizeBytes, stats.SizeLimitBytes)))
+	}
+	if stats.MaxConnections > 0 && percentOf(int64(stats.Connections), int64(stats.MaxConnections)) >= dbStatsWarnPercent {
+		warnings = append(warnings, fmt.Sprintf("'%s' has %d of %d connections open", name, stats.Connections, stats.MaxConnections))
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintln(stdout)
+		for _, w := range warnings {
+			fmt.Fprintf(stdout, "%s %s\n", platform.Icon("⚠️", "[!]"), w)
+		}
+	}
+	return 0
+}
+
+// percentOf is n as a whole percentage of limit.
+func percentOf(n, limit int64) int64 {
+	return n * 100 / limit
+}
@@ -0,0 +1,64 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+func statsServer(t *testing.T, stats db.DatabaseStats) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/deploy/databases/mydb/stats" || r.URL.Query().Get("top") != "2" {
+			t.Errorf("unexpected %s", r.URL)
+		}
+		json.NewEncoder(w).Encode(stats)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestRunDbStatsCore_Text(t *testing.T) {
+	ratio := 0.9925
+	url := statsServer(t, db.DatabaseStats{
+		Database: "mydb", SizeBytes: 900 << 20, SizeLimitBytes: 1 << 30, TableCount: 12,
+		LargestTables: []db.TableStats{{Name: "events", SizeBytes: 600 << 20, Rows: 2500000}, {Name: "users", SizeBytes: 200 << 20, Rows: 1200}},
+		Connections:   4, MaxConnections: 100, CacheHitRatio: &ratio,
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runDbStatsCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", 2, "text"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"900.0 MiB of 1.0 GiB (87%)", "Tables:       12", "4 of 100", "99.2%", "events", "600.0 MiB", "2500000", "uses 87% of its storage limit"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "connections open") {
+		t.Errorf("warned about connections:\n%s", out)
+	}
+}
+
+func TestRunDbStatsCore_JSONWithoutLimits(t *testing.T) {
+	url := statsServer(t, db.DatabaseStats{Database: "mydb", SizeBytes: 1024, Connections: 1})
+	var stdout, stderr bytes.Buffer
+	if code := runDbStatsCore(context.Background(), &stdout, &stderr, url, "tok", "mydb", 2, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout.String())
+	}
+	if got["size_bytes"] != 1024.0 || got["largest_tables"] == nil {
+		t.Errorf("got %v", got)
+	}
+	if _, ok := got["cache_hit_ratio"]; ok {
+		t.Errorf("unreported cache hit ratio included: %v", got)
+	}
+}
@@ -0,0 +1,177 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	deploypkg "github.com/dibbla-agents/dibbla-cli/internal/deploy"
+	"github.com/dibbla-agents/dibbla-cli/internal/deploy/render"
+	"github.com/dibbla-agents/dibbla-cli/internal/manifest"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// appDeployResult is one row of the `deploy --all` summary. The JSON shape
+// is what --all --json emits under "apps".
+type appDeployResult struct {
+	Dir   string `json:"dir"`
+	Alias string `json:"alias"`
+	URL   string `json:"url,omitempty"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+
+	// log holds the buffered per-app output in parallel/JSON mode so it
+	// can be replayed for failed apps after the run.
+	log string
+}
+
+// runDeployAll deploys every app DiscoverApps finds under root and prints a
+// per-app result table (or one JSON object under --json). Sequential runs
+// stream each app's normal deploy output under a "==> <dir>" header;
+// parallel runs buffer output per app and replay it only for failures, so
+// concurrent builds don't interleave. Returns 1 if any app failed.
+func runDeployAll(ctx context.Context, cfg *config.Config, root string, parallel int, stdout, stderr io.Writer) int {
+	dirs, err := deploypkg.DiscoverApps(root)
+	if err != nil {
+		fmt.Fprintf(stderr, "✗ %v\n", err)
+		return 1
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+	buffered := parallel > 1 || deployJSON
+
+	results := make([]appDeployResult, len(dirs))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, dir := range dirs {
+		rel, _ := filepath.Rel(root, dir)
+		rel = filepath.ToSlash(rel)
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, dir, rel string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if buffered {
+				var buf bytes.Buffer
+				results[i] = deployApp(ctx, cfg, dir, &buf, render.NewQuiet(&buf))
+				results[i].log = buf.String()
+			} else {
+				fmt.Fprintf(stdout, "\n==> %s\n", rel)
+				results[i] = deployApp(ctx, cfg, dir, stdout, selectRenderer())
+			}
+			results[i].Dir = rel
+		}(i, dir, rel)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, res := range results {
+		if res.OK {
+			continue
+		}
+		failed++
+		if buffered && res.log != "" {
+			fmt.Fprintf(stderr, "\n==> %s\n%s", res.Dir, res.log)
+		}
+	}
+
+	if deployJSON {
+		_ = json.NewEncoder(stdout).Encode(map[string]any{
+			"ok":   failed == 0,
+			"apps": results,
+		})
+	} else {
+		writeDeployAllTable(stdout, results)
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// deployApp runs the single-app deploy flow (review gate, hooks, upload)
+// for dir, writing hook and gate output to out.
+func deployApp(ctx context.Context, cfg *config.Config, dir string, out io.Writer, r render.Renderer) appDeployResult {
+	res := appDeployResult{Alias: filepath.Base(dir)}
+
+	if !deploySkipReview {
+		if missing := checkReviewArtifacts(dir); len(missing) > 0 {
+			writeReviewGateError(out, missing)
+			res.Error = "pre-deploy review gate failed"
+			return res
+		}
+	}
+
+	hooks := &manifest.Hooks{}
+	if !deployNoHooks {
+		h, err := deploypkg.LoadHooks(dir)
+		if err != nil {
+			res.Error = err.Error()
+			return res
+		}
+		hooks = h
+	}
+	if err := deploypkg.RunHooks(deploypkg.HookPreDeploy, hooks.PreDeploy, dir, nil, out); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	capture := &resultCapture{Renderer: r}
+	opts := deployOptions(cfg, dir)
+	opts.Context = ctx
+	started := time.Now()
+	resp, code := runWithRenderer(opts, capture)
+	notifyDeploy(ctx, out, res.Alias, resp, code, capture.errMsg, time.Since(started))
+	if code != 0 {
+		res.Error = capture.errMsg
+		if res.Error == "" {
+			res.Error = fmt.Sprintf("deploy failed (exit %d)", code)
+		}
+		return res
+	}
+	if resp != nil {
+		res.Alias = resp.Deployment.Alias
+		res.URL = resp.Deployment.URL
+	}
+
+	if err := deploypkg.RunHooks(deploypkg.HookPostDeploy, hooks.PostDeploy, dir, deploypkg.PostDeployEnv(resp), out); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.OK = true
+	return res
+}
+
+func writeDeployAllTable(w io.Writer, results []appDeployResult) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%-24s %-20s %-8s %s\n", "APP", "ALIAS", "RESULT", "URL / ERROR")
+	fmt.Fprintf(w, "%-24s %-20s %-8s %s\n", "---", "-----", "------", "-----------")
+	for _, res := range results {
+		status, detail := platform.Icon("✅", "[OK]"), res.URL
+		if !res.OK {
+			status, detail = platform.Icon("❌", "[X]"), res.Error
+		}
+		fmt.Fprintf(w, "%-24s %-20s %-8s %s\n", res.Dir, res.Alias, status, detail)
+	}
+}
+
+// resultCapture records the message of the terminal error event so the
+// --all summary can show why an app failed.
+type resultCapture struct {
+	render.Renderer
+	errMsg string
+}
+
+func (c *resultCapture) OnEvent(ev render.DeployEvent) {
+	if ev.Type == "error" && ev.Error != nil && ev.Error.APIError != nil {
+		c.errMsg = ev.Error.APIError.Code + ": " + ev.Error.APIError.Message
+	}
+	c.Renderer.OnEvent(ev)
+}
@@ -0,0 +1,66 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+)
+
+func TestRunDeployAllPrintsPerAppTable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(50 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		alias := r.FormValue("app_name")
+		w.Header().Set("Content-Type", "application/json")
+		if alias == "broken" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"status":"error","error":{"code":"BUILD_FAILED","message":"no Dockerfile"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status":     "success",
+			"deployment": map[string]any{"id": "dep_" + alias, "alias": alias, "url": "https://" + alias + ".dibbla.com", "status": "running"},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	root := t.TempDir()
+	for _, app := range []string{"api", "broken", "web"} {
+		dir := filepath.Join(root, app)
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deploySkipReview = true
+	t.Cleanup(func() { deploySkipReview = false })
+
+	var stdout, stderr bytes.Buffer
+	code := runDeployAll(context.Background(), &config.Config{APIURL: srv.URL, APIToken: "tok"}, root, 2, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1 with one failing app, got %d", code)
+	}
+	out := stdout.String()
+	for _, want := range []string{"https://api.dibbla.com", "https://web.dibbla.com", "BUILD_FAILED"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(stderr.String(), "==> broken") {
+		t.Errorf("failed app output should be replayed on stderr, got %q", stderr.String())
+	}
+}
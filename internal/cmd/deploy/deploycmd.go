@@ -1,13 +1,22 @@
 package deploy
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
 	"github.com/dibbla-agents/dibbla-cli/internal/config"
 	deploypkg "github.com/dibbla-agents/dibbla-cli/internal/deploy"
 	"github.com/dibbla-agents/dibbla-cli/internal/deploy/render"
+	"github.com/dibbla-agents/dibbla-cli/internal/env"
+	"github.com/dibbla-agents/dibbla-cli/internal/manifest"
+	"github.com/dibbla-agents/dibbla-cli/internal/notify"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 	isatty "github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
@@ -18,9 +27,16 @@ var (
 	deployUpdate          bool
 	deployAlias           string
 	deployEnv             []string
+	deployEnvFiles        []string
+	deployBuildArgs       []string
+	deployBuilder         string
+	deployRegion          string
 	deployCPU             string
 	deployMemory          string
 	deployPort            string
+	deployHealthPath      string
+	deployNoDetect        bool
+	deployReplicas        int
 	deployFavicon         string
 	deployRequireLogin    bool
 	deployAccessPolicy    string
@@ -43,6 +59,86 @@ var (
 	// know what they're doing; coding agents should run the guardrails
 	// workflow and emit REVIEW.md instead.
 	deploySkipReview bool
+
+	// --no-hooks skips the hooks.pre_deploy / hooks.post_deploy commands
+	// declared in dibbla.yaml.
+	deployNoHooks bool
+
+	// --all deploys every app in a monorepo workspace; --parallel caps
+	// how many upload/build at once.
+	deployAll      bool
+	deployParallel int
+
+	// --domain binds a custom domain to the alias after a successful deploy.
+	deployDomain string
+
+	// --schedule deploys the archive as a cron job rather than a
+	// long-running service. Manifest deploys declare top-level jobs: in
+	// dibbla.yaml instead.
+	deploySchedule string
+
+	// --include / --exclude adjust the archive filter for this invocation
+	// only, on top of the built-in exclusions. --include wins.
+	deployInclude []string
+	deployExclude []string
+
+	// --respect-gitignore leaves .gitignore'd paths out of the archive.
+	// Unset (deployRespectGitignoreSet false) defers to
+	// archive.respect_gitignore in dibbla.yaml.
+	deployRespectGitignore    bool
+	deployRespectGitignoreSet bool
+
+	// --follow-symlinks archives the targets of symlinks that point outside
+	// the deploy root instead of skipping them.
+	deployFollowSymlinks bool
+
+	// --no-wait returns as soon as the upload is accepted, printing the
+	// deployment ID for `dibbla deployments status`.
+	deployNoWait bool
+
+	// --force-upload rebuilds even when the server reports the archive's
+	// content hash is already running.
+	deployForceUpload bool
+
+	// --allow-secrets uploads the archive even when the pre-upload scan
+	// finds env files, private keys or tokens in it.
+	deployAllowSecrets bool
+
+	// --output json is --json with failures written to stderr, so stdout
+	// carries nothing but a successful result. "text" is the default.
+	deployOutput     string
+	deployOutputJSON bool
+
+	// --compression / --compression-level pick the archive codec (gzip or
+	// zstd) and its level; 0 means the codec default.
+	deployCompression      string
+	deployCompressionLevel int
+
+	// --strategy canary / --canary-weight send only a share of traffic to
+	// the new revision until `apps promote` or `apps abort-canary`.
+	deployStrategy     string
+	deployCanaryWeight int
+
+	// --notify posts the deploy result to a webhook (Slack or any JSON
+	// endpoint). runDeploy merges in notifications.webhooks from
+	// ~/.dibbla/config.yaml before deploying.
+	deployNotify []string
+
+	// --diff shows the files changed since the last successful deploy
+	// (recorded in .dibbla/state) and asks before uploading.
+	deployDiff bool
+
+	// --preview deploys to an ephemeral alias derived from the git branch.
+	deployPreview bool
+
+	// --archive uploads a pre-built tar.gz ("-" reads stdin) instead of
+	// archiving [path].
+	deployArchive string
+
+	// --retries overrides DIBBLA_RETRIES for the upload. Unset
+	// (deployRetriesSet false) defers to the env var.
+	deployRetries    int
+	deployRetriesSet bool
 )
 
 var deployCmd = &cobra.Command{
@@ -52,13 +148,34 @@ var deployCmd = &cobra.Command{
 
 Your application will be available at https://<alias>.dibbla.com
 
+Archive filter: .git, node_modules, private keys and similar are always left
+out of the upload. --exclude adds globs to that list for one deploy and
+--include re-adds matching paths, overriding both the built-in rules and
+--exclude. Globs are relative to the deploy root, ** spans directories, and
+a pattern without a slash (e.g. *.log) matches at any depth.
+--respect-gitignore (or archive.respect_gitignore: true in dibbla.yaml) also
+leaves out everything the project's .gitignore files ignore, including those
+in parent directories up to the git repository root.
+
+Secret scan: before uploading, the archive is checked for env files (.env,
+.env.local, ...; .env.example and other templates are fine), private key
+blocks, AWS access keys and high-entropy values assigned to names like
+API_KEY or password. Any finding blocks the deploy; leave the files out
+with --exclude, or pass --allow-secrets if they are meant to ship. Run
+'dibbla scan' to check without deploying.
+
 Symlinks: symlinks inside the deploy directory are followed and their content is
 included as regular files in the archive. Symlinks whose target escapes the deploy
 root (including absolute symlinks such as /etc/passwd) are skipped to prevent
-accidentally packaging host files.
+accidentally packaging host files; --follow-symlinks includes their targets
+anyway (e.g. a shared library checked out next to the app). Sockets, FIFOs
+and device files are never archived; each skip is listed on stderr.
 
 Configuration:
   Run dibbla login to store credentials, or set DIBBLA_API_TOKEN (and optionally DIBBLA_API_URL) in your environment or .env file.
+  On deploy, --profile activates manifest profiles from dibbla.yaml. To
+  deploy with a profile from ~/.dibbla/config.yaml (its API URL and token),
+  use --context instead: dibbla deploy --context prod.
 
 Output modes:
   When stdout is a TTY, dibbla streams a live build view with per-step
@@ -68,18 +185,148 @@ Output modes:
   On build failure --verbose-build asks the server to ship the full build
   log instead of relying on parsed compile diagnostics alone.
 
+Hooks:
+  dibbla.yaml may declare hooks.pre_deploy and hooks.post_deploy shell
+  commands, run locally from the deploy root. A failing pre_deploy hook
+  aborts the deploy before upload; post_deploy hooks run only after a
+  successful deploy and see DIBBLA_DEPLOY_URL, DIBBLA_DEPLOY_ALIAS and
+  DIBBLA_DEPLOY_ID. --no-hooks skips both.
+
+Monorepos:
+  --all deploys every app under [path]: the directories listed in
+  workspace.apps of a root dibbla.yaml, or else each immediate
+  subdirectory with a Dockerfile or dibbla.yaml. Each app gets its
+  directory name as alias, and a per-app result table is printed at the
+  end. --parallel N deploys N apps at once (output is buffered per app
+  and shown only for failures).
+
+Project settings:
+  A .dibbla/config.yaml in [path] or a parent sets the project's alias,
+  region, env_file and profile, so each repo deploys to its own app
+  without flags. env_file must be a relative path inside the project; a
+  profile it switches to is named on stderr. Flags (and DIBBLA_PROFILE)
+  win; --all ignores alias and env_file.
+
+Unchanged deploys:
+  Each upload carries a SHA-256 of the archive contents and deploy settings.
+  When the server is already running exactly that, it skips the build and
+  dibbla prints "no changes, skipping" and exits 0. --force-upload (or
+  --force) always rebuilds.
+
+Build args:
+  --build-arg KEY=value (repeatable) sets a Docker build ARG, e.g. a
+  private registry token or a version string. Defaults come from a
+  top-level build_args map in dibbla.yaml; --build-arg overrides it.
+
+Builders:
+  A project with a Dockerfile at [path] is built from it. Without a
+  Dockerfile (and without dibbla.yaml) the platform builds the source with
+  Cloud Native Buildpacks instead. --builder buildpacks or --builder
+  dockerfile picks one explicitly; dibbla builders list shows the
+  buildpack stacks and the languages they support.
+
+Framework detection:
+  Without dibbla.yaml, dibbla recognizes Next.js, Express, Rails, Flask and
+  Go projects from their package files and fills in --port, --health-path
+  and --memory defaults for that framework, printing what it inferred.
+  Only the first deploy of an app gets them, so a redeploy keeps settings
+  changed on the server. Flags you pass win; --no-detect turns detection
+  off. Not applied to --archive, --schedule or --all deploys.
+
+Reviewing changes:
+  Every successful deploy records its files (path and content hash) in
+  .dibbla/state/<alias>.json under [path]; add .dibbla/state/ to .gitignore.
+  --diff lists the files added, modified and removed since then and asks
+  before uploading (no prompt when stdin is not a terminal or under
+  --json / --quiet).
+
+Canary deploys:
+  --strategy canary deploys the new revision next to the running one and
+  routes --canary-weight percent of traffic to it (default 10). Finish with
+  dibbla apps promote <alias>, or roll back with dibbla apps abort-canary
+  <alias>.
+
+Preview deployments:
+  --preview deploys to an ephemeral alias built from the app alias and the
+  current git branch (shop on feature/checkout → shop-feature-checkout).
+  Re-running on the same branch replaces that preview. Previews are listed
+  with dibbla apps list --previews; dibbla apps cleanup-previews deletes the
+  ones whose branch has been merged or deleted.
+
+Compression:
+  --compression zstd compresses the archive with multi-threaded zstd, which
+  is much faster than gzip for large node/go projects. --compression-level
+  trades speed for size (gzip 1-9, zstd 1-22). If the server does not accept
+  zstd the same archive is re-sent as gzip.
+
+Pre-built archives:
+  --archive app.tar.gz uploads an existing tar.gz (or tar.zst) as-is instead of archiving
+  [path]; --archive - reads it from stdin. The archive must contain the
+  Dockerfile (or dibbla.yaml) at its root. [path] still supplies the default
+  alias, hooks and the review gate; the archive filter flags don't apply.
+
+Retries:
+  Connection resets, timeouts and 502/503/504 responses before the build
+  starts are retried with jittered exponential backoff ("retrying (2/3)…"
+  on stderr). --retries N sets the budget for this deploy; DIBBLA_RETRIES
+  sets it for every command (default 3, 0 disables).
+
+Async deploys:
+  --no-wait returns as soon as the server accepts the upload and prints the
+  deployment ID; the build continues server-side. Follow up with
+  dibbla deployments status <id>. post_deploy hooks and --domain need a
+  finished deploy, so they are skipped / not allowed with --no-wait.
+
+Notifications:
+  --notify <url> (repeatable) POSTs a JSON payload (event, alias, url,
+  status, deployment_id, duration_ms, error, text) to the webhook after the
+  deploy succeeds or fails; Slack incoming webhooks work as-is. Webhooks
+  listed under notifications.webhooks in ~/.dibbla/config.yaml are always
+  notified as well. A failed notification is reported on stderr and never
+  changes the exit code. Skipped under --no-wait.
+
+Scheduled jobs:
+  --schedule "<cron>" deploys the app as a job that runs to completion on
+  each tick (standard 5-field cron, UTC) instead of a long-running
+  service. With dibbla.yaml, declare top-level jobs: instead. Use
+  dibbla jobs to list jobs, inspect their runs and trigger one manually.
+
 Examples:
   dibbla deploy              # Deploy current directory
   dibbla deploy ./myapp      # Deploy specific directory
   dibbla deploy --alias my-api  # Deploy with custom alias name
   dibbla deploy -m "feat: add /healthz endpoint"   # Set VCS commit subject
   dibbla deploy --update     # Rolling update (zero downtime)
+  dibbla deploy --context prod  # Deploy to the API of the prod profile
   dibbla deploy --force      # Force redeploy existing alias (causes downtime)
   dibbla deploy --cpu 500m --memory 512Mi --port 3000
+  dibbla deploy --build-arg NPM_TOKEN=$NPM_TOKEN --build-arg VERSION=1.4.2
+  dibbla deploy --builder buildpacks   # Build from source, ignoring the Dockerfile
+  dibbla deploy --health-path /healthz   # Override the detected health check path
+  dibbla deploy --replicas 3
+  dibbla deploy --region us-east   # Run in a specific region (see 'dibbla regions list')
   dibbla deploy -e NODE_ENV=production -e LOG_LEVEL=info
+  dibbla deploy --env-file .env.production -e LOG_LEVEL=debug
   dibbla deploy --favicon https://example.com/favicon.ico
   dibbla deploy --quiet      # Single-line success/failure (script-friendly)
-  dibbla deploy --json       # Structured JSON output for jq / agents`,
+  dibbla deploy --json       # Structured JSON output for jq / agents
+  dibbla deploy -o json      # JSON result on stdout, JSON errors on stderr (CI)
+  dibbla deploy --no-hooks   # Skip dibbla.yaml pre/post deploy hooks
+  dibbla deploy --domain api.example.com   # Bind a custom domain after deploy
+  dibbla deploy --schedule "*/5 * * * *"   # Run as a cron job every 5 minutes
+  dibbla deploy --exclude "dist/**" --include "vendor/**"   # One-off archive filter tweaks
+  dibbla deploy --respect-gitignore   # Skip everything .gitignore ignores
+  dibbla deploy --follow-symlinks     # Include symlinked dirs outside the project
+  dibbla deploy --diff       # Review changed files since the last deploy, then confirm
+  dibbla deploy --strategy canary --canary-weight 10   # 10% of traffic to the new revision
+  dibbla deploy --preview    # Ephemeral <alias>-<branch> preview of this branch
+  dibbla deploy --compression zstd   # Faster archiving for big projects
+  dibbla deploy --archive build/app.tar.gz -a my-api   # Upload a pre-built archive
+  dibbla deploy --notify https://hooks.slack.com/services/T000/B000/XXXX   # Post the result to Slack
+  tar czf - . | dibbla deploy --archive - -a my-api    # ...or stream one on stdin
+  dibbla deploy --no-wait --quiet -m "ci: build 512"   # Print only the deployment ID and return
+  dibbla deploy --all -m "chore: redeploy all"          # Every app in the monorepo
+  dibbla deploy --all --parallel 3 -m "feat: ship v2"   # Three apps at a time`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runDeploy,
 }
@@ -87,11 +334,18 @@ Examples:
 func init() {
 	deployCmd.Flags().BoolVarP(&deployForce, "force", "f", false, "Force redeploy if alias already exists (causes downtime)")
 	deployCmd.Flags().BoolVarP(&deployUpdate, "update", "u", false, "Rolling update of existing deployment (zero downtime)")
-	deployCmd.Flags().StringVarP(&deployAlias, "alias", "a", "", "Custom alias name (default: directory name)")
+	deployCmd.Flags().StringVarP(&deployAlias, "alias", "a", "", "Custom alias name (default: alias in .dibbla/config.yaml, else directory name)")
 	deployCmd.Flags().StringArrayVarP(&deployEnv, "env", "e", nil, "Set env var KEY=value (repeatable)")
+	deployCmd.Flags().StringArrayVar(&deployEnvFiles, "env-file", nil, "Read env vars from a dotenv file (repeatable; -e overrides)")
+	deployCmd.Flags().StringArrayVar(&deployBuildArgs, "build-arg", nil, "Set Docker build ARG KEY=value (repeatable; overrides build_args in dibbla.yaml)")
+	deployCmd.Flags().StringVar(&deployBuilder, "builder", "", "Build with dockerfile or buildpacks (default: buildpacks when there is no Dockerfile; see 'dibbla builders list')")
+	deployCmd.Flags().StringVar(&deployRegion, "region", "", "Region to run in, e.g. eu-west or us-east (see 'dibbla regions list'; default: the project's or profile's region, else the server default)")
 	deployCmd.Flags().StringVar(&deployCPU, "cpu", "", "CPU request (e.g. 500m)")
 	deployCmd.Flags().StringVar(&deployMemory, "memory", "", "Memory request (e.g. 512Mi)")
 	deployCmd.Flags().StringVar(&deployPort, "port", "", "Container port (e.g. 3000)")
+	deployCmd.Flags().StringVar(&deployHealthPath, "health-path", "", "HTTP path probed for readiness (e.g. /healthz)")
+	deployCmd.Flags().BoolVar(&deployNoDetect, "no-detect", false, "Don't infer port, health path and memory from the detected framework")
+	deployCmd.Flags().IntVar(&deployReplicas, "replicas", 0, "Initial number of replicas (default: server default)")
 	deployCmd.Flags().StringVar(&deployFavicon, "favicon", "", "Favicon URL (e.g. https://example.com/favicon.ico)")
 	deployCmd.Flags().BoolVar(&deployRequireLogin, "require-login", false, "Require authentication to access the app")
 	deployCmd.Flags().StringVar(&deployAccessPolicy, "access-policy", "", "Access policy: all_members or invite_only")
@@ -100,18 +354,66 @@ func init() {
 	deployCmd.Flags().StringVarP(&deployMessage, "message", "m", "", "Deploy message, used as the VCS commit subject (e.g. \"fix: handle null user\")")
 	deployCmd.Flags().BoolVar(&deployQuiet, "quiet", false, "Suppress build progress; print one line on success/failure")
 	deployCmd.Flags().BoolVar(&deployJSON, "json", false, "Emit a single structured JSON object on completion")
+	deployCmd.Flags().StringVarP(&deployOutput, "output", "o", "text", "Output format: text or json (json: result on stdout, errors as JSON on stderr)")
 	deployCmd.Flags().BoolVar(&deployVerboseBuild, "verbose-build", false, "On build failure, request the full server build log instead of just the elided tail")
 	deployCmd.Flags().StringVar(&deployTargetEnv, "target-env", "", "Manifest env name to resolve (e.g. prod, staging, dev). Defaults to 'prod' server-side.")
-	deployCmd.Flags().StringArrayVar(&deployProfiles, "profile", nil, "Activate a manifest profile (repeatable)")
+	deployCmd.Flags().StringArrayVar(&deployProfiles, "profile", nil, "Activate a manifest profile from dibbla.yaml (repeatable); the CLI profile is --context")
 	deployCmd.Flags().BoolVar(&deployNoPublic, "no-public", false, "Allow deploy with no public:true service (worker-only)")
 	deployCmd.Flags().BoolVar(&deploySkipReview, "skip-review", false, "Skip the REVIEW.md + handbook pre-deploy gate (use sparingly)")
+	deployCmd.Flags().BoolVar(&deployNoHooks, "no-hooks", false, "Skip hooks.pre_deploy / hooks.post_deploy from dibbla.yaml")
+	deployCmd.Flags().BoolVar(&deployAll, "all", false, "Deploy every app in the workspace at [path] (monorepo)")
+	deployCmd.Flags().IntVar(&deployParallel, "parallel", 1, "With --all, number of apps to deploy concurrently")
 	deployCmd.MarkFlagsMutuallyExclusive("force", "update")
+	deployCmd.Flags().StringVar(&deployDomain, "domain", "", "Bind a custom domain (e.g. api.example.com) to the alias after a successful deploy")
+	deployCmd.Flags().StringVar(&deploySchedule, "schedule", "", "Deploy as a cron job on this schedule (e.g. \"*/5 * * * *\") instead of a long-running service")
+	deployCmd.Flags().StringArrayVar(&deployExclude, "exclude", nil, "Leave paths matching this glob out of the archive (repeatable, e.g. \"dist/**\")")
+	deployCmd.Flags().StringArrayVar(&deployInclude, "include", nil, "Add paths matching this glob back, overriding built-in exclusions and --exclude (repeatable)")
+	deployCmd.Flags().BoolVar(&deployRespectGitignore, "respect-gitignore", false, "Leave paths ignored by .gitignore out of the archive (default: archive.respect_gitignore in dibbla.yaml)")
+	deployCmd.Flags().BoolVar(&deployFollowSymlinks, "follow-symlinks", false, "Archive the targets of symlinks that point outside [path] instead of skipping them")
+	deployCmd.Flags().BoolVar(&deployNoWait, "no-wait", false, "Return once the upload is accepted and print the deployment ID (see 'dibbla deployments status')")
+	deployCmd.Flags().StringVar(&deployStrategy, "strategy", "", "Rollout strategy: canary (see --canary-weight)")
+	deployCmd.Flags().IntVar(&deployCanaryWeight, "canary-weight", 0, "With --strategy canary, percent of traffic for the new revision (1-99, default 10)")
+	deployCmd.Flags().BoolVar(&deployDiff, "diff", false, "Show files added/modified/removed since the last deploy and confirm before uploading")
+	deployCmd.Flags().BoolVar(&deployPreview, "preview", false, "Deploy an ephemeral preview to <alias>-<git-branch> (see 'apps list --previews')")
+	deployCmd.Flags().StringVar(&deployCompression, "compression", deploypkg.CompressionGzip, "Archive compression: gzip or zstd (zstd is faster on large projects)")
+	deployCmd.Flags().IntVar(&deployCompressionLevel, "compression-level", 0, "Compression level (gzip 1-9, zstd 1-22; default: codec default)")
+	deployCmd.Flags().StringVar(&deployArchive, "archive", "", "Upload this pre-built tar.gz instead of archiving [path] (\"-\" reads stdin)")
+	deployCmd.Flags().IntVar(&deployRetries, "retries", apiclient.DefaultRetries, "Retries on connection resets, timeouts and 502/503/504 (0 disables; DIBBLA_RETRIES changes the default)")
+	deployCmd.Flags().StringArrayVar(&deployNotify, "notify", nil, "Post the deploy result as JSON to this webhook URL, e.g. a Slack incoming webhook (repeatable; adds to ~/.dibbla/config.yaml)")
+	deployCmd.Flags().BoolVar(&deployForceUpload, "force-upload", false, "Rebuild even if the same content is already running (skip content-hash dedupe)")
+	deployCmd.Flags().BoolVar(&deployAllowSecrets, "allow-secrets", false, "Upload even if the secret scan finds env files, keys or tokens in the archive (see 'dibbla scan')")
+	deployCmd.MarkFlagsMutuallyExclusive("all", "alias")
+	deployCmd.MarkFlagsMutuallyExclusive("all", "domain")
+	deployCmd.MarkFlagsMutuallyExclusive("schedule", "domain")
+	deployCmd.MarkFlagsMutuallyExclusive("no-wait", "all")
+	deployCmd.MarkFlagsMutuallyExclusive("no-wait", "domain")
+	deployCmd.MarkFlagsMutuallyExclusive("strategy", "force")
+	deployCmd.MarkFlagsMutuallyExclusive("strategy", "all")
+	deployCmd.MarkFlagsMutuallyExclusive("strategy", "preview")
+	deployCmd.MarkFlagsMutuallyExclusive("strategy", "schedule")
+	deployCmd.MarkFlagsMutuallyExclusive("preview", "all")
+	deployCmd.MarkFlagsMutuallyExclusive("preview", "schedule")
+	deployCmd.MarkFlagsMutuallyExclusive("preview", "domain")
+	deployCmd.MarkFlagsMutuallyExclusive("archive", "all")
+	deployCmd.MarkFlagsMutuallyExclusive("archive", "include")
+	deployCmd.MarkFlagsMutuallyExclusive("archive", "exclude")
+	deployCmd.MarkFlagsMutuallyExclusive("archive", "respect-gitignore")
+	deployCmd.MarkFlagsMutuallyExclusive("archive", "follow-symlinks")
+	deployCmd.MarkFlagsMutuallyExclusive("archive", "compression")
+	deployCmd.MarkFlagsMutuallyExclusive("archive", "compression-level")
 	deployCmd.MarkFlagsMutuallyExclusive("quiet", "json")
+	deployCmd.MarkFlagsMutuallyExclusive("quiet", "output")
 }
 
 func runDeploy(cmd *cobra.Command, args []string) {
-	cfg := config.Load()
-	requireToken(cfg)
+	switch deployOutput {
+	case "text":
+	case "json":
+		deployJSON = true
+		deployOutputJSON = true
+	default:
+		failDeploy("invalid --output %q (expected text or json)", deployOutput)
+	}
 
 	path := "."
 	if len(args) > 0 {
@@ -120,57 +422,414 @@ func runDeploy(cmd *cobra.Command, args []string) {
 
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "✗ invalid path: %v\n", err)
-		os.Exit(1)
+		failDeploy("invalid path: %v", err)
 	}
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "✗ directory not found: %s\n", absPath)
-		os.Exit(1)
+		failDeploy("directory not found: %s", absPath)
+	}
+
+	// The settings are those of the project being deployed, which need
+	// not be the one holding the working directory.
+	config.SetProjectDir(absPath)
+	cfg := loadConfig()
+	if deployOutputJSON && !cfg.HasToken() {
+		failDeploy("API token is required: run dibbla login or set DIBBLA_API_TOKEN")
+	}
+	requireToken(cfg)
+
+	deployRespectGitignoreSet = cmd.Flags().Changed("respect-gitignore")
+	deployRetriesSet = cmd.Flags().Changed("retries")
+	if deployRetriesSet && deployRetries < 0 {
+		failDeploy("--retries must be 0 or more")
+	}
+
+	if cmd.Flags().Changed("replicas") && deployReplicas < 1 {
+		failDeploy("--replicas must be at least 1")
+	}
+
+	if deploySchedule != "" {
+		if err := manifest.ValidateSchedule(deploySchedule); err != nil {
+			failDeploy("invalid --schedule: %v", err)
+		}
+	}
+
+	if err := deploypkg.ValidateStrategy(deployStrategy, deployCanaryWeight); err != nil {
+		failDeploy("%v", err)
+	}
+
+	if err := deploypkg.ValidateBuildArgs(deployBuildArgs); err != nil {
+		failDeploy("%v", err)
+	}
+
+	if err := deploypkg.ValidateBuilder(deployBuilder); err != nil {
+		failDeploy("%v", err)
+	}
+
+	for _, w := range deployNotify {
+		if err := config.ValidateWebhookURL(w); err != nil {
+			failDeploy("invalid --notify: %v", err)
+		}
+	}
+	userCfg, err := config.LoadUserConfig()
+	if err != nil {
+		failDeploy("%v", err)
+	}
+	deployNotify = append(userCfg.Notifications.Webhooks, deployNotify...)
+
+	if deployRegion == "" {
+		deployRegion = cfg.Region // the project's or profile's default region, if any
+	}
+	if deployRegion != "" && !apps.RegionRe.MatchString(deployRegion) {
+		failDeploy("invalid --region %q (expected an ID like eu-west; see 'dibbla regions list')", deployRegion)
+	}
+
+	if deployDomain != "" && !apps.DomainRe.MatchString(strings.ToLower(strings.TrimSuffix(deployDomain, "."))) {
+		failDeploy("invalid --domain %q (expected a hostname like api.example.com)", deployDomain)
+	}
+
+	// .dibbla/config.yaml names the project's app and env file; not for
+	// --all, whose apps each need their own alias.
+	if p := cfg.Project; p != nil && !deployAll {
+		if deployAlias == "" {
+			deployAlias = p.Alias
+		}
+		if len(deployEnvFiles) == 0 && p.EnvFile != "" {
+			deployEnvFiles = []string{p.EnvFilePath()}
+		}
+	}
+
+	if len(deployEnvFiles) > 0 {
+		filePairs, err := env.ReadEnvFilePairs(deployEnvFiles)
+		if err != nil {
+			failDeploy("%v", err)
+		}
+		deployEnv = append(filePairs, deployEnv...)
+	}
+
+	if deployAll {
+		os.Exit(runDeployAll(cmd.Context(), cfg, absPath, deployParallel, os.Stdout, os.Stderr))
 	}
 
 	if !deploySkipReview {
 		if missing := checkReviewArtifacts(absPath); len(missing) > 0 {
+			if deployOutputJSON {
+				whats := make([]string, len(missing))
+				for i, m := range missing {
+					whats[i] = m.what
+				}
+				failDeploy("deploy blocked: pre-deploy review incomplete (missing %s); re-run with --skip-review to bypass", strings.Join(whats, ", "))
+			}
 			writeReviewGateError(os.Stderr, missing)
 			os.Exit(1)
 		}
 	}
 
-	r := selectRenderer()
+	hooks := &manifest.Hooks{}
+	if !deployNoHooks {
+		hooks, err = deploypkg.LoadHooks(absPath)
+		if err != nil {
+			failDeploy("%v", err)
+		}
+	}
+	// Hook and custom-domain output goes to stderr under --json so stdout
+	// stays a single parseable object.
+	extraOut := io.Writer(os.Stdout)
+	if deployJSON {
+		extraOut = os.Stderr
+	}
+
+	if len(hooks.PreDeploy) > 0 {
+		if err := deploypkg.RunHooks(deploypkg.HookPreDeploy, hooks.PreDeploy, absPath, nil, extraOut); err != nil {
+			failDeploy("%v\n  deploy aborted; fix the hook or re-run with --no-hooks", err)
+		}
+	}
+
+	opts := deployOptions(cfg, path)
+	opts.Context = cmd.Context()
+	switch deployArchive {
+	case "":
+	case "-":
+		opts.Archive = os.Stdin
+	default:
+		f, err := os.Open(deployArchive)
+		if err != nil {
+			failDeploy("cannot read --archive: %v", err)
+		}
+		defer f.Close()
+		opts.Archive = f
+	}
+	if deployPreview {
+		branch, err := deploypkg.CurrentBranch(absPath)
+		if err != nil {
+			failDeploy("--preview: %v", err)
+		}
+		base := opts.Alias
+		if base == "" {
+			base = filepath.Base(absPath)
+		}
+		opts.PreviewBranch, opts.PreviewOf = branch, base
+		opts.Alias = deploypkg.PreviewAlias(base, branch)
+	}
+	stateAlias := opts.Alias
+	if stateAlias == "" {
+		stateAlias = filepath.Base(absPath)
+	}
+	if !deployNoDetect && deployArchive == "" && deploySchedule == "" {
+		if _, _, found := manifest.Discover(absPath); !found && firstDeploy(cmd.Context(), cfg, stateAlias) {
+			out := extraOut
+			if deployQuiet {
+				out = io.Discard
+			}
+			applyFrameworkDefaults(out, &opts, deploypkg.DetectFramework(absPath))
+		}
+	}
+	if deployBuilder == "" && deployArchive == "" && !deployQuiet &&
+		deploypkg.ResolveBuilder("", absPath) == deploypkg.BuilderBuildpacks {
+		fmt.Fprintln(extraOut, "No Dockerfile found: building with Cloud Native Buildpacks (see 'dibbla builders list')")
+	}
+	var shipped map[string]string
+	opts.OnArchive = func(files map[string]string) error {
+		shipped = files
+		if !deployDiff {
+			return nil
+		}
+		return confirmDiff(extraOut, absPath, stateAlias, files)
+	}
+	if deployNoWait {
+		if len(hooks.PostDeploy) > 0 {
+			fmt.Fprintln(os.Stderr, "note: skipping post_deploy hooks under --no-wait")
+		}
+		if len(deployNotify) > 0 {
+			fmt.Fprintln(os.Stderr, "note: skipping deploy notifications under --no-wait")
+		}
+		os.Exit(runNoWait(opts, os.Stdout, os.Stderr))
+	}
+
+	capture := &resultCapture{Renderer: selectRenderer()}
+	started := time.Now()
+	resp, code := runWithRenderer(opts, capture)
+	notifyDeploy(cmd.Context(), os.Stderr, stateAlias, resp, code, capture.errMsg, time.Since(started))
+	if code == 0 && deployStrategy == deploypkg.StrategyCanary && resp != nil && !deployQuiet {
+		printCanaryNext(extraOut, resp.Deployment.Alias, opts.CanaryWeight)
+	}
+	if code == 0 && deployDomain != "" && resp != nil {
+		if !attachDomain(cmd.Context(), extraOut, cfg, resp.Deployment.Alias, deployDomain) {
+			os.Exit(1)
+		}
+	}
+	if code == 0 && resp != nil && shipped != nil {
+		st := deploypkg.DeployState{
+			Alias:        stateAlias,
+			DeploymentID: resp.Deployment.ID,
+			DeployedAt:   time.Now().UTC(),
+			Files:        shipped,
+		}
+		if err := deploypkg.SaveDeployState(absPath, st); err != nil {
+			fmt.Fprintf(os.Stderr, "note: could not record deploy state for --diff: %v\n", err)
+		}
+	}
+	if code == 0 && len(hooks.PostDeploy) > 0 {
+		if err := deploypkg.RunHooks(deploypkg.HookPostDeploy, hooks.PostDeploy, absPath, deploypkg.PostDeployEnv(resp), extraOut); err != nil {
+			failDeploy("%v", err)
+		}
+	}
+	os.Exit(code)
+}
+
+// notifyDeploy posts the outcome of a deploy to the --notify webhooks (and
+// those from ~/.dibbla/config.yaml). Delivery failures are reported on warn but
+// never change the exit code.
+func notifyDeploy(ctx context.Context, warn io.Writer, alias string, resp *deploypkg.DeployResponse, code int, errMsg string, took time.Duration) {
+	if len(deployNotify) == 0 {
+		return
+	}
+	var appURL, status, id string
+	if resp != nil {
+		if resp.Deployment.Alias != "" {
+			alias = resp.Deployment.Alias
+		}
+		appURL, status, id = resp.Deployment.URL, resp.Deployment.Status, resp.Deployment.ID
+	}
+	if code == 0 {
+		errMsg = ""
+	} else if errMsg == "" {
+		errMsg = fmt.Sprintf("deploy failed (exit %d)", code)
+	}
+	notify.SendAll(ctx, deployNotify, notify.NewDeployEvent(alias, appURL, status, id, errMsg, took), warn)
+}
+
+// firstDeploy reports whether alias has never been deployed. Framework
+// defaults apply only then: on a redeploy they would overwrite settings
+// changed on the server since. When the API can't tell, it isn't.
+func firstDeploy(ctx context.Context, cfg *config.Config, alias string) bool {
+	exists, err := apps.AppExists(ctx, cfg.APIURL, cfg.APIToken, alias)
+	return err == nil && !exists
+}
+
+// applyFrameworkDefaults fills the port, health path and memory that fw
+// implies into opts wherever no flag set them, and reports what it filled.
+func applyFrameworkDefaults(w io.Writer, opts *deploypkg.Options, fw *deploypkg.Framework) {
+	if fw == nil {
+		return
+	}
+	var inferred []string
+	set := func(flag string, dst *string, val string) {
+		if *dst == "" && val != "" {
+			*dst = val
+			inferred = append(inferred, "--"+flag+" "+val)
+		}
+	}
+	set("port", &opts.Port, fw.Port)
+	set("health-path", &opts.HealthPath, fw.HealthPath)
+	set("memory", &opts.Memory, fw.Memory)
+	if len(inferred) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Detected %s: using %s (override with the flag, or --no-detect)\n", fw.Name, strings.Join(inferred, " "))
+}
 
+// confirmDiff prints the files that changed since the last recorded deploy
+// of alias and, on an interactive terminal, asks whether to go ahead.
+func confirmDiff(w io.Writer, root, alias string, files map[string]string) error {
+	prev, err := deploypkg.LoadDeployState(root, alias)
+	if err != nil {
+		return err
+	}
+	if prev == nil {
+		fmt.Fprintf(w, "No previous deploy of %s recorded in .dibbla/state; all %d files are new.\n", alias, len(files))
+	} else {
+		writeFileDiff(w, prev, deploypkg.DiffFiles(prev.Files, files))
+	}
+	if deployJSON || deployQuiet || !isatty.IsTerminal(os.Stdin.Fd()) {
+		return nil
+	}
+	if !askConfirm("Deploy these changes?") {
+		return fmt.Errorf("deploy cancelled at the --diff prompt")
+	}
+	return nil
+}
+
+func writeFileDiff(w io.Writer, prev *deploypkg.DeployState, d deploypkg.FileDiff) {
+	since := prev.DeployedAt.Local().Format("2006-01-02 15:04")
+	if prev.DeploymentID != "" {
+		since += ", " + prev.DeploymentID
+	}
+	if d.Empty() {
+		fmt.Fprintf(w, "No file changes since the last deploy of %s (%s).\n", prev.Alias, since)
+		return
+	}
+	fmt.Fprintf(w, "Changes since the last deploy of %s (%s):\n", prev.Alias, since)
+	for _, p := range d.Added {
+		fmt.Fprintf(w, "  + %s\n", p)
+	}
+	for _, p := range d.Modified {
+		fmt.Fprintf(w, "  ~ %s\n", p)
+	}
+	for _, p := range d.Removed {
+		fmt.Fprintf(w, "  - %s\n", p)
+	}
+	fmt.Fprintf(w, "%d added, %d modified, %d removed\n", len(d.Added), len(d.Modified), len(d.Removed))
+}
+
+// failDeploy reports a CLI-side deploy error and exits 1: "✗ msg" on
+// stderr, or under --output json the same deploy.failed object the
+// renderer emits for server errors.
+func failDeploy(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if deployOutputJSON {
+		render.WriteFailure(os.Stderr, &render.DeployError{
+			APIError: &render.APIError{Code: "CLI_ERROR", Message: msg},
+		})
+	} else {
+		fmt.Fprintf(os.Stderr, "✗ %s\n", msg)
+	}
+	os.Exit(1)
+}
+
+// attachDomain binds domain to alias and prints the DNS records the user
+// must create. The deploy itself already succeeded, so a failure here is
+// reported with the live URL left intact.
+func attachDomain(ctx context.Context, w io.Writer, cfg *config.Config, alias, domain string) bool {
+	b, err := apps.AttachDomain(ctx, cfg.APIURL, cfg.APIToken, alias, domain)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "✗ deploy succeeded, but binding %s to %s failed: %v\n", domain, alias, err)
+		return false
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s Custom domain %s bound to %s (%s)\n", platform.Icon("🔗", "[>]"), b.Domain, alias, b.Status)
+	if len(b.DNSRecords) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Create these DNS records at your DNS provider:")
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "  %-8s %-40s %s\n", "TYPE", "NAME", "VALUE")
+		for _, rec := range b.DNSRecords {
+			fmt.Fprintf(w, "  %-8s %-40s %s\n", rec.Type, rec.Name, rec.Value)
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Once DNS resolves, a TLS certificate is issued automatically and https://%s goes live.\n", b.Domain)
+	}
+	if b.Message != "" {
+		fmt.Fprintln(w, b.Message)
+	}
+	return true
+}
+
+// deployOptions maps the deploy flags onto deploypkg.Options for the
+// project at path. Shared by single-app and --all deploys.
+func deployOptions(cfg *config.Config, path string) deploypkg.Options {
 	opts := deploypkg.Options{
-		APIURL:          cfg.APIURL,
-		APIToken:        cfg.APIToken,
-		Path:            path,
-		Force:           deployForce,
-		Update:          deployUpdate,
-		Alias:           deployAlias,
-		Env:             deployEnv,
-		CPU:             deployCPU,
-		Memory:          deployMemory,
-		Port:            deployPort,
-		FaviconURL:      deployFavicon,
-		RequireLogin:    deployRequireLogin,
-		AccessPolicy:    deployAccessPolicy,
-		GoogleScopes:    deployGoogleScopes,
-		MicrosoftScopes: deployMicrosoftScopes,
-		Message:         deployMessage,
-		VerboseBuild:    deployVerboseBuild,
-		TargetEnv:       deployTargetEnv,
-		Profiles:        deployProfiles,
-		NoPublic:        deployNoPublic,
-	}
-
-	os.Exit(runWithRenderer(opts, r))
+		APIURL:           cfg.APIURL,
+		APIToken:         cfg.APIToken,
+		Path:             path,
+		Force:            deployForce,
+		Update:           deployUpdate,
+		Alias:            deployAlias,
+		Env:              deployEnv,
+		CPU:              deployCPU,
+		Memory:           deployMemory,
+		Port:             deployPort,
+		HealthPath:       deployHealthPath,
+		Replicas:         deployReplicas,
+		Schedule:         deploySchedule,
+		BuildArgs:        deployBuildArgs,
+		Builder:          deployBuilder,
+		Region:           deployRegion,
+		FaviconURL:       deployFavicon,
+		RequireLogin:     deployRequireLogin,
+		AccessPolicy:     deployAccessPolicy,
+		GoogleScopes:     deployGoogleScopes,
+		MicrosoftScopes:  deployMicrosoftScopes,
+		Message:          deployMessage,
+		VerboseBuild:     deployVerboseBuild,
+		TargetEnv:        deployTargetEnv,
+		Profiles:         deployProfiles,
+		NoPublic:         deployNoPublic,
+		Compression:      deployCompression,
+		CompressionLevel: deployCompressionLevel,
+		Include:          deployInclude,
+		Exclude:          deployExclude,
+		FollowSymlinks:   deployFollowSymlinks,
+		ForceUpload:      deployForceUpload,
+		AllowSecrets:     deployAllowSecrets,
+	}
+	if deployRespectGitignoreSet {
+		opts.RespectGitignore = &deployRespectGitignore
+	}
+	if deployRetriesSet {
+		opts.Retries = &deployRetries
+	}
+	return opts
 }
 
 // runWithRenderer executes the deploy and guarantees the renderer sees a
 // terminal event. deploy.Run can fail before the server stream produces
 // one — local manifest validation, archive creation, the 50 MB limit,
 // network errors before NDJSON negotiation — and without this the error
-// would never render and the process would exit 0.
-func runWithRenderer(opts deploypkg.Options, r render.Renderer) int {
+// would never render and the process would exit 0. The deploy response is
+// returned alongside the exit code so post-deploy hooks can use it.
+func runWithRenderer(opts deploypkg.Options, r render.Renderer) (*deploypkg.DeployResponse, int) {
 	tr := &terminalTracking{Renderer: r}
-	_, err := deploypkg.Run(opts, tr)
+	resp, err := deploypkg.Run(opts, tr)
 	if err != nil && !tr.sawTerminal {
 		tr.OnEvent(render.DeployEvent{
 			Type: "error",
@@ -179,7 +838,7 @@ func runWithRenderer(opts deploypkg.Options, r render.Renderer) int {
 			},
 		})
 	}
-	return r.OnDone()
+	return resp, r.OnDone()
 }
 
 // terminalTracking wraps a Renderer and records whether a terminal event
@@ -198,12 +857,14 @@ func (t *terminalTracking) OnEvent(ev render.DeployEvent) {
 }
 
 // selectRenderer picks an output renderer based on flags and stdout type.
-// Order: --json > --quiet > TTY (interactive) > log (CI / piped).
+// Order: --output json > --json > --quiet > TTY (interactive) > log (CI / piped).
 // platform.IsCI is used as a belt-and-braces fallback so that explicit CI
 // env vars force the log renderer even if isatty is fooled by an
 // allocated pty (some CI runners do this).
 func selectRenderer() render.Renderer {
 	switch {
+	case deployOutputJSON:
+		return render.NewJSONSplit(os.Stdout, os.Stderr)
 	case deployJSON:
 		return render.NewJSON(os.Stdout)
 	case deployQuiet:
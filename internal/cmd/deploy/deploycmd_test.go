@@ -22,7 +22,7 @@ func TestRunWithRendererPreStreamFailure(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	code := runWithRenderer(deploypkg.Options{Path: dir}, render.NewQuiet(&out))
+	_, code := runWithRenderer(deploypkg.Options{Path: dir}, render.NewQuiet(&out))
 
 	if code == 0 {
 		t.Fatalf("expected non-zero exit code, got 0; output: %q", out.String())
@@ -50,3 +50,25 @@ func TestTerminalTracking(t *testing.T) {
 		t.Fatal("error event must count as terminal")
 	}
 }
+
+// Framework defaults fill only what no flag set, and report just those.
+func TestApplyFrameworkDefaults(t *testing.T) {
+	var out bytes.Buffer
+	opts := deploypkg.Options{Port: "4000"}
+	fw := &deploypkg.Framework{Name: "Next.js", Port: "3000", HealthPath: "/", Memory: "1Gi"}
+	applyFrameworkDefaults(&out, &opts, fw)
+
+	if opts.Port != "4000" || opts.HealthPath != "/" || opts.Memory != "1Gi" {
+		t.Errorf("unexpected opts: port=%q health=%q memory=%q", opts.Port, opts.HealthPath, opts.Memory)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Detected Next.js") || !strings.Contains(got, "--health-path / --memory 1Gi") || strings.Contains(got, "--port") {
+		t.Errorf("unexpected report: %q", got)
+	}
+
+	out.Reset()
+	applyFrameworkDefaults(&out, &opts, fw)
+	if out.Len() != 0 {
+		t.Errorf("nothing inferred should print nothing, got %q", out.String())
+	}
+}
@@ -0,0 +1,128 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	deploypkg "github.com/dibbla-agents/dibbla-cli/internal/deploy"
+	"github.com/dibbla-agents/dibbla-cli/internal/deploy/render"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var deploymentsCmd = &cobra.Command{
+	Use:   "deployments",
+	Short: "Inspect individual deploys",
+	Long:  `Commands for following up on a single deploy by its deployment ID (as printed by dibbla deploy --no-wait).`,
+}
+
+var deploymentsStatusCmd = &cobra.Command{
+	Use:   "status <id>",
+	Short: "Show the status of a deployment",
+	Long: `Show the current status of a deployment by ID.
+
+Exit codes make it usable from CI polling loops:
+  0  the deployment is running, or still in progress (received, building, ...)
+  1  the deployment failed or is unhealthy, or the request failed
+
+Examples:
+  dibbla deployments status dep_123
+  dibbla deployments status dep_123 --json | jq -r .deployment.status`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDeploymentsStatus,
+}
+
+var deploymentsStatusJSON bool
+
+func init() {
+	deploymentsCmd.AddCommand(deploymentsStatusCmd)
+	deploymentsStatusCmd.Flags().BoolVar(&deploymentsStatusJSON, "json", false, "Print the JSON response body")
+}
+
+func runDeploymentsStatus(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runDeploymentsStatusCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], deploymentsStatusJSON))
+}
+
+// runDeploymentsStatusCore is the testable inner implementation of
+// `deployments status`. Returns the exit code.
+func runDeploymentsStatusCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, id string, jsonOut bool) int {
+	st, err := deploypkg.GetDeploymentStatus(ctx, apiURL, apiToken, id)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s status lookup failed: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	failed := st.Deployment.Status == "failed" || st.Deployment.Status == "unhealthy"
+
+	if jsonOut {
+		_ = json.NewEncoder(stdout).Encode(st)
+	} else {
+		d := st.Deployment
+		fmt.Fprintf(stdout, "   ID:     %s\n", d.ID)
+		fmt.Fprintf(stdout, "   Alias:  %s\n", d.Alias)
+		fmt.Fprintf(stdout, "   URL:    %s\n", d.URL)
+		fmt.Fprintf(stdout, "   Status: %s\n", d.Status)
+		if d.DeployedAt != "" {
+			fmt.Fprintf(stdout, "   Deployed at: %s\n", d.DeployedAt)
+		}
+		for _, svc := range d.Services {
+			fmt.Fprintf(stdout, "   - %s: %d/%d ready\n", svc.Name, svc.ReadyReplicas, svc.Replicas)
+		}
+		if st.Error != nil {
+			fmt.Fprintf(stdout, "   Error:  %s: %s\n", st.Error.Code, st.Error.Message)
+		}
+		if !deploypkg.IsTerminalStatus(d.Status) {
+			fmt.Fprintf(stdout, "\nStill in progress; run 'dibbla deployments status %s' again to check.\n", d.ID)
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// runNoWait uploads without waiting for the build and prints the
+// deployment ID to follow up on with `dibbla deployments status`.
+func runNoWait(opts deploypkg.Options, stdout, stderr io.Writer) int {
+	opts.NoWait = true
+	resp, err := deploypkg.Run(opts, nil)
+	if err != nil {
+		if deployOutputJSON {
+			render.WriteFailure(stderr, &render.DeployError{
+				APIError: &render.APIError{Code: "CLI_ERROR", Message: err.Error()},
+			})
+		} else {
+			fmt.Fprintf(stderr, "✗ %v\n", err)
+		}
+		return 1
+	}
+	d := resp.Deployment
+	unchanged := resp.Status == render.ResultStatusUnchanged
+	switch {
+	case deployJSON:
+		out := map[string]any{
+			"ok":     true,
+			"id":     d.ID,
+			"alias":  d.Alias,
+			"url":    d.URL,
+			"status": d.Status,
+		}
+		if unchanged {
+			out["unchanged"] = true
+		}
+		_ = json.NewEncoder(stdout).Encode(out)
+	case deployQuiet:
+		fmt.Fprintln(stdout, d.ID)
+	case unchanged:
+		fmt.Fprintf(stdout, "✓ %s: no changes, skipping\n", d.Alias)
+	default:
+		fmt.Fprintf(stdout, "✓ Deploy accepted for %s (%s)\n", d.Alias, d.Status)
+		fmt.Fprintf(stdout, "  Deployment ID: %s\n", d.ID)
+		fmt.Fprintf(stdout, "  Check on it with: dibbla deployments status %s\n", d.ID)
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRunDeploymentsStatusCore_InProgress(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"deployment": map[string]any{"id": "dep_1", "alias": "shop", "status": "building"},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runDeploymentsStatusCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "dep_1", false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Still in progress") {
+		t.Errorf("missing in-progress hint: %q", stdout.String())
+	}
+}
+
+func TestRunDeploymentsStatusCore_FailedExitsNonZero(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"deployment": map[string]any{"id": "dep_1", "alias": "shop", "status": "failed"},
+		"error":      map[string]any{"code": "BUILD_FAILED", "message": "npm ci exited 1"},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runDeploymentsStatusCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "dep_1", false); code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "BUILD_FAILED") {
+		t.Errorf("missing error detail: %q", stdout.String())
+	}
+}
+
+func TestRunDeploymentsStatusCore_NotFound(t *testing.T) {
+	srv := newRestartServer(t, http.StatusNotFound, map[string]any{
+		"status": "error", "error": map[string]any{"code": "NOT_FOUND", "message": "no such deployment"},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runDeploymentsStatusCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "dep_x", true); code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "NOT_FOUND") {
+		t.Errorf("stderr: %q", stderr.String())
+	}
+}
@@ -0,0 +1,200 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var appsEditCmd = &cobra.Command{
+	Use:   "edit <alias>",
+	Short: "Edit a deployment's settings in $EDITOR",
+	Long: `Open the editable settings of a deployment (replicas, resources, port,
+region, access, autoscaling and env vars) as YAML in $VISUAL or $EDITOR
+(default vi, notepad on Windows). When the editor exits, only the fields
+you changed are sent, like 'dibbla apps update' with the matching flags.
+
+Saving an invalid file reopens it with the error at the top; saving it
+unchanged, or emptying it, cancels the edit.
+
+The file holds env var values; it is created readable only by you and
+removed afterwards.
+
+Examples:
+  dibbla apps edit myapp
+  EDITOR="code --wait" dibbla apps edit myapp`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAppsEdit,
+}
+
+func init() {
+	appsCmd.AddCommand(appsEditCmd)
+}
+
+func runAppsEdit(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runAppsEditCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], runEditor))
+}
+
+// runEditor opens path in the user's editor and waits for it to exit.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	// $EDITOR may carry arguments, e.g. "code --wait".
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+const editHeader = `# Editing %s. Change the values below, then save and exit to apply.
+# Only changed fields are sent. Remove an env entry to delete the variable.
+# Save unchanged or empty the file to cancel.
+`
+
+// errEditCancelled means the user left the file unchanged or emptied it.
+var errEditCancelled = errors.New("edit cancelled")
+
+// runAppsEditCore is the testable inner implementation of `apps edit`;
+// edit opens the file at the given path for the user. Returns the exit
+// code.
+func runAppsEditCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias string, edit func(path string) error) int {
+	detail, err := apps.GetApp(ctx, apiURL, apiToken, alias)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to fetch '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		return 1
+	}
+	env, err := apps.ListEnv(ctx, apiURL, apiToken, alias, true)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to fetch environment variables of '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		return 1
+	}
+	old := apps.NewEditSpec(detail, env.Variables)
+
+	req, changes, err := editSpec(alias, old, edit)
+	if errors.Is(err, errEditCancelled) {
+		fmt.Fprintln(stdout, "Edit cancelled, no changes made.")
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(stdout, "No changes made.")
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "%s Updating deployment '%s':\n", platform.Icon("✏️", "[UPDATE]"), alias)
+	for _, c := range changes {
+		fmt.Fprintf(stdout, "   %s\n", c)
+	}
+	dep, err := apps.UpdateApp(ctx, apiURL, apiToken, alias, req)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Update failed: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Deployment updated successfully (status: %s).\n", platform.Icon("✅", "[OK]"), dep.Status)
+	return 0
+}
+
+// editSpec writes old to a temp file, lets the user edit it and returns the
+// resulting update. An unparsable or invalid file is reopened with the
+// error on top until it is fixed or left unchanged.
+func editSpec(alias string, old apps.EditSpec, edit func(path string) error) (apps.UpdateDeploymentRequest, []string, error) {
+	body, err := yaml.Marshal(old)
+	if err != nil {
+		return apps.UpdateDeploymentRequest{}, nil, fmt.Errorf("encode spec: %w", err)
+	}
+	// The file holds revealed env values: CreateTemp makes it 0600, and it
+	// is removed on every return and on Ctrl-C.
+	f, err := os.CreateTemp("", "dibbla-edit-"+alias+"-*.yaml")
+	if err != nil {
+		return apps.UpdateDeploymentRequest{}, nil, fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	f.Close()
+	remove := func() { os.Remove(path) }
+	defer interrupt.OnCleanup(remove)()
+	defer remove()
+
+	content := []byte(fmt.Sprintf(editHeader, alias) + string(body))
+	for {
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			return apps.UpdateDeploymentRequest{}, nil, fmt.Errorf("write temp file: %w", err)
+		}
+		if err := edit(path); err != nil {
+			return apps.UpdateDeploymentRequest{}, nil, err
+		}
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return apps.UpdateDeploymentRequest{}, nil, fmt.Errorf("read temp file: %w", err)
+		}
+		if bytes.Equal(edited, content) || len(bytes.TrimSpace(stripComments(edited))) == 0 {
+			return apps.UpdateDeploymentRequest{}, nil, errEditCancelled
+		}
+
+		var spec apps.EditSpec
+		dec := yaml.NewDecoder(bytes.NewReader(edited))
+		dec.KnownFields(true)
+		err = dec.Decode(&spec)
+		var req apps.UpdateDeploymentRequest
+		var changes []string
+		if err == nil {
+			req, changes, err = spec.Diff(old)
+		}
+		if err == nil {
+			return req, changes, nil
+		}
+		content = append([]byte("# Error: "+strings.ReplaceAll(err.Error(), "\n", "\n# ")+"\n"), stripErrorLines(edited)...)
+	}
+}
+
+// stripComments drops # comment lines.
+func stripComments(b []byte) []byte {
+	var out []byte
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("#")) {
+			out = append(out, line...)
+		}
+	}
+	return out
+}
+
+// stripErrorLines drops the "# Error:" lines a previous round added, so
+// errors don't pile up.
+func stripErrorLines(b []byte) []byte {
+	lines := bytes.SplitAfter(b, []byte("\n"))
+	i := 0
+	for i < len(lines) && bytes.HasPrefix(lines[i], []byte("# Error: ")) {
+		i++
+		for i < len(lines) && bytes.HasPrefix(lines[i], []byte("# ")) && !bytes.HasPrefix(lines[i], []byte("# Editing ")) {
+			i++
+		}
+	}
+	return bytes.Join(lines[i:], nil)
+}
@@ -0,0 +1,119 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+// editServer serves one deployment and records the PUT body.
+func editServer(t *testing.T, put *apps.UpdateDeploymentRequest, puts *int) string {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/deploy/deployments/shop", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"alias": "shop", "status": "running", "replicas": 2, "cpu": "500m", "memory": "512Mi", "port": 3000})
+	})
+	mux.HandleFunc("GET /api/deploy/deployments/shop/env", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"alias": "shop", "variables": []map[string]string{{"name": "DEBUG", "value": "1"}}})
+	})
+	mux.HandleFunc("PUT /api/deploy/deployments/shop", func(w http.ResponseWriter, r *http.Request) {
+		*puts++
+		json.NewDecoder(r.Body).Decode(put)
+		json.NewEncoder(w).Encode(map[string]any{"alias": "shop", "status": "running"})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// replaceIn returns an editor that swaps old for new in the file.
+func replaceIn(t *testing.T, old, new string) func(string) error {
+	return func(path string) error {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(b), old) {
+			t.Fatalf("%q not in file:\n%s", old, b)
+		}
+		return os.WriteFile(path, []byte(strings.Replace(string(b), old, new, 1)), 0o600)
+	}
+}
+
+func TestRunAppsEditCore_SendsOnlyChanges(t *testing.T) {
+	var put apps.UpdateDeploymentRequest
+	var puts int
+	url := editServer(t, &put, &puts)
+	var stdout, stderr bytes.Buffer
+	code := runAppsEditCore(context.Background(), &stdout, &stderr, url, "tok", "shop", replaceIn(t, "memory: 512Mi", "memory: 1Gi"))
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if puts != 1 || put.Memory != "1Gi" || put.CPU != "" || put.Replicas != nil || put.EnvironmentVariables != nil {
+		t.Errorf("puts=%d body=%+v", puts, put)
+	}
+	if !strings.Contains(stdout.String(), "memory: 512Mi -> 1Gi") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunAppsEditCore_Unchanged(t *testing.T) {
+	var put apps.UpdateDeploymentRequest
+	var puts int
+	url := editServer(t, &put, &puts)
+	var stdout, stderr bytes.Buffer
+	code := runAppsEditCore(context.Background(), &stdout, &stderr, url, "tok", "shop", func(string) error { return nil })
+	if code != 0 || puts != 0 || !strings.Contains(stdout.String(), "Edit cancelled") {
+		t.Errorf("exit %d puts=%d stdout=%q", code, puts, stdout.String())
+	}
+}
+
+func TestRunAppsEditCore_ReopensOnError(t *testing.T) {
+	var put apps.UpdateDeploymentRequest
+	var puts int
+	url := editServer(t, &put, &puts)
+	rounds := 0
+	edit := func(path string) error {
+		rounds++
+		if rounds == 1 {
+			return replaceIn(t, "port: 3000", "port: 99999")(path)
+		}
+		b, _ := os.ReadFile(path)
+		if !strings.HasPrefix(string(b), "# Error: port must be between 1 and 65535") {
+			t.Errorf("reopened file lacks the error:\n%s", b)
+		}
+		return replaceIn(t, "port: 99999", "port: 8080")(path)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runAppsEditCore(context.Background(), &stdout, &stderr, url, "tok", "shop", edit); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if rounds != 2 || put.Port == nil || *put.Port != 8080 {
+		t.Errorf("rounds=%d body=%+v", rounds, put)
+	}
+}
+
+func TestEditSpec_RemovesTempFile(t *testing.T) {
+	var path string
+	_, _, err := editSpec("shop", apps.EditSpec{}, func(p string) error {
+		path = p
+		if fi, err := os.Stat(p); err != nil || (runtime.GOOS != "windows" && fi.Mode().Perm() != 0o600) {
+			t.Errorf("temp file: %v, %v; want mode 0600", fi, err)
+		}
+		return errors.New("editor failed")
+	})
+	if err == nil || err.Error() != "editor failed" {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("temp file %s left behind: %v", path, err)
+	}
+}
@@ -0,0 +1,202 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/env"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage a deployment's environment variables",
+	Long: `List, set and unset the environment variables of a deployment. Changes
+are merged into the existing variables and the deployment restarts to pick
+them up.
+
+Examples:
+  dibbla apps env list myapp
+  dibbla apps env list myapp --show-values
+  dibbla apps env set myapp NODE_ENV=production LOG_LEVEL=info
+  dibbla apps env set myapp --env-file .env.production
+  dibbla apps env unset myapp DEBUG OLD_FEATURE_FLAG`,
+}
+
+var appsEnvListCmd = &cobra.Command{
+	Use:   "list <alias>",
+	Short: "List environment variables (values masked)",
+	Args:  cobra.ExactArgs(1),
+	Run:   runAppsEnvList,
+}
+
+var appsEnvSetCmd = &cobra.Command{
+	Use:   "set <alias> KEY=value [KEY=value...]",
+	Short: "Set environment variables, keeping the others",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runAppsEnvSet,
+}
+
+var appsEnvUnsetCmd = &cobra.Command{
+	Use:   "unset <alias> KEY [KEY...]",
+	Short: "Remove environment variables",
+	Args:  cobra.MinimumNArgs(2),
+	Run:   runAppsEnvUnset,
+}
+
+var (
+	envShowValues bool
+	envSetFiles   []string
+)
+
+// maskedValue stands in for a variable's value unless --show-values is set.
+const maskedValue = "********"
+
+func init() {
+	appsCmd.AddCommand(appsEnvCmd)
+	appsEnvCmd.AddCommand(appsEnvListCmd)
+	appsEnvCmd.AddCommand(appsEnvSetCmd)
+	appsEnvCmd.AddCommand(appsEnvUnsetCmd)
+	appsEnvListCmd.Flags().BoolVar(&envShowValues, "show-values", false, "Show values instead of masking them")
+	appsEnvSetCmd.Flags().StringArrayVar(&envSetFiles, "env-file", nil, "Read variables from a dotenv file (repeatable; arguments override)")
+}
+
+func runAppsEnvList(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runAppsEnvListCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], envShowValues, appsOutput))
+}
+
+func runAppsEnvSet(cmd *cobra.Command, args []string) {
+	pairs := args[1:]
+	if len(envSetFiles) > 0 {
+		filePairs, err := env.ReadEnvFilePairs(envSetFiles)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+		pairs = append(filePairs, pairs...)
+	}
+	set, err := parseEnvAssignments(pairs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	if len(set) == 0 {
+		fmt.Fprintf(os.Stderr, "%s Nothing to set: pass KEY=value arguments or --env-file\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runAppsEnvPatchCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], apps.EnvPatch{Set: set}, appsOutput))
+}
+
+func runAppsEnvUnset(cmd *cobra.Command, args []string) {
+	for _, k := range args[1:] {
+		if !apps.EnvKeyRe.MatchString(k) {
+			fmt.Fprintf(os.Stderr, "%s invalid variable name %q\n", platform.Icon("❌", "[X]"), k)
+			os.Exit(1)
+		}
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runAppsEnvPatchCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], apps.EnvPatch{Unset: args[1:]}, appsOutput))
+}
+
+// parseEnvAssignments turns KEY=value arguments into a map; later
+// assignments of the same key win.
+func parseEnvAssignments(pairs []string) (map[string]string, error) {
+	set := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || !apps.EnvKeyRe.MatchString(k) {
+			return nil, fmt.Errorf("invalid assignment %q (expected KEY=value)", p)
+		}
+		set[k] = v
+	}
+	return set, nil
+}
+
+// runAppsEnvListCore is the testable inner implementation of
+// `apps env list`. Returns the exit code.
+func runAppsEnvListCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias string, showValues bool, format string) int {
+	if err := validateAppsOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	out, err := apps.ListEnv(ctx, apiURL, apiToken, alias, showValues)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to list environment variables of '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		return 1
+	}
+	if !showValues {
+		for i := range out.Variables {
+			out.Variables[i].Value = ""
+		}
+	}
+	sort.Slice(out.Variables, func(i, j int) bool { return out.Variables[i].Name < out.Variables[j].Name })
+	if format != "table" {
+		return writeAppsOutput(stdout, stderr, format, out)
+	}
+
+	if len(out.Variables) == 0 {
+		fmt.Fprintf(stdout, "No environment variables set on '%s'.\n", alias)
+		return 0
+	}
+	width := len("NAME")
+	for _, v := range out.Variables {
+		width = max(width, len(v.Name))
+	}
+	fmt.Fprintf(stdout, "%-*s  %s\n", width, "NAME", "VALUE")
+	for _, v := range out.Variables {
+		value := maskedValue
+		if showValues {
+			value = v.Value
+		}
+		fmt.Fprintf(stdout, "%-*s  %s\n", width, v.Name, value)
+	}
+	return 0
+}
+
+// runAppsEnvPatchCore is the testable inner implementation of
+// `apps env set` and `apps env unset`. Returns the exit code.
+func runAppsEnvPatchCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias string, patch apps.EnvPatch, format string) int {
+	if err := validateAppsOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	out, err := apps.PatchEnv(ctx, apiURL, apiToken, alias, patch)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to update environment variables of '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		return 1
+	}
+	for i := range out.Variables {
+		out.Variables[i].Value = ""
+	}
+	if format != "table" {
+		return writeAppsOutput(stdout, stderr, format, out)
+	}
+
+	if len(patch.Set) > 0 {
+		keys := make([]string, 0, len(patch.Set))
+		for k := range patch.Set {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(stdout, "%s Set %s on '%s'\n", platform.Icon("✅", "[OK]"), strings.Join(keys, ", "), alias)
+	}
+	if len(patch.Unset) > 0 {
+		fmt.Fprintf(stdout, "%s Removed %s from '%s'\n", platform.Icon("✅", "[OK]"), strings.Join(patch.Unset, ", "), alias)
+	}
+	if out.Message != "" {
+		fmt.Fprintf(stdout, "   %s\n", out.Message)
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func TestRunAppsEnvListCore_MasksValues(t *testing.T) {
+	// A server that returns values even without ?reveal must not leak them.
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"alias":     "shop",
+		"variables": []map[string]any{{"name": "NODE_ENV", "value": "production"}, {"name": "API_KEY", "value": "s3cret"}},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsEnvListCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", false, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	if strings.Contains(out, "s3cret") || strings.Contains(out, "production") {
+		t.Errorf("values leaked:\n%s", out)
+	}
+	if !strings.Contains(out, "API_KEY   ********") || strings.Index(out, "API_KEY") > strings.Index(out, "NODE_ENV") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+}
+
+func TestRunAppsEnvListCore_ShowValues(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"alias": "shop", "variables": []map[string]any{{"name": "NODE_ENV", "value": "production"}},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsEnvListCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", true, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "NODE_ENV  production") {
+		t.Errorf("unexpected table:\n%s", stdout.String())
+	}
+}
+
+func TestRunAppsEnvPatchCore(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{"alias": "shop", "variables": []map[string]any{{"name": "A"}}})
+	var stdout, stderr bytes.Buffer
+	patch := apps.EnvPatch{Set: map[string]string{"B": "2", "A": "1"}}
+	if code := runAppsEnvPatchCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", patch, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Set A, B on 'shop'") {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+}
+
+func TestParseEnvAssignments(t *testing.T) {
+	got, err := parseEnvAssignments([]string{"A=1", "B=x=y", "A=2", "EMPTY="})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got["A"] != "2" || got["B"] != "x=y" || got["EMPTY"] != "" || len(got) != 3 {
+		t.Errorf("got %v", got)
+	}
+	for _, bad := range []string{"NOEQUALS", "=v", "1A=v"} {
+		if _, err := parseEnvAssignments([]string{bad}); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
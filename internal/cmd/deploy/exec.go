@@ -0,0 +1,153 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var appsExecCmd = &cobra.Command{
+	Use:   "exec <alias> -- <command> [args...]",
+	Short: "Run a command inside a running app container",
+	Long: `Run a one-off command inside the running container of a deployment and
+exit with the command's exit code.
+
+Pass -i to forward your stdin to the command and -t to allocate a
+terminal (needed for interactive programs such as editors or REPLs).
+In multi-service deployments, --service picks the container; the public
+service is used by default.
+
+Examples:
+  dibbla apps exec myapp -- ls -la /app
+  dibbla apps exec myapp -- node scripts/migrate.js
+  dibbla apps exec myapp -s worker -- cat /tmp/queue.log
+  cat fix.sql | dibbla apps exec myapp -i -- psql "$DATABASE_URL"
+  dibbla apps exec myapp -it -- python`,
+	Args: cobra.MinimumNArgs(2),
+	Run:  runAppsExec,
+}
+
+var appsShellCmd = &cobra.Command{
+	Use:   "shell <alias>",
+	Short: "Open an interactive shell in a running app container",
+	Long: `Open an interactive shell inside the running container of a deployment.
+bash is used when the image has it, sh otherwise; --shell picks another
+one. Exit the shell (exit or Ctrl-D) to end the session.
+
+Examples:
+  dibbla apps shell myapp
+  dibbla apps shell myapp --service worker
+  dibbla apps shell myapp --shell /bin/zsh`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAppsShell,
+}
+
+var (
+	execService string
+	execStdin   bool
+	execTTY     bool
+	shellPath   string
+)
+
+// defaultShell starts bash when the image has it and falls back to sh.
+var defaultShell = []string{"sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash || exec sh"}
+
+func init() {
+	appsCmd.AddCommand(appsExecCmd)
+	appsCmd.AddCommand(appsShellCmd)
+
+	appsExecCmd.Flags().StringVarP(&execService, "service", "s", "", "Service to run the command in (multi-service deployments)")
+	appsExecCmd.Flags().BoolVarP(&execStdin, "stdin", "i", false, "Forward stdin to the command")
+	appsExecCmd.Flags().BoolVarP(&execTTY, "tty", "t", false, "Allocate a terminal for the command")
+
+	appsShellCmd.Flags().StringVarP(&execService, "service", "s", "", "Service to open the shell in (multi-service deployments)")
+	appsShellCmd.Flags().StringVar(&shellPath, "shell", "", "Shell to run instead of bash/sh")
+}
+
+func runAppsExec(cmd *cobra.Command, args []string) {
+	if dash := cmd.ArgsLenAtDash(); dash != -1 && dash != 1 {
+		fmt.Fprintf(os.Stderr, "%s Usage: dibbla apps exec <alias> -- <command> [args...]\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+	execInContainer(cmd.Context(), args[0], apps.ExecOptions{
+		Command: args[1:],
+		Service: execService,
+		TTY:     execTTY,
+	}, execStdin)
+}
+
+func runAppsShell(cmd *cobra.Command, args []string) {
+	command := defaultShell
+	if shellPath != "" {
+		command = []string{shellPath}
+	}
+	execInContainer(cmd.Context(), args[0], apps.ExecOptions{
+		Command: command,
+		Service: execService,
+		TTY:     true,
+	}, true)
+}
+
+// execInContainer wires the local terminal to an exec session and exits
+// with the remote command's exit code.
+func execInContainer(ctx context.Context, alias string, opts apps.ExecOptions, stdin bool) {
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	var in io.Reader
+	if stdin {
+		in = os.Stdin
+	}
+	if opts.TTY {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			fmt.Fprintf(os.Stderr, "%s A terminal session needs stdin to be a terminal; drop -t (or use 'apps exec -i') when piping input\n", platform.Icon("❌", "[X]"))
+			os.Exit(1)
+		}
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to set up the terminal: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+		restore := func() { _ = term.Restore(fd, state) }
+		removeCleanup := interrupt.OnCleanup(restore)
+
+		resize, stopResize := watchTermSize(int(os.Stdout.Fd()))
+		opts.Resize = resize
+
+		code := runAppsExecCore(ctx, in, os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, alias, opts)
+		stopResize()
+		removeCleanup()
+		restore()
+		os.Exit(code)
+	}
+	os.Exit(runAppsExecCore(ctx, in, os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, alias, opts))
+}
+
+// runAppsExecCore is the testable inner implementation of `apps exec` and
+// `apps shell`. Returns the remote command's exit code, or 1 when the
+// session fails.
+func runAppsExecCore(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, apiURL, apiToken, alias string, opts apps.ExecOptions) int {
+	opts.Stdin = stdin
+	opts.Stdout = stdout
+	opts.Stderr = stderr
+	code, err := apps.Exec(ctx, apiURL, apiToken, alias, opts)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			interrupt.Wait()
+		}
+		fmt.Fprintf(stderr, "%s Failed to exec in '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	return code
+}
@@ -0,0 +1,48 @@
+//go:build !windows
+
+package deploy
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"golang.org/x/term"
+)
+
+// watchTermSize reports the size of the terminal at fd now and after every
+// SIGWINCH until stop is called.
+func watchTermSize(fd int) (sizes <-chan apps.TermSize, stop func()) {
+	ch := make(chan apps.TermSize, 1)
+	winch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(winch, syscall.SIGWINCH)
+
+	send := func() {
+		cols, rows, err := term.GetSize(fd)
+		if err != nil {
+			return
+		}
+		select {
+		case ch <- apps.TermSize{Cols: uint16(cols), Rows: uint16(rows)}:
+		case <-done:
+		}
+	}
+	go func() {
+		defer close(ch)
+		send()
+		for {
+			select {
+			case <-winch:
+				send()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return ch, func() {
+		signal.Stop(winch)
+		close(done)
+	}
+}
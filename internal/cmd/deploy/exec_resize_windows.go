@@ -0,0 +1,19 @@
+//go:build windows
+
+package deploy
+
+import (
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"golang.org/x/term"
+)
+
+// watchTermSize reports the size of the console at fd once; Windows has no
+// SIGWINCH, so later resizes are not forwarded.
+func watchTermSize(fd int) (sizes <-chan apps.TermSize, stop func()) {
+	ch := make(chan apps.TermSize, 1)
+	if cols, rows, err := term.GetSize(fd); err == nil {
+		ch <- apps.TermSize{Cols: uint16(cols), Rows: uint16(rows)}
+	}
+	close(ch)
+	return ch, func() {}
+}
@@ -0,0 +1,51 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/gorilla/websocket"
+)
+
+func TestRunAppsExecCore_ExitCode(t *testing.T) {
+	var sawService string
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawService = r.URL.Query().Get("service")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.BinaryMessage, append([]byte{apps.ExecChannelStdout}, "total 0\n"...))
+		conn.WriteMessage(websocket.BinaryMessage, append([]byte{apps.ExecChannelStatus}, `{"exit_code":2}`...))
+	}))
+	t.Cleanup(srv.Close)
+
+	var stdout, stderr bytes.Buffer
+	code := runAppsExecCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "shop",
+		apps.ExecOptions{Command: []string{"ls"}, Service: "worker"})
+	if code != 2 {
+		t.Errorf("exit %d, want the remote exit code 2 (stderr=%q)", code, stderr.String())
+	}
+	if stdout.String() != "total 0\n" || sawService != "worker" {
+		t.Errorf("stdout=%q service=%q", stdout.String(), sawService)
+	}
+}
+
+func TestRunAppsExecCore_APIError(t *testing.T) {
+	srv := newRestartServer(t, http.StatusNotFound, map[string]any{
+		"status": "error", "error": map[string]any{"code": "NOT_FOUND", "message": "deployment not found"},
+	})
+	var stdout, stderr bytes.Buffer
+	code := runAppsExecCore(context.Background(), nil, &stdout, &stderr, srv.URL, "tok", "nope",
+		apps.ExecOptions{Command: []string{"ls"}})
+	if code != 1 || !strings.Contains(stderr.String(), "Failed to exec in 'nope'") {
+		t.Errorf("exit %d stderr=%q", code, stderr.String())
+	}
+}
@@ -0,0 +1,78 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsCheckCmd = &cobra.Command{
+	Use:   "check [alias]",
+	Short: "Run an app's health check now",
+	Long: `Ask the platform to run the health probe for a deployment immediately and
+report its status, latency and last error, instead of waiting for the next
+scheduled probe. Handy right after fixing an unhealthy app.
+
+Exits with status 1 when the probe fails, so it can gate scripts.
+
+Without an alias, default_deployment is used (dibbla config set
+default_deployment <alias>).
+
+Examples:
+  dibbla apps check myapp
+  dibbla apps check myapp -o json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAppsCheck,
+}
+
+func init() {
+	appsCmd.AddCommand(appsCheckCmd)
+}
+
+func runAppsCheck(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runAppsCheckCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, aliasArg(args), appsOutput))
+}
+
+// runAppsCheckCore runs the health probe for alias and reports it. Returns
+// 0 when the app is healthy, 1 when the probe failed or couldn't run.
+func runAppsCheckCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias, format string) int {
+	if err := validateAppsOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	out, err := apps.CheckHealth(ctx, apiURL, apiToken, alias)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to check '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		return 1
+	}
+	if format != "table" {
+		if code := writeAppsOutput(stdout, stderr, format, out); code != 0 {
+			return code
+		}
+	} else {
+		h := out.HealthCheck
+		icon := platform.Icon("✅", "[OK]")
+		if !out.Healthy() {
+			icon = platform.Icon("❌", "[X]")
+		}
+		fmt.Fprintf(stdout, "%s %s: %s (%dms)\n", icon, alias, h.Status, h.ResponseTimeMs)
+		fmt.Fprintf(stdout, "   Status:   %s\n", out.Status)
+		if h.FailureCount > 0 {
+			fmt.Fprintf(stdout, "   Failures: %d in a row\n", h.FailureCount)
+		}
+		if h.LastError != "" {
+			fmt.Fprintf(stdout, "   Last error: %s\n", h.LastError)
+		}
+	}
+	if !out.Healthy() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRunAppsCheckCore_Healthy(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"alias": "shop", "status": "running",
+		"health_check": map[string]any{"status": "healthy", "response_time_ms": 42},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsCheckCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "shop: healthy (42ms)") || strings.Contains(stdout.String(), "Last error") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunAppsCheckCore_Unhealthy(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"alias": "shop", "status": "unhealthy",
+		"health_check": map[string]any{"status": "unhealthy", "response_time_ms": 5000, "failure_count": 2, "last_error": "GET / returned 502"},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsCheckCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", "json"); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if !strings.Contains(stdout.String(), `"last_error": "GET / returned 502"`) {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
@@ -0,0 +1,190 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var appsInspectCmd = &cobra.Command{
+	Use:   "inspect [alias]",
+	Short: "Show the full record of a deployment",
+	Long: `Show everything the platform knows about a deployment: IDs, image and
+container, resources and replicas, access settings, env var names (values
+are never shown), health check history and recent errors.
+
+Without an alias, default_deployment is used (dibbla config set
+default_deployment <alias>).
+
+Examples:
+  dibbla apps inspect myapp
+  dibbla apps inspect myapp -o json | jq -r .image_id
+  dibbla apps inspect myapp -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAppsInspect,
+}
+
+var inspectOutput string
+
+func init() {
+	appsCmd.AddCommand(appsInspectCmd)
+	appsInspectCmd.Flags().StringVarP(&inspectOutput, "output", "o", "text", "Output format: text, json or yaml")
+}
+
+func runAppsInspect(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runAppsInspectCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, aliasArg(args), inspectOutput))
+}
+
+// runAppsInspectCore is the testable inner implementation of
+// `apps inspect`. Returns the exit code.
+func runAppsInspectCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias, format string) int {
+	switch format {
+	case "text", "json", "yaml":
+	default:
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected text, json or yaml)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+
+	d, err := apps.GetApp(ctx, apiURL, apiToken, alias)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to inspect '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		return 1
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(d)
+	case "yaml":
+		if err := writeYAML(stdout, d); err != nil {
+			fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+	default:
+		writeInspectText(stdout, d)
+	}
+	return 0
+}
+
+// writeYAML renders v as YAML under its JSON field names, so -o yaml and
+// -o json agree on keys (yaml.v3 would otherwise ignore the json tags).
+func writeYAML(w io.Writer, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+func writeInspectText(w io.Writer, d *apps.DeploymentDetail) {
+	row := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%-15s %s\n", label+":", value)
+		}
+	}
+	row("Alias", d.Alias)
+	row("ID", d.ID)
+	row("URL", d.URL)
+	row("Status", string(d.Status))
+	row("Region", d.Region)
+	if d.Preview {
+		row("Preview of", fmt.Sprintf("%s (branch %s)", d.PreviewOf, d.PreviewBranch))
+	}
+	row("Created", formatInspectTime(d.CreatedAt))
+	row("Updated", formatInspectTime(d.UpdatedAt))
+	if d.DeployedAt != nil {
+		row("Last deployed", formatInspectTime(*d.DeployedAt))
+	}
+	row("Image", d.ImageID)
+	row("Container", d.ContainerID)
+	row("Error", d.Error)
+
+	fmt.Fprintln(w)
+	row("Replicas", fmt.Sprintf("%d/%d ready", d.ReadyReplicas, d.Replicas))
+	if a := d.Autoscaler; a != nil && a.Enabled {
+		row("Autoscaling", fmt.Sprintf("%d-%d replicas, target %d%% CPU", a.MinReplicas, a.MaxReplicas, a.TargetCPUPercent))
+		state := fmt.Sprintf("%d current, %d desired", a.CurrentReplicas, a.DesiredReplicas)
+		if a.CurrentCPUPercent != nil {
+			state += fmt.Sprintf(", CPU at %d%%", *a.CurrentCPUPercent)
+		}
+		if a.LastScaleTime != nil {
+			state += ", last scaled " + formatInspectTime(*a.LastScaleTime)
+		}
+		row("Autoscaler", state)
+	}
+	row("CPU", d.CPU)
+	row("Memory", d.Memory)
+	if d.Port > 0 {
+		row("Port", fmt.Sprint(d.Port))
+	}
+	access := "public"
+	if d.RequireLogin {
+		access = "login required"
+		if d.AppAccessPolicy != "" {
+			access += " (" + d.AppAccessPolicy + ")"
+		}
+	}
+	row("Access", access)
+	row("Google scopes", strings.Join(d.GoogleScopes, ", "))
+	row("MS scopes", strings.Join(d.MicrosoftScopes, ", "))
+
+	fmt.Fprintln(w)
+	if len(d.EnvVarNames) == 0 {
+		row("Env vars", "(none)")
+	} else {
+		row("Env vars", strings.Join(d.EnvVarNames, ", ")+" (values hidden)")
+	}
+
+	if d.HealthCheck != nil {
+		h := d.HealthCheck
+		fmt.Fprintln(w)
+		row("Health", fmt.Sprintf("%s (%dms, %d failure(s), checked %s)", h.Status, h.ResponseTimeMs, h.FailureCount, formatInspectTime(h.CheckedAt)))
+		row("Last error", h.LastError)
+	}
+	if len(d.HealthHistory) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Health history:")
+		for _, h := range d.HealthHistory {
+			line := fmt.Sprintf("  %-20s %-10s %5dms", formatInspectTime(h.CheckedAt), h.Status, h.ResponseTimeMs)
+			if h.LastError != "" {
+				line += "  " + h.LastError
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	if len(d.RecentErrors) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Recent errors:")
+		for _, e := range d.RecentErrors {
+			fmt.Fprintf(w, "  %-20s %s: %s\n", formatInspectTime(e.At), e.Code, e.Message)
+		}
+	}
+}
+
+func formatInspectTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,69 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func inspectServer(t *testing.T) string {
+	return newRestartServer(t, http.StatusOK, map[string]any{
+		"id": "dep_1", "alias": "shop", "status": "running", "image_id": "sha256:abc",
+		"container_id": "c0ffee", "cpu": "500m", "memory": "512Mi", "port": 3000,
+		"replicas": 2, "ready_replicas": 2,
+		"env_var_names":  []string{"DATABASE_URL", "NODE_ENV"},
+		"health_history": []map[string]any{{"status": "healthy", "response_time_ms": 12, "checked_at": "2026-01-01T00:00:00Z"}},
+		"recent_errors":  []map[string]any{{"at": "2026-01-01T00:00:00Z", "code": "HEALTH_CHECK_FAILED", "message": "timeout"}},
+	}).URL
+}
+
+func TestRunAppsInspectCore_Text(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runAppsInspectCore(context.Background(), &stdout, &stderr, inspectServer(t), "tok", "shop", "text"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"sha256:abc", "c0ffee", "2/2 ready", "DATABASE_URL, NODE_ENV (values hidden)", "Health history:", "HEALTH_CHECK_FAILED: timeout"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunAppsInspectCore_YAMLUsesJSONKeys(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runAppsInspectCore(context.Background(), &stdout, &stderr, inspectServer(t), "tok", "shop", "yaml"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "image_id: sha256:abc") {
+		t.Errorf("yaml output:\n%s", stdout.String())
+	}
+}
+
+func TestRunAppsInspectCore_BadOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runAppsInspectCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "shop", "xml"); code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+}
+
+func TestRunAppsInspectCore_Autoscaler(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"alias": "shop", "status": "running", "replicas": 4, "ready_replicas": 4,
+		"autoscaler": map[string]any{
+			"enabled": true, "min_replicas": 2, "max_replicas": 10, "target_cpu_percent": 70,
+			"current_cpu_percent": 82, "current_replicas": 4, "desired_replicas": 5,
+		},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsInspectCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", "text"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	for _, want := range []string{"2-10 replicas, target 70% CPU", "4 current, 5 desired, CPU at 82%"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, stdout.String())
+		}
+	}
+}
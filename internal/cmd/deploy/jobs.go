@@ -0,0 +1,139 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Manage scheduled jobs",
+	Long: `List scheduled (cron) jobs, inspect their runs, and trigger runs manually.
+
+Jobs are created with dibbla deploy --schedule "<cron>", or declared under
+the top-level jobs: key of dibbla.yaml.`,
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled jobs",
+	Run:   runJobsList,
+}
+
+var jobsRunsCmd = &cobra.Command{
+	Use:   "runs <alias>",
+	Short: "Show recent runs of a job",
+	Args:  cobra.ExactArgs(1),
+	Run:   runJobsRuns,
+}
+
+var jobsTriggerCmd = &cobra.Command{
+	Use:   "trigger <alias>",
+	Short: "Run a job now, outside its schedule",
+	Long: `Start a run of a scheduled job immediately. The job's schedule is unchanged.
+
+Examples:
+  dibbla jobs trigger nightly-report
+  dibbla jobs trigger myapp --job nightly-cleanup`,
+	Args: cobra.ExactArgs(1),
+	Run:  runJobsTrigger,
+}
+
+var (
+	jobsName  string
+	jobsLimit int
+)
+
+func init() {
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsRunsCmd)
+	jobsCmd.AddCommand(jobsTriggerCmd)
+	jobsRunsCmd.Flags().StringVarP(&jobsName, "job", "j", "", "Only show runs of this job (deployments with several jobs)")
+	jobsRunsCmd.Flags().IntVar(&jobsLimit, "limit", 20, "Maximum number of runs to show")
+	jobsTriggerCmd.Flags().StringVarP(&jobsName, "job", "j", "", "Job to run (required if the deployment declares several)")
+}
+
+func runJobsList(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	out, err := apps.ListJobs(cmd.Context(), cfg.APIURL, cfg.APIToken)
+	if err != nil {
+		fmt.Printf("%s Failed to list jobs: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	if len(out.Jobs) == 0 {
+		fmt.Println("No scheduled jobs. Deploy one with: dibbla deploy --schedule \"*/5 * * * *\"")
+		return
+	}
+
+	fmt.Printf("%-20s %-12s %-16s %-10s %-22s %s\n", "ALIAS", "JOB", "SCHEDULE", "STATUS", "LAST RUN", "NEXT RUN")
+	fmt.Printf("%-20s %-12s %-16s %-10s %-22s %s\n", "-----", "---", "--------", "------", "--------", "--------")
+	for _, j := range out.Jobs {
+		last := formatJobTime(j.LastRunAt)
+		if j.LastRunStatus != "" {
+			last += " (" + j.LastRunStatus + ")"
+		}
+		fmt.Printf("%-20s %-12s %-16s %-10s %-22s %s\n", j.Alias, j.Name, j.Schedule, j.Status, last, formatJobTime(j.NextRunAt))
+	}
+}
+
+func runJobsRuns(cmd *cobra.Command, args []string) {
+	alias := args[0]
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	out, err := apps.ListJobRuns(cmd.Context(), cfg.APIURL, cfg.APIToken, alias, jobsName, jobsLimit)
+	if err != nil {
+		fmt.Printf("%s Failed to list runs for '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		os.Exit(1)
+	}
+	if len(out.Runs) == 0 {
+		fmt.Printf("No runs yet for '%s'.\n", alias)
+		return
+	}
+
+	fmt.Printf("%-24s %-12s %-10s %-9s %-20s %-10s %s\n", "RUN ID", "JOB", "STATUS", "TRIGGER", "STARTED", "DURATION", "EXIT")
+	fmt.Printf("%-24s %-12s %-10s %-9s %-20s %-10s %s\n", "------", "---", "------", "-------", "-------", "--------", "----")
+	for _, r := range out.Runs {
+		duration, exit := "-", "-"
+		if r.StartedAt != nil && r.FinishedAt != nil {
+			duration = r.FinishedAt.Sub(*r.StartedAt).Round(time.Second).String()
+		}
+		if r.ExitCode != nil {
+			exit = fmt.Sprint(*r.ExitCode)
+		}
+		fmt.Printf("%-24s %-12s %-10s %-9s %-20s %-10s %s\n", r.ID, r.Job, r.Status, r.Trigger, formatJobTime(r.StartedAt), duration, exit)
+	}
+}
+
+func runJobsTrigger(cmd *cobra.Command, args []string) {
+	alias := args[0]
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	if jobsName != "" && !apps.ServiceNameRe.MatchString(jobsName) {
+		fmt.Printf("%s job name %q does not match %s\n", platform.Icon("❌", "[X]"), jobsName, apps.ServiceNameRe.String())
+		os.Exit(1)
+	}
+
+	run, err := apps.TriggerJob(cmd.Context(), cfg.APIURL, cfg.APIToken, alias, jobsName)
+	if err != nil {
+		fmt.Printf("%s Failed to trigger '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s Run %s started for %s (%s)\n", platform.Icon("✅", "[OK]"), run.ID, alias, run.Status)
+	fmt.Printf("   Follow it with: dibbla jobs runs %s\n", alias)
+}
+
+func formatJobTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,219 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+)
+
+// appsColumn is one column `apps list --columns` can show.
+type appsColumn struct {
+	name   string
+	header string
+	value  func(apps.Deployment) string
+}
+
+var appsColumns = []appsColumn{
+	{"alias", "ALIAS", func(d apps.Deployment) string { return d.Alias }},
+	{"url", "URL", func(d apps.Deployment) string { return d.URL }},
+	{"region", "REGION", formatRegion},
+	{"status", "STATUS", func(d apps.Deployment) string { return string(d.Status) }},
+	{"replicas", "REPLICAS", func(d apps.Deployment) string { return fmt.Sprintf("%d/%d", d.ReadyReplicas, d.Replicas) }},
+	{"cpu", "CPU", func(d apps.Deployment) string { return orDash(d.CPU) }},
+	{"memory", "MEMORY", func(d apps.Deployment) string { return orDash(d.Memory) }},
+	{"deployed_at", "LAST DEPLOYED", formatDeployedAt},
+}
+
+// defaultAppsColumns is the apps list layout without --columns.
+const defaultAppsColumns = "alias,url,region,status,deployed_at"
+
+// appsSortKeys are the accepted --sort values.
+var appsSortKeys = []string{"alias", "status", "deployed_at"}
+
+// minURLWidth is the narrowest the URL column shrinks to on a narrow
+// terminal before the table is allowed to wrap.
+const minURLWidth = 20
+
+// appsTable is the layout of the apps list table.
+type appsTable struct {
+	columns []appsColumn
+	sortBy  string
+	// width is the terminal width the table must fit in; 0 means no limit.
+	width int
+}
+
+// newAppsTable validates --columns and --sort.
+func newAppsTable(columns, sortBy string, width int) (appsTable, error) {
+	t := appsTable{sortBy: sortBy, width: width}
+	if sortBy != "" && !containsString(appsSortKeys, sortBy) {
+		return t, fmt.Errorf("invalid --sort %q (expected one of: %s)", sortBy, strings.Join(appsSortKeys, ", "))
+	}
+	for _, name := range strings.Split(columns, ",") {
+		name = strings.TrimSpace(name)
+		col, ok := findAppsColumn(name)
+		if !ok {
+			names := make([]string, len(appsColumns))
+			for i, c := range appsColumns {
+				names[i] = c.name
+			}
+			return t, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		t.columns = append(t.columns, col)
+	}
+	return t, nil
+}
+
+func findAppsColumn(name string) (appsColumn, bool) {
+	for _, c := range appsColumns {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return appsColumn{}, false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sorted returns listed ordered by t.sortBy: aliases A-Z, statuses A-Z,
+// deployments newest first (never-deployed last). Ties keep API order.
+func (t appsTable) sorted(listed []apps.Deployment) []apps.Deployment {
+	if t.sortBy == "" {
+		return listed
+	}
+	out := append([]apps.Deployment(nil), listed...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch t.sortBy {
+		case "status":
+			return a.Status < b.Status
+		case "deployed_at":
+			if a.DeployedAt == nil || b.DeployedAt == nil {
+				return a.DeployedAt != nil && b.DeployedAt == nil
+			}
+			return a.DeployedAt.After(*b.DeployedAt)
+		default:
+			return a.Alias < b.Alias
+		}
+	})
+	return out
+}
+
+// print writes the table. Aliases in changed get their status highlighted:
+// in bold yellow with useColor, else with a trailing *. When the table is
+// wider than t.width the URL column is truncated to fit.
+func (t appsTable) print(w io.Writer, listed []apps.Deployment, changed map[string]bool, useColor bool) {
+	listed = t.sorted(listed)
+	cells := make([][]string, len(listed))
+	widths := make([]int, len(t.columns))
+	for i, c := range t.columns {
+		widths[i] = len(c.header)
+	}
+	for r, dep := range listed {
+		cells[r] = make([]string, len(t.columns))
+		for i, c := range t.columns {
+			v := c.value(dep)
+			if c.name == "status" && changed[dep.Alias] && !useColor {
+				v += "*"
+			}
+			cells[r][i] = v
+			widths[i] = max(widths[i], utf8.RuneCountInString(v))
+		}
+	}
+	t.fitURL(widths)
+
+	row := func(values []string, highlight bool) {
+		var b strings.Builder
+		for i, v := range values {
+			last := i == len(values)-1
+			if t.columns[i].name == "url" {
+				v = truncate(v, widths[i])
+			}
+			cell := v
+			if !last {
+				cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(v))
+			}
+			if highlight && t.columns[i].name == "status" {
+				cell = "\033[1;33m" + cell + "\033[0m"
+			}
+			b.WriteString(cell)
+			if !last {
+				b.WriteByte(' ')
+			}
+		}
+		fmt.Fprintln(w, b.String())
+	}
+
+	headers := make([]string, len(t.columns))
+	dashes := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		headers[i] = c.header
+		dashes[i] = strings.Repeat("-", len(c.header))
+	}
+	row(headers, false)
+	row(dashes, false)
+	for r, dep := range listed {
+		row(cells[r], useColor && changed[dep.Alias])
+	}
+}
+
+// fitURL shrinks the URL column so the table fits in t.width.
+func (t appsTable) fitURL(widths []int) {
+	if t.width <= 0 {
+		return
+	}
+	total := len(widths) - 1 // separators
+	urlCol := -1
+	for i, w := range widths {
+		total += w
+		if t.columns[i].name == "url" {
+			urlCol = i
+		}
+	}
+	if urlCol < 0 || total <= t.width {
+		return
+	}
+	widths[urlCol] = max(minURLWidth, widths[urlCol]-(total-t.width))
+}
+
+// truncate shortens s to n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	ellipsis := platform.Icon("…", "~")
+	return string([]rune(s)[:n-1]) + ellipsis
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// terminalWidth returns the width of the terminal on stdout, or 0 when
+// stdout isn't a terminal (no truncation when piping).
+func terminalWidth() int {
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return 0
+	}
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		return w
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package deploy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func defaultTable(t *testing.T) appsTable {
+	t.Helper()
+	table, err := newAppsTable(defaultAppsColumns, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestNewAppsTable_Validation(t *testing.T) {
+	if _, err := newAppsTable("alias,bogus", "", 0); err == nil || !strings.Contains(err.Error(), `unknown column "bogus"`) {
+		t.Errorf("unknown column: got %v", err)
+	}
+	if _, err := newAppsTable("alias", "url", 0); err == nil || !strings.Contains(err.Error(), "invalid --sort") {
+		t.Errorf("bad sort: got %v", err)
+	}
+	table, err := newAppsTable(" alias , replicas,cpu", "status", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.columns) != 3 || table.columns[1].name != "replicas" {
+		t.Errorf("columns = %+v", table.columns)
+	}
+}
+
+func TestAppsTable_Columns(t *testing.T) {
+	table, err := newAppsTable("alias,replicas,cpu", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	table.print(&buf, []apps.Deployment{{Alias: "shop", Replicas: 3, ReadyReplicas: 2, CPU: "500m"}, {Alias: "blog"}}, nil, false)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	want := []string{
+		"ALIAS REPLICAS CPU",
+		"----- -------- ---",
+		"shop  2/3      500m",
+		"blog  0/0      -",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), strings.Join(want, "\n"))
+	}
+}
+
+func TestAppsTable_Sort(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	listed := []apps.Deployment{
+		{Alias: "b", Status: apps.DeploymentStatusRunning, DeployedAt: &older},
+		{Alias: "c", Status: apps.DeploymentStatusFailed},
+		{Alias: "a", Status: apps.DeploymentStatusStopped, DeployedAt: &newer},
+	}
+	for sortBy, want := range map[string]string{
+		"":            "bca",
+		"alias":       "abc",
+		"status":      "cba",
+		"deployed_at": "abc",
+	} {
+		table := appsTable{sortBy: sortBy}
+		var got string
+		for _, d := range table.sorted(listed) {
+			got += d.Alias
+		}
+		if got != want {
+			t.Errorf("sort %q = %s, want %s", sortBy, got, want)
+		}
+	}
+}
+
+func TestAppsTable_FitsURLToWidth(t *testing.T) {
+	table, err := newAppsTable("alias,url,status", "", 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long := "https://a-very-long-application-alias.apps.dibbla.com"
+	var buf bytes.Buffer
+	table.print(&buf, []apps.Deployment{{Alias: "shop", URL: long, Status: "running"}}, nil, false)
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		if n := len([]rune(line)); n > 40 {
+			t.Errorf("line is %d wide: %q", n, line)
+		}
+	}
+	if strings.Contains(buf.String(), long) || !strings.Contains(buf.String(), "https://a-very-long-") {
+		t.Errorf("URL not truncated:\n%s", buf.String())
+	}
+
+	// Without a width (piped output) nothing is cut.
+	buf.Reset()
+	defaultTable(t).print(&buf, []apps.Deployment{{Alias: "shop", URL: long}}, nil, false)
+	if !strings.Contains(buf.String(), long) {
+		t.Errorf("URL truncated without a width:\n%s", buf.String())
+	}
+}
+
+func TestAppsTable_ChangedStatus(t *testing.T) {
+	table, _ := newAppsTable("alias,status", "", 0)
+	var buf bytes.Buffer
+	table.print(&buf, []apps.Deployment{{Alias: "shop", Status: "failed"}}, map[string]bool{"shop": true}, true)
+	if !strings.Contains(buf.String(), "\033[1;33mfailed\033[0m") {
+		t.Errorf("changed status not highlighted: %q", buf.String())
+	}
+}
@@ -0,0 +1,244 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsMetricsCmd = &cobra.Command{
+	Use:   "metrics [alias]",
+	Short: "Show CPU, memory, restart and request stats for an app",
+	Long: `Show current and recent resource usage of a deployment: CPU, memory,
+container restarts, request rate and p95 latency. The text output is a
+compact table with the current value, the average and peak over --since,
+and a sparkline of the window.
+
+Without an alias, default_deployment is used (dibbla config set
+default_deployment <alias>).
+
+Examples:
+  dibbla apps metrics myapp
+  dibbla apps metrics myapp --since 24h --service worker
+  dibbla apps metrics myapp -o json | jq .current.p95_latency_ms`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAppsMetrics,
+}
+
+var (
+	metricsSince   time.Duration
+	metricsService string
+	metricsOutput  string
+)
+
+// sparkWidth caps the number of points drawn in a sparkline; longer series
+// are averaged down to it.
+const sparkWidth = 30
+
+func init() {
+	appsCmd.AddCommand(appsMetricsCmd)
+	appsMetricsCmd.Flags().DurationVar(&metricsSince, "since", time.Hour, "Window of recent usage to summarize (e.g. 15m, 6h, 24h)")
+	appsMetricsCmd.Flags().StringVarP(&metricsService, "service", "s", "", "Only this service (multi-service deployments)")
+	appsMetricsCmd.Flags().StringVarP(&metricsOutput, "output", "o", "text", "Output format: text or json")
+}
+
+func runAppsMetrics(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runAppsMetricsCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, aliasArg(args),
+		apps.MetricsOptions{Window: metricsSince, Service: metricsService}, metricsOutput))
+}
+
+// runAppsMetricsCore is the testable inner implementation of
+// `apps metrics`. Returns the exit code.
+func runAppsMetricsCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias string, opts apps.MetricsOptions, format string) int {
+	if format != "text" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected text or json)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+	if opts.Window < 0 {
+		fmt.Fprintf(stderr, "%s --since must be positive\n", platform.Icon("❌", "[X]"))
+		return 1
+	}
+
+	m, err := apps.GetMetrics(ctx, apiURL, apiToken, alias, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to get metrics for '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		return 1
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(m)
+		return 0
+	}
+	writeMetricsText(stdout, m, opts.Window)
+	return 0
+}
+
+// metricRow describes how one metric is pulled from a sample and printed.
+type metricRow struct {
+	name   string
+	value  func(apps.MetricsSample) float64
+	format func(float64) string
+	limit  func(apps.MetricsSample) string
+}
+
+var metricRows = []metricRow{
+	{
+		name:   "CPU",
+		value:  func(s apps.MetricsSample) float64 { return s.CPUMillicores },
+		format: func(v float64) string { return fmt.Sprintf("%.0fm", v) },
+		limit: func(s apps.MetricsSample) string {
+			if s.CPULimitMillicores > 0 {
+				return fmt.Sprintf("%.0fm", s.CPULimitMillicores)
+			}
+			return ""
+		},
+	},
+	{
+		name:   "Memory",
+		value:  func(s apps.MetricsSample) float64 { return float64(s.MemoryBytes) },
+		format: formatBytes,
+		limit: func(s apps.MetricsSample) string {
+			if s.MemoryLimitBytes > 0 {
+				return formatBytes(float64(s.MemoryLimitBytes))
+			}
+			return ""
+		},
+	},
+	{
+		name:   "Restarts",
+		value:  func(s apps.MetricsSample) float64 { return float64(s.Restarts) },
+		format: func(v float64) string { return fmt.Sprintf("%.0f", v) },
+	},
+	{
+		name:   "Requests",
+		value:  func(s apps.MetricsSample) float64 { return s.RequestsPerSecond },
+		format: func(v float64) string { return fmt.Sprintf("%.1f/s", v) },
+	},
+	{
+		name:   "p95 latency",
+		value:  func(s apps.MetricsSample) float64 { return s.P95LatencyMs },
+		format: func(v float64) string { return fmt.Sprintf("%.0f ms", v) },
+	},
+}
+
+func writeMetricsText(w io.Writer, m *apps.MetricsResponse, window time.Duration) {
+	title := m.Alias
+	if m.Service != "" {
+		title += " (" + m.Service + ")"
+	}
+	if window > 0 {
+		fmt.Fprintf(w, "%s — last %s\n\n", title, formatWindow(window))
+	} else {
+		fmt.Fprintf(w, "%s\n\n", title)
+	}
+
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METRIC\tNOW\tAVG\tMAX\tTREND")
+	for _, row := range metricRows {
+		now := row.format(row.value(m.Current))
+		if row.limit != nil {
+			if limit := row.limit(m.Current); limit != "" {
+				now += " / " + limit
+			}
+		}
+		avg, peak, trend := "-", "-", ""
+		if len(m.Series) > 0 {
+			values := make([]float64, len(m.Series))
+			sum, hi := 0.0, math.Inf(-1)
+			for i, s := range m.Series {
+				values[i] = row.value(s)
+				sum += values[i]
+				hi = math.Max(hi, values[i])
+			}
+			avg = row.format(sum / float64(len(values)))
+			peak = row.format(hi)
+			trend = sparkline(values, sparkWidth)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", row.name, now, avg, peak, trend)
+	}
+	tw.Flush()
+}
+
+// sparkline draws values as a row of block characters scaled between the
+// series minimum and maximum, averaging down to at most width points.
+// Legacy consoles get an ASCII ramp instead.
+func sparkline(values []float64, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = downsample(values, width)
+	}
+	ramp := []rune("▁▂▃▄▅▆▇█")
+	if !platform.SupportsUnicode() {
+		ramp = []rune("_.-:=+*#")
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	out := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(ramp)-1))
+		}
+		out[i] = ramp[level]
+	}
+	return string(out)
+}
+
+// downsample averages values into n equal buckets.
+func downsample(values []float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		start, end := i*len(values)/n, (i+1)*len(values)/n
+		var sum float64
+		for _, v := range values[start:end] {
+			sum += v
+		}
+		out[i] = sum / float64(end-start)
+	}
+	return out
+}
+
+// formatWindow prints a duration without trailing zero units ("1h", not
+// "1h0m0s").
+func formatWindow(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// formatBytes renders a byte count with a binary unit (KiB, MiB, GiB).
+func formatBytes(v float64) string {
+	const unit = 1024
+	if v < unit {
+		return fmt.Sprintf("%.0f B", v)
+	}
+	div, exp := float64(unit), 0
+	for n := v / unit; n >= unit && exp < 3; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", v/div, "KMGT"[exp])
+}
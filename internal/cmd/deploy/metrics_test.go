@@ -0,0 +1,75 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func metricsServer(t *testing.T) string {
+	return newRestartServer(t, http.StatusOK, map[string]any{
+		"alias": "shop", "step_seconds": 60,
+		"current": map[string]any{"cpu_millicores": 120, "cpu_limit_millicores": 500, "memory_bytes": 100 << 20,
+			"memory_limit_bytes": 512 << 20, "restarts": 1, "requests_per_second": 4.5, "p95_latency_ms": 87},
+		"series": []map[string]any{
+			{"at": "2026-01-01T00:00:00Z", "cpu_millicores": 100, "p95_latency_ms": 50},
+			{"at": "2026-01-01T00:01:00Z", "cpu_millicores": 200, "p95_latency_ms": 90},
+		},
+	}).URL
+}
+
+func TestRunAppsMetricsCore_Text(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runAppsMetricsCore(context.Background(), &stdout, &stderr, metricsServer(t), "tok", "shop",
+		apps.MetricsOptions{Window: time.Hour}, "text")
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"shop — last 1h\n", "METRIC", "120m / 500m", "100.0 MiB / 512.0 MiB", "4.5/s", "87 ms", "150m", "200m"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunAppsMetricsCore_JSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runAppsMetricsCore(context.Background(), &stdout, &stderr, metricsServer(t), "tok", "shop",
+		apps.MetricsOptions{}, "json")
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var m apps.MetricsResponse
+	if err := json.Unmarshal(stdout.Bytes(), &m); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, stdout.String())
+	}
+	if m.Current.P95LatencyMs != 87 || len(m.Series) != 2 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestRunAppsMetricsCore_BadOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runAppsMetricsCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "shop", apps.MetricsOptions{}, "csv"); code != 1 {
+		t.Errorf("exit %d, want 1", code)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 7, 14}, 30); got != "▁▄█" {
+		t.Errorf("sparkline = %q", got)
+	}
+	if got := sparkline([]float64{5, 5}, 30); got != "▁▁" {
+		t.Errorf("flat sparkline = %q", got)
+	}
+	if got := []rune(sparkline(make([]float64, 100), 30)); len(got) != 30 {
+		t.Errorf("downsampled width = %d, want 30", len(got))
+	}
+}
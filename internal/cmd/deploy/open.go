@@ -0,0 +1,87 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/auth"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsOpenCmd = &cobra.Command{
+	Use:   "open [alias]",
+	Short: "Open an app's URL in the browser",
+	Long: `Look up the deployment's URL and open it in the default browser (open on
+macOS, xdg-open on Linux, the URL handler on Windows). Without a graphical
+session, or with --print, the URL is printed instead.
+
+Without an alias, default_deployment is used (dibbla config set
+default_deployment <alias>).
+
+Examples:
+  dibbla apps open myapp
+  dibbla apps open myapp --path /admin
+  curl "$(dibbla apps open myapp --path /health --print)"`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAppsOpen,
+}
+
+var (
+	openPath  string
+	openPrint bool
+)
+
+func init() {
+	appsCmd.AddCommand(appsOpenCmd)
+	appsOpenCmd.Flags().StringVar(&openPath, "path", "", "Path to append to the app URL, e.g. /admin")
+	appsOpenCmd.Flags().BoolVar(&openPrint, "print", false, "Print the URL instead of opening it")
+}
+
+func runAppsOpen(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	opener := auth.OpenBrowser
+	if openPrint || !auth.HasGraphicalSession() || auth.IsSSHSession() {
+		opener = nil
+	}
+	os.Exit(runAppsOpenCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, aliasArg(args), openPath, opener))
+}
+
+// runAppsOpenCore resolves alias's URL and hands it to opener, or prints it
+// when opener is nil. Returns the exit code.
+func runAppsOpenCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias, path string, opener func(string) error) int {
+	d, err := apps.GetApp(ctx, apiURL, apiToken, alias)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to look up '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+		return 1
+	}
+	if d.URL == "" {
+		fmt.Fprintf(stderr, "%s '%s' has no URL yet (status: %s)\n", platform.Icon("❌", "[X]"), alias, d.Status)
+		return 1
+	}
+	target := joinURLPath(d.URL, path)
+
+	if opener == nil {
+		fmt.Fprintln(stdout, target)
+		return 0
+	}
+	fmt.Fprintf(stdout, "%s Opening %s\n", platform.Icon("🌐", "[>]"), target)
+	if err := opener(target); err != nil {
+		fmt.Fprintf(stderr, "%s Could not open a browser (%v); visit the URL above.\n", platform.Icon("⚠️", "[!]"), err)
+	}
+	return 0
+}
+
+// joinURLPath appends path to base with exactly one slash between them.
+func joinURLPath(base, path string) string {
+	if path == "" {
+		return base
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
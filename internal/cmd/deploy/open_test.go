@@ -0,0 +1,45 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRunAppsOpenCore_OpensURLWithPath(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{"alias": "shop", "url": "https://shop.dibbla.com/", "status": "running"})
+	var opened string
+	var stdout, stderr bytes.Buffer
+	code := runAppsOpenCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", "admin", func(u string) error {
+		opened = u
+		return nil
+	})
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if opened != "https://shop.dibbla.com/admin" {
+		t.Errorf("opened %q", opened)
+	}
+}
+
+func TestRunAppsOpenCore_PrintOnly(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{"alias": "shop", "url": "https://shop.dibbla.com", "status": "running"})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsOpenCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", "/health", nil); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if stdout.String() != "https://shop.dibbla.com/health\n" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunAppsOpenCore_NoURL(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{"alias": "shop", "status": "building"})
+	var stdout, stderr bytes.Buffer
+	code := runAppsOpenCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", "", nil)
+	if code != 1 || !strings.Contains(stderr.String(), "has no URL yet (status: building)") {
+		t.Errorf("exit %d stderr=%q", code, stderr.String())
+	}
+}
@@ -0,0 +1,85 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsPortForwardCmd = &cobra.Command{
+	Use:   "port-forward <alias> [local:]remote",
+	Short: "Forward a local port to a port in a running app container",
+	Long: `Open a local port that tunnels to a port of the running container over
+an authenticated WebSocket, so private or internal services can be tested
+locally without exposing them publicly. Each local connection gets its own
+tunnel. Runs until Ctrl-C.
+
+The local port defaults to the remote one; use 0 to pick a free port. The
+listener binds to 127.0.0.1 unless --address says otherwise.
+
+Examples:
+  dibbla apps port-forward myapp 8080:3000
+  dibbla apps port-forward myapp 9090 --service metrics
+  dibbla apps port-forward myapp 0:5432`,
+	Args: cobra.ExactArgs(2),
+	Run:  runAppsPortForward,
+}
+
+var (
+	portForwardService string
+	portForwardAddress string
+)
+
+func init() {
+	appsCmd.AddCommand(appsPortForwardCmd)
+	appsPortForwardCmd.Flags().StringVarP(&portForwardService, "service", "s", "", "Service to forward to (multi-service deployments)")
+	appsPortForwardCmd.Flags().StringVar(&portForwardAddress, "address", "127.0.0.1", "Local address to listen on")
+}
+
+func runAppsPortForward(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	// Ctrl-C stops forwarding cleanly rather than exiting the process.
+	ctx, cancel := interrupt.GracefulContext(cmd.Context())
+	defer cancel()
+
+	os.Exit(runAppsPortForwardCore(ctx, os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], args[1], portForwardService, portForwardAddress))
+}
+
+// runAppsPortForwardCore is the testable inner implementation of
+// `apps port-forward`. It blocks until ctx is cancelled. Returns the exit
+// code.
+func runAppsPortForwardCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias, spec, service, address string) int {
+	localPort, remotePort, err := apps.ParsePortSpec(spec)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(localPort)))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to listen on %s: %v\n", platform.Icon("❌", "[X]"), net.JoinHostPort(address, strconv.Itoa(localPort)), err)
+		return 1
+	}
+
+	target := alias
+	if service != "" {
+		target += "/" + service
+	}
+	fmt.Fprintf(stdout, "%s Forwarding %s -> %s:%d (Ctrl-C to stop)\n", platform.Icon("🔌", "[>]"), ln.Addr(), target, remotePort)
+
+	err = apps.PortForward(ctx, apiURL, apiToken, alias, apps.PortForwardOptions{Port: remotePort, Service: service}, ln, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Port forwarding stopped: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,29 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRunAppsPortForwardCore_BadSpec(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runAppsPortForwardCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "shop", "http", "", "127.0.0.1")
+	if code != 1 || !strings.Contains(stderr.String(), "invalid port spec") {
+		t.Errorf("exit %d stderr=%q", code, stderr.String())
+	}
+}
+
+func TestRunAppsPortForwardCore_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var stdout, stderr bytes.Buffer
+	code := runAppsPortForwardCore(ctx, &stdout, &stderr, "http://unused", "tok", "shop", "0:3000", "web", "127.0.0.1")
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "-> shop/web:3000") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
@@ -0,0 +1,185 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	deploypkg "github.com/dibbla-agents/dibbla-cli/internal/deploy"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsCleanupPreviewsCmd = &cobra.Command{
+	Use:   "cleanup-previews [path]",
+	Short: "Delete preview deployments whose branch was merged or deleted",
+	Long: `Delete the branch previews (from 'dibbla deploy --preview') of the app in
+[path] whose git branch has been merged into the base branch, or no longer
+exists locally or on any remote. The remotes are asked directly (git
+ls-remote), so branches that were never fetched don't count as deleted;
+when a remote can't be reached, only previews of merged branches are
+deleted.
+
+The app is identified like 'dibbla deploy' does: --alias, else the alias in
+.dibbla/config.yaml of [path] or a parent, else the directory name. The base branch defaults to origin's HEAD, else main, else master.
+Run 'git fetch --prune' first so remote branch state is current.
+
+Examples:
+  dibbla apps cleanup-previews
+  dibbla apps cleanup-previews --dry-run
+  dibbla apps cleanup-previews ./shop --base develop --yes`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAppsCleanupPreviews,
+}
+
+var (
+	cleanupAlias  string
+	cleanupBase   string
+	cleanupDryRun bool
+	cleanupYes    bool
+)
+
+func init() {
+	appsCleanupPreviewsCmd.Flags().StringVarP(&cleanupAlias, "alias", "a", "", "App alias the previews belong to (default: alias in .dibbla/config.yaml, else directory name)")
+	appsCleanupPreviewsCmd.Flags().StringVar(&cleanupBase, "base", "", "Branch previews merge into (default: origin's HEAD, main or master)")
+	appsCleanupPreviewsCmd.Flags().BoolVar(&cleanupDryRun, "dry-run", false, "List the previews that would be deleted without deleting them")
+	appsCleanupPreviewsCmd.Flags().BoolVarP(&cleanupYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runAppsCleanupPreviews(cmd *cobra.Command, args []string) {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		fmt.Printf("%s Invalid path: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+
+	// Like deploy, read the settings of the project at [path].
+	config.SetProjectDir(absPath)
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	app := cleanupPreviewsApp(cleanupAlias, cfg.Project, absPath)
+	base := cleanupBase
+	if base == "" {
+		base = deploypkg.DefaultBranch(absPath)
+	}
+	branches, err := deploypkg.BranchStates(absPath, base)
+	if err != nil {
+		fmt.Printf("%s Failed to read git branches: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	// Branches never fetched are missing from BranchStates: ask the remotes
+	// before calling a branch deleted.
+	gone := true
+	live, err := deploypkg.RemoteBranches(absPath)
+	if err != nil {
+		fmt.Printf("%s Could not list the remote branches (%v); keeping previews of branches that look deleted.\n", platform.Icon("⚠️", "[!]"), err)
+		gone = false
+	}
+	for _, name := range live {
+		if _, ok := branches[name]; !ok {
+			branches[name] = false
+		}
+	}
+	// The base branch is "merged" into itself; never clean up its preview.
+	for _, name := range []string{base, strings.TrimPrefix(base, "origin/")} {
+		if _, ok := branches[name]; ok {
+			branches[name] = false
+		}
+	}
+
+	confirm := askConfirm
+	if cleanupYes {
+		confirm = func(string) bool { return true }
+	}
+	os.Exit(runCleanupPreviewsCore(cmd.Context(), os.Stdout, cfg.APIURL, cfg.APIToken, app, base, branches, gone, cleanupDryRun, confirm))
+}
+
+// cleanupPreviewsApp is the app whose previews are cleaned up, picked as
+// deploy --preview picks the parent app: alias, else the project's alias,
+// else the name of the directory at absPath.
+func cleanupPreviewsApp(alias string, project *config.ProjectConfig, absPath string) string {
+	if alias != "" {
+		return alias
+	}
+	if project != nil && project.Alias != "" {
+		return project.Alias
+	}
+	return filepath.Base(absPath)
+}
+
+// runCleanupPreviewsCore deletes the stale previews of app. branches is
+// deploypkg.BranchStates output: branch name → merged into base. gone
+// says whether branches is complete, so previews of branches missing from
+// it can be deleted too.
+func runCleanupPreviewsCore(ctx context.Context, w io.Writer, apiURL, apiToken, app, base string, branches map[string]bool, gone, dryRun bool, confirm func(string) bool) int {
+	deployments, err := apps.ListApps(ctx, apiURL, apiToken, apps.ListOptions{})
+	if err != nil {
+		fmt.Fprintf(w, "%s Failed to list applications: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+
+	stale := stalePreviews(deployments.Deployments, app, branches, gone)
+	if len(stale) == 0 {
+		fmt.Fprintf(w, "No stale previews of %s (base branch %s).\n", app, base)
+		return 0
+	}
+
+	fmt.Fprintf(w, "Previews of %s whose branch is merged into %s or deleted:\n", app, base)
+	fmt.Fprintln(w)
+	for _, dep := range stale {
+		state := "deleted"
+		if branches[dep.PreviewBranch] {
+			state = "merged"
+		}
+		fmt.Fprintf(w, "  %-30s %-30s %s\n", dep.Alias, dep.PreviewBranch, state)
+	}
+	fmt.Fprintln(w)
+
+	if dryRun {
+		fmt.Fprintf(w, "Dry run: %d preview(s) would be deleted.\n", len(stale))
+		return 0
+	}
+	if !confirm(fmt.Sprintf("Delete these %d preview(s)? This action cannot be undone.", len(stale))) {
+		fmt.Fprintln(w, "Cleanup cancelled.")
+		return 0
+	}
+
+	failed := 0
+	for _, dep := range stale {
+		if _, err := apps.DeleteApp(ctx, apiURL, apiToken, dep.Alias); err != nil {
+			fmt.Fprintf(w, "%s %s: %v\n", platform.Icon("❌", "[X]"), dep.Alias, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "%s Deleted %s\n", platform.Icon("✅", "[OK]"), dep.Alias)
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// stalePreviews picks the previews of app whose branch is merged (true in
+// branches) or, when gone is set, deleted (absent from branches).
+func stalePreviews(deps []apps.Deployment, app string, branches map[string]bool, gone bool) []apps.Deployment {
+	var stale []apps.Deployment
+	for _, dep := range deps {
+		if !dep.Preview || dep.PreviewOf != app || dep.PreviewBranch == "" {
+			continue
+		}
+		if merged, exists := branches[dep.PreviewBranch]; merged || (gone && !exists) {
+			stale = append(stale, dep)
+		}
+	}
+	return stale
+}
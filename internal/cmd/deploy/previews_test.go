@@ -0,0 +1,123 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+)
+
+func TestStalePreviews(t *testing.T) {
+	deps := []apps.Deployment{
+		{Alias: "shop"},
+		{Alias: "shop-merged", Preview: true, PreviewOf: "shop", PreviewBranch: "merged"},
+		{Alias: "shop-open", Preview: true, PreviewOf: "shop", PreviewBranch: "open"},
+		{Alias: "shop-gone", Preview: true, PreviewOf: "shop", PreviewBranch: "gone"},
+		{Alias: "blog-merged", Preview: true, PreviewOf: "blog", PreviewBranch: "merged"},
+	}
+	branches := map[string]bool{"merged": true, "open": false}
+
+	var got []string
+	for _, d := range stalePreviews(deps, "shop", branches, true) {
+		got = append(got, d.Alias)
+	}
+	if strings.Join(got, ",") != "shop-merged,shop-gone" {
+		t.Errorf("stale previews: got %v", got)
+	}
+
+	// Without the remotes' branches, a missing branch may just be unfetched.
+	got = nil
+	for _, d := range stalePreviews(deps, "shop", branches, false) {
+		got = append(got, d.Alias)
+	}
+	if strings.Join(got, ",") != "shop-merged" {
+		t.Errorf("stale previews without remotes: got %v", got)
+	}
+}
+
+func TestRunCleanupPreviewsCore_DeletesAfterConfirm(t *testing.T) {
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/api/deploy/deployments/"))
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "deleted"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(apps.DeploymentsListResponse{Deployments: []apps.Deployment{
+			{Alias: "shop-feature-x", Preview: true, PreviewOf: "shop", PreviewBranch: "feature/x"},
+			{Alias: "shop-wip", Preview: true, PreviewOf: "shop", PreviewBranch: "wip"},
+		}})
+	}))
+	defer srv.Close()
+
+	branches := map[string]bool{"feature/x": true, "wip": false}
+	var out bytes.Buffer
+	asked := false
+	code := runCleanupPreviewsCore(context.Background(), &out, srv.URL, "tok", "shop", "main", branches, true, false, func(string) bool {
+		asked = true
+		return true
+	})
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, out.String())
+	}
+	if !asked {
+		t.Error("expected a confirmation prompt")
+	}
+	if strings.Join(deleted, ",") != "shop-feature-x" {
+		t.Errorf("deleted: got %v", deleted)
+	}
+}
+
+func TestRunCleanupPreviewsCore_DryRunDeletesNothing(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, apps.DeploymentsListResponse{Deployments: []apps.Deployment{
+		{Alias: "shop-old", Preview: true, PreviewOf: "shop", PreviewBranch: "old"},
+	}})
+
+	var out bytes.Buffer
+	code := runCleanupPreviewsCore(context.Background(), &out, srv.URL, "tok", "shop", "main", map[string]bool{}, true, true, func(string) bool {
+		t.Error("dry run must not prompt")
+		return false
+	})
+	if code != 0 {
+		t.Fatalf("exit %d", code)
+	}
+	if !strings.Contains(out.String(), "shop-old") || !strings.Contains(out.String(), "would be deleted") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestCleanupPreviewsApp_ProjectAlias(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := filepath.Join(t.TempDir(), "checkout")
+	if err := os.MkdirAll(filepath.Join(dir, ".dibbla"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, config.ProjectConfigFile), []byte("alias: shop\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config.SetProjectDir(dir)
+	t.Cleanup(func() { config.SetProjectDir("") })
+	project, err := config.LoadProjectConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cleanupPreviewsApp("", project, dir); got != "shop" {
+		t.Errorf("app = %q, want the project's alias shop", got)
+	}
+	if got := cleanupPreviewsApp("blog", project, dir); got != "blog" {
+		t.Errorf("app with --alias = %q, want blog", got)
+	}
+	if got := cleanupPreviewsApp("", nil, dir); got != "checkout" {
+		t.Errorf("app without a project = %q, want the directory name", got)
+	}
+}
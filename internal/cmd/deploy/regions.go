@@ -0,0 +1,73 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var regionsCmd = &cobra.Command{
+	Use:   "regions",
+	Short: "Show the regions apps can run in",
+	Long: `Commands for the regions deployments can run in.
+
+Pick a region with dibbla deploy --region <id>, or move an existing app
+with dibbla apps update <alias> --region <id>.`,
+}
+
+var regionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available regions",
+	Long: `List the regions available to your organization. The default region is
+used when a deploy doesn't pass --region.
+
+Examples:
+  dibbla regions list
+  dibbla regions list --json`,
+	Args: cobra.NoArgs,
+	Run:  runRegionsList,
+}
+
+var regionsListJSON bool
+
+func init() {
+	regionsCmd.AddCommand(regionsListCmd)
+	regionsListCmd.Flags().BoolVar(&regionsListJSON, "json", false, "Print the JSON response body")
+}
+
+func runRegionsList(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runRegionsListCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, regionsListJSON))
+}
+
+// runRegionsListCore is the testable inner implementation of
+// `regions list`. Returns the exit code.
+func runRegionsListCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken string, jsonOut bool) int {
+	out, err := apps.ListRegions(ctx, apiURL, apiToken)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to list regions: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if jsonOut {
+		_ = json.NewEncoder(stdout).Encode(out)
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "%-16s %-20s %s\n", "REGION", "NAME", "LOCATION")
+	fmt.Fprintf(stdout, "%-16s %-20s %s\n", "------", "----", "--------")
+	for _, r := range out.Regions {
+		id := r.ID
+		if r.Default {
+			id += " (default)"
+		}
+		fmt.Fprintf(stdout, "%-16s %-20s %s\n", id, r.Name, r.Location)
+	}
+	return 0
+}
@@ -0,0 +1,41 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRunRegionsListCore(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"regions": []map[string]any{
+			{"id": "eu-west", "name": "EU West", "location": "Dublin", "default": true},
+			{"id": "us-east", "name": "US East", "location": "Virginia"},
+		},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runRegionsListCore(context.Background(), &stdout, &stderr, srv.URL, "tok", false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"eu-west (default)", "us-east", "Virginia"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunRegionsListCore_Error(t *testing.T) {
+	srv := newRestartServer(t, http.StatusUnauthorized, map[string]any{
+		"status": "error", "error": map[string]any{"code": "UNAUTHORIZED", "message": "bad token"},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runRegionsListCore(context.Background(), &stdout, &stderr, srv.URL, "tok", false); code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "UNAUTHORIZED") {
+		t.Errorf("stderr: %q", stderr.String())
+	}
+}
@@ -16,6 +16,21 @@ func Register(root *cobra.Command) {
 	root.AddCommand(deployCmd)
 	root.AddCommand(dbCmd)
 	root.AddCommand(secretsCmd)
+	root.AddCommand(jobsCmd)
+	root.AddCommand(deploymentsCmd)
+	root.AddCommand(buildersCmd)
+	root.AddCommand(regionsCmd)
+	root.AddCommand(scanCmd)
+}
+
+// loadConfig is config.Load, exiting if the configuration can't be used.
+func loadConfig() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	return cfg
 }
 
 func requireToken(cfg *config.Config) {
@@ -31,6 +46,25 @@ func requireToken(cfg *config.Config) {
 	}
 }
 
+// aliasArg returns the alias of a command whose <alias> argument is
+// optional, falling back to default_deployment in ~/.dibbla/config.yaml.
+func aliasArg(args []string) string {
+	alias, err := config.AliasArg(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	return alias
+}
+
 func askConfirm(msg string) bool {
 	return prompt.AskConfirm(msg)
 }
+
+func askConfirmStderr(msg string) bool {
+	return prompt.AskConfirmStderr(msg)
+}
+
+func askTypedConfirm(msg, want string) bool {
+	return prompt.AskTypedConfirm(msg, want)
+}
@@ -0,0 +1,103 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsRenameCmd = &cobra.Command{
+	Use:   "rename <old-alias> <new-alias>",
+	Short: "Change the alias of a deployment",
+	Long: `Change the alias of a deployment. The public URL follows the alias, so
+links, webhooks and OAuth redirect URIs pointing at the old URL stop
+working unless --keep-redirect is given and the platform supports it.
+
+The new alias must be lowercase letters, digits and hyphens (max 63, no
+leading or trailing hyphen).
+
+Examples:
+  dibbla apps rename shop store
+  dibbla apps rename shop store --keep-redirect -y`,
+	Args: cobra.ExactArgs(2),
+	Run:  runAppsRename,
+}
+
+var (
+	renameKeepRedirect bool
+	renameYes          bool
+)
+
+func init() {
+	appsCmd.AddCommand(appsRenameCmd)
+	appsRenameCmd.Flags().BoolVar(&renameKeepRedirect, "keep-redirect", false, "Redirect the old URL to the new one (if the platform supports it)")
+	appsRenameCmd.Flags().BoolVarP(&renameYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runAppsRename(cmd *cobra.Command, args []string) {
+	oldAlias, newAlias := args[0], args[1]
+	if err := validateAppsOutput(appsOutput); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	if !apps.AliasRe.MatchString(newAlias) {
+		fmt.Fprintf(os.Stderr, "%s invalid alias %q: use lowercase letters, digits and hyphens (max 63, no leading or trailing hyphen)\n", platform.Icon("❌", "[X]"), newAlias)
+		os.Exit(1)
+	}
+	if appsOutput != "table" && !renameYes {
+		fmt.Fprintf(os.Stderr, "%s -o %s needs --yes (there is no prompt in machine-readable mode)\n", platform.Icon("❌", "[X]"), appsOutput)
+		os.Exit(1)
+	}
+
+	cfg := loadConfig()
+	requireToken(cfg)
+
+	if !renameYes {
+		fmt.Printf("%s Renaming '%s' to '%s' changes its public URL.\n", platform.Icon("⚠️", "[!]"), oldAlias, newAlias)
+		if !renameKeepRedirect {
+			fmt.Println("   The old URL will stop working; pass --keep-redirect to redirect it.")
+		}
+		if !askConfirm(fmt.Sprintf("Rename '%s' to '%s'?", oldAlias, newAlias)) {
+			fmt.Println("Rename cancelled.")
+			os.Exit(0)
+		}
+	}
+	os.Exit(runAppsRenameCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, oldAlias, newAlias, renameKeepRedirect, appsOutput))
+}
+
+// runAppsRenameCore is the testable inner implementation of `apps rename`
+// (after confirmation). Returns the exit code.
+func runAppsRenameCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, oldAlias, newAlias string, keepRedirect bool, format string) int {
+	out, err := apps.RenameApp(ctx, apiURL, apiToken, oldAlias, newAlias, keepRedirect)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to rename '%s': %v\n", platform.Icon("❌", "[X]"), oldAlias, err)
+		return 1
+	}
+	if keepRedirect && !out.Redirect {
+		fmt.Fprintf(stderr, "%s The platform did not set up a redirect; the old URL no longer works.\n", platform.Icon("⚠️", "[!]"))
+	}
+	if format != "table" {
+		return writeAppsOutput(stdout, stderr, format, out)
+	}
+
+	fmt.Fprintf(stdout, "%s Renamed '%s' to '%s'\n", platform.Icon("✅", "[OK]"), oldAlias, out.Alias)
+	if out.URL != "" {
+		fmt.Fprintf(stdout, "   New URL: %s\n", out.URL)
+	}
+	if out.Redirect {
+		old := out.OldURL
+		if old == "" {
+			old = "The old URL"
+		}
+		fmt.Fprintf(stdout, "   %s redirects to the new one.\n", old)
+	}
+	if out.Message != "" {
+		fmt.Fprintf(stdout, "   %s\n", out.Message)
+	}
+	return 0
+}
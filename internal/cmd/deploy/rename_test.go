@@ -0,0 +1,43 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRunAppsRenameCore(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"alias": "store", "old_alias": "shop", "url": "https://store.dibbla.com",
+		"old_url": "https://shop.dibbla.com", "redirect": true,
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsRenameCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", "store", true, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Renamed 'shop' to 'store'", "https://store.dibbla.com", "https://shop.dibbla.com redirects"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+}
+
+func TestRunAppsRenameCore_RedirectUnsupported(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{"alias": "store", "redirect": false})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsRenameCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", "store", true, "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "did not set up a redirect") {
+		t.Errorf("missing redirect warning: %q", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `"alias": "store"`) {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
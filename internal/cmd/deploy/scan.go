@@ -0,0 +1,107 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	deploypkg "github.com/dibbla-agents/dibbla-cli/internal/deploy"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [path]",
+	Short: "Check the files a deploy would upload for secrets",
+	Long: `Builds the archive 'dibbla deploy' would upload from [path] (default: the
+current directory), with the same exclusions and filter flags, and checks
+it for secrets that should not leave your machine: env files (.env,
+.env.local, ...; templates such as .env.example are fine), private key
+blocks, AWS access keys and high-entropy values assigned to names like
+API_KEY or password. Matches are shown cut short, never in full.
+
+'dibbla deploy' runs the same scan and refuses to upload when it finds
+anything, unless --allow-secrets is passed. Exits 1 when there are
+findings, so it can run in CI or a pre-commit hook.
+
+Examples:
+  dibbla scan
+  dibbla scan ./api --exclude "fixtures/**"
+  dibbla scan -o json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runScan,
+}
+
+var (
+	scanInclude          []string
+	scanExclude          []string
+	scanRespectGitignore bool
+	scanFollowSymlinks   bool
+	scanOutput           string
+)
+
+func init() {
+	scanCmd.Flags().StringArrayVar(&scanExclude, "exclude", nil, "Leave paths matching this glob out, as for deploy (repeatable)")
+	scanCmd.Flags().StringArrayVar(&scanInclude, "include", nil, "Add paths matching this glob back, as for deploy (repeatable)")
+	scanCmd.Flags().BoolVar(&scanRespectGitignore, "respect-gitignore", false, "Leave paths ignored by .gitignore out (default: archive.respect_gitignore in dibbla.yaml)")
+	scanCmd.Flags().BoolVar(&scanFollowSymlinks, "follow-symlinks", false, "Include the targets of symlinks that point outside [path]")
+	scanCmd.Flags().StringVarP(&scanOutput, "output", "o", "text", "Output format: text or json")
+}
+
+func runScan(cmd *cobra.Command, args []string) {
+	opts := deploypkg.Options{
+		Path:           ".",
+		Include:        scanInclude,
+		Exclude:        scanExclude,
+		FollowSymlinks: scanFollowSymlinks,
+	}
+	if len(args) > 0 {
+		opts.Path = args[0]
+	}
+	if cmd.Flags().Changed("respect-gitignore") {
+		opts.RespectGitignore = &scanRespectGitignore
+	}
+	os.Exit(runScanCore(os.Stdout, os.Stderr, opts, scanOutput))
+}
+
+// runScanCore is the testable inner implementation of `scan`. Returns the
+// exit code: 1 on findings or errors.
+func runScanCore(stdout, stderr io.Writer, opts deploypkg.Options, format string) int {
+	if format != "text" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected text or json)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+	findings, err := deploypkg.ScanProject(opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Scan failed: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	code := 0
+	if len(findings) > 0 {
+		code = 1
+	}
+	if format == "json" {
+		if findings == nil {
+			findings = []deploypkg.Finding{}
+		}
+		if writeDbJSON(stdout, stderr, struct {
+			Findings []deploypkg.Finding `json:"findings"`
+		}{findings}) != 0 {
+			return 1
+		}
+		return code
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintf(stdout, "%s No secrets found in the files to upload\n", platform.Icon("✅", "[OK]"))
+		return 0
+	}
+	fmt.Fprintf(stdout, "%s %d possible secret(s) in the files to upload:\n", platform.Icon("⚠️", "[!]"), len(findings))
+	fmt.Fprintln(stdout)
+	for _, f := range findings {
+		fmt.Fprintf(stdout, "  %s\n", f)
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Remove them or leave them out with --exclude; 'dibbla deploy' refuses to upload them without --allow-secrets.")
+	return code
+}
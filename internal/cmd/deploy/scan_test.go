@@ -0,0 +1,44 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	deploypkg "github.com/dibbla-agents/dibbla-cli/internal/deploy"
+)
+
+func TestRunScanCore(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Dockerfile":   "FROM scratch\n",
+		".env":         "TOKEN=abc\n",
+		".env.example": "TOKEN=\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runScanCore(&stdout, &stderr, deploypkg.Options{Path: dir}, "text"); code != 1 {
+		t.Fatalf("exit %d, want 1 (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "1 possible secret(s)") || !strings.Contains(stdout.String(), "  .env: env file") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runScanCore(&stdout, &stderr, deploypkg.Options{Path: dir, Exclude: []string{".env"}}, "json"); code != 0 {
+		t.Fatalf("--exclude: exit %d (stderr=%q)", code, stderr.String())
+	}
+	var got struct {
+		Findings []deploypkg.Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || got.Findings == nil || len(got.Findings) != 0 {
+		t.Errorf("stdout = %q (%v)", stdout.String(), err)
+	}
+}
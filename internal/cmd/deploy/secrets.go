@@ -2,12 +2,17 @@ package deploy
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
-	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
 	"github.com/spf13/cobra"
@@ -22,24 +27,52 @@ var secretsCmd = &cobra.Command{
 var secretsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List secrets",
-	Long:  `List secrets. Use --deployment <alias> for a single deployment; omit for global secrets only.`,
-	Run:   runSecretsList,
+	Long: `List secrets. Use --deployment <alias> for a single deployment; omit for global secrets only.
+--all lists the global secrets and every deployment's, per-service ones
+included, in one table grouped by deployment.
+
+Examples:
+  dibbla secrets list -d myapp
+  dibbla secrets list --all
+  dibbla secrets list --all -o json`,
+	Run: runSecretsList,
 }
 
 var secretsSetCmd = &cobra.Command{
-	Use:   "set <name> [value]",
-	Short: "Create or update a secret",
-	Long:  `Set a secret by name. If value is omitted, it is read from stdin (e.g. echo "secret" | dibbla secrets set API_KEY). Use --deployment to attach to an app.`,
-	Args:  cobra.RangeArgs(1, 2),
-	Run:   runSecretsSet,
+	Use:   "set <name> [value] | <KEY=VALUE>...",
+	Short: "Create or update secrets",
+	Long: `Set a secret by name. If value is omitted, it is read from stdin (e.g. echo "secret" | dibbla secrets set API_KEY). Use --deployment to attach to an app.
+
+--from-file reads the value from a file exactly as it is, newlines included
+(PEM keys, JSON credentials); add --base64 to store a binary file
+base64-encoded.
+
+Several secrets can be set at once as KEY=VALUE arguments, in one request;
+each is reported separately and the command exits 1 if any failed.
+
+Examples:
+  dibbla secrets set API_KEY sk-123 -d myapp
+  dibbla secrets set A=1 B=2 C=3 -d myapp
+  dibbla secrets set GCP_CREDENTIALS --from-file ./service-account.json -d myapp`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runSecretsSet,
 }
 
 var secretsGetCmd = &cobra.Command{
 	Use:   "get <name>",
 	Short: "Get a secret's value",
-	Long:  `Get a secret by name. Use --deployment for a deployment-scoped secret.`,
-	Args:  cobra.ExactArgs(1),
-	Run:   runSecretsGet,
+	Long: `Get a secret by name. Use --deployment for a deployment-scoped secret.
+
+The value is masked by default, so it doesn't end up in shared terminals or
+CI logs; the secret's length and last update are shown instead. --reveal
+shows it in plain text, and --quiet prints only the raw value for scripts.
+
+Examples:
+  dibbla secrets get API_KEY -d myapp
+  dibbla secrets get API_KEY -d myapp --reveal
+  export API_KEY=$(dibbla secrets get API_KEY -d myapp -q)`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSecretsGet,
 }
 
 var secretsDeleteCmd = &cobra.Command{
@@ -56,10 +89,16 @@ var (
 	secretsGetDeployment    string
 	secretsDeleteDeployment string
 	secretsListService      string
+	secretsListAll          bool
+	secretsListOutput       string
 	secretsSetService       string
 	secretsGetService       string
+	secretsGetReveal        bool
+	secretsGetQuiet         bool
 	secretsDeleteService    string
 	secretsDeleteYes        bool
+	secretsSetFromFile      string
+	secretsSetBase64        bool
 )
 
 func init() {
@@ -70,10 +109,16 @@ func init() {
 
 	secretsListCmd.Flags().StringVarP(&secretsDeployment, "deployment", "d", "", "List secrets for this deployment only (omit for global)")
 	secretsListCmd.Flags().StringVarP(&secretsListService, "service", "s", "", "Scope to a single service in the deployment (requires -d)")
+	secretsListCmd.Flags().BoolVar(&secretsListAll, "all", false, "List global secrets and every deployment's in one table")
+	secretsListCmd.Flags().StringVarP(&secretsListOutput, "output", "o", "text", "Output format: text or json")
 	secretsSetCmd.Flags().StringVarP(&secretsSetDeployment, "deployment", "d", "", "Attach secret to this deployment (omit for global)")
 	secretsSetCmd.Flags().StringVarP(&secretsSetService, "service", "s", "", "Scope secret to a single service (requires -d)")
+	secretsSetCmd.Flags().StringVar(&secretsSetFromFile, "from-file", "", "Read the value from a file, byte for byte (multi-line PEM keys, JSON credentials)")
+	secretsSetCmd.Flags().BoolVar(&secretsSetBase64, "base64", false, "With --from-file, store the file base64-encoded (for binary files)")
 	secretsGetCmd.Flags().StringVarP(&secretsGetDeployment, "deployment", "d", "", "Get deployment-scoped secret")
 	secretsGetCmd.Flags().StringVarP(&secretsGetService, "service", "s", "", "Scope to a single service entry (requires -d)")
+	secretsGetCmd.Flags().BoolVar(&secretsGetReveal, "reveal", false, "Show the value in plain text instead of masked")
+	secretsGetCmd.Flags().BoolVarP(&secretsGetQuiet, "quiet", "q", false, "Print only the raw value, for pipes and scripts")
 	secretsDeleteCmd.Flags().StringVarP(&secretsDeleteDeployment, "deployment", "d", "", "Delete deployment-scoped secret")
 	secretsDeleteCmd.Flags().StringVarP(&secretsDeleteService, "service", "s", "", "Scope delete to a single service entry (requires -d)")
 	secretsDeleteCmd.Flags().BoolVarP(&secretsDeleteYes, "yes", "y", false, "Skip confirmation prompt")
@@ -90,31 +135,66 @@ func requireServiceWithDeployment(stderr io.Writer, deployment, service string)
 }
 
 func runSecretsList(cmd *cobra.Command, args []string) {
-	if !requireServiceWithDeployment(os.Stderr, secretsDeployment, secretsListService) {
-		os.Exit(1)
-	}
-	fmt.Printf("%s Retrieving secrets...\n", platform.Icon("🌱", "[>]"))
-	fmt.Println()
-
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
+	os.Exit(runSecretsListCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, secretsDeployment, secretsListService, secretsListAll, secretsListOutput))
+}
 
-	list, err := secrets.ListSecrets(cfg.APIURL, cfg.APIToken, secretsDeployment, secretsListService)
+// runSecretsListCore is the testable inner implementation of `secrets
+// list`. all lists global secrets and every deployment's instead of one
+// scope. Returns the exit code.
+func runSecretsListCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, deployment, service string, all bool, format string) int {
+	if format != "text" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected text or json)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+	if all && deployment != "" {
+		fmt.Fprintf(stderr, "%s --all lists every deployment; it can't be combined with --deployment\n", platform.Icon("❌", "[X]"))
+		return 1
+	}
+	if !requireServiceWithDeployment(stderr, deployment, service) {
+		return 1
+	}
+	if format == "text" {
+		fmt.Fprintf(stdout, "%s Retrieving secrets...\n", platform.Icon("🌱", "[>]"))
+		fmt.Fprintln(stdout)
+	}
+
+	var list *secrets.SecretsListResponse
+	var err error
+	if all {
+		list, err = listAllSecrets(ctx, apiURL, apiToken)
+	} else {
+		list, err = secrets.ListSecrets(ctx, apiURL, apiToken, deployment, service)
+	}
 	if err != nil {
-		fmt.Printf("%s Failed to list secrets: %v\n", platform.Icon("❌", "[X]"), err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "%s Failed to list secrets: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	if format == "json" {
+		if list.Secrets == nil {
+			list.Secrets = []secrets.SecretListItem{}
+		}
+		return writeDbJSON(stdout, stderr, list)
 	}
 
-	scope := scopeLabel(secretsDeployment, secretsListService)
-	if list.Total == 0 {
-		fmt.Printf("No secrets found (%s).\n", scope)
-		return
+	scope := scopeLabel(deployment, service)
+	if all {
+		scope = "global and every deployment"
 	}
-	fmt.Printf("Found %d secret(s) (%s):\n", list.Total, scope)
-	fmt.Println()
-	fmt.Printf("%-25s %-20s %-12s %s\n", "NAME", "DEPLOYMENT", "SERVICE", "UPDATED")
-	fmt.Printf("%-25s %-20s %-12s %s\n", "----", "-----------", "-------", "------")
-	for _, s := range list.Secrets {
+	if len(list.Secrets) == 0 {
+		fmt.Fprintf(stdout, "No secrets found (%s).\n", scope)
+		return 0
+	}
+	fmt.Fprintf(stdout, "Found %d secret(s) (%s):\n", len(list.Secrets), scope)
+	fmt.Fprintln(stdout)
+	fmt.Fprintf(stdout, "%-25s %-20s %-12s %s\n", "NAME", "DEPLOYMENT", "SERVICE", "UPDATED")
+	fmt.Fprintf(stdout, "%-25s %-20s %-12s %s\n", "----", "-----------", "-------", "------")
+	for i, s := range list.Secrets {
+		// Separate the groups of --all with a blank line.
+		if all && i > 0 && s.DeploymentAlias != list.Secrets[i-1].DeploymentAlias {
+			fmt.Fprintln(stdout)
+		}
 		dep := s.DeploymentAlias
 		if dep == "" {
 			dep = "(global)"
@@ -123,8 +203,51 @@ func runSecretsList(cmd *cobra.Command, args []string) {
 		if svc == "" {
 			svc = "(all)"
 		}
-		fmt.Printf("%-25s %-20s %-12s %s\n", s.Name, dep, svc, s.UpdatedAt)
+		fmt.Fprintf(stdout, "%-25s %-20s %-12s %s\n", s.Name, dep, svc, s.UpdatedAt)
 	}
+	return 0
+}
+
+// listAllSecrets lists the global secrets followed by each deployment's,
+// deployment-wide then per service, grouped by deployment in alias order.
+func listAllSecrets(ctx context.Context, apiURL, apiToken string) (*secrets.SecretsListResponse, error) {
+	global, err := secrets.ListSecrets(ctx, apiURL, apiToken, "", "")
+	if err != nil {
+		return nil, err
+	}
+	deps, err := apps.ListApps(ctx, apiURL, apiToken, apps.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	aliases := make([]string, 0, len(deps.Deployments))
+	for _, d := range deps.Deployments {
+		aliases = append(aliases, d.Alias)
+	}
+	sort.Strings(aliases)
+
+	out := &secrets.SecretsListResponse{Secrets: global.Secrets}
+	for _, alias := range aliases {
+		list, err := secrets.ListSecrets(ctx, apiURL, apiToken, alias, "")
+		if err != nil {
+			return nil, fmt.Errorf("deployment %s: %w", alias, err)
+		}
+		out.Secrets = append(out.Secrets, list.Secrets...)
+
+		detail, err := apps.GetApp(ctx, apiURL, apiToken, alias)
+		if err != nil {
+			return nil, fmt.Errorf("deployment %s: %w", alias, err)
+		}
+		sort.Strings(detail.Services)
+		for _, service := range detail.Services {
+			list, err := secrets.ListSecrets(ctx, apiURL, apiToken, alias, service)
+			if err != nil {
+				return nil, fmt.Errorf("deployment %s, service %s: %w", alias, service, err)
+			}
+			out.Secrets = append(out.Secrets, list.Secrets...)
+		}
+	}
+	out.Total = len(out.Secrets)
+	return out, nil
 }
 
 // scopeLabel summarizes the deployment+service scope for human messages.
@@ -143,9 +266,37 @@ func runSecretsSet(cmd *cobra.Command, args []string) {
 	if !requireServiceWithDeployment(os.Stderr, secretsSetDeployment, secretsSetService) {
 		os.Exit(1)
 	}
+	if secretsSetFromFile == "" && secretsSetBase64 {
+		fmt.Fprintf(os.Stderr, "%s Error: --base64 only applies to --from-file\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+	if secretsSetFromFile != "" && (len(args) > 1 || strings.Contains(args[0], "=")) {
+		fmt.Fprintf(os.Stderr, "%s Error: --from-file sets one secret; pass just its name\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
+	if strings.Contains(args[0], "=") {
+		values, err := parseSecretPairs(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+		cfg := loadConfig()
+		requireToken(cfg)
+		os.Exit(runSecretsSetManyCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, values, secretsSetDeployment, secretsSetService))
+	}
+	if len(args) > 2 {
+		fmt.Printf("%s Error: too many arguments; to set several secrets pass KEY=VALUE pairs\n", platform.Icon("❌", "[X]"))
+		os.Exit(1)
+	}
 	name := args[0]
 	value := ""
-	if len(args) == 2 {
+	if secretsSetFromFile != "" {
+		var err error
+		if value, err = readSecretFile(secretsSetFromFile, secretsSetBase64); err != nil {
+			fmt.Fprintf(os.Stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+	} else if len(args) == 2 {
 		value = args[1]
 	} else {
 		scanner := bufio.NewScanner(os.Stdin)
@@ -161,17 +312,17 @@ func runSecretsSet(cmd *cobra.Command, args []string) {
 	}
 
 	if value == "" {
-		fmt.Printf("%s Error: secret value is required (provide as second argument or via stdin)\n", platform.Icon("❌", "[X]"))
+		fmt.Printf("%s Error: secret value is required (provide as second argument, via stdin or with --from-file)\n", platform.Icon("❌", "[X]"))
 		os.Exit(1)
 	}
 
 	fmt.Printf("%s Setting secret '%s'...\n", platform.Icon("🌱", "[>]"), name)
 	fmt.Println()
 
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
 
-	res, err := secrets.CreateSecret(cfg.APIURL, cfg.APIToken, name, value, secretsSetDeployment, secretsSetService)
+	res, err := secrets.CreateSecret(cmd.Context(), cfg.APIURL, cfg.APIToken, name, value, secretsSetDeployment, secretsSetService)
 	if err != nil {
 		fmt.Printf("%s Failed to set secret: %v\n", platform.Icon("❌", "[X]"), err)
 		os.Exit(1)
@@ -187,25 +338,130 @@ func runSecretsSet(cmd *cobra.Command, args []string) {
 	}
 }
 
-func runSecretsGet(cmd *cobra.Command, args []string) {
-	if !requireServiceWithDeployment(os.Stderr, secretsGetDeployment, secretsGetService) {
-		os.Exit(1)
+// readSecretFile reads a secret value from path exactly as it is, newlines
+// included, e.g. a PEM key or JSON credentials. Binary content has to be
+// stored base64-encoded (b64), since secret values are text.
+func readSecretFile(path string, b64 bool) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
 	}
-	name := args[0]
+	if b64 {
+		return base64.StdEncoding.EncodeToString(b), nil
+	}
+	if !utf8.Valid(b) || bytes.IndexByte(b, 0) >= 0 {
+		return "", fmt.Errorf("%s is binary; pass --base64 to store it base64-encoded", path)
+	}
+	return string(b), nil
+}
 
-	cfg := config.Load()
+// parseSecretPairs parses `secrets set` KEY=VALUE arguments. The value is
+// everything after the first "=", so it may contain "=" itself.
+func parseSecretPairs(args []string) ([]secrets.SecretValue, error) {
+	values := make([]secrets.SecretValue, 0, len(args))
+	seen := make(map[string]bool, len(args))
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		switch {
+		case !ok || name == "":
+			return nil, fmt.Errorf("%q is not KEY=VALUE; pass every secret as KEY=VALUE when setting several", arg)
+		case value == "":
+			return nil, fmt.Errorf("the value of %s is empty", name)
+		case seen[name]:
+			return nil, fmt.Errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+		values = append(values, secrets.SecretValue{Name: name, Value: value})
+	}
+	return values, nil
+}
+
+// runSecretsSetManyCore is the testable inner implementation of `secrets
+// set KEY=VALUE...`. Returns the exit code: 1 if any secret failed.
+func runSecretsSetManyCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken string, values []secrets.SecretValue, deployment, service string) int {
+	if !requireServiceWithDeployment(stderr, deployment, service) {
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Setting %d secrets (%s)...\n", platform.Icon("🌱", "[>]"), len(values), scopeLabel(deployment, service))
+	fmt.Fprintln(stdout)
+
+	results, err := secrets.SetSecrets(ctx, apiURL, apiToken, values, deployment, service)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to set secrets: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			fmt.Fprintf(stderr, "  %s %s: %s\n", platform.Icon("❌", "[X]"), r.Name, r.Error)
+			continue
+		}
+		fmt.Fprintf(stdout, "  %s %s\n", platform.Icon("✅", "[OK]"), r.Name)
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintf(stdout, "Set %d of %d secrets.\n", len(results)-failed, len(results))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runSecretsGet(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
 	requireToken(cfg)
+	os.Exit(runSecretsGetCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], secretsGetDeployment, secretsGetService, secretsGetReveal, secretsGetQuiet))
+}
 
-	res, err := secrets.GetSecret(cfg.APIURL, cfg.APIToken, name, secretsGetDeployment, secretsGetService)
+// runSecretsGetCore is the testable inner implementation of `secrets get`.
+// The value is masked unless reveal is set; quiet prints only the raw
+// value, for pipes. Returns the exit code.
+func runSecretsGetCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, deployment, service string, reveal, quiet bool) int {
+	if !requireServiceWithDeployment(stderr, deployment, service) {
+		return 1
+	}
+	res, err := secrets.GetSecret(ctx, apiURL, apiToken, name, deployment, service)
 	if err != nil {
-		fmt.Printf("%s Failed to get secret: %v\n", platform.Icon("❌", "[X]"), err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "%s Failed to get secret: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
 	}
 
-	fmt.Print(res.Value)
-	if !strings.HasSuffix(res.Value, "\n") {
-		fmt.Println()
+	if quiet {
+		fmt.Fprint(stdout, res.Value)
+		if !strings.HasSuffix(res.Value, "\n") {
+			fmt.Fprintln(stdout)
+		}
+		return 0
+	}
+	value := maskSecret(res.Value) + "  (masked; --reveal to show, -q to print it alone)"
+	if reveal {
+		value = res.Value
+		if strings.Contains(value, "\n") {
+			value = "\n" + strings.TrimSuffix(value, "\n")
+		}
+	}
+	fmt.Fprintf(stdout, "Secret:   %s (%s)\n", name, scopeLabel(deployment, service))
+	fmt.Fprintf(stdout, "Value:    %s\n", value)
+	fmt.Fprintf(stdout, "Length:   %d characters", utf8.RuneCountInString(res.Value))
+	if lines := strings.Count(strings.TrimSuffix(res.Value, "\n"), "\n") + 1; lines > 1 {
+		fmt.Fprintf(stdout, ", %d lines", lines)
+	}
+	fmt.Fprintln(stdout)
+	if res.UpdatedAt != "" {
+		fmt.Fprintf(stdout, "Updated:  %s\n", res.UpdatedAt)
+	}
+	return 0
+}
+
+// maskSecret hides a secret value for display. Long values keep their last
+// four characters so two secrets can be told apart; short ones are fully
+// hidden.
+func maskSecret(value string) string {
+	r := []rune(value)
+	if len(r) < 16 || strings.ContainsAny(value, "\r\n") {
+		return "********"
 	}
+	return "********" + string(r[len(r)-4:])
 }
 
 func runSecretsDelete(cmd *cobra.Command, args []string) {
@@ -218,7 +474,7 @@ func runSecretsDelete(cmd *cobra.Command, args []string) {
 	fmt.Printf("%s Attempting to delete secret '%s' (%s)...\n", platform.Icon("🗑️", "[DEL]"), name, scope)
 	fmt.Println()
 
-	cfg := config.Load()
+	cfg := loadConfig()
 	requireToken(cfg)
 
 	if !secretsDeleteYes {
@@ -228,7 +484,7 @@ func runSecretsDelete(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	del, err := secrets.DeleteSecret(cfg.APIURL, cfg.APIToken, name, secretsDeleteDeployment, secretsDeleteService)
+	del, err := secrets.DeleteSecret(cmd.Context(), cfg.APIURL, cfg.APIToken, name, secretsDeleteDeployment, secretsDeleteService)
 	if err != nil {
 		fmt.Printf("%s Failed to delete secret: %v\n", platform.Icon("❌", "[X]"), err)
 		os.Exit(1)
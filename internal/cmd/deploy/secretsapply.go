@@ -0,0 +1,175 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var secretsApplyCmd = &cobra.Command{
+	Use:   "apply <file>",
+	Short: "Make the platform's secrets match a declared file",
+	Long: `Reads the secrets declared in a YAML file and makes a scope match it:
+secrets missing on the platform are created and ones with a different value
+are updated. With --prune, secrets the file doesn't declare are deleted.
+A plan is printed first and you are asked to confirm (skip with --yes).
+
+The file has a top-level secrets map. ${NAME} in a value is replaced with
+the local environment variable NAME, so the file can be committed and the
+values supplied by CI:
+
+  secrets:
+    LOG_LEVEL: info
+    STRIPE_KEY: ${STRIPE_KEY}
+
+Examples:
+  dibbla secrets apply secrets.yaml --deployment myapp
+  dibbla secrets apply secrets.yaml -d myapp --prune --yes
+  dibbla secrets apply secrets.yaml -d myapp --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSecretsApply,
+}
+
+var (
+	secretsApplyDeployment string
+	secretsApplyService    string
+	secretsApplyPrune      bool
+	secretsApplyDryRun     bool
+	secretsApplyYes        bool
+)
+
+func init() {
+	secretsCmd.AddCommand(secretsApplyCmd)
+
+	secretsApplyCmd.Flags().StringVarP(&secretsApplyDeployment, "deployment", "d", "", "Apply to this deployment's secrets (omit for global)")
+	secretsApplyCmd.Flags().StringVarP(&secretsApplyService, "service", "s", "", "Apply to a single service's secrets (requires -d)")
+	secretsApplyCmd.Flags().BoolVar(&secretsApplyPrune, "prune", false, "Delete secrets in the scope that the file doesn't declare")
+	secretsApplyCmd.Flags().BoolVar(&secretsApplyDryRun, "dry-run", false, "Print the plan without changing anything")
+	secretsApplyCmd.Flags().BoolVarP(&secretsApplyYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runSecretsApply(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	confirm := askConfirm
+	if secretsApplyYes {
+		confirm = nil
+	}
+	os.Exit(runSecretsApplyCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], os.LookupEnv,
+		secretsApplyDeployment, secretsApplyService, secretsApplyPrune, secretsApplyDryRun, confirm))
+}
+
+// runSecretsApplyCore is the testable inner implementation of `secrets
+// apply`. lookup resolves ${NAME} references in the file. confirm is asked
+// before changing anything; nil skips it. Returns the exit code: 1 if any
+// change failed.
+func runSecretsApplyCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, file string, lookup func(string) (string, bool), deployment, service string, prune, dryRun bool, confirm func(string) bool) int {
+	if !requireServiceWithDeployment(stderr, deployment, service) {
+		return 1
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to read secrets file: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	values, err := secrets.ParseSecretsFile(data, lookup)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s %s: %v\n", platform.Icon("❌", "[X]"), file, err)
+		return 1
+	}
+	scope := scopeLabel(deployment, service)
+	key := secrets.NewDigestKey()
+	remote, err := secrets.GetSecretDigests(ctx, apiURL, apiToken, key, deployment, service)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to get secrets (%s): %v\n", platform.Icon("❌", "[X]"), scope, err)
+		return 1
+	}
+
+	local := make(map[string]string, len(values))
+	for _, v := range values {
+		local[v.Name] = v.Value
+	}
+	diffs, _ := diffSecrets(local, remote, key)
+	var set []secrets.SecretValue
+	var extra []string
+	for _, d := range diffs {
+		if d.Status == "extra" {
+			extra = append(extra, d.Name)
+		} else {
+			set = append(set, secrets.SecretValue{Name: d.Name, Value: local[d.Name]})
+		}
+	}
+	if len(set) == 0 && (len(extra) == 0 || !prune) {
+		fmt.Fprintf(stdout, "%s Secrets (%s) already match %s\n", platform.Icon("✅", "[OK]"), scope, file)
+		if len(extra) > 0 {
+			fmt.Fprintf(stdout, "   %d secret(s) not in the file were left alone; --prune deletes them.\n", len(extra))
+		}
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "Plan for secrets (%s):\n", scope)
+	fmt.Fprintln(stdout)
+	for _, d := range diffs {
+		switch {
+		case d.Status == "missing":
+			fmt.Fprintf(stdout, "  + %s (create)\n", d.Name)
+		case d.Status == "changed":
+			fmt.Fprintf(stdout, "  ~ %s (update)\n", d.Name)
+		case prune:
+			fmt.Fprintf(stdout, "  - %s (delete)\n", d.Name)
+		}
+	}
+	fmt.Fprintln(stdout)
+	if len(extra) > 0 && !prune {
+		fmt.Fprintf(stdout, "%d secret(s) not in the file are left alone; --prune deletes them.\n", len(extra))
+		extra = nil
+	}
+	if dryRun {
+		fmt.Fprintln(stdout, "Dry run: nothing was changed.")
+		return 0
+	}
+	if confirm != nil && !confirm(fmt.Sprintf("Apply %d change(s) to secrets (%s)?", len(set)+len(extra), scope)) {
+		fmt.Fprintln(stdout, "Apply cancelled.")
+		return 0
+	}
+
+	failed := 0
+	if len(set) > 0 {
+		results, err := secrets.SetSecrets(ctx, apiURL, apiToken, set, deployment, service)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s Failed to set secrets: %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		for _, r := range results {
+			if r.Error != "" {
+				failed++
+				fmt.Fprintf(stderr, "  %s %s: %s\n", platform.Icon("❌", "[X]"), r.Name, r.Error)
+				continue
+			}
+			fmt.Fprintf(stdout, "  %s Set %s\n", platform.Icon("✅", "[OK]"), r.Name)
+		}
+	}
+	for _, name := range extra {
+		if _, err := secrets.DeleteSecret(ctx, apiURL, apiToken, name, deployment, service); err != nil {
+			failed++
+			fmt.Fprintf(stderr, "  %s %s: %v\n", platform.Icon("❌", "[X]"), name, err)
+			continue
+		}
+		fmt.Fprintf(stdout, "  %s Deleted %s\n", platform.Icon("✅", "[OK]"), name)
+	}
+	fmt.Fprintln(stdout)
+	total := len(set) + len(extra)
+	fmt.Fprintf(stdout, "Applied %d of %d changes.\n", total-failed, total)
+	if deployment != "" && failed < total {
+		fmt.Fprintf(stdout, "  They take effect when the app restarts: dibbla apps restart %s\n", deployment)
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+)
+
+// applyServer serves the digests of values and records the batch set and
+// the deletes that `secrets apply` sends.
+func applyServer(t *testing.T, values map[string]string) (*httptest.Server, *[]secrets.SecretValue, *[]string) {
+	t.Helper()
+	var set []secrets.SecretValue
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/deploy/secrets/digests":
+			digests := map[string]string{}
+			for name, v := range values {
+				digests[name] = secrets.Digest(r.Header.Get(secrets.DigestKeyHeader), v)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"digests": digests})
+		case r.URL.Path == "/api/deploy/secrets/batch":
+			var body struct {
+				Secrets []secrets.SecretValue `json:"secrets"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			set = append(set, body.Secrets...)
+			results := []secrets.SetResult{}
+			for _, s := range body.Secrets {
+				results = append(results, secrets.SetResult{Name: s.Name, Message: "ok"})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"results": results})
+		case r.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/api/deploy/secrets/"))
+			json.NewEncoder(w).Encode(secrets.DeleteResponse{Status: "success"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &set, &deleted
+}
+
+func writeSecretsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+var applyTestValues = map[string]string{
+	"SAME":    "one",
+	"CHANGED": "old",
+	"EXTRA":   "gone",
+}
+
+const applyTestFile = "secrets:\n  SAME: one\n  CHANGED: ${NEW_VALUE}\n  ADDED: two\n"
+
+func applyTestLookup(k string) (string, bool) {
+	if k == "NEW_VALUE" {
+		return "new", true
+	}
+	return "", false
+}
+
+func TestRunSecretsApplyCore_Prune(t *testing.T) {
+	srv, set, deleted := applyServer(t, applyTestValues)
+	var stdout, stderr bytes.Buffer
+	var asked string
+	confirm := func(msg string) bool { asked = msg; return true }
+	code := runSecretsApplyCore(context.Background(), &stdout, &stderr, srv.URL, "tok", writeSecretsFile(t, applyTestFile), applyTestLookup, "myapp", "", true, false, confirm)
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"+ ADDED (create)", "~ CHANGED (update)", "- EXTRA (delete)", "Applied 3 of 3 changes.", "dibbla apps restart myapp"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "SAME") {
+		t.Errorf("unchanged secret in plan:\n%s", out)
+	}
+	if asked != "Apply 3 change(s) to secrets (deployment myapp)?" {
+		t.Errorf("confirm = %q", asked)
+	}
+	if len(*set) != 2 || (*set)[0] != (secrets.SecretValue{Name: "ADDED", Value: "two"}) || (*set)[1] != (secrets.SecretValue{Name: "CHANGED", Value: "new"}) {
+		t.Errorf("set = %+v", *set)
+	}
+	if strings.Join(*deleted, ",") != "EXTRA" {
+		t.Errorf("deleted = %v", *deleted)
+	}
+}
+
+func TestRunSecretsApplyCore_KeepsExtraWithoutPrune(t *testing.T) {
+	srv, set, deleted := applyServer(t, applyTestValues)
+	var stdout, stderr bytes.Buffer
+	code := runSecretsApplyCore(context.Background(), &stdout, &stderr, srv.URL, "tok", writeSecretsFile(t, applyTestFile), applyTestLookup, "myapp", "", false, false, nil)
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(*set) != 2 || len(*deleted) != 0 {
+		t.Errorf("set = %+v, deleted = %v", *set, *deleted)
+	}
+	if !strings.Contains(stdout.String(), "1 secret(s) not in the file are left alone") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunSecretsApplyCore_DryRunAndCancel(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		dryRun  bool
+		confirm func(string) bool
+	}{
+		{"dry run", true, nil},
+		{"cancelled", false, func(string) bool { return false }},
+	} {
+		srv, set, deleted := applyServer(t, applyTestValues)
+		var stdout, stderr bytes.Buffer
+		code := runSecretsApplyCore(context.Background(), &stdout, &stderr, srv.URL, "tok", writeSecretsFile(t, applyTestFile), applyTestLookup, "myapp", "", true, tc.dryRun, tc.confirm)
+		if code != 0 || len(*set) != 0 || len(*deleted) != 0 {
+			t.Errorf("%s: exit %d, set = %+v, deleted = %v", tc.name, code, *set, *deleted)
+		}
+	}
+}
+
+func TestRunSecretsApplyCore_NothingToDo(t *testing.T) {
+	srv, _, _ := applyServer(t, map[string]string{"A": "1"})
+	var stdout, stderr bytes.Buffer
+	confirm := func(string) bool { t.Error("asked to confirm"); return false }
+	if code := runSecretsApplyCore(context.Background(), &stdout, &stderr, srv.URL, "tok", writeSecretsFile(t, "secrets:\n  A: 1\n"), applyTestLookup, "", "", true, false, confirm); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Secrets (global) already match") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunSecretsApplyCore_UnsetReference(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runSecretsApplyCore(context.Background(), &stdout, &stderr, "http://unused", "tok", writeSecretsFile(t, "secrets:\n  A: ${NOPE}\n"), applyTestLookup, "", "", false, false, nil)
+	if code != 1 || !strings.Contains(stderr.String(), "unset environment variable(s) NOPE") {
+		t.Errorf("exit %d, stderr = %q", code, stderr.String())
+	}
+}
@@ -0,0 +1,170 @@
+package deploy
+
+import (
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var secretsDiffCmd = &cobra.Command{
+	Use:   "diff <env-file>",
+	Short: "Compare a local env file with the platform's secrets",
+	Long: `Compares the keys and values of a local dotenv file with the secrets in a
+scope and reports keys that are missing on the platform, extra keys that
+the file doesn't have, and keys whose values differ. Values are compared by
+HMAC digests keyed for this run only; neither values nor digests are
+printed.
+
+The exit status is 0 when the two match, 1 when they differ and 2 on
+errors, so it can gate a deploy in CI.
+
+Examples:
+  dibbla secrets diff .env --deployment myapp
+  dibbla secrets diff .env.web -d myapp -s web
+  dibbla secrets diff .env.production -d myapp -o json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSecretsDiff,
+}
+
+var (
+	secretsDiffDeployment string
+	secretsDiffService    string
+	secretsDiffOutput     string
+)
+
+// Exit codes of `secrets diff`, as for diff(1).
+const (
+	secretsDiffSame    = 0
+	secretsDiffDiffers = 1
+	secretsDiffTrouble = 2
+)
+
+// secretDiff is one key that differs between the env file and the
+// platform. Status is missing (only in the file), extra (only on the
+// platform) or changed.
+type secretDiff struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// secretsDiffResult is the JSON output of `secrets diff`.
+type secretsDiffResult struct {
+	File        string       `json:"file"`
+	Deployment  string       `json:"deployment,omitempty"`
+	Service     string       `json:"service,omitempty"`
+	InSync      bool         `json:"in_sync"`
+	Unchanged   int          `json:"unchanged"`
+	Differences []secretDiff `json:"differences"`
+}
+
+func init() {
+	secretsCmd.AddCommand(secretsDiffCmd)
+
+	secretsDiffCmd.Flags().StringVarP(&secretsDiffDeployment, "deployment", "d", "", "Compare with this deployment's secrets (omit for global)")
+	secretsDiffCmd.Flags().StringVarP(&secretsDiffService, "service", "s", "", "Compare with a single service's secrets (requires -d)")
+	secretsDiffCmd.Flags().StringVarP(&secretsDiffOutput, "output", "o", "text", "Output format: text or json")
+}
+
+func runSecretsDiff(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	// requireToken exits 1, which here would read as "differs".
+	if !cfg.HasToken() {
+		fmt.Fprintf(os.Stderr, "%s API token is required: run 'dibbla login' or set DIBBLA_API_TOKEN\n", platform.Icon("❌", "[X]"))
+		os.Exit(secretsDiffTrouble)
+	}
+	os.Exit(runSecretsDiffCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], secretsDiffDeployment, secretsDiffService, secretsDiffOutput))
+}
+
+// diffSecrets compares local values with platform digests made under key,
+// sorted by key name. It returns the differences and how many keys match.
+func diffSecrets(local, platformDigests map[string]string, key string) ([]secretDiff, int) {
+	diffs := []secretDiff{}
+	unchanged := 0
+	for name, value := range local {
+		remote, ok := platformDigests[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, secretDiff{Name: name, Status: "missing"})
+		case !hmac.Equal([]byte(remote), []byte(secrets.Digest(key, value))):
+			diffs = append(diffs, secretDiff{Name: name, Status: "changed"})
+		default:
+			unchanged++
+		}
+	}
+	for name := range platformDigests {
+		if _, ok := local[name]; !ok {
+			diffs = append(diffs, secretDiff{Name: name, Status: "extra"})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs, unchanged
+}
+
+// runSecretsDiffCore is the testable inner implementation of `secrets
+// diff`. Returns secretsDiffSame, secretsDiffDiffers or secretsDiffTrouble.
+func runSecretsDiffCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, file, deployment, service, format string) int {
+	if format != "text" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected text or json)\n", platform.Icon("❌", "[X]"), format)
+		return secretsDiffTrouble
+	}
+	if !requireServiceWithDeployment(stderr, deployment, service) {
+		return secretsDiffTrouble
+	}
+	local, err := godotenv.Read(file)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to read env file %s: %v\n", platform.Icon("❌", "[X]"), file, err)
+		return secretsDiffTrouble
+	}
+	scope := scopeLabel(deployment, service)
+	key := secrets.NewDigestKey()
+	remote, err := secrets.GetSecretDigests(ctx, apiURL, apiToken, key, deployment, service)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to get secrets (%s): %v\n", platform.Icon("❌", "[X]"), scope, err)
+		return secretsDiffTrouble
+	}
+
+	diffs, unchanged := diffSecrets(local, remote, key)
+	code := secretsDiffSame
+	if len(diffs) > 0 {
+		code = secretsDiffDiffers
+	}
+	if format == "json" {
+		if writeDbJSON(stdout, stderr, secretsDiffResult{
+			File:        file,
+			Deployment:  deployment,
+			Service:     service,
+			InSync:      len(diffs) == 0,
+			Unchanged:   unchanged,
+			Differences: diffs,
+		}) != 0 {
+			return secretsDiffTrouble
+		}
+		return code
+	}
+
+	if len(diffs) == 0 {
+		fmt.Fprintf(stdout, "%s %s matches the secrets (%s): %d key(s)\n", platform.Icon("✅", "[OK]"), file, scope, unchanged)
+		return code
+	}
+	counts := map[string]int{}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tSTATUS")
+	for _, d := range diffs {
+		counts[d.Status]++
+		fmt.Fprintf(tw, "%s\t%s\n", d.Name, d.Status)
+	}
+	tw.Flush()
+	fmt.Fprintln(stdout)
+	fmt.Fprintf(stdout, "%s %s differs from the secrets (%s): %d missing, %d extra, %d changed, %d unchanged\n",
+		platform.Icon("⚠️", "[!]"), file, scope, counts["missing"], counts["extra"], counts["changed"], unchanged)
+	return code
+}
@@ -0,0 +1,91 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+)
+
+func writeEnvFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// digestsServer answers the digests endpoint like the API does: values
+// digested under the key the request carries.
+func digestsServer(t *testing.T, values map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(secrets.DigestKeyHeader)
+		if key == "" {
+			t.Error("no digest key sent")
+		}
+		digests := map[string]string{}
+		for name, v := range values {
+			digests[name] = secrets.Digest(key, v)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"digests": digests})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRunSecretsDiffCore_Differences(t *testing.T) {
+	file := writeEnvFile(t, "SAME=one\nCHANGED=local-value\nMISSING=new\n")
+	srv := digestsServer(t, map[string]string{"SAME": "one", "CHANGED": "platform-value", "EXTRA": "old"})
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsDiffCore(context.Background(), &stdout, &stderr, srv.URL, "tok", file, "myapp", "", "text"); code != 1 {
+		t.Fatalf("exit %d, want 1 (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"CHANGED  changed\n",
+		"EXTRA    extra\n",
+		"MISSING  missing\n",
+		"1 missing, 1 extra, 1 changed, 1 unchanged",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"local-value", "platform-value", "new"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("output shows value %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestRunSecretsDiffCore_InSync(t *testing.T) {
+	file := writeEnvFile(t, "A=1\n")
+	srv := digestsServer(t, map[string]string{"A": "1"})
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsDiffCore(context.Background(), &stdout, &stderr, srv.URL, "tok", file, "", "", "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var got secretsDiffResult
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || !got.InSync || got.Unchanged != 1 || len(got.Differences) != 0 {
+		t.Errorf("stdout = %q (%v)", stdout.String(), err)
+	}
+}
+
+func TestRunSecretsDiffCore_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsDiffCore(context.Background(), &stdout, &stderr, "http://unused", "tok", filepath.Join(t.TempDir(), "nope.env"), "", "", "text"); code != 2 {
+		t.Errorf("missing file: exit %d, want 2", code)
+	}
+	srv := newRestartServer(t, http.StatusInternalServerError, map[string]any{"error": map[string]string{"code": "INTERNAL", "message": "boom"}})
+	if code := runSecretsDiffCore(context.Background(), &stdout, &stderr, srv.URL, "tok", writeEnvFile(t, "A=1\n"), "myapp", "", "text"); code != 2 {
+		t.Errorf("API error: exit %d, want 2", code)
+	}
+}
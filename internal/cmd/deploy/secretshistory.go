@@ -0,0 +1,159 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var secretsHistoryCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "Show the versions of a secret",
+	Long: `Lists every version of a secret, newest first: when it was set, by whom,
+and which one is current. Values are not shown; use 'dibbla secrets
+rollback' to bring an earlier one back.
+
+Examples:
+  dibbla secrets history API_KEY
+  dibbla secrets history API_KEY -d myapp -o json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSecretsHistory,
+}
+
+var secretsRollbackCmd = &cobra.Command{
+	Use:   "rollback <name> --version <n>",
+	Short: "Restore an earlier version of a secret",
+	Long: `Makes an earlier version's value the secret's current one, e.g. after an
+accidental overwrite. The rollback is recorded as a new version, so it can
+be undone the same way. Apps see the value the next time they restart.
+
+Examples:
+  dibbla secrets rollback API_KEY --version 3 -d myapp`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSecretsRollback,
+}
+
+var (
+	secretsHistoryDeployment  string
+	secretsHistoryService     string
+	secretsHistoryOutput      string
+	secretsRollbackDeployment string
+	secretsRollbackService    string
+	secretsRollbackVersion    int
+	secretsRollbackYes        bool
+)
+
+func init() {
+	secretsCmd.AddCommand(secretsHistoryCmd)
+	secretsCmd.AddCommand(secretsRollbackCmd)
+
+	secretsHistoryCmd.Flags().StringVarP(&secretsHistoryDeployment, "deployment", "d", "", "Deployment-scoped secret")
+	secretsHistoryCmd.Flags().StringVarP(&secretsHistoryService, "service", "s", "", "Scope to a single service entry (requires -d)")
+	secretsHistoryCmd.Flags().StringVarP(&secretsHistoryOutput, "output", "o", "text", "Output format: text or json")
+	secretsRollbackCmd.Flags().StringVarP(&secretsRollbackDeployment, "deployment", "d", "", "Deployment-scoped secret")
+	secretsRollbackCmd.Flags().StringVarP(&secretsRollbackService, "service", "s", "", "Scope to a single service entry (requires -d)")
+	secretsRollbackCmd.Flags().IntVar(&secretsRollbackVersion, "version", 0, "Version to restore (see 'dibbla secrets history')")
+	secretsRollbackCmd.Flags().BoolVarP(&secretsRollbackYes, "yes", "y", false, "Skip confirmation prompt")
+	secretsRollbackCmd.MarkFlagRequired("version")
+}
+
+func runSecretsHistory(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runSecretsHistoryCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], secretsHistoryDeployment, secretsHistoryService, secretsHistoryOutput))
+}
+
+func runSecretsRollback(cmd *cobra.Command, args []string) {
+	name := args[0]
+	if !requireServiceWithDeployment(os.Stderr, secretsRollbackDeployment, secretsRollbackService) {
+		os.Exit(1)
+	}
+	if !secretsRollbackYes {
+		scope := scopeLabel(secretsRollbackDeployment, secretsRollbackService)
+		if !askConfirm(fmt.Sprintf("Roll back secret '%s' (%s) to version %d?", name, scope, secretsRollbackVersion)) {
+			fmt.Println("Rollback cancelled.")
+			os.Exit(0)
+		}
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runSecretsRollbackCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, name, secretsRollbackVersion, secretsRollbackDeployment, secretsRollbackService))
+}
+
+// runSecretsHistoryCore is the testable inner implementation of `secrets
+// history`. Returns the exit code.
+func runSecretsHistoryCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, deployment, service, format string) int {
+	if format != "text" && format != "json" {
+		fmt.Fprintf(stderr, "%s invalid --output %q (expected text or json)\n", platform.Icon("❌", "[X]"), format)
+		return 1
+	}
+	if !requireServiceWithDeployment(stderr, deployment, service) {
+		return 1
+	}
+	hist, err := secrets.GetSecretHistory(ctx, apiURL, apiToken, name, deployment, service)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to get history of secret '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	if format == "json" {
+		return writeDbJSON(stdout, stderr, hist)
+	}
+
+	scope := scopeLabel(deployment, service)
+	if len(hist.Versions) == 0 {
+		fmt.Fprintf(stdout, "No versions of secret '%s' (%s).\n", name, scope)
+		return 0
+	}
+	fmt.Fprintf(stdout, "Secret '%s' (%s), %d version(s):\n", name, scope, len(hist.Versions))
+	fmt.Fprintln(stdout)
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tCHANGE\tCREATED\tBY")
+	for _, v := range hist.Versions {
+		version := strconv.Itoa(v.Version)
+		if v.Current {
+			version += " (current)"
+		}
+		change := v.Action
+		if v.RolledBackFrom > 0 {
+			change = fmt.Sprintf("rollback to v%d", v.RolledBackFrom)
+		}
+		by := v.CreatedBy
+		if by == "" {
+			by = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", version, change, v.CreatedAt, by)
+	}
+	tw.Flush()
+	return 0
+}
+
+// runSecretsRollbackCore is the testable inner implementation of `secrets
+// rollback`, after confirmation. Returns the exit code.
+func runSecretsRollbackCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name string, version int, deployment, service string) int {
+	if version < 1 {
+		fmt.Fprintf(stderr, "%s --version must be a version number from 'dibbla secrets history %s'\n", platform.Icon("❌", "[X]"), name)
+		return 1
+	}
+	res, err := secrets.RollbackSecret(ctx, apiURL, apiToken, name, version, deployment, service)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to roll back secret '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	message := res.Message
+	if message == "" {
+		message = fmt.Sprintf("Secret '%s' rolled back to version %d", name, version)
+	}
+	fmt.Fprintf(stdout, "%s %s\n", platform.Icon("✅", "[OK]"), message)
+	fmt.Fprintf(stdout, "  Secret: %s (%s)\n", name, scopeLabel(deployment, service))
+	if deployment != "" {
+		fmt.Fprintf(stdout, "  It takes effect when the app restarts: dibbla apps restart %s\n", deployment)
+	}
+	return 0
+}
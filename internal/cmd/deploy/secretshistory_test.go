@@ -0,0 +1,59 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+)
+
+var testSecretHistory = secrets.SecretHistoryResponse{Name: "API_KEY", DeploymentAlias: "myapp", Versions: []secrets.SecretVersion{
+	{Version: 3, Action: "rollback", RolledBackFrom: 1, CreatedAt: "2026-10-14T09:00:00Z", CreatedBy: "ana@example.com", Current: true},
+	{Version: 2, Action: "set", CreatedAt: "2026-10-13T17:30:00Z", CreatedBy: "ci-token"},
+	{Version: 1, Action: "set", CreatedAt: "2026-09-01T12:00:00Z"},
+}}
+
+func TestRunSecretsHistoryCore_Table(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, testSecretHistory)
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsHistoryCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "API_KEY", "myapp", "", "text"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Secret 'API_KEY' (deployment myapp), 3 version(s)", "3 (current)  rollback to v1", "ana@example.com", "2026-09-01T12:00:00Z  -"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunSecretsHistoryCore_JSON(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, testSecretHistory)
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsHistoryCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "API_KEY", "myapp", "", "json"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	var got secrets.SecretHistoryResponse
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || len(got.Versions) != 3 {
+		t.Errorf("stdout = %q (%v)", stdout.String(), err)
+	}
+}
+
+func TestRunSecretsRollbackCore(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, secrets.SecretCreateResponse{Status: "success", Message: "Secret 'API_KEY' rolled back to version 2"})
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsRollbackCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "API_KEY", 2, "myapp", ""); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "rolled back to version 2") || !strings.Contains(stdout.String(), "dibbla apps restart myapp") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+
+	if code := runSecretsRollbackCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "API_KEY", 0, "", ""); code != 1 {
+		t.Errorf("--version 0: exit %d, want 1", code)
+	}
+}
@@ -0,0 +1,132 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/env"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var secretsPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Write a deployment's secrets into a local .env file",
+	Long: `Writes the secrets a deployment runs with into a dotenv file, so the app
+can be run locally with the same configuration: the global secrets, then
+the deployment's own (which win on a name clash), then with --service that
+service's. The file starts with a header saying where it came from and is
+written with 0600 permissions. Values are never printed.
+
+An existing file is not overwritten unless --force is passed. --merge
+updates the pulled keys in place and keeps everything else in the file.
+
+Keep the file out of version control; 'dibbla deploy' refuses to upload
+env files.
+
+Examples:
+  dibbla secrets pull --deployment myapp
+  dibbla secrets pull -d myapp --output .env.development --force
+  dibbla secrets pull -d myapp --merge`,
+	Args: cobra.NoArgs,
+	Run:  runSecretsPull,
+}
+
+var (
+	secretsPullDeployment string
+	secretsPullService    string
+	secretsPullOutput     string
+	secretsPullMerge      bool
+	secretsPullForce      bool
+)
+
+func init() {
+	secretsCmd.AddCommand(secretsPullCmd)
+
+	secretsPullCmd.Flags().StringVarP(&secretsPullDeployment, "deployment", "d", "", "Deployment to pull secrets for (required)")
+	secretsPullCmd.Flags().StringVarP(&secretsPullService, "service", "s", "", "Also pull this service's secrets (requires -d)")
+	secretsPullCmd.Flags().StringVarP(&secretsPullOutput, "output", "o", ".env.local", "File to write")
+	secretsPullCmd.Flags().BoolVar(&secretsPullMerge, "merge", false, "Update the pulled keys in an existing file and keep its other lines")
+	secretsPullCmd.Flags().BoolVar(&secretsPullForce, "force", false, "Overwrite an existing file")
+	secretsPullCmd.MarkFlagsMutuallyExclusive("merge", "force")
+}
+
+func runSecretsPull(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runSecretsPullCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken,
+		secretsPullDeployment, secretsPullService, secretsPullOutput, secretsPullMerge, secretsPullForce))
+}
+
+// runSecretsPullCore is the testable inner implementation of `secrets
+// pull`. Returns the exit code.
+func runSecretsPullCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, deployment, service, output string, merge, force bool) int {
+	if deployment == "" {
+		fmt.Fprintf(stderr, "%s --deployment is required\n", platform.Icon("❌", "[X]"))
+		return 1
+	}
+	if !requireServiceWithDeployment(stderr, deployment, service) {
+		return 1
+	}
+	_, statErr := os.Stat(output)
+	exists := statErr == nil
+	if statErr != nil && !errors.Is(statErr, fs.ErrNotExist) {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), statErr)
+		return 1
+	}
+	if exists && !merge && !force {
+		fmt.Fprintf(stderr, "%s %s already exists; pass --merge to update its keys or --force to overwrite it\n", platform.Icon("❌", "[X]"), output)
+		return 1
+	}
+
+	// Later scopes override earlier ones, as on the platform.
+	scopes := [][2]string{{"", ""}, {deployment, ""}}
+	if service != "" {
+		scopes = append(scopes, [2]string{deployment, service})
+	}
+	values := map[string]string{}
+	for _, sc := range scopes {
+		scoped, err := secrets.GetSecretValues(ctx, apiURL, apiToken, sc[0], sc[1])
+		if err != nil {
+			fmt.Fprintf(stderr, "%s Failed to get secrets (%s): %v\n", platform.Icon("❌", "[X]"), scopeLabel(sc[0], sc[1]), err)
+			return 1
+		}
+		for name, value := range scoped {
+			values[name] = value
+		}
+	}
+	if len(values) == 0 {
+		fmt.Fprintf(stdout, "No secrets found (%s); %s was not written.\n", scopeLabel(deployment, service), output)
+		return 0
+	}
+
+	if merge && exists {
+		if _, err := env.MergeEnvFile(output, values); err != nil {
+			fmt.Fprintf(stderr, "%s Failed to write %s: %v\n", platform.Icon("❌", "[X]"), output, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%s Merged %d secret(s) (%s) into %s\n", platform.Icon("✅", "[OK]"), len(values), scopeLabel(deployment, service), output)
+		return 0
+	}
+
+	pulled := "dibbla secrets pull -d " + deployment
+	if service != "" {
+		pulled += " -s " + service
+	}
+	header := []string{
+		fmt.Sprintf("Generated by '%s' on %s.", pulled, time.Now().UTC().Format(time.RFC3339)),
+		"Contains secret values: do not commit this file. Re-run the command to refresh it.",
+	}
+	if err := env.WriteEnvFile(output, header, values); err != nil {
+		fmt.Fprintf(stderr, "%s Failed to write %s: %v\n", platform.Icon("❌", "[X]"), output, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Wrote %d secret(s) (%s) to %s\n", platform.Icon("✅", "[OK]"), len(values), scopeLabel(deployment, service), output)
+	return 0
+}
@@ -0,0 +1,100 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+)
+
+// pullServer serves a global API_URL and LOG_LEVEL and, for myapp, its own
+// LOG_LEVEL and DATABASE_URL.
+func pullServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	scopes := map[string]map[string]string{
+		"":      {"API_URL": "https://api.example.com", "LOG_LEVEL": "info"},
+		"myapp": {"LOG_LEVEL": "debug", "DATABASE_URL": "postgres://u:p@db/app"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values := scopes[r.URL.Query().Get("deployment")]
+		if r.URL.Path == "/api/deploy/secrets" {
+			var list secrets.SecretsListResponse
+			for name := range values {
+				list.Secrets = append(list.Secrets, secrets.SecretListItem{Name: name})
+			}
+			json.NewEncoder(w).Encode(list)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/api/deploy/secrets/")
+		json.NewEncoder(w).Encode(secrets.SecretResponse{Name: name, Value: values[name]})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRunSecretsPullCore(t *testing.T) {
+	srv := pullServer(t)
+	path := filepath.Join(t.TempDir(), ".env.local")
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsPullCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "", path, false, false); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if !strings.HasPrefix(got, "# Generated by 'dibbla secrets pull -d myapp'") || !strings.Contains(got, "do not commit") {
+		t.Errorf("missing header:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "\nAPI_URL=https://api.example.com\nDATABASE_URL=postgres://u:p@db/app\nLOG_LEVEL=debug\n") {
+		t.Errorf("body:\n%s", got)
+	}
+	if strings.Contains(stdout.String(), "postgres://") {
+		t.Errorf("output shows a value:\n%s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "Wrote 3 secret(s) (deployment myapp)") {
+		t.Errorf("stdout:\n%s", stdout.String())
+	}
+}
+
+func TestRunSecretsPullCore_ExistingFile(t *testing.T) {
+	srv := pullServer(t)
+	path := filepath.Join(t.TempDir(), ".env.local")
+	if err := os.WriteFile(path, []byte("# mine\nPORT=3000\nLOG_LEVEL=warn\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsPullCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "", path, false, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "--merge") {
+		t.Errorf("stderr: %s", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := runSecretsPullCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "myapp", "", path, true, false); code != 0 {
+		t.Fatalf("merge: exit %d (stderr=%q)", code, stderr.String())
+	}
+	data, _ := os.ReadFile(path)
+	got := string(data)
+	if !strings.HasPrefix(got, "# mine\nPORT=3000\nLOG_LEVEL=debug\n") || !strings.Contains(got, "DATABASE_URL=") {
+		t.Errorf("merged file:\n%s", got)
+	}
+}
+
+func TestRunSecretsPullCore_RequiresDeployment(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsPullCore(context.Background(), &stdout, &stderr, "http://unused", "tok", "", "", ".env.local", false, false); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+}
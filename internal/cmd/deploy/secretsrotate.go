@@ -0,0 +1,202 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var secretsRotateCmd = &cobra.Command{
+	Use:   "rotate <name>",
+	Short: "Replace a secret with a new random value",
+	Long: `Generates a new random value for an existing secret and stores it. The
+value itself is never printed; use 'dibbla secrets get --reveal' if you
+need it. The old value stays in 'dibbla secrets history', so a rotation can
+be rolled back.
+
+Generators:
+  hex32      32 random bytes as 64 hex characters (default)
+  uuid       a random UUID
+  base64-48  48 random bytes as 64 URL-safe base64 characters
+
+--restart restarts the deployment (or just --service) afterwards so it
+picks the new value up. For a global secret it restarts every deployment
+that reads it, previews included, skipping those that override the name
+with a secret of their own. They are listed before the confirmation
+prompt. Stopped deployments are left stopped unless --include-stopped is
+given, which starts them again.
+
+Examples:
+  dibbla secrets rotate SESSION_SECRET -d myapp --restart
+  dibbla secrets rotate WEBHOOK_TOKEN -d myapp --generator uuid -y
+  dibbla secrets rotate SIGNING_KEY --restart --include-stopped`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSecretsRotate,
+}
+
+var (
+	secretsRotateDeployment string
+	secretsRotateService    string
+	secretsRotateGenerator  string
+	secretsRotateRestart    bool
+	secretsRotateStopped    bool
+	secretsRotateYes        bool
+)
+
+func init() {
+	secretsCmd.AddCommand(secretsRotateCmd)
+
+	secretsRotateCmd.Flags().StringVarP(&secretsRotateDeployment, "deployment", "d", "", "Deployment-scoped secret")
+	secretsRotateCmd.Flags().StringVarP(&secretsRotateService, "service", "s", "", "Scope to a single service entry (requires -d)")
+	secretsRotateCmd.Flags().StringVar(&secretsRotateGenerator, "generator", secrets.GeneratorHex32, "New value: "+strings.Join(secrets.Generators, ", "))
+	secretsRotateCmd.Flags().BoolVar(&secretsRotateRestart, "restart", false, "Restart the deployment (or --service) afterwards; for a global secret, every deployment using it")
+	secretsRotateCmd.Flags().BoolVar(&secretsRotateStopped, "include-stopped", false, "With --restart on a global secret, also restart (and so start) stopped deployments")
+	secretsRotateCmd.Flags().BoolVarP(&secretsRotateYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runSecretsRotate(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	confirm := askConfirm
+	if secretsRotateYes {
+		confirm = nil
+	}
+	os.Exit(runSecretsRotateCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0],
+		secretsRotateDeployment, secretsRotateService, secretsRotateGenerator, secretsRotateRestart, secretsRotateStopped, confirm))
+}
+
+// runSecretsRotateCore is the testable inner implementation of `secrets
+// rotate`. includeStopped also restarts stopped deployments when a global
+// secret is rotated with restart. confirm is asked before the value is
+// replaced; nil skips it. Returns the exit code.
+func runSecretsRotateCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, name, deployment, service, generator string, restart, includeStopped bool, confirm func(string) bool) int {
+	if !requireServiceWithDeployment(stderr, deployment, service) {
+		return 1
+	}
+	value, err := secrets.GenerateValue(generator)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+
+	scope := scopeLabel(deployment, service)
+	// Rotating is for existing secrets; a typo shouldn't create a new one.
+	list, err := secrets.ListSecrets(ctx, apiURL, apiToken, deployment, service)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to look up secret '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	found := false
+	for _, s := range list.Secrets {
+		found = found || s.Name == name
+	}
+	if !found {
+		fmt.Fprintf(stderr, "%s Secret '%s' not found (%s); create it with 'dibbla secrets set'\n", platform.Icon("❌", "[X]"), name, scope)
+		return 1
+	}
+
+	// Settle which deployments a global rotation restarts before anything
+	// changes, so they can be confirmed.
+	var users []apps.Deployment
+	question := fmt.Sprintf("Replace secret '%s' (%s) with a new random value? Anything using the old value stops working.", name, scope)
+	if restart && deployment == "" {
+		users, err = globalSecretUsers(ctx, stdout, apiURL, apiToken, name, includeStopped)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+			fmt.Fprintln(stderr, "  Nothing was rotated.")
+			return 1
+		}
+		question = fmt.Sprintf("Replace secret '%s' (%s) with a new random value and restart the %d deployments listed? Anything using the old value stops working.", name, scope, len(users))
+	}
+	if confirm != nil && !confirm(question) {
+		fmt.Fprintln(stdout, "Rotation cancelled.")
+		return 0
+	}
+	if _, err := secrets.CreateSecret(ctx, apiURL, apiToken, name, value, deployment, service); err != nil {
+		fmt.Fprintf(stderr, "%s Failed to rotate secret '%s': %v\n", platform.Icon("❌", "[X]"), name, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s Rotated secret '%s' (%s)\n", platform.Icon("✅", "[OK]"), name, scope)
+	fmt.Fprintf(stdout, "  New value: %s (%d characters, %s)\n", maskSecret(value), len(value), generator)
+
+	if !restart {
+		if deployment != "" {
+			fmt.Fprintf(stdout, "  It takes effect when the app restarts: dibbla apps restart %s\n", deployment)
+		}
+		return 0
+	}
+	if deployment == "" {
+		code := 0
+		for _, d := range users {
+			if restartLinkedApp(ctx, stdout, stderr, apiURL, apiToken, d.Alias, dbLinkOptions{Restart: true}) != 0 {
+				code = 1
+			}
+		}
+		return code
+	}
+	return restartLinkedApp(ctx, stdout, stderr, apiURL, apiToken, deployment, dbLinkOptions{Service: service, Restart: true})
+}
+
+// globalSecretUsers returns the deployments a rotation of the global
+// secret name restarts, sorted by alias, and lists them on w: all of them
+// except those with a deployment-scoped secret of the same name, which
+// shadows the global one, and, unless includeStopped, stopped ones.
+func globalSecretUsers(ctx context.Context, w io.Writer, apiURL, apiToken, name string, includeStopped bool) ([]apps.Deployment, error) {
+	deps, err := apps.ListApps(ctx, apiURL, apiToken, apps.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	all := deps.Deployments
+	sort.Slice(all, func(i, j int) bool { return all[i].Alias < all[j].Alias })
+
+	var users []apps.Deployment
+	var skipped []string
+	for _, d := range all {
+		if d.Status == apps.DeploymentStatusStopped && !includeStopped {
+			skipped = append(skipped, fmt.Sprintf("%s: stopped (--include-stopped starts it)", d.Alias))
+			continue
+		}
+		list, err := secrets.ListSecrets(ctx, apiURL, apiToken, d.Alias, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check secrets of '%s': %w", d.Alias, err)
+		}
+		shadowed := false
+		for _, s := range list.Secrets {
+			shadowed = shadowed || s.Name == name
+		}
+		if shadowed {
+			skipped = append(skipped, fmt.Sprintf("%s: it has its own '%s'", d.Alias, name))
+			continue
+		}
+		users = append(users, d)
+	}
+
+	if len(users) == 0 {
+		fmt.Fprintf(w, "No deployment reads the global '%s'; nothing to restart.\n", name)
+	} else {
+		fmt.Fprintf(w, "Deployments to restart after rotating '%s':\n", name)
+		for _, d := range users {
+			label := d.Alias
+			if d.Preview {
+				label += " (preview)"
+			}
+			fmt.Fprintf(w, "  %s\n", label)
+		}
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintln(w, "Not restarted:")
+		for _, s := range skipped {
+			fmt.Fprintf(w, "  %s\n", s)
+		}
+	}
+	fmt.Fprintln(w)
+	return users, nil
+}
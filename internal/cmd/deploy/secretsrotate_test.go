@@ -0,0 +1,165 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/secrets"
+)
+
+// rotateServer lists SESSION_SECRET in every scope and records the value
+// `secrets rotate` stores and the paths it restarts.
+func rotateServer(t *testing.T) (*httptest.Server, *string, *[]string) {
+	t.Helper()
+	var stored string
+	var restarted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/deploy/secrets":
+			json.NewEncoder(w).Encode(secrets.SecretsListResponse{Secrets: []secrets.SecretListItem{{Name: "SESSION_SECRET"}}, Total: 1})
+		case r.Method == "POST" && r.URL.Path == "/api/deploy/secrets":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			stored = body["value"]
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(secrets.SecretCreateResponse{Status: "success"})
+		case r.Method == "POST":
+			restarted = append(restarted, r.URL.Path)
+			json.NewEncoder(w).Encode(map[string]string{"status": "restarted"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &stored, &restarted
+}
+
+func TestRunSecretsRotateCore(t *testing.T) {
+	srv, stored, restarted := rotateServer(t)
+	var stdout, stderr bytes.Buffer
+	code := runSecretsRotateCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "SESSION_SECRET", "myapp", "", "uuid", true, false, func(string) bool { return true })
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(*stored) != 36 {
+		t.Errorf("stored value = %q, want a UUID", *stored)
+	}
+	out := stdout.String()
+	if strings.Contains(out, *stored) {
+		t.Errorf("output shows the new value:\n%s", out)
+	}
+	if !strings.Contains(out, "Rotated secret 'SESSION_SECRET' (deployment myapp)") || !strings.Contains(out, "(36 characters, uuid)") {
+		t.Errorf("stdout:\n%s", out)
+	}
+	if len(*restarted) != 1 || !strings.Contains((*restarted)[0], "myapp") {
+		t.Errorf("restarted = %v", *restarted)
+	}
+}
+
+func TestRunSecretsRotateCore_Refuses(t *testing.T) {
+	srv, stored, _ := rotateServer(t)
+	for _, tc := range []struct {
+		name, secret, deployment, generator string
+		restart                             bool
+		want                                string
+	}{
+		{"unknown secret", "TYPO", "myapp", "hex32", false, "Secret 'TYPO' not found"},
+		{"unknown generator", "SESSION_SECRET", "myapp", "hex16", false, "unknown generator"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := runSecretsRotateCore(context.Background(), &stdout, &stderr, srv.URL, "tok", tc.secret, tc.deployment, "", tc.generator, tc.restart, false, nil); code != 1 || !strings.Contains(stderr.String(), tc.want) {
+			t.Errorf("%s: exit %d, stderr = %q", tc.name, code, stderr.String())
+		}
+	}
+	if *stored != "" {
+		t.Errorf("stored %q", *stored)
+	}
+}
+
+// globalRotateServer lists web, api (which overrides SESSION_SECRET), the
+// preview web-feature-x and the stopped old, and records the paths
+// `secrets rotate` restarts.
+func globalRotateServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var restarted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/deploy/deployments":
+			json.NewEncoder(w).Encode(map[string]any{"deployments": []map[string]any{
+				{"alias": "web", "status": "running"},
+				{"alias": "api", "status": "running"},
+				{"alias": "web-feature-x", "status": "running", "preview": true, "preview_of": "web"},
+				{"alias": "old", "status": "stopped"},
+			}, "total": 4})
+		case r.Method == "GET" && r.URL.Path == "/api/deploy/secrets":
+			// api overrides SESSION_SECRET, so the global rotation doesn't reach it.
+			var items []secrets.SecretListItem
+			if d := r.URL.Query().Get("deployment"); d == "" || d == "api" {
+				items = append(items, secrets.SecretListItem{Name: "SESSION_SECRET"})
+			}
+			json.NewEncoder(w).Encode(secrets.SecretsListResponse{Secrets: items, Total: len(items)})
+		case r.Method == "POST" && r.URL.Path == "/api/deploy/secrets":
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(secrets.SecretCreateResponse{Status: "success"})
+		case r.Method == "POST":
+			restarted = append(restarted, r.URL.Path)
+			json.NewEncoder(w).Encode(map[string]string{"status": "restarted"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &restarted
+}
+
+func TestRunSecretsRotateCore_GlobalRestart(t *testing.T) {
+	srv, restarted := globalRotateServer(t)
+	var stdout, stderr bytes.Buffer
+	var listed string
+	confirm := func(q string) bool {
+		listed = stdout.String()
+		if !strings.Contains(q, "restart the 2 deployments listed") {
+			t.Errorf("question = %q", q)
+		}
+		return true
+	}
+	if code := runSecretsRotateCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "SESSION_SECRET", "", "", "hex32", true, false, confirm); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	want := "/api/deploy/deployments/web/restart,/api/deploy/deployments/web-feature-x/restart"
+	if got := strings.Join(*restarted, ","); got != want {
+		t.Errorf("restarted = %v, want web and web-feature-x", got)
+	}
+	for _, line := range []string{"  web\n", "  web-feature-x (preview)\n", "  api: it has its own 'SESSION_SECRET'", "  old: stopped (--include-stopped starts it)"} {
+		if !strings.Contains(listed, line) {
+			t.Errorf("output before the prompt misses %q:\n%s", line, listed)
+		}
+	}
+}
+
+func TestRunSecretsRotateCore_GlobalRestartIncludeStopped(t *testing.T) {
+	srv, restarted := globalRotateServer(t)
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsRotateCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "SESSION_SECRET", "", "", "hex32", true, true, nil); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(*restarted) != 3 || (*restarted)[0] != "/api/deploy/deployments/old/restart" {
+		t.Errorf("restarted = %v, want old, web and web-feature-x", *restarted)
+	}
+}
+
+func TestRunSecretsRotateCore_GlobalRestartCancelled(t *testing.T) {
+	srv, restarted := globalRotateServer(t)
+	var stdout, stderr bytes.Buffer
+	if code := runSecretsRotateCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "SESSION_SECRET", "", "", "hex32", true, false, func(string) bool { return false }); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if len(*restarted) != 0 || strings.Contains(stdout.String(), "Rotated") {
+		t.Errorf("restarted %v after cancelling:\n%s", *restarted, stdout.String())
+	}
+}
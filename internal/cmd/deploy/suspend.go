@@ -0,0 +1,90 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsStopCmd = &cobra.Command{
+	Use:   "stop <alias>",
+	Short: "Scale an app to zero without deleting it",
+	Long: `Stop a deployment by scaling it to zero replicas. Its configuration, env
+vars and URL are kept, and 'dibbla apps start' brings it back with the
+replica count it had. Useful to save cost on staging apps.
+
+Examples:
+  dibbla apps stop staging-shop
+  dibbla apps start staging-shop`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAppsStop,
+}
+
+var appsStartCmd = &cobra.Command{
+	Use:   "start <alias>",
+	Short: "Start a stopped app with its previous replica count",
+	Long: `Start a deployment stopped with 'dibbla apps stop', restoring the replica
+count it had before.
+
+Examples:
+  dibbla apps start staging-shop`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAppsStart,
+}
+
+func init() {
+	appsCmd.AddCommand(appsStopCmd)
+	appsCmd.AddCommand(appsStartCmd)
+}
+
+func runAppsStop(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runSuspendActionCore(cmd.Context(), os.Stdout, os.Stderr, apps.StopApp, cfg.APIURL, cfg.APIToken, args[0], "stop", appsOutput))
+}
+
+func runAppsStart(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runSuspendActionCore(cmd.Context(), os.Stdout, os.Stderr, apps.StartApp, cfg.APIURL, cfg.APIToken, args[0], "start", appsOutput))
+}
+
+// runSuspendActionCore runs apps.StopApp or apps.StartApp and reports it.
+// Returns the exit code.
+func runSuspendActionCore(ctx context.Context, stdout, stderr io.Writer, action func(ctx context.Context, apiURL, apiToken, alias string) (*apps.SuspendResponse, error), apiURL, apiToken, alias, verb, format string) int {
+	if err := validateAppsOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	out, err := action(ctx, apiURL, apiToken, alias)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s Failed to %s '%s': %v\n", platform.Icon("❌", "[X]"), verb, alias, err)
+		return 1
+	}
+	if format != "table" {
+		return writeAppsOutput(stdout, stderr, format, out)
+	}
+
+	switch verb {
+	case "stop":
+		fmt.Fprintf(stdout, "%s %s stopped (scaled to 0", platform.Icon("✅", "[OK]"), out.Alias)
+		if out.PreviousReplicas > 0 {
+			fmt.Fprintf(stdout, "; 'dibbla apps start %s' restores %d replica(s)", out.Alias, out.PreviousReplicas)
+		}
+		fmt.Fprintln(stdout, ")")
+	default:
+		fmt.Fprintf(stdout, "%s %s starting with %d replica(s)\n", platform.Icon("✅", "[OK]"), out.Alias, out.Replicas)
+		if out.URL != "" {
+			fmt.Fprintf(stdout, "   URL: %s\n", out.URL)
+		}
+	}
+	if out.Message != "" {
+		fmt.Fprintf(stdout, "   %s\n", out.Message)
+	}
+	return 0
+}
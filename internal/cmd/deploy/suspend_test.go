@@ -0,0 +1,33 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func TestRunSuspendActionCore_Stop(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{"alias": "staging", "status": "stopped", "replicas": 0, "previous_replicas": 2})
+	var stdout, stderr bytes.Buffer
+	if code := runSuspendActionCore(context.Background(), &stdout, &stderr, apps.StopApp, srv.URL, "tok", "staging", "stop", "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "staging stopped (scaled to 0; 'dibbla apps start staging' restores 2 replica(s))") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRunSuspendActionCore_StartError(t *testing.T) {
+	srv := newRestartServer(t, http.StatusConflict, map[string]any{
+		"status": "error", "error": map[string]any{"code": "NOT_STOPPED", "message": "deployment is already running"},
+	})
+	var stdout, stderr bytes.Buffer
+	code := runSuspendActionCore(context.Background(), &stdout, &stderr, apps.StartApp, srv.URL, "tok", "staging", "start", "table")
+	if code != 1 || !strings.Contains(stderr.String(), "Failed to start 'staging'") || !strings.Contains(stderr.String(), "NOT_STOPPED") {
+		t.Errorf("exit %d stderr=%q", code, stderr.String())
+	}
+}
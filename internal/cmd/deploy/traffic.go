@@ -0,0 +1,109 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var appsTrafficCmd = &cobra.Command{
+	Use:   "traffic <alias>",
+	Short: "Show or set how traffic is split between revisions",
+	Long: `Without flags, show how a deployment's traffic is split between its
+revisions. With --split, route traffic by weight, e.g. to move a new
+revision from 10% to 50% to 100% during a gradual rollout.
+
+Every revision in --split needs a percentage and the percentages must add
+up to 100; revisions left out get no traffic.
+
+Examples:
+  dibbla apps traffic myapp
+  dibbla apps traffic myapp --split v42=90,v43=10
+  dibbla apps traffic myapp --split v43=100`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAppsTraffic,
+}
+
+var trafficSplit string
+
+func init() {
+	appsCmd.AddCommand(appsTrafficCmd)
+	appsTrafficCmd.Flags().StringVar(&trafficSplit, "split", "", "Weighted routing, REVISION=PERCENT,... (must add up to 100)")
+}
+
+func runAppsTraffic(cmd *cobra.Command, args []string) {
+	var targets []apps.TrafficTarget
+	if cmd.Flags().Changed("split") {
+		var err error
+		targets, err = apps.ParseTrafficSplit(trafficSplit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s invalid --split: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+	}
+	cfg := loadConfig()
+	requireToken(cfg)
+	os.Exit(runAppsTrafficCore(cmd.Context(), os.Stdout, os.Stderr, cfg.APIURL, cfg.APIToken, args[0], targets, appsOutput))
+}
+
+// runAppsTrafficCore shows alias's traffic split, or sets it when targets
+// is non-empty. Returns the exit code.
+func runAppsTrafficCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken, alias string, targets []apps.TrafficTarget, format string) int {
+	if err := validateAppsOutput(format); err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+	var out *apps.TrafficResponse
+	var err error
+	if len(targets) > 0 {
+		out, err = apps.SetTraffic(ctx, apiURL, apiToken, alias, targets)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s Failed to set traffic split for '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+			return 1
+		}
+	} else {
+		out, err = apps.GetTraffic(ctx, apiURL, apiToken, alias)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s Failed to get traffic split for '%s': %v\n", platform.Icon("❌", "[X]"), alias, err)
+			return 1
+		}
+	}
+	if format != "table" {
+		return writeAppsOutput(stdout, stderr, format, out)
+	}
+
+	if len(targets) > 0 {
+		fmt.Fprintf(stdout, "%s Traffic split updated for '%s'\n\n", platform.Icon("✅", "[OK]"), alias)
+	}
+	if len(out.Targets) == 0 {
+		fmt.Fprintf(stdout, "No revisions are serving traffic for '%s'.\n", alias)
+		return 0
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REVISION\tTRAFFIC\t\tIMAGE\tCREATED")
+	for _, t := range out.Targets {
+		created := "-"
+		if t.CreatedAt != nil {
+			created = t.CreatedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%s\t%d%%\t%s\t%s\t%s\n", t.Revision, t.Percent, trafficBar(t.Percent), orDash(t.Image), created)
+	}
+	tw.Flush()
+	if out.Message != "" {
+		fmt.Fprintf(stdout, "\n%s\n", out.Message)
+	}
+	return 0
+}
+
+// trafficBar draws percent as a 20-cell bar.
+func trafficBar(percent int) string {
+	full := percent / 5
+	return strings.Repeat(platform.Icon("█", "#"), full) + strings.Repeat(platform.Icon("░", "."), 20-full)
+}
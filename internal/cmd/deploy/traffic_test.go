@@ -0,0 +1,52 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func TestRunAppsTrafficCore_Show(t *testing.T) {
+	srv := newRestartServer(t, http.StatusOK, map[string]any{
+		"alias": "shop",
+		"targets": []map[string]any{
+			{"revision": "v42", "percent": 90, "image": "sha256:aaa"},
+			{"revision": "v43", "percent": 10},
+		},
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runAppsTrafficCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", nil, "table"); code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"REVISION", "v42", "90%", "sha256:aaa", "v43", "10%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "updated") {
+		t.Errorf("show printed an update banner:\n%s", out)
+	}
+}
+
+func TestRunAppsTrafficCore_SetError(t *testing.T) {
+	srv := newRestartServer(t, http.StatusNotFound, map[string]any{
+		"status": "error", "error": map[string]any{"code": "REVISION_NOT_FOUND", "message": "no revision v99"},
+	})
+	var stdout, stderr bytes.Buffer
+	targets := []apps.TrafficTarget{{Revision: "v99", Percent: 100}}
+	code := runAppsTrafficCore(context.Background(), &stdout, &stderr, srv.URL, "tok", "shop", targets, "table")
+	if code != 1 || !strings.Contains(stderr.String(), "REVISION_NOT_FOUND") {
+		t.Errorf("exit %d stderr=%q", code, stderr.String())
+	}
+}
+
+func TestTrafficBar(t *testing.T) {
+	if got := []rune(trafficBar(50)); len(got) != 20 {
+		t.Errorf("bar width = %d", len(got))
+	}
+}
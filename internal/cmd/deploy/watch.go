@@ -0,0 +1,97 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/mattn/go-isatty"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// runAppsListWatch runs `apps list --watch` until Ctrl-C. On a terminal the
+// table is redrawn in place; otherwise each refresh is appended.
+func runAppsListWatch(ctx context.Context, apiURL, apiToken string, filter apps.ListOptions, table appsTable, interval time.Duration) int {
+	// Ctrl-C ends the watch cleanly rather than exiting the process.
+	ctx, cancel := interrupt.GracefulContext(ctx)
+	defer cancel()
+
+	tty := isatty.IsTerminal(os.Stdout.Fd())
+	return runAppsListWatchCore(ctx, os.Stdout, os.Stderr, apiURL, apiToken, filter, table, interval, tty)
+}
+
+// runAppsListWatchCore refreshes the apps table every interval until ctx is
+// cancelled, highlighting apps whose status changed since the previous
+// refresh. A failed refresh keeps the last table and notes the error; only
+// a failing first fetch is fatal. Returns the exit code.
+func runAppsListWatchCore(ctx context.Context, stdout, stderr io.Writer, apiURL, apiToken string, filter apps.ListOptions, table appsTable, interval time.Duration, tty bool) int {
+	var (
+		listed   []apps.Deployment
+		hidden   int
+		previous map[string]apps.DeploymentStatus
+		changed  map[string]bool
+	)
+	for first := true; ; first = false {
+		resp, err := apps.ListApps(ctx, apiURL, apiToken, filter)
+		if ctx.Err() != nil {
+			return 0
+		}
+		if err != nil && first {
+			fmt.Fprintf(stderr, "%s Failed to list applications: %v\n", platform.Icon("❌", "[X]"), err)
+			return 1
+		}
+		if err == nil {
+			listed, hidden = listed[:0], 0
+			current := make(map[string]apps.DeploymentStatus, len(resp.Deployments))
+			changed = map[string]bool{}
+			for _, dep := range resp.Deployments {
+				if dep.Preview {
+					hidden++
+					continue
+				}
+				listed = append(listed, dep)
+				current[dep.Alias] = dep.Status
+				if previous != nil && previous[dep.Alias] != dep.Status {
+					changed[dep.Alias] = true
+				}
+			}
+			previous = current
+		}
+
+		var frame bytes.Buffer
+		fmt.Fprintf(&frame, "Every %s: dibbla apps list (updated %s)\n\n", interval, time.Now().Format("15:04:05"))
+		if len(listed) == 0 {
+			fmt.Fprintln(&frame, "No applications match.")
+		} else {
+			table.print(&frame, listed, changed, tty)
+		}
+		printHiddenPreviews(&frame, hidden)
+		if len(changed) > 0 && !tty {
+			fmt.Fprintln(&frame, "\n* status changed since the last refresh")
+		}
+		if err != nil {
+			fmt.Fprintf(&frame, "\n%s Refresh failed (showing the last result): %v\n", platform.Icon("⚠️", "[!]"), err)
+		}
+
+		if tty {
+			fmt.Fprint(stdout, clearScreen)
+		} else if !first {
+			fmt.Fprintln(stdout)
+		}
+		_, _ = stdout.Write(frame.Bytes())
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return 0
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apps"
+)
+
+func TestRunAppsListWatchCore_HighlightsStatusChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		status := "building"
+		if n >= 2 {
+			status = "running"
+		}
+		if n >= 3 {
+			cancel()
+		}
+		fmt.Fprintf(w, `{"total":2,"deployments":[{"alias":"shop","status":%q},{"alias":"blog","status":"running"}]}`, status)
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := runAppsListWatchCore(ctx, &stdout, &stderr, srv.URL, "tok", apps.ListOptions{}, defaultTable(t), 10*time.Millisecond, false)
+	if code != 0 {
+		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
+	}
+	frames := strings.Split(stdout.String(), "Every ")
+	if len(frames) != 3 { // leading "" + two frames
+		t.Fatalf("got %d frames:\n%s", len(frames)-1, stdout.String())
+	}
+	if strings.Contains(frames[1], "*") {
+		t.Errorf("first frame should not highlight anything:\n%s", frames[1])
+	}
+	for _, line := range strings.Split(frames[2], "\n") {
+		switch {
+		case strings.HasPrefix(line, "shop ") && !strings.Contains(line, "running*"):
+			t.Errorf("shop should be highlighted: %q", line)
+		case strings.HasPrefix(line, "blog ") && strings.Contains(line, "*"):
+			t.Errorf("blog should not be highlighted: %q", line)
+		}
+	}
+}
+
+func TestRunAppsListWatchCore_FirstFetchFails(t *testing.T) {
+	srv := newRestartServer(t, http.StatusInternalServerError, map[string]any{"error": "boom"})
+	var stdout, stderr bytes.Buffer
+	code := runAppsListWatchCore(context.Background(), &stdout, &stderr, srv.URL, "tok", apps.ListOptions{}, defaultTable(t), time.Second, false)
+	if code != 1 || !strings.Contains(stderr.String(), "Failed to list applications") {
+		t.Errorf("exit %d stderr=%q", code, stderr.String())
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
-	"github.com/dibbla-agents/dibbla-cli/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -53,7 +52,7 @@ func runFeedback(cmd *cobra.Command, args []string) {
 	// when args don't match a subcommand, so treat them as a message.
 	message := strings.Join(args, " ")
 
-	cfg := config.Load()
+	cfg := loadConfig()
 	if cfg.APIToken == "" {
 		fmt.Fprintln(os.Stderr, "Not logged in. Run 'dibbla login' first.")
 		os.Exit(3)
@@ -90,7 +89,7 @@ type feedbackListItem struct {
 }
 
 func runFeedbackList(cmd *cobra.Command, args []string) {
-	cfg := config.Load()
+	cfg := loadConfig()
 	if cfg.APIToken == "" {
 		fmt.Fprintln(os.Stderr, "Not logged in. Run 'dibbla login' first.")
 		os.Exit(3)
@@ -133,7 +132,7 @@ func runFeedbackList(cmd *cobra.Command, args []string) {
 func runFeedbackDelete(cmd *cobra.Command, args []string) {
 	id := args[0]
 
-	cfg := config.Load()
+	cfg := loadConfig()
 	if cfg.APIToken == "" {
 		fmt.Fprintln(os.Stderr, "Not logged in. Run 'dibbla login' first.")
 		os.Exit(3)
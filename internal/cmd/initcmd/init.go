@@ -41,7 +41,7 @@ func (execRunner) Run(name string, args ...string) error {
 var (
 	defaultRunner  Runner = execRunner{}
 	executablePath        = os.Executable
-	hasToken              = func() bool { return config.Load().HasToken() }
+	hasToken              = func() bool { cfg, err := config.Load(); return err == nil && cfg.HasToken() }
 )
 
 var initCmd = &cobra.Command{
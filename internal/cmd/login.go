@@ -65,6 +65,11 @@ Persistence:
                        installed. Combine with --write-env to persist credentials
                        to the project's .env instead.
 
+With a profile in use (--profile, DIBBLA_PROFILE or 'dibbla config use-context'),
+the token and URL are saved to that profile in ~/.dibbla/config.yaml instead of the
+keyring, creating it if needed; its api_url is the default endpoint:
+  dibbla login --profile selfhosted https://dibbla.example.com
+
 In CI, set DIBBLA_API_TOKEN (and optionally DIBBLA_API_URL) in the shell environment or
 ./.env — the CLI reads both, and login is not required.`,
 	Args: cobra.MaximumNArgs(1),
@@ -80,7 +85,19 @@ func init() {
 }
 
 func runLogin(cmd *cobra.Command, args []string) {
-	baseURL, err := resolveLoginBaseURL(args)
+	userCfg, err := config.LoadUserConfig()
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	profileName := config.ProfileName(userCfg)
+	profile := userCfg.Profiles[profileName]
+
+	defaultURL := profile.APIURL
+	if profileName == "" {
+		defaultURL = userCfg.APIURL
+	}
+	baseURL, err := resolveLoginBaseURL(args, defaultURL)
 	if err != nil {
 		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
 		os.Exit(1)
@@ -91,6 +108,7 @@ func runLogin(cmd *cobra.Command, args []string) {
 	}
 
 	token := strings.TrimSpace(loginAPIKey)
+	issued := &auth.APIToken{}
 	if token == "" && loginBrowser {
 		// Over SSH the localhost-callback browser flow can't complete —
 		// the callback URL points at this host's loopback, not the
@@ -113,16 +131,15 @@ func runLogin(cmd *cobra.Command, args []string) {
 			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
 			os.Exit(1)
 		}
-		token = strings.TrimSpace(t)
+		issued, token = t, strings.TrimSpace(t.Token)
 	}
 	if token == "" {
-		var err error
-		token, err = acquireToken(baseURL)
+		t, err := acquireToken(baseURL)
 		if err != nil {
 			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
 			os.Exit(1)
 		}
-		token = strings.TrimSpace(token)
+		issued, token = t, strings.TrimSpace(t.Token)
 		if token == "" {
 			fmt.Printf("%s Error: API token is required\n", platform.Icon("❌", "[X]"))
 			os.Exit(1)
@@ -138,11 +155,32 @@ func runLogin(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Record when the token expires, so commands can warn before it does
+	// (or refresh it). The login flow may not say; then ask the API.
+	info := credential.TokenInfo{ExpiresAt: issued.ExpiresAt, RefreshToken: issued.RefreshToken, IssuedBy: baseURL}
+	if info.ExpiresAt == nil {
+		if id, err := apiclient.Whoami(cmd.Context(), baseURL, token); err == nil {
+			info.ExpiresAt = id.ExpiresAt
+		}
+	}
+
 	usedFileFallback := false
-	if !loginNoKeychain {
+	if !loginNoKeychain && profileName != "" {
+		profile.APIURL, profile.Token = baseURL, token
+		profile.TokenExpiresAt, profile.RefreshToken, profile.TokenIssuedBy = info.ExpiresAt, info.RefreshToken, info.IssuedBy
+		if err := config.SaveProfile(profileName, profile); err != nil {
+			fmt.Printf("%s Error: Token validated but failed to save profile %q: %v\n", platform.Icon("❌", "[X]"), profileName, err)
+			os.Exit(1)
+		}
+	} else if !loginNoKeychain {
 		err := credential.SetToken(token)
 		switch {
 		case err == nil:
+			if err := credential.SetTokenInfo(info); err != nil {
+				// The token works without it; only expiry warnings and
+				// refreshes are lost.
+				fmt.Printf("%s Warning: Token stored but failed to store its expiry: %v\n", platform.Icon("⚠️", "[!]"), err)
+			}
 			// Keychain succeeded — manage the URL through the keyring as before.
 			if baseURL != config.DefaultAPIURL {
 				if err := credential.SetAPIURL(baseURL); err != nil {
@@ -160,7 +198,11 @@ func runLogin(cmd *cobra.Command, args []string) {
 			if baseURL != config.DefaultAPIURL {
 				fileURL = baseURL
 			}
-			if ferr := credential.SetTokenFile(token, fileURL); ferr != nil {
+			ferr := credential.SetTokenFile(token, fileURL)
+			if ferr == nil {
+				ferr = credential.UpdateTokenFile(token, info)
+			}
+			if ferr != nil {
 				fmt.Printf("%s Error: OS keyring unavailable on this host AND file fallback failed: %v\n",
 					platform.Icon("❌", "[X]"), ferr)
 				os.Exit(1)
@@ -187,6 +229,8 @@ func runLogin(cmd *cobra.Command, args []string) {
 		fmt.Printf("%s Validated %s (keychain skipped, credentials in .env)\n", platform.Icon("✅", "[OK]"), baseURL)
 	case loginNoKeychain:
 		fmt.Printf("%s Validated %s (keychain skipped — re-run with --write-env to persist, or re-run without --no-keychain)\n", platform.Icon("✅", "[OK]"), baseURL)
+	case profileName != "":
+		fmt.Printf("%s Logged in to %s (profile %s)\n", platform.Icon("✅", "[OK]"), baseURL, profileName)
 	case usedFileFallback && loginWriteEnv:
 		fmt.Printf("%s Logged in to %s (credentials in %s and .env)\n",
 			platform.Icon("✅", "[OK]"), baseURL, credential.TokenFilePath())
@@ -246,7 +290,7 @@ func writeEnvAndGitignore(token, baseURL string) error {
 }
 
 // acquireToken presents the user with a choice of login methods and returns an API token.
-func acquireToken(baseURL string) (string, error) {
+func acquireToken(baseURL string) (*auth.APIToken, error) {
 	interactive := isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
 	if !interactive {
 		// Tailor the recovery options to context. Over SSH, --browser
@@ -254,11 +298,11 @@ func acquireToken(baseURL string) (string, error) {
 		// user's laptop) — leave it out so we don't lead the user
 		// into a 5-minute timeout.
 		if auth.IsSSHSession() {
-			return "", fmt.Errorf("non-interactive SSH session detected. Use one of:\n"+
+			return nil, fmt.Errorf("non-interactive SSH session detected. Use one of:\n"+
 				"  --api-key TOK     pass a token (create one at %s)\n"+
 				"  env DIBBLA_API_TOKEN=...   for headless CI", apiKeysURL)
 		}
-		return "", fmt.Errorf("non-interactive terminal detected. Use one of:\n"+
+		return nil, fmt.Errorf("non-interactive terminal detected. Use one of:\n"+
 			"  --browser         opens your browser (works in Claude Code, agentic shells, CI with a browser)\n"+
 			"  --api-key TOK     pass a token (create one at %s)\n"+
 			"  env DIBBLA_API_TOKEN=...   for headless CI", apiKeysURL)
@@ -288,7 +332,7 @@ func acquireToken(baseURL string) (string, error) {
 		Options: []string{optBrowser, optAPIToken},
 	}
 	if err := survey.AskOne(prompt, &method); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	switch method {
@@ -300,20 +344,20 @@ func acquireToken(baseURL string) (string, error) {
 }
 
 // browserLogin performs the browser-based OAuth login flow.
-func browserLogin(apiBaseURL string) (string, error) {
+func browserLogin(apiBaseURL string) (*auth.APIToken, error) {
 	// Derive the app URL for the auth UI.
 	appURL := config.DefaultAppURL
 	if apiBaseURL != config.DefaultAPIURL {
 		derived, err := auth.DeriveAppURL(apiBaseURL)
 		if err != nil {
-			return "", fmt.Errorf("cannot determine app URL for %s: %w\nUse 'Paste an API token' instead", apiBaseURL, err)
+			return nil, fmt.Errorf("cannot determine app URL for %s: %w\nUse 'Paste an API token' instead", apiBaseURL, err)
 		}
 		appURL = derived
 	}
 
 	state, err := auth.GenerateState()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
@@ -353,16 +397,16 @@ func browserLogin(apiBaseURL string) (string, error) {
 	result := <-resultCh
 	if result.Err != nil {
 		if ctx.Err() != nil {
-			return "", fmt.Errorf("login timed out after 5 minutes; try again or use --api-key")
+			return nil, fmt.Errorf("login timed out after 5 minutes; try again or use --api-key")
 		}
-		return "", result.Err
+		return nil, result.Err
 	}
 
 	fmt.Printf("%s Browser login successful! Creating API token...\n", platform.Icon("✅", "[OK]"))
 
 	apiToken, err := auth.ExchangeJWTForAPIToken(apiBaseURL, result.Token)
 	if err != nil {
-		return "", fmt.Errorf("failed to create API token: %w", err)
+		return nil, fmt.Errorf("failed to create API token: %w", err)
 	}
 
 	// Linger briefly so the browser can finish loading the success page
@@ -384,11 +428,13 @@ func browserLogin(apiBaseURL string) (string, error) {
 //      steprunner when injecting env into subprocesses — ensures `dibbla
 //      login` invoked from inside a task file targets the same service
 //      the parent CLI is logged into).
-//   4. config.DefaultAPIURL.
+//   4. defaultURL: the api_url of the profile being logged in to, or
+//      without a profile, api_url in ~/.dibbla/config.yaml.
+//   5. config.DefaultAPIURL.
 //
 // The keyring URL is intentionally NOT consulted here — a login command's
 // purpose is to set that value, so reading it back would be circular.
-func resolveLoginBaseURL(args []string) (string, error) {
+func resolveLoginBaseURL(args []string, defaultURL string) (string, error) {
 	flagURL := strings.TrimSpace(loginAPIURL)
 	var posURL string
 	if len(args) > 0 {
@@ -409,6 +455,9 @@ func resolveLoginBaseURL(args []string) (string, error) {
 	if u := strings.TrimSpace(os.Getenv("DIBBLA_AUTH_SERVICE_URL")); u != "" {
 		return normalizeAPIURL(u), nil
 	}
+	if defaultURL != "" {
+		return normalizeAPIURL(defaultURL), nil
+	}
 	return config.DefaultAPIURL, nil
 }
 
@@ -424,12 +473,12 @@ func normalizeAPIURL(input string) string {
 	return strings.TrimSuffix(input, "/")
 }
 
-func promptAPIToken() (string, error) {
+func promptAPIToken() (*auth.APIToken, error) {
 	var token string
 	prompt := &survey.Password{
 		Message: "API token:",
 		Help:    "Get your token at " + apiKeysURL,
 	}
 	err := survey.AskOne(prompt, &token)
-	return token, err
+	return &auth.APIToken{Token: token}, err
 }
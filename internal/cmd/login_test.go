@@ -114,7 +114,7 @@ func TestResolveLoginBaseURL_APIURLPositionalConflict(t *testing.T) {
 	t.Cleanup(func() { loginAPIURL = orig })
 
 	loginAPIURL = "https://api.dibbla.net"
-	_, err := resolveLoginBaseURL([]string{"https://api.dibbla.com"})
+	_, err := resolveLoginBaseURL([]string{"https://api.dibbla.com"}, "")
 	if err == nil {
 		t.Fatal("expected error when both --api-url and positional arg given")
 	}
@@ -133,7 +133,7 @@ func TestResolveLoginBaseURL_APIURLFlagWinsOverEnv(t *testing.T) {
 	t.Setenv("DIBBLA_API_URL", "https://api.from.env")
 	loginAPIURL = "https://api.from.flag"
 
-	got, err := resolveLoginBaseURL(nil)
+	got, err := resolveLoginBaseURL(nil, "")
 	if err != nil {
 		t.Fatalf("resolveLoginBaseURL: %v", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
 	"github.com/dibbla-agents/dibbla-cli/internal/credential"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 	"github.com/spf13/cobra"
@@ -14,11 +15,29 @@ var logoutCmd = &cobra.Command{
 	Short: "Remove stored API credentials",
 	Long: `Removes the API token and optional API URL stored by "dibbla login" from
 the OS credential store and from the user-level credentials file
-(used as a fallback on hosts where no keyring service is available).`,
+(used as a fallback on hosts where no keyring service is available).
+
+With a profile in use (--profile, DIBBLA_PROFILE or 'dibbla config
+use-context'), only that profile's token is removed.`,
 	Run: runLogout,
 }
 
 func runLogout(cmd *cobra.Command, args []string) {
+	name, profile, err := config.ActiveProfile()
+	if err != nil {
+		fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		os.Exit(1)
+	}
+	if profile != nil {
+		profile.Token, profile.TokenExpiresAt, profile.RefreshToken, profile.TokenIssuedBy = "", nil, "", ""
+		if err := config.SaveProfile(name, *profile); err != nil {
+			fmt.Printf("%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s Logged out of profile %s\n", platform.Icon("✅", "[OK]"), name)
+		return
+	}
+
 	// Keychain removal is best-effort: on hosts without libsecret the
 	// keyring lookup itself errors, but we don't want logout to fail
 	// just because there was nothing in the keyring to remove. Treat
@@ -28,6 +47,7 @@ func runLogout(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 	_ = credential.DeleteAPIURL()
+	_ = credential.DeleteTokenInfo()
 	// Always remove the user-level file too — it's where credentials
 	// land on hosts without a keyring, and keeping it would leave the
 	// user "logged in" by virtue of the fallback read path in config.Load.
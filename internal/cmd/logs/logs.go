@@ -6,8 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/mattn/go-isatty"
@@ -15,6 +13,7 @@ import (
 
 	"github.com/dibbla-agents/dibbla-cli/internal/applogs"
 	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 )
 
@@ -30,14 +29,43 @@ var (
 	flagPodStream bool
 )
 
-var logsCmd = &cobra.Command{
-	Use:   "logs <app>",
-	Short: "Print logs for a deployed app",
-	Long: `Print logs for one of your deployed apps.
+var logsCmd = newLogsCmd("logs [app]")
 
-By default prints the last 15 minutes of logs and exits.
+// NewAppsLogsCmd returns the same command for `dibbla apps logs <alias>`,
+// sharing flags and behavior with `dibbla logs`.
+func NewAppsLogsCmd() *cobra.Command {
+	return newLogsCmd("logs [alias]")
+}
+
+func newLogsCmd(use string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: "Print logs for a deployed app",
+		Long:  logsLong,
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runLogs,
+	}
+	cmd.Flags().DurationVar(&flagSince, "since", 15*time.Minute, "Show logs newer than this duration (e.g. 10m, 24h)")
+	cmd.Flags().BoolVarP(&flagFollow, "follow", "f", false, "Stream new log lines as they arrive (reconnects if the connection drops)")
+	cmd.Flags().IntVarP(&flagTail, "tail", "n", 0, "Show only the last N lines (0 = use --since window)")
+	cmd.Flags().StringVar(&flagGrep, "grep", "", "Server-side regex line filter (LogQL |~)")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Emit raw NDJSON instead of human-readable lines")
+	cmd.Flags().BoolVar(&flagNoColor, "no-color", false, "Disable color output")
+	cmd.Flags().IntVar(&flagLimit, "limit", 0, "Max lines to fetch in range mode (server caps the value; 0 = server default)")
+	cmd.Flags().StringVarP(&flagService, "service", "s", "", "Filter to a single service (forwarded as ?service=)")
+	cmd.Flags().BoolVar(&flagPodStream, "pod-stream", false, "Stream pod logs via the K8s API instead of Loki (requires --service)")
+	return cmd
+}
+
+const logsLong = `Print logs for one of your deployed apps. Also available as
+dibbla apps logs <alias>.
+
+By default prints the last 15 minutes of logs and exits. Without an app,
+default_deployment is used (dibbla config set default_deployment <alias>).
 
-Use -f / --follow to stream new lines as they arrive.
+Use -f / --follow to stream new lines as they arrive. If the connection
+drops, the stream is reopened from the last line received (with backoff),
+without repeating lines.
 Use -n / --tail N to print only the last N lines.
 
 Multi-service:
@@ -54,93 +82,65 @@ Examples:
   dibbla logs expense-reporter --grep "timeout"
   dibbla logs expense-reporter --json | jq .
   dibbla logs myapp --service worker -f
-  dibbla logs myapp --service web --pod-stream -f`,
-	Args: cobra.ExactArgs(1),
-	RunE: runLogs,
-}
-
-func init() {
-	logsCmd.Flags().DurationVar(&flagSince, "since", 15*time.Minute, "Show logs newer than this duration (e.g. 10m, 24h)")
-	logsCmd.Flags().BoolVarP(&flagFollow, "follow", "f", false, "Stream new log lines as they arrive")
-	logsCmd.Flags().IntVarP(&flagTail, "tail", "n", 0, "Show only the last N lines (0 = use --since window)")
-	logsCmd.Flags().StringVar(&flagGrep, "grep", "", "Server-side regex line filter (LogQL |~)")
-	logsCmd.Flags().BoolVar(&flagJSON, "json", false, "Emit raw NDJSON instead of human-readable lines")
-	logsCmd.Flags().BoolVar(&flagNoColor, "no-color", false, "Disable color output")
-	logsCmd.Flags().IntVar(&flagLimit, "limit", 0, "Max lines to fetch in range mode (server caps the value; 0 = server default)")
-	logsCmd.Flags().StringVarP(&flagService, "service", "s", "", "Filter to a single service (forwarded as ?service=)")
-	logsCmd.Flags().BoolVar(&flagPodStream, "pod-stream", false, "Stream pod logs via the K8s API instead of Loki (requires --service)")
-}
+  dibbla logs myapp --service web --pod-stream -f
+  dibbla apps logs myapp --follow --tail 200 --since 1h --grep ERROR`
 
 func runLogs(cmd *cobra.Command, args []string) error {
-	alias := args[0]
+	alias, err := config.AliasArg(args)
+	if err != nil {
+		return err
+	}
 
 	if flagPodStream && flagService == "" {
 		return fmt.Errorf("--pod-stream requires --service")
 	}
 
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
 	if !cfg.HasToken() {
 		fmt.Fprintf(os.Stderr, "%s Error: API token is required. Run `dibbla login` or set DIBBLA_API_TOKEN.\n", platform.Icon("❌", "[X]"))
 		os.Exit(1)
 	}
 
-	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	// Ctrl-C ends the stream/run cleanly rather than exiting the process.
+	ctx, cancel := interrupt.GracefulContext(cmd.Context())
 	defer cancel()
 
 	if flagPodStream {
 		return runPodStream(ctx, cfg.APIURL, cfg.APIToken, alias)
 	}
 
-	body, err := applogs.Stream(ctx, cfg.APIURL, cfg.APIToken, alias, applogs.Options{
+	opts := applogs.Options{
 		Since:   flagSince,
 		Limit:   flagLimit,
 		Tail:    flagTail,
 		Grep:    flagGrep,
 		Follow:  flagFollow,
 		Service: flagService,
-	})
+	}
+	useColor := !flagNoColor && !flagJSON && isatty.IsTerminal(os.Stdout.Fd())
+	emit := func(line []byte) { printLine(line, useColor) }
+
+	if flagFollow {
+		return logsError(alias, applogs.Follow(ctx, cfg.APIURL, cfg.APIToken, alias, opts, emit, os.Stderr))
+	}
+
+	body, err := applogs.Stream(ctx, cfg.APIURL, cfg.APIToken, alias, opts)
 	if err != nil {
-		var httpErr *applogs.HTTPError
-		if errors.As(err, &httpErr) {
-			switch httpErr.Status {
-			case 401, 403:
-				return fmt.Errorf("not authorized — check your API token (got %d)", httpErr.Status)
-			case 404:
-				return fmt.Errorf("app %q not found in your organization", alias)
-			case 503:
-				return fmt.Errorf("logs are not enabled on this Dibbla instance: %s", httpErr.Body)
-			}
-		}
-		return err
+		return logsError(alias, err)
 	}
 	defer body.Close()
 
-	useColor := !flagNoColor && !flagJSON && isatty.IsTerminal(os.Stdout.Fd())
-
 	scanner := bufio.NewScanner(body)
 	// Allow long log lines (default 64KB is small).
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
 	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-		if flagJSON {
-			fmt.Println(string(line))
-			continue
+		if line := scanner.Bytes(); len(line) > 0 {
+			emit(line)
 		}
-
-		entry, ok, derr := applogs.DecodeLine(line)
-		if derr != nil {
-			fmt.Fprintln(os.Stderr, "logs: "+derr.Error())
-			continue
-		}
-		if !ok {
-			// Already handled above (DecodeLine returned an error envelope).
-			continue
-		}
-		fmt.Println(applogs.FormatEntry(entry, useColor))
 	}
 	if err := scanner.Err(); err != nil {
 		// Cancelled streams produce a context error — exit quietly.
@@ -152,6 +152,42 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printLine writes one NDJSON row from the logs endpoint to stdout, raw
+// under --json and formatted otherwise. Server error envelopes go to stderr.
+func printLine(line []byte, useColor bool) {
+	if flagJSON {
+		fmt.Println(string(line))
+		return
+	}
+	entry, ok, derr := applogs.DecodeLine(line)
+	if derr != nil {
+		fmt.Fprintln(os.Stderr, "logs: "+derr.Error())
+		return
+	}
+	if !ok {
+		// Already handled above (DecodeLine returned an error envelope).
+		return
+	}
+	fmt.Println(applogs.FormatEntry(entry, useColor))
+}
+
+// logsError turns HTTP failures from the logs endpoint into actionable
+// messages. nil passes through.
+func logsError(alias string, err error) error {
+	var httpErr *applogs.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.Status {
+		case 401, 403:
+			return fmt.Errorf("not authorized — check your API token (got %d)", httpErr.Status)
+		case 404:
+			return fmt.Errorf("app %q not found in your organization", alias)
+		case 503:
+			return fmt.Errorf("logs are not enabled on this Dibbla instance: %s", httpErr.Body)
+		}
+	}
+	return err
+}
+
 // runPodStream consumes the text/plain pod-log stream from the K8s-direct
 // endpoint and copies it to stdout verbatim. The server already prefixes each
 // line with `[<pod>] ` so no per-line decoding is needed.
@@ -40,3 +40,15 @@ func TestRunLogs_PodStreamRequiresService(t *testing.T) {
 		t.Errorf("unexpected err: %v", err)
 	}
 }
+
+func TestAppsLogsCmdSharesFlags(t *testing.T) {
+	cmd := NewAppsLogsCmd()
+	if cmd.Name() != "logs" {
+		t.Errorf("name = %q, want logs", cmd.Name())
+	}
+	for _, name := range []string{"follow", "tail", "since", "grep", "service", "json"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("apps logs is missing --%s", name)
+		}
+	}
+}
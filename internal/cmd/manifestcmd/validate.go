@@ -39,6 +39,9 @@ server. For those, use 'dibbla preview'.
 Exits 0 if valid (or if no manifest is found and the dir would deploy via the
 legacy single-Dockerfile path) and 1 with the error code on the first failure.
 
+--profile names manifest profiles from dibbla.yaml, not a profile from
+~/.dibbla/config.yaml (that is --context).
+
 Examples:
   dibbla manifest validate              # validate ./dibbla.yaml
   dibbla manifest validate ./myapp      # validate ./myapp/dibbla.yaml
@@ -51,7 +54,7 @@ Examples:
 
 func init() {
 	validateCmd.Flags().StringVar(&validateTargetEnv, "target-env", "", "Manifest env name to record in the report (informational; resolution is server-side)")
-	validateCmd.Flags().StringArrayVar(&validateProfiles, "profile", nil, "Activate a manifest profile (repeatable; informational)")
+	validateCmd.Flags().StringArrayVar(&validateProfiles, "profile", nil, "Activate a manifest profile from dibbla.yaml (repeatable; informational); the CLI profile is --context")
 	validateCmd.Flags().BoolVar(&validateNoPublic, "no-public", false, "Allow zero public services (informational; the local check accepts both)")
 	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "Emit a structured JSON report instead of human text")
 }
@@ -8,6 +8,7 @@
 package preview
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -43,15 +44,19 @@ Use this when:
 
 For local-only schema checks (no network), use 'dibbla manifest validate'.
 
+--profile activates manifest profiles from dibbla.yaml. To preview against
+a profile from ~/.dibbla/config.yaml (its API URL and token), use --context.
+
 Examples:
   dibbla preview                              # ./, defaults to env=prod
   dibbla preview ./myapp --target-env staging
   dibbla preview --profile mailcatcher --profile metrics
+  dibbla preview --context self-hosted        # against another instance
   dibbla preview --no-public                  # worker-only is OK
   dibbla preview --json                       # machine-readable`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		os.Exit(runPreview(os.Stdout, os.Stderr, args))
+		os.Exit(runPreview(cmd.Context(), os.Stdout, os.Stderr, args))
 	},
 }
 
@@ -63,7 +68,7 @@ func Register(root *cobra.Command) {
 func init() {
 	previewCmd.Flags().StringVarP(&previewAlias, "alias", "a", "", "Custom alias name (default: directory name)")
 	previewCmd.Flags().StringVar(&previewTargetEnv, "target-env", "", "Manifest env name to resolve (defaults to 'prod' server-side)")
-	previewCmd.Flags().StringArrayVar(&previewProfiles, "profile", nil, "Activate a manifest profile (repeatable)")
+	previewCmd.Flags().StringArrayVar(&previewProfiles, "profile", nil, "Activate a manifest profile from dibbla.yaml (repeatable); the CLI profile is --context")
 	previewCmd.Flags().BoolVar(&previewNoPublic, "no-public", false, "Allow preview with no public:true service")
 	previewCmd.Flags().StringVar(&previewPort, "port", "", "Forwarded as 'port' field — used by the no-manifest synthesizer")
 	previewCmd.Flags().BoolVar(&previewJSON, "json", false, "Emit raw PreviewResponse JSON instead of human text")
@@ -71,8 +76,12 @@ func init() {
 
 // runPreview is the testable entry point. Returns exit code: 0 if valid,
 // 1 if the server reported errors or the request failed.
-func runPreview(stdout, stderr io.Writer, args []string) int {
-	cfg := config.Load()
+func runPreview(ctx context.Context, stdout, stderr io.Writer, args []string) int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
 	if !cfg.HasToken() {
 		fmt.Fprintf(stderr, "%s API token is required (run 'dibbla login' or set DIBBLA_API_TOKEN)\n",
 			platform.Icon("❌", "[X]"))
@@ -85,6 +94,7 @@ func runPreview(stdout, stderr io.Writer, args []string) int {
 	}
 
 	opts := deploypkg.PreviewOptions{
+		Context:   ctx,
 		APIURL:    cfg.APIURL,
 		APIToken:  cfg.APIToken,
 		Path:      path,
@@ -2,6 +2,7 @@ package preview
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -68,7 +69,7 @@ func TestRunPreview_HumanOutput_Valid(t *testing.T) {
 	withAPI(t, srv.URL)
 
 	var stdout, stderr bytes.Buffer
-	code := runPreview(&stdout, &stderr, []string{dir})
+	code := runPreview(context.Background(), &stdout, &stderr, []string{dir})
 	if code != 0 {
 		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
 	}
@@ -113,7 +114,7 @@ func TestRunPreview_JSONPassthrough(t *testing.T) {
 	withAPI(t, srv.URL)
 
 	var stdout, stderr bytes.Buffer
-	if code := runPreview(&stdout, &stderr, []string{dir}); code != 0 {
+	if code := runPreview(context.Background(), &stdout, &stderr, []string{dir}); code != 0 {
 		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
 	}
 	var got deploypkg.PreviewResponse
@@ -140,7 +141,7 @@ func TestRunPreview_InvalidExitsOneAndPrintsErrors(t *testing.T) {
 	withAPI(t, srv.URL)
 
 	var stdout, stderr bytes.Buffer
-	if code := runPreview(&stdout, &stderr, []string{dir}); code != 1 {
+	if code := runPreview(context.Background(), &stdout, &stderr, []string{dir}); code != 1 {
 		t.Fatalf("expected exit 1, got %d", code)
 	}
 	se := stderr.String()
@@ -158,7 +159,7 @@ func TestRunPreview_NoTokenExitsOneBeforeUpload(t *testing.T) {
 	t.Setenv("DIBBLA_API_URL", "")
 	t.Setenv("CI", "1")
 	var stdout, stderr bytes.Buffer
-	if code := runPreview(&stdout, &stderr, []string{t.TempDir()}); code != 1 {
+	if code := runPreview(context.Background(), &stdout, &stderr, []string{t.TempDir()}); code != 1 {
 		t.Fatalf("exit %d", code)
 	}
 	if !strings.Contains(stderr.String(), "API token is required") {
@@ -180,7 +181,7 @@ func TestRunPreview_LocalManifestErrorBeforeUpload(t *testing.T) {
 	withAPI(t, srv.URL)
 
 	var stdout, stderr bytes.Buffer
-	if code := runPreview(&stdout, &stderr, []string{dir}); code != 1 {
+	if code := runPreview(context.Background(), &stdout, &stderr, []string{dir}); code != 1 {
 		t.Fatalf("exit %d (stderr=%q)", code, stderr.String())
 	}
 	if !strings.Contains(stderr.String(), "manifest validation failed") {
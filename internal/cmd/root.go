@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 
@@ -17,15 +18,19 @@ import (
 	"github.com/dibbla-agents/dibbla-cli/internal/cmd/uninstall"
 	updatecmd "github.com/dibbla-agents/dibbla-cli/internal/cmd/update"
 	"github.com/dibbla-agents/dibbla-cli/internal/cmd/wf"
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
 	"github.com/dibbla-agents/dibbla-cli/internal/update"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Version is set at build time via ldflags
 var Version = "dev"
 
 var skillPrompt bool
+var profileFlag string
 var checkInBackground = update.CheckInBackground
 var printNotice = update.PrintNotice
 
@@ -54,10 +59,17 @@ Get started:
 func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("dibbla version %s\n", Version))
 	rootCmd.Flags().BoolVar(&skillPrompt, "skill-prompt", false, "Show detailed instructions for LLM-based tools")
+	// deploy, preview and manifest validate have a --profile of their own
+	// (manifest profiles), so the CLI profile is --context, with --profile
+	// as an alias everywhere else (see aliasProfileFlag).
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "context", "", "Profile from ~/.dibbla/config.yaml to use (default: DIBBLA_PROFILE, then 'dibbla config use-context'); --profile too, on commands without a --profile of their own")
+	cobra.OnInitialize(func() { config.SelectProfile(profileFlag) })
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(logoutCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(whoamiCmd)
 	rootCmd.AddCommand(feedbackCmd)
+	rootCmd.AddCommand(configCmd)
 	deploycmd.Register(rootCmd)
 	wf.Register(rootCmd)
 	run.Register(rootCmd)
@@ -71,6 +83,23 @@ func init() {
 	preview.Register(rootCmd)
 	admincmd.Register(rootCmd)
 	aigateway.Register(rootCmd)
+	aliasProfileFlag(rootCmd)
+}
+
+// aliasProfileFlag makes --profile an alias of the global --context on c
+// and its subcommands, except on those with a --profile of their own.
+func aliasProfileFlag(c *cobra.Command) {
+	if c.Flags().Lookup("profile") == nil && c.PersistentFlags().Lookup("profile") == nil {
+		c.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+			if name == "profile" {
+				name = "context"
+			}
+			return pflag.NormalizedName(name)
+		})
+	}
+	for _, sub := range c.Commands() {
+		aliasProfileFlag(sub)
+	}
 }
 
 // Execute runs the root command.
@@ -81,10 +110,15 @@ func init() {
 // godotenv.Load() does not overwrite vars already present in the shell env, so
 // explicit shell exports still win over .env. Centralizing it here avoids
 // having each command remember to call godotenv.Load() individually.
+//
+// Commands run under a context that Ctrl-C / SIGTERM cancels; see package
+// interrupt for the shutdown sequence and exit code.
 func Execute() error {
 	_ = godotenv.Load()
+	ctx, stop := interrupt.Notify(context.Background())
+	defer stop()
 	ch := checkInBackground(Version)
-	err := rootCmd.Execute()
+	err := rootCmd.ExecuteContext(ctx)
 	if ch != nil {
 		select {
 		case info := <-ch:
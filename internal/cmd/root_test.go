@@ -90,3 +90,36 @@ func TestExecute_PrintsWhenUpdateResultReady(t *testing.T) {
 		t.Fatal("expected printNotice to be called for ready update result")
 	}
 }
+
+func TestProfileFlagAlias(t *testing.T) {
+	context := rootCmd.PersistentFlags().Lookup("context")
+	t.Cleanup(func() { context.Value.Set(""); context.Changed = false })
+
+	if err := statusCmd.ParseFlags([]string{"--profile", "staging"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := context.Value.String(); got != "staging" {
+		t.Errorf("status --profile: --context = %q, want staging", got)
+	}
+
+	// No command may shadow the global flag, and commands with a
+	// --profile of their own keep it.
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if c != rootCmd && (c.LocalNonPersistentFlags().Lookup("context") != nil || c.PersistentFlags().Lookup("context") != nil) {
+			t.Errorf("%s has a --context of its own", c.CommandPath())
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+
+	deploy, _, err := rootCmd.Find([]string{"deploy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := deploy.Flags().Lookup("profile"); f == nil || f.Usage == context.Usage {
+		t.Errorf("deploy --profile = %+v, want the manifest profile flag", f)
+	}
+}
@@ -4,10 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/signal"
 	"path/filepath"
 	"strings"
-	"syscall"
 
 	steprunner "github.com/dibbla-agents/dibbla-tasks"
 	"github.com/dibbla-agents/dibbla-tasks/model"
@@ -16,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
 	cliout "github.com/dibbla-agents/dibbla-cli/internal/output"
 )
 
@@ -108,7 +107,8 @@ func ExecuteTask(ctx context.Context, arg string, opts TaskOpts) error {
 		return nil
 	}
 
-	runCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	// Ctrl-C ends the stream/run cleanly rather than exiting the process.
+	runCtx, stop := interrupt.GracefulContext(ctx)
 	defer stop()
 
 	runOpts := []steprunner.Option{
@@ -226,7 +226,10 @@ func buildEnv(envFlags []string, envFile string) (map[string]string, error) {
 		env[k] = v
 	}
 
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
 	if cfg.HasToken() {
 		env["DIBBLA_API_TOKEN"] = cfg.APIToken
 		env["DIBBLA_AUTH_SERVICE_URL"] = cfg.APIURL
@@ -32,8 +32,13 @@ state of the token (revoked / expired tokens show as not logged in). Use
 
 The "source" annotations show where each value came from. Resolution order
 matches the rest of the CLI:
-  API URL: DIBBLA_API_URL > DIBBLA_AUTH_SERVICE_URL > keyring > credentials file > default
+  API URL: DIBBLA_API_URL > DIBBLA_AUTH_SERVICE_URL > keyring > credentials file >
+           api_url in ~/.dibbla/config.yaml > default
   Token:   DIBBLA_API_TOKEN > keyring > credentials file > none
+With a profile in use (--context, DIBBLA_PROFILE, the project's
+.dibbla/config.yaml or 'dibbla config use-context'), the profile takes the
+place of the keyring and credentials file; with DIBBLA_API_TOKEN set, none
+is used.
 
 Exit codes:
   0  logged in (or --no-validate and a token is configured)
@@ -50,6 +55,8 @@ func init() {
 
 type statusReport struct {
 	Version         string `json:"version"`
+	Profile         string `json:"profile,omitempty"`
+	ProjectConfig   string `json:"project_config,omitempty"`
 	APIURL          string `json:"api_url"`
 	APIURLSource    string `json:"api_url_source"`
 	TokenConfigured bool   `json:"token_configured"`
@@ -60,6 +67,14 @@ type statusReport struct {
 }
 
 func runStatus(cmd *cobra.Command, args []string) {
+	if _, err := config.LoadProjectConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if _, _, err := activeProfile(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	report := buildStatusReport(statusNoValidate)
 
 	if statusJSON {
@@ -85,17 +100,23 @@ func runStatus(cmd *cobra.Command, args []string) {
 // optionally validates the token. Pulled out of runStatus so tests can
 // drive it without touching the cobra command or os.Exit.
 func buildStatusReport(noValidate bool) statusReport {
-	apiURL, apiURLSource := resolveAPIURLWithSource()
-	token, tokenSource := resolveTokenWithSource()
+	profileName, profile, _ := activeProfile()
+	apiURL, apiURLSource := resolveAPIURLWithSource(profileName, profile)
+	token, tokenSource := resolveTokenWithSource(profileName, profile)
 
 	r := statusReport{
 		Version:         Version,
+		Profile:         profileName,
 		APIURL:          apiURL,
 		APIURLSource:    apiURLSource,
 		TokenConfigured: token != "",
 		TokenSource:     tokenSource,
 	}
 
+	if pc, _ := config.LoadProjectConfig(); pc != nil {
+		r.ProjectConfig = pc.Path
+	}
+
 	if !r.TokenConfigured || noValidate {
 		return r
 	}
@@ -110,16 +131,31 @@ func buildStatusReport(noValidate bool) statusReport {
 	return r
 }
 
+// activeProfile is config.ActiveProfile, except that with DIBBLA_API_TOKEN
+// set there is none: as in config.Load, an env token means env only.
+func activeProfile() (string, *config.Profile, error) {
+	if os.Getenv("DIBBLA_API_TOKEN") != "" {
+		return "", nil, nil
+	}
+	return config.ActiveProfile()
+}
+
 // resolveAPIURLWithSource mirrors config.Load's URL precedence and reports
 // where the chosen value came from. Kept inline rather than refactoring
 // config.Load so the precedence change blast radius stays in one file.
-func resolveAPIURLWithSource() (url, source string) {
+func resolveAPIURLWithSource(profileName string, profile *config.Profile) (url, source string) {
 	if v := strings.TrimSpace(os.Getenv("DIBBLA_API_URL")); v != "" {
 		return normalizeURL(v), "env (DIBBLA_API_URL)"
 	}
 	if v := strings.TrimSpace(os.Getenv("DIBBLA_AUTH_SERVICE_URL")); v != "" {
 		return normalizeURL(v), "env (DIBBLA_AUTH_SERVICE_URL)"
 	}
+	if profile != nil {
+		if profile.APIURL != "" {
+			return normalizeURL(profile.APIURL), "profile " + profileName
+		}
+		return config.DefaultAPIURL, "default"
+	}
 	// Honor the same env-only short-circuit as config.Load: when
 	// DIBBLA_API_TOKEN is set or we're in CI, the keyring/file are not
 	// consulted, so reporting their stored URLs would be misleading.
@@ -132,13 +168,22 @@ func resolveAPIURLWithSource() (url, source string) {
 			return normalizeURL(fileURL), "credentials file"
 		}
 	}
+	if uc, err := config.LoadUserConfig(); err == nil && uc.APIURL != "" {
+		return normalizeURL(uc.APIURL), "config file"
+	}
 	return config.DefaultAPIURL, "default"
 }
 
-func resolveTokenWithSource() (token, source string) {
+func resolveTokenWithSource(profileName string, profile *config.Profile) (token, source string) {
 	if v := strings.TrimSpace(os.Getenv("DIBBLA_API_TOKEN")); v != "" {
 		return v, "env (DIBBLA_API_TOKEN)"
 	}
+	if profile != nil {
+		if profile.Token != "" {
+			return profile.Token, "profile " + profileName
+		}
+		return "", "none"
+	}
 	if platform.IsCI() {
 		// CI without DIBBLA_API_TOKEN: same short-circuit as config.Load
 		// — keyring is not consulted. Report nothing rather than silently
@@ -171,6 +216,12 @@ func printStatusHuman(r statusReport) {
 	warn := platform.Icon("⚠", "[!]")
 
 	fmt.Printf("Dibbla CLI %s\n", r.Version)
+	if r.Profile != "" {
+		fmt.Printf("Profile: %s\n", r.Profile)
+	}
+	if r.ProjectConfig != "" {
+		fmt.Printf("Project: %s\n", r.ProjectConfig)
+	}
 	fmt.Printf("API:     %s  (%s)\n", r.APIURL, r.APIURLSource)
 	if r.TokenConfigured {
 		fmt.Printf("Token:   configured  (source: %s)\n", r.TokenSource)
@@ -180,7 +231,11 @@ func printStatusHuman(r statusReport) {
 
 	switch {
 	case !r.TokenConfigured:
-		fmt.Printf("Status:  %s not logged in — run `dibbla login`\n", bad)
+		if r.Profile != "" {
+			fmt.Printf("Status:  %s not logged in — run `dibbla login --profile %s`\n", bad, r.Profile)
+		} else {
+			fmt.Printf("Status:  %s not logged in — run `dibbla login`\n", bad)
+		}
 	case !r.Validated:
 		fmt.Printf("Status:  %s token configured (validation skipped)\n", warn)
 	case r.LoggedIn:
@@ -1,27 +1,40 @@
 package template
 
 import (
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 
 	cliout "github.com/dibbla-agents/dibbla-cli/internal/output"
+	"github.com/dibbla-agents/dibbla-cli/internal/templates"
 )
 
 var (
-	listRefresh bool
-	listVerbose bool
+	listRefresh  bool
+	listVerbose  bool
+	listLanguage string
 )
 
-var listCmd = &cobra.Command{
-	Use:          "list",
-	Short:        "List available templates",
-	Args:         cobra.NoArgs,
-	SilenceUsage: true,
-	RunE:         runList,
-}
+var listCmd = NewListCmd()
 
-func init() {
-	listCmd.Flags().BoolVar(&listRefresh, "refresh", false, "Force re-fetch of the manifest, bypassing the fresh cache")
-	listCmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "Print manifest source (cache/network)")
+// NewListCmd returns a `list` command for the templates in the manifest.
+// It backs both `dibbla template list` and `dibbla create list`.
+func NewListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List available templates",
+		Long: `List the official and community templates in the hosted manifest, with
+their languages and when they were last updated. Install one with
+'dibbla template install <id>'.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runList,
+	}
+	cmd.Flags().BoolVar(&listRefresh, "refresh", false, "Force re-fetch of the manifest, bypassing the fresh cache")
+	cmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "Print manifest source (cache/network)")
+	cmd.Flags().StringVar(&listLanguage, "language", "", "Only list templates in this language, e.g. go")
+	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -29,16 +42,58 @@ func runList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if len(m.Templates) == 0 {
-		cliout.Stderr("no templates available")
+	rows := listRows(m, listLanguage)
+	if len(rows) == 0 {
+		if listLanguage != "" {
+			cliout.Stderr("no %s templates available", listLanguage)
+		} else {
+			cliout.Stderr("no templates available")
+		}
 		return nil
 	}
 
-	headers := []string{"ID", "NAME", "CATEGORY", "DESCRIPTION"}
+	headers := []string{"ID", "NAME", "LANGUAGES", "SOURCE", "UPDATED", "DESCRIPTION"}
+	cliout.PrintTable(headers, rows)
+	return nil
+}
+
+// listRows builds the `template list` table, keeping only templates in
+// language (case-insensitive) when it is set.
+func listRows(m *templates.Manifest, language string) [][]string {
 	rows := make([][]string, 0, len(m.Templates))
 	for _, t := range m.Templates {
-		rows = append(rows, []string{t.ID, t.Name, t.Category, t.Description})
+		if language != "" && !hasLanguage(t, language) {
+			continue
+		}
+		source := "official"
+		if t.Community {
+			source = "community"
+		}
+		languages := strings.Join(t.Languages, ", ")
+		if languages == "" {
+			languages = "-"
+		}
+		rows = append(rows, []string{t.ID, t.Name, languages, source, formatUpdated(t.UpdatedAt), t.Description})
 	}
-	cliout.PrintTable(headers, rows)
-	return nil
+	return rows
+}
+
+func hasLanguage(t templates.Template, language string) bool {
+	for _, l := range t.Languages {
+		if strings.EqualFold(l, language) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatUpdated shortens an RFC 3339 timestamp to its date.
+func formatUpdated(s string) string {
+	if s == "" {
+		return "-"
+	}
+	if ts, err := time.Parse(time.RFC3339, s); err == nil {
+		return ts.Format("2006-01-02")
+	}
+	return s
 }
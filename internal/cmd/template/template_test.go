@@ -37,3 +37,30 @@ func TestHumanDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestListRows(t *testing.T) {
+	m := &templates.Manifest{Templates: []templates.Template{
+		{ID: "go-worker", Name: "Go worker", Languages: []string{"Go"}, UpdatedAt: "2026-09-30T12:00:00Z", Description: "Worker"},
+		{ID: "acme-node", Name: "Acme Node", Languages: []string{"TypeScript", "Node"}, UpdatedAt: "2026-08-01", Community: true},
+		{ID: "bare", Name: "Bare"},
+	}}
+
+	rows := listRows(m, "")
+	want := [][]string{
+		{"go-worker", "Go worker", "Go", "official", "2026-09-30", "Worker"},
+		{"acme-node", "Acme Node", "TypeScript, Node", "community", "2026-08-01", ""},
+		{"bare", "Bare", "-", "official", "-", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+
+	if rows := listRows(m, "go"); len(rows) != 1 || rows[0][0] != "go-worker" {
+		t.Errorf("--language go: %q", rows)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
 	"github.com/dibbla-agents/dibbla-cli/internal/update"
 	"github.com/minio/selfupdate"
 )
@@ -59,6 +60,7 @@ func SelfReplace(rel *update.Release, targetPath, currentVersion string) error {
 		return fmt.Errorf("create temp dir next to %s: %w", targetPath, err)
 	}
 	defer os.RemoveAll(tmpDir)
+	defer interrupt.OnCleanup(func() { os.RemoveAll(tmpDir) })()
 
 	archivePath := filepath.Join(tmpDir, asset.Name)
 	if err := downloadFile(asset.DownloadURL, archivePath, currentVersion); err != nil {
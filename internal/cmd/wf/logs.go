@@ -5,9 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/mattn/go-isatty"
@@ -15,6 +13,7 @@ import (
 
 	"github.com/dibbla-agents/dibbla-cli/internal/applogs"
 	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 )
 
@@ -68,13 +67,17 @@ func runRunLogs(cmd *cobra.Command, args []string) error {
 // and `dibbla wf execute --follow`, which captures the runId from the
 // async-execute response and then tails.
 func runLogsByID(cmd *cobra.Command, runID string) error {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
 	if !cfg.HasToken() {
 		fmt.Fprintf(os.Stderr, "%s Error: API token is required. Run `dibbla login` or set DIBBLA_API_TOKEN.\n", platform.Icon("❌", "[X]"))
 		os.Exit(1)
 	}
 
-	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	// Ctrl-C ends the stream/run cleanly rather than exiting the process.
+	ctx, cancel := interrupt.GracefulContext(cmd.Context())
 	defer cancel()
 
 	// When invoked from `wf execute --follow` the logsFlagXxx package globals
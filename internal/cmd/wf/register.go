@@ -39,7 +39,10 @@ func Register(root *cobra.Command) {
 }
 
 func initClient(cmd *cobra.Command, args []string) error {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
 	if !cfg.HasToken() {
 		return fmt.Errorf("API token required: run dibbla login or set DIBBLA_API_TOKEN")
 	}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var whoamiJSON bool
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the account and token the CLI is using",
+	Long: `Check the configured API token against the API and print who it belongs to:
+the account, organization, token name, scopes and expiry. A revoked or expired
+token is reported as such, so it can be replaced before a deploy fails halfway
+with a 401.
+
+Exit codes:
+  0  the token is valid
+  3  not logged in, or the token was rejected
+  1  unexpected error (network, malformed response)`,
+	Args: cobra.NoArgs,
+	Run:  runWhoami,
+}
+
+func init() {
+	whoamiCmd.Flags().BoolVar(&whoamiJSON, "json", false, "Print the identity as JSON")
+}
+
+func runWhoami(cmd *cobra.Command, args []string) {
+	cfg := loadConfig()
+	os.Exit(runWhoamiCore(cmd.Context(), os.Stdout, os.Stderr, cfg, whoamiJSON, time.Now()))
+}
+
+// runWhoamiCore is the testable inner implementation of `whoami`. Returns
+// the exit code.
+func runWhoamiCore(ctx context.Context, stdout, stderr io.Writer, cfg *config.Config, jsonOut bool, now time.Time) int {
+	if !cfg.HasToken() {
+		fmt.Fprintf(stderr, "%s Not logged in. Run 'dibbla login' or set DIBBLA_API_TOKEN.\n", platform.Icon("❌", "[X]"))
+		return 3
+	}
+
+	id, err := apiclient.Whoami(ctx, cfg.APIURL, cfg.APIToken)
+	if errors.Is(err, apiclient.ErrWhoamiUnsupported) {
+		// Older servers can still say whether the token is valid.
+		if err = apiclient.ValidateToken(cfg.APIURL, cfg.APIToken); err == nil {
+			if jsonOut {
+				_ = json.NewEncoder(stdout).Encode(map[string]any{"api_url": cfg.APIURL, "valid": true})
+				return 0
+			}
+			fmt.Fprintf(stdout, "%s The token is valid for %s, which doesn't report token details.\n", platform.Icon("✅", "[OK]"), cfg.APIURL)
+			return 0
+		}
+	}
+	if err != nil {
+		var apiErr *apiclient.APIError
+		if errors.As(err, &apiErr) {
+			fmt.Fprintf(stderr, "%s Token rejected by %s: %s\n", platform.Icon("❌", "[X]"), cfg.APIURL, apiErr.Message)
+			if apiErr.StatusCode == 401 || apiErr.StatusCode == 403 {
+				fmt.Fprintf(stderr, "   Log in again with 'dibbla login', or create a new token at %s\n", apiKeysURL)
+			}
+			return apiclient.ExitCodeForStatus(apiErr.StatusCode)
+		}
+		fmt.Fprintf(stderr, "%s Error: %v\n", platform.Icon("❌", "[X]"), err)
+		return 1
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(id)
+		return 0
+	}
+
+	account := id.Account
+	if id.Name != "" {
+		account = fmt.Sprintf("%s (%s)", id.Name, id.Account)
+	}
+	org := id.Organization
+	if id.OrganizationID != "" {
+		org = fmt.Sprintf("%s (%s)", id.Organization, id.OrganizationID)
+	}
+	scopes := "all (full access)"
+	if len(id.Scopes) > 0 {
+		scopes = strings.Join(id.Scopes, ", ")
+	}
+	fmt.Fprintf(stdout, "Account:       %s\n", account)
+	fmt.Fprintf(stdout, "Organization:  %s\n", org)
+	if id.TokenName != "" {
+		fmt.Fprintf(stdout, "Token:         %s\n", id.TokenName)
+	}
+	fmt.Fprintf(stdout, "Scopes:        %s\n", scopes)
+	fmt.Fprintf(stdout, "Expires:       %s\n", formatExpiry(id.ExpiresAt, now))
+	if cfg.Profile != "" {
+		fmt.Fprintf(stdout, "API:           %s (profile %s)\n", cfg.APIURL, cfg.Profile)
+	} else {
+		fmt.Fprintf(stdout, "API:           %s\n", cfg.APIURL)
+	}
+
+	// config.Load has already warned about a token with a recorded
+	// expiry; record it for next time if it wasn't.
+	if id.ExpiresAt == nil || cfg.TokenInfo.ExpiresAt != nil {
+		return 0
+	}
+	if err := cfg.RecordTokenExpiry(id.ExpiresAt); err != nil {
+		fmt.Fprintf(stderr, "%s Could not record the token's expiry: %v\n", platform.Icon("⚠️", "[!]"), err)
+	}
+	if id.ExpiresAt.Sub(now) < config.TokenExpiryWarning() {
+		fmt.Fprintf(stdout, "\n%s The token expires soon. Create a new one at %s and run 'dibbla login'.\n", platform.Icon("⚠️", "[!]"), apiKeysURL)
+	}
+	return 0
+}
+
+// formatExpiry describes when a token expires, relative to now.
+func formatExpiry(at *time.Time, now time.Time) string {
+	if at == nil {
+		return "never"
+	}
+	date := at.Local().Format("2006-01-02 15:04")
+	switch left := at.Sub(now); {
+	case left <= 0:
+		return date + " (expired)"
+	case left < 24*time.Hour:
+		return date + " (in less than a day)"
+	case left < 48*time.Hour:
+		return date + " (in 1 day)"
+	default:
+		return fmt.Sprintf("%s (in %d days)", date, int(left.Hours()/24))
+	}
+}
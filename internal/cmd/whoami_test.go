@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/config"
+)
+
+func TestRunWhoamiCore(t *testing.T) {
+	t.Setenv("DIBBLA_API_TOKEN", "")
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer good":
+			_, _ = w.Write([]byte(`{"account":"ada@example.com","name":"Ada","organization":"Acme","organization_id":"org_1","token_name":"laptop","expires_at":"2026-10-18T12:00:00Z"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":"token_revoked","message":"revoked"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	cfg := &config.Config{APIURL: srv.URL, APIToken: "good", Profile: "prod"}
+	if code := runWhoamiCore(context.Background(), &stdout, &stderr, cfg, false, now); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	for _, want := range []string{
+		"Account:       Ada (ada@example.com)\n",
+		"Organization:  Acme (org_1)\n",
+		"Token:         laptop\n",
+		"Scopes:        all (full access)\n",
+		"(in 3 days)\n",
+		"(profile prod)\n",
+		"The token expires soon.",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	cfg.APIToken = "old"
+	if code := runWhoamiCore(context.Background(), &stdout, &stderr, cfg, false, now); code != 3 {
+		t.Errorf("revoked token: exit %d, want 3", code)
+	}
+	if !strings.Contains(stderr.String(), "the API token was revoked") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	cfg.APIToken = ""
+	if code := runWhoamiCore(context.Background(), &stdout, &stderr, cfg, false, now); code != 3 {
+		t.Errorf("no token: exit %d, want 3", code)
+	}
+}
+
+func TestRunWhoamiCore_OlderServer(t *testing.T) {
+	t.Setenv("DIBBLA_API_TOKEN", "")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/auth/v1/tokens/validate" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	cfg := &config.Config{APIURL: srv.URL, APIToken: "tok"}
+	if code := runWhoamiCore(context.Background(), &stdout, &stderr, cfg, false, time.Now()); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "doesn't report token details") {
+		t.Errorf("output = %q", stdout.String())
+	}
+}
+
+func TestFormatExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+	cases := []struct {
+		at   *time.Time
+		want string
+	}{
+		{nil, "never"},
+		{at(-time.Hour), "(expired)"},
+		{at(2 * time.Hour), "(in less than a day)"},
+		{at(30 * time.Hour), "(in 1 day)"},
+		{at(90 * 24 * time.Hour), "(in 90 days)"},
+	}
+	for _, tc := range cases {
+		if got := formatExpiry(tc.at, now); !strings.HasSuffix(got, tc.want) {
+			t.Errorf("formatExpiry(%v) = %q, want suffix %q", tc.at, got, tc.want)
+		}
+	}
+}
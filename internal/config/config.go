@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/dibbla-agents/dibbla-cli/internal/credential"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
@@ -20,17 +23,70 @@ const (
 type Config struct {
 	APIURL   string
 	APIToken string
+	// Profile is the name of the profile in use, if any; Region is the
+	// default region, the project's or else the profile's.
+	Profile string
+	Region  string
+	// Project holds the settings of the project the CLI runs in, if any.
+	Project *ProjectConfig
+	// TokenInfo is what login recorded about a stored token: its expiry
+	// and refresh token. Empty for a token from the environment.
+	TokenInfo credential.TokenInfo
+
+	// tokenStore is where APIToken came from, to save a refreshed one.
+	tokenStore tokenStore
+	// urlOverridden is set when APIURL isn't the one stored with the
+	// token but comes from DIBBLA_API_URL, DIBBLA_AUTH_SERVICE_URL or
+	// api_url in the config file. The token is then not refreshed.
+	urlOverridden bool
+	// projectProfile is set when the project's config picked Profile
+	// over currentContext, the profile used elsewhere.
+	projectProfile bool
+	currentContext string
 }
 
 // Load reads configuration from environment variables, .env file, and OS credential store.
-// In CI or when DIBBLA_API_TOKEN is set, only env is used. Otherwise stored credentials from
-// "dibbla login" are used.
+// When DIBBLA_API_TOKEN is set, only env is used: no profile is read, so its token can't
+// be sent to a profile's API. Otherwise, with a profile in use (see ActiveProfile) its
+// settings take the place of the stored credentials. A project's .dibbla/config.yaml (see
+// LoadProjectConfig) can pick the profile and override its region. In CI only env is
+// used too. Otherwise stored credentials from "dibbla login" are used. A broken project
+// file or an unknown profile is an error: carrying on with other credentials could act on
+// the wrong instance.
 //
 // The API URL is resolved with this precedence: DIBBLA_API_URL (preferred name)
 // falls back to DIBBLA_AUTH_SERVICE_URL (the name used by the dibbla-tasks
 // steprunner and desktop app when injecting env into child processes), then to
-// the stored credential-store URL, then to DefaultAPIURL.
-func Load() *Config {
+// the stored credential-store URL, then to api_url in ~/.dibbla/config.yaml, then to
+// DefaultAPIURL.
+//
+// A stored token that is about to expire is refreshed first when login
+// recorded a refresh token for it and the API URL is the one that issued it;
+// otherwise a warning is printed to stderr (see TokenExpiryWarning).
+func Load() (*Config, error) {
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+	cfg.warnProfileSwitch(os.Stderr)
+	cfg.checkToken(os.Stderr, time.Now())
+	return cfg, nil
+}
+
+// warnProfileSwitch tells on w when the project's config, rather than
+// --context, DIBBLA_PROFILE or current_context, picked the profile, so a
+// repo can't silently send commands to another instance.
+func (c *Config) warnProfileSwitch(w io.Writer) {
+	switch {
+	case !c.projectProfile:
+	case c.currentContext == "":
+		fmt.Fprintf(w, "%s Using profile %q from %s\n", platform.Icon("⚠️", "[!]"), c.Profile, c.Project.Path)
+	default:
+		fmt.Fprintf(w, "%s Using profile %q from %s instead of the current context %q\n", platform.Icon("⚠️", "[!]"), c.Profile, c.Project.Path, c.currentContext)
+	}
+}
+
+func load() (*Config, error) {
 	// Load .env file if it exists (ignores error if file doesn't exist)
 	_ = godotenv.Load()
 
@@ -45,12 +101,60 @@ func Load() *Config {
 		APIToken: envToken,
 	}
 
+	project, err := LoadProjectConfig()
+	if err != nil {
+		return nil, err
+	}
+	if project != nil {
+		cfg.Project, cfg.Region = project, project.Region
+	}
+
+	// A profile replaces the stored login credentials; a DIBBLA_API_URL
+	// still wins.
+	var name string
+	var profile *Profile
+	if envToken == "" {
+		if name, profile, err = ActiveProfile(); err != nil {
+			return nil, err
+		}
+	}
+	if profile != nil {
+		cfg.Profile = name
+		if project != nil && name == project.Profile && selectedProfile == "" && os.Getenv("DIBBLA_PROFILE") == "" {
+			if uc, err := LoadUserConfig(); err == nil && uc.CurrentContext != name {
+				cfg.projectProfile, cfg.currentContext = true, uc.CurrentContext
+			}
+		}
+		if cfg.Region == "" {
+			cfg.Region = profile.Region
+		}
+		if profile.Token != "" {
+			cfg.APIToken, cfg.tokenStore = profile.Token, tokenStoreProfile
+			cfg.TokenInfo = credential.TokenInfo{ExpiresAt: profile.TokenExpiresAt, RefreshToken: profile.RefreshToken, IssuedBy: profile.TokenIssuedBy}
+		}
+		if profile.APIURL != "" {
+			cfg.APIURL = profile.APIURL
+		}
+		if envURL != "" {
+			cfg.APIURL, cfg.urlOverridden = envURL, true
+		}
+		cfg.APIURL = strings.TrimRight(strings.TrimSuffix(cfg.APIURL, "/"), "\x00")
+		return cfg, nil
+	}
+
+	// api_url in the config file is only a default: a stored login
+	// keeps the URL its token belongs to.
+	configURL := false
+	if uc, err := LoadUserConfig(); err == nil && uc.APIURL != "" {
+		cfg.APIURL, configURL = uc.APIURL, true
+	}
+
 	if envToken != "" || platform.IsCI() {
 		// Use env only; do not read keychain
 		if envURL != "" {
 			cfg.APIURL = envURL
 		}
-		return cfg
+		return cfg, nil
 	}
 
 	// Read order: keyring first (single read to avoid multiple OS
@@ -61,26 +165,34 @@ func Load() *Config {
 	// machine-wide, persists across `cd` — rather than the cwd-bound
 	// `--write-env` behavior.
 	storedToken, storedURL, err := credential.GetCredentials()
+	store := tokenStoreKeyring
 	if err != nil || storedToken == "" {
 		if fileToken, fileURL, ferr := credential.GetTokenFile(); ferr == nil && fileToken != "" {
-			storedToken, storedURL = fileToken, fileURL
+			storedToken, storedURL, store = fileToken, fileURL, tokenStoreFile
 		}
 	}
 	if storedToken != "" {
-		cfg.APIToken = storedToken
+		cfg.APIToken, cfg.tokenStore = storedToken, store
+		// A missing or unreadable record only means no expiry checks.
+		if store == tokenStoreKeyring {
+			cfg.TokenInfo, _ = credential.GetTokenInfo()
+		} else {
+			cfg.TokenInfo, _ = credential.GetTokenFileInfo()
+		}
 		if storedURL != "" {
-			cfg.APIURL = storedURL
+			cfg.APIURL, configURL = storedURL, false
 		}
+		cfg.urlOverridden = configURL
 	}
 	if envURL != "" {
-		cfg.APIURL = envURL
+		cfg.APIURL, cfg.urlOverridden = envURL, true
 	}
 
 	// Normalize: strip trailing slashes and null bytes that some OS credential
 	// stores (e.g. Windows Credential Manager) may introduce.
 	cfg.APIURL = strings.TrimRight(strings.TrimSuffix(cfg.APIURL, "/"), "\x00")
 
-	return cfg
+	return cfg, nil
 }
 
 // HasToken returns true if an API token is configured
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named set of API settings in ~/.dibbla/config.yaml, for working
+// against more than one Dibbla instance, such as production and a
+// self-hosted server.
+type Profile struct {
+	APIURL string `yaml:"api_url,omitempty"`
+	// Token is written by 'dibbla login' while the profile is in use.
+	Token string `yaml:"token,omitempty"`
+	// TokenExpiresAt, RefreshToken and TokenIssuedBy (the API URL the
+	// token came from) are recorded by 'dibbla login' along with the
+	// token, when known.
+	TokenExpiresAt *time.Time `yaml:"token_expires_at,omitempty"`
+	RefreshToken   string     `yaml:"refresh_token,omitempty"`
+	TokenIssuedBy  string     `yaml:"token_issued_by,omitempty"`
+	// Region is the default --region of deploy and db create.
+	Region string `yaml:"region,omitempty"`
+}
+
+// selectedProfile is the profile picked with the global --context flag.
+var selectedProfile string
+
+// SelectProfile makes Load use the named profile, over DIBBLA_PROFILE and
+// the config file's current_context. "" leaves the choice to those.
+func SelectProfile(name string) {
+	selectedProfile = strings.TrimSpace(name)
+}
+
+// ProfileName returns the name of the profile to use, which may not exist
+// yet: the one picked with SelectProfile, else DIBBLA_PROFILE, else the
+// project's (see LoadProjectConfig), else current_context, else "".
+func ProfileName(uc *UserConfig) string {
+	if selectedProfile != "" {
+		return selectedProfile
+	}
+	if name := strings.TrimSpace(os.Getenv("DIBBLA_PROFILE")); name != "" {
+		return name
+	}
+	// A broken project file is reported by Load.
+	if pc, _ := LoadProjectConfig(); pc != nil && pc.Profile != "" {
+		return pc.Profile
+	}
+	return uc.CurrentContext
+}
+
+// ActiveProfile returns the profile in use (see ProfileName) and its name,
+// or "" and nil when there is none. Naming one that doesn't exist is an
+// error.
+func ActiveProfile() (string, *Profile, error) {
+	uc, err := LoadUserConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	name := ProfileName(uc)
+	if name == "" {
+		return "", nil, nil
+	}
+	p, ok := uc.Profiles[name]
+	if !ok {
+		path, _ := userConfigPath()
+		return "", nil, fmt.Errorf("profile %q not found in %s (see 'dibbla config get-contexts')", name, path)
+	}
+	return name, &p, nil
+}
+
+// ProfileNames returns the names of the profiles in uc, sorted.
+func (uc *UserConfig) ProfileNames() []string {
+	names := make([]string, 0, len(uc.Profiles))
+	for name := range uc.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseContext makes the named profile the one used by default, or stops
+// using one for "". The rest of the config file is kept as it is.
+func UseContext(name string) error {
+	if name != "" {
+		uc, err := LoadUserConfig()
+		if err != nil {
+			return err
+		}
+		if _, ok := uc.Profiles[name]; !ok {
+			path, _ := userConfigPath()
+			return fmt.Errorf("profile %q not found in %s (see 'dibbla config get-contexts')", name, path)
+		}
+	}
+	return editUserConfig(func(root *yaml.Node) error {
+		if name == "" {
+			deleteKey(root, "current_context")
+			return nil
+		}
+		setKey(root, "current_context", &yaml.Node{Kind: yaml.ScalarNode, Value: name})
+		return nil
+	})
+}
+
+// SaveProfile adds or replaces the named profile, keeping the rest of the
+// config file as it is.
+func SaveProfile(name string, p Profile) error {
+	var value yaml.Node
+	if err := value.Encode(p); err != nil {
+		return err
+	}
+	return editUserConfig(func(root *yaml.Node) error {
+		profiles := lookupKey(root, "profiles")
+		if profiles == nil || profiles.Kind != yaml.MappingNode {
+			profiles = &yaml.Node{Kind: yaml.MappingNode}
+			setKey(root, "profiles", profiles)
+		}
+		setKey(profiles, name, &value)
+		return nil
+	})
+}
+
+// editUserConfig applies edit to the top-level mapping of ~/.dibbla/config.yaml
+// and writes the file back, creating it if needed. Editing the YAML nodes
+// rather than UserConfig keeps comments and settings this version of the
+// CLI doesn't know about.
+func editUserConfig(edit func(root *yaml.Node) error) error {
+	path, err := userConfigPath()
+	if err != nil {
+		return err
+	}
+	// A file under the old name, ~/.dibbla/config, is moved to config.yaml.
+	read, data, err := readUserConfigFile()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", read, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: expected a mapping at the top level", path)
+	}
+	if err := edit(root); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Profiles hold API tokens.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if read != path {
+		return os.Remove(read)
+	}
+	return nil
+}
+
+func lookupKey(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setKey(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+func deleteKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
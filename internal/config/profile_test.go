@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const profilesConfig = `# my settings
+notifications:
+  webhooks: ["https://hooks.example.com/x"]
+profiles:
+  prod:
+    region: eu-west
+  selfhosted:
+    api_url: https://dibbla.example.com/
+    token: ak_selfhosted
+current_context: prod
+`
+
+func withProfile(t *testing.T, flag, env string) {
+	t.Helper()
+	t.Setenv("DIBBLA_PROFILE", env)
+	SelectProfile(flag)
+	t.Cleanup(func() { SelectProfile("") })
+}
+
+func TestActiveProfile(t *testing.T) {
+	withUserConfig(t, profilesConfig)
+	cases := []struct {
+		flag, env, want string
+	}{
+		{"", "", "prod"},
+		{"", "selfhosted", "selfhosted"},
+		{"prod", "selfhosted", "prod"},
+	}
+	for _, tc := range cases {
+		withProfile(t, tc.flag, tc.env)
+		name, p, err := ActiveProfile()
+		if err != nil || name != tc.want || p == nil {
+			t.Errorf("flag %q, env %q: ActiveProfile() = %q, %v, %v; want %q", tc.flag, tc.env, name, p, err, tc.want)
+		}
+	}
+
+	withProfile(t, "staging", "")
+	if _, _, err := ActiveProfile(); err == nil || !strings.Contains(err.Error(), `profile "staging" not found`) {
+		t.Errorf("err = %v, want a not found error", err)
+	}
+}
+
+func TestActiveProfileNone(t *testing.T) {
+	withUserConfig(t, "")
+	withProfile(t, "", "")
+	if name, p, err := ActiveProfile(); name != "" || p != nil || err != nil {
+		t.Errorf("ActiveProfile() = %q, %v, %v; want none", name, p, err)
+	}
+}
+
+func TestLoadWithProfile(t *testing.T) {
+	withUserConfig(t, profilesConfig)
+	withProfile(t, "selfhosted", "")
+	t.Setenv("DIBBLA_API_TOKEN", "")
+	t.Setenv("DIBBLA_API_URL", "")
+	t.Setenv("DIBBLA_AUTH_SERVICE_URL", "")
+
+	cfg := mustLoad(t)
+	if cfg.Profile != "selfhosted" || cfg.APIURL != "https://dibbla.example.com" || cfg.APIToken != "ak_selfhosted" {
+		t.Errorf("Load() = %+v", cfg)
+	}
+
+	// DIBBLA_API_URL takes precedence over the profile's URL.
+	t.Setenv("DIBBLA_API_URL", "https://api.env.example.com")
+	cfg = mustLoad(t)
+	if cfg.APIURL != "https://api.env.example.com" || cfg.APIToken != "ak_selfhosted" {
+		t.Errorf("Load() with DIBBLA_API_URL = %+v", cfg)
+	}
+
+	// An env token means env only: it never goes to the profile's URL.
+	t.Setenv("DIBBLA_API_URL", "")
+	t.Setenv("DIBBLA_API_TOKEN", "ak_env")
+	cfg = mustLoad(t)
+	if cfg.Profile != "" || cfg.APIURL != DefaultAPIURL || cfg.APIToken != "ak_env" {
+		t.Errorf("Load() with DIBBLA_API_TOKEN = %+v", cfg)
+	}
+
+	t.Setenv("DIBBLA_API_TOKEN", "")
+	withProfile(t, "prod", "")
+	if cfg := mustLoad(t); cfg.Region != "eu-west" {
+		t.Errorf("Region = %q, want eu-west", cfg.Region)
+	}
+
+	withProfile(t, "staging", "")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), `profile "staging" not found`) {
+		t.Errorf("Load() with an unknown profile: err = %v", err)
+	}
+}
+
+func TestUserConfigLegacyName(t *testing.T) {
+	withUserConfig(t, "")
+	path, _ := userConfigPath()
+	legacy := strings.TrimSuffix(path, ".yaml")
+	if err := os.WriteFile(legacy, []byte("api_url: https://legacy.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if uc, err := LoadUserConfig(); err != nil || uc.APIURL != "https://legacy.example.com" {
+		t.Fatalf("LoadUserConfig() = %+v, %v", uc, err)
+	}
+
+	if err := SetUserSetting("default_deployment", "web"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "api_url: https://legacy.example.com\ndefault_deployment: web\n"; string(data) != want {
+		t.Errorf("config.yaml = %q, want %q", data, want)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("%s left after moving it: %v", legacy, err)
+	}
+}
+
+func TestSaveProfileAndUseContext(t *testing.T) {
+	withUserConfig(t, profilesConfig)
+	if err := SaveProfile("staging", Profile{APIURL: "https://api.staging.example.com", Token: "ak_staging"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := UseContext("staging"); err != nil {
+		t.Fatal(err)
+	}
+	if err := UseContext("missing"); err == nil {
+		t.Error("want an error using a profile that doesn't exist")
+	}
+
+	uc, err := LoadUserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc.CurrentContext != "staging" || uc.Profiles["staging"].Token != "ak_staging" || uc.Profiles["selfhosted"].Token != "ak_selfhosted" {
+		t.Errorf("config = %+v", uc)
+	}
+	if len(uc.Notifications.Webhooks) != 1 {
+		t.Errorf("webhooks = %v, want them kept", uc.Notifications.Webhooks)
+	}
+	path, _ := userConfigPath()
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# my settings\n") {
+		t.Errorf("comment not kept:\n%s", data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	if err := UseContext(""); err != nil {
+		t.Fatal(err)
+	}
+	if uc, _ := LoadUserConfig(); uc.CurrentContext != "" {
+		t.Errorf("current_context = %q after --none", uc.CurrentContext)
+	}
+}
+
+func TestSaveProfileCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".dibbla", "config.yaml")
+	orig := userConfigPath
+	userConfigPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { userConfigPath = orig })
+
+	if err := SaveProfile("local", Profile{APIURL: "http://localhost:8080"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "profiles:\n  local:\n    api_url: http://localhost:8080\n"; string(data) != want {
+		t.Errorf("config = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile is the per-project settings file, relative to the
+// project directory.
+const ProjectConfigFile = ".dibbla/config.yaml"
+
+// ProjectConfig holds per-project settings from .dibbla/config.yaml. They
+// override the profile and ~/.dibbla/config.yaml for commands run anywhere in
+// the project, so each repo targets its own app.
+type ProjectConfig struct {
+	// Path is the file the settings were read from.
+	Path string `yaml:"-"`
+
+	// Profile is the profile to use, unless --context or DIBBLA_PROFILE
+	// picks one.
+	Profile string `yaml:"profile"`
+	// Alias is the app deploy targets without --alias, and the default
+	// alias of logs and apps inspect/metrics/open/check.
+	Alias string `yaml:"alias"`
+	// Region is the default --region of deploy and db create.
+	Region string `yaml:"region"`
+	// EnvFile is read by deploy when no --env-file is given. It is
+	// relative to the project directory and must stay inside it.
+	EnvFile string `yaml:"env_file"`
+}
+
+// projectDir is where LoadProjectConfig looks, set with SetProjectDir;
+// "" for the working directory.
+var projectDir string
+
+// SetProjectDir makes LoadProjectConfig, and so Load, use the project
+// holding dir rather than the working directory, for commands given a
+// project path (such as deploy [path]). "" goes back to the working
+// directory.
+func SetProjectDir(dir string) {
+	projectDir = dir
+}
+
+// Dir returns the project directory, the one holding .dibbla/.
+func (pc *ProjectConfig) Dir() string {
+	return filepath.Dir(filepath.Dir(pc.Path))
+}
+
+// EnvFilePath returns EnvFile resolved against the project directory, or
+// "" when unset.
+func (pc *ProjectConfig) EnvFilePath() string {
+	if pc.EnvFile == "" {
+		return ""
+	}
+	return filepath.Join(pc.Dir(), pc.EnvFile)
+}
+
+// LoadProjectConfig returns the settings of the project holding the
+// working directory, or the directory set with SetProjectDir (see
+// FindProjectConfig).
+func LoadProjectConfig() (*ProjectConfig, error) {
+	if projectDir != "" {
+		return FindProjectConfig(projectDir)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return FindProjectConfig(wd)
+}
+
+// FindProjectConfig reads the .dibbla/config.yaml in dir or its nearest
+// parent that has one. It returns nil and no error when there is none.
+// The search stops at the home directory, whose .dibbla/ holds the user
+// config instead.
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	home, _ := os.UserHomeDir()
+	for {
+		if dir == home {
+			return nil, nil
+		}
+		path := filepath.Join(dir, ProjectConfigFile)
+		data, err := os.ReadFile(path)
+		if err == nil {
+			pc := &ProjectConfig{Path: path}
+			if err := yaml.Unmarshal(data, pc); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			// A repo's settings can't make deploy read files
+			// outside it.
+			if pc.EnvFile != "" && !filepath.IsLocal(pc.EnvFile) {
+				return nil, fmt.Errorf("%s: env_file %q must be a relative path inside the project", path, pc.EnvFile)
+			}
+			return pc, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProjectConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".dibbla"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	writeProjectConfig(t, root, "alias: shop\nregion: us-east\nenv_file: .env.production\n")
+	sub := filepath.Join(root, "web", "src")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	pc, err := FindProjectConfig(sub)
+	if err != nil || pc == nil {
+		t.Fatalf("FindProjectConfig = %v, %v", pc, err)
+	}
+	if pc.Alias != "shop" || pc.Region != "us-east" || pc.Dir() != root {
+		t.Errorf("config = %+v, dir %s", pc, pc.Dir())
+	}
+	if got, want := pc.EnvFilePath(), filepath.Join(root, ".env.production"); got != want {
+		t.Errorf("EnvFilePath() = %q, want %q", got, want)
+	}
+
+	if pc, err := FindProjectConfig(t.TempDir()); pc != nil || err != nil {
+		t.Errorf("no project: %v, %v", pc, err)
+	}
+
+	writeProjectConfig(t, root, "alias: [shop\n")
+	if _, err := FindProjectConfig(sub); err == nil {
+		t.Error("want an error for invalid YAML")
+	}
+}
+
+func TestFindProjectConfigEnvFileOutside(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	for _, envFile := range []string{"/etc/passwd", "../.env", "config/../../.env"} {
+		writeProjectConfig(t, root, "env_file: "+envFile+"\n")
+		if pc, err := FindProjectConfig(root); err == nil {
+			t.Errorf("env_file %s: FindProjectConfig = %+v; want an error", envFile, pc)
+		}
+	}
+}
+
+func TestLoadProjectConfigFromProjectDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	writeProjectConfig(t, dir, "alias: shop\n")
+	t.Chdir(t.TempDir())
+
+	SetProjectDir(dir)
+	t.Cleanup(func() { SetProjectDir("") })
+	if pc, err := LoadProjectConfig(); err != nil || pc == nil || pc.Alias != "shop" {
+		t.Errorf("LoadProjectConfig() = %+v, %v; want the config in %s", pc, err, dir)
+	}
+	SetProjectDir("")
+	if pc, err := LoadProjectConfig(); pc != nil || err != nil {
+		t.Errorf("LoadProjectConfig() in the working directory = %+v, %v; want none", pc, err)
+	}
+}
+
+func TestFindProjectConfigStopsAtHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// ~/.dibbla holds the user config, not a project's.
+	writeProjectConfig(t, home, "alias: not-a-project\n")
+	dir := filepath.Join(home, "code")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if pc, err := FindProjectConfig(dir); pc != nil || err != nil {
+		t.Errorf("FindProjectConfig = %+v, %v; want none", pc, err)
+	}
+}
+
+func TestLoadWithProjectConfig(t *testing.T) {
+	withUserConfig(t, profilesConfig)
+	withProfile(t, "", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DIBBLA_API_TOKEN", "")
+	t.Setenv("DIBBLA_API_URL", "")
+	t.Setenv("DIBBLA_AUTH_SERVICE_URL", "")
+	dir := t.TempDir()
+	writeProjectConfig(t, dir, "profile: selfhosted\nalias: shop\nregion: us-east\n")
+	t.Chdir(dir)
+
+	cfg := mustLoad(t)
+	if cfg.Profile != "selfhosted" || cfg.APIURL != "https://dibbla.example.com" || cfg.Region != "us-east" || cfg.Project == nil {
+		t.Errorf("Load() = %+v", cfg)
+	}
+	if alias, err := AliasArg(nil); alias != "shop" || err != nil {
+		t.Errorf("AliasArg(nil) = %q, %v; want the project's alias", alias, err)
+	}
+	var warning bytes.Buffer
+	cfg.warnProfileSwitch(&warning)
+	if got := warning.String(); !strings.Contains(got, `"selfhosted"`) || !strings.Contains(got, `current context "prod"`) {
+		t.Errorf("warning = %q; want the switch from prod to selfhosted", got)
+	}
+
+	// The flag still picks the profile; the project's region still wins
+	// over the profile's.
+	withProfile(t, "prod", "")
+	cfg = mustLoad(t)
+	if cfg.Profile != "prod" || cfg.Region != "us-east" {
+		t.Errorf("Load() with --context = %+v", cfg)
+	}
+	warning.Reset()
+	if cfg.warnProfileSwitch(&warning); warning.Len() != 0 {
+		t.Errorf("warning with --context = %q; want none", warning.String())
+	}
+}
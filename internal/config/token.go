@@ -0,0 +1,165 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/auth"
+	"github.com/dibbla-agents/dibbla-cli/internal/credential"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// DefaultTokenExpiryWarningDays is how many days before its expiry a token
+// gets a warning, unless token_expiry_warning_days says otherwise.
+const DefaultTokenExpiryWarningDays = 7
+
+// tokenRefreshWindow is how long before its expiry a token with a refresh
+// token is replaced.
+const tokenRefreshWindow = 24 * time.Hour
+
+// tokenStore is where a token from 'dibbla login' is kept.
+type tokenStore string
+
+const (
+	tokenStoreProfile tokenStore = "profile"
+	tokenStoreKeyring tokenStore = "keyring"
+	tokenStoreFile    tokenStore = "file"
+)
+
+// refreshAPIToken is auth.RefreshAPIToken. Overridable in tests.
+var refreshAPIToken = auth.RefreshAPIToken
+
+// TokenExpiryWarning returns how long before its expiry a token gets a
+// warning: token_expiry_warning_days in ~/.dibbla/config.yaml, else
+// DefaultTokenExpiryWarningDays. 0 turns the warning off.
+func TokenExpiryWarning() time.Duration {
+	days := DefaultTokenExpiryWarningDays
+	if uc, err := LoadUserConfig(); err == nil && uc.TokenExpiryWarningDays != nil {
+		days = *uc.TokenExpiryWarningDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// checkToken refreshes a stored token about to expire when it has a
+// refresh token (see canRefresh). Otherwise, or when that fails, it warns
+// on w if the token expires within TokenExpiryWarning.
+func (c *Config) checkToken(w io.Writer, now time.Time) {
+	if c.tokenStore == "" || c.TokenInfo.ExpiresAt == nil {
+		return
+	}
+	if c.canRefresh() && c.TokenInfo.ExpiresAt.Sub(now) < tokenRefreshWindow {
+		err := c.refreshToken()
+		if err == nil {
+			return
+		}
+		// Another dibbla process may have refreshed it first, using up
+		// the refresh token we sent.
+		if !c.reloadToken() {
+			fmt.Fprintf(w, "%s Could not refresh the API token: %v\n", platform.Icon("⚠️", "[!]"), err)
+		}
+		if c.TokenInfo.ExpiresAt == nil {
+			return
+		}
+	}
+
+	at := c.TokenInfo.ExpiresAt
+	switch left := at.Sub(now); {
+	case left <= 0:
+		fmt.Fprintf(w, "%s The API token expired on %s. Run 'dibbla login' to get a new one.\n",
+			platform.Icon("⚠️", "[!]"), at.Local().Format("2006-01-02 15:04"))
+	case left < TokenExpiryWarning():
+		fmt.Fprintf(w, "%s The API token expires on %s. Run 'dibbla login' to get a new one.\n",
+			platform.Icon("⚠️", "[!]"), at.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+// canRefresh reports whether the token's refresh token may be used: only
+// with the API that issued it, so it is never sent to another one. Tokens
+// recorded without the issuing URL are not refreshed.
+func (c *Config) canRefresh() bool {
+	return c.TokenInfo.RefreshToken != "" && c.TokenInfo.IssuedBy != "" &&
+		!c.urlOverridden && c.TokenInfo.IssuedBy == c.APIURL
+}
+
+// refreshToken replaces the token with one from its refresh token and
+// saves it where the old one was.
+func (c *Config) refreshToken() error {
+	t, err := refreshAPIToken(c.TokenInfo.IssuedBy, c.TokenInfo.RefreshToken)
+	if err != nil {
+		return err
+	}
+	info := credential.TokenInfo{ExpiresAt: t.ExpiresAt, RefreshToken: t.RefreshToken, IssuedBy: c.TokenInfo.IssuedBy}
+	if info.RefreshToken == "" {
+		// The server didn't rotate it.
+		info.RefreshToken = c.TokenInfo.RefreshToken
+	}
+	// Use the new token even if saving it fails: the old one may no
+	// longer be valid.
+	c.APIToken, c.TokenInfo = t.Token, info
+	if err := c.saveToken(); err != nil {
+		return fmt.Errorf("the new token could not be saved: %w", err)
+	}
+	return nil
+}
+
+// reloadToken reads the token back from its store and reports whether
+// it was replaced since Load, in which case the new one is used.
+func (c *Config) reloadToken() bool {
+	var token string
+	var info credential.TokenInfo
+	switch c.tokenStore {
+	case tokenStoreProfile:
+		uc, err := LoadUserConfig()
+		if err != nil {
+			return false
+		}
+		p := uc.Profiles[c.Profile]
+		token = p.Token
+		info = credential.TokenInfo{ExpiresAt: p.TokenExpiresAt, RefreshToken: p.RefreshToken, IssuedBy: p.TokenIssuedBy}
+	case tokenStoreKeyring:
+		token, _, _ = credential.GetCredentials()
+		info, _ = credential.GetTokenInfo()
+	case tokenStoreFile:
+		token, _, _ = credential.GetTokenFile()
+		info, _ = credential.GetTokenFileInfo()
+	}
+	if token == "" || token == c.APIToken {
+		return false
+	}
+	c.APIToken, c.TokenInfo = token, info
+	return true
+}
+
+// RecordTokenExpiry records when the stored token in use expires, as
+// learned from the API. It does nothing for a token from the environment.
+func (c *Config) RecordTokenExpiry(at *time.Time) error {
+	if c.tokenStore == "" {
+		return nil
+	}
+	c.TokenInfo.ExpiresAt = at
+	return c.saveToken()
+}
+
+// saveToken writes APIToken and TokenInfo back to the store APIToken was
+// read from.
+func (c *Config) saveToken() error {
+	switch c.tokenStore {
+	case tokenStoreProfile:
+		uc, err := LoadUserConfig()
+		if err != nil {
+			return err
+		}
+		p := uc.Profiles[c.Profile]
+		p.Token, p.TokenExpiresAt, p.RefreshToken, p.TokenIssuedBy = c.APIToken, c.TokenInfo.ExpiresAt, c.TokenInfo.RefreshToken, c.TokenInfo.IssuedBy
+		return SaveProfile(c.Profile, p)
+	case tokenStoreKeyring:
+		if err := credential.SetToken(c.APIToken); err != nil {
+			return err
+		}
+		return credential.SetTokenInfo(c.TokenInfo)
+	case tokenStoreFile:
+		return credential.UpdateTokenFile(c.APIToken, c.TokenInfo)
+	}
+	return nil
+}
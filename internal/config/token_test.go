@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/auth"
+	"github.com/dibbla-agents/dibbla-cli/internal/credential"
+)
+
+func withRefresher(t *testing.T, fn func(apiURL, refreshToken string) (*auth.APIToken, error)) {
+	t.Helper()
+	orig := refreshAPIToken
+	refreshAPIToken = fn
+	t.Cleanup(func() { refreshAPIToken = orig })
+}
+
+func profileConfig(info credential.TokenInfo) *Config {
+	return &Config{APIURL: "https://dibbla.example.com", APIToken: "ak_old", Profile: "selfhosted", TokenInfo: info, tokenStore: tokenStoreProfile}
+}
+
+func TestCheckTokenWarnings(t *testing.T) {
+	withUserConfig(t, "")
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+	cases := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{"expires soon", profileConfig(credential.TokenInfo{ExpiresAt: at(3 * 24 * time.Hour)}), "The API token expires on"},
+		{"expired", profileConfig(credential.TokenInfo{ExpiresAt: at(-time.Hour)}), "The API token expired on"},
+		{"far off", profileConfig(credential.TokenInfo{ExpiresAt: at(30 * 24 * time.Hour)}), ""},
+		{"no expiry", profileConfig(credential.TokenInfo{}), ""},
+		{"env token", &Config{APIToken: "ak_env", TokenInfo: credential.TokenInfo{ExpiresAt: at(time.Hour)}}, ""},
+	}
+	for _, tc := range cases {
+		var out bytes.Buffer
+		tc.cfg.checkToken(&out, now)
+		if tc.want == "" && out.Len() > 0 || !strings.Contains(out.String(), tc.want) {
+			t.Errorf("%s: output %q, want %q", tc.name, out.String(), tc.want)
+		}
+	}
+
+	withUserConfig(t, "token_expiry_warning_days: 0\n")
+	var out bytes.Buffer
+	profileConfig(credential.TokenInfo{ExpiresAt: at(time.Hour)}).checkToken(&out, now)
+	if out.Len() > 0 {
+		t.Errorf("warning with token_expiry_warning_days: 0: %q", out.String())
+	}
+}
+
+func TestCheckTokenRefreshes(t *testing.T) {
+	withUserConfig(t, profilesConfig)
+	now := time.Now()
+	soon, later := now.Add(time.Hour), now.Add(30*24*time.Hour)
+	withRefresher(t, func(apiURL, refreshToken string) (*auth.APIToken, error) {
+		if apiURL != "https://dibbla.example.com" || refreshToken != "rt_old" {
+			t.Errorf("refresh(%q, %q)", apiURL, refreshToken)
+		}
+		return &auth.APIToken{Token: "ak_new", ExpiresAt: &later}, nil
+	})
+
+	cfg := profileConfig(credential.TokenInfo{ExpiresAt: &soon, RefreshToken: "rt_old", IssuedBy: "https://dibbla.example.com"})
+	var out bytes.Buffer
+	cfg.checkToken(&out, now)
+	if out.Len() > 0 {
+		t.Errorf("output = %q, want none", out.String())
+	}
+	if cfg.APIToken != "ak_new" || !cfg.TokenInfo.ExpiresAt.Equal(later) || cfg.TokenInfo.RefreshToken != "rt_old" || cfg.TokenInfo.IssuedBy != "https://dibbla.example.com" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	uc, err := LoadUserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := uc.Profiles["selfhosted"]
+	if p.Token != "ak_new" || p.RefreshToken != "rt_old" || p.TokenExpiresAt == nil || p.APIURL != "https://dibbla.example.com/" || p.TokenIssuedBy != "https://dibbla.example.com" {
+		t.Errorf("saved profile = %+v", p)
+	}
+}
+
+func TestCheckTokenRefreshFails(t *testing.T) {
+	withUserConfig(t, profilesConfig)
+	if err := SaveProfile("selfhosted", Profile{APIURL: "https://dibbla.example.com/", Token: "ak_old"}); err != nil {
+		t.Fatal(err)
+	}
+	withRefresher(t, func(string, string) (*auth.APIToken, error) {
+		return nil, errors.New("token refresh failed (HTTP 401)")
+	})
+	now := time.Now()
+	soon := now.Add(time.Hour)
+	cfg := profileConfig(credential.TokenInfo{ExpiresAt: &soon, RefreshToken: "rt_old", IssuedBy: "https://dibbla.example.com"})
+	var out bytes.Buffer
+	cfg.checkToken(&out, now)
+	for _, want := range []string{"Could not refresh the API token: token refresh failed (HTTP 401)", "The API token expires on"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output %q lacks %q", out.String(), want)
+		}
+	}
+	if cfg.APIToken != "ak_old" {
+		t.Errorf("APIToken = %q, want the old one", cfg.APIToken)
+	}
+}
+
+func TestCheckTokenRefreshedElsewhere(t *testing.T) {
+	withUserConfig(t, profilesConfig)
+	now := time.Now()
+	soon, later := now.Add(time.Hour), now.Add(30*24*time.Hour)
+	// Another process refreshed the token first; ours is now rejected.
+	if err := SaveProfile("selfhosted", Profile{APIURL: "https://dibbla.example.com/", Token: "ak_other", TokenExpiresAt: &later, RefreshToken: "rt_other"}); err != nil {
+		t.Fatal(err)
+	}
+	withRefresher(t, func(string, string) (*auth.APIToken, error) {
+		return nil, errors.New("token refresh failed (HTTP 401)")
+	})
+	cfg := profileConfig(credential.TokenInfo{ExpiresAt: &soon, RefreshToken: "rt_old", IssuedBy: "https://dibbla.example.com"})
+	var out bytes.Buffer
+	cfg.checkToken(&out, now)
+	if out.Len() > 0 {
+		t.Errorf("output = %q, want none", out.String())
+	}
+	if cfg.APIToken != "ak_other" || cfg.TokenInfo.RefreshToken != "rt_other" {
+		t.Errorf("cfg = %+v; want the stored token", cfg)
+	}
+}
+
+func TestCheckTokenRefreshOnlyWithIssuer(t *testing.T) {
+	withUserConfig(t, profilesConfig)
+	withRefresher(t, func(apiURL, _ string) (*auth.APIToken, error) {
+		t.Errorf("refresh sent to %s", apiURL)
+		return nil, errors.New("unexpected")
+	})
+	now := time.Now()
+	soon := now.Add(time.Hour)
+	cases := []struct {
+		name     string
+		issuedBy string
+		override bool
+	}{
+		{"not recorded", "", false},
+		{"other API", "https://api.dibbla.com", false},
+		{"URL overridden", "https://dibbla.example.com", true},
+	}
+	for _, tc := range cases {
+		cfg := profileConfig(credential.TokenInfo{ExpiresAt: &soon, RefreshToken: "rt_old", IssuedBy: tc.issuedBy})
+		cfg.urlOverridden = tc.override
+		var out bytes.Buffer
+		cfg.checkToken(&out, now)
+		if !strings.Contains(out.String(), "The API token expires on") || cfg.APIToken != "ak_old" {
+			t.Errorf("%s: output %q, token %q; want the expiry warning only", tc.name, out.String(), cfg.APIToken)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UserConfig holds optional per-user settings from ~/.dibbla/config.yaml
+// (YAML). A missing file is the same as an empty one.
+type UserConfig struct {
+	Notifications Notifications `yaml:"notifications"`
+	// APIURL is the API used when no login, profile or env var sets one;
+	// 'dibbla login' logs in to it by default.
+	APIURL string `yaml:"api_url"`
+	// DefaultDeployment is the app alias of commands run without one.
+	DefaultDeployment string `yaml:"default_deployment"`
+	// TokenExpiryWarningDays is how many days before its expiry a token
+	// gets a warning; nil for the default, 0 for never.
+	TokenExpiryWarningDays *int `yaml:"token_expiry_warning_days"`
+	// Profiles are named API settings; CurrentContext is the one used
+	// when neither --profile nor DIBBLA_PROFILE picks one.
+	Profiles       map[string]Profile `yaml:"profiles"`
+	CurrentContext string             `yaml:"current_context"`
+}
+
+// Notifications configures where deploy results are posted.
+type Notifications struct {
+	// Webhooks receive a JSON POST after every deploy, whether it
+	// succeeded or failed (Slack incoming webhooks work as-is).
+	Webhooks []string `yaml:"webhooks"`
+}
+
+// userConfigPath resolves ~/.dibbla/config.yaml. Overridable in tests.
+var userConfigPath = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".dibbla", "config.yaml"), nil
+}
+
+// readUserConfigFile reads ~/.dibbla/config.yaml, or while only that
+// exists ~/.dibbla/config, the name earlier versions used, and returns the
+// path read.
+func readUserConfigFile() (string, []byte, error) {
+	path, err := userConfigPath()
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := os.ReadFile(path)
+	if !errors.Is(err, os.ErrNotExist) {
+		return path, data, err
+	}
+	legacy := strings.TrimSuffix(path, ".yaml")
+	if data, lerr := os.ReadFile(legacy); !errors.Is(lerr, os.ErrNotExist) {
+		return legacy, data, lerr
+	}
+	return path, nil, err
+}
+
+// LoadUserConfig reads ~/.dibbla/config.yaml and validates it.
+func LoadUserConfig() (*UserConfig, error) {
+	path, data, err := readUserConfigFile()
+	if errors.Is(err, os.ErrNotExist) {
+		return &UserConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var uc UserConfig
+	if err := yaml.Unmarshal(data, &uc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, w := range uc.Notifications.Webhooks {
+		if err := ValidateWebhookURL(w); err != nil {
+			return nil, fmt.Errorf("%s: notifications.webhooks[%d]: %w", path, i, err)
+		}
+	}
+	return &uc, nil
+}
+
+// SetUserSetting sets a top-level key of ~/.dibbla/config.yaml to value,
+// keeping the rest of the file as it is.
+func SetUserSetting(key, value string) error {
+	return editUserConfig(func(root *yaml.Node) error {
+		setKey(root, key, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+		return nil
+	})
+}
+
+// UnsetUserSetting removes a top-level key from ~/.dibbla/config.yaml.
+func UnsetUserSetting(key string) error {
+	return editUserConfig(func(root *yaml.Node) error {
+		deleteKey(root, key)
+		return nil
+	})
+}
+
+// AliasArg returns the app alias in args, else the project's alias (see
+// LoadProjectConfig), else default_deployment, for commands whose alias
+// argument is optional.
+func AliasArg(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	pc, err := LoadProjectConfig()
+	if err != nil {
+		return "", err
+	}
+	if pc != nil && pc.Alias != "" {
+		return pc.Alias, nil
+	}
+	uc, err := LoadUserConfig()
+	if err != nil {
+		return "", err
+	}
+	if uc.DefaultDeployment == "" {
+		return "", errors.New("no app alias given and no default_deployment set (dibbla config set default_deployment <alias>)")
+	}
+	return uc.DefaultDeployment, nil
+}
+
+// ValidateWebhookURL checks that raw is an absolute http(s) URL.
+func ValidateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q (expected https://...)", raw)
+	}
+	return nil
+}
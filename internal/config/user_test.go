@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func withUserConfig(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig := userConfigPath
+	userConfigPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { userConfigPath = orig })
+}
+
+func mustLoad(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestLoadUserConfigMissingFile(t *testing.T) {
+	withUserConfig(t, "")
+	uc, err := LoadUserConfig()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(uc.Notifications.Webhooks) != 0 {
+		t.Errorf("webhooks = %v, want none", uc.Notifications.Webhooks)
+	}
+}
+
+func TestLoadUserConfigWebhooks(t *testing.T) {
+	withUserConfig(t, "notifications:\n  webhooks:\n    - https://hooks.slack.com/services/T/B/x\n    - http://localhost:9000/deploys\n")
+	uc, err := LoadUserConfig()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := uc.Notifications.Webhooks; len(got) != 2 || got[1] != "http://localhost:9000/deploys" {
+		t.Errorf("webhooks = %v", got)
+	}
+}
+
+func TestLoadUserConfigRejectsBadWebhook(t *testing.T) {
+	withUserConfig(t, "notifications:\n  webhooks: [\"hooks.slack.com/x\"]\n")
+	_, err := LoadUserConfig()
+	if err == nil || !strings.Contains(err.Error(), "notifications.webhooks[0]") {
+		t.Errorf("err = %v, want a notifications.webhooks[0] error", err)
+	}
+}
+
+func TestSetUserSetting(t *testing.T) {
+	withUserConfig(t, "# mine\nnotifications:\n  webhooks: [\"https://hooks.example.com/x\"]\n")
+	if err := SetUserSetting("default_deployment", "myapp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetUserSetting("api_url", "https://dibbla.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	uc, err := LoadUserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc.DefaultDeployment != "myapp" || uc.APIURL != "https://dibbla.example.com" || len(uc.Notifications.Webhooks) != 1 {
+		t.Errorf("config = %+v", uc)
+	}
+	if alias, err := AliasArg(nil); alias != "myapp" || err != nil {
+		t.Errorf("AliasArg(nil) = %q, %v", alias, err)
+	}
+	if alias, _ := AliasArg([]string{"other"}); alias != "other" {
+		t.Errorf("AliasArg(other) = %q", alias)
+	}
+
+	if err := UnsetUserSetting("default_deployment"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AliasArg(nil); err == nil || !strings.Contains(err.Error(), "default_deployment") {
+		t.Errorf("err = %v, want a default_deployment hint", err)
+	}
+	path, _ := userConfigPath()
+	if data, _ := os.ReadFile(path); !strings.HasPrefix(string(data), "# mine\n") {
+		t.Errorf("comment not kept:\n%s", data)
+	}
+}
+
+func TestLoadAPIURLFromUserConfig(t *testing.T) {
+	withUserConfig(t, "api_url: https://dibbla.example.com\n")
+	t.Setenv("DIBBLA_PROFILE", "")
+	t.Setenv("DIBBLA_API_TOKEN", "ak_env")
+	t.Setenv("DIBBLA_API_URL", "")
+	t.Setenv("DIBBLA_AUTH_SERVICE_URL", "")
+	if cfg := mustLoad(t); cfg.APIURL != "https://dibbla.example.com" {
+		t.Errorf("APIURL = %q, want the config file's", cfg.APIURL)
+	}
+	t.Setenv("DIBBLA_API_URL", "https://api.env.example.com")
+	if cfg := mustLoad(t); cfg.APIURL != "https://api.env.example.com" {
+		t.Errorf("APIURL = %q, want DIBBLA_API_URL", cfg.APIURL)
+	}
+}
@@ -0,0 +1,266 @@
+package create
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// `dibbla create add` puts a component into an already generated project,
+// so what was left out at creation (such as the frontend) can be added
+// later.
+
+// HandlerDir is where AddHTTPHandler puts handler packages, as in the Go
+// worker template.
+const HandlerDir = "internal/http_handlers"
+
+// handlerNamePattern keeps handler names usable in package names.
+var handlerNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// AddFrontend plans adding the template's frontend to the project in
+// projectDir, generated without it. The template, at the version in the
+// project's LockFile, is rendered without and with the frontend, and the
+// difference applied like an upgrade: files edited in the project (such
+// as main.go) become conflicts. Apply records the frontend in the lock.
+// Call Close when done.
+func AddFrontend(projectDir string) (*Upgrade, error) {
+	from, err := ReadLock(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if from.Frontend {
+		return nil, errors.New("the project already has the frontend")
+	}
+	to := *from
+	to.Frontend = true
+	u, err := planTemplateChange(projectDir, from, to, from.Commit)
+	if err != nil {
+		return nil, err
+	}
+	if u.To.Commit == "" {
+		u.To.Commit = from.Commit
+	}
+	if len(u.Changes) == 0 {
+		u.Close()
+		return nil, fmt.Errorf("the template %s has no frontend", from.Repo)
+	}
+	return u, nil
+}
+
+// AddFeature plans turning on the template feature name in the project in
+// projectDir, the way AddFrontend adds the frontend; a Go worker's
+// features are its extras (see ExtraDB). Apply records the feature in the
+// lock and adds its settings to .env. Call Close when done.
+func AddFeature(projectDir, name string) (*Upgrade, error) {
+	from, err := ReadLock(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if from.Features[name] {
+		return nil, fmt.Errorf("the project already has %s", name)
+	}
+	to := *from
+	to.Features = maps.Clone(from.Features)
+	if to.Features == nil {
+		to.Features = map[string]bool{}
+	}
+	to.Features[name] = true
+	u, err := planTemplateChange(projectDir, from, to, from.Commit)
+	if err != nil {
+		return nil, err
+	}
+	f := u.manifest.Feature(name)
+	if f == nil {
+		u.Close()
+		return nil, fmt.Errorf("the template %s has no %s feature", from.Repo, name)
+	}
+	if u.To.Commit == "" {
+		u.To.Commit = from.Commit
+	}
+	u.env = f.Env
+	return u, nil
+}
+
+// errNoHandlerRegistration is returned by registerHandler when main.go
+// registers no handler from HandlerDir to follow.
+var errNoHandlerRegistration = errors.New("no handler registration found")
+
+// HTTPHandler is a handler package added by AddHTTPHandler.
+type HTTPHandler struct {
+	// ImportPath is the package's import path.
+	ImportPath string
+	// RegisteredIn is the file, relative to the project, where the
+	// handler was registered with the router, or empty if that's left
+	// to the project.
+	RegisteredIn string
+}
+
+// AddHTTPHandler adds a handler package called http<name> serving
+// /api/<name> to the Go project in projectDir, under HandlerDir, and
+// registers it in the worker's main.go next to the handlers already
+// registered there (such as the template's httpgreeting). When main.go
+// registers none, wiring it into the router is left to the project.
+func AddHTTPHandler(projectDir, name string) (*HTTPHandler, error) {
+	if !handlerNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid handler name %q: use lowercase letters, digits and _, starting with a letter", name)
+	}
+	module, err := goModule(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(projectDir, filepath.FromSlash(HandlerDir), name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%s/%s already exists", HandlerDir, name)
+	}
+	data := struct{ Name, Package, Path string }{
+		Name:    name,
+		Package: "http" + name,
+		Path:    "/api/" + strings.ReplaceAll(name, "_", "-"),
+	}
+	h := &HTTPHandler{ImportPath: path.Join(module, HandlerDir, name)}
+
+	// Plan the main.go edit before writing anything, so a main.go that
+	// doesn't parse leaves the project as it was.
+	var mainFile string
+	var mainSrc []byte
+	if mainDir, err := workerMainDir(projectDir); err == nil {
+		mainFile = filepath.Join(mainDir, "main.go")
+		if err := checkRegular(mainFile); err != nil {
+			return nil, err
+		}
+		src, err := os.ReadFile(mainFile)
+		if err != nil {
+			return nil, err
+		}
+		mainSrc, err = registerHandler(src, path.Join(module, HandlerDir), h.ImportPath, data.Package)
+		if err != nil && !errors.Is(err, errNoHandlerRegistration) {
+			return nil, fmt.Errorf("registering the handler in %s: %w", mainFile, err)
+		}
+	}
+
+	if err := renderExtraFile("extras/handler/handler.go.tmpl", filepath.Join(dir, "handler.go"), data); err != nil {
+		return nil, err
+	}
+	if mainSrc != nil {
+		if err := os.WriteFile(mainFile, mainSrc, 0644); err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(projectDir, mainFile)
+		if err != nil {
+			return nil, err
+		}
+		h.RegisteredIn = filepath.ToSlash(rel)
+	}
+	return h, nil
+}
+
+// registerHandler returns src, a Go file, with pkg (imported from
+// importPath) registered after its last <handler>.Register(router) call
+// on a package under handlersPath, or errNoHandlerRegistration if there
+// is no such call. The rest of the file is left as written.
+func registerHandler(src []byte, handlersPath, importPath, pkg string) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Names the file imports handler packages under, and where to add
+	// the new import.
+	handlers := map[string]bool{}
+	var lastImport *ast.GenDecl
+	for _, d := range f.Decls {
+		gen, ok := d.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			imp := spec.(*ast.ImportSpec)
+			p := strings.Trim(imp.Path.Value, `"`)
+			if p == importPath {
+				return nil, fmt.Errorf("%s is already imported", importPath)
+			}
+			if strings.HasPrefix(p, handlersPath+"/") {
+				if imp.Name != nil {
+					handlers[imp.Name.Name] = true
+				} else {
+					handlers["http"+path.Base(p)] = true
+				}
+			}
+		}
+		if len(gen.Specs) > 0 {
+			lastImport = gen
+		}
+	}
+
+	var last *ast.ExprStmt
+	ast.Inspect(f, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.ExprStmt)
+		if !ok {
+			return true
+		}
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Register" {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && handlers[id.Name] {
+			last = stmt
+		}
+		return true
+	})
+	if last == nil || lastImport == nil {
+		return nil, errNoHandlerRegistration
+	}
+
+	call := last.X.(*ast.CallExpr)
+	router := src[fset.Position(call.Args[0].Pos()).Offset:fset.Position(call.Args[0].End()).Offset]
+	stmtEnd := fset.Position(last.End()).Offset
+	importLine := fmt.Sprintf("\nimport %q", importPath)
+	importEnd := fset.Position(lastImport.End()).Offset
+	if lastImport.Rparen.IsValid() {
+		importLine = fmt.Sprintf("\n\t%q", importPath)
+		importEnd = fset.Position(lastImport.Specs[len(lastImport.Specs)-1].End()).Offset
+	}
+
+	// The import comes before the call, so both offsets are into src.
+	var b []byte
+	b = append(b, src[:importEnd]...)
+	b = append(b, importLine...)
+	b = append(b, src[importEnd:stmtEnd]...)
+	b = append(b, fmt.Sprintf("\n%s.Register(%s)", pkg, router)...)
+	b = append(b, src[stmtEnd:]...)
+	return format.Source(b)
+}
+
+// goModule returns the module path in dir's go.mod.
+func goModule(dir string) (string, error) {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%s is not a Go project (no go.mod)", dir)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if fields := strings.Fields(s.Text()); len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", errors.New("go.mod has no module line")
+}
@@ -0,0 +1,229 @@
+package create
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAddFrontend(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := t.TempDir()
+	writeFiles(t, repo, map[string]string{
+		TemplateManifestFile:  "render: [\"*.tmpl\"]\nfrontend: [frontend]\n",
+		"main.go.tmpl":        "package main\n{{if .Features.frontend}}// serves the frontend\n{{end}}",
+		"frontend/index.html": "<html></html>\n",
+	})
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "--all"}, {"commit", "--quiet", "-m", "t"}} {
+		if err := runGit(repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	project := t.TempDir()
+	lock := &TemplateLock{Template: KindFromTemplate, Repo: repo, Name: "w"}
+	commit, _, err := renderVersion(lock, "", project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Commit = commit
+	if err := WriteLock(project, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := AddFrontend(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer u.Close()
+	want := []UpgradeChange{{"frontend/index.html", UpgradeAdd}, {"main.go", UpgradeUpdate}}
+	if !reflect.DeepEqual(u.Changes, want) {
+		t.Fatalf("Changes = %v, want %v", u.Changes, want)
+	}
+	if err := u.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if main, _ := os.ReadFile(filepath.Join(project, "main.go")); !strings.Contains(string(main), "serves the frontend") {
+		t.Errorf("main.go = %q", main)
+	}
+	if got, err := ReadLock(project); err != nil || !got.Frontend || got.Commit != commit {
+		t.Errorf("lock after apply = %+v, %v", got, err)
+	}
+
+	if _, err := AddFrontend(project); err == nil {
+		t.Error("want an error adding the frontend twice")
+	}
+}
+
+func TestAddFeature(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := t.TempDir()
+	writeFiles(t, repo, map[string]string{
+		TemplateManifestFile:          "render: [\"*.tmpl\"]\nfeatures:\n  - name: db\n    paths: [internal/database]\n    env: [DATABASE_URL=]\n",
+		"main.go.tmpl":                "package main\n{{if .Features.db}}// opens the database\n{{end}}",
+		"internal/database/db.go":     "package database\n",
+		"env.example":                 "SERVER_NAME=my-worker\n",
+		"migrations/1_users.up.sql":   "create table users ();\n",
+		"migrations/1_users.down.sql": "drop table users;\n",
+	})
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "--all"}, {"commit", "--quiet", "-m", "t"}} {
+		if err := runGit(repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	project := t.TempDir()
+	lock := &TemplateLock{Template: KindFromTemplate, Repo: repo, Name: "w"}
+	commit, _, err := renderVersion(lock, "", project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Commit = commit
+	if err := WriteLock(project, lock); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, project, map[string]string{".env": "SERVER_NAME=w\n"})
+
+	if _, err := AddFeature(project, "queue"); err == nil || !strings.Contains(err.Error(), "no queue feature") {
+		t.Errorf("unknown feature: err = %v", err)
+	}
+	u, err := AddFeature(project, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer u.Close()
+	want := []UpgradeChange{{"env.example", UpgradeUpdate}, {"internal/database/db.go", UpgradeAdd}, {"main.go", UpgradeUpdate}}
+	if !reflect.DeepEqual(u.Changes, want) {
+		t.Fatalf("Changes = %v, want %v", u.Changes, want)
+	}
+	if err := u.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if env, _ := os.ReadFile(filepath.Join(project, ".env")); !strings.Contains(string(env), "\nDATABASE_URL=\n") {
+		t.Errorf(".env = %q", env)
+	}
+	if got, err := ReadLock(project); err != nil || !got.Features["db"] || got.Commit != commit {
+		t.Errorf("lock after apply = %+v, %v", got, err)
+	}
+	if _, err := AddFeature(project, "db"); err == nil {
+		t.Error("want an error adding the feature twice")
+	}
+}
+
+func TestAddHTTPHandler(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"go.mod": "module github.com/acme/w\n\ngo 1.23\n"})
+
+	h, err := AddHTTPHandler(dir, "order_status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.ImportPath != "github.com/acme/w/internal/http_handlers/order_status" {
+		t.Errorf("import path = %q", h.ImportPath)
+	}
+	if h.RegisteredIn != "" {
+		t.Errorf("registered in %q without a main.go", h.RegisteredIn)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "internal", "http_handlers", "order_status", "handler.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package httporder_status\n", `router.Handle("/api/order-status"`} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("handler.go lacks %q:\n%s", want, got)
+		}
+	}
+
+	for _, name := range []string{"order_status", "Orders", "9x", "a-b"} {
+		if _, err := AddHTTPHandler(dir, name); err == nil {
+			t.Errorf("AddHTTPHandler(%q): want an error", name)
+		}
+	}
+}
+
+func TestAddHTTPHandler_RegistersInMain(t *testing.T) {
+	const main = `package main
+
+import (
+	"net/http"
+
+	"github.com/acme/w/internal/frontend"
+	httpgreeting "github.com/acme/w/internal/http_handlers/greeting"
+)
+
+func main() {
+	router := frontend.NewRouter()
+	// Handlers
+	httpgreeting.Register(router)
+
+	http.ListenAndServe(":8080", router)
+}
+`
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":             "module github.com/acme/w\n\ngo 1.23\n",
+		"cmd/worker/main.go": main,
+	})
+
+	h, err := AddHTTPHandler(dir, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.RegisteredIn != "cmd/worker/main.go" {
+		t.Errorf("registered in %q", h.RegisteredIn)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "cmd", "worker", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(
+		"greeting\"\n", "greeting\"\n\t\"github.com/acme/w/internal/http_handlers/orders\"\n",
+		"httpgreeting.Register(router)\n", "httpgreeting.Register(router)\n\thttporders.Register(router)\n",
+	).Replace(main)
+	if string(got) != want {
+		t.Errorf("main.go =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAddHTTPHandler_NoRegistration(t *testing.T) {
+	const main = "package main\n\nfunc main() {}\n"
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":  "module github.com/acme/w\n\ngo 1.23\n",
+		"main.go": main,
+	})
+
+	h, err := AddHTTPHandler(dir, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.RegisteredIn != "" {
+		t.Errorf("registered in %q", h.RegisteredIn)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "main.go")); string(got) != main {
+		t.Errorf("main.go changed:\n%s", got)
+	}
+}
@@ -0,0 +1,98 @@
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteCompose(t *testing.T) {
+	cases := []struct {
+		name   string
+		files  map[string]string
+		config ProjectConfig
+		start  string
+		want   []string // in ComposeFile
+	}{
+		{
+			name:   "go",
+			files:  map[string]string{"go.mod": "module w\n\ngo 1.23.4\n", "cmd/worker/main.go": "package main\n"},
+			config: ProjectConfig{Name: "github.com/acme/my-worker"},
+			want:   []string{"image: golang:1.23.4", `"go run ./cmd/worker"`, "go-mod:/go/pkg/mod", "POSTGRES_DB: my_worker", "@db:5432/my_worker"},
+		},
+		{
+			name:   "node with start",
+			files:  map[string]string{"package.json": "{}"},
+			config: ProjectConfig{Name: "w"},
+			start:  "npm run dev",
+			want:   []string{"image: node:22", `"npm install && npm run dev"`, "/app/node_modules"},
+		},
+		{
+			name:   "dockerfile",
+			files:  map[string]string{"Dockerfile": "FROM scratch\n", "go.mod": "module w\n"},
+			config: ProjectConfig{Name: "w"},
+			want:   []string{"build: ."},
+		},
+		{
+			name:   "metrics",
+			files:  map[string]string{"go.mod": "module w\n", "main.go": "package main\n"},
+			config: ProjectConfig{Name: "w", Features: map[string]bool{ExtraMetrics: true}},
+			want:   []string{"image: golang\n", `"go run ."`, `"9100:9100"`, "prom/prometheus"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tc.files[".env"] = "SERVER_NAME=w\nDATABASE_URL=\n"
+			writeFiles(t, dir, tc.files)
+			if err := writeCompose(tc.config, dir, tc.start); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, ComposeFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var parsed struct {
+				Services map[string]any `yaml:"services"`
+			}
+			if err := yaml.Unmarshal(got, &parsed); err != nil || parsed.Services["app"] == nil || parsed.Services["db"] == nil {
+				t.Fatalf("%s doesn't parse to app and db services (%v):\n%s", ComposeFile, err, got)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(string(got), w) {
+					t.Errorf("%s lacks %q:\n%s", ComposeFile, w, got)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(dir, "prometheus.yml")); (err == nil) != tc.config.Features[ExtraMetrics] {
+				t.Errorf("prometheus.yml exists = %v, want %v", err == nil, tc.config.Features[ExtraMetrics])
+			}
+			envFile, _ := os.ReadFile(filepath.Join(dir, ".env"))
+			if !strings.Contains(string(envFile), "@localhost:5432/") || strings.Count(string(envFile), "DATABASE_URL=") != 1 {
+				t.Errorf(".env = %q", envFile)
+			}
+		})
+	}
+}
+
+func TestWriteCompose_UnknownApp(t *testing.T) {
+	if err := writeCompose(ProjectConfig{Name: "w"}, t.TempDir(), ""); err == nil {
+		t.Error("want an error for a project without a Dockerfile, go.mod or package.json")
+	}
+}
+
+func TestComposeDatabase(t *testing.T) {
+	cases := map[string]string{
+		"my-worker":              "my_worker",
+		"github.com/acme/Worker": "worker",
+		"9lives":                 "app_9lives",
+		"--":                     "app",
+	}
+	for name, want := range cases {
+		if got := composeDatabase(name); got != want {
+			t.Errorf("composeDatabase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
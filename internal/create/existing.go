@@ -0,0 +1,191 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// Projects are fetched and rendered in a temporary directory and only then
+// moved into place, so one can be created in a directory that already
+// exists, such as a freshly cloned empty repository.
+
+// ErrOverwriteDeclined is returned when ProjectConfig.ConfirmOverwrite
+// turns down overwriting existing files. The directory is left as it was.
+var ErrOverwriteDeclined = errors.New("cancelled; no files were changed")
+
+// EmptyDir reports whether dir holds nothing but, possibly, .git.
+func EmptyDir(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range entries {
+		if e.Name() != ".git" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// newStageDir makes the temporary directory a project is rendered in.
+func newStageDir() (string, error) {
+	dir, err := os.MkdirTemp("", "dibbla-create-*")
+	if err != nil {
+		return "", err
+	}
+	// MkdirTemp's 0700 would carry over to the project.
+	return dir, os.Chmod(dir, 0755)
+}
+
+// placement records what moveIntoProject changed in a project directory,
+// so that a failure later on can undo it.
+type placement struct {
+	dir string
+	// created is set when dir didn't exist before.
+	created bool
+	// added are the files that didn't exist before, in an existing dir.
+	added []string
+	// backup holds the files that were overwritten, at the same paths.
+	backup string
+	// resumed marks a project finished by --resume, which is never undone.
+	resumed bool
+	// before are the entries in an existing dir before the install steps
+	// ran, to find the ones they created (go.sum, node_modules).
+	before map[string]bool
+}
+
+// undo puts dir back as it was before moveIntoProject.
+func (p *placement) undo() error {
+	defer p.done()
+	if p.created {
+		return os.RemoveAll(p.dir)
+	}
+	if p.before != nil {
+		now, err := listEntries(p.dir)
+		if err != nil {
+			return err
+		}
+		for _, f := range p.added {
+			delete(now, f)
+		}
+		for f := range p.before {
+			delete(now, f)
+		}
+		p.added = append(p.added, slices.Sorted(maps.Keys(now))...)
+	}
+	for i := len(p.added) - 1; i >= 0; i-- {
+		path := filepath.Join(p.dir, filepath.FromSlash(p.added[i]))
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		// Directories the file needed go too, once empty.
+		for d := filepath.Dir(path); d != filepath.Clean(p.dir); d = filepath.Dir(d) {
+			if os.Remove(d) != nil {
+				break
+			}
+		}
+	}
+	if p.backup == "" {
+		return nil
+	}
+	files, err := listFiles(p.backup)
+	if err != nil {
+		return err
+	}
+	for f := range files {
+		if err := copyFile(filepath.Join(p.backup, filepath.FromSlash(f)), filepath.Join(p.dir, filepath.FromSlash(f))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// done drops the backup of overwritten files, once they're no longer
+// needed.
+func (p *placement) done() {
+	if p.backup != "" {
+		os.RemoveAll(p.backup)
+		p.backup = ""
+	}
+}
+
+// listEntries returns the paths, relative to dir, of everything in dir
+// that isn't a directory, symlinks included (and not followed).
+func listEntries(dir string) (map[string]bool, error) {
+	entries := map[string]bool{}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		entries[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	return entries, err
+}
+
+// moveIntoProject puts the project rendered in stage at dir. A dir that
+// doesn't exist yet becomes stage; into an existing one stage's files are
+// copied, once confirm (if set) agrees to overwriting those already there.
+// The returned placement, also on a failed copy, can undo the move.
+func moveIntoProject(stage, dir string, confirm func(paths []string) bool) (*placement, error) {
+	p := &placement{dir: dir}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		p.created = true
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return nil, err
+		}
+		if os.Rename(stage, dir) == nil {
+			return p, nil
+		}
+		// Most likely stage is on another file system; copy instead.
+	} else if err != nil {
+		return nil, err
+	}
+
+	files, err := listFiles(stage)
+	if err != nil {
+		return nil, err
+	}
+	var existing []string
+	for f := range files {
+		if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(f))); err == nil {
+			existing = append(existing, f)
+		} else {
+			p.added = append(p.added, f)
+		}
+	}
+	sort.Strings(existing)
+	sort.Strings(p.added)
+	if len(existing) > 0 && confirm != nil && !confirm(existing) {
+		return nil, ErrOverwriteDeclined
+	}
+	if len(existing) > 0 {
+		if p.backup, err = os.MkdirTemp("", "dibbla-backup-*"); err != nil {
+			return nil, err
+		}
+		for _, f := range existing {
+			if err := copyFile(filepath.Join(dir, filepath.FromSlash(f)), filepath.Join(p.backup, filepath.FromSlash(f))); err != nil {
+				p.done()
+				return nil, fmt.Errorf("backing up %s: %w", f, err)
+			}
+		}
+	}
+
+	fmt.Println("  Copying files into place...")
+	for f := range files {
+		src := filepath.Join(stage, filepath.FromSlash(f))
+		if err := copyFile(src, filepath.Join(dir, filepath.FromSlash(f))); err != nil {
+			return p, fmt.Errorf("%s: %w", f, err)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,98 @@
+package create
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	if empty, err := EmptyDir(dir); err != nil || !empty {
+		t.Errorf("new dir: EmptyDir = %v, %v", empty, err)
+	}
+	writeFiles(t, dir, map[string]string{".git/HEAD": "ref: refs/heads/main\n"})
+	if empty, err := EmptyDir(dir); err != nil || !empty {
+		t.Errorf("empty clone: EmptyDir = %v, %v", empty, err)
+	}
+	writeFiles(t, dir, map[string]string{"README.md": "# x\n"})
+	if empty, err := EmptyDir(dir); err != nil || empty {
+		t.Errorf("with a README: EmptyDir = %v, %v", empty, err)
+	}
+}
+
+func TestMoveIntoProject(t *testing.T) {
+	rendered := map[string]string{"main.go": "package main\n", "README.md": "# template\n", "web/index.html": "<html>\n"}
+
+	t.Run("new directory", func(t *testing.T) {
+		stage, dir := t.TempDir(), filepath.Join(t.TempDir(), "a", "b")
+		writeFiles(t, stage, rendered)
+		p, err := moveIntoProject(stage, dir, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := os.ReadFile(filepath.Join(dir, "web", "index.html")); string(data) != "<html>\n" {
+			t.Errorf("web/index.html = %q", data)
+		}
+		if err := p.undo(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("undo left %s: %v", dir, err)
+		}
+	})
+
+	existing := func(t *testing.T) (stage, dir string) {
+		stage, dir = t.TempDir(), t.TempDir()
+		writeFiles(t, stage, rendered)
+		writeFiles(t, dir, map[string]string{"README.md": "# mine\n", "LICENSE": "MIT\n"})
+		return stage, dir
+	}
+
+	t.Run("declined", func(t *testing.T) {
+		stage, dir := existing(t)
+		var asked []string
+		_, err := moveIntoProject(stage, dir, func(paths []string) bool { asked = paths; return false })
+		if !errors.Is(err, ErrOverwriteDeclined) {
+			t.Fatalf("err = %v", err)
+		}
+		if !reflect.DeepEqual(asked, []string{"README.md"}) {
+			t.Errorf("asked about %v", asked)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "main.go")); !os.IsNotExist(err) {
+			t.Errorf("main.go was written: %v", err)
+		}
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		stage, dir := existing(t)
+		p, err := moveIntoProject(stage, dir, func([]string) bool { return true })
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, want := range map[string]string{"README.md": "# template\n", "LICENSE": "MIT\n", "main.go": "package main\n"} {
+			if data, _ := os.ReadFile(filepath.Join(dir, name)); string(data) != want {
+				t.Errorf("%s = %q, want %q", name, data, want)
+			}
+		}
+
+		if err := p.undo(); err != nil {
+			t.Fatal(err)
+		}
+		files, err := listFiles(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(files, map[string]bool{"README.md": true, "LICENSE": true}) {
+			t.Errorf("after undo: %v", files)
+		}
+		if data, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(data) != "# mine\n" {
+			t.Errorf("README.md after undo = %q", data)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "web")); !os.IsNotExist(err) {
+			t.Errorf("undo left web/: %v", err)
+		}
+	})
+}
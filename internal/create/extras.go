@@ -0,0 +1,83 @@
+package create
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Extras are the features of the Go worker starter its --with-* options
+// turn on: a database client with a migrations folder, a cron scheduler
+// and a Prometheus metrics endpoint. Their code comes with the starter's
+// built-in manifest, under starters/go-worker.
+const (
+	ExtraDB      = "db"
+	ExtraCron    = "cron"
+	ExtraMetrics = "metrics"
+)
+
+// extrasFS holds the templates `create` adds to projects outside their
+// template: compose files and HTTP handlers.
+//
+//go:embed extras
+var extrasFS embed.FS
+
+// renderExtraFile renders the template name in extrasFS with data to dest.
+func renderExtraFile(name, dest string, data any) error {
+	content, err := extrasFS.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, out.Bytes(), 0644)
+}
+
+// workerMainDir finds the worker's main package in dir: cmd/worker, as in
+// the starter template, or the project root.
+func workerMainDir(dir string) (string, error) {
+	for _, d := range []string{filepath.Join(dir, "cmd", "worker"), dir} {
+		if _, err := os.Stat(filepath.Join(d, "main.go")); err == nil {
+			return d, nil
+		}
+	}
+	return "", errors.New("can't find the worker's main.go (looked in cmd/worker and the project root)")
+}
+
+// appendLines adds lines to the end of file, after a blank line, if file
+// exists. file must be a regular file.
+func appendLines(file string, lines []string) error {
+	if err := checkRegular(file); errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	b.Write(content)
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		b.WriteString("\n")
+	}
+	b.WriteString("\n" + strings.Join(lines, "\n") + "\n")
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(b.String()), info.Mode().Perm())
+}
@@ -0,0 +1,109 @@
+package create
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoWorkerExtras(t *testing.T) {
+	cases := []struct {
+		name          string
+		extras        map[string]bool
+		files, absent []string
+		env           []string
+	}{
+		{
+			name:   "none",
+			absent: []string{"internal/database", "migrations", "cmd/worker/worker_extras.go"},
+		},
+		{
+			name:   "db",
+			extras: map[string]bool{ExtraDB: true},
+			files:  []string{"internal/database/database.go", "migrations/00000000000000_init.up.sql"},
+			absent: []string{"internal/jobs", "cmd/worker/worker_extras.go"},
+			env:    []string{"DATABASE_URL="},
+		},
+		{
+			name:   "all",
+			extras: map[string]bool{ExtraDB: true, ExtraCron: true, ExtraMetrics: true},
+			files:  []string{"internal/database/database.go", "internal/jobs/jobs.go", "internal/metrics/metrics.go", "cmd/worker/worker_extras.go"},
+			env:    []string{"DATABASE_URL=", `CRON_SCHEDULE="*/5 * * * *"`, "METRICS_ADDR=:9100"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"go.mod":             "module " + templateModule + "\n",
+				"cmd/worker/main.go": "package main\n",
+				".env":               "SERVER_NAME=w\n",
+				"env.example":        "SERVER_NAME=my-worker",
+			})
+			m, err := renderGoWorker(dir, "github.com/acme/w", false, tc.extras)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := lockedFeatures(m.FeatureValues(false, tc.extras)); len(got) != 3 {
+				t.Errorf("locked features = %v, want all three extras", got)
+			}
+			for _, f := range tc.files {
+				if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+					t.Errorf("%s: %v", f, err)
+				}
+			}
+			for _, f := range tc.absent {
+				if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+					t.Errorf("%s exists", f)
+				}
+			}
+			for _, file := range []string{".env", "env.example"} {
+				got, _ := os.ReadFile(filepath.Join(dir, file))
+				for _, line := range tc.env {
+					if !strings.Contains(string(got), "\n"+line+"\n") {
+						t.Errorf("%s = %q, want a line %q", file, got, line)
+					}
+				}
+			}
+
+			// The generated Go must at least parse.
+			filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+				if err == nil && strings.HasSuffix(p, ".go") {
+					if _, err := parser.ParseFile(token.NewFileSet(), p, nil, 0); err != nil {
+						t.Error(err)
+					}
+				}
+				return nil
+			})
+		})
+	}
+}
+
+func TestGoWorkerExtras_ImportsModule(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"go.mod": "module " + templateModule + "\n"})
+	if _, err := renderGoWorker(dir, "github.com/acme/w", false, map[string]bool{ExtraCron: true}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "cmd", "worker", "worker_extras.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `"github.com/acme/w/internal/jobs"`) || strings.Contains(string(got), "internal/metrics") {
+		t.Errorf("worker_extras.go = %s", got)
+	}
+}
+
+func TestGoWorkerExtras_NotInManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		TemplateManifestFile: "module: " + templateModule + "\nfiles: [go.mod]\n",
+		"go.mod":             "module " + templateModule + "\n",
+	})
+	if _, err := renderGoWorker(dir, "github.com/acme/w", false, map[string]bool{ExtraDB: true}); err == nil || !strings.Contains(err.Error(), "no db feature") {
+		t.Fatalf("err = %v, want a missing-feature error", err)
+	}
+}
@@ -0,0 +1,110 @@
+package create
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// gitignoreEntries are added to a generated project's .gitignore when it
+// doesn't already have them: env files holding secrets and installed
+// dependencies. The lock file in .dibbla stays tracked.
+var gitignoreEntries = []string{".env", ".env.local", "node_modules/"}
+
+// gitInitStep makes the project in dir a git repository, as a setupStep;
+// commitProject then makes the first commit once the other steps are done.
+// The repository is skipped when git isn't installed or dir is already
+// inside one (such as a clone the project was created in); failures only
+// get a warning, since the project itself is complete. initialized is set
+// when the step made a repository.
+func gitInitStep(dir string, initialized *bool) setupStep {
+	return setupStep{
+		name:     "git init",
+		optional: true,
+		run: func(io.Writer) error {
+			if _, err := exec.LookPath("git"); err != nil {
+				return &skippedError{"git not found"}
+			}
+			if err := runGit(dir, "rev-parse", "--git-dir"); err == nil {
+				return &skippedError{"already inside a git repository"}
+			}
+			if err := runGit(dir, "init", "--quiet"); err != nil {
+				return err
+			}
+			*initialized = true
+			return nil
+		},
+	}
+}
+
+// commitProject makes sure the project in dir has a .gitignore, then, if
+// gitInitStep made it a repository, commits the generated files with lock
+// saying where they came from.
+func commitProject(dir string, lock *TemplateLock, initialized bool) {
+	if err := ensureGitignore(dir); err != nil {
+		fmt.Printf("  %s Warning: could not write .gitignore: %v\n", platform.Icon("⚠️", "[!]"), err)
+		return
+	}
+	if !initialized {
+		return
+	}
+
+	fmt.Println("  Creating the initial commit...")
+	if err := runGit(dir, "add", "--all"); err != nil {
+		fmt.Printf("  %s Warning: git add failed: %v\n", platform.Icon("⚠️", "[!]"), err)
+		return
+	}
+	// Without a committer identity git fails with a long explanation.
+	if err := runGit(dir, "var", "GIT_COMMITTER_IDENT"); err != nil {
+		fmt.Printf("  %s Warning: git user.name and user.email are not set, so the files are staged but not committed.\n", platform.Icon("⚠️", "[!]"))
+		fmt.Println("     Set them, then run 'git commit'.")
+		return
+	}
+	msg := fmt.Sprintf("Initial commit\n\nGenerated by 'dibbla create %s' from %s at %s.", lock.Template, lock.Repo, lock.Version())
+	if err := runGit(dir, "commit", "--quiet", "--no-verify", "-m", msg); err != nil {
+		fmt.Printf("  %s Warning: could not create the initial commit: %v\n", platform.Icon("⚠️", "[!]"), err)
+	}
+}
+
+// ensureGitignore adds to dir's .gitignore the gitignoreEntries it is
+// missing, creating the file if needed.
+func ensureGitignore(dir string) error {
+	path := filepath.Join(dir, ".gitignore")
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	have := map[string]bool{}
+	for _, line := range strings.Split(string(content), "\n") {
+		have[strings.Trim(strings.TrimSpace(line), "/")] = true
+	}
+	var missing []string
+	for _, e := range gitignoreEntries {
+		if !have[strings.Trim(e, "/")] {
+			missing = append(missing, e)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.Write(content)
+	if len(content) > 0 {
+		if !strings.HasSuffix(string(content), "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("# Added by dibbla create\n")
+	for _, e := range missing {
+		b.WriteString(e + "\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
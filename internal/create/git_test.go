@@ -0,0 +1,79 @@
+package create
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsureGitignore(t *testing.T) {
+	cases := []struct {
+		name, existing, want string
+	}{
+		{"no file", "", "# Added by dibbla create\n.env\n.env.local\nnode_modules/\n"},
+		{"some entries", "/node_modules\n.env", "/node_modules\n.env\n\n# Added by dibbla create\n.env.local\n"},
+		{"all entries", ".env\n.env.local\nnode_modules/\n", ".env\n.env.local\nnode_modules/\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tc.existing != "" {
+				writeFiles(t, dir, map[string]string{".gitignore": tc.existing})
+			}
+			if err := ensureGitignore(dir); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := os.ReadFile(filepath.Join(dir, ".gitignore"))
+			if string(got) != tc.want {
+				t.Errorf(".gitignore = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGitInitAndCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"main.go": "package main\n", ".env": "SERVER_API_TOKEN=ak_secret\n"})
+	var initialized bool
+	if err := gitInitStep(dir, &initialized).run(io.Discard); err != nil || !initialized {
+		t.Fatalf("git init: %v (initialized %v)", err, initialized)
+	}
+	commitProject(dir, &TemplateLock{Template: KindGoWorker, Repo: "https://example.com/t.git", Ref: "v1", Name: "w"}, initialized)
+
+	out, err := exec.Command("git", "-C", dir, "ls-files").Output()
+	if err != nil {
+		t.Fatalf("git ls-files: %v", err)
+	}
+	if files := strings.Fields(string(out)); strings.Join(files, " ") != ".gitignore main.go" {
+		t.Errorf("committed files = %v", files)
+	}
+	msg, _ := exec.Command("git", "-C", dir, "log", "-1", "--format=%B").Output()
+	if !strings.Contains(string(msg), "from https://example.com/t.git at v1") {
+		t.Errorf("commit message = %q", msg)
+	}
+
+	// A project inside a repository gets no repository of its own.
+	nested := filepath.Join(dir, "nested")
+	writeFiles(t, nested, map[string]string{"main.go": "package main\n"})
+	initialized = false
+	var skipped *skippedError
+	if err := gitInitStep(nested, &initialized).run(io.Discard); !errors.As(err, &skipped) || initialized {
+		t.Errorf("git init in a repository: %v (initialized %v)", err, initialized)
+	}
+	if _, err := os.Stat(filepath.Join(nested, ".git")); !os.IsNotExist(err) {
+		t.Errorf("nested .git exists: %v", err)
+	}
+}
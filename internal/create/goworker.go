@@ -1,13 +1,13 @@
 package create
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-
-	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 )
 
 const (
@@ -23,104 +23,155 @@ type ProjectConfig struct {
 	SelfHosted      bool
 	GrpcAddress     string
 	UseTLS          bool
+	// SkipInstall leaves out installing dependencies (go mod tidy, npm
+	// install).
+	SkipInstall bool
+	// Ref is the template branch, tag or commit to use; empty for the
+	// default branch.
+	Ref string
+	// Offline uses the template cached by an earlier create instead of
+	// cloning or downloading it.
+	Offline bool
+	// NoGit leaves out initializing a git repository with an initial
+	// commit.
+	NoGit bool
+	// Dir is the directory the project goes in; empty for one named
+	// Name. It may already exist, e.g. as an empty clone.
+	Dir string
+	// Features turns template features on or off by name; the others get
+	// their defaults. The frontend is IncludeFrontend. For a Go worker
+	// these are the extras (see ExtraDB).
+	Features map[string]bool
+	// WithCompose adds a ComposeFile for local development.
+	WithCompose bool
+	// DryRun renders the project without writing it, printing its file
+	// tree and key files instead; dependencies and git are left out too.
+	DryRun bool
+	// KeepPartial leaves a project that failed after its files were put
+	// in place as it is, instead of undoing the changes (see PartialError).
+	KeepPartial bool
+	// Resume finishes such a kept project: it runs the remaining steps
+	// (dependencies, git) in the existing Dir without generating anything.
+	Resume bool
+	// ConfirmOverwrite is asked, with their paths, before files already
+	// in an existing Dir are overwritten; false cancels with
+	// ErrOverwriteDeclined. Nil overwrites them.
+	ConfirmOverwrite func(paths []string) bool
+}
+
+// ProjectDir is the directory the project goes in.
+func (c ProjectConfig) ProjectDir() string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	return c.Name
 }
 
 // GoWorker creates a new Go worker project from the template
 func GoWorker(config ProjectConfig) error {
-	// Step 1: Clone the template
-	fmt.Println("  Cloning template...")
-	if err := cloneTemplate(config.Name); err != nil {
-		return fmt.Errorf("failed to clone template: %w", err)
+	if config.Resume {
+		return resumeProject(config, KindGoWorker, func(c ProjectConfig, _ *TemplateLock) []setupStep { return goInstallSteps(c) })
 	}
-
-	// Step 2: Remove .git directory
-	gitDir := filepath.Join(config.Name, ".git")
-	if err := os.RemoveAll(gitDir); err != nil {
-		return fmt.Errorf("failed to remove .git: %w", err)
+	stage, err := newStageDir()
+	if err != nil {
+		return err
 	}
+	defer os.RemoveAll(stage)
 
-	// Step 3: Replace module path in all files
-	fmt.Println("  Configuring module path...")
-	if err := replaceModulePath(config.Name); err != nil {
-		return fmt.Errorf("failed to replace module path: %w", err)
+	// Step 1: Get the template (clone, download or cached copy)
+	commit, err := fetchTemplate(templateRepo, config.Ref, stage, config.Offline)
+	if err != nil {
+		return err
 	}
 
-	// Step 4: Create .env file
+	// Step 2: Create .env file
 	fmt.Println("  Creating .env...")
-	if err := createEnvFile(config); err != nil {
+	if err := createEnvFile(config, stage, "env.example"); err != nil {
 		return fmt.Errorf("failed to create .env: %w", err)
 	}
 
-	// Step 5: Handle frontend toggle
+	// Step 3: Module path, frontend toggle and clean-up
+	fmt.Println("  Configuring module path...")
 	if !config.IncludeFrontend {
 		fmt.Println("  Removing frontend (not selected)...")
-		if err := removeFrontend(config.Name); err != nil {
-			return fmt.Errorf("failed to remove frontend: %w", err)
-		}
-	} else {
-		// Install frontend dependencies
-		fmt.Println("  Installing frontend dependencies...")
-		if err := installFrontendDeps(config.Name); err != nil {
-			// Non-fatal - warn but continue
-			fmt.Printf("  %s Warning: npm install failed: %v\n", platform.Icon("⚠️", "[!]"), err)
-			fmt.Println("     Run 'cd frontend && npm install' manually.")
+	}
+	m, err := renderGoWorker(stage, config.Name, config.IncludeFrontend, config.Features)
+	if err != nil {
+		return err
+	}
+	if config.WithCompose {
+		if err := writeCompose(config, stage, "go run ./cmd/worker"); err != nil {
+			return err
 		}
 	}
 
-	// Step 6: Clean up optional/docs folders
-	fmt.Println("  Cleaning up...")
-	if err := cleanupProject(config.Name); err != nil {
-		// Non-fatal, just warn
-		fmt.Printf("  %s Warning: cleanup had issues: %v\n", platform.Icon("⚠️", "[!]"), err)
+	// Step 4: Record the template version
+	lock := &TemplateLock{
+		Template: KindGoWorker, Repo: templateRepo, Ref: config.Ref, Commit: commit,
+		Name: config.Name, Frontend: config.IncludeFrontend,
+		Features: lockedFeatures(m.FeatureValues(config.IncludeFrontend, config.Features)),
 	}
-
-	// Step 7: Run go mod tidy
-	fmt.Println("  Running go mod tidy...")
-	if err := runGoModTidy(config.Name); err != nil {
-		return fmt.Errorf("failed to run go mod tidy: %w", err)
+	if err := WriteLock(stage, lock); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFile, err)
+	}
+	if config.DryRun {
+		return previewProject(os.Stdout, stage, config.ProjectDir())
+	}
+	p, err := moveIntoProject(stage, config.ProjectDir(), config.ConfirmOverwrite)
+	if err != nil {
+		return config.abandon(p, err, false)
 	}
 
-	return nil
-}
-
-func cloneTemplate(destDir string) error {
-	cmd := exec.Command("git", "clone", "--depth", "1", templateRepo, destDir)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	// Steps 5 and 6: Install dependencies, initial commit
+	return finishProject(config, p, lock, goInstallSteps(config))
 }
 
-func replaceModulePath(projectDir string) error {
-	// Files to update: go.mod and all .go files
-	return filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip .git directory
-		if info.IsDir() && info.Name() == ".git" {
-			return filepath.SkipDir
-		}
-
-		// Skip node_modules
-		if info.IsDir() && info.Name() == "node_modules" {
-			return filepath.SkipDir
-		}
-
-		// Only process go.mod and .go files
-		if info.IsDir() {
+// goInstallSteps install the project's dependencies.
+func goInstallSteps(config ProjectConfig) []setupStep {
+	dir := config.ProjectDir()
+	var steps []setupStep
+	if config.IncludeFrontend {
+		steps = append(steps, frontendInstallStep(dir))
+	}
+	return append(steps, setupStep{
+		name: "go mod tidy",
+		run: func(out io.Writer) error {
+			if err := RunGoModTidy(dir, out); err != nil {
+				return fmt.Errorf("failed to run go mod tidy: %w", err)
+			}
 			return nil
-		}
+		},
+	})
+}
 
-		if info.Name() == "go.mod" || strings.HasSuffix(info.Name(), ".go") {
-			return replaceInFile(path, templateModule, projectDir)
-		}
+// frontendInstallStep installs the dependencies in frontend/. The worker
+// runs without them, so a failure is only a warning.
+func frontendInstallStep(projectDir string) setupStep {
+	return setupStep{
+		name:     "npm install (frontend)",
+		optional: true,
+		hint:     "Run 'cd frontend && npm install' manually.",
+		run: func(out io.Writer) error {
+			return npmInstall(filepath.Join(projectDir, "frontend"), out)
+		},
+	}
+}
 
-		return nil
-	})
+// renderGoWorker makes the file changes GoWorker applies to the template
+// in dir: module path, frontend toggle, extras and clean-up, as the
+// starter's manifest describes, and returns the manifest. `create
+// upgrade` replays it on each template version.
+func renderGoWorker(dir, module string, includeFrontend bool, extras map[string]bool) (*TemplateManifest, error) {
+	return renderStarter(dir, templateRepo, "go-worker", module, includeFrontend, extras)
 }
 
+// replaceInFile replaces every oldStr in filePath with newStr. filePath
+// must be a regular file: templates are untrusted, and a symlink could
+// point anywhere.
 func replaceInFile(filePath, oldStr, newStr string) error {
+	if err := checkRegular(filePath); err != nil {
+		return err
+	}
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return err
@@ -135,9 +186,20 @@ func replaceInFile(filePath, oldStr, newStr string) error {
 	return nil
 }
 
-func createEnvFile(config ProjectConfig) error {
-	envPath := filepath.Join(config.Name, ".env")
-	examplePath := filepath.Join(config.Name, "env.example")
+// createEnvFile writes dir's .env from the template's example env file (a
+// path relative to dir), filling in the name, token and self-hosted
+// settings.
+func createEnvFile(config ProjectConfig, dir, exampleFile string) error {
+	envPath := filepath.Join(dir, ".env")
+	examplePath := filepath.Join(dir, exampleFile)
+
+	// The template's files mustn't redirect the read or the write through
+	// a symlink.
+	for _, path := range []string{examplePath, envPath} {
+		if err := checkRegular(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
 
 	// Read env.example
 	content, err := os.ReadFile(examplePath)
@@ -170,147 +232,30 @@ func createEnvFile(config ProjectConfig) error {
 	return os.WriteFile(envPath, []byte(envContent), 0644)
 }
 
-func removeFrontend(projectDir string) error {
-	// Directories to remove when frontend is disabled
-	dirsToRemove := []string{
-		filepath.Join(projectDir, "frontend"),
-		filepath.Join(projectDir, "internal", "frontend"),
-		filepath.Join(projectDir, "internal", "http_handlers"),
-	}
-
-	for _, dir := range dirsToRemove {
-		if err := os.RemoveAll(dir); err != nil {
-			return err
-		}
-	}
-
-	// Update main.go to remove frontend imports and code
-	mainGoPath := filepath.Join(projectDir, "cmd", "worker", "main.go")
-	return updateMainGoWithoutFrontend(mainGoPath)
-}
-
-func updateMainGoWithoutFrontend(mainGoPath string) error {
-	content, err := os.ReadFile(mainGoPath)
+// checkRegular returns an error unless path is a regular file; a missing
+// file's error wraps os.ErrNotExist.
+func checkRegular(path string) error {
+	info, err := os.Lstat(path)
 	if err != nil {
 		return err
 	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	skipBlock := false
-	skipHTTPConfig := false
-
-	for _, line := range lines {
-		// Skip net/http import (not needed without frontend)
-		if strings.Contains(line, `"net/http"`) {
-			continue
-		}
-
-		// Skip frontend-related imports
-		if strings.Contains(line, `internal/frontend"`) ||
-			strings.Contains(line, `internal/http_handlers/`) {
-			continue
-		}
-
-		// Skip comment about frontend
-		if strings.Contains(line, "Frontend and HTTP handlers (optional") {
-			continue
-		}
-
-		// Skip HTTP server config block (starts with comment)
-		if strings.Contains(line, "HTTP server config") {
-			skipHTTPConfig = true
-			continue
-		}
-
-		// End of HTTP config block (empty line or next comment)
-		if skipHTTPConfig {
-			if strings.TrimSpace(line) == "" || strings.Contains(line, "// Create SDK server") {
-				skipHTTPConfig = false
-				if strings.Contains(line, "// Create SDK server") {
-					newLines = append(newLines, line)
-				}
-				continue
-			}
-			continue
-		}
-
-		// Skip HTTP server setup block
-		if strings.Contains(line, "Start HTTP server with frontend") {
-			skipBlock = true
-			continue
-		}
-
-		// End of HTTP server block
-		if skipBlock && strings.Contains(line, "}()") {
-			skipBlock = false
-			continue
-		}
-
-		if skipBlock {
-			continue
-		}
-
-		// Skip router and http handler lines
-		if strings.Contains(line, "router := frontend.NewRouter()") ||
-			strings.Contains(line, "httpgreeting.Register(") ||
-			strings.Contains(line, `HTTP: POST /api/greeting`) {
-			continue
-		}
-
-		newLines = append(newLines, line)
-	}
-
-	// Clean up multiple consecutive empty lines
-	cleanedContent := cleanEmptyLines(strings.Join(newLines, "\n"))
-
-	return os.WriteFile(mainGoPath, []byte(cleanedContent), 0644)
-}
-
-func cleanEmptyLines(content string) string {
-	lines := strings.Split(content, "\n")
-	var result []string
-	prevEmpty := false
-
-	for _, line := range lines {
-		isEmpty := strings.TrimSpace(line) == ""
-		if isEmpty && prevEmpty {
-			continue
-		}
-		result = append(result, line)
-		prevEmpty = isEmpty
-	}
-
-	return strings.Join(result, "\n")
-}
-
-func cleanupProject(projectDir string) error {
-	// Remove _optional directory (not needed in generated project)
-	optionalDir := filepath.Join(projectDir, "_optional")
-	if err := os.RemoveAll(optionalDir); err != nil {
-		return err
-	}
-
-	// Remove nested template directory if it exists
-	nestedTemplate := filepath.Join(projectDir, "go-worker-starter-template")
-	if err := os.RemoveAll(nestedTemplate); err != nil {
-		return err
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
 	}
-
 	return nil
 }
 
-func runGoModTidy(projectDir string) error {
+// RunGoModTidy runs go mod tidy in projectDir, which also adds the
+// modules new imports need, writing its output to out.
+func RunGoModTidy(projectDir string, out io.Writer) error {
 	cmd := exec.Command("go", "mod", "tidy")
 	cmd.Dir = projectDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = out
+	cmd.Stderr = out
 	return cmd.Run()
 }
 
-func installFrontendDeps(projectDir string) error {
-	frontendDir := filepath.Join(projectDir, "frontend")
-
+func npmInstall(dir string, out io.Writer) error {
 	// Check if npm is available
 	checkCmd := exec.Command("npm", "--version")
 	if err := checkCmd.Run(); err != nil {
@@ -318,8 +263,8 @@ func installFrontendDeps(projectDir string) error {
 	}
 
 	cmd := exec.Command("npm", "install")
-	cmd.Dir = frontendDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
 	return cmd.Run()
 }
@@ -0,0 +1,67 @@
+package create
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderGoWorker_BuiltinManifest(t *testing.T) {
+	for _, frontend := range []bool{true, false} {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"go.mod":                      "module " + templateModule + "\n",
+			"cmd/worker/main.go":          "package main // the starter's own\n",
+			"internal/frontend/router.go": "package frontend\n",
+			"internal/http_handlers/greeting/greet.go": "package greeting\n",
+			"frontend/index.html":                      "<html></html>\n",
+			"_optional/README.md":                      "notes\n",
+		})
+
+		if _, err := renderGoWorker(dir, "example.com/w", frontend, nil); err != nil {
+			t.Fatalf("frontend=%v: %v", frontend, err)
+		}
+		if got, _ := os.ReadFile(filepath.Join(dir, "go.mod")); string(got) != "module example.com/w\n" {
+			t.Errorf("frontend=%v: go.mod = %q", frontend, got)
+		}
+		main, _ := os.ReadFile(filepath.Join(dir, "cmd", "worker", "main.go"))
+		if _, err := parser.ParseFile(token.NewFileSet(), "main.go", main, 0); err != nil {
+			t.Errorf("frontend=%v: main.go doesn't parse: %v\n%s", frontend, err, main)
+		}
+		if strings.Contains(string(main), templateModule) {
+			t.Errorf("frontend=%v: main.go still imports the template module:\n%s", frontend, main)
+		}
+		if got := strings.Contains(string(main), `"example.com/w/internal/frontend"`); got != frontend {
+			t.Errorf("frontend=%v: main.go imports the frontend = %v:\n%s", frontend, got, main)
+		}
+		for _, p := range []string{"frontend", "internal/frontend", "internal/http_handlers"} {
+			if _, err := os.Stat(filepath.Join(dir, p)); (err == nil) != frontend {
+				t.Errorf("frontend=%v: %s exists = %v", frontend, p, err == nil)
+			}
+		}
+		for _, gone := range []string{"_optional", TemplateManifestFile, "cmd/worker/main.go.tmpl"} {
+			if _, err := os.Stat(filepath.Join(dir, gone)); !os.IsNotExist(err) {
+				t.Errorf("frontend=%v: %s not removed", frontend, gone)
+			}
+		}
+	}
+}
+
+func TestRenderGoWorker_OwnManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		TemplateManifestFile: "module: " + templateModule + "\nfiles: [go.mod]\n",
+		"go.mod":             "module " + templateModule + "\n",
+		"cmd/worker/main.go": "package main // the starter's own\n",
+	})
+
+	if _, err := renderGoWorker(dir, "example.com/w", false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "cmd", "worker", "main.go")); string(got) != "package main // the starter's own\n" {
+		t.Errorf("main.go = %q, want the starter's", got)
+	}
+}
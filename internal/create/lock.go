@@ -0,0 +1,106 @@
+package create
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LockFile records, inside a generated project, which template and
+// version it came from, so `dibbla create upgrade` can bring in later
+// template changes.
+const LockFile = ".dibbla/template.lock"
+
+// Template kinds recorded in TemplateLock.Template.
+const (
+	KindGoWorker     = "go-worker"
+	KindNodeWorker   = "node-worker"
+	KindFromTemplate = "from-template"
+)
+
+// TemplateLock is the content of LockFile.
+type TemplateLock struct {
+	Template string `yaml:"template"`
+	Repo     string `yaml:"repo"`
+	// Ref is the branch, tag or commit asked for; empty for the default
+	// branch.
+	Ref string `yaml:"ref,omitempty"`
+	// Commit is the commit the project was generated from, when known
+	// (not for downloaded archives).
+	Commit string `yaml:"commit,omitempty"`
+	// Name is what the template's module path or package name was
+	// replaced with.
+	Name     string `yaml:"name"`
+	Frontend bool   `yaml:"frontend"`
+	// Features records the template's other features, on or off.
+	Features map[string]bool `yaml:"features,omitempty"`
+}
+
+// Version is the lock's template version for messages: the ref and short
+// commit, or "default branch".
+func (l *TemplateLock) Version() string {
+	v := l.Ref
+	if v == "" {
+		v = "default branch"
+	}
+	if l.Commit != "" && l.Commit != l.Ref {
+		v += " (" + shortCommit(l.Commit) + ")"
+	}
+	return v
+}
+
+// pinnedRef is the ref that fetches exactly the lock's version: the
+// commit, when known.
+func (l *TemplateLock) pinnedRef() string {
+	if l.Commit != "" {
+		return l.Commit
+	}
+	return l.Ref
+}
+
+func shortCommit(c string) string {
+	if len(c) > 7 {
+		return c[:7]
+	}
+	return c
+}
+
+// WriteLock writes lock to projectDir's LockFile.
+func WriteLock(projectDir string, lock *TemplateLock) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(projectDir, filepath.FromSlash(LockFile))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	header := "# Written by dibbla create; used by 'dibbla create upgrade'. Commit it.\n"
+	return os.WriteFile(path, append([]byte(header), data...), 0644)
+}
+
+// ReadLock reads projectDir's LockFile.
+func ReadLock(projectDir string) (*TemplateLock, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(LockFile)))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no %s in %s; is it a project made with dibbla create?", LockFile, projectDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lock TemplateLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", LockFile, err)
+	}
+	switch lock.Template {
+	case KindGoWorker, KindNodeWorker, KindFromTemplate:
+	default:
+		return nil, fmt.Errorf("invalid %s: unknown template %q", LockFile, lock.Template)
+	}
+	if lock.Repo == "" || lock.Name == "" {
+		return nil, fmt.Errorf("invalid %s: repo and name are required", LockFile)
+	}
+	return &lock, nil
+}
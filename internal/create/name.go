@@ -0,0 +1,71 @@
+package create
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Project names become the project directory, the Go module or npm
+// package name and, as the directory name, the default alias of 'dibbla
+// deploy', so they have to be valid DNS labels.
+
+// maxProjectNameLen is the longest DNS label.
+const maxProjectNameLen = 63
+
+var (
+	projectNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	// camelBoundary finds where a word starts in camelCase names.
+	camelBoundary   = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	nonProjectChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// reservedNames clash with Go tooling (main, test, ...) or hostnames the
+// platform keeps for itself. NormalizeProjectName adds "-worker" to them.
+var reservedNames = map[string]bool{
+	"go": true, "main": true, "test": true, "internal": true, "vendor": true, "std": true,
+	"api": true, "app": true, "www": true, "admin": true, "dibbla": true,
+}
+
+// CheckProjectName reports what is wrong with name as a project name, if
+// anything, with a corrected name to suggest ("" if there is none).
+func CheckProjectName(name string) (suggestion string, err error) {
+	switch {
+	case name == "":
+		err = fmt.Errorf("the project name can't be empty")
+	case strings.ContainsAny(name, `/\`):
+		err = fmt.Errorf("project name %q can't contain path separators; create the project from its parent directory instead", name)
+	case reservedNames[name]:
+		err = fmt.Errorf("project name %q is reserved", name)
+	case len(name) > maxProjectNameLen:
+		err = fmt.Errorf("project name %q is longer than %d characters, so it can't be an app alias", name, maxProjectNameLen)
+	case !projectNamePattern.MatchString(name):
+		err = fmt.Errorf("project name %q must be lowercase letters, digits and dashes, starting and ending with a letter or digit, to be usable as an app alias", name)
+	default:
+		return "", nil
+	}
+	if s := NormalizeProjectName(name); s != name {
+		if _, serr := CheckProjectName(s); serr == nil {
+			suggestion = s
+		}
+	}
+	return suggestion, err
+}
+
+// NormalizeProjectName turns name into kebab-case, as a valid project
+// name if possible: "My Worker", "myWorker" and "my_worker" all become
+// "my-worker". Of a path, only the last element is kept.
+func NormalizeProjectName(name string) string {
+	if i := strings.LastIndexAny(strings.TrimRight(name, `/\`), `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = camelBoundary.ReplaceAllString(name, "$1-$2")
+	name = strings.Trim(nonProjectChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if reservedNames[name] {
+		name += "-worker"
+	}
+	if len(name) > maxProjectNameLen {
+		name = strings.TrimRight(name[:maxProjectNameLen], "-")
+	}
+	return name
+}
@@ -0,0 +1,50 @@
+package create
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckProjectName(t *testing.T) {
+	tests := []struct {
+		name       string
+		valid      bool
+		suggestion string
+	}{
+		{"my-worker", true, ""},
+		{"w2", true, ""},
+		{"", false, ""},
+		{"My Worker", false, "my-worker"},
+		{"myWorker", false, "my-worker"},
+		{"my_worker", false, "my-worker"},
+		{"-worker-", false, "worker"},
+		{"apps/worker", false, "worker"},
+		{`apps\worker`, false, "worker"},
+		{"main", false, "main-worker"},
+		{"api", false, "api-worker"},
+		{strings.Repeat("a", 64), false, strings.Repeat("a", 63)},
+		{"___", false, ""},
+	}
+	for _, tt := range tests {
+		suggestion, err := CheckProjectName(tt.name)
+		if (err == nil) != tt.valid || suggestion != tt.suggestion {
+			t.Errorf("CheckProjectName(%q) = %q, %v; want suggestion %q, valid %v", tt.name, suggestion, err, tt.suggestion, tt.valid)
+		}
+	}
+}
+
+func TestNormalizeProjectName(t *testing.T) {
+	cases := map[string]string{
+		"OrderSync":        "order-sync",
+		"order sync  2":    "order-sync-2",
+		"Order.Sync":       "order-sync",
+		"./apps/Billing/":  "billing",
+		"test":             "test-worker",
+		"already-kebab-42": "already-kebab-42",
+	}
+	for in, want := range cases {
+		if got := NormalizeProjectName(in); got != want {
+			t.Errorf("NormalizeProjectName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package create
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	nodeTemplateRepo = "https://github.com/dibbla-agents/node-worker-starter-template.git"
+	nodeTemplateName = "node-worker-starter-template"
+)
+
+// NodeWorker creates a new Node.js worker project from the template
+func NodeWorker(config ProjectConfig) error {
+	if config.Resume {
+		return resumeProject(config, KindNodeWorker, func(c ProjectConfig, _ *TemplateLock) []setupStep { return nodeInstallSteps(c) })
+	}
+	stage, err := newStageDir()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stage)
+
+	// Step 1: Get the template (clone, download or cached copy)
+	commit, err := fetchTemplate(nodeTemplateRepo, config.Ref, stage, config.Offline)
+	if err != nil {
+		return err
+	}
+
+	// Step 2: Create .env file
+	fmt.Println("  Creating .env...")
+	if err := createEnvFile(config, stage, "env.example"); err != nil {
+		return fmt.Errorf("failed to create .env: %w", err)
+	}
+
+	// Step 3: Package name, frontend toggle and clean-up
+	fmt.Println("  Configuring package name...")
+	if !config.IncludeFrontend {
+		fmt.Println("  Removing frontend (not selected)...")
+	}
+	m, err := renderNodeWorker(stage, config.Name, config.IncludeFrontend)
+	if err != nil {
+		return err
+	}
+	if config.WithCompose {
+		if err := writeCompose(config, stage, "npm run dev"); err != nil {
+			return err
+		}
+	}
+
+	// Step 4: Record the template version
+	lock := &TemplateLock{
+		Template: KindNodeWorker, Repo: nodeTemplateRepo, Ref: config.Ref, Commit: commit,
+		Name: config.Name, Frontend: config.IncludeFrontend,
+		Features: lockedFeatures(m.FeatureValues(config.IncludeFrontend, nil)),
+	}
+	if err := WriteLock(stage, lock); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFile, err)
+	}
+	if config.DryRun {
+		return previewProject(os.Stdout, stage, config.ProjectDir())
+	}
+	p, err := moveIntoProject(stage, config.ProjectDir(), config.ConfirmOverwrite)
+	if err != nil {
+		return config.abandon(p, err, false)
+	}
+
+	// Steps 5 and 6: Install dependencies, initial commit
+	return finishProject(config, p, lock, nodeInstallSteps(config))
+}
+
+// nodeInstallSteps install the project's dependencies.
+func nodeInstallSteps(config ProjectConfig) []setupStep {
+	dir := config.ProjectDir()
+	var steps []setupStep
+	if config.IncludeFrontend {
+		steps = append(steps, frontendInstallStep(dir))
+	}
+	return append(steps, setupStep{
+		name: "npm install",
+		run: func(out io.Writer) error {
+			if err := npmInstall(dir, out); err != nil {
+				return fmt.Errorf("failed to run npm install: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+// renderNodeWorker makes the file changes NodeWorker applies to the
+// template in dir: frontend toggle and clean-up, as the starter's manifest
+// describes, then the package name, which npm wants lowercased. Returns
+// the manifest.
+func renderNodeWorker(dir, name string, includeFrontend bool) (*TemplateManifest, error) {
+	m, err := renderStarter(dir, nodeTemplateRepo, "node-worker", name, includeFrontend, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := replacePackageName(dir, name); err != nil {
+		return nil, fmt.Errorf("failed to set package name: %w", err)
+	}
+	return m, nil
+}
+
+// replacePackageName renames the template's package in package.json and
+// package-lock.json to the base name of project, lowercased as npm
+// requires.
+func replacePackageName(projectDir, project string) error {
+	name := strconv.Quote(strings.ToLower(filepath.Base(project)))
+	for _, file := range []string{"package.json", "package-lock.json"} {
+		path := filepath.Join(projectDir, file)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := replaceInFile(path, strconv.Quote(nodeTemplateName), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
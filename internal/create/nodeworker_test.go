@@ -0,0 +1,53 @@
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderNodeWorker(t *testing.T) {
+	for _, frontend := range []bool{true, false} {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"package.json":                           `{"name": "node-worker-starter-template", "version": "1.0.0"}` + "\n",
+			"package-lock.json":                      `{"name": "node-worker-starter-template", "packages": {"": {"name": "node-worker-starter-template"}}}` + "\n",
+			"frontend/package.json":                  `{"name": "frontend"}` + "\n",
+			"_optional/README.md":                    "notes\n",
+			"node-worker-starter-template/README.md": "nested copy\n",
+		})
+
+		if _, err := renderNodeWorker(dir, "acme/My-Worker", frontend); err != nil {
+			t.Fatalf("frontend=%v: %v", frontend, err)
+		}
+		if got, _ := os.ReadFile(filepath.Join(dir, "package.json")); string(got) != `{"name": "my-worker", "version": "1.0.0"}`+"\n" {
+			t.Errorf("frontend=%v: package.json = %q", frontend, got)
+		}
+		if got, _ := os.ReadFile(filepath.Join(dir, "package-lock.json")); string(got) != `{"name": "my-worker", "packages": {"": {"name": "my-worker"}}}`+"\n" {
+			t.Errorf("frontend=%v: package-lock.json = %q", frontend, got)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "frontend")); (err == nil) != frontend {
+			t.Errorf("frontend=%v: frontend/ exists = %v", frontend, err == nil)
+		}
+		for _, gone := range []string{"_optional", nodeTemplateName} {
+			if _, err := os.Stat(filepath.Join(dir, gone)); !os.IsNotExist(err) {
+				t.Errorf("frontend=%v: %s not removed", frontend, gone)
+			}
+		}
+	}
+}
+
+func TestReplacePackageName_NoLockFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"package.json": `{"name": "node-worker-starter-template"}`})
+
+	if err := replacePackageName(dir, "Worker"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "package.json")); string(got) != `{"name": "worker"}` {
+		t.Errorf("package.json = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "package-lock.json")); !os.IsNotExist(err) {
+		t.Error("package-lock.json was created")
+	}
+}
@@ -0,0 +1,144 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+)
+
+// A create that fails after the project's files are in place undoes the
+// move, unless ProjectConfig.KeepPartial; a kept project can be finished
+// with ProjectConfig.Resume once the problem (say, a failed go mod tidy)
+// is fixed.
+
+// PendingFile marks a project whose files are in place but whose create
+// hasn't finished installing dependencies yet.
+const PendingFile = ".dibbla/create-pending"
+
+// errInterrupted is what abandon reports for a create stopped with Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// PartialError is returned when creating a project failed after its files
+// were put in place.
+type PartialError struct {
+	Dir string
+	// Kept is set when the project was left as it was, Resumable when
+	// Resume can then finish it.
+	Kept, Resumable bool
+	Err             error
+}
+
+func (e *PartialError) Error() string { return e.Err.Error() }
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// abandon handles err, a failure after p (if set) put files in the
+// project: they are removed again unless c.KeepPartial.
+func (c ProjectConfig) abandon(p *placement, err error, resumable bool) error {
+	if p == nil {
+		return err
+	}
+	if c.KeepPartial || p.resumed {
+		p.done()
+		return &PartialError{Dir: p.dir, Kept: true, Resumable: resumable, Err: err}
+	}
+	if undoErr := p.undo(); undoErr != nil {
+		return fmt.Errorf("%w (undoing the changes to %s also failed: %v)", err, p.dir, undoErr)
+	}
+	return &PartialError{Dir: p.dir, Err: err}
+}
+
+// finishProject runs the steps after p put the project's files in place:
+// the install steps (unless SkipInstall) and git init (unless NoGit) at
+// the same time, then the initial commit. PendingFile marks the project
+// until the install steps succeed. A failure or Ctrl-C while the steps
+// run undoes the project, including what the steps created, unless it is
+// kept.
+func finishProject(config ProjectConfig, p *placement, lock *TemplateLock, install []setupStep) error {
+	pending := filepath.Join(p.dir, filepath.FromSlash(PendingFile))
+	if err := os.WriteFile(pending, []byte("dibbla create "+lock.Template+" didn't finish; run it again with --resume\n"), 0644); err != nil {
+		return config.abandon(p, err, false)
+	}
+	if !p.created && !p.resumed {
+		p.added = append(p.added, PendingFile)
+		before, err := listEntries(p.dir)
+		if err != nil {
+			return config.abandon(p, err, false)
+		}
+		p.before = before
+	}
+
+	var steps []setupStep
+	if !config.SkipInstall {
+		steps = append(steps, install...)
+	}
+	var repo bool
+	if !config.NoGit {
+		steps = append(steps, gitInitStep(p.dir, &repo))
+	}
+	_, err := os.Stat(filepath.Join(p.dir, ".git"))
+	hadRepo := err == nil
+	removeCleanup := interrupt.OnCleanup(func() {
+		// git init may not have finished, so repo can't be trusted here.
+		if !hadRepo && !config.NoGit {
+			os.RemoveAll(filepath.Join(p.dir, ".git"))
+		}
+		reportInterrupted(config.abandon(p, errInterrupted, true))
+	})
+	err = runSetupSteps(os.Stdout, steps, showStepProgress())
+	removeCleanup()
+	if err != nil {
+		// The repository is made again when the project is resumed.
+		if repo {
+			os.RemoveAll(filepath.Join(p.dir, ".git"))
+		}
+		return config.abandon(p, err, true)
+	}
+	if err := os.Remove(pending); err != nil {
+		return config.abandon(p, err, true)
+	}
+	p.done()
+
+	if !config.NoGit {
+		commitProject(p.dir, lock, repo)
+	}
+	return nil
+}
+
+// reportInterrupted tells, on stderr, what became of a project whose
+// create was interrupted; err is abandon's result.
+func reportInterrupted(err error) {
+	var partial *PartialError
+	switch {
+	case !errors.As(err, &partial):
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+	case partial.Kept:
+		fmt.Fprintf(os.Stderr, "\nKept %s. Run the same command with --resume to finish it.\n", partial.Dir)
+	default:
+		fmt.Fprintf(os.Stderr, "\nUndid the changes to %s.\n", partial.Dir)
+	}
+}
+
+// resumeProject finishes the project config names, kept by a create of
+// kind that failed (see PendingFile), running the steps install returns
+// for the settings recorded in its lock.
+func resumeProject(config ProjectConfig, kind string, install func(ProjectConfig, *TemplateLock) []setupStep) error {
+	dir := config.ProjectDir()
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(PendingFile))); os.IsNotExist(err) {
+		return fmt.Errorf("nothing to resume: %s has no unfinished dibbla create", dir)
+	}
+	lock, err := ReadLock(dir)
+	if err != nil {
+		return err
+	}
+	if lock.Template != kind {
+		return fmt.Errorf("%s was created with 'dibbla create %s'; resume it with that", dir, lock.Template)
+	}
+	config.Name = lock.Name
+	config.IncludeFrontend = lock.Frontend
+	fmt.Println("  Resuming...")
+	p := &placement{dir: dir, resumed: true}
+	return finishProject(config, p, lock, install(config, lock))
+}
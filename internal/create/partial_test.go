@@ -0,0 +1,112 @@
+package create
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFinishProject_Failure(t *testing.T) {
+	errInstall := errors.New("go mod tidy failed")
+	failing := []setupStep{{name: "go mod tidy", run: func(io.Writer) error { return errInstall }}}
+	lock := &TemplateLock{Template: KindGoWorker, Repo: "r", Name: "w"}
+
+	place := func(t *testing.T) (*placement, string) {
+		stage, dir := t.TempDir(), filepath.Join(t.TempDir(), "w")
+		writeFiles(t, stage, map[string]string{"main.go": "package main\n"})
+		if err := WriteLock(stage, lock); err != nil {
+			t.Fatal(err)
+		}
+		p, err := moveIntoProject(stage, dir, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p, dir
+	}
+
+	t.Run("rolled back", func(t *testing.T) {
+		p, dir := place(t)
+		err := finishProject(ProjectConfig{Name: "w", Dir: dir, NoGit: true}, p, lock, failing)
+		var partial *PartialError
+		if !errors.As(err, &partial) || partial.Kept || !errors.Is(err, errInstall) {
+			t.Fatalf("err = %#v", err)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s still exists: %v", dir, err)
+		}
+	})
+
+	t.Run("install artifacts in an existing directory", func(t *testing.T) {
+		stage, dir := t.TempDir(), t.TempDir()
+		writeFiles(t, stage, map[string]string{"go.mod": "module w\n"})
+		writeFiles(t, dir, map[string]string{"README.md": "mine\n", "docs/notes.md": "mine\n"})
+		if err := WriteLock(stage, lock); err != nil {
+			t.Fatal(err)
+		}
+		p, err := moveIntoProject(stage, dir, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		install := []setupStep{{name: "npm install", run: func(io.Writer) error {
+			writeFiles(t, dir, map[string]string{
+				"go.sum":                         "sum\n",
+				"node_modules/left-pad/index.js": "x\n",
+				"docs/generated.md":              "x\n",
+			})
+			if err := os.Symlink("left-pad/index.js", filepath.Join(dir, "node_modules", "pad")); err != nil {
+				t.Fatal(err)
+			}
+			return errInstall
+		}}}
+		if err := finishProject(ProjectConfig{Name: "w", Dir: dir, NoGit: true}, p, lock, install); !errors.Is(err, errInstall) {
+			t.Fatalf("err = %v", err)
+		}
+		got, err := listEntries(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[string]bool{"README.md": true, "docs/notes.md": true}; !reflect.DeepEqual(got, want) {
+			t.Errorf("left %v, want %v", got, want)
+		}
+		if _, err := os.Lstat(filepath.Join(dir, "node_modules")); !os.IsNotExist(err) {
+			t.Errorf("node_modules left: %v", err)
+		}
+	})
+
+	t.Run("kept and resumed", func(t *testing.T) {
+		p, dir := place(t)
+		config := ProjectConfig{Name: "w", Dir: dir, NoGit: true, KeepPartial: true}
+		err := finishProject(config, p, lock, failing)
+		var partial *PartialError
+		if !errors.As(err, &partial) || !partial.Kept || !partial.Resumable {
+			t.Fatalf("err = %#v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(PendingFile))); err != nil {
+			t.Fatalf("no %s: %v", PendingFile, err)
+		}
+
+		resume := ProjectConfig{Dir: dir, NoGit: true, Resume: true}
+		if err := resumeProject(resume, KindNodeWorker, nil); err == nil {
+			t.Error("want an error resuming with another template")
+		}
+		var installed bool
+		err = resumeProject(resume, KindGoWorker, func(c ProjectConfig, _ *TemplateLock) []setupStep {
+			return []setupStep{{name: "go mod tidy", run: func(io.Writer) error {
+				installed = c.ProjectDir() == dir
+				return nil
+			}}}
+		})
+		if err != nil || !installed {
+			t.Fatalf("resume: %v (installed %v)", err, installed)
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(PendingFile))); !os.IsNotExist(err) {
+			t.Errorf("%s left after resuming: %v", PendingFile, err)
+		}
+		if err := resumeProject(resume, KindGoWorker, nil); err == nil {
+			t.Error("want an error resuming a finished project")
+		}
+	})
+}
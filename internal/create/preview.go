@@ -0,0 +1,98 @@
+package create
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// previewFiles are shown in full by a dry run, when the project has them.
+var previewFiles = []string{"go.mod", "package.json", ".env"}
+
+// secretEnvKey matches .env keys whose values a preview masks.
+var secretEnvKey = regexp.MustCompile(`(?i)(TOKEN|SECRET|PASSWORD|API_KEY)`)
+
+// previewProject prints, for ProjectConfig.DryRun, the files of the
+// project rendered in stage as a tree, marking those that would overwrite
+// files already in dir, followed by the key files with .env secrets
+// masked.
+func previewProject(w io.Writer, stage, dir string) error {
+	files, err := listFiles(stage)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(w, "\nDry run: nothing was written. %s would contain:\n\n", dir)
+	fmt.Fprintf(w, "%s/\n", strings.TrimSuffix(dir, "/"))
+	printTree(w, paths, func(p string) string {
+		if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(p))); err == nil {
+			return "  (overwrites existing file)"
+		}
+		return ""
+	})
+
+	for _, name := range previewFiles {
+		content, err := os.ReadFile(filepath.Join(stage, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if name == ".env" {
+			content = []byte(maskEnv(string(content)))
+		}
+		fmt.Fprintf(w, "\n--- %s ---\n%s", name, content)
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
+
+// printTree prints the sorted slash-separated paths as an indented tree,
+// appending note(path) to each file.
+func printTree(w io.Writer, paths []string, note func(string) string) {
+	var prev []string
+	for _, p := range paths {
+		parts := strings.Split(p, "/")
+		dirs := parts[:len(parts)-1]
+		same := 0
+		for same < len(dirs) && same < len(prev) && dirs[same] == prev[same] {
+			same++
+		}
+		for i := same; i < len(dirs); i++ {
+			fmt.Fprintf(w, "%s%s/\n", strings.Repeat("  ", i+1), dirs[i])
+		}
+		fmt.Fprintf(w, "%s%s%s\n", strings.Repeat("  ", len(dirs)+1), path.Base(p), note(p))
+		prev = dirs
+	}
+}
+
+// maskEnv hides the values of secret-looking keys in env file content.
+// Long values keep their last four characters, so a token can be checked.
+func maskEnv(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(strings.TrimSpace(key), "#") || !secretEnvKey.MatchString(key) || value == "" {
+			continue
+		}
+		masked := "********"
+		if r := []rune(value); len(r) >= 16 {
+			masked += string(r[len(r)-4:])
+		}
+		lines[i] = key + "=" + masked
+	}
+	return strings.Join(lines, "\n")
+}
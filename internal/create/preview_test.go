@@ -0,0 +1,60 @@
+package create
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPreviewProject(t *testing.T) {
+	stage, dir := t.TempDir(), t.TempDir()
+	writeFiles(t, stage, map[string]string{
+		"go.mod":             "module w\n",
+		".env":               "SERVER_NAME=w\nSERVER_API_TOKEN=ak_1234567890abcdef\n",
+		"cmd/worker/main.go": "package main\n",
+		"internal/a/a.go":    "package a\n",
+		"internal/b/b.go":    "package b\n",
+	})
+	writeFiles(t, dir, map[string]string{"go.mod": "module old\n"})
+
+	var out bytes.Buffer
+	if err := previewProject(&out, stage, dir); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	tree := strings.Join([]string{
+		"  .env",
+		"  cmd/",
+		"    worker/",
+		"      main.go",
+		"  go.mod  (overwrites existing file)",
+		"  internal/",
+		"    a/",
+		"      a.go",
+		"    b/",
+		"      b.go",
+	}, "\n")
+	for _, want := range []string{tree, "--- go.mod ---\nmodule w\n", "SERVER_API_TOKEN=********cdef\n", "SERVER_NAME=w\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "ak_1234567890abcdef") {
+		t.Errorf("preview shows the token:\n%s", got)
+	}
+}
+
+func TestMaskEnv(t *testing.T) {
+	cases := map[string]string{
+		"SERVER_API_TOKEN=ak_short":          "SERVER_API_TOKEN=********",
+		"DB_PASSWORD=a-long-password-1234":   "DB_PASSWORD=********1234",
+		"SERVER_API_TOKEN=":                  "SERVER_API_TOKEN=",
+		"# SERVER_API_TOKEN=ak_commentedout": "# SERVER_API_TOKEN=ak_commentedout",
+		"SERVER_NAME=w":                      "SERVER_NAME=w",
+	}
+	for in, want := range cases {
+		if got := maskEnv(in); got != want {
+			t.Errorf("maskEnv(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
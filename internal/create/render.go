@@ -0,0 +1,199 @@
+package create
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// FrontendFeature is the feature name of a template's optional frontend,
+// chosen with --frontend/--no-frontend rather than --feature.
+const FrontendFeature = "frontend"
+
+// TemplateSuffix is dropped from the name of a rendered template file, so
+// a template can keep e.g. main.go.tmpl out of its own build.
+const TemplateSuffix = ".tmpl"
+
+// TemplateFeature is an optional part of a template, turned on or off when
+// generating. Its paths are removed when it is off, and rendered files can
+// test it with {{if .Features.<name>}}.
+type TemplateFeature struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Default     bool     `yaml:"default"`
+	Paths       []string `yaml:"paths"`
+	// Env are lines added to .env and the example env file when the
+	// feature is on, e.g. its settings with their defaults.
+	Env []string `yaml:"env"`
+}
+
+// featureNamePattern keeps feature names usable as .Features.<name>.
+var featureNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Feature returns the feature called name, or nil.
+func (m *TemplateManifest) Feature(name string) *TemplateFeature {
+	for i := range m.Features {
+		if m.Features[i].Name == name {
+			return &m.Features[i]
+		}
+	}
+	return nil
+}
+
+// FeatureValues says for every feature m declares whether it is on: the
+// frontend as includeFrontend, the others as in chosen or else their
+// default.
+func (m *TemplateManifest) FeatureValues(includeFrontend bool, chosen map[string]bool) map[string]bool {
+	values := make(map[string]bool, len(m.Features))
+	for _, f := range m.Features {
+		on, ok := chosen[f.Name]
+		switch {
+		case f.Name == FrontendFeature:
+			on = includeFrontend
+		case !ok:
+			on = f.Default
+		}
+		values[f.Name] = on
+	}
+	return values
+}
+
+// StepsFor returns the post_gen steps that run with features.
+func (m *TemplateManifest) StepsFor(features map[string]bool) []PostGenStep {
+	var steps []PostGenStep
+	for _, step := range m.PostGen {
+		if step.When == "" || features[step.When] {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// startersFS holds a manifest for each built-in starter, with the files
+// it renders, for starter versions from before they shipped their own.
+//
+//go:embed starters
+var startersFS embed.FS
+
+// renderStarter renders the built-in starter template of repo in dir with
+// its manifest and the features in chosen, the others at their defaults,
+// and returns the manifest. Without a manifest of its own, the files under
+// starters/<starter> (manifest included) are copied over it first.
+// Choosing a feature the manifest doesn't declare is an error.
+func renderStarter(dir, repo, starter, name string, includeFrontend bool, chosen map[string]bool) (*TemplateManifest, error) {
+	m, err := readTemplateManifest(repo, dir)
+	if errors.Is(err, errNoManifest) {
+		if err = copyStarterFiles(path.Join("starters", starter), dir); err != nil {
+			return nil, fmt.Errorf("failed to add the %s manifest: %w", starter, err)
+		}
+		m, err = readTemplateManifest(repo, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for feature, on := range chosen {
+		if on && m.Feature(feature) == nil {
+			return nil, fmt.Errorf("this version of the %s starter has no %s feature", starter, feature)
+		}
+	}
+	return m, renderFromTemplate(dir, name, m.FeatureValues(includeFrontend, chosen), m)
+}
+
+// copyStarterFiles copies the files under root in startersFS into dir, at
+// the same relative paths, replacing files already there.
+func copyStarterFiles(root, dir string) error {
+	return fs.WalkDir(startersFS, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := startersFS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(p, root+"/")))
+		if err := checkRegular(dest); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(dest, content, 0644)
+	})
+}
+
+// templateData is what rendered template files see.
+type templateData struct {
+	// Name is the project name; Module what the template's module path is
+	// replaced with (currently the same).
+	Name     string
+	Module   string
+	Features map[string]bool
+}
+
+// renderTemplateFiles executes the files in dir whose base name matches one
+// of patterns as text/template templates with data, dropping TemplateSuffix
+// from their names. A file that renders to nothing but white space is
+// removed, so a file can depend on several features. Using a feature the
+// manifest doesn't declare is an error.
+func renderTemplateFiles(dir string, patterns []string, data templateData) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || !matchesAny(info.Name(), patterns) {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(rel).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("rendering %s: %w", filepath.ToSlash(rel), err)
+		}
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, data); err != nil {
+			return fmt.Errorf("rendering %s: %w", filepath.ToSlash(rel), err)
+		}
+		target := strings.TrimSuffix(path, TemplateSuffix)
+		if len(bytes.TrimSpace(out.Bytes())) == 0 {
+			return os.Remove(path)
+		}
+		if err := checkRegular(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.WriteFile(target, out.Bytes(), info.Mode().Perm()); err != nil {
+			return err
+		}
+		if target != path {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
+
+// matchesAny reports whether name matches one of the filepath.Match
+// patterns.
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
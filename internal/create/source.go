@@ -0,0 +1,341 @@
+package create
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+)
+
+// Templates are cached as gzipped tarballs in ~/.dibbla/templates, so a
+// project can still be created without git or network access. Every archive
+// holds the files under a single top-level directory, as GitHub's do.
+
+// downloadTimeout bounds downloading a template archive.
+const downloadTimeout = 2 * time.Minute
+
+// archiveURL returns where to download repo (a GitHub HTTPS clone URL) as a
+// tarball of ref, or of its default branch if ref is empty. Overridable in
+// tests.
+var archiveURL = func(repo, ref string) string {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return strings.TrimSuffix(repo, ".git") + "/archive/" + ref + ".tar.gz"
+}
+
+// templateCacheDir resolves ~/.dibbla/templates.
+func templateCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home dir: %w", err)
+	}
+	return filepath.Join(home, ".dibbla", "templates"), nil
+}
+
+// cachedArchivePath is the cached tarball of repo at ref.
+func cachedArchivePath(repo, ref string) (string, error) {
+	dir, err := templateCacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSuffix(path.Base(repo), ".git")
+	if ref != "" {
+		name += "@" + strings.NewReplacer("/", "_", "\\", "_").Replace(ref)
+	}
+	return filepath.Join(dir, name+".tar.gz"), nil
+}
+
+// fetchTemplate puts the files of repo at ref (a branch, tag or commit;
+// empty for the default branch) into destDir, without .git, and returns
+// the commit when it is known. It clones with git when git is installed,
+// downloads the archive when it isn't, and with offline (or when both
+// fail) uses the copy cached by the last successful fetch.
+func fetchTemplate(repo, ref, destDir string, offline bool) (string, error) {
+	if err := checkTemplateSource(repo, ref); err != nil {
+		return "", err
+	}
+	cached, err := cachedArchivePath(repo, ref)
+	if err != nil {
+		return "", err
+	}
+	if !offline {
+		commit, err := cloneOrDownload(repo, ref, destDir, cached)
+		if err == nil {
+			return commit, nil
+		}
+		if _, statErr := os.Stat(cached); statErr != nil {
+			return "", err
+		}
+		os.RemoveAll(destDir)
+		fmt.Printf("  %s Warning: %v\n", platform.Icon("⚠️", "[!]"), err)
+	}
+
+	info, err := os.Stat(cached)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("no cached copy of %s; run create once with network access first", repo)
+	}
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("  Using cached template from %s...\n", info.ModTime().Format("2006-01-02"))
+	return "", extractTemplateArchive(cached, destDir)
+}
+
+// templateSchemes are the URL schemes a template repository may use.
+// GIT_ALLOW_PROTOCOL holds git to the same list, submodules included.
+var templateSchemes = []string{"https", "ssh", "file"}
+
+// checkTemplateSource rejects a repo or ref git could take for an option,
+// and a repo that isn't an https, ssh or file URL, an scp-style ssh
+// address (user@host:path) or a local path.
+func checkTemplateSource(repo, ref string) error {
+	if repo == "" || strings.HasPrefix(repo, "-") {
+		return fmt.Errorf("invalid template repository %q", repo)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid template ref %q", ref)
+	}
+	scheme := ""
+	if i := strings.Index(repo, "://"); i > 0 {
+		scheme = strings.ToLower(repo[:i])
+	} else if strings.Contains(repo, "::") {
+		// git's <transport>::<address> syntax runs a remote helper.
+		scheme = repo[:strings.Index(repo, "::")]
+	}
+	if scheme != "" && !slices.Contains(templateSchemes, scheme) {
+		return fmt.Errorf("template repository %s: only %s URLs are supported", repo, strings.Join(templateSchemes, ", "))
+	}
+	return nil
+}
+
+// cloneOrDownload fetches repo at ref into destDir and refreshes the cache
+// at cached. A cache that can't be written only gets a warning.
+func cloneOrDownload(repo, ref, destDir, cached string) (string, error) {
+	if _, err := exec.LookPath("git"); err == nil {
+		fmt.Println("  Cloning template...")
+		commit, err := cloneTemplate(repo, ref, destDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to clone template: %w", err)
+		}
+		if err := writeTemplateArchive(destDir, cached); err != nil {
+			fmt.Printf("  %s Warning: could not cache the template: %v\n", platform.Icon("⚠️", "[!]"), err)
+		}
+		return commit, nil
+	}
+
+	fmt.Println("  Downloading template (git not found)...")
+	if err := downloadTemplateArchive(archiveURL(repo, ref), cached); err != nil {
+		return "", fmt.Errorf("failed to download template: %w", err)
+	}
+	return "", extractTemplateArchive(cached, destDir)
+}
+
+// cloneTemplate clones repo at ref into destDir with git and removes .git,
+// returning the commit. Branches and tags get a shallow clone; anything
+// else (a commit) a full clone and a checkout.
+func cloneTemplate(repo, ref, destDir string) (string, error) {
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	if err := runGit("", append(args, "--", repo, destDir)...); err != nil {
+		if ref == "" {
+			return "", err
+		}
+		os.RemoveAll(destDir)
+		if err := runGit("", "clone", "--quiet", "--", repo, destDir); err != nil {
+			return "", err
+		}
+		if err := runGit(destDir, "checkout", "--quiet", ref, "--"); err != nil {
+			return "", fmt.Errorf("ref %s not found: %w", ref, err)
+		}
+	}
+	out, err := exec.Command("git", "-C", destDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("reading the template commit: %w", err)
+	}
+	if err := os.RemoveAll(filepath.Join(destDir, ".git")); err != nil {
+		return "", fmt.Errorf("failed to remove .git: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runGit runs git quietly in dir; its error output ends up in the error.
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_ALLOW_PROTOCOL="+strings.Join(templateSchemes, ":"))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+	}
+	return err
+}
+
+// downloadTemplateArchive saves the tarball at url to dest, replacing it
+// only once the download is complete.
+func downloadTemplateArchive(url, dest string) error {
+	client := &http.Client{Timeout: downloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("fetching %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), filepath.Base(dest)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("fetching %s: %w", url, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
+
+// writeTemplateArchive caches the files in dir as a tarball at dest, under
+// a "template/" top-level directory.
+func writeTemplateArchive(dir, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), filepath.Base(dest)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join("template", filepath.ToSlash(rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
+
+// extractTemplateArchive unpacks a cached template tarball into destDir,
+// dropping its top-level directory. Only directories and regular files
+// are extracted; an entry that would land outside destDir is an error.
+func extractTemplateArchive(archive, destDir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", archive, err)
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", archive, err)
+		}
+		// Drop the top-level directory.
+		_, rel, _ := strings.Cut(strings.TrimPrefix(hdr.Name, "./"), "/")
+		if rel == "" || strings.TrimSuffix(rel, "/") == "" {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return fmt.Errorf("reading %s: entry %q is outside the template", archive, hdr.Name)
+		}
+		target := filepath.Join(destDir, filepath.FromSlash(rel))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fs.FileMode(hdr.Mode).Perm()|0600)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
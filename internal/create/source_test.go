@@ -0,0 +1,134 @@
+package create
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tarball builds a gzipped tarball of files, each under a "repo-sha/" top
+// directory as in GitHub archives.
+func tarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: "repo-sha/" + name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestTemplateArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "cmd", "worker"), 0755)
+	os.WriteFile(filepath.Join(src, "go.mod"), []byte("module x\n"), 0644)
+	os.WriteFile(filepath.Join(src, "cmd", "worker", "main.go"), []byte("package main\n"), 0644)
+
+	archive := filepath.Join(t.TempDir(), "cache", "tpl.tar.gz")
+	if err := writeTemplateArchive(src, archive); err != nil {
+		t.Fatalf("writeTemplateArchive: %v", err)
+	}
+	dest := filepath.Join(t.TempDir(), "out")
+	if err := extractTemplateArchive(archive, dest); err != nil {
+		t.Fatalf("extractTemplateArchive: %v", err)
+	}
+	for name, want := range map[string]string{"go.mod": "module x\n", "cmd/worker/main.go": "package main\n"} {
+		got, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(name)))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+}
+
+func TestExtractTemplateArchive_RejectsEscapes(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "evil.tar.gz")
+	os.WriteFile(archive, tarball(t, map[string]string{"../../escape.txt": "x"}), 0644)
+	if err := extractTemplateArchive(archive, filepath.Join(t.TempDir(), "out")); err == nil || !strings.Contains(err.Error(), "outside the template") {
+		t.Fatalf("err = %v, want an outside-the-template error", err)
+	}
+}
+
+func TestFetchTemplate_Offline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	repo := "https://github.com/acme/worker-template.git"
+
+	_, err := fetchTemplate(repo, "", filepath.Join(t.TempDir(), "a"), true)
+	if err == nil || !strings.Contains(err.Error(), "no cached copy") {
+		t.Fatalf("without a cache: err = %v", err)
+	}
+
+	cached, err := cachedArchivePath(repo, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Dir(cached), 0755)
+	os.WriteFile(cached, tarball(t, map[string]string{"go.mod": "module cached\n"}), 0644)
+	dest := filepath.Join(t.TempDir(), "b")
+	if _, err := fetchTemplate(repo, "", dest, true); err != nil {
+		t.Fatalf("with a cache: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dest, "go.mod")); string(got) != "module cached\n" {
+		t.Errorf("go.mod = %q", got)
+	}
+}
+
+func TestFetchTemplate_DownloadsWithoutGit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	t.Setenv("PATH", t.TempDir()) // no git
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(tarball(t, map[string]string{"go.mod": "module downloaded\n"}))
+	}))
+	defer srv.Close()
+	old := archiveURL
+	archiveURL = func(string, string) string { return srv.URL }
+	defer func() { archiveURL = old }()
+
+	repo := "https://github.com/acme/worker-template.git"
+	dest := filepath.Join(t.TempDir(), "out")
+	if _, err := fetchTemplate(repo, "", dest, false); err != nil {
+		t.Fatalf("fetchTemplate: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dest, "go.mod")); string(got) != "module downloaded\n" {
+		t.Errorf("go.mod = %q", got)
+	}
+	cached, _ := cachedArchivePath(repo, "")
+	if _, err := os.Stat(cached); err != nil {
+		t.Errorf("archive not cached: %v", err)
+	}
+}
+
+func TestCheckTemplateSource(t *testing.T) {
+	for _, tc := range []struct {
+		repo, ref string
+		ok        bool
+	}{
+		{"https://github.com/acme/t.git", "v1", true},
+		{"ssh://git@github.com/acme/t.git", "", true},
+		{"git@github.com:acme/t.git", "", true},
+		{"file:///srv/templates/t", "", true},
+		{"/srv/templates/t", "main", true},
+		{"http://example.com/t.git", "", false},
+		{"git://example.com/t.git", "", false},
+		{"ext::sh -c touch% /tmp/pwned", "", false},
+		{"--upload-pack=touch /tmp/pwned", "", false},
+		{"https://github.com/acme/t.git", "--output=/tmp/x", false},
+	} {
+		if err := checkTemplateSource(tc.repo, tc.ref); (err == nil) != tc.ok {
+			t.Errorf("checkTemplateSource(%q, %q) = %v, want ok %v", tc.repo, tc.ref, err, tc.ok)
+		}
+	}
+}
@@ -0,0 +1 @@
+-- init: revert what the .up.sql file does
@@ -0,0 +1,192 @@
+package create
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"github.com/mattn/go-isatty"
+)
+
+// failedOutputLines is how much of a failed step's output is shown.
+const failedOutputLines = 20
+
+// A setupStep is one of the independent steps finishProject runs at the
+// same time once a project's files are in place, such as npm install in
+// frontend/ next to go mod tidy.
+type setupStep struct {
+	name string
+	// optional steps only warn when they fail: the project works without
+	// them, and hint says how to finish them by hand.
+	optional bool
+	hint     string
+	// run does the step, writing the output of the commands it runs to out.
+	run func(out io.Writer) error
+}
+
+// skippedError is returned by a step that had nothing to do.
+type skippedError struct{ reason string }
+
+func (e *skippedError) Error() string { return e.reason }
+
+// stepResult is how a setupStep went.
+type stepResult struct {
+	finished bool
+	err      error
+	took     time.Duration
+	output   bytes.Buffer
+}
+
+// showStepProgress reports whether runSetupSteps can redraw its status
+// lines in place.
+func showStepProgress() bool {
+	return isatty.IsTerminal(os.Stdout.Fd()) && !platform.IsCI()
+}
+
+// runSetupSteps runs steps concurrently and returns the error of the
+// first required one that failed. Their output is captured and only shown
+// for failed steps; w gets a status line per step instead, redrawn in
+// place with live, followed by the warnings of optional steps.
+func runSetupSteps(w io.Writer, steps []setupStep, live bool) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	results := make([]*stepResult, len(steps))
+	for i := range results {
+		results[i] = &stepResult{}
+	}
+
+	var mu sync.Mutex
+	frame := 0
+	draw := func() {
+		for i, s := range steps {
+			fmt.Fprintf(w, "\r\033[K  %s\n", statusLine(s, results[i], frame))
+		}
+	}
+	stopTicker := func() {}
+	if live {
+		draw()
+		done, finished := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(finished)
+			ticker := time.NewTicker(120 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					mu.Lock()
+					frame++
+					fmt.Fprintf(w, "\033[%dA", len(steps))
+					draw()
+					mu.Unlock()
+				}
+			}
+		}()
+		var once sync.Once
+		var remove func()
+		stopTicker = func() {
+			once.Do(func() {
+				close(done)
+				<-finished
+				remove()
+			})
+		}
+		remove = interrupt.OnCleanup(stopTicker)
+	} else {
+		for _, s := range steps {
+			fmt.Fprintf(w, "  Running %s...\n", s.name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, s := range steps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := s.run(&results[i].output)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[i].finished, results[i].err, results[i].took = true, err, time.Since(start)
+			if live {
+				fmt.Fprintf(w, "\033[%dA", len(steps))
+				draw()
+			} else {
+				fmt.Fprintf(w, "  %s\n", statusLine(s, results[i], 0))
+			}
+		}()
+	}
+	wg.Wait()
+	stopTicker()
+
+	var failed error
+	for i, s := range steps {
+		r := results[i]
+		var skipped *skippedError
+		if r.err == nil || errors.As(r.err, &skipped) {
+			continue
+		}
+		// The caller reports the error of a required step.
+		if s.optional {
+			fmt.Fprintf(w, "  %s Warning: %s failed: %v\n", platform.Icon("⚠️", "[!]"), s.name, r.err)
+		} else if r.output.Len() > 0 {
+			fmt.Fprintf(w, "  Output of %s:\n", s.name)
+		}
+		printOutputTail(w, r.output.String())
+		if s.optional && s.hint != "" {
+			fmt.Fprintf(w, "     %s\n", s.hint)
+		}
+		if !s.optional && failed == nil {
+			failed = r.err
+		}
+	}
+	return failed
+}
+
+// statusLine describes how step is doing, animating a running one with
+// frame.
+func statusLine(step setupStep, r *stepResult, frame int) string {
+	var skipped *skippedError
+	switch {
+	case !r.finished:
+		frames := []string{"|", "/", "-", "\\"}
+		if platform.SupportsUnicode() {
+			frames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+		}
+		return fmt.Sprintf("%s %s...", frames[frame%len(frames)], step.name)
+	case errors.As(r.err, &skipped):
+		return fmt.Sprintf("%s %s skipped (%s)", platform.Icon("⏭️", "[-]"), step.name, skipped.reason)
+	case r.err == nil:
+		return fmt.Sprintf("%s %s (%s)", platform.Icon("✅", "[OK]"), step.name, r.took.Round(100*time.Millisecond))
+	case step.optional:
+		return fmt.Sprintf("%s %s failed", platform.Icon("⚠️", "[!]"), step.name)
+	default:
+		return fmt.Sprintf("%s %s failed", platform.Icon("❌", "[X]"), step.name)
+	}
+}
+
+// printOutputTail prints the last lines of a failed step's output,
+// indented under its message.
+func printOutputTail(w io.Writer, output string) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return
+	}
+	if len(lines) > failedOutputLines {
+		fmt.Fprintf(w, "     ... (%d lines before)\n", len(lines)-failedOutputLines)
+		lines = lines[len(lines)-failedOutputLines:]
+	}
+	for _, line := range lines {
+		fmt.Fprintf(w, "     %s\n", line)
+	}
+}
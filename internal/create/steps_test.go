@@ -0,0 +1,89 @@
+package create
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunSetupSteps(t *testing.T) {
+	// Each of the first two steps waits for the other to start, so they
+	// only finish when run at the same time.
+	started := [2]chan struct{}{make(chan struct{}), make(chan struct{})}
+	wait := func(i int) func(io.Writer) error {
+		return func(out io.Writer) error {
+			close(started[i])
+			select {
+			case <-started[1-i]:
+			case <-time.After(5 * time.Second):
+				return errors.New("steps didn't run concurrently")
+			}
+			fmt.Fprintln(out, "installed")
+			return nil
+		}
+	}
+	errTidy := errors.New("go mod tidy failed")
+	steps := []setupStep{
+		{name: "a", run: wait(0)},
+		{name: "b", run: wait(1)},
+		{name: "npm install (frontend)", optional: true, hint: "Run it manually.", run: func(out io.Writer) error {
+			fmt.Fprintln(out, "npm ERR! network")
+			return errors.New("exit status 1")
+		}},
+		{name: "git init", optional: true, run: func(io.Writer) error { return &skippedError{"git not found"} }},
+		{name: "go mod tidy", run: func(out io.Writer) error {
+			fmt.Fprintln(out, "go: module not found")
+			return errTidy
+		}},
+	}
+
+	var out bytes.Buffer
+	if err := runSetupSteps(&out, steps, false); err != errTidy {
+		t.Fatalf("err = %v, want %v", err, errTidy)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"  Running a...\n",
+		"git init skipped (git not found)\n",
+		"Warning: npm install (frontend) failed: exit status 1\n     npm ERR! network\n     Run it manually.\n",
+		"Output of go mod tidy:\n     go: module not found\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "installed") {
+		t.Errorf("output of successful steps shown:\n%s", got)
+	}
+
+	out.Reset()
+	if err := runSetupSteps(&out, steps[2:4], true); err != nil {
+		t.Errorf("optional steps failing: err = %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "\033[2A") || !strings.Contains(got, "git init skipped") {
+		t.Errorf("live output = %q", got)
+	}
+}
+
+func TestPrintOutputTail(t *testing.T) {
+	var lines []string
+	for i := 1; i <= failedOutputLines+5; i++ {
+		lines = append(lines, fmt.Sprint("line ", i))
+	}
+	var out bytes.Buffer
+	printOutputTail(&out, strings.Join(lines, "\n")+"\n")
+	got := out.String()
+	if !strings.HasPrefix(got, "     ... (5 lines before)\n     line 6\n") || !strings.HasSuffix(got, fmt.Sprintf("line %d\n", failedOutputLines+5)) {
+		t.Errorf("tail = %q", got)
+	}
+
+	out.Reset()
+	printOutputTail(&out, "")
+	if out.Len() != 0 {
+		t.Errorf("empty output printed %q", out.String())
+	}
+}
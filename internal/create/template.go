@@ -0,0 +1,431 @@
+package create
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/platform"
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateManifestFile is the manifest a repository needs to be used with
+// `dibbla create from-template`. It is removed from the generated project.
+const TemplateManifestFile = "dibbla-template.yaml"
+
+// TemplateManifest describes how to turn a cloned template repository into
+// a project:
+//
+//	name: acme-worker
+//	module: github.com/acme/worker-template
+//	files: [go.mod, "*.go"]
+//	render: ["*.tmpl"]
+//	env: env.example
+//	frontend: [frontend, internal/frontend]
+//	features:
+//	  - name: metrics
+//	    description: Prometheus metrics endpoint
+//	    default: true
+//	    paths: [internal/metrics]
+//	    env: ["METRICS_ADDR=:9100"]
+//	remove: [docs]
+//	post_gen:
+//	  - run: go mod tidy
+//	  - run: npm install
+//	    dir: frontend
+//	    when: frontend
+//	start: go run ./cmd/worker
+//
+// Rendered files are text/template templates, e.g.
+//
+//	{{if .Features.metrics}}
+//		go metrics.Serve(":9100")
+//	{{end}}
+type TemplateManifest struct {
+	// Name is shown while generating; defaults to the repository name.
+	Name string `yaml:"name"`
+	// Module is replaced with the project name in the files whose base
+	// name matches one of the Files patterns.
+	Module string   `yaml:"module"`
+	Files  []string `yaml:"files"`
+	// Render lists file name patterns of files rendered with
+	// text/template (see templateData), after the paths of features that
+	// are off are removed and before Module is replaced.
+	Render []string `yaml:"render"`
+	// Env is the example env file .env is generated from (default
+	// env.example).
+	Env string `yaml:"env"`
+	// Frontend lists the paths removed when the frontend is not included,
+	// short for a "frontend" feature with those paths. Without either
+	// there is no frontend choice.
+	Frontend []string `yaml:"frontend"`
+	// Features are the template's optional parts.
+	Features []TemplateFeature `yaml:"features"`
+	// Remove lists paths that are always removed.
+	Remove []string `yaml:"remove"`
+	// PostGen are commands run in order once the files are in place.
+	PostGen []PostGenStep `yaml:"post_gen"`
+	// Start is how to run the generated project, shown at the end.
+	Start string `yaml:"start"`
+
+	// origin is where CloneTemplate got the template, for the lock.
+	origin TemplateLock
+	// dir is where CloneTemplate put it.
+	dir string
+}
+
+// PostGenStep is a command run after generating a project, without a
+// shell, in Dir (relative to the project root).
+type PostGenStep struct {
+	Run string `yaml:"run"`
+	Dir string `yaml:"dir"`
+	// When names a feature the step only runs with.
+	When string `yaml:"when"`
+	// Frontend is short for when: frontend.
+	Frontend bool `yaml:"frontend"`
+}
+
+// HasFrontend reports whether the template has an optional frontend.
+func (m *TemplateManifest) HasFrontend() bool {
+	return m.Feature(FrontendFeature) != nil
+}
+
+// ParseTemplateManifest reads and checks a template manifest. Every path
+// in it must stay inside the project.
+func ParseTemplateManifest(data []byte) (*TemplateManifest, error) {
+	var m TemplateManifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", TemplateManifestFile, err)
+	}
+	if m.Module != "" && len(m.Files) == 0 {
+		return nil, fmt.Errorf("invalid %s: module needs files, the file name patterns to replace it in", TemplateManifestFile)
+	}
+	for _, pattern := range append(m.Files, m.Render...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s: bad file name pattern %q", TemplateManifestFile, pattern)
+		}
+	}
+	if m.Env == "" {
+		m.Env = "env.example"
+	}
+
+	if len(m.Frontend) > 0 {
+		if m.Feature(FrontendFeature) != nil {
+			return nil, fmt.Errorf("invalid %s: frontend is declared both as a list of paths and as a feature", TemplateManifestFile)
+		}
+		m.Features = append([]TemplateFeature{{Name: FrontendFeature, Description: "Starter frontend", Paths: m.Frontend}}, m.Features...)
+	}
+	seen := map[string]bool{}
+	paths := []string{m.Env}
+	for _, f := range m.Features {
+		if !featureNamePattern.MatchString(f.Name) {
+			return nil, fmt.Errorf("invalid %s: feature name %q must be lowercase letters, digits and underscores", TemplateManifestFile, f.Name)
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("invalid %s: feature %s is declared twice", TemplateManifestFile, f.Name)
+		}
+		seen[f.Name] = true
+		paths = append(paths, f.Paths...)
+	}
+	paths = append(paths, m.Remove...)
+	for i, step := range m.PostGen {
+		if strings.TrimSpace(step.Run) == "" {
+			return nil, fmt.Errorf("invalid %s: a post_gen step has no run command", TemplateManifestFile)
+		}
+		if step.Frontend {
+			if step.When != "" && step.When != FrontendFeature {
+				return nil, fmt.Errorf("invalid %s: post_gen step %q has both frontend and when", TemplateManifestFile, step.Run)
+			}
+			m.PostGen[i].When = FrontendFeature
+		}
+		if w := m.PostGen[i].When; w != "" && !seen[w] {
+			return nil, fmt.Errorf("invalid %s: post_gen step %q needs feature %s, which isn't declared", TemplateManifestFile, step.Run, w)
+		}
+		if step.Dir != "" {
+			paths = append(paths, step.Dir)
+		}
+	}
+	for _, p := range paths {
+		if !filepath.IsLocal(filepath.FromSlash(p)) {
+			return nil, fmt.Errorf("invalid %s: path %q is outside the project", TemplateManifestFile, p)
+		}
+	}
+	return &m, nil
+}
+
+// CloneTemplate clones ref (a branch, tag or commit; empty for the default
+// branch) of repo into a temporary directory, drops its git history and
+// reads its manifest. FromTemplate removes the directory.
+func CloneTemplate(repo, ref string) (m *TemplateManifest, err error) {
+	stage, err := newStageDir()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(stage)
+		}
+	}()
+
+	fmt.Println("  Cloning template...")
+	commit, err := cloneTemplate(repo, ref, stage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone template: %w", err)
+	}
+	m, err = readTemplateManifest(repo, stage)
+	if err != nil {
+		return nil, err
+	}
+	m.origin = TemplateLock{Template: KindFromTemplate, Repo: repo, Ref: ref, Commit: commit}
+	m.dir = stage
+	return m, nil
+}
+
+// CloneLockedTemplate clones the template version the project in dir was
+// created from with FromTemplate, as its LockFile records, to finish the
+// project with ProjectConfig.Resume: the ref it was created from may have
+// moved since. repo must be the template the lock names.
+func CloneLockedTemplate(dir, repo string) (*TemplateManifest, error) {
+	lock, err := ReadLock(dir)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case lock.Template != KindFromTemplate:
+		return nil, fmt.Errorf("%s was created with 'dibbla create %s'; resume it with that", dir, lock.Template)
+	case lock.Repo != repo:
+		return nil, fmt.Errorf("%s was created from the template %s, not %s", dir, lock.Repo, repo)
+	case lock.Commit == "":
+		return nil, fmt.Errorf("%s records no template commit, so the version this project was created from is unknown", LockFile)
+	}
+	return CloneTemplate(lock.Repo, lock.Commit)
+}
+
+// errNoManifest is wrapped by readTemplateManifest's error for a template
+// without a manifest.
+var errNoManifest = errors.New("no template manifest")
+
+// readTemplateManifest reads the manifest of repo, checked out in dir.
+func readTemplateManifest(repo, dir string) (*TemplateManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, TemplateManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s has no %s, so it can't be used as a template: %w", repo, TemplateManifestFile, errNoManifest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m, err := ParseTemplateManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	if m.Name == "" {
+		m.Name = strings.TrimSuffix(filepath.Base(repo), ".git")
+	}
+	return m, nil
+}
+
+// FromTemplate turns a template cloned with CloneTemplate into a project,
+// as GoWorker does for the Go starter: .env, module path, frontend toggle,
+// clean-up, then the manifest's post_gen steps (skipped with SkipInstall)
+// and the initial commit.
+func FromTemplate(config ProjectConfig, m *TemplateManifest) error {
+	if m.dir == "" {
+		// Without a checkout every step would run in the working directory.
+		return errors.New("the template hasn't been cloned; use CloneTemplate")
+	}
+	defer os.RemoveAll(m.dir)
+	if config.Resume {
+		return resumeProject(config, KindFromTemplate, func(c ProjectConfig, lock *TemplateLock) []setupStep {
+			return postGenSetupSteps(c.ProjectDir(), m.StepsFor(m.FeatureValues(lock.Frontend, lock.Features)))
+		})
+	}
+
+	// Step 1: Create .env file
+	fmt.Println("  Creating .env...")
+	if err := createEnvFile(config, m.dir, m.Env); err != nil {
+		return fmt.Errorf("failed to create .env: %w", err)
+	}
+
+	// Step 2: Module path, frontend toggle and clean-up
+	if m.Module != "" {
+		fmt.Println("  Configuring module path...")
+	}
+	if m.HasFrontend() && !config.IncludeFrontend {
+		fmt.Println("  Removing frontend (not selected)...")
+	}
+	if len(m.Render) > 0 {
+		fmt.Println("  Rendering template files...")
+	}
+	fmt.Println("  Cleaning up...")
+	features := m.FeatureValues(config.IncludeFrontend, config.Features)
+	if err := renderFromTemplate(m.dir, config.Name, features, m); err != nil {
+		return err
+	}
+	if config.WithCompose {
+		if err := writeCompose(config, m.dir, m.Start); err != nil {
+			return err
+		}
+	}
+
+	// Step 3: Record the template version
+	lock := m.origin
+	lock.Name = config.Name
+	lock.Frontend = config.IncludeFrontend
+	lock.Features = lockedFeatures(features)
+	if err := WriteLock(m.dir, &lock); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFile, err)
+	}
+	if config.DryRun {
+		return previewProject(os.Stdout, m.dir, config.ProjectDir())
+	}
+	p, err := moveIntoProject(m.dir, config.ProjectDir(), config.ConfirmOverwrite)
+	if err != nil {
+		return config.abandon(p, err, false)
+	}
+
+	// Steps 4 and 5: Post-generation steps, initial commit
+	return finishProject(config, p, &lock, postGenSetupSteps(config.ProjectDir(), m.StepsFor(features)))
+}
+
+// postGenSetupSteps is the setupStep running steps in the project in dir.
+// They may depend on each other, so it runs them in order, stopping at
+// the first that fails.
+func postGenSetupSteps(dir string, steps []PostGenStep) []setupStep {
+	if len(steps) == 0 {
+		return nil
+	}
+	name := steps[0].String()
+	if len(steps) > 1 {
+		name = fmt.Sprintf("%d post-generation steps", len(steps))
+	}
+	return []setupStep{{
+		name: name,
+		run: func(out io.Writer) error {
+			for _, step := range steps {
+				if err := runPostGenStep(dir, step, out); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}}
+}
+
+// renderFromTemplate makes the file changes m asks for in dir with
+// features (from FeatureValues): removing the paths of features that are
+// off and the manifest itself, rendering template files, replacing the
+// module path and adding the env lines of features that are on. `create
+// upgrade` replays it on each template version.
+func renderFromTemplate(dir, name string, features map[string]bool, m *TemplateManifest) error {
+	remove := append([]string{TemplateManifestFile}, m.Remove...)
+	var env []string
+	for _, f := range m.Features {
+		if !features[f.Name] {
+			remove = append(remove, f.Paths...)
+		} else if len(f.Env) > 0 {
+			env = append(env, f.Env...)
+		}
+	}
+	for _, p := range remove {
+		if err := os.RemoveAll(filepath.Join(dir, filepath.FromSlash(p))); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", p, err)
+		}
+	}
+	if len(m.Render) > 0 {
+		data := templateData{Name: name, Module: name, Features: features}
+		if err := renderTemplateFiles(dir, m.Render, data); err != nil {
+			return err
+		}
+	}
+	if m.Module != "" {
+		if err := replaceInMatchingFiles(dir, m.Files, m.Module, name); err != nil {
+			return fmt.Errorf("failed to replace module path: %w", err)
+		}
+	}
+	if len(env) > 0 {
+		for _, file := range []string{".env", m.Env} {
+			if err := appendLines(filepath.Join(dir, filepath.FromSlash(file)), env); err != nil {
+				return fmt.Errorf("updating %s: %w", file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// lockedFeatures is what the lock records of features: all but the
+// frontend, which has its own field.
+func lockedFeatures(features map[string]bool) map[string]bool {
+	locked := map[string]bool{}
+	for name, on := range features {
+		if name != FrontendFeature {
+			locked[name] = on
+		}
+	}
+	if len(locked) == 0 {
+		return nil
+	}
+	return locked
+}
+
+func replaceInMatchingFiles(projectDir string, patterns []string, oldStr, newStr string) error {
+	return filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() && matchesAny(info.Name(), patterns) {
+			return replaceInFile(path, oldStr, newStr)
+		}
+		return nil
+	})
+}
+
+// String is the step as a shell command run from the project root.
+func (s PostGenStep) String() string {
+	if s.Dir == "" {
+		return s.Run
+	}
+	return "(cd " + s.Dir + " && " + s.Run + ")"
+}
+
+func runPostGenStep(projectDir string, step PostGenStep, out io.Writer) error {
+	fmt.Fprintf(out, "$ %s\n", step)
+	fields := strings.Fields(step.Run)
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Dir = filepath.Join(projectDir, filepath.FromSlash(step.Dir))
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("post-generation step %q failed: %w", step.Run, err)
+	}
+	return nil
+}
+
+// PrintPostGenSteps lists the commands FromTemplate will run, so they can
+// be checked before going ahead.
+func PrintPostGenSteps(m *TemplateManifest) {
+	if len(m.PostGen) == 0 {
+		return
+	}
+	fmt.Printf("%s This template runs commands after generating the project:\n", platform.Icon("⚠️", "[!]"))
+	for _, step := range m.PostGen {
+		line := "   " + step.String()
+		if step.When != "" {
+			line += " (with " + step.When + ")"
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+}
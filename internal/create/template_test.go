@@ -0,0 +1,246 @@
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTemplateManifest(t *testing.T) {
+	m, err := ParseTemplateManifest([]byte(`
+module: github.com/acme/tpl
+files: [go.mod, "*.go"]
+frontend: [web]
+features:
+  - name: metrics
+    default: true
+    paths: [metrics]
+post_gen:
+  - run: go mod tidy
+  - run: npm install
+    dir: web
+    frontend: true
+  - run: go run ./tools/metrics-setup
+    when: metrics
+start: go run .
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Env != "env.example" || !m.HasFrontend() || len(m.PostGen) != 3 || m.PostGen[1].String() != "(cd web && npm install)" {
+		t.Errorf("manifest = %+v", m)
+	}
+	if f := m.Feature(FrontendFeature); f == nil || f.Paths[0] != "web" || m.PostGen[1].When != FrontendFeature {
+		t.Errorf("frontend not turned into a feature: %+v", m.Features)
+	}
+	values := m.FeatureValues(false, nil)
+	if values["frontend"] || !values["metrics"] {
+		t.Errorf("FeatureValues(false, nil) = %v", values)
+	}
+	if steps := m.StepsFor(m.FeatureValues(false, map[string]bool{"metrics": false})); len(steps) != 1 {
+		t.Errorf("StepsFor without features = %v", steps)
+	}
+
+	for name, data := range map[string]string{
+		"unknown field":      "modul: x\n",
+		"module no files":    "module: github.com/acme/tpl\n",
+		"bad pattern":        "module: m\nfiles: [\"[\"]\n",
+		"path outside":       "remove: [../x]\n",
+		"absolute path":      "frontend: [/etc]\n",
+		"step dir outside":   "post_gen:\n  - run: ls\n    dir: ../..\n",
+		"step without a run": "post_gen:\n  - dir: web\n",
+		"bad feature name":   "features:\n  - name: Metrics\n",
+		"feature twice":      "features:\n  - name: a\n  - name: a\n",
+		"frontend twice":     "frontend: [web]\nfeatures:\n  - name: frontend\n",
+		"feature path":       "features:\n  - name: a\n    paths: [../a]\n",
+		"step unknown when":  "post_gen:\n  - run: ls\n    when: metrics\n",
+		"bad render pattern": "render: [\"[\"]\n",
+	} {
+		if _, err := ParseTemplateManifest([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFromTemplate(t *testing.T) {
+	src := t.TempDir()
+	dir := filepath.Join(t.TempDir(), "my-worker")
+	files := map[string]string{
+		TemplateManifestFile: "module: github.com/acme/tpl\nfiles: [go.mod, \"*.go\"]\nenv: config/env.example\nfrontend: [web]\nremove: [docs]\n",
+		"go.mod":             "module github.com/acme/tpl\n",
+		"main.go":            "package main\n\nimport _ \"github.com/acme/tpl/internal\"\n",
+		"README.md":          "github.com/acme/tpl\n",
+		"config/env.example": "SERVER_NAME=my-worker\nSERVER_API_TOKEN=your_api_token_here\n",
+		"web/index.html":     "<html></html>\n",
+		"docs/notes.md":      "notes\n",
+	}
+	writeFiles(t, src, files)
+	m, err := ParseTemplateManifest([]byte(files[TemplateManifestFile]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.dir = src
+
+	if err := FromTemplate(ProjectConfig{Name: dir, Token: "ak_123", NoGit: true}, m); err != nil {
+		t.Fatalf("FromTemplate: %v", err)
+	}
+
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return string(data)
+	}
+	if got := read("go.mod"); got != "module "+dir+"\n" {
+		t.Errorf("go.mod = %q", got)
+	}
+	if !strings.Contains(read("main.go"), dir+"/internal") {
+		t.Errorf("main.go not rewritten:\n%s", read("main.go"))
+	}
+	if read("README.md") != files["README.md"] {
+		t.Errorf("README.md should be left alone, got %q", read("README.md"))
+	}
+	if !strings.Contains(read(".env"), "SERVER_API_TOKEN=ak_123") {
+		t.Errorf(".env:\n%s", read(".env"))
+	}
+	for _, gone := range []string{TemplateManifestFile, "web", "docs"} {
+		if _, err := os.Stat(filepath.Join(dir, gone)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed", gone)
+		}
+	}
+}
+
+func TestRenderFromTemplate_Features(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		TemplateManifestFile: "",
+		"go.mod":             "module github.com/acme/tpl\n",
+		"main.go.tmpl": `package main
+
+import "github.com/acme/tpl/internal/app"
+
+func main() {
+{{- if .Features.metrics}}
+	go app.ServeMetrics()
+{{- end}}
+{{- if .Features.frontend}}
+	go app.ServeFrontend()
+{{- end}}
+	app.Run("{{.Name}}")
+}
+`,
+		"metrics/metrics.go": "package metrics\n",
+		"tracing/tracing.go": "package tracing\n",
+		"web/index.html":     "<html></html>\n",
+		"README.md":          "{{ not rendered }}\n",
+	})
+	m, err := ParseTemplateManifest([]byte(`
+module: github.com/acme/tpl
+files: [go.mod, "*.go"]
+render: ["*.go.tmpl"]
+frontend: [web]
+features:
+  - name: metrics
+    default: true
+    paths: [metrics]
+  - name: tracing
+    paths: [tracing]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	features := m.FeatureValues(false, map[string]bool{"tracing": true})
+	if err := renderFromTemplate(dir, "example.com/w", features, m); err != nil {
+		t.Fatal(err)
+	}
+	want := `package main
+
+import "example.com/w/internal/app"
+
+func main() {
+	go app.ServeMetrics()
+	app.Run("example.com/w")
+}
+`
+	if data, _ := os.ReadFile(filepath.Join(dir, "main.go")); string(data) != want {
+		t.Errorf("main.go =\n%s\nwant\n%s", data, want)
+	}
+	for _, gone := range []string{"main.go.tmpl", "web", TemplateManifestFile} {
+		if _, err := os.Stat(filepath.Join(dir, gone)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed", gone)
+		}
+	}
+	for _, kept := range []string{"metrics/metrics.go", "tracing/tracing.go", "README.md"} {
+		if _, err := os.Stat(filepath.Join(dir, kept)); err != nil {
+			t.Errorf("%s: %v", kept, err)
+		}
+	}
+
+	// A feature the manifest doesn't declare is an error, not silently off.
+	writeFiles(t, dir, map[string]string{"x.go.tmpl": "{{if .Features.queue}}x{{end}}"})
+	if err := renderTemplateFiles(dir, m.Render, templateData{Features: features}); err == nil || !strings.Contains(err.Error(), "x.go.tmpl") {
+		t.Errorf("undeclared feature: err = %v", err)
+	}
+}
+
+func TestTemplateSymlinksStayUntouched(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "outside.go")
+	writeFiles(t, filepath.Dir(outside), map[string]string{"outside.go": "package github.com/acme/tpl\n"})
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"main.go": "package main // github.com/acme/tpl\n"})
+	for _, link := range []string{"link.go", "env.example", ".env"} {
+		if err := os.Symlink(outside, filepath.Join(dir, link)); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+
+	if err := replaceInMatchingFiles(dir, []string{"*.go"}, "github.com/acme/tpl", "example.com/w"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "main.go")); !strings.Contains(string(data), "example.com/w") {
+		t.Errorf("main.go = %q", data)
+	}
+	if err := replaceInFile(filepath.Join(dir, "link.go"), "github.com/acme/tpl", "example.com/w"); err == nil {
+		t.Error("replaceInFile followed a symlink")
+	}
+	if err := createEnvFile(ProjectConfig{Name: "w"}, dir, "env.example"); err == nil {
+		t.Error("createEnvFile followed a symlink")
+	}
+	if data, _ := os.ReadFile(outside); string(data) != "package github.com/acme/tpl\n" {
+		t.Errorf("the symlink target was rewritten: %q", data)
+	}
+}
+
+func TestFromTemplate_NotCloned(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := FromTemplate(ProjectConfig{Name: filepath.Join(t.TempDir(), "w"), NoGit: true}, &TemplateManifest{}); err == nil {
+		t.Fatal("expected an error for a manifest without a checkout")
+	}
+	if _, err := os.Stat(filepath.Join(wd, ".env")); !os.IsNotExist(err) {
+		t.Error("FromTemplate wrote .env into the working directory")
+	}
+}
+
+func TestCloneLockedTemplate_Refusals(t *testing.T) {
+	const repo = "https://example.com/t.git"
+	cases := []struct {
+		name string
+		lock TemplateLock
+		want string
+	}{
+		{"another generator", TemplateLock{Template: KindGoWorker, Repo: repo, Name: "w", Commit: "abc"}, "dibbla create go-worker"},
+		{"another template", TemplateLock{Template: KindFromTemplate, Repo: "https://example.com/other.git", Name: "w", Commit: "abc"}, "other.git"},
+		{"no commit", TemplateLock{Template: KindFromTemplate, Repo: repo, Name: "w", Ref: "main"}, "no template commit"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := WriteLock(dir, &tc.lock); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := CloneLockedTemplate(dir, repo); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
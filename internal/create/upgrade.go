@@ -0,0 +1,306 @@
+package create
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Upgrade actions, as reported in UpgradeChange.Action.
+const (
+	UpgradeAdd      = "add"
+	UpgradeUpdate   = "update"
+	UpgradeDelete   = "delete"
+	UpgradeConflict = "conflict"
+)
+
+// ConflictSuffix is appended to a file's path to hold the template's new
+// version when the file was also changed in the project.
+const ConflictSuffix = ".template-new"
+
+// UpgradeChange is one file `create upgrade` changes in the project.
+type UpgradeChange struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+}
+
+// Upgrade compares the template version a project was generated from with
+// a newer one. Both versions are rendered the way the project was (name,
+// features) and compared file by file with the project:
+//   - changed only in the template: the project gets the new version
+//   - changed in both, differently: a conflict; the new version is written
+//     next to the file with ConflictSuffix
+//
+// Files the project generates or installs itself (.env, go.sum,
+// package-lock.json, node_modules) are not compared. Adding a component to
+// a project (AddFrontend) is planned the same way, at the same version.
+type Upgrade struct {
+	From    *TemplateLock
+	To      *TemplateLock
+	Changes []UpgradeChange
+
+	projectDir string
+	tmp        string // holds "old" and "new", the two rendered versions
+	// manifest is the new version's manifest.
+	manifest *TemplateManifest
+	// env are lines Apply adds to the project's .env, which isn't
+	// compared: the settings of a feature being turned on.
+	env []string
+}
+
+// upgradeSkipped reports whether path (slash-separated, relative) is left
+// out of upgrades.
+func upgradeSkipped(p string) bool {
+	switch path.Base(p) {
+	case ".env", "go.sum", "package-lock.json":
+		return true
+	}
+	return strings.HasPrefix(p, ".dibbla/") || strings.HasPrefix(p, "node_modules/") || strings.Contains(p, "/node_modules/")
+}
+
+// PlanUpgrade works out how to bring the project in projectDir up to ref
+// (empty for the template's default branch) of the template recorded in
+// its LockFile. Nothing in the project changes until Apply. Call Close
+// when done.
+func PlanUpgrade(projectDir, ref string) (*Upgrade, error) {
+	from, err := ReadLock(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	to := *from
+	to.Ref = ref
+	return planTemplateChange(projectDir, from, to, ref)
+}
+
+// planTemplateChange plans bringing the project in projectDir, generated
+// as from records, to how to would generate it at ref. A lock without a
+// commit (the template was downloaded or taken from the cache) is
+// refused: its ref may have moved since, so the version the project was
+// generated from can't be fetched again.
+func planTemplateChange(projectDir string, from *TemplateLock, to TemplateLock, ref string) (*Upgrade, error) {
+	if from.Commit == "" {
+		return nil, fmt.Errorf("%s records no template commit, so the version this project was generated from is unknown; compare it with the template by hand", LockFile)
+	}
+	tmp, err := os.MkdirTemp("", "dibbla-upgrade-*")
+	if err != nil {
+		return nil, err
+	}
+	u := &Upgrade{From: from, projectDir: projectDir, tmp: tmp}
+
+	if _, _, err := renderVersion(from, from.pinnedRef(), filepath.Join(tmp, "old")); err != nil {
+		u.Close()
+		return nil, fmt.Errorf("template version %s: %w", from.Version(), err)
+	}
+	to.Commit, u.manifest, err = renderVersion(&to, ref, filepath.Join(tmp, "new"))
+	if err != nil {
+		u.Close()
+		return nil, fmt.Errorf("template version %s: %w", to.Version(), err)
+	}
+	u.To = &to
+
+	if err := u.plan(); err != nil {
+		u.Close()
+		return nil, err
+	}
+	return u, nil
+}
+
+// renderVersion fetches lock's template at ref into dir and renders it as
+// the project was generated. Returns the commit, when known, and the
+// template's manifest.
+func renderVersion(lock *TemplateLock, ref, dir string) (string, *TemplateManifest, error) {
+	commit, err := fetchTemplate(lock.Repo, ref, dir, false)
+	if err != nil {
+		return "", nil, err
+	}
+	var m *TemplateManifest
+	switch lock.Template {
+	case KindGoWorker:
+		m, err = renderGoWorker(dir, lock.Name, lock.Frontend, lock.Features)
+	case KindNodeWorker:
+		m, err = renderNodeWorker(dir, lock.Name, lock.Frontend)
+	default:
+		if m, err = readTemplateManifest(lock.Repo, dir); err == nil {
+			err = renderFromTemplate(dir, lock.Name, m.FeatureValues(lock.Frontend, lock.Features), m)
+		}
+	}
+	return commit, m, err
+}
+
+func (u *Upgrade) plan() error {
+	oldDir, newDir := filepath.Join(u.tmp, "old"), filepath.Join(u.tmp, "new")
+	oldFiles, err := listFiles(oldDir)
+	if err != nil {
+		return err
+	}
+	newFiles, err := listFiles(newDir)
+	if err != nil {
+		return err
+	}
+	paths := map[string]bool{}
+	for p := range oldFiles {
+		paths[p] = true
+	}
+	for p := range newFiles {
+		paths[p] = true
+	}
+
+	for p := range paths {
+		if upgradeSkipped(p) {
+			continue
+		}
+		base, err := readIfExists(filepath.Join(oldDir, filepath.FromSlash(p)))
+		if err != nil {
+			return err
+		}
+		theirs, err := readIfExists(filepath.Join(newDir, filepath.FromSlash(p)))
+		if err != nil {
+			return err
+		}
+		ours, err := readIfExists(filepath.Join(u.projectDir, filepath.FromSlash(p)))
+		if err != nil {
+			return err
+		}
+		var action string
+		switch {
+		case sameContent(base, theirs), sameContent(ours, theirs):
+			continue
+		case !sameContent(ours, base):
+			action = UpgradeConflict
+		case theirs == nil:
+			action = UpgradeDelete
+		case ours == nil:
+			action = UpgradeAdd
+		default:
+			action = UpgradeUpdate
+		}
+		u.Changes = append(u.Changes, UpgradeChange{Path: p, Action: action})
+	}
+	sort.Slice(u.Changes, func(i, j int) bool { return u.Changes[i].Path < u.Changes[j].Path })
+	return nil
+}
+
+// Conflicts returns how many changes are conflicts.
+func (u *Upgrade) Conflicts() int {
+	n := 0
+	for _, c := range u.Changes {
+		if c.Action == UpgradeConflict {
+			n++
+		}
+	}
+	return n
+}
+
+// Apply makes the planned changes in the project, adds the settings of
+// features turned on to its .env and records the new template version in
+// its LockFile.
+func (u *Upgrade) Apply() error {
+	newDir := filepath.Join(u.tmp, "new")
+	for _, c := range u.Changes {
+		src := filepath.Join(newDir, filepath.FromSlash(c.Path))
+		dst := filepath.Join(u.projectDir, filepath.FromSlash(c.Path))
+		var err error
+		switch c.Action {
+		case UpgradeAdd, UpgradeUpdate:
+			err = copyFile(src, dst)
+		case UpgradeDelete:
+			err = os.Remove(dst)
+		case UpgradeConflict:
+			if _, statErr := os.Stat(src); statErr == nil {
+				err = copyFile(src, dst+ConflictSuffix)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.Path, err)
+		}
+	}
+	if len(u.env) > 0 {
+		if err := appendLines(filepath.Join(u.projectDir, ".env"), u.env); err != nil {
+			return fmt.Errorf(".env: %w", err)
+		}
+	}
+	return WriteLock(u.projectDir, u.To)
+}
+
+// WritePatch writes the template's own changes between the two versions
+// to w as a unified diff, using git.
+func (u *Upgrade) WritePatch(w io.Writer) error {
+	cmd := exec.Command("git", "diff", "--no-index", "--no-color", "--", "old", "new")
+	cmd.Dir = u.tmp
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	// git diff exits 1 when there are differences.
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return nil
+	}
+	return err
+}
+
+// Close removes the rendered template versions.
+func (u *Upgrade) Close() {
+	os.RemoveAll(u.tmp)
+}
+
+// listFiles returns the regular files under dir as slash-separated
+// relative paths.
+func listFiles(dir string) (map[string]bool, error) {
+	files := map[string]bool{}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	return files, err
+}
+
+// readIfExists returns the content of path, or nil if it doesn't exist.
+func readIfExists(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if data == nil && err == nil {
+		data = []byte{}
+	}
+	return data, err
+}
+
+// sameContent compares two files read with readIfExists; nil (missing)
+// only equals nil.
+func sameContent(a, b []byte) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return bytes.Equal(a, b)
+}
+
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, info.Mode().Perm())
+}
@@ -0,0 +1,140 @@
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLockRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	lock := &TemplateLock{Template: KindGoWorker, Repo: "https://example.com/t.git", Ref: "v1.2.0", Commit: "0123456789abcdef", Name: "github.com/acme/w", Frontend: true}
+	if err := WriteLock(dir, lock); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, lock) {
+		t.Errorf("ReadLock = %+v, want %+v", got, lock)
+	}
+	if got.Version() != "v1.2.0 (0123456)" {
+		t.Errorf("Version = %q", got.Version())
+	}
+
+	if _, err := ReadLock(t.TempDir()); err == nil {
+		t.Error("expected an error without a lock file")
+	}
+}
+
+func TestPlanUpgrade_NoCommit(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteLock(dir, &TemplateLock{Template: KindGoWorker, Repo: "https://example.com/t.git", Ref: "main", Name: "w"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PlanUpgrade(dir, ""); err == nil || !strings.Contains(err.Error(), "no template commit") {
+		t.Fatalf("PlanUpgrade: err = %v, want a no-commit error", err)
+	}
+	if _, err := AddFrontend(dir); err == nil || !strings.Contains(err.Error(), "no template commit") {
+		t.Fatalf("AddFrontend: err = %v, want a no-commit error", err)
+	}
+	if _, err := AddFeature(dir, ExtraDB); err == nil || !strings.Contains(err.Error(), "no template commit") {
+		t.Fatalf("AddFeature: err = %v, want a no-commit error", err)
+	}
+}
+
+func TestUpgradePlanAndApply(t *testing.T) {
+	tmp, project := t.TempDir(), t.TempDir()
+	writeFiles(t, filepath.Join(tmp, "old"), map[string]string{
+		"same.go":      "same",
+		"updated.go":   "v1",
+		"edited.go":    "v1",
+		"removed.go":   "gone",
+		"kept.go":      "v1",
+		"go.sum":       "v1",
+		"both-same.go": "v1",
+	})
+	writeFiles(t, filepath.Join(tmp, "new"), map[string]string{
+		"same.go":      "same",
+		"updated.go":   "v2",
+		"edited.go":    "v2",
+		"added.go":     "new",
+		"kept.go":      "v2",
+		"go.sum":       "v2",
+		"both-same.go": "v2",
+	})
+	writeFiles(t, project, map[string]string{
+		"same.go":      "local change",
+		"updated.go":   "v1",
+		"edited.go":    "local change",
+		"removed.go":   "gone",
+		"go.sum":       "local",
+		"both-same.go": "v2",
+		"mine.go":      "mine",
+	})
+	// kept.go was deleted in the project and changed in the template.
+
+	u := &Upgrade{
+		From:       &TemplateLock{Template: KindGoWorker, Repo: "r", Ref: "v1", Name: "m"},
+		To:         &TemplateLock{Template: KindGoWorker, Repo: "r", Ref: "v2", Name: "m"},
+		projectDir: project,
+		tmp:        tmp,
+	}
+	if err := u.plan(); err != nil {
+		t.Fatal(err)
+	}
+	want := []UpgradeChange{
+		{"added.go", UpgradeAdd},
+		{"edited.go", UpgradeConflict},
+		{"kept.go", UpgradeConflict},
+		{"removed.go", UpgradeDelete},
+		{"updated.go", UpgradeUpdate},
+	}
+	if !reflect.DeepEqual(u.Changes, want) {
+		t.Fatalf("Changes = %v, want %v", u.Changes, want)
+	}
+	if u.Conflicts() != 2 {
+		t.Errorf("Conflicts = %d", u.Conflicts())
+	}
+
+	if err := u.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"added.go":                   "new",
+		"updated.go":                 "v2",
+		"edited.go":                  "local change",
+		"edited.go" + ConflictSuffix: "v2",
+		"kept.go" + ConflictSuffix:   "v2",
+		"same.go":                    "local change",
+		"go.sum":                     "local",
+		"mine.go":                    "mine",
+	} {
+		data, err := os.ReadFile(filepath.Join(project, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(project, "removed.go")); !os.IsNotExist(err) {
+		t.Errorf("removed.go still exists: %v", err)
+	}
+	if lock, err := ReadLock(project); err != nil || lock.Ref != "v2" {
+		t.Errorf("lock after apply = %+v, %v", lock, err)
+	}
+}
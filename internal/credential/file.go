@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 
@@ -19,9 +20,12 @@ import (
 // behavior of `--write-env`.
 
 const (
-	fileTokenKey  = "DIBBLA_API_TOKEN"
-	fileAPIURLKey = "DIBBLA_API_URL"
-	credFileName  = "credentials.env"
+	fileTokenKey        = "DIBBLA_API_TOKEN"
+	fileAPIURLKey       = "DIBBLA_API_URL"
+	fileExpiresAtKey    = "DIBBLA_TOKEN_EXPIRES_AT"
+	fileRefreshTokenKey = "DIBBLA_REFRESH_TOKEN"
+	fileIssuedByKey     = "DIBBLA_TOKEN_ISSUED_BY"
+	credFileName        = "credentials.env"
 )
 
 // tokenFilePath resolves the credentials file path. Overridable in
@@ -68,27 +72,70 @@ func SetTokenFile(token, apiURL string) error {
 	return nil
 }
 
+// UpdateTokenFile replaces the token in the user-level credentials file
+// and records info about it, keeping the stored API URL.
+func UpdateTokenFile(token string, info TokenInfo) error {
+	path := tokenFilePath()
+	if path == "" {
+		return errors.New("could not resolve user config directory for credentials file")
+	}
+	expiresAt := ""
+	if info.ExpiresAt != nil {
+		expiresAt = info.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	_, err := env.MergeEnvFile(path, map[string]string{
+		fileTokenKey:        token,
+		fileExpiresAtKey:    expiresAt,
+		fileRefreshTokenKey: info.RefreshToken,
+		fileIssuedByKey:     info.IssuedBy,
+	})
+	return err
+}
+
 // GetTokenFile reads token and apiURL from the user-level credentials
 // file. Returns ("", "", nil) if the file doesn't exist — callers
 // should treat this as "no stored credentials" rather than an error.
 func GetTokenFile() (token, apiURL string, err error) {
+	vars, err := readTokenFile()
+	return vars[fileTokenKey], vars[fileAPIURLKey], err
+}
+
+// GetTokenFileInfo reads what UpdateTokenFile recorded about the token
+// in the user-level credentials file.
+func GetTokenFileInfo() (TokenInfo, error) {
+	var info TokenInfo
+	vars, err := readTokenFile()
+	if err != nil {
+		return info, err
+	}
+	if v := vars[fileExpiresAtKey]; v != "" {
+		at, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return info, fmt.Errorf("%s: invalid %s: %w", tokenFilePath(), fileExpiresAtKey, err)
+		}
+		info.ExpiresAt = &at
+	}
+	info.RefreshToken = vars[fileRefreshTokenKey]
+	info.IssuedBy = vars[fileIssuedByKey]
+	return info, nil
+}
+
+// readTokenFile parses the user-level credentials file; nil if it
+// doesn't exist.
+func readTokenFile() (map[string]string, error) {
 	path := tokenFilePath()
 	if path == "" {
-		return "", "", nil
+		return nil, nil
 	}
-	f, ferr := os.Open(path)
-	if ferr != nil {
-		if os.IsNotExist(ferr) {
-			return "", "", nil
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
 		}
-		return "", "", ferr
+		return nil, err
 	}
 	defer f.Close()
-	vars, perr := godotenv.Parse(f)
-	if perr != nil {
-		return "", "", perr
-	}
-	return vars[fileTokenKey], vars[fileAPIURLKey], nil
+	return godotenv.Parse(f)
 }
 
 // DeleteTokenFile removes the user-level credentials file. No-op if it
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 )
 
 // withTempCredFile redirects tokenFilePath to a fresh temp dir for the
@@ -210,3 +211,30 @@ func TestGetTokenFile_ToleratesCommentsAndQuotes(t *testing.T) {
 		t.Errorf("apiURL = %q, want %q", apiURL, "https://api.example.com")
 	}
 }
+
+func TestUpdateTokenFile_KeepsURLAndRecordsInfo(t *testing.T) {
+	withTempCredFile(t)
+	if err := SetTokenFile("ak_old", "https://api.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	expires := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+	if err := UpdateTokenFile("ak_new", TokenInfo{ExpiresAt: &expires, RefreshToken: "rt_1", IssuedBy: "https://api.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, apiURL, err := GetTokenFile()
+	if err != nil || token != "ak_new" || apiURL != "https://api.example.com" {
+		t.Errorf("GetTokenFile() = %q, %q, %v", token, apiURL, err)
+	}
+	info, err := GetTokenFileInfo()
+	if err != nil || info.ExpiresAt == nil || !info.ExpiresAt.Equal(expires) || info.RefreshToken != "rt_1" || info.IssuedBy != "https://api.example.com" {
+		t.Errorf("GetTokenFileInfo() = %+v, %v", info, err)
+	}
+
+	if err := UpdateTokenFile("ak_api_key", TokenInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := GetTokenFileInfo(); err != nil || info != (TokenInfo{}) {
+		t.Errorf("after clearing: %+v, %v", info, err)
+	}
+}
@@ -1,8 +1,10 @@
 package credential
 
 import (
+	"encoding/json"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/zalando/go-keyring"
 )
@@ -11,8 +13,21 @@ const (
 	serviceName = "dibbla-cli"
 	keyToken    = "api_token"
 	keyAPIURL   = "api_url"
+	keyInfo     = "token_info"
 )
 
+// TokenInfo is what login learned about a stored token besides its value.
+// Both fields are optional.
+type TokenInfo struct {
+	// ExpiresAt is nil when the token doesn't expire or it isn't known.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// RefreshToken gets a new token before this one expires.
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// IssuedBy is the API URL that issued the token, the only one its
+	// refresh token is sent to.
+	IssuedBy string `json:"issued_by,omitempty"`
+}
+
 func get(key string) (string, error) {
 	val, err := keyring.Get(serviceName, key)
 	if err != nil {
@@ -78,3 +93,37 @@ func DeleteAPIURL() error {
 	}
 	return err
 }
+
+// GetTokenInfo returns what is known about the token in the OS credential
+// store; the zero TokenInfo when nothing was recorded.
+func GetTokenInfo() (TokenInfo, error) {
+	var info TokenInfo
+	val, err := get(keyInfo)
+	if err != nil || val == "" {
+		return info, err
+	}
+	err = json.Unmarshal([]byte(val), &info)
+	return info, err
+}
+
+// SetTokenInfo records info about the token in the OS credential store,
+// removing the record when info is empty.
+func SetTokenInfo(info TokenInfo) error {
+	if info == (TokenInfo{}) {
+		return DeleteTokenInfo()
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return keyring.Set(serviceName, keyInfo, string(data))
+}
+
+// DeleteTokenInfo removes the record written by SetTokenInfo.
+func DeleteTokenInfo() error {
+	err := keyring.Delete(serviceName, keyInfo)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
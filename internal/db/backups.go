@@ -0,0 +1,183 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// Backup is one retained snapshot of a database.
+type Backup struct {
+	ID        string     `json:"id"`
+	Database  string     `json:"database,omitempty"`
+	Kind      string     `json:"kind,omitempty"` // scheduled or manual
+	Status    string     `json:"status"`         // pending, completed or failed
+	SizeBytes int64      `json:"size_bytes,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// BackupSchedule is a database's automatic backup setting. Time is the
+// daily start time, HH:MM in UTC.
+type BackupSchedule struct {
+	Enabled       bool   `json:"enabled"`
+	Time          string `json:"time,omitempty"`
+	RetentionDays int    `json:"retention_days,omitempty"`
+}
+
+// BackupsListResponse is the response for listing a database's backups,
+// newest first.
+type BackupsListResponse struct {
+	Database string          `json:"database"`
+	Backups  []Backup        `json:"backups"`
+	Schedule *BackupSchedule `json:"schedule,omitempty"`
+}
+
+// BackupScheduleUpdate changes a backup schedule; nil fields are left as
+// they are.
+type BackupScheduleUpdate struct {
+	Enabled       *bool   `json:"enabled,omitempty"`
+	Time          *string `json:"time,omitempty"`
+	RetentionDays *int    `json:"retention_days,omitempty"`
+}
+
+var backupTimeRe = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// Validate checks the update locally before it is sent.
+func (u BackupScheduleUpdate) Validate() error {
+	if u.Time != nil && !backupTimeRe.MatchString(*u.Time) {
+		return fmt.Errorf("invalid time %q (expected HH:MM, 24-hour UTC)", *u.Time)
+	}
+	if u.RetentionDays != nil && *u.RetentionDays < 1 {
+		return fmt.Errorf("retention must be at least 1 day")
+	}
+	return nil
+}
+
+// ListBackups returns the retained backups of a database and its schedule.
+func ListBackups(ctx context.Context, apiURL, apiToken, name string) (*BackupsListResponse, error) {
+	var out BackupsListResponse
+	if err := backupsRequest(ctx, apiURL, apiToken, "GET", "/api/deploy/databases/"+name+"/backups", nil, &out, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBackup starts a manual backup of a database. The returned backup
+// is usually still pending.
+func CreateBackup(ctx context.Context, apiURL, apiToken, name string) (*Backup, error) {
+	var out Backup
+	if err := backupsRequest(ctx, apiURL, apiToken, "POST", "/api/deploy/databases/"+name+"/backups", nil, &out, http.StatusCreated, http.StatusAccepted); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// WaitBackup polls the backups of database name every interval until
+// backup id has completed or failed, and returns it as last listed. A
+// status it doesn't know, or still waiting after limit, is an error.
+func WaitBackup(ctx context.Context, apiURL, apiToken, name, id string, interval, limit time.Duration) (*Backup, error) {
+	deadline := time.Now().Add(limit)
+	for {
+		list, err := ListBackups(ctx, apiURL, apiToken, name)
+		if err != nil {
+			return nil, err
+		}
+		var found *Backup
+		for i := range list.Backups {
+			if list.Backups[i].ID == id {
+				found = &list.Backups[i]
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("backup %s of '%s' not found", id, name)
+		}
+		switch found.Status {
+		case "completed", "failed":
+			return found, nil
+		case "pending":
+		default:
+			return nil, fmt.Errorf("backup %s has unknown status %q", id, found.Status)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("backup %s still pending after %s", id, limit)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// RestoreBackup starts restoring a database from one of its backups, as a
+// restore job; follow it with WaitRestoreJob.
+func RestoreBackup(ctx context.Context, apiURL, apiToken, name, backupID string) (*DatabaseRestoreResponse, error) {
+	var out DatabaseRestoreResponse
+	path := "/api/deploy/databases/" + name + "/backups/" + url.PathEscape(backupID) + "/restore"
+	if err := backupsRequest(ctx, apiURL, apiToken, "POST", path, nil, &out, http.StatusOK, http.StatusAccepted); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateBackupSchedule changes the automatic backup schedule of a database
+// and returns the new schedule.
+func UpdateBackupSchedule(ctx context.Context, apiURL, apiToken, name string, update BackupScheduleUpdate) (*BackupSchedule, error) {
+	var out BackupSchedule
+	if err := backupsRequest(ctx, apiURL, apiToken, "PUT", "/api/deploy/databases/"+name+"/backups/schedule", update, &out, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// backupsRequest sends a JSON request (body nil for none) and decodes the
+// response into out when the status is one of ok.
+func backupsRequest(ctx context.Context, apiURL, apiToken, method, path string, body, out any, ok ...int) error {
+	client := &http.Client{Timeout: requestTimeout}
+	var reqBody io.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, makeAPIURL(apiURL, path), reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	accepted := false
+	for _, s := range ok {
+		accepted = accepted || resp.StatusCode == s
+	}
+	if !accepted {
+		return parseError(respBody, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
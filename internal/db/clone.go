@@ -0,0 +1,70 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// cloneTimeout bounds a server-side copy; the data never passes through
+// the client, but large databases still take a while.
+const cloneTimeout = 30 * time.Minute
+
+// CloneRequest is the body of a database clone.
+type CloneRequest struct {
+	Name            string `json:"name"`
+	DeploymentAlias string `json:"deployment_alias,omitempty"`
+}
+
+// CloneResponse is the response for cloning a database.
+type CloneResponse struct {
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	Database   string `json:"database"`
+	Source     string `json:"source"`
+	Engine     string `json:"engine,omitempty"`
+	SecretName string `json:"secret_name,omitempty"`
+	SizeBytes  int64  `json:"size_bytes,omitempty"`
+}
+
+// CloneDatabase copies database source, schema and data, into a new
+// managed database on the server. req.DeploymentAlias scopes the new
+// database and its secret as for CreateDatabase.
+func CloneDatabase(ctx context.Context, apiURL, apiToken, source string, req CloneRequest) (*CloneResponse, error) {
+	client := &http.Client{Timeout: cloneTimeout}
+	payload, _ := json.Marshal(req)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", makeAPIURL(apiURL, "/api/deploy/databases/"+source+"/clone"), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+apiToken)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, parseError(body, resp.StatusCode)
+	}
+
+	var out CloneResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &out, nil
+}
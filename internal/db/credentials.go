@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Credential is a login to one database that only a single app uses, so
+// the app doesn't connect with its owner's API token.
+type Credential struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateCredential mints a login to database name for app. Calling it
+// again for the same app replaces the password.
+func CreateCredential(ctx context.Context, apiURL, apiToken, name, app string) (*Credential, error) {
+	var out Credential
+	body := map[string]string{"app": app}
+	if err := backupsRequest(ctx, apiURL, apiToken, "POST", "/api/deploy/databases/"+name+"/credentials", body, &out, http.StatusOK, http.StatusCreated); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeCredential deletes the login CreateCredential minted for app.
+func RevokeCredential(ctx context.Context, apiURL, apiToken, name, app string) error {
+	var out map[string]any
+	return backupsRequest(ctx, apiURL, apiToken, "DELETE", "/api/deploy/databases/"+name+"/credentials/"+url.PathEscape(app), nil, &out, http.StatusOK)
+}
@@ -2,37 +2,92 @@ package db
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
 )
 
 const requestTimeout = 60 * time.Second
 
 // DatabasesListResponse is the response for listing databases.
 type DatabasesListResponse struct {
-	Databases []string `json:"databases"`
-	Total     int      `json:"total"`
+	Databases []Database `json:"databases"`
+	Total     int        `json:"total"`
+}
+
+// Has reports whether the list holds a database called name.
+func (l *DatabasesListResponse) Has(name string) bool {
+	_, ok := l.Get(name)
+	return ok
+}
+
+// Get returns the database called name.
+func (l *DatabasesListResponse) Get(name string) (Database, bool) {
+	for _, d := range l.Databases {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Database{}, false
+}
+
+// Database is one managed database in DatabasesListResponse.
+type Database struct {
+	Name          string     `json:"name"`
+	Engine        string     `json:"engine,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	EngineVersion string     `json:"engine_version,omitempty"`
+	SizeBytes     int64      `json:"size_bytes,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+}
+
+// UnmarshalJSON also accepts a bare name, which is how older API servers
+// list databases.
+func (d *Database) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*d = Database{Name: name}
+		return nil
+	}
+	type plain Database
+	return json.Unmarshal(b, (*plain)(d))
 }
 
 // DatabaseCreateResponse is the response for creating a database.
+// EngineVersion, Tier and Region describe the instance as created,
+// server defaults included.
 type DatabaseCreateResponse struct {
-	Status     string `json:"status"`
-	Message    string `json:"message"`
-	Database   string `json:"database"`
-	SecretName string `json:"secret_name,omitempty"`
+	Status        string `json:"status"`
+	Message       string `json:"message"`
+	Database      string `json:"database"`
+	Engine        string `json:"engine,omitempty"`
+	EngineVersion string `json:"engine_version,omitempty"`
+	Tier          string `json:"tier,omitempty"`
+	Region        string `json:"region,omitempty"`
+	SecretName    string `json:"secret_name,omitempty"`
 }
 
-// DatabaseRestoreResponse is the response for restoring a database.
+// DatabaseRestoreResponse is the response for submitting a restore. JobID
+// is set when the server restores in the background (202 Accepted); older
+// servers restore synchronously and leave it empty.
 type DatabaseRestoreResponse struct {
 	Status   string `json:"status"`
 	Message  string `json:"message"`
 	Database string `json:"database"`
+	JobID    string `json:"job_id,omitempty"`
 }
 
 // DeleteResponse is the response for deleting a database.
@@ -87,16 +142,16 @@ func parseError(body []byte, statusCode int) error {
 }
 
 // ListDatabases returns all managed databases.
-func ListDatabases(apiURL, apiToken string) (*DatabasesListResponse, error) {
+func ListDatabases(ctx context.Context, apiURL, apiToken string) (*DatabasesListResponse, error) {
 	client := &http.Client{Timeout: requestTimeout}
-	req, err := http.NewRequest("GET", makeAPIURL(apiURL, "/api/deploy/databases"), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", makeAPIURL(apiURL, "/api/deploy/databases"), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -118,16 +173,71 @@ func ListDatabases(apiURL, apiToken string) (*DatabasesListResponse, error) {
 	return &out, nil
 }
 
-// CreateDatabase creates a new managed database.
-// deploymentAlias is optional; if non-empty, the database and its auto-created secret are scoped to that deployment.
-func CreateDatabase(apiURL, apiToken, name, deploymentAlias string) (*DatabaseCreateResponse, error) {
+// Instance tiers, smallest first.
+const (
+	TierSmall  = "small"
+	TierMedium = "medium"
+	TierLarge  = "large"
+)
+
+// Tiers lists the accepted instance tiers.
+var Tiers = []string{TierSmall, TierMedium, TierLarge}
+
+// CreateOptions configure a new database. Empty fields leave the choice
+// to the server.
+type CreateOptions struct {
+	// Deployment scopes the database and its auto-created secret to
+	// that deployment.
+	Deployment string
+	// Engine is one of Engines (server default postgres).
+	Engine string
+	// Version is the engine's major version, e.g. "16".
+	Version string
+	// Tier is one of Tiers.
+	Tier string
+	// Region is where the instance runs, e.g. "eu-west-1".
+	Region string
+}
+
+var (
+	engineVersionRe = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+	regionRe        = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// Validate rejects values the API would, before anything is created.
+func (o CreateOptions) Validate() error {
+	if o.Engine != "" && !slices.Contains(Engines, o.Engine) {
+		return fmt.Errorf("invalid --engine %q (expected one of: %s)", o.Engine, strings.Join(Engines, ", "))
+	}
+	if o.Version != "" && !engineVersionRe.MatchString(o.Version) {
+		return fmt.Errorf("invalid --version %q (expected a version number such as 16)", o.Version)
+	}
+	if o.Tier != "" && !slices.Contains(Tiers, o.Tier) {
+		return fmt.Errorf("invalid --tier %q (expected one of: %s)", o.Tier, strings.Join(Tiers, ", "))
+	}
+	if o.Region != "" && !regionRe.MatchString(o.Region) {
+		return fmt.Errorf("invalid --region %q (expected a region name such as eu-west-1)", o.Region)
+	}
+	return nil
+}
+
+// CreateDatabase creates a new managed database configured by opts.
+func CreateDatabase(ctx context.Context, apiURL, apiToken, name string, opts CreateOptions) (*DatabaseCreateResponse, error) {
 	client := &http.Client{Timeout: requestTimeout}
 	reqBody := map[string]string{"name": name}
-	if deploymentAlias != "" {
-		reqBody["deployment_alias"] = deploymentAlias
+	for key, v := range map[string]string{
+		"deployment_alias": opts.Deployment,
+		"engine":           opts.Engine,
+		"engine_version":   opts.Version,
+		"tier":             opts.Tier,
+		"region":           opts.Region,
+	} {
+		if v != "" {
+			reqBody[key] = v
+		}
 	}
 	payload, _ := json.Marshal(reqBody)
-	req, err := http.NewRequest("POST", makeAPIURL(apiURL, "/api/deploy/databases"), bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", makeAPIURL(apiURL, "/api/deploy/databases"), bytes.NewReader(payload))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -135,7 +245,7 @@ func CreateDatabase(apiURL, apiToken, name, deploymentAlias string) (*DatabaseCr
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -158,16 +268,16 @@ func CreateDatabase(apiURL, apiToken, name, deploymentAlias string) (*DatabaseCr
 }
 
 // DeleteDatabase deletes a database by name.
-func DeleteDatabase(apiURL, apiToken, name string) (*DeleteResponse, error) {
+func DeleteDatabase(ctx context.Context, apiURL, apiToken, name string) (*DeleteResponse, error) {
 	client := &http.Client{Timeout: requestTimeout}
-	req, err := http.NewRequest("DELETE", makeAPIURL(apiURL, "/api/deploy/databases/"+name), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", makeAPIURL(apiURL, "/api/deploy/databases/"+name), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -189,30 +299,66 @@ func DeleteDatabase(apiURL, apiToken, name string) (*DeleteResponse, error) {
 	return &out, nil
 }
 
-// RestoreDatabase restores a database from an uploaded dump file.
-func RestoreDatabase(apiURL, apiToken, name, dumpPath string) (*DatabaseRestoreResponse, error) {
-	f, err := os.Open(dumpPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open dump file: %w", err)
-	}
-	defer f.Close()
-
-	var body bytes.Buffer
-	w := multipart.NewWriter(&body)
+// RestoreOptions are forwarded to the server-side restore (pg_restore's
+// --clean and --if-exists, and the dump's format).
+type RestoreOptions struct {
+	// Format is the dump's format (see DetectDumpFormat), so the server
+	// can pick its restore tool without sniffing; "" leaves it to guess.
+	Format string
+	// Clean drops existing objects before recreating them.
+	Clean bool
+	// IfExists makes Clean's drops not fail on missing objects.
+	IfExists bool
+}
 
-	part, err := w.CreateFormFile("dump", "dump")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+// Validate reports option combinations pg_restore rejects.
+func (o RestoreOptions) Validate() error {
+	if o.IfExists && !o.Clean {
+		return fmt.Errorf("--if-exists requires --clean")
 	}
-	if _, err := io.Copy(part, f); err != nil {
-		return nil, fmt.Errorf("failed to write dump to form: %w", err)
-	}
-	if err := w.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	return nil
+}
+
+// RestoreDatabase uploads a dump read from dump and starts restoring it
+// into the database with opts. The dump is streamed as it is read rather
+// than buffered, so the upload has no timeout of its own; cancel ctx to
+// stop it. Follow the returned job with GetRestoreJob or WaitRestoreJob.
+func RestoreDatabase(ctx context.Context, apiURL, apiToken, name string, dump io.Reader, opts RestoreOptions) (*DatabaseRestoreResponse, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		var err error
+		// Options go before the dump so the server knows them when the
+		// restore starts.
+		if opts.Format != "" {
+			err = w.WriteField("format", opts.Format)
+		}
+		if err == nil && opts.Clean {
+			err = w.WriteField("clean", "true")
+		}
+		if err == nil && opts.IfExists {
+			err = w.WriteField("if_exists", "true")
+		}
+		var part io.Writer
+		if err == nil {
+			part, err = w.CreateFormFile("dump", "dump")
+		}
+		if err == nil {
+			_, err = io.Copy(part, dump)
+		}
+		if err == nil {
+			err = w.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	// Unblock the writer if the request ends before reading all of it.
+	defer pr.Close()
 
-	client := &http.Client{Timeout: 10 * time.Minute}
-	req, err := http.NewRequest("POST", makeAPIURL(apiURL, "/api/deploy/databases/"+name+"/restore"), &body)
+	client := &http.Client{}
+	req, err := http.NewRequestWithContext(ctx, "POST", makeAPIURL(apiURL, "/api/deploy/databases/"+name+"/restore"), pr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -220,7 +366,7 @@ func RestoreDatabase(apiURL, apiToken, name, dumpPath string) (*DatabaseRestoreR
 	req.Header.Set("Authorization", "Bearer "+apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -231,7 +377,7 @@ func RestoreDatabase(apiURL, apiToken, name, dumpPath string) (*DatabaseRestoreR
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		return nil, parseError(respBody, resp.StatusCode)
 	}
 
@@ -242,27 +388,172 @@ func RestoreDatabase(apiURL, apiToken, name, dumpPath string) (*DatabaseRestoreR
 	return &out, nil
 }
 
-// DumpDatabase downloads a database dump and writes it to out. Caller closes out.
-func DumpDatabase(apiURL, apiToken, name string, out io.Writer) error {
+// Dump formats, as pg_dump --format names them, plus Redis's RDB
+// snapshot. The directory format is not offered: it isn't a single stream.
+const (
+	DumpFormatCustom = "custom"
+	DumpFormatPlain  = "plain"
+	DumpFormatTar    = "tar"
+	DumpFormatRDB    = "rdb"
+)
+
+// DumpFormats lists the Postgres dump formats, default first; see
+// DumpFormatsFor for other engines.
+var DumpFormats = []string{DumpFormatCustom, DumpFormatPlain, DumpFormatTar}
+
+// DumpExtension is the usual file extension for a dump format.
+func DumpExtension(format string) string {
+	switch format {
+	case DumpFormatPlain:
+		return ".sql"
+	case DumpFormatTar:
+		return ".tar"
+	case DumpFormatRDB:
+		return ".rdb"
+	default:
+		return ".dump"
+	}
+}
+
+// DumpChecksumHeader is the response header in which the server may send
+// the hex SHA-256 of the whole dump; DumpDatabase verifies it when present.
+const DumpChecksumHeader = "X-Dump-Sha256"
+
+// dumpResumeDelay is how long DumpDatabase waits before resuming a broken
+// download.
+const dumpResumeDelay = 500 * time.Millisecond
+
+// DumpDatabase downloads a database dump in the given format ("" for the
+// server default, custom) and writes it to out. Caller closes out.
+// progress, when non-nil, is called as bytes arrive with the running count
+// and the total size (-1 when the server doesn't send Content-Length).
+//
+// When the connection breaks mid-download, the rest is requested with a
+// Range header (and If-Range, so a dump that changed on the server isn't
+// spliced) and appended to out; each resume is reported on notice. Up to
+// apiclient.Retries() resumes in a row without progress are attempted.
+// Once complete, the size is checked against Content-Length and the
+// content against DumpChecksumHeader when the server sends them.
+func DumpDatabase(ctx context.Context, apiURL, apiToken, name, format string, out io.Writer, progress func(written, total int64), notice io.Writer) error {
+	dumpURL := makeAPIURL(apiURL, "/api/deploy/databases/"+name+"/dump")
+	if format != "" {
+		dumpURL += "?format=" + url.QueryEscape(format)
+	}
 	client := &http.Client{Timeout: 5 * time.Minute}
-	req, err := http.NewRequest("GET", makeAPIURL(apiURL, "/api/deploy/databases/"+name+"/dump"), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	hash := sha256.New()
+	dw := &dumpWriter{w: io.MultiWriter(out, hash), total: -1, report: progress}
+	var etag, checksum string
+	retries, failures := apiclient.Retries(), 0
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", dumpURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+apiToken)
+		req.Header.Set("Accept", "application/octet-stream")
+		if dw.written > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", dw.written))
+			if etag != "" {
+				req.Header.Set("If-Range", etag)
+			}
+		}
+
+		resp, err := apiclient.Do(client, req)
+		if err != nil {
+			return fmt.Errorf("failed to make API request: %w", err)
+		}
+		switch {
+		case dw.written == 0 && resp.StatusCode == http.StatusOK:
+			dw.total = resp.ContentLength
+			etag = resp.Header.Get("ETag")
+			checksum = resp.Header.Get(DumpChecksumHeader)
+		case dw.written > 0 && resp.StatusCode == http.StatusPartialContent:
+			if start := contentRangeStart(resp.Header.Get("Content-Range")); start != dw.written {
+				resp.Body.Close()
+				return fmt.Errorf("server resumed the dump at byte %d instead of %d", start, dw.written)
+			}
+		case dw.written > 0 && resp.StatusCode == http.StatusOK:
+			resp.Body.Close()
+			return fmt.Errorf("the download broke after %d bytes and the server can't resume this dump; run it again", dw.written)
+		default:
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return parseError(body, resp.StatusCode)
+		}
+
+		before := dw.written
+		_, err = io.Copy(dw, resp.Body)
+		resp.Body.Close()
+		if err == nil {
+			break
+		}
+		if dw.err != nil || ctx.Err() != nil {
+			return err
+		}
+		if dw.written > before {
+			failures = 0
+		}
+		if failures++; failures > retries {
+			return fmt.Errorf("download broke after %d bytes: %w", dw.written, err)
+		}
+		if notice != nil {
+			fmt.Fprintf(notice, "Download broke after %d bytes (%v), resuming (%d/%d)…\n", dw.written, err, failures, retries)
+		}
+		select {
+		case <-time.After(dumpResumeDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
-	req.Header.Set("Authorization", "Bearer "+apiToken)
-	req.Header.Set("Accept", "application/octet-stream")
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to make API request: %w", err)
+	if dw.total >= 0 && dw.written != dw.total {
+		return fmt.Errorf("dump incomplete: got %d of %d bytes", dw.written, dw.total)
 	}
-	defer resp.Body.Close()
+	if checksum != "" {
+		if got := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(got, checksum) {
+			return fmt.Errorf("dump checksum mismatch: got sha256 %s, server sent %s", got, checksum)
+		}
+	}
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return parseError(body, resp.StatusCode)
+// contentRangeStart returns the first byte position of a Content-Range
+// header such as "bytes 100-199/200", or -1 when it can't be parsed.
+func contentRangeStart(h string) int64 {
+	rest, ok := strings.CutPrefix(h, "bytes ")
+	if !ok {
+		return -1
 	}
+	first, _, ok := strings.Cut(rest, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+// dumpWriter counts the bytes written across resumed requests, reports
+// them after every write and remembers a write error, which unlike a read
+// error can't be resumed from.
+type dumpWriter struct {
+	w       io.Writer
+	written int64
+	total   int64
+	report  func(written, total int64)
+	err     error
+}
+
+func (d *dumpWriter) Write(b []byte) (int, error) {
+	n, err := d.w.Write(b)
+	d.written += int64(n)
+	if d.report != nil {
+		d.report(d.written, d.total)
+	}
+	if err != nil {
+		d.err = err
+	}
+	return n, err
 }
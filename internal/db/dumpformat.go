@@ -0,0 +1,57 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// DumpSniffLen is how many leading bytes DetectDumpFormat needs to tell
+// every format apart (a tar header's magic ends at byte 262).
+const DumpSniffLen = 512
+
+// DetectDumpFormat guesses a dump's format from its first bytes (up to
+// DumpSniffLen of them): a pg_dump custom archive, a tar archive, a Redis
+// RDB snapshot or a plain SQL script. It returns an error describing the
+// data when it is none of these, e.g. an empty or compressed file.
+func DetectDumpFormat(head []byte) (string, error) {
+	switch {
+	case len(head) == 0:
+		return "", fmt.Errorf("the dump is empty")
+	case bytes.HasPrefix(head, []byte("PGDMP")):
+		return DumpFormatCustom, nil
+	case bytes.HasPrefix(head, []byte("REDIS")):
+		return DumpFormatRDB, nil
+	case len(head) >= 262 && bytes.Equal(head[257:262], []byte("ustar")):
+		return DumpFormatTar, nil
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return "", fmt.Errorf("the dump is gzip-compressed; decompress it first (e.g. gunzip -c dump.gz | dibbla db restore <name> -f -)")
+	case bytes.HasPrefix(head, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return "", fmt.Errorf("the dump is zstd-compressed; decompress it first (e.g. zstd -dc dump.zst | dibbla db restore <name> -f -)")
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return "", fmt.Errorf("the dump is a zip archive; extract the dump from it first")
+	case isText(head):
+		return DumpFormatPlain, nil
+	default:
+		return "", fmt.Errorf("the file is not a recognized dump (expected a pg_dump archive, a tar archive, an RDB snapshot or an SQL script)")
+	}
+}
+
+// isText reports whether head looks like the start of a text file: no NUL
+// bytes. The encoding isn't checked; dumps in e.g. Latin-1 are common.
+func isText(head []byte) bool {
+	return bytes.IndexByte(head, 0) < 0
+}
+
+// IsUTF8 reports whether head is valid UTF-8. A rune cut off at the end of
+// head is allowed.
+func IsUTF8(head []byte) bool {
+	for len(head) > 0 {
+		r, size := utf8.DecodeRune(head)
+		if r == utf8.RuneError && size == 1 {
+			return !utf8.FullRune(head)
+		}
+		head = head[size:]
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package db
+
+// Database engines. Databases created before engines were selectable, and
+// servers that don't report one, are Postgres.
+const (
+	EnginePostgres = "postgres"
+	EngineMySQL    = "mysql"
+	EngineRedis    = "redis"
+)
+
+// Engines lists the accepted engines, default first.
+var Engines = []string{EnginePostgres, EngineMySQL, EngineRedis}
+
+// EngineOrDefault is the database's engine, postgres when unset.
+func (d Database) EngineOrDefault() string {
+	if d.Engine == "" {
+		return EnginePostgres
+	}
+	return d.Engine
+}
+
+// DumpFormatsFor lists the dump formats an engine supports, default first:
+// pg_dump's formats for Postgres, a mysqldump script for MySQL and an RDB
+// snapshot for Redis.
+func DumpFormatsFor(engine string) []string {
+	switch engine {
+	case EngineMySQL:
+		return []string{DumpFormatPlain}
+	case EngineRedis:
+		return []string{DumpFormatRDB}
+	default:
+		return DumpFormats
+	}
+}
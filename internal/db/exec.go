@@ -0,0 +1,101 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// execTimeout bounds a whole Exec batch; seed scripts can be long.
+const execTimeout = 30 * time.Minute
+
+// ExecRequest is the body of an exec batch.
+type ExecRequest struct {
+	Statements []string `json:"statements"`
+	// Transaction runs the batch in one transaction, rolled back if a
+	// statement fails.
+	Transaction bool `json:"transaction"`
+}
+
+// ExecResult is one line of the exec stream: the outcome of the statement
+// at Index (0-based). Error is set when it failed, which ends the batch.
+type ExecResult struct {
+	Index        int    `json:"index"`
+	Command      string `json:"command,omitempty"`
+	RowsAffected int64  `json:"rows_affected"`
+	Error        string `json:"error,omitempty"`
+}
+
+// ExecError is a statement of an Exec batch failing.
+type ExecError struct {
+	Index   int
+	Message string
+}
+
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("statement %d failed: %s", e.Index+1, e.Message)
+}
+
+// Exec runs req.Statements in order, in one database session, and calls
+// done with the result of each as the server streams it back (NDJSON).
+// It stops at the first failing statement and returns an *ExecError; any
+// other error leaves the outcome of the batch unknown.
+func Exec(ctx context.Context, apiURL, apiToken, name string, req ExecRequest, done func(ExecResult)) error {
+	client := &http.Client{Timeout: execTimeout}
+	payload, _ := json.Marshal(req)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", makeAPIURL(apiURL, "/api/deploy/databases/"+name+"/exec"), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+apiToken)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/x-ndjson")
+
+	// Never retried: a batch that reached the server may have run.
+	resp, err := apiclient.DoWithRetries(client, httpReq, 0)
+	if err != nil {
+		return fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return parseError(body, resp.StatusCode)
+	}
+
+	n := 0
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var res ExecResult
+		if err := json.Unmarshal(line, &res); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		if res.Error != "" {
+			return &ExecError{Index: res.Index, Message: strings.TrimSpace(res.Error)}
+		}
+		if done != nil {
+			done(res)
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if n < len(req.Statements) {
+		return fmt.Errorf("the server stopped after %d of %d statements", n, len(req.Statements))
+	}
+	return nil
+}
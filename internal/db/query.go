@@ -0,0 +1,56 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// QueryResult is the response for running a SQL statement. Columns and
+// Rows are empty for statements that return no rows; Command is the
+// command tag (e.g. "SELECT 3", "UPDATE 1").
+type QueryResult struct {
+	Columns      []string `json:"columns"`
+	Rows         [][]any  `json:"rows"`
+	Command      string   `json:"command"`
+	RowsAffected int64    `json:"rows_affected"`
+}
+
+// Query runs one SQL statement against a database through the API.
+func Query(ctx context.Context, apiURL, apiToken, name, sql string) (*QueryResult, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	payload, _ := json.Marshal(map[string]string{"sql": sql})
+	req, err := http.NewRequestWithContext(ctx, "POST", makeAPIURL(apiURL, "/api/deploy/databases/"+name+"/query"), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(body, resp.StatusCode)
+	}
+
+	var out QueryResult
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &out, nil
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"net/http"
+)
+
+// ResetResponse is the response for resetting a database.
+type ResetResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	Database string `json:"database"`
+}
+
+// ResetDatabase drops database name and recreates it empty on the same
+// instance. Its name, credentials and secrets are kept, so apps using it
+// reconnect to the empty database without changes.
+func ResetDatabase(ctx context.Context, apiURL, apiToken, name string) (*ResetResponse, error) {
+	var out ResetResponse
+	if err := backupsRequest(ctx, apiURL, apiToken, "POST", "/api/deploy/databases/"+name+"/reset", nil, &out, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
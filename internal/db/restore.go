@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// Restore job statuses. Queued and running jobs are still in progress.
+const (
+	RestoreQueued    = "queued"
+	RestoreRunning   = "running"
+	RestoreSucceeded = "succeeded"
+	RestoreFailed    = "failed"
+)
+
+// RestoreJob is the state of a background restore.
+type RestoreJob struct {
+	ID           string     `json:"job_id"`
+	Database     string     `json:"database"`
+	Status       string     `json:"status"`
+	Phase        string     `json:"phase,omitempty"`
+	RowsRestored int64      `json:"rows_restored"`
+	TablesDone   int        `json:"tables_done,omitempty"`
+	TablesTotal  int        `json:"tables_total,omitempty"`
+	Message      string     `json:"message,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// Done reports whether the job has finished, successfully or not. A status
+// this CLI doesn't know counts as finished (see Succeeded), so a new server
+// state can't keep it polling forever.
+func (j *RestoreJob) Done() bool {
+	return j.Status != RestoreQueued && j.Status != RestoreRunning
+}
+
+// Succeeded reports whether the job finished successfully.
+func (j *RestoreJob) Succeeded() bool {
+	return j.Status == RestoreSucceeded
+}
+
+// GetRestoreJob returns the current state of a restore job.
+func GetRestoreJob(ctx context.Context, apiURL, apiToken, name, jobID string) (*RestoreJob, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	req, err := http.NewRequestWithContext(ctx, "GET", makeAPIURL(apiURL, "/api/deploy/databases/"+name+"/restore/"+url.PathEscape(jobID)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(body, resp.StatusCode)
+	}
+
+	var out RestoreJob
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &out, nil
+}
+
+// WaitRestoreJob polls a restore job every interval until it finishes or
+// ctx is done. update, when non-nil, is called with every poll result. A
+// job that finishes unsuccessfully is returned without an error; check
+// Succeeded.
+func WaitRestoreJob(ctx context.Context, apiURL, apiToken, name, jobID string, interval time.Duration, update func(*RestoreJob)) (*RestoreJob, error) {
+	for {
+		job, err := GetRestoreJob(ctx, apiURL, apiToken, name, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if update != nil {
+			update(job)
+		}
+		if job.Done() {
+			return job, nil
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
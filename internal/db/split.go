@@ -0,0 +1,115 @@
+package db
+
+import "strings"
+
+// SplitStatements splits a SQL script for a database of engine into
+// statements on the semicolons that end them. Semicolons inside quoted
+// strings and identifiers ('…', "…", `…`), Postgres dollar-quoted bodies
+// ($$…$$, $tag$…$tag$) and comments don't count. A backslash escapes the
+// next character in MySQL strings and in Postgres E'…' strings. Statements
+// are trimmed, and ones that are only whitespace and comments are dropped.
+func SplitStatements(sql, engine string) []string {
+	mysql := engine == EngineMySQL
+	var stmts []string
+	start := 0
+	flush := func(end int) {
+		if s := strings.TrimSpace(sql[start:end]); hasCode(s) {
+			stmts = append(stmts, s)
+		}
+	}
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			backslash := mysql && c != '`' || !mysql && c == '\'' && escapeString(sql, i)
+			i = skipQuoted(sql, i, c, backslash)
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			i = skipTo(sql, i, "\n")
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			i = skipTo(sql, i+2, "*/")
+		case c == '$' && !mysql:
+			if tag, ok := dollarTag(sql[i:]); ok {
+				i = skipTo(sql, i+len(tag), tag)
+			}
+		case c == ';':
+			flush(i)
+			start = i + 1
+		}
+	}
+	flush(len(sql))
+	return stmts
+}
+
+// skipQuoted returns the index of the quote closing the string opened by
+// quote at i, or the last index when it is unterminated. A doubled quote
+// is an escaped one, and so, with backslash, is one after a backslash.
+func skipQuoted(sql string, i int, quote byte, backslash bool) int {
+	for j := i + 1; j < len(sql); j++ {
+		if backslash && sql[j] == '\\' {
+			j++
+			continue
+		}
+		if sql[j] != quote {
+			continue
+		}
+		if j+1 < len(sql) && sql[j+1] == quote {
+			j++
+			continue
+		}
+		return j
+	}
+	return len(sql) - 1
+}
+
+// escapeString reports whether the quote at i opens a Postgres escape
+// string: E'…' (or e'…'), where the E isn't the end of an identifier.
+func escapeString(sql string, i int) bool {
+	if i == 0 || sql[i-1] != 'E' && sql[i-1] != 'e' {
+		return false
+	}
+	return i == 1 || !isIdentByte(sql[i-2])
+}
+
+// isIdentByte reports whether c can be part of an unquoted identifier.
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// skipTo returns the index of the last byte of the first end found from
+// i, or the last index when there is none.
+func skipTo(sql string, i int, end string) int {
+	if j := strings.Index(sql[i:], end); j >= 0 {
+		return i + j + len(end) - 1
+	}
+	return len(sql) - 1
+}
+
+// dollarTag returns the dollar-quote opener ($$ or $tag$) s starts with.
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '$':
+			return s[:j+1], true
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > 1 && c >= '0' && c <= '9':
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// hasCode reports whether s holds anything besides comments.
+func hasCode(s string) bool {
+	for s != "" {
+		s = strings.TrimSpace(s)
+		switch {
+		case strings.HasPrefix(s, "--"):
+			_, s, _ = strings.Cut(s, "\n")
+		case strings.HasPrefix(s, "/*"):
+			_, s, _ = strings.Cut(s, "*/")
+		default:
+			return s != ""
+		}
+	}
+	return false
+}
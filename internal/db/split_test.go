@@ -0,0 +1,44 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name, engine, sql string
+		want              []string
+	}{
+		{"simple", EnginePostgres, "CREATE TABLE t (id int);\nINSERT INTO t VALUES (1);", []string{"CREATE TABLE t (id int)", "INSERT INTO t VALUES (1)"}},
+		{"no trailing semicolon", EnginePostgres, "SELECT 1", []string{"SELECT 1"}},
+		{"quoted semicolons", EnginePostgres, `INSERT INTO t VALUES ('a;b', 'it''s; fine');SELECT "x;y" FROM t`, []string{`INSERT INTO t VALUES ('a;b', 'it''s; fine')`, `SELECT "x;y" FROM t`}},
+		{"comments", EnginePostgres, "-- setup; really\nSELECT 1; /* a; b */ SELECT 2;\n-- trailing comment", []string{"-- setup; really\nSELECT 1", "/* a; b */ SELECT 2"}},
+		{"empty statements", EnginePostgres, ";;\n  ;-- nothing\n", nil},
+
+		{"dollar quoted", EnginePostgres, "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql;\nDO $body$ BEGIN PERFORM 1; END $body$;", []string{"CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql", "DO $body$ BEGIN PERFORM 1; END $body$"}},
+		{"dollar quote holding another tag", EnginePostgres, "DO $outer$ BEGIN EXECUTE $q$ SELECT 1; $q$; END $outer$; SELECT 2", []string{"DO $outer$ BEGIN EXECUTE $q$ SELECT 1; $q$; END $outer$", "SELECT 2"}},
+		{"dollar quote holding quotes", EnginePostgres, "SELECT $$it's; \"x\"$$; SELECT 2", []string{"SELECT $$it's; \"x\"$$", "SELECT 2"}},
+		{"positional parameter", EnginePostgres, "PREPARE p AS SELECT $1; EXECUTE p(1);", []string{"PREPARE p AS SELECT $1", "EXECUTE p(1)"}},
+		{"unterminated dollar quote", EnginePostgres, "SELECT 1; DO $$ BEGIN; END", []string{"SELECT 1", "DO $$ BEGIN; END"}},
+
+		{"escape string", EnginePostgres, `INSERT INTO t VALUES (E'it\'s; fine'); SELECT 2`, []string{`INSERT INTO t VALUES (E'it\'s; fine')`, "SELECT 2"}},
+		{"lower-case escape string", EnginePostgres, `SELECT e'a\\'; SELECT 2`, []string{`SELECT e'a\\'`, "SELECT 2"}},
+		{"backslash in a standard string", EnginePostgres, `SELECT 'C:\'; SELECT 2`, []string{`SELECT 'C:\'`, "SELECT 2"}},
+		{"identifier ending in e", EnginePostgres, `SELECT name'x\'; SELECT 2`, []string{`SELECT name'x\'`, "SELECT 2"}},
+
+		{"mysql backslash-escaped quote", EngineMySQL, `INSERT INTO t VALUES ('it\'s; fine'); SELECT 2`, []string{`INSERT INTO t VALUES ('it\'s; fine')`, "SELECT 2"}},
+		{"mysql double-quoted string", EngineMySQL, `SELECT "a\"; b"; SELECT 2`, []string{`SELECT "a\"; b"`, "SELECT 2"}},
+		{"mysql escaped backslash", EngineMySQL, `SELECT 'a\\'; SELECT 2`, []string{`SELECT 'a\\'`, "SELECT 2"}},
+		{"mysql backticks", EngineMySQL, "SELECT `a\\`; SELECT 2", []string{"SELECT `a\\`", "SELECT 2"}},
+		{"mysql dollar in identifiers", EngineMySQL, "SELECT a$b$ FROM t; SELECT 2", []string{"SELECT a$b$ FROM t", "SELECT 2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitStatements(tt.sql, tt.engine)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
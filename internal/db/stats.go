@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// TableStats is the size of one table in DatabaseStats.
+type TableStats struct {
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"size_bytes"`
+	Rows      int64  `json:"rows"`
+}
+
+// DatabaseStats is the payload of GET /databases/{name}/stats. The limits
+// are zero when the plan sets none; CacheHitRatio is nil when the engine
+// doesn't report one.
+type DatabaseStats struct {
+	Database       string       `json:"database"`
+	Engine         string       `json:"engine,omitempty"`
+	SizeBytes      int64        `json:"size_bytes"`
+	SizeLimitBytes int64        `json:"size_limit_bytes,omitempty"`
+	TableCount     int          `json:"table_count"`
+	LargestTables  []TableStats `json:"largest_tables"`
+	Connections    int          `json:"connections"`
+	MaxConnections int          `json:"max_connections,omitempty"`
+	CacheHitRatio  *float64     `json:"cache_hit_ratio,omitempty"`
+}
+
+// GetDatabaseStats returns size and usage figures for database name, with
+// its top largest tables (0 leaves the number to the server).
+func GetDatabaseStats(ctx context.Context, apiURL, apiToken, name string, top int) (*DatabaseStats, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	u := makeAPIURL(apiURL, "/api/deploy/databases/"+name+"/stats")
+	if top > 0 {
+		u += "?top=" + strconv.Itoa(top)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(body, resp.StatusCode)
+	}
+
+	var out DatabaseStats
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &out, nil
+}
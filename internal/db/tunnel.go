@@ -0,0 +1,19 @@
+package db
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/tunnel"
+)
+
+// Tunnel accepts connections on ln until ctx is cancelled and forwards each
+// one to database name over its own authenticated WebSocket
+// (/databases/{name}/tunnel), so the database is reachable locally without
+// going through the public proxy port. Per-connection failures are
+// reported on notice. ln is closed on return.
+func Tunnel(ctx context.Context, apiURL, apiToken, name string, ln net.Listener, notice io.Writer) error {
+	return tunnel.Forward(ctx, apiURL, apiToken, "/databases/"+url.PathEscape(name)+"/tunnel", nil, ln, notice)
+}
@@ -0,0 +1,134 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// archiveFilter layers invocation-time --include / --exclude globs on top of
+// the built-in shouldExclude rules. Patterns use forward slashes relative to
+// the deploy root; `**` matches any number of directories, and a pattern
+// without a slash matches the base name at any depth (so "*.log" works like
+// it does in .gitignore). A pattern that matches a directory covers
+// everything beneath it. --include wins over the built-in rules, .gitignore
+// (when respected) and --exclude.
+type archiveFilter struct {
+	Include   []string
+	Exclude   []string
+	Gitignore *gitignore // nil unless --respect-gitignore / archive.respect_gitignore
+	// FollowSymlinks dereferences symlinks that point outside the deploy
+	// root (--follow-symlinks) instead of skipping them.
+	FollowSymlinks bool
+}
+
+// validate rejects malformed glob patterns up front so a typo fails the
+// deploy instead of silently matching nothing.
+func (f archiveFilter) validate() error {
+	for _, list := range [][]string{f.Include, f.Exclude} {
+		for _, p := range list {
+			for _, seg := range globSegments(p) {
+				if _, err := path.Match(seg, ""); err != nil {
+					return fmt.Errorf("invalid glob %q: %w", p, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// excluded reports whether relPath is left out of the archive. For an
+// excluded directory, descend is true when an --include pattern could still
+// match something beneath it, so the walk continues into it instead of
+// skipping the whole subtree.
+func (f archiveFilter) excluded(relPath string, info os.FileInfo) (exclude, descend bool) {
+	rel := filepath.ToSlash(relPath)
+	if !shouldExclude(relPath, info) && !f.Gitignore.ignored(relPath, info.IsDir()) && !matchAnyGlob(f.Exclude, rel) {
+		return false, false
+	}
+	if matchAnyGlob(f.Include, rel) {
+		return false, false
+	}
+	return true, info.IsDir() && f.mayIncludeUnder(rel)
+}
+
+func (f archiveFilter) mayIncludeUnder(dir string) bool {
+	dirSegs := strings.Split(dir, "/")
+	for _, p := range f.Include {
+		segs := globSegments(p)
+		if len(segs) == 1 || globPrefixMatch(segs, dirSegs) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyGlob reports whether rel, or any directory above it, matches one
+// of patterns.
+func matchAnyGlob(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		segs := globSegments(p)
+		relSegs := strings.Split(rel, "/")
+		if len(segs) == 1 {
+			for _, s := range relSegs {
+				if ok, _ := path.Match(segs[0], s); ok {
+					return true
+				}
+			}
+			continue
+		}
+		for i := len(relSegs); i > 0; i-- {
+			if globMatch(segs, relSegs[:i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func globSegments(pattern string) []string {
+	p := strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+// globMatch matches path segments against pattern segments, with "**"
+// standing for zero or more whole segments.
+func globMatch(pat, segs []string) bool {
+	if len(pat) == 0 {
+		return len(segs) == 0
+	}
+	if pat[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if globMatch(pat[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pat[0], segs[0]); !ok {
+		return false
+	}
+	return globMatch(pat[1:], segs[1:])
+}
+
+// globPrefixMatch reports whether some path under dir could match pat.
+func globPrefixMatch(pat, dir []string) bool {
+	if len(dir) == 0 {
+		return true
+	}
+	if len(pat) == 0 {
+		return false
+	}
+	if pat[0] == "**" {
+		return true
+	}
+	if ok, _ := path.Match(pat[0], dir[0]); !ok {
+		return false
+	}
+	return globPrefixMatch(pat[1:], dir[1:])
+}
@@ -0,0 +1,68 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchAnyGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"dist/**", "dist", true},
+		{"dist/**", "dist/assets/app.js", true},
+		{"dist", "dist/app.js", true},
+		{"*.log", "logs/today/app.log", true},
+		{"**/*.map", "web/dist/app.js.map", true},
+		{"./docs/*.md", "docs/intro.md", true},
+		{"docs/*.md", "docs/sub/intro.md", false},
+		{"dist/**", "src/dist.go", false},
+	}
+	for _, tt := range tests {
+		if got := matchAnyGlob([]string{tt.pattern}, tt.rel); got != tt.want {
+			t.Errorf("matchAnyGlob(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestArchiveFilterValidateRejectsBadGlob(t *testing.T) {
+	if err := (archiveFilter{Exclude: []string{"dist/[a-"}}).validate(); err == nil {
+		t.Fatal("expected malformed glob to be rejected")
+	}
+}
+
+func TestBuildArchive_IncludeExclude(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"dist", "src", "node_modules/local-pkg", "node_modules/other"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	writeFile(t, dir, "dist/bundle.js", "x")
+	writeFile(t, dir, "src/main.js", "x")
+	writeFile(t, dir, "node_modules/local-pkg/index.js", "x")
+	writeFile(t, dir, "node_modules/other/index.js", "x")
+
+	archive, err := buildArchive(dir, archiveFilter{
+		Exclude: []string{"dist/**"},
+		Include: []string{"node_modules/local-pkg/**"},
+	}, compression{})
+	if err != nil {
+		t.Fatalf("buildArchive: %v", err)
+	}
+	entries := readTarEntries(t, archive)
+	for _, want := range []string{"Dockerfile", "src/main.js", "node_modules/local-pkg/index.js"} {
+		if _, ok := entries[want]; !ok {
+			t.Errorf("expected %q in archive; got %v", want, entryNames(entries))
+		}
+	}
+	for _, unwanted := range []string{"dist/", "dist/bundle.js", "node_modules/other/index.js"} {
+		if _, ok := entries[unwanted]; ok {
+			t.Errorf("did not expect %q in archive", unwanted)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package deploy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// maxAliasLen keeps aliases valid DNS labels.
+const maxAliasLen = 63
+
+var nonAliasChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// PreviewAlias derives the alias of a branch preview from the app's alias
+// and the git branch: "shop" + "feature/Checkout_v2" → "shop-feature-checkout-v2".
+// Aliases that would exceed 63 characters are shortened and suffixed with a
+// hash of the branch so two long branch names never collide.
+func PreviewAlias(base, branch string) string {
+	slug := strings.Trim(nonAliasChars.ReplaceAllString(strings.ToLower(branch), "-"), "-")
+	alias := base + "-" + slug
+	if len(alias) <= maxAliasLen {
+		return alias
+	}
+	sum := sha1.Sum([]byte(branch))
+	suffix := "-" + hex.EncodeToString(sum[:])[:8]
+	return strings.TrimRight(alias[:maxAliasLen-len(suffix)], "-") + suffix
+}
+
+// CurrentBranch returns the git branch checked out in dir. A detached HEAD
+// or a directory outside a git repository is an error: previews are keyed
+// by branch.
+func CurrentBranch(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("cannot determine the git branch: %w", err)
+	}
+	if out == "HEAD" {
+		return "", fmt.Errorf("cannot determine the git branch: HEAD is detached")
+	}
+	return out, nil
+}
+
+// DefaultBranch guesses the branch previews merge into: origin's HEAD when
+// known, else main, else master.
+func DefaultBranch(dir string) string {
+	if ref, err := git(dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil && ref != "" {
+		return ref
+	}
+	if _, err := git(dir, "rev-parse", "--verify", "--quiet", "main"); err == nil {
+		return "main"
+	}
+	return "master"
+}
+
+// BranchStates reports, for every local and remote-tracking branch in dir,
+// whether it is merged into base. Remote-tracking names are reported
+// without their remote prefix ("origin/feature-x" → "feature-x"); a branch
+// counts as merged if any of its refs is. Branches missing from the map no
+// longer exist anywhere the repository knows about.
+func BranchStates(dir, base string) (map[string]bool, error) {
+	states := map[string]bool{}
+	all, err := git(dir, "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range strings.Fields(all) {
+		if name := branchName(ref); name != "" {
+			if _, ok := states[name]; !ok {
+				states[name] = false
+			}
+		}
+	}
+	merged, err := git(dir, "for-each-ref", "--format=%(refname)", "--merged", base, "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range strings.Fields(merged) {
+		if name := branchName(ref); name != "" {
+			states[name] = true
+		}
+	}
+	return states, nil
+}
+
+// RemoteBranches lists the branches on dir's remotes as the servers have
+// them now (git ls-remote), including ones never fetched into
+// remote-tracking refs.
+func RemoteBranches(dir string) ([]string, error) {
+	remotes, err := git(dir, "remote")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, remote := range strings.Fields(remotes) {
+		out, err := git(dir, "ls-remote", "--heads", remote)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if _, ref, ok := strings.Cut(line, "\t"); ok {
+				if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// branchName strips refs/heads/ or refs/remotes/<remote>/ from ref and
+// drops symbolic remote HEADs.
+func branchName(ref string) string {
+	if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+		return name
+	}
+	if rest, ok := strings.CutPrefix(ref, "refs/remotes/"); ok {
+		if _, name, ok := strings.Cut(rest, "/"); ok && name != "HEAD" {
+			return name
+		}
+	}
+	return ""
+}
+
+func git(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,74 @@
+package deploy
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestPreviewAlias(t *testing.T) {
+	if got := PreviewAlias("shop", "feature/Checkout_v2"); got != "shop-feature-checkout-v2" {
+		t.Errorf("got %q", got)
+	}
+	long := PreviewAlias("shop", strings.Repeat("very-long-branch-name-", 5))
+	if len(long) > maxAliasLen {
+		t.Errorf("alias too long (%d): %q", len(long), long)
+	}
+	other := PreviewAlias("shop", strings.Repeat("very-long-branch-name-", 5)+"2")
+	if long == other {
+		t.Errorf("long branch names should not collide: %q", long)
+	}
+}
+
+func TestBranchStates(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.email=t@example.com", "-c", "user.name=t"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	run("commit", "-q", "--allow-empty", "-m", "root")
+	run("branch", "merged")
+	run("checkout", "-q", "-b", "feature/open")
+	run("commit", "-q", "--allow-empty", "-m", "wip")
+
+	branch, err := CurrentBranch(dir)
+	if err != nil || branch != "feature/open" {
+		t.Fatalf("CurrentBranch = %q, %v", branch, err)
+	}
+	if got := DefaultBranch(dir); got != "main" {
+		t.Errorf("DefaultBranch = %q, want main", got)
+	}
+	states, err := BranchStates(dir, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !states["merged"] || states["feature/open"] {
+		t.Errorf("unexpected states %v", states)
+	}
+	if _, ok := states["deleted"]; ok {
+		t.Error("unknown branch should be absent")
+	}
+
+	// A branch pushed by someone else and never fetched only shows up on
+	// the remote.
+	remote := t.TempDir()
+	run("init", "-q", "--bare", remote)
+	run("remote", "add", "origin", remote)
+	run("push", "-q", "origin", "feature/open:refs/heads/unfetched")
+	run("update-ref", "-d", "refs/remotes/origin/unfetched")
+	states, _ = BranchStates(dir, "main")
+	if _, ok := states["unfetched"]; ok {
+		t.Fatal("unfetched branch should not be known locally")
+	}
+	names, err := RemoteBranches(dir)
+	if err != nil || strings.Join(names, ",") != "unfetched" {
+		t.Errorf("RemoteBranches = %v, %v", names, err)
+	}
+}
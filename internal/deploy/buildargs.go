@@ -0,0 +1,62 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/manifest"
+)
+
+// ValidateBuildArgs checks that each --build-arg is KEY=value with a valid
+// Docker ARG name. The value may be empty or contain "=".
+func ValidateBuildArgs(pairs []string) error {
+	for _, p := range pairs {
+		name, _, ok := strings.Cut(p, "=")
+		if !ok || !manifest.BuildArgNameRe.MatchString(name) {
+			return fmt.Errorf("invalid --build-arg %q (expected KEY=value)", p)
+		}
+	}
+	return nil
+}
+
+// resolveBuildArgs merges build_args from the dibbla.yaml at projectRoot
+// (after shell ${VAR} substitution, so tokens can stay out of the file)
+// with the --build-arg pairs; flags win on conflicts. It returns the JSON
+// object for the build_args upload field, or "" when there are none.
+func resolveBuildArgs(pairs []string, projectRoot string) (string, error) {
+	if err := ValidateBuildArgs(pairs); err != nil {
+		return "", err
+	}
+	args := map[string]string{}
+	if p, ambiguous, found := manifest.Discover(projectRoot); found && !ambiguous {
+		raw, err := os.ReadFile(p)
+		if err != nil {
+			return "", fmt.Errorf("read manifest: %w", err)
+		}
+		subbed, err := SubstituteShellVarsFromOSEnv(raw)
+		if err != nil {
+			return "", fmt.Errorf("manifest shell-var substitution: %w", err)
+		}
+		fromManifest, err := manifest.LoadBuildArgs(subbed)
+		if err != nil {
+			return "", err
+		}
+		for k, v := range fromManifest {
+			args[k] = v
+		}
+	}
+	for _, p := range pairs {
+		name, value, _ := strings.Cut(p, "=")
+		args[name] = value
+	}
+	if len(args) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
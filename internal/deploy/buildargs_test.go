@@ -0,0 +1,61 @@
+package deploy
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateBuildArgs(t *testing.T) {
+	if err := ValidateBuildArgs([]string{"VERSION=1.2", "EMPTY=", "URL=a=b"}); err != nil {
+		t.Errorf("valid args rejected: %v", err)
+	}
+	for _, bad := range []string{"NOVALUE", "=x", "9LIVES=1", "BAD-NAME=1"} {
+		if err := ValidateBuildArgs([]string{bad}); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestRunSendsMergedBuildArgs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "dibbla.yaml", "version: 1\nservices:\n  app: { build: . }\nbuild_args:\n  VERSION: \"1.0\"\n  REGISTRY: ${TEST_BUILD_REGISTRY}\n")
+	t.Setenv("TEST_BUILD_REGISTRY", "ghcr.io")
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+
+	f := newFakeDeployServer(t)
+	_, err := Run(Options{
+		APIURL:    f.srv.URL,
+		APIToken:  "tok",
+		Path:      dir,
+		BuildArgs: []string{"VERSION=2.0", "NPM_TOKEN=secret"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(f.formVals["build_args"]), &got); err != nil {
+		t.Fatalf("build_args field %q: %v", f.formVals["build_args"], err)
+	}
+	want := map[string]string{"VERSION": "2.0", "REGISTRY": "ghcr.io", "NPM_TOKEN": "secret"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: want %q, got %q", k, v, got[k])
+		}
+	}
+}
+
+func TestRunOmitsBuildArgsWhenNone(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+
+	f := newFakeDeployServer(t)
+	if _, err := Run(Options{APIURL: f.srv.URL, APIToken: "tok", Path: dir}, nil); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, ok := f.formVals["build_args"]; ok {
+		t.Error("build_args should be absent without --build-arg or build_args")
+	}
+}
@@ -0,0 +1,107 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+	"github.com/dibbla-agents/dibbla-cli/internal/manifest"
+)
+
+// Builders the platform can build a single-app deploy with.
+// BuilderDockerfile builds the Dockerfile at the deploy root;
+// BuilderBuildpacks builds from source with Cloud Native Buildpacks.
+const (
+	BuilderDockerfile = "dockerfile"
+	BuilderBuildpacks = "buildpacks"
+)
+
+// ValidateBuilder checks a --builder value. Empty means "pick from the
+// project" (see ResolveBuilder).
+func ValidateBuilder(builder string) error {
+	switch builder {
+	case "", BuilderDockerfile, BuilderBuildpacks:
+		return nil
+	}
+	return fmt.Errorf("unknown builder %q (supported: %s, %s; see 'dibbla builders list')", builder, BuilderDockerfile, BuilderBuildpacks)
+}
+
+// ResolveBuilder returns the builder to request for the project at dir.
+// An explicit builder wins; otherwise a project with neither a Dockerfile
+// nor dibbla.yaml is built with buildpacks, and anything else is left to
+// the server ("").
+func ResolveBuilder(builder, dir string) string {
+	if builder != "" {
+		return builder
+	}
+	if _, _, found := manifest.Discover(dir); found {
+		return ""
+	}
+	if fileExistsAt(filepath.Join(dir, "Dockerfile")) {
+		return ""
+	}
+	return BuilderBuildpacks
+}
+
+// BuilderStack is one buildpack stack (base image and the languages its
+// buildpacks detect).
+type BuilderStack struct {
+	ID        string   `json:"id"`
+	Languages []string `json:"languages"`
+	Default   bool     `json:"default,omitempty"`
+}
+
+// Builder describes one build strategy the platform supports.
+type Builder struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Stacks      []BuilderStack `json:"stacks,omitempty"`
+}
+
+// BuildersResponse is the payload of GET /builders.
+type BuildersResponse struct {
+	Builders []Builder `json:"builders"`
+}
+
+// ListBuilders returns the builders (and buildpack stacks) the platform
+// supports.
+func ListBuilders(ctx context.Context, apiURL, apiToken string) (*BuildersResponse, error) {
+	url := strings.TrimSuffix(apiURL, "/") + "/api/deploy/builders"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		var out BuildersResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,54 @@
+package deploy
+
+import "testing"
+
+func TestResolveBuilder(t *testing.T) {
+	bare := t.TempDir()
+	writeFile(t, bare, "package.json", "{}")
+	docker := t.TempDir()
+	writeFile(t, docker, "Dockerfile", "FROM scratch\n")
+	multi := t.TempDir()
+	writeFile(t, multi, "dibbla.yaml", "version: 1\n")
+
+	cases := []struct {
+		builder, dir, want string
+	}{
+		{"", bare, BuilderBuildpacks},
+		{"", docker, ""},
+		{"", multi, ""},
+		{BuilderBuildpacks, docker, BuilderBuildpacks},
+		{BuilderDockerfile, bare, BuilderDockerfile},
+	}
+	for _, c := range cases {
+		if got := ResolveBuilder(c.builder, c.dir); got != c.want {
+			t.Errorf("ResolveBuilder(%q, %s) = %q, want %q", c.builder, c.dir, got, c.want)
+		}
+	}
+	if err := ValidateBuilder("nixpacks"); err == nil {
+		t.Error("unknown builder accepted")
+	}
+}
+
+func TestRunSendsBuilderForDockerfileLessProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "package.json", `{"dependencies":{"express":"4"}}`)
+
+	f := newFakeDeployServer(t)
+	if _, err := Run(Options{APIURL: f.srv.URL, APIToken: "tok", Path: dir}, nil); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := f.formVals["builder"]; got != BuilderBuildpacks {
+		t.Errorf("builder field = %q, want %q", got, BuilderBuildpacks)
+	}
+}
+
+func TestRunRejectsBuilderWithManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "dibbla.yaml", "version: 1\nservices:\n  app: { build: . }\n")
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+
+	f := newFakeDeployServer(t)
+	if _, err := Run(Options{APIURL: f.srv.URL, APIToken: "tok", Path: dir, Builder: BuilderBuildpacks}, nil); err == nil {
+		t.Fatal("want an error for --builder with dibbla.yaml")
+	}
+}
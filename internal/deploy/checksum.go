@@ -0,0 +1,31 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ArchiveChecksumHeader carries the hex SHA-256 of the uploaded archive. The
+// CLI sets it on the deploy request so the server can reject a truncated or
+// corrupted upload before building, and the server may echo back the
+// checksum of what it actually received on the response.
+const ArchiveChecksumHeader = "X-Archive-SHA256"
+
+// archiveChecksum returns the hex SHA-256 of the raw archive bytes. Unlike
+// contentHash it covers the compressed stream exactly as sent.
+func archiveChecksum(archive []byte) string {
+	sum := sha256.Sum256(archive)
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyArchiveChecksum compares the checksum the server echoed back with
+// the one that was sent. Servers that don't echo a checksum are trusted.
+func verifyArchiveChecksum(sent, echoed string) error {
+	echoed = strings.TrimSpace(echoed)
+	if echoed == "" || strings.EqualFold(echoed, sent) {
+		return nil
+	}
+	return fmt.Errorf("archive checksum mismatch: sent %s, server received %s (the upload was corrupted in transit; retry the deploy)", sent, echoed)
+}
@@ -0,0 +1,74 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunSendsArchiveChecksum(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+
+	var header string
+	var archive []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(ArchiveChecksumHeader)
+		if err := r.ParseMultipartForm(50 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f, _, err := r.FormFile("archive")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		archive, _ = io.ReadAll(f)
+		w.Header().Set(ArchiveChecksumHeader, header)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"status":"success","deployment":{"id":"dep_x","alias":"shop","status":"running"}}`))
+	}))
+	defer srv.Close()
+
+	if _, err := Run(Options{APIURL: srv.URL, APIToken: "tok", Path: dir, Alias: "shop"}, nil); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	sum := sha256.Sum256(archive)
+	if want := hex.EncodeToString(sum[:]); header != want {
+		t.Errorf("%s: want %s, got %q", ArchiveChecksumHeader, want, header)
+	}
+}
+
+func TestRunFailsOnEchoedChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ArchiveChecksumHeader, strings.Repeat("0", 64))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"status":"success","deployment":{"id":"dep_x","alias":"shop","status":"running"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := Run(Options{APIURL: srv.URL, APIToken: "tok", Path: dir, Alias: "shop"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch error, got %v", err)
+	}
+}
+
+func TestVerifyArchiveChecksum(t *testing.T) {
+	sent := archiveChecksum([]byte("archive"))
+	if err := verifyArchiveChecksum(sent, ""); err != nil {
+		t.Errorf("missing echo should be accepted, got %v", err)
+	}
+	if err := verifyArchiveChecksum(sent, strings.ToUpper(sent)); err != nil {
+		t.Errorf("case-insensitive match should be accepted, got %v", err)
+	}
+	if err := verifyArchiveChecksum(sent, archiveChecksum([]byte("other"))); err == nil {
+		t.Error("expected mismatch error")
+	}
+}
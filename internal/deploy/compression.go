@@ -0,0 +1,102 @@
+package deploy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Archive compression formats accepted by Options.Compression.
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// errZstdUnsupported is returned by upload when the server answers a zstd
+// archive with 415, so Run can fall back to gzip.
+var errZstdUnsupported = errors.New("server does not accept zstd archives")
+
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// compression selects the archive codec and level. The zero value is gzip
+// at its default level, which is what createArchive and older servers use.
+type compression struct {
+	name  string
+	level int // 0 = codec default
+}
+
+func (c compression) validate() error {
+	switch c.name {
+	case "", CompressionGzip:
+		if c.level != 0 && (c.level < gzip.BestSpeed || c.level > gzip.BestCompression) {
+			return fmt.Errorf("gzip compression level must be between %d and %d", gzip.BestSpeed, gzip.BestCompression)
+		}
+	case CompressionZstd:
+		if c.level != 0 && (c.level < 1 || c.level > 22) {
+			return fmt.Errorf("zstd compression level must be between 1 and 22")
+		}
+	default:
+		return fmt.Errorf("unknown compression %q (expected gzip or zstd)", c.name)
+	}
+	return nil
+}
+
+// newWriter wraps w in the selected compressor. zstd encodes on all cores.
+func (c compression) newWriter(w io.Writer) (io.WriteCloser, error) {
+	if c.name == CompressionZstd {
+		opts := []zstd.EOption{}
+		if c.level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(c.level)))
+		}
+		return zstd.NewWriter(w, opts...)
+	}
+	level := c.level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// archiveCompression reports the codec of an archive from its magic bytes.
+func archiveCompression(archive []byte) string {
+	if bytes.HasPrefix(archive, zstdMagic) {
+		return CompressionZstd
+	}
+	return CompressionGzip
+}
+
+// decompress returns a reader over the tar stream inside a gzip or zstd
+// archive.
+func decompress(archive []byte) (io.ReadCloser, error) {
+	if archiveCompression(archive) == CompressionZstd {
+		zr, err := zstd.NewReader(bytes.NewReader(archive))
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return gzip.NewReader(bytes.NewReader(archive))
+}
+
+// recompressGzip re-encodes an archive as gzip, leaving the tar stream
+// (and therefore its content hash) untouched.
+func recompressGzip(archive []byte) ([]byte, error) {
+	rc, err := decompress(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	if _, err := io.Copy(gzw, rc); err != nil {
+		return nil, err
+	}
+	if err := gzw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,96 @@
+package deploy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestZstdArchiveHashesLikeGzip(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	writeFile(t, dir, "main.go", "package main\n")
+
+	gz, err := buildArchive(dir, archiveFilter{}, compression{name: CompressionGzip, level: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zst, err := buildArchive(dir, archiveFilter{}, compression{name: CompressionZstd, level: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := archiveCompression(gz); got != CompressionGzip {
+		t.Errorf("gzip archive detected as %s", got)
+	}
+	if got := archiveCompression(zst); got != CompressionZstd {
+		t.Errorf("zstd archive detected as %s", got)
+	}
+
+	hg, err := contentHash(gz, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hz, err := contentHash(zst, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hg != hz {
+		t.Errorf("content hash should not depend on compression: %s != %s", hg, hz)
+	}
+
+	back, err := recompressGzip(zst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := readTarEntries(t, back)["main.go"]; !ok {
+		t.Error("recompressed archive lost main.go")
+	}
+}
+
+func TestCompressionValidate(t *testing.T) {
+	for _, c := range []compression{{}, {name: "gzip", level: 1}, {name: "zstd", level: 22}} {
+		if err := c.validate(); err != nil {
+			t.Errorf("%+v: unexpected error %v", c, err)
+		}
+	}
+	for _, c := range []compression{{name: "gzip", level: 10}, {name: "zstd", level: 23}, {name: "brotli"}} {
+		if err := c.validate(); err == nil {
+			t.Errorf("%+v: expected error", c)
+		}
+	}
+}
+
+func TestRunFallsBackToGzipOn415(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(50 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, hdr, err := r.FormFile("archive")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		seen = append(seen, hdr.Filename+"|"+r.FormValue("compression"))
+		if r.FormValue("compression") == CompressionZstd {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"status":"success","deployment":{"id":"dep_x","alias":"shop","status":"running"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := Run(Options{APIURL: srv.URL, APIToken: "tok", Path: dir, Compression: CompressionZstd}, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	want := []string{"app.tar.zst|zstd", "app.tar.gz|"}
+	if len(seen) != 2 || seen[0] != want[0] || seen[1] != want[1] {
+		t.Errorf("uploads: want %v, got %v", want, seen)
+	}
+}
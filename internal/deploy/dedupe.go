@@ -0,0 +1,54 @@
+package deploy
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// dedupeIgnoredFields are upload fields that don't change what ends up
+// running, so they stay out of the content hash: a new deploy message or
+// --update on unchanged code is still "no changes".
+var dedupeIgnoredFields = map[string]bool{
+	"commit_message": true,
+	"force":          true,
+	"update":         true,
+	"async":          true,
+}
+
+// contentHash returns a deterministic SHA-256 over the archive's entries
+// (path, type, permission bits and content — not mtimes or compression)
+// plus the deploy settings in fields, hex-encoded. Two deploys of the same
+// tree with the same settings hash identically on any machine, which lets
+// the server recognise an upload that matches what is already running.
+func contentHash(archive []byte, fields []formField) (string, error) {
+	rc, err := decompress(archive)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%c\x00%o\x00%d\x00", hdr.Name, hdr.Typeflag, hdr.Mode&0o777, hdr.Size)
+		if _, err := io.Copy(h, tr); err != nil {
+			return "", err
+		}
+	}
+	for _, f := range fields {
+		if !dedupeIgnoredFields[f.name] {
+			fmt.Fprintf(h, "%s=%s\x00", f.name, f.value)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,72 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestContentHashIgnoresMtimeAndMessage(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	writeFile(t, dir, "main.go", "package main\n")
+
+	a, err := createArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "main.go"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	b, err := createArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ha, err := contentHash(a, []formField{{"app_name", "shop"}, {"commit_message", "first"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb, err := contentHash(b, []formField{{"app_name", "shop"}, {"commit_message", "second"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ha != hb {
+		t.Errorf("hash should ignore mtimes and deploy message: %s != %s", ha, hb)
+	}
+
+	hc, _ := contentHash(b, []formField{{"app_name", "shop"}, {"env_vars", `{"A":"1"}`}})
+	if hc == hb {
+		t.Error("hash should change when deploy settings change")
+	}
+
+	writeFile(t, dir, "main.go", "package main // edited\n")
+	c, _ := createArchive(dir)
+	hd, _ := contentHash(c, []formField{{"app_name", "shop"}})
+	if hd == ha {
+		t.Error("hash should change when file content changes")
+	}
+}
+
+func TestRunSendsContentHashUnlessForceUpload(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+
+	f := newFakeDeployServer(t)
+	if _, err := Run(Options{APIURL: f.srv.URL, APIToken: "tok", Path: dir}, nil); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(f.formVals["content_hash"]) != 64 {
+		t.Errorf("content_hash: want 64 hex chars, got %q", f.formVals["content_hash"])
+	}
+
+	g := newFakeDeployServer(t)
+	if _, err := Run(Options{APIURL: g.srv.URL, APIToken: "tok", Path: dir, ForceUpload: true}, nil); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if v, ok := g.formVals["content_hash"]; ok {
+		t.Errorf("--force-upload must not send content_hash, got %q", v)
+	}
+}
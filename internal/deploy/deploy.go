@@ -4,18 +4,23 @@ import (
 	"archive/tar"
 	"bufio"
 	"bytes"
-	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
 	"github.com/dibbla-agents/dibbla-cli/internal/deploy/render"
+	"github.com/dibbla-agents/dibbla-cli/internal/manifest"
 )
 
 // DeployResponse represents a successful deployment response
@@ -34,6 +39,7 @@ type Deployment struct {
 	ImageID     string       `json:"image_id"`
 	CreatedAt   string       `json:"created_at"`
 	DeployedAt  string       `json:"deployed_at"`
+	Region      string       `json:"region,omitempty"`
 	HealthCheck *HealthCheck `json:"health_check,omitempty"`
 	// Services is the per-service breakdown for multi-service deployments.
 	// Empty for legacy single-container deployments.
@@ -98,6 +104,9 @@ type ValidationError struct {
 
 // Options configures the deployment
 type Options struct {
+	// Context cancels archive upload and build streaming (Ctrl-C). Nil
+	// means context.Background().
+	Context  context.Context
 	APIURL   string
 	APIToken string
 	Path     string
@@ -109,7 +118,15 @@ type Options struct {
 	CPU        string   // e.g. 500m
 	Memory     string   // e.g. 512Mi
 	Port       string   // e.g. 3000
+	HealthPath string   // HTTP path probed for readiness, e.g. /healthz
 	FaviconURL string   // e.g. https://example.com/favicon.ico
+	Replicas   int      // initial replica count; 0 leaves it to the server default
+	Schedule   string   // cron expression; deploys a scheduled job instead of a long-running service
+	BuildArgs  []string // KEY=value Docker build args; merged over build_args in dibbla.yaml
+	Region     string   // e.g. eu-west; empty leaves it to the server default
+	// Builder is BuilderDockerfile, BuilderBuildpacks, or "" to build with
+	// buildpacks only when Path has neither a Dockerfile nor dibbla.yaml.
+	Builder string
 	// Login guard settings
 	RequireLogin    bool     // Require authentication to access the app
 	AccessPolicy    string   // "all_members" or "invite_only"
@@ -131,11 +148,86 @@ type Options struct {
 	TargetEnv string
 	Profiles  []string
 	NoPublic  bool
+
+	// Archive, when set, is uploaded as-is instead of building one from
+	// Path: a pre-built tar.gz from a file or stdin. Local manifest
+	// validation and the archive filter are skipped; Path still supplies
+	// the default app name.
+	Archive io.Reader
+
+	// Strategy is "" (replace the running revision) or StrategyCanary.
+	// CanaryWeight is the canary's traffic percentage; 0 means
+	// DefaultCanaryWeight.
+	Strategy     string
+	CanaryWeight int
+
+	// OnArchive, when set, is called with the archive's file manifest
+	// (see ArchiveFiles) after it is built and before upload. Returning an
+	// error aborts the deploy with that error.
+	OnArchive func(files map[string]string) error
+
+	// PreviewBranch marks the deploy as an ephemeral preview of the app
+	// PreviewOf for that git branch. Alias should already be the derived
+	// preview alias (see PreviewAlias).
+	PreviewBranch string
+	PreviewOf     string
+
+	// Compression is CompressionGzip (default when empty) or
+	// CompressionZstd; CompressionLevel 0 means the codec default. A server
+	// that rejects zstd with 415 gets the same archive re-sent as gzip.
+	Compression      string
+	CompressionLevel int
+
+	// Include and Exclude are extra archive globs applied on top of the
+	// built-in exclusions for this invocation only. Include wins.
+	Include []string
+	Exclude []string
+	// RespectGitignore leaves .gitignore'd paths out of the archive. nil
+	// defers to archive.respect_gitignore in dibbla.yaml.
+	RespectGitignore *bool
+	// FollowSymlinks archives the targets of symlinks that point outside
+	// the deploy root instead of skipping them.
+	FollowSymlinks bool
+
+	// NoWait asks the server to accept the upload and build in the
+	// background. The response (202) carries the deployment ID and an
+	// in-progress status; poll it with GetDeploymentStatus. Pass a nil
+	// renderer — there is no event stream to render.
+	NoWait bool
+
+	// ForceUpload skips the content-hash dedupe key, so the server
+	// rebuilds even when the same content is already running. Force
+	// implies it.
+	ForceUpload bool
+
+	// AllowSecrets uploads the archive even when ScanArchive finds
+	// possible secrets in it (--allow-secrets).
+	AllowSecrets bool
+
+	// Retries is how many times the upload is retried after a connection
+	// reset, timeout or 502/503/504 before the stream starts. nil defers
+	// to DIBBLA_RETRIES (default 3).
+	Retries *int
+}
+
+func (o Options) ctx() context.Context {
+	if o.Context != nil {
+		return o.Context
+	}
+	return context.Background()
+}
+
+func (o Options) retries() int {
+	if o.Retries != nil {
+		return *o.Retries
+	}
+	return apiclient.Retries()
 }
 
 // excludedPaths are paths that should not be included in the archive
 var excludedPaths = []string{
 	".git",
+	".dibbla",
 	"node_modules",
 	".env.production",
 	".env.prod",
@@ -178,28 +270,116 @@ func Run(opts Options, r render.Renderer) (*DeployResponse, error) {
 		return nil, fmt.Errorf("invalid path: %w", err)
 	}
 
-	// Multi-service: detect dibbla.yaml/dibbla.yml at the project root and
-	// validate locally so common mistakes fail before the archive upload.
-	// The server is authoritative; this is a best-effort fast path.
-	if err := validateLocalManifest(absPath); err != nil {
+	if err := ValidateStrategy(opts.Strategy, opts.CanaryWeight); err != nil {
 		return nil, err
 	}
-
-	archive, err := createArchive(absPath)
+	if err := ValidateBuilder(opts.Builder); err != nil {
+		return nil, err
+	}
+	buildArgs, err := resolveBuildArgs(opts.BuildArgs, absPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create archive: %w", err)
+		return nil, err
+	}
+
+	var archive []byte
+	if opts.Archive != nil {
+		if archive, err = readPrebuiltArchive(opts.Archive); err != nil {
+			return nil, err
+		}
+	} else {
+		// Multi-service: detect dibbla.yaml/dibbla.yml at the project root and
+		// validate locally so common mistakes fail before the archive upload.
+		// The server is authoritative; this is a best-effort fast path.
+		if err := validateLocalManifest(absPath); err != nil {
+			return nil, err
+		}
+		if _, _, found := manifest.Discover(absPath); found && opts.Builder != "" {
+			return nil, fmt.Errorf("--builder does not apply to dibbla.yaml deploys; each service builds from its own Dockerfile")
+		}
+		opts.Builder = ResolveBuilder(opts.Builder, absPath)
+
+		if archive, err = projectArchive(opts, absPath); err != nil {
+			return nil, err
+		}
 	}
 
-	if len(archive) > 50*1024*1024 {
+	if len(archive) > maxArchiveSize {
 		return nil, fmt.Errorf("archive size (%d MB) exceeds 50 MB limit", len(archive)/(1024*1024))
 	}
 
+	if !opts.AllowSecrets {
+		findings, err := ScanArchive(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan archive: %w", err)
+		}
+		if len(findings) > 0 {
+			return nil, &LeakError{Findings: findings}
+		}
+	}
+
+	if opts.OnArchive != nil {
+		files, err := ArchiveFiles(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if err := opts.OnArchive(files); err != nil {
+			return nil, err
+		}
+	}
+
 	appName := filepath.Base(absPath)
 	if opts.Alias != "" {
 		appName = opts.Alias
 	}
 
-	return upload(opts, archive, appName, r)
+	fields := deployFields(opts, appName)
+	if buildArgs != "" {
+		fields = append(fields, formField{"build_args", buildArgs})
+	}
+	if !opts.ForceUpload && !opts.Force {
+		hash, err := contentHash(archive, fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash archive: %w", err)
+		}
+		fields = append(fields, formField{"content_hash", hash})
+	}
+
+	resp, err := upload(opts, archive, fields, r)
+	if errors.Is(err, errZstdUnsupported) {
+		fmt.Fprintln(os.Stderr, "note: the server does not accept zstd archives; retrying with gzip")
+		if archive, err = recompressGzip(archive); err != nil {
+			return nil, fmt.Errorf("failed to recompress archive: %w", err)
+		}
+		return upload(opts, archive, fields, r)
+	}
+	return resp, err
+}
+
+// projectArchive builds the archive of the project at absPath with the
+// filter and compression in opts.
+func projectArchive(opts Options, absPath string) ([]byte, error) {
+	filter := archiveFilter{Include: opts.Include, Exclude: opts.Exclude, FollowSymlinks: opts.FollowSymlinks}
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	respect, err := respectGitignore(opts, absPath)
+	if err != nil {
+		return nil, err
+	}
+	if respect {
+		if filter.Gitignore, err = loadGitignore(absPath); err != nil {
+			return nil, err
+		}
+	}
+	comp := compression{name: opts.Compression, level: opts.CompressionLevel}
+	if err := comp.validate(); err != nil {
+		return nil, err
+	}
+	archive, err := buildArchive(absPath, filter, comp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	return archive, nil
 }
 
 // createArchive creates a tar.gz archive from the given directory.
@@ -212,9 +392,18 @@ func Run(opts Options, r render.Renderer) (*DeployResponse, error) {
 // of host files and also avoids tripping the backend's archive-safety check,
 // which rejects any symlink target containing "..".
 func createArchive(dir string) ([]byte, error) {
+	return buildArchive(dir, archiveFilter{}, compression{})
+}
+
+// buildArchive is createArchive with --include / --exclude globs applied
+// and the chosen compression.
+func buildArchive(dir string, filter archiveFilter, comp compression) ([]byte, error) {
 	var buf bytes.Buffer
-	gzw := gzip.NewWriter(&buf)
-	tw := tar.NewWriter(gzw)
+	cw, err := comp.newWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	tw := tar.NewWriter(cw)
 
 	rootAbs, err := filepath.Abs(dir)
 	if err != nil {
@@ -227,7 +416,7 @@ func createArchive(dir string) ([]byte, error) {
 		rootAbs = resolved
 	}
 
-	var skipped []string
+	skips := &archiveSkips{}
 
 	walkErr := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -246,27 +435,30 @@ func createArchive(dir string) ([]byte, error) {
 		}
 
 		// Check if path should be excluded
-		if shouldExclude(relPath, info) {
-			if info.IsDir() {
+		if exclude, descend := filter.excluded(relPath, info); exclude {
+			if info.IsDir() && !descend {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Symlink: dereference-if-within-root, skip-if-outside.
+		// Symlink: dereference-if-within-root, skip-if-outside (unless
+		// --follow-symlinks).
 		if info.Mode()&os.ModeSymlink != 0 {
 			visited := make(map[string]bool)
-			didSkip, serr := archiveSymlink(tw, path, relPath, rootAbs, visited)
-			if serr != nil {
+			if serr := archiveSymlink(tw, path, relPath, rootAbs, visited, filter, skips); serr != nil {
 				fmt.Fprintf(os.Stderr, "warning: skipping symlink %s: %v\n", relPath, serr)
-				return nil
-			}
-			if didSkip {
-				skipped = append(skipped, relPath)
 			}
 			return nil
 		}
 
+		// Sockets, FIFOs and devices can't be built from and would either
+		// fail the tar writer or hang a reader; leave them out.
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			skips.special = append(skips.special, relPath)
+			return nil
+		}
+
 		// Regular file or directory: write a standard header and content.
 		header, err := tar.FileInfoHeader(info, "")
 		if err != nil {
@@ -335,59 +527,84 @@ func createArchive(dir string) ([]byte, error) {
 		return nil, err
 	}
 
-	if err := gzw.Close(); err != nil {
+	if err := cw.Close(); err != nil {
 		return nil, err
 	}
 
-	if len(skipped) > 0 {
-		fmt.Fprintf(os.Stderr, "Skipped %d symlink(s) pointing outside the deploy root: %s\n",
-			len(skipped), strings.Join(skipped, ", "))
-	}
+	skips.report(os.Stderr, filter.FollowSymlinks)
 
 	return buf.Bytes(), nil
 }
 
+// archiveSkips collects the paths left out of the archive for reasons the
+// user may want to know about, reported once the walk is done.
+type archiveSkips struct {
+	symlinks []string // broken, or pointing outside the deploy root
+	special  []string // sockets, FIFOs, device files
+}
+
+func (s *archiveSkips) report(w io.Writer, followSymlinks bool) {
+	if len(s.symlinks) > 0 {
+		hint := " (--follow-symlinks includes their targets)"
+		if followSymlinks {
+			hint = ""
+		}
+		fmt.Fprintf(w, "Skipped %d broken or outside-root symlink(s)%s: %s\n",
+			len(s.symlinks), hint, strings.Join(s.symlinks, ", "))
+	}
+	if len(s.special) > 0 {
+		fmt.Fprintf(w, "warning: skipped %d special file(s) (socket, FIFO or device): %s\n",
+			len(s.special), strings.Join(s.special, ", "))
+	}
+}
+
 // archiveSymlink handles a single symlink encountered during the walk.
-// Returns skipped=true when nothing was written (target escaped root, broken
-// link, unsupported mode) so the caller can count it for the summary line.
+// When nothing is written (target escaped root, broken link, special file)
+// the path is recorded in skips for the summary line. With
+// filter.FollowSymlinks, targets outside the root are archived too.
 // A visited map is threaded through recursion so a self-referential directory
 // loop terminates; sibling symlinks to the same target each get a fresh map
 // from the top-level walker and are not de-duplicated across independent
 // dereference chains.
-func archiveSymlink(tw *tar.Writer, path, logicalPath, rootAbs string, visited map[string]bool) (skipped bool, err error) {
+func archiveSymlink(tw *tar.Writer, path, logicalPath, rootAbs string, visited map[string]bool, filter archiveFilter, skips *archiveSkips) error {
 	target, err := filepath.EvalSymlinks(path)
 	if err != nil {
 		// Broken, dangling, or cycle detected by Go's resolver — skip quietly.
-		return true, nil
+		skips.symlinks = append(skips.symlinks, logicalPath)
+		return nil
 	}
 	targetAbs, err := filepath.Abs(target)
 	if err != nil {
-		return true, err
+		skips.symlinks = append(skips.symlinks, logicalPath)
+		return err
 	}
-	if !isWithinRoot(targetAbs, rootAbs) {
-		return true, nil
+	if !filter.FollowSymlinks && !isWithinRoot(targetAbs, rootAbs) {
+		skips.symlinks = append(skips.symlinks, logicalPath)
+		return nil
 	}
 	if visited[targetAbs] {
 		// Already expanded in this dereference chain — prevents self-loop
-		// recursion. Not counted as "skipped" because the content is already
+		// recursion. Not counted as skipped because the content is already
 		// in the archive under a parent logical path.
-		return false, nil
+		return nil
 	}
 	visited[targetAbs] = true
 
 	targetInfo, err := os.Stat(target)
 	if err != nil {
-		return true, nil
+		skips.symlinks = append(skips.symlinks, logicalPath)
+		return nil
 	}
 
 	if targetInfo.Mode().IsRegular() {
-		return false, writeSymlinkedFile(tw, targetAbs, targetInfo, logicalPath)
+		return writeSymlinkedFile(tw, targetAbs, targetInfo, logicalPath)
 	}
 	if targetInfo.IsDir() {
-		return false, archiveSymlinkedDir(tw, targetAbs, logicalPath, rootAbs, visited)
+		return archiveSymlinkedDir(tw, targetAbs, logicalPath, rootAbs, visited, filter, skips)
 	}
 	// Sockets, devices, named pipes, etc.
-	return true, nil
+	skips.special = append(skips.special, logicalPath)
+	return nil
 }
 
 // writeSymlinkedFile emits a single regular-file tar entry at logicalPath,
@@ -413,10 +630,10 @@ func writeSymlinkedFile(tw *tar.Writer, targetAbs string, targetInfo os.FileInfo
 }
 
 // archiveSymlinkedDir walks a directory reached through a symlink and emits
-// tar entries under logicalPrefix. Sub-entries pass through shouldExclude and
+// tar entries under logicalPrefix. Sub-entries pass through the filter and
 // the same in-root check; sub-symlinks recurse via archiveSymlink so an
 // escaping or cyclic link inside a dereferenced tree is handled safely.
-func archiveSymlinkedDir(tw *tar.Writer, realRoot, logicalPrefix, archiveRootAbs string, visited map[string]bool) error {
+func archiveSymlinkedDir(tw *tar.Writer, realRoot, logicalPrefix, archiveRootAbs string, visited map[string]bool, filter archiveFilter, skips *archiveSkips) error {
 	topInfo, err := os.Stat(realRoot)
 	if err != nil {
 		return err
@@ -444,19 +661,23 @@ func archiveSymlinkedDir(tw *tar.Writer, realRoot, logicalPrefix, archiveRootAbs
 
 		logical := filepath.Join(logicalPrefix, rel)
 
-		if shouldExclude(logical, info) {
-			if info.IsDir() {
+		if exclude, descend := filter.excluded(logical, info); exclude {
+			if info.IsDir() && !descend {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
 		if info.Mode()&os.ModeSymlink != 0 {
-			if _, serr := archiveSymlink(tw, p, logical, archiveRootAbs, visited); serr != nil {
+			if serr := archiveSymlink(tw, p, logical, archiveRootAbs, visited, filter, skips); serr != nil {
 				fmt.Fprintf(os.Stderr, "warning: skipping symlink %s: %v\n", logical, serr)
 			}
 			return nil
 		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			skips.special = append(skips.special, logical)
+			return nil
+		}
 
 		header, herr := tar.FileInfoHeader(info, "")
 		if herr != nil {
@@ -545,66 +766,118 @@ func envPairsToJSON(pairs []string) string {
 	return string(b)
 }
 
-// upload sends the archive to the API. When r is non-nil it negotiates an
-// NDJSON streaming response by setting Accept: application/x-ndjson;
-// otherwise it reads the response as a single JSON object (legacy path).
-func upload(opts Options, archive []byte, appName string, r render.Renderer) (*DeployResponse, error) {
-	var body bytes.Buffer
-	writer := multipart.NewWriter(&body)
-
-	part, err := writer.CreateFormFile("archive", "app.tar.gz")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-	if _, err := part.Write(archive); err != nil {
-		return nil, fmt.Errorf("failed to write archive: %w", err)
-	}
+// formField is one multipart field of the deploy upload.
+type formField struct {
+	name, value string
+}
 
-	writeField := func(name, val string) error {
-		if val == "" {
-			return nil
+// deployFields returns the non-archive multipart fields for opts, in wire
+// order. Empty values are omitted.
+func deployFields(opts Options, appName string) []formField {
+	var fields []formField
+	add := func(name, val string) {
+		if val != "" {
+			fields = append(fields, formField{name, val})
 		}
-		return writer.WriteField(name, val)
 	}
 	if opts.Force {
-		_ = writeField("force", "true")
+		add("force", "true")
 	}
 	if opts.Update {
-		_ = writeField("update", "true")
+		add("update", "true")
 	}
-	_ = writeField("app_name", appName)
-	_ = writeField("commit_message", opts.Message)
+	add("app_name", appName)
+	add("commit_message", opts.Message)
 	if envJSON := envPairsToJSON(opts.Env); envJSON != "" {
-		_ = writeField("env_vars", envJSON)
+		add("env_vars", envJSON)
 	}
-	_ = writeField("cpu", opts.CPU)
-	_ = writeField("memory", opts.Memory)
-	_ = writeField("port", opts.Port)
-	_ = writeField("favicon_url", opts.FaviconURL)
+	add("cpu", opts.CPU)
+	add("memory", opts.Memory)
+	add("port", opts.Port)
+	add("health_path", opts.HealthPath)
+	add("builder", opts.Builder)
+	add("region", opts.Region)
+	if opts.Replicas > 0 {
+		add("replicas", strconv.Itoa(opts.Replicas))
+	}
+	add("schedule", opts.Schedule)
+	if opts.Strategy == StrategyCanary {
+		weight := opts.CanaryWeight
+		if weight == 0 {
+			weight = DefaultCanaryWeight
+		}
+		add("strategy", StrategyCanary)
+		add("canary_weight", strconv.Itoa(weight))
+	}
+	if opts.PreviewBranch != "" {
+		add("preview", "true")
+		add("preview_branch", opts.PreviewBranch)
+		add("preview_of", opts.PreviewOf)
+	}
+	add("favicon_url", opts.FaviconURL)
 	if opts.RequireLogin {
-		_ = writeField("require_login", "true")
+		add("require_login", "true")
 	}
-	_ = writeField("app_access_policy", opts.AccessPolicy)
+	add("app_access_policy", opts.AccessPolicy)
 	if len(opts.GoogleScopes) > 0 {
 		scopesJSON, _ := json.Marshal(opts.GoogleScopes)
-		_ = writeField("google_scopes", string(scopesJSON))
+		add("google_scopes", string(scopesJSON))
 	}
 	if len(opts.MicrosoftScopes) > 0 {
 		scopesJSON, _ := json.Marshal(opts.MicrosoftScopes)
-		_ = writeField("microsoft_scopes", string(scopesJSON))
+		add("microsoft_scopes", string(scopesJSON))
 	}
 
 	// Multi-service fields: only sent when explicitly set so the wire shape
 	// stays byte-identical for legacy callers (no manifest, no flags).
 	if opts.TargetEnv != "" {
-		_ = writeField("env", opts.TargetEnv)
+		add("env", opts.TargetEnv)
 	}
 	if len(opts.Profiles) > 0 {
 		profilesJSON, _ := json.Marshal(opts.Profiles)
-		_ = writeField("profiles", string(profilesJSON))
+		add("profiles", string(profilesJSON))
 	}
 	if opts.NoPublic {
-		_ = writeField("no_public", "true")
+		add("no_public", "true")
+	}
+	if opts.NoWait {
+		add("async", "true")
+	}
+	return fields
+}
+
+// upload sends the archive to the API. When r is non-nil it negotiates an
+// NDJSON streaming response by setting Accept: application/x-ndjson;
+// otherwise it reads the response as a single JSON object (legacy path).
+func upload(opts Options, archive []byte, fields []formField, r render.Renderer) (*DeployResponse, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	zstdArchive := archiveCompression(archive) == CompressionZstd
+	var (
+		part io.Writer
+		err  error
+	)
+	if zstdArchive {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="archive"; filename="app.tar.zst"`)
+		h.Set("Content-Type", "application/zstd")
+		part, err = writer.CreatePart(h)
+	} else {
+		part, err = writer.CreateFormFile("archive", "app.tar.gz")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(archive); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	for _, f := range fields {
+		_ = writer.WriteField(f.name, f.value)
+	}
+	if zstdArchive {
+		_ = writer.WriteField("compression", CompressionZstd)
 	}
 
 	if err := writer.Close(); err != nil {
@@ -615,23 +888,37 @@ func upload(opts Options, archive []byte, appName string, r render.Renderer) (*D
 	if opts.VerboseBuild {
 		url += "?verbose=1"
 	}
-	req, err := http.NewRequest("POST", url, &body)
+	req, err := http.NewRequestWithContext(opts.ctx(), "POST", url, &body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("Authorization", "Bearer "+opts.APIToken)
+	checksum := archiveChecksum(archive)
+	req.Header.Set(ArchiveChecksumHeader, checksum)
+	// The API creates one deployment per key, so a retried upload can't
+	// deploy twice.
+	apiclient.SetIdempotencyKey(req)
 	if r != nil {
 		req.Header.Set("Accept", "application/x-ndjson")
 	}
 
 	client := &http.Client{Timeout: 10 * time.Minute}
-	resp, err := client.Do(req)
+	resp, err := apiclient.DoWithRetries(client, req, opts.retries())
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// The echoed checksum arrives with the headers, so a corrupted upload
+	// is reported here rather than as a confusing build failure later on.
+	if err := verifyArchiveChecksum(checksum, resp.Header.Get(ArchiveChecksumHeader)); err != nil {
+		return nil, err
+	}
+	if zstdArchive && resp.StatusCode == http.StatusUnsupportedMediaType {
+		return nil, errZstdUnsupported
+	}
+
 	if r != nil && strings.Contains(resp.Header.Get("Content-Type"), "application/x-ndjson") {
 		return readStream(resp.Body, r)
 	}
@@ -646,7 +933,7 @@ func upload(opts Options, archive []byte, appName string, r render.Renderer) (*D
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
-	if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK {
+	if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK || (opts.NoWait && resp.StatusCode == http.StatusAccepted) {
 		var deployResp DeployResponse
 		if err := json.Unmarshal(respBody, &deployResp); err != nil {
 			return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -5,9 +5,13 @@ import (
 	"bytes"
 	"compress/gzip"
 	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -489,3 +493,68 @@ func TestCreateArchive_Symlink_Broken_Skipped(t *testing.T) {
 		t.Errorf("regular file should be present, got: %v", entryNames(entries))
 	}
 }
+
+func TestBuildArchive_FollowSymlinks_IncludesOutsideTarget(t *testing.T) {
+	dir := t.TempDir()
+	shared := t.TempDir()
+	if err := os.WriteFile(filepath.Join(shared, "common.txt"), []byte("shared lib"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(shared, filepath.Join(dir, "vendor")); err != nil {
+		t.Fatal(err)
+	}
+
+	archiveBytes, err := buildArchive(dir, archiveFilter{FollowSymlinks: true}, compression{})
+	if err != nil {
+		t.Fatalf("buildArchive: %v", err)
+	}
+	entries := readTarEntries(t, archiveBytes)
+	if e, ok := entries["vendor/common.txt"]; !ok || string(e.content) != "shared lib" {
+		t.Errorf("--follow-symlinks should archive vendor/common.txt, got: %v", entryNames(entries))
+	}
+}
+
+func TestCreateArchive_SpecialFile_Skipped(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "app.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer l.Close()
+	if err := os.WriteFile(filepath.Join(dir, "ok.txt"), []byte("ok"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archiveBytes, err := createArchive(dir)
+	if err != nil {
+		t.Fatalf("createArchive: %v", err)
+	}
+	entries := readTarEntries(t, archiveBytes)
+	if _, ok := entries["app.sock"]; ok {
+		t.Error("socket should be skipped")
+	}
+	if _, ok := entries["ok.txt"]; !ok {
+		t.Errorf("regular file should be present, got: %v", entryNames(entries))
+	}
+}
+
+func TestRunRetriesZeroSendsUploadOnce(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	noRetries := 0
+	if _, err := Run(Options{APIURL: srv.URL, APIToken: "tok", Path: dir, Retries: &noRetries}, nil); err == nil {
+		t.Fatal("expected error from 503")
+	}
+	if calls != 1 {
+		t.Errorf("--retries 0: want 1 attempt, got %d", calls)
+	}
+}
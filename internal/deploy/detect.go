@@ -0,0 +1,103 @@
+package deploy
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Framework is a recognized app framework and the deploy defaults it
+// implies. Empty fields leave the value to the server.
+type Framework struct {
+	Name       string
+	Port       string
+	HealthPath string
+	Memory     string
+}
+
+// frameworks is checked in order; the first match wins. Next.js comes
+// before Express because custom Next servers often depend on express too.
+var frameworks = []struct {
+	Framework
+	match func(dir string) bool
+}{
+	{Framework{Name: "Next.js", Port: "3000", HealthPath: "/", Memory: "1Gi"}, nodeDependency("next")},
+	{Framework{Name: "Express", Port: "3000", HealthPath: "/", Memory: "512Mi"}, nodeDependency("express")},
+	{Framework{Name: "Rails", Port: "3000", HealthPath: "/up", Memory: "1Gi"}, gemDependency("rails")},
+	{Framework{Name: "Flask", Port: "5000", HealthPath: "/", Memory: "512Mi"}, pythonDependency("flask")},
+	{Framework{Name: "Go", Port: "8080", HealthPath: "/", Memory: "256Mi"}, func(dir string) bool {
+		return fileExistsAt(filepath.Join(dir, "go.mod"))
+	}},
+}
+
+// DetectFramework recognizes the framework of the project at dir from its
+// package files (package.json, Gemfile, requirements.txt / pyproject.toml,
+// go.mod). It returns nil when nothing matches.
+func DetectFramework(dir string) *Framework {
+	for _, f := range frameworks {
+		if f.match(dir) {
+			fw := f.Framework
+			return &fw
+		}
+	}
+	return nil
+}
+
+func nodeDependency(name string) func(dir string) bool {
+	return func(dir string) bool {
+		data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+		if err != nil {
+			return false
+		}
+		var pkg struct {
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		if json.Unmarshal(data, &pkg) != nil {
+			return false
+		}
+		_, dep := pkg.Dependencies[name]
+		_, dev := pkg.DevDependencies[name]
+		return dep || dev
+	}
+}
+
+func gemDependency(name string) func(dir string) bool {
+	re := regexp.MustCompile(`^\s*gem\s+["']` + regexp.QuoteMeta(name) + `["']`)
+	return func(dir string) bool {
+		return anyLine(filepath.Join(dir, "Gemfile"), re.MatchString)
+	}
+}
+
+// pythonDependency matches a requirement line (flask, Flask==3.0, flask[async]>=2)
+// in requirements.txt or a quoted dependency in pyproject.toml.
+func pythonDependency(name string) func(dir string) bool {
+	req := regexp.MustCompile(`(?i)^\s*` + regexp.QuoteMeta(name) + `\s*($|[\[=<>~!;\s])`)
+	pyproject := regexp.MustCompile(`(?i)["']` + regexp.QuoteMeta(name) + `\s*($|[\[=<>~!;"'\s])`)
+	return func(dir string) bool {
+		return anyLine(filepath.Join(dir, "requirements.txt"), req.MatchString) ||
+			anyLine(filepath.Join(dir, "pyproject.toml"), pyproject.MatchString)
+	}
+}
+
+func anyLine(path string, match func(string) bool) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if match(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package deploy
+
+import "testing"
+
+func TestDetectFramework(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"next", map[string]string{"package.json": `{"dependencies":{"next":"14.0.0","express":"^4"}}`}, "Next.js"},
+		{"express", map[string]string{"package.json": `{"dependencies":{"express":"^4"}}`}, "Express"},
+		{"rails", map[string]string{"Gemfile": "source \"https://rubygems.org\"\ngem \"rails\", \"~> 7.1\"\n"}, "Rails"},
+		{"flask requirements", map[string]string{"requirements.txt": "# web\nFlask==3.0.0\ngunicorn\n"}, "Flask"},
+		{"flask pyproject", map[string]string{"pyproject.toml": "dependencies = [\"flask>=3\"]\n"}, "Flask"},
+		{"go", map[string]string{"go.mod": "module example.com/x\n"}, "Go"},
+		{"flask-cors only", map[string]string{"requirements.txt": "flask-cors\n"}, ""},
+		{"commented gem", map[string]string{"Gemfile": "# gem 'rails'\n"}, ""},
+		{"plain node", map[string]string{"package.json": `{"dependencies":{"lodash":"^4"}}`}, ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, dir, name, content)
+			}
+			fw := DetectFramework(dir)
+			got := ""
+			if fw != nil {
+				got = fw.Name
+			}
+			if got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHealthPathField(t *testing.T) {
+	fields := deployFields(Options{HealthPath: "/up"}, "shop")
+	for _, f := range fields {
+		if f.name == "health_path" {
+			if f.value != "/up" {
+				t.Errorf("health_path = %q", f.value)
+			}
+			return
+		}
+	}
+	t.Error("health_path field missing")
+}
@@ -0,0 +1,209 @@
+package deploy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/manifest"
+)
+
+// gitignoreRule is one non-comment line of a .gitignore file.
+type gitignoreRule struct {
+	base     string // directory of the .gitignore, relative to gitignore.top ("" for top)
+	segs     []string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// gitignore evaluates the .gitignore files that apply to a deploy root: the
+// ones inside it, plus those in ancestor directories up to the enclosing git
+// repository root, so a monorepo's top-level .gitignore still counts when
+// deploying one app from a subdirectory. Paths passed to ignored are relative
+// to the deploy root.
+type gitignore struct {
+	prefix string // deploy root relative to top, slash-separated ("" when equal)
+	rules  []gitignoreRule
+}
+
+// loadGitignore collects the .gitignore hierarchy for root. Rules are kept
+// in precedence order — outer directories before inner ones, file order
+// within a file — so the last matching rule decides, as in git.
+func loadGitignore(root string) (*gitignore, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	top := root
+	var ancestors []string
+	for dir := root; ; {
+		if fileOrDirExists(filepath.Join(dir, ".git")) {
+			top = dir
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			ancestors = nil // not inside a git repo: only the deploy root's own files count
+			break
+		}
+		dir = parent
+		ancestors = append([]string{dir}, ancestors...)
+	}
+
+	g := &gitignore{}
+	if top != root {
+		rel, _ := filepath.Rel(top, root)
+		g.prefix = filepath.ToSlash(rel)
+	}
+	for _, dir := range ancestors {
+		if err := g.addFile(top, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	err = filepath.Walk(root, func(p string, info os.FileInfo, werr error) error {
+		if werr != nil {
+			return werr
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		if rel != "." {
+			if shouldExclude(rel, info) || g.ignored(rel, true) {
+				return filepath.SkipDir
+			}
+		}
+		return g.addFile(top, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *gitignore) addFile(top, dir string) error {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read .gitignore: %w", err)
+	}
+	defer f.Close()
+
+	base, _ := filepath.Rel(top, dir)
+	base = filepath.ToSlash(base)
+	if base == "." {
+		base = ""
+	}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if r, ok := parseGitignoreLine(sc.Text()); ok {
+			r.base = base
+			g.rules = append(g.rules, r)
+		}
+	}
+	return sc.Err()
+}
+
+func parseGitignoreLine(line string) (gitignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+	var r gitignoreRule
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+	r.anchored = strings.Contains(line, "/")
+	r.segs = strings.Split(strings.TrimPrefix(line, "/"), "/")
+	return r, true
+}
+
+// ignored reports whether rel (relative to the deploy root) is ignored. As
+// in git, anything below an ignored directory is ignored too.
+func (g *gitignore) ignored(rel string, isDir bool) bool {
+	if g == nil || len(g.rules) == 0 {
+		return false
+	}
+	full := filepath.ToSlash(rel)
+	if g.prefix != "" {
+		full = g.prefix + "/" + full
+	}
+	segs := strings.Split(full, "/")
+	for i := 1; i < len(segs); i++ {
+		if g.match(segs[:i], true) {
+			return true
+		}
+	}
+	return g.match(segs, isDir)
+}
+
+func (g *gitignore) match(segs []string, isDir bool) bool {
+	ignored := false
+	for _, r := range g.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		local := segs
+		if r.base != "" {
+			baseSegs := strings.Split(r.base, "/")
+			if len(segs) <= len(baseSegs) || strings.Join(segs[:len(baseSegs)], "/") != r.base {
+				continue
+			}
+			local = segs[len(baseSegs):]
+		}
+		var hit bool
+		if r.anchored {
+			hit = globMatch(r.segs, local)
+		} else {
+			hit, _ = path.Match(r.segs[0], local[len(local)-1])
+		}
+		if hit {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// respectGitignore resolves whether the archive should honour .gitignore:
+// an explicit opts.RespectGitignore wins, otherwise the
+// archive.respect_gitignore toggle in dibbla.yaml decides.
+func respectGitignore(opts Options, projectRoot string) (bool, error) {
+	if opts.RespectGitignore != nil {
+		return *opts.RespectGitignore, nil
+	}
+	p, ambiguous, found := manifest.Discover(projectRoot)
+	if !found || ambiguous {
+		return false, nil
+	}
+	raw, err := os.ReadFile(p)
+	if err != nil {
+		return false, fmt.Errorf("read manifest: %w", err)
+	}
+	settings, err := manifest.LoadArchiveSettings(raw)
+	if err != nil {
+		return false, err
+	}
+	return settings.RespectGitignore, nil
+}
+
+func fileOrDirExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
@@ -0,0 +1,105 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitignoreRules(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "web", "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, ".gitignore", "# build output\n*.log\n/coverage\nbuild/\n!keep.log\n")
+	writeFile(t, dir, "web/.gitignore", "public/*.map\n")
+
+	g, err := loadGitignore(dir)
+	if err != nil {
+		t.Fatalf("loadGitignore: %v", err)
+	}
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"app.log", false, true},
+		{"web/debug.log", false, true},
+		{"keep.log", false, false},
+		{"coverage", true, true},
+		{"web/coverage", true, false},
+		{"build", true, true},
+		{"build/out.js", false, true},
+		{"build", false, false},
+		{"web/public/app.js.map", false, true},
+		{"public/app.js.map", false, false},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		if got := g.ignored(tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("ignored(%q, dir=%v) = %v, want %v", tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestGitignoreAppliesParentFilesInsideRepo(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	app := filepath.Join(repo, "apps", "api")
+	if err := os.MkdirAll(app, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, repo, ".gitignore", "dist/\napps/api/tmp\n")
+
+	g, err := loadGitignore(app)
+	if err != nil {
+		t.Fatalf("loadGitignore: %v", err)
+	}
+	if !g.ignored("dist", true) {
+		t.Error("expected repo-root dist/ rule to apply inside apps/api")
+	}
+	if !g.ignored("tmp", true) {
+		t.Error("expected anchored apps/api/tmp rule to apply")
+	}
+	if g.ignored("src", true) {
+		t.Error("src should not be ignored")
+	}
+}
+
+func TestRunRespectGitignoreFromManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "dibbla.yaml", "version: 1\narchive:\n  respect_gitignore: true\nservices:\n  web:\n    build: .\n    port: 3000\n    public: true\n")
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	writeFile(t, dir, ".gitignore", "secret.txt\n")
+	writeFile(t, dir, "secret.txt", "x")
+
+	ok, err := respectGitignore(Options{}, dir)
+	if err != nil || !ok {
+		t.Fatalf("respectGitignore from manifest: ok=%v err=%v", ok, err)
+	}
+	off := false
+	if ok, _ := respectGitignore(Options{RespectGitignore: &off}, dir); ok {
+		t.Error("explicit --respect-gitignore=false should override the manifest")
+	}
+
+	g, err := loadGitignore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := buildArchive(dir, archiveFilter{Gitignore: g}, compression{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := readTarEntries(t, archive)
+	if _, ok := entries["secret.txt"]; ok {
+		t.Error("gitignored secret.txt should not be archived")
+	}
+	if _, ok := entries["Dockerfile"]; !ok {
+		t.Error("Dockerfile missing from archive")
+	}
+}
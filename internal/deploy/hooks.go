@@ -0,0 +1,78 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/manifest"
+)
+
+// Hook phases, used both as section labels in output and as the value of
+// DIBBLA_HOOK_PHASE in the hook's environment.
+const (
+	HookPreDeploy  = "pre_deploy"
+	HookPostDeploy = "post_deploy"
+)
+
+// LoadHooks returns the hooks block of the dibbla.yaml/dibbla.yml at
+// projectRoot. Shell ${VAR} placeholders are resolved first, matching what
+// validateLocalManifest and the archive see. No manifest (or an ambiguous
+// pair, which validateLocalManifest reports) yields empty hooks.
+func LoadHooks(projectRoot string) (*manifest.Hooks, error) {
+	path, ambiguous, found := manifest.Discover(projectRoot)
+	if !found || ambiguous {
+		return &manifest.Hooks{}, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	subbed, err := SubstituteShellVarsFromOSEnv(raw)
+	if err != nil {
+		return nil, fmt.Errorf("manifest shell-var substitution: %w", err)
+	}
+	return manifest.LoadHooks(subbed)
+}
+
+// PostDeployEnv returns the extra environment passed to post_deploy hooks
+// so smoke tests can target the deployment that was just created.
+func PostDeployEnv(resp *DeployResponse) []string {
+	if resp == nil {
+		return nil
+	}
+	return []string{
+		"DIBBLA_DEPLOY_ID=" + resp.Deployment.ID,
+		"DIBBLA_DEPLOY_ALIAS=" + resp.Deployment.Alias,
+		"DIBBLA_DEPLOY_URL=" + resp.Deployment.URL,
+		"DIBBLA_DEPLOY_STATUS=" + resp.Deployment.Status,
+	}
+}
+
+// RunHooks runs cmds one at a time through the platform shell (sh -c, or
+// cmd /C on Windows) with dir as the working directory. Each command gets a
+// "==> phase [i/n]: cmd" section header, and its stdout/stderr are streamed
+// to out. The first non-zero exit stops the sequence and is returned.
+func RunHooks(phase string, cmds []string, dir string, env []string, out io.Writer) error {
+	for i, c := range cmds {
+		fmt.Fprintf(out, "==> %s [%d/%d]: %s\n", phase, i+1, len(cmds), c)
+		cmd := shellCommand(c)
+		cmd.Dir = dir
+		cmd.Env = append(append(os.Environ(), "DIBBLA_HOOK_PHASE="+phase), env...)
+		cmd.Stdout = out
+		cmd.Stderr = out
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", phase, c, err)
+		}
+	}
+	return nil
+}
+
+func shellCommand(c string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", c)
+	}
+	return exec.Command("sh", "-c", c)
+}
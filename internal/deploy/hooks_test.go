@@ -0,0 +1,64 @@
+package deploy
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLoadHooksNoManifest(t *testing.T) {
+	h, err := LoadHooks(t.TempDir())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(h.PreDeploy) != 0 || len(h.PostDeploy) != 0 {
+		t.Errorf("expected no hooks, got %+v", h)
+	}
+}
+
+func TestLoadHooksFromManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "dibbla.yaml", "version: 1\nservices:\n  app: { build: . }\nhooks:\n  pre_deploy: make build\n")
+	h, err := LoadHooks(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(h.PreDeploy) != 1 || h.PreDeploy[0] != "make build" {
+		t.Errorf("pre_deploy: %q", h.PreDeploy)
+	}
+}
+
+func TestRunHooksStopsAtFirstFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh syntax")
+	}
+	dir := t.TempDir()
+	var out bytes.Buffer
+	err := RunHooks(HookPreDeploy, []string{"echo first", "exit 3", "echo never"}, dir, nil, &out)
+	if err == nil {
+		t.Fatal("expected error from failing hook")
+	}
+	got := out.String()
+	if !strings.Contains(got, "==> pre_deploy [1/3]: echo first") || !strings.Contains(got, "first") {
+		t.Errorf("missing first section: %q", got)
+	}
+	if strings.Contains(got, "never") {
+		t.Errorf("hook after failure must not run: %q", got)
+	}
+}
+
+func TestRunHooksPassesPostDeployEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh syntax")
+	}
+	resp := &DeployResponse{Deployment: Deployment{ID: "dep_1", Alias: "shop", URL: "https://shop.dibbla.com"}}
+	var out bytes.Buffer
+	err := RunHooks(HookPostDeploy, []string{`echo "$DIBBLA_HOOK_PHASE $DIBBLA_DEPLOY_URL"`}, t.TempDir(), PostDeployEnv(resp), &out)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !strings.Contains(out.String(), "post_deploy https://shop.dibbla.com") {
+		t.Errorf("env not passed: %q", out.String())
+	}
+}
@@ -167,7 +167,7 @@ func TestRunNoManifestPreservesLegacyMultipart(t *testing.T) {
 	if err != nil {
 		t.Fatalf("run: %v", err)
 	}
-	for _, newField := range []string{"env", "profiles", "no_public"} {
+	for _, newField := range []string{"env", "profiles", "no_public", "replicas", "schedule", "preview", "strategy"} {
 		if _, ok := f.formVals[newField]; ok {
 			t.Errorf("legacy upload should not include %s field; got %q", newField, f.formVals[newField])
 		}
@@ -180,6 +180,25 @@ func TestRunNoManifestPreservesLegacyMultipart(t *testing.T) {
 	}
 }
 
+func TestRunReplicasSerialized(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	f := newFakeDeployServer(t)
+	_, err := Run(Options{
+		APIURL:   f.srv.URL,
+		APIToken: "tok",
+		Path:     dir,
+		Alias:    "shop",
+		Replicas: 3,
+	}, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := f.formVals["replicas"]; got != "3" {
+		t.Errorf("replicas field: want 3, got %q", got)
+	}
+}
+
 func TestValidateLocalManifestNoOpWhenAbsent(t *testing.T) {
 	dir := t.TempDir()
 	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
@@ -202,3 +221,44 @@ services:
 		t.Errorf("valid manifest should pass, got %v", err)
 	}
 }
+
+func TestRunCanaryStrategySerialized(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	f := newFakeDeployServer(t)
+	_, err := Run(Options{
+		APIURL:   f.srv.URL,
+		APIToken: "tok",
+		Path:     dir,
+		Alias:    "shop",
+		Strategy: StrategyCanary,
+	}, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := f.formVals["strategy"]; got != "canary" {
+		t.Errorf("strategy field: want canary, got %q", got)
+	}
+	if got := f.formVals["canary_weight"]; got != "10" {
+		t.Errorf("canary_weight field: want default 10, got %q", got)
+	}
+}
+
+func TestRunRejectsInvalidCanaryWeight(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	f := newFakeDeployServer(t)
+	for _, opts := range []Options{
+		{Strategy: StrategyCanary, CanaryWeight: 100},
+		{CanaryWeight: 20},
+		{Strategy: "blue-green"},
+	} {
+		opts.APIURL, opts.APIToken, opts.Path = f.srv.URL, "tok", dir
+		if _, err := Run(opts, nil); err == nil {
+			t.Errorf("%+v: expected validation error", opts)
+		}
+	}
+	if atomic.LoadInt32(&f.called) != 0 {
+		t.Error("server should NOT have been called")
+	}
+}
@@ -0,0 +1,50 @@
+package deploy
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+)
+
+// maxArchiveSize is the largest archive the deploy API accepts.
+const maxArchiveSize = 50 * 1024 * 1024
+
+// readPrebuiltArchive reads a caller-supplied tar.gz or tar.zst (see
+// Options.Archive)
+// and checks that it really is one, so a wrong file fails here instead of
+// as a confusing server-side build error. Reading stops just past the size
+// limit rather than buffering an arbitrarily large stdin.
+func readPrebuiltArchive(r io.Reader) ([]byte, error) {
+	archive, err := io.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if len(archive) == 0 {
+		return nil, fmt.Errorf("archive is empty")
+	}
+	if len(archive) > maxArchiveSize {
+		return nil, fmt.Errorf("archive size exceeds 50 MB limit")
+	}
+
+	rc, err := decompress(archive)
+	if err != nil {
+		return nil, fmt.Errorf("archive is not gzip- or zstd-compressed: %w", err)
+	}
+	defer rc.Close()
+	tr := tar.NewReader(rc)
+	entries := 0
+	for {
+		_, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("archive is not a valid compressed tar: %w", err)
+		}
+		entries++
+	}
+	if entries == 0 {
+		return nil, fmt.Errorf("archive contains no files")
+	}
+	return archive, nil
+}
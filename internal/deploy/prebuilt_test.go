@@ -0,0 +1,82 @@
+package deploy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunUploadsPrebuiltArchiveAsIs(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, src, "Dockerfile", "FROM scratch\n")
+	archive, err := createArchive(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(50 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f, _, err := r.FormFile("archive")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		got, _ = io.ReadAll(f)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"status":"success","deployment":{"id":"dep_x","alias":"shop","status":"running"}}`))
+	}))
+	defer srv.Close()
+
+	// Path points at an empty dir: nothing may be archived from it.
+	_, err = Run(Options{
+		APIURL:   srv.URL,
+		APIToken: "tok",
+		Path:     t.TempDir(),
+		Alias:    "shop",
+		Archive:  bytes.NewReader(archive),
+	}, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !bytes.Equal(got, archive) {
+		t.Errorf("uploaded archive differs from the one supplied (%d vs %d bytes)", len(got), len(archive))
+	}
+}
+
+func TestReadPrebuiltArchiveRejectsGarbage(t *testing.T) {
+	cases := map[string]struct {
+		in   []byte
+		want string
+	}{
+		"empty":     {nil, "empty"},
+		"not gzip":  {[]byte("PK\x03\x04 zip file"), "not gzip- or zstd-compressed"},
+		"gzip only": {gzipBytes(t, []byte(strings.Repeat("x", 1024))), "not a valid compressed tar"},
+	}
+	for name, tc := range cases {
+		_, err := readPrebuiltArchive(bytes.NewReader(tc.in))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: want error containing %q, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
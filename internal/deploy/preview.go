@@ -2,6 +2,7 @@ package deploy
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
 )
 
 // PreviewService mirrors deploy-api PreviewService so consumers can decode
@@ -54,6 +57,7 @@ type PreviewResponse struct {
 
 // PreviewOptions selects what to preview.
 type PreviewOptions struct {
+	Context   context.Context // nil means context.Background()
 	APIURL    string
 	APIToken  string
 	Path      string
@@ -134,7 +138,11 @@ func uploadPreview(opts PreviewOptions, archive []byte, alias string) (*PreviewR
 	}
 
 	url := strings.TrimSuffix(opts.APIURL, "/") + "/api/deploy/deployments/preview"
-	req, err := http.NewRequest(http.MethodPost, url, &body)
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -142,7 +150,7 @@ func uploadPreview(opts PreviewOptions, archive []byte, alias string) (*PreviewR
 	req.Header.Set("Authorization", "Bearer "+opts.APIToken)
 
 	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -11,6 +11,7 @@ import (
 // output in the design (cli-output.jsx:532-541 and 504-505).
 type JSONRenderer struct {
 	out       io.Writer
+	errOut    io.Writer
 	startedAt time.Time
 
 	prevRevision string // best-effort, populated from rollout-start source
@@ -19,7 +20,14 @@ type JSONRenderer struct {
 }
 
 func NewJSON(out io.Writer) *JSONRenderer {
-	return &JSONRenderer{out: out, startedAt: time.Now()}
+	return NewJSONSplit(out, out)
+}
+
+// NewJSONSplit is NewJSON with the failure object written to errOut instead
+// of out (`dibbla deploy --output json`), so stdout only ever carries a
+// successful result.
+func NewJSONSplit(out, errOut io.Writer) *JSONRenderer {
+	return &JSONRenderer{out: out, errOut: errOut, startedAt: time.Now()}
 }
 
 func (j *JSONRenderer) OnEvent(ev DeployEvent) {
@@ -35,13 +43,13 @@ func (j *JSONRenderer) OnDone() int {
 	enc := json.NewEncoder(j.out)
 	switch {
 	case j.errEv != nil:
-		_ = enc.Encode(structuredFailure(j.errEv))
+		WriteFailure(j.errOut, j.errEv)
 		if j.errEv.FailedStep != "" {
 			return 2
 		}
 		return 1
 	case j.result != nil:
-		_ = enc.Encode(map[string]any{
+		out := map[string]any{
 			"ok":         true,
 			"alias":      j.result.Deployment.Alias,
 			"url":        j.result.Deployment.URL,
@@ -49,7 +57,18 @@ func (j *JSONRenderer) OnDone() int {
 			"deploy_id":  j.result.Deployment.ID,
 			"vcs_commit": j.result.VCSCommit,
 			"elapsed_ms": time.Since(j.startedAt).Milliseconds(),
-		})
+			"deployment": j.result.Deployment,
+		}
+		if j.result.Unchanged() {
+			out["unchanged"] = true
+		}
+		_ = enc.Encode(out)
 	}
 	return 0
 }
+
+// WriteFailure encodes e as the single-line deploy.failed object shared by
+// the JSON and log renderers.
+func WriteFailure(w io.Writer, e *DeployError) {
+	_ = json.NewEncoder(w).Encode(structuredFailure(e))
+}
@@ -46,3 +46,44 @@ func TestJSON_Failure(t *testing.T) {
 		t.Errorf("step = %q, want go-build", ev.Step)
 	}
 }
+
+func TestJSONSplit_FailureGoesToErrOut(t *testing.T) {
+	var out, errOut bytes.Buffer
+	r := NewJSONSplit(&out, &errOut)
+	scriptedFailure(r)
+	if code := r.OnDone(); code != 2 {
+		t.Fatalf("OnDone = %d, want 2", code)
+	}
+	if out.Len() != 0 {
+		t.Errorf("stdout should be empty on failure, got %q", out.String())
+	}
+	var ev structuredFailureEvent
+	if err := json.Unmarshal(errOut.Bytes(), &ev); err != nil {
+		t.Fatalf("stderr is not valid JSON: %v\n%s", err, errOut.String())
+	}
+	if ev.Event != "deploy.failed" {
+		t.Errorf("event = %q, want deploy.failed", ev.Event)
+	}
+}
+
+func TestJSONSplit_HappyIncludesDeployment(t *testing.T) {
+	var out, errOut bytes.Buffer
+	r := NewJSONSplit(&out, &errOut)
+	scriptedHappy(r)
+	if code := r.OnDone(); code != 0 {
+		t.Fatalf("OnDone = %d, want 0", code)
+	}
+	var got struct {
+		OK         bool             `json:"ok"`
+		Deployment ResultDeployment `json:"deployment"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out.String())
+	}
+	if !got.OK || got.Deployment.Alias != "analytics-api" {
+		t.Errorf("unexpected result %+v", got)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("stderr should be empty on success, got %q", errOut.String())
+	}
+}
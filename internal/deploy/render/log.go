@@ -37,7 +37,10 @@ func (l *Log) OnEvent(ev DeployEvent) {
 		l.line("info", "rollout", "phase="+ev.State+joinFields(ev.Source))
 	case "result":
 		l.result = ev.Result
-		if l.result != nil {
+		if l.result.Unchanged() {
+			l.line("info", "deploy", fmt.Sprintf("status=unchanged url=%s alias=%s elapsed=%s",
+				l.result.Deployment.URL, l.result.Deployment.Alias, l.elapsed()))
+		} else if l.result != nil {
 			l.line("info", "deploy", fmt.Sprintf("status=ok url=%s alias=%s elapsed=%s",
 				l.result.Deployment.URL, l.result.Deployment.Alias, l.elapsed()))
 		}
@@ -125,7 +128,9 @@ func (l *Log) OnDone() int {
 		}
 		return 1
 	}
-	if l.result != nil {
+	if l.result.Unchanged() {
+		printlnTo(l.out, fmt.Sprintf("no changes, skipping  ·  %s  ·  %s", l.result.Deployment.URL, l.elapsed()))
+	} else if l.result != nil {
 		printlnTo(l.out, fmt.Sprintf("deploy ok  ·  %s  ·  %s", l.result.Deployment.URL, l.elapsed()))
 	}
 	return 0
@@ -45,6 +45,8 @@ func (q *Quiet) OnDone() int {
 			return 2
 		}
 		return 1
+	case q.result.Unchanged():
+		fmt.Fprintf(q.out, "✓ %s  ·  no changes, skipping\n", q.result.Deployment.Alias)
 	case q.result != nil:
 		// Append a "(N services)" suffix for multi-service deploys so quiet
 		// output reflects the new shape; legacy single-app deploys keep the
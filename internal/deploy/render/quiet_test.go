@@ -36,3 +36,18 @@ func TestQuiet_Failure(t *testing.T) {
 		t.Errorf("expected BUILD_FAILED in output, got: %s", buf.String())
 	}
 }
+
+func TestQuiet_Unchanged(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewQuiet(&buf)
+	r.OnEvent(DeployEvent{Type: "result", Result: &DeployResult{
+		Status:     ResultStatusUnchanged,
+		Deployment: ResultDeployment{Alias: "analytics-api", URL: "https://analytics-api.dibbla.com", Status: "running"},
+	}})
+	if code := r.OnDone(); code != 0 {
+		t.Fatalf("OnDone = %d, want 0", code)
+	}
+	if !strings.Contains(buf.String(), "no changes, skipping") {
+		t.Errorf("expected skip message, got: %s", buf.String())
+	}
+}
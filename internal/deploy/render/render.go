@@ -43,6 +43,16 @@ type DeployResult struct {
 	VCSCommit  string         `json:"vcs_commit,omitempty"`
 }
 
+// ResultStatusUnchanged is DeployResult.Status when the server matched the
+// upload's content hash to the build that is already running and skipped
+// the deploy.
+const ResultStatusUnchanged = "unchanged"
+
+// Unchanged reports whether the server skipped the deploy as a no-op.
+func (r *DeployResult) Unchanged() bool {
+	return r != nil && r.Status == ResultStatusUnchanged
+}
+
 type ResultDeployment struct {
 	ID       string        `json:"id"`
 	Alias    string        `json:"alias"`
@@ -291,6 +291,15 @@ func (t *TTY) finalize() {
 	if t.result == nil {
 		return
 	}
+	if t.result.Unchanged() {
+		fmt.Fprintln(t.w)
+		fmt.Fprintln(t.w, t.paint("NO CHANGES", colorBrand+colorBold))
+		t.prop("url", t.paint(t.result.Deployment.URL, colorCyan))
+		t.prop("alias", t.paint(t.result.Deployment.Alias, colorBright+colorBold))
+		fmt.Fprintln(t.w)
+		fmt.Fprintf(t.w, "  %s\n", t.paint("no changes, skipping · same content is already running (--force-upload to rebuild)", colorDim))
+		return
+	}
 	// BuildKit doesn't always emit a terminal "completed" event for its
 	// transient internal vertices (load build definition, load
 	// .dockerignore, load build context). Those steps stick at "running"
@@ -0,0 +1,238 @@
+package deploy
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Finding is a possible secret in a file about to be uploaded. Line is 0
+// for findings about the file as a whole, such as an env file.
+type Finding struct {
+	Path string `json:"path"`
+	Line int    `json:"line,omitempty"`
+	Rule string `json:"rule"`
+	// Excerpt is the start of the match, cut short so the secret itself
+	// is never printed.
+	Excerpt string `json:"excerpt,omitempty"`
+}
+
+// String formats f as path:line: description (excerpt…).
+func (f Finding) String() string {
+	where := f.Path
+	if f.Line > 0 {
+		where = fmt.Sprintf("%s:%d", f.Path, f.Line)
+	}
+	s := where + ": " + ruleDescriptions[f.Rule]
+	if f.Excerpt != "" {
+		s += " (" + f.Excerpt + ")"
+	}
+	return s
+}
+
+// Scan rules, as reported in Finding.Rule.
+const (
+	RuleEnvFile      = "env-file"
+	RulePrivateKey   = "private-key"
+	RuleAWSAccessKey = "aws-access-key"
+	RuleHighEntropy  = "high-entropy"
+)
+
+var ruleDescriptions = map[string]string{
+	RuleEnvFile:      "env file",
+	RulePrivateKey:   "private key",
+	RuleAWSAccessKey: "AWS access key",
+	RuleHighEntropy:  "high-entropy value assigned to a secret-looking name",
+}
+
+var (
+	privateKeyRe   = regexp.MustCompile(`-----BEGIN ((RSA|EC|DSA|OPENSSH|ENCRYPTED|PGP) )?PRIVATE KEY( BLOCK)?-----`)
+	awsAccessKeyRe = regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)
+	// secretAssignRe matches a value assigned to a name that suggests a
+	// credential, e.g. API_KEY=..., "clientSecret": "...".
+	secretAssignRe = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|pwd|api[_-]?key|access[_-]?key|auth|credential)[a-z0-9_-]*["']?\s*[:=]\s*["'` + "`" + `]?([A-Za-z0-9+/=_-]{20,})`)
+)
+
+// Files larger than maxScanSize, or that look binary, are not scanned.
+const (
+	maxScanSize = 2 << 20
+	// minSecretEntropy is the Shannon entropy, in bits per character, a
+	// value needs to count as a generated secret rather than a word.
+	minSecretEntropy = 3.5
+)
+
+// envFileTemplates are env file suffixes meant to be committed, holding
+// placeholders rather than values.
+var envFileTemplates = []string{".example", ".sample", ".template", ".dist", ".defaults"}
+
+// ScanArchive looks for secrets that should not be uploaded in the files of
+// a gzip or zstd tar archive: env files, private key blocks, AWS access
+// keys and high-entropy values assigned to names like API_KEY or
+// password. Findings are sorted by path and line.
+func ScanArchive(archive []byte) ([]Finding, error) {
+	rc, err := decompress(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var findings []Finding
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if isEnvFile(hdr.Name) {
+			findings = append(findings, Finding{Path: hdr.Name, Rule: RuleEnvFile})
+			continue
+		}
+		if hdr.Size > maxScanSize {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, scanContent(hdr.Name, content)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// ScanProject builds the archive `dibbla deploy` would upload for the
+// project at opts.Path, with the same exclusions and filters, and scans
+// it with ScanArchive.
+func ScanProject(opts Options) ([]Finding, error) {
+	p := opts.Path
+	if p == "" {
+		p = "."
+	}
+	absPath, err := filepath.Abs(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+	archive, err := projectArchive(opts, absPath)
+	if err != nil {
+		return nil, err
+	}
+	return ScanArchive(archive)
+}
+
+// isEnvFile reports whether name is a dotenv file with real values: .env
+// or .env.<something>, but not a template such as .env.example.
+func isEnvFile(name string) bool {
+	base := path.Base(name)
+	if base != ".env" && !strings.HasPrefix(base, ".env.") {
+		return false
+	}
+	for _, suffix := range envFileTemplates {
+		if strings.HasSuffix(base, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// scanContent applies the line-based rules to one text file.
+func scanContent(name string, content []byte) []Finding {
+	if bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0 {
+		return nil
+	}
+	var findings []Finding
+	for i, line := range strings.Split(string(content), "\n") {
+		n := i + 1
+		if m := privateKeyRe.FindString(line); m != "" {
+			findings = append(findings, Finding{Path: name, Line: n, Rule: RulePrivateKey, Excerpt: m})
+		}
+		for _, m := range awsAccessKeyRe.FindAllString(line, -1) {
+			if !strings.HasSuffix(m, "EXAMPLE") {
+				findings = append(findings, Finding{Path: name, Line: n, Rule: RuleAWSAccessKey, Excerpt: redact(m)})
+			}
+		}
+		for _, m := range secretAssignRe.FindAllStringSubmatch(line, -1) {
+			value := m[3]
+			if awsAccessKeyRe.MatchString(value) || !looksGenerated(value) {
+				continue
+			}
+			findings = append(findings, Finding{Path: name, Line: n, Rule: RuleHighEntropy, Excerpt: redact(value)})
+		}
+	}
+	return findings
+}
+
+// looksGenerated reports whether value looks like a generated secret
+// rather than an identifier or a path: it mixes letters and digits and its
+// entropy is at least minSecretEntropy.
+func looksGenerated(value string) bool {
+	if strings.HasPrefix(value, "/") {
+		return false
+	}
+	hasLetter := strings.ContainsFunc(value, func(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' })
+	hasDigit := strings.ContainsAny(value, "0123456789")
+	return hasLetter && hasDigit && shannonEntropy(value) >= minSecretEntropy
+}
+
+// shannonEntropy is the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	var h float64
+	n := float64(len(s))
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// redact keeps the first four characters of a secret.
+func redact(s string) string {
+	if len(s) <= 4 {
+		return "…"
+	}
+	return s[:4] + "…"
+}
+
+// LeakError blocks a deploy whose archive contains possible secrets (see
+// ScanArchive).
+type LeakError struct {
+	Findings []Finding
+}
+
+// maxListedFindings caps how many findings LeakError lists.
+const maxListedFindings = 10
+
+func (e *LeakError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "deploy blocked: %d possible secret(s) in the files to upload:", len(e.Findings))
+	for i, f := range e.Findings {
+		if i == maxListedFindings {
+			fmt.Fprintf(&b, "\n  … and %d more (see 'dibbla scan')", len(e.Findings)-i)
+			break
+		}
+		b.WriteString("\n  " + f.String())
+	}
+	b.WriteString("\nremove them or leave them out with --exclude, or re-run with --allow-secrets if they are safe to upload")
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package deploy
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// stateDir holds per-alias records of the last successful deploy, relative
+// to the deploy root. It is never archived (see excludedPaths).
+const stateDir = ".dibbla/state"
+
+// DeployState records what the last successful deploy of an alias shipped.
+type DeployState struct {
+	Alias        string            `json:"alias"`
+	DeploymentID string            `json:"deployment_id,omitempty"`
+	DeployedAt   time.Time         `json:"deployed_at"`
+	Files        map[string]string `json:"files"` // path → SHA-256 of content
+}
+
+// FileDiff lists the paths that changed between two file manifests.
+type FileDiff struct {
+	Added    []string
+	Modified []string
+	Removed  []string
+}
+
+// Empty reports whether nothing changed.
+func (d FileDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Modified) == 0 && len(d.Removed) == 0
+}
+
+// ArchiveFiles returns the path → SHA-256 manifest of the regular files in
+// a gzip or zstd tar archive.
+func ArchiveFiles(archive []byte) (map[string]string, error) {
+	rc, err := decompress(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	files := map[string]string{}
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, tr); err != nil {
+			return nil, err
+		}
+		files[hdr.Name] = hex.EncodeToString(h.Sum(nil))
+	}
+	return files, nil
+}
+
+// DiffFiles compares the previous manifest with the current one. Each list
+// is sorted.
+func DiffFiles(prev, cur map[string]string) FileDiff {
+	var d FileDiff
+	for path, sum := range cur {
+		old, ok := prev[path]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, path)
+		case old != sum:
+			d.Modified = append(d.Modified, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := cur[path]; !ok {
+			d.Removed = append(d.Removed, path)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Modified)
+	sort.Strings(d.Removed)
+	return d
+}
+
+func statePath(root, alias string) string {
+	return filepath.Join(root, filepath.FromSlash(stateDir), alias+".json")
+}
+
+// LoadDeployState reads the last recorded deploy of alias under root. A
+// missing record returns (nil, nil).
+func LoadDeployState(root, alias string) (*DeployState, error) {
+	data, err := os.ReadFile(statePath(root, alias))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st DeployState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("invalid deploy state %s: %w", statePath(root, alias), err)
+	}
+	return &st, nil
+}
+
+// SaveDeployState records st as the last successful deploy of st.Alias.
+func SaveDeployState(root string, st DeployState) error {
+	path := statePath(root, st.Alias)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
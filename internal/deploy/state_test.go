@@ -0,0 +1,100 @@
+package deploy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiffFiles(t *testing.T) {
+	prev := map[string]string{"a.go": "1", "b.go": "2", "gone.txt": "3"}
+	cur := map[string]string{"a.go": "1", "b.go": "changed", "new.txt": "4", "aaa.txt": "5"}
+	d := DiffFiles(prev, cur)
+	want := FileDiff{
+		Added:    []string{"aaa.txt", "new.txt"},
+		Modified: []string{"b.go"},
+		Removed:  []string{"gone.txt"},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("want %+v, got %+v", want, d)
+	}
+	if !DiffFiles(cur, cur).Empty() {
+		t.Error("identical manifests should diff empty")
+	}
+}
+
+func TestArchiveFilesSkipsStateDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	if err := SaveDeployState(dir, DeployState{Alias: "shop", Files: map[string]string{}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	archive, err := createArchive(dir)
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	files, err := ArchiveFiles(archive)
+	if err != nil {
+		t.Fatalf("files: %v", err)
+	}
+	if len(files) != 1 || files["Dockerfile"] == "" {
+		t.Errorf("want only Dockerfile, got %v", files)
+	}
+}
+
+func TestDeployStateRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if st, err := LoadDeployState(dir, "shop"); err != nil || st != nil {
+		t.Fatalf("missing state: want (nil, nil), got (%v, %v)", st, err)
+	}
+	in := DeployState{
+		Alias:        "shop",
+		DeploymentID: "dep_1",
+		DeployedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Files:        map[string]string{"Dockerfile": "abc"},
+	}
+	if err := SaveDeployState(dir, in); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".dibbla", "state", "shop.json")); err != nil {
+		t.Fatalf("state file: %v", err)
+	}
+	out, err := LoadDeployState(dir, "shop")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !reflect.DeepEqual(*out, in) {
+		t.Errorf("want %+v, got %+v", in, *out)
+	}
+}
+
+func TestRunOnArchiveErrorAbortsUpload(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	f := newFakeDeployServer(t)
+
+	stop := errors.New("cancelled")
+	var got map[string]string
+	_, err := Run(Options{
+		APIURL:   f.srv.URL,
+		APIToken: "tok",
+		Path:     dir,
+		OnArchive: func(files map[string]string) error {
+			got = files
+			return stop
+		},
+	}, nil)
+	if !errors.Is(err, stop) {
+		t.Fatalf("want cancel error, got %v", err)
+	}
+	if atomic.LoadInt32(&f.called) != 0 {
+		t.Error("upload should not happen when OnArchive fails")
+	}
+	if _, ok := got["Dockerfile"]; !ok {
+		t.Errorf("OnArchive manifest missing Dockerfile: %v", got)
+	}
+}
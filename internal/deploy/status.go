@@ -0,0 +1,71 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// Terminal deployment statuses. Anything else (received, building,
+// starting, health_check, ...) means the deploy is still in progress.
+var terminalStatuses = map[string]bool{
+	"running":   true,
+	"failed":    true,
+	"unhealthy": true,
+	"deleted":   true,
+}
+
+// IsTerminalStatus reports whether a deployment status is final.
+func IsTerminalStatus(status string) bool {
+	return terminalStatuses[status]
+}
+
+// DeploymentStatus is the payload of GET /deployments/by-id/{id}: the
+// deployment plus, once it has failed, the server's error detail.
+type DeploymentStatus struct {
+	Deployment Deployment   `json:"deployment"`
+	Error      *ErrorDetail `json:"error,omitempty"`
+}
+
+// GetDeploymentStatus fetches the current state of the deployment with the
+// given ID, as returned by `dibbla deploy --no-wait`.
+func GetDeploymentStatus(ctx context.Context, apiURL, apiToken, id string) (*DeploymentStatus, error) {
+	url := fmt.Sprintf("%s/api/deploy/deployments/by-id/%s", strings.TrimSuffix(apiURL, "/"), id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		var out DeploymentStatus
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("decode response: %w (body=%s)", err, string(body))
+		}
+		return &out, nil
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+	}
+	return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
@@ -0,0 +1,61 @@
+package deploy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunNoWaitAccepts202(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM scratch\n")
+	var async, accept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseMultipartForm(50 << 20)
+		async = r.FormValue("async")
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"status":"accepted","deployment":{"id":"dep_1","alias":"shop","status":"building"}}`))
+	}))
+	defer srv.Close()
+
+	resp, err := Run(Options{APIURL: srv.URL, APIToken: "tok", Path: dir, NoWait: true}, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if async != "true" {
+		t.Errorf("async field: want true, got %q", async)
+	}
+	if strings.Contains(accept, "ndjson") {
+		t.Errorf("--no-wait must not negotiate a stream, Accept=%q", accept)
+	}
+	if resp.Deployment.ID != "dep_1" || resp.Deployment.Status != "building" {
+		t.Errorf("unexpected response: %+v", resp.Deployment)
+	}
+}
+
+func TestGetDeploymentStatus(t *testing.T) {
+	var sawPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path
+		w.Write([]byte(`{"deployment":{"id":"dep_1","alias":"shop","status":"failed"},"error":{"code":"BUILD_FAILED","message":"npm ci exited 1"}}`))
+	}))
+	defer srv.Close()
+
+	st, err := GetDeploymentStatus(context.Background(), srv.URL, "tok", "dep_1")
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if sawPath != "/api/deploy/deployments/by-id/dep_1" {
+		t.Errorf("path: %q", sawPath)
+	}
+	if st.Deployment.Status != "failed" || st.Error == nil || st.Error.Code != "BUILD_FAILED" {
+		t.Errorf("unexpected status: %+v", st)
+	}
+	if !IsTerminalStatus(st.Deployment.Status) || IsTerminalStatus("building") {
+		t.Error("IsTerminalStatus misclassified")
+	}
+}
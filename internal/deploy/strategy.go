@@ -0,0 +1,30 @@
+package deploy
+
+import "fmt"
+
+// StrategyCanary routes only CanaryWeight percent of traffic to the new
+// revision until it is promoted (`dibbla apps promote`) or aborted
+// (`dibbla apps abort-canary`).
+const StrategyCanary = "canary"
+
+// DefaultCanaryWeight is the canary's traffic share when none is given.
+const DefaultCanaryWeight = 10
+
+// ValidateStrategy checks a deploy strategy and canary weight. An empty
+// strategy is the default deploy; a weight without the canary strategy is
+// rejected rather than silently ignored.
+func ValidateStrategy(strategy string, weight int) error {
+	switch strategy {
+	case "":
+		if weight != 0 {
+			return fmt.Errorf("--canary-weight requires --strategy canary")
+		}
+	case StrategyCanary:
+		if weight != 0 && (weight < 1 || weight > 99) {
+			return fmt.Errorf("--canary-weight must be between 1 and 99, got %d", weight)
+		}
+	default:
+		return fmt.Errorf("unknown deploy strategy %q (supported: canary)", strategy)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/manifest"
+)
+
+// DiscoverApps returns the absolute app directories `dibbla deploy --all`
+// should deploy from root.
+//
+// When root has a dibbla.yaml with a workspace.apps list, that list is
+// authoritative and every entry must exist. Otherwise each immediate,
+// non-hidden subdirectory holding a Dockerfile or a dibbla.yaml/dibbla.yml
+// counts as an app. Results are sorted so sequential deploys and the result
+// table are stable across runs.
+func DiscoverApps(root string) ([]string, error) {
+	if path, ambiguous, found := manifest.Discover(root); found {
+		if ambiguous {
+			return nil, fmt.Errorf("both dibbla.yaml and dibbla.yml are present at %s; remove one", root)
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read manifest: %w", err)
+		}
+		ws, err := manifest.LoadWorkspace(raw)
+		if err != nil {
+			return nil, err
+		}
+		if ws != nil {
+			var dirs []string
+			for _, a := range ws.Apps {
+				dir := filepath.Join(root, filepath.FromSlash(a))
+				if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+					return nil, fmt.Errorf("workspace app %q: directory not found", a)
+				}
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			return dirs, nil
+		}
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || e.Name() == "node_modules" {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if _, _, found := manifest.Discover(dir); found || fileExistsAt(filepath.Join(dir, "Dockerfile")) {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no apps found under %s (add workspace.apps to dibbla.yaml, or give each app a Dockerfile)", root)
+	}
+	return dirs, nil
+}
+
+func fileExistsAt(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && !info.IsDir()
+}
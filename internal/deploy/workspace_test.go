@@ -0,0 +1,65 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiscoverAppsScansSubdirectories(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"web", "api", "docs", ".hidden"} {
+		if err := os.Mkdir(filepath.Join(root, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(root, "web"), "Dockerfile", "FROM scratch\n")
+	writeFile(t, filepath.Join(root, "api"), "dibbla.yaml", "version: 1\n")
+	writeFile(t, filepath.Join(root, ".hidden"), "Dockerfile", "FROM scratch\n")
+
+	dirs, err := DiscoverApps(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	want := []string{filepath.Join(root, "api"), filepath.Join(root, "web")}
+	if len(dirs) != len(want) || dirs[0] != want[0] || dirs[1] != want[1] {
+		t.Errorf("got %v, want %v", dirs, want)
+	}
+}
+
+func TestDiscoverAppsUsesWorkspaceList(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "apps", "shop"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A Dockerfile-bearing sibling that is not listed must be ignored.
+	if err := os.Mkdir(filepath.Join(root, "tools"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "tools"), "Dockerfile", "FROM scratch\n")
+	writeFile(t, root, "dibbla.yaml", "workspace:\n  apps:\n    - apps/shop\n")
+
+	dirs, err := DiscoverApps(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	if len(dirs) != 1 || dirs[0] != filepath.Join(root, "apps", "shop") {
+		t.Errorf("got %v", dirs)
+	}
+}
+
+func TestDiscoverAppsMissingWorkspaceDir(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "dibbla.yaml", "workspace:\n  apps: [nope]\n")
+	_, err := DiscoverApps(root)
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected missing-dir error, got %v", err)
+	}
+}
+
+func TestDiscoverAppsNoneFound(t *testing.T) {
+	if _, err := DiscoverApps(t.TempDir()); err == nil {
+		t.Fatal("expected error for empty workspace")
+	}
+}
@@ -0,0 +1,38 @@
+package env
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/joho/godotenv"
+)
+
+// ReadEnvFilePairs parses the dotenv files at paths (comments, single/double
+// quoting, and `export` prefixes are handled by godotenv) and returns their
+// variables as Docker-style KEY=value pairs. Later files override earlier
+// ones; within a file the pairs are sorted by key so the result is stable.
+//
+// Callers append their own -e pairs after the result, so explicit flags win
+// over file values when the pairs are folded into a map.
+func ReadEnvFilePairs(paths []string) ([]string, error) {
+	merged := map[string]string{}
+	for _, p := range paths {
+		vars, err := godotenv.Read(p)
+		if err != nil {
+			return nil, fmt.Errorf("read env file %s: %w", p, err)
+		}
+		for k, v := range vars {
+			merged[k] = v
+		}
+	}
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+merged[k])
+	}
+	return pairs, nil
+}
@@ -0,0 +1,50 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadEnvFilePairs_DotenvSyntax(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env.production")
+	content := "# comment\n\nexport NODE_ENV=production\nGREETING=\"hello world\"\nRAW='a=b#c'\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadEnvFilePairs([]string{path})
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := []string{"GREETING=hello world", "NODE_ENV=production", "RAW=a=b#c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReadEnvFilePairs_LaterFileWins(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.env")
+	b := filepath.Join(dir, "b.env")
+	if err := os.WriteFile(a, []byte("X=1\nY=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("Y=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadEnvFilePairs([]string{a, b})
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if want := []string{"X=1", "Y=2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReadEnvFilePairs_MissingFile(t *testing.T) {
+	if _, err := ReadEnvFilePairs([]string{filepath.Join(t.TempDir(), "nope.env")}); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
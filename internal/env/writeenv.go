@@ -1,7 +1,8 @@
-// Package env provides helpers for writing to .env files and patching
-// .gitignore entries. Used by `dibbla login --write-env` to materialize
-// credentials in a project's working directory without disturbing other
-// keys, comments, or ordering in existing files.
+// Package env provides helpers for reading and writing .env files and
+// patching .gitignore entries. Used by `dibbla login --write-env` to
+// materialize credentials in a project's working directory without
+// disturbing other keys, comments, or ordering in existing files, and by
+// the --env-file flags of `dibbla deploy` / `dibbla apps update`.
 package env
 
 import (
@@ -9,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 )
 
@@ -93,6 +95,31 @@ func MergeEnvFile(envPath string, updates map[string]string) ([]string, error) {
 	return written, nil
 }
 
+// WriteEnvFile replaces envPath with the given key/value pairs, one per
+// line in key order, below header. Each header line is written as a
+// "# " comment. Permissions and atomicity are as for MergeEnvFile.
+func WriteEnvFile(envPath string, header []string, values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, line := range header {
+		b.WriteString(strings.TrimRight("# "+line, " "))
+		b.WriteByte('\n')
+	}
+	if len(header) > 0 && len(keys) > 0 {
+		b.WriteByte('\n')
+	}
+	for _, k := range keys {
+		b.WriteString(formatLine(k, values[k]))
+		b.WriteByte('\n')
+	}
+	return atomicWrite(envPath, []byte(b.String()), 0600)
+}
+
 // EnsureGitignoreEntry guarantees that gitignorePath contains a line
 // exactly matching ".env" or "/.env" (case-sensitive, whitespace-trimmed).
 // If the file doesn't exist it's created containing ".env\n". If it exists
@@ -247,6 +247,33 @@ func TestMergeEnvFile_QuotesWhenNeeded(t *testing.T) {
 	}
 }
 
+func TestWriteEnvFile_HeaderAndSortedKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env.local")
+	if err := os.WriteFile(path, []byte("OLD=gone\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteEnvFile(path, []string{"Generated file", ""}, map[string]string{
+		"ZED":   "last",
+		"ALPHA": "has space ",
+	})
+	if err != nil {
+		t.Fatalf("WriteEnvFile: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "# Generated file\n#\n\nALPHA=\"has space \"\nZED=last\n"
+	if string(data) != want {
+		t.Errorf("got:\n%q\nwant:\n%q", data, want)
+	}
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(path)
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("perms: got %o, want 0600", perm)
+		}
+	}
+}
+
 func TestEnsureGitignoreEntry_NewFile(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, ".gitignore")
@@ -0,0 +1,141 @@
+// Package interrupt handles Ctrl-C (SIGINT) and SIGTERM for the whole CLI.
+//
+// The first signal cancels the root command context, which aborts every
+// in-flight API request made with it, then runs the registered cleanups
+// (stop spinners, remove partial output files) and exits with ExitCode.
+// Commands that stop cleanly on cancellation by themselves (log streaming,
+// local task runs) call Graceful and are left to return on their own; a
+// second signal still forces the exit.
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// ExitCode is the process exit code after an interrupt (128 + SIGINT, as
+// shells report it).
+const ExitCode = 130
+
+var (
+	mu       sync.Mutex
+	cleanups = map[int]func(){}
+	nextID   int
+
+	interrupted atomic.Bool
+	graceful    atomic.Bool
+
+	// exit and out are variables so tests can observe the shutdown.
+	exit           = os.Exit
+	out  io.Writer = os.Stderr
+)
+
+// Notify returns a copy of parent that is cancelled on SIGINT or SIGTERM
+// and starts the shutdown handler. Call stop to release the signal
+// handler when the command is done.
+func Notify(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sigs:
+		case <-done:
+			return
+		}
+		interrupted.Store(true)
+		cancel()
+		if graceful.Load() {
+			select {
+			case <-sigs:
+			case <-done:
+				return
+			}
+		}
+		Exit()
+	}()
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+			cancel()
+		})
+	}
+}
+
+// OnCleanup registers fn to run if the process is interrupted. Call the
+// returned remove once the resource no longer needs cleaning up.
+// Cleanups run most recently registered first.
+func OnCleanup(fn func()) (remove func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	id := nextID
+	nextID++
+	cleanups[id] = fn
+	return func() {
+		mu.Lock()
+		delete(cleanups, id)
+		mu.Unlock()
+	}
+}
+
+// Graceful marks the running command as stopping cleanly on context
+// cancellation, so the first signal only cancels and doesn't exit.
+func Graceful() {
+	graceful.Store(true)
+}
+
+// GracefulContext marks the running command as graceful (see Graceful)
+// and returns a copy of parent that is cancelled on SIGINT or SIGTERM, for
+// commands that wind down by themselves once their context ends. Call stop
+// when the command is done.
+func GracefulContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	Graceful()
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
+
+// Interrupted reports whether a shutdown signal has been received.
+func Interrupted() bool {
+	return interrupted.Load()
+}
+
+// Wait blocks forever once the process has been interrupted, leaving the
+// exit (and its exit code) to the signal handler; otherwise it returns
+// immediately. Call it where a cancelled request surfaces as an error, so
+// the command doesn't print "context canceled" and exit 1 first.
+func Wait() {
+	if Interrupted() && !graceful.Load() {
+		select {}
+	}
+}
+
+// Exit runs the registered cleanups, newest first, reports the interrupt
+// on stderr and exits with ExitCode.
+func Exit() {
+	mu.Lock()
+	ids := make([]int, 0, len(cleanups))
+	for id := range cleanups {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+	fns := make([]func(), len(ids))
+	for i, id := range ids {
+		fns[i] = cleanups[id]
+	}
+	cleanups = map[int]func(){}
+	mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+	fmt.Fprintln(out, "\nInterrupted.")
+	exit(ExitCode)
+}
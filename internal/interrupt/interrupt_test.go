@@ -0,0 +1,56 @@
+package interrupt
+
+import (
+	"context"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExitRunsCleanupsNewestFirst(t *testing.T) {
+	var order []string
+	OnCleanup(func() { order = append(order, "first") })
+	remove := OnCleanup(func() { order = append(order, "removed") })
+	OnCleanup(func() { order = append(order, "last") })
+	remove()
+
+	defer func(orig func(int)) { exit = orig }(exit)
+	code := -1
+	exit = func(c int) { code = c }
+	defer func(orig io.Writer) { out = orig }(out)
+	out = io.Discard
+	Exit()
+
+	if code != ExitCode {
+		t.Errorf("exit code: want %d, got %d", ExitCode, code)
+	}
+	if want := []string{"last", "first"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("cleanup order: want %v, got %v", want, order)
+	}
+}
+
+func TestWaitReturnsWhenNotInterrupted(t *testing.T) {
+	Wait() // must not block
+	if Interrupted() {
+		t.Error("Interrupted before any signal")
+	}
+}
+
+func TestGracefulContextCancelsOnSignal(t *testing.T) {
+	t.Cleanup(func() { graceful.Store(false) })
+	ctx, stop := GracefulContext(context.Background())
+	defer stop()
+
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(os.Interrupt); err != nil {
+		t.Skipf("can't signal self: %v", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled by SIGINT")
+	}
+	Wait() // graceful: must not block
+}
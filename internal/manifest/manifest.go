@@ -29,6 +29,7 @@ const (
 	ErrCodeDockerfileMissing     = "DOCKERFILE_MISSING"
 	ErrCodeStatefulNoVolume      = "STATEFUL_NO_VOLUME"
 	ErrCodeRouteInvalid          = "ROUTE_INVALID"
+	ErrCodeScheduleInvalid       = "SCHEDULE_INVALID"
 )
 
 // Error is the structured error returned by the CLI validator.
@@ -51,6 +52,63 @@ func (e *Error) Error() string {
 type Manifest struct {
 	Version  int                 `yaml:"version"`
 	Services map[string]*Service `yaml:"services"`
+	Jobs     map[string]*Job     `yaml:"jobs,omitempty"`
+	Hooks    *Hooks              `yaml:"hooks,omitempty"`
+	// Workspace marks a monorepo root. It lists app directories for
+	// `dibbla deploy --all`; a workspace root is not itself deployable.
+	Workspace *Workspace `yaml:"workspace,omitempty"`
+	// Archive tunes how the CLI packages the deploy root. Like hooks, it
+	// is read locally and ignored by deploy-api.
+	Archive *ArchiveSettings `yaml:"archive,omitempty"`
+	// BuildArgs are Docker build ARG values. The CLI reads them locally,
+	// merges in --build-arg, and forwards them with the upload.
+	BuildArgs map[string]string `yaml:"build_args,omitempty"`
+}
+
+// ArchiveSettings holds CLI-side archive options from dibbla.yaml.
+type ArchiveSettings struct {
+	// RespectGitignore leaves paths matched by the project's .gitignore
+	// files out of the upload (`dibbla deploy --respect-gitignore`).
+	RespectGitignore bool `yaml:"respect_gitignore"`
+}
+
+// Workspace lists the app directories, relative to the manifest, that
+// `dibbla deploy --all` deploys.
+type Workspace struct {
+	Apps []string `yaml:"apps"`
+}
+
+// Hooks are shell commands the CLI runs locally around `dibbla deploy`.
+// They never reach the server — the manifest is uploaded as-is, but
+// deploy-api ignores the block.
+type Hooks struct {
+	PreDeploy  HookCommands `yaml:"pre_deploy,omitempty"`
+	PostDeploy HookCommands `yaml:"post_deploy,omitempty"`
+}
+
+// HookCommands accepts either a single command string or a list of
+// commands, so `pre_deploy: npm run build` and the list form both work.
+type HookCommands []string
+
+// UnmarshalYAML implements yaml.Unmarshaler for the string-or-list form.
+func (h *HookCommands) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var s string
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		*h = HookCommands{s}
+		return nil
+	case yaml.SequenceNode:
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		*h = HookCommands(list)
+		return nil
+	}
+	return fmt.Errorf("line %d: hook must be a command string or a list of commands", node.Line)
 }
 
 // Service holds the per-service fields the CLI cares about for local
@@ -74,6 +132,20 @@ type Service struct {
 	Routes      []Route     `yaml:"routes,omitempty"`
 }
 
+// Job is a top-level jobs: entry — a cron-scheduled container that runs to
+// completion on each tick. Rendered server-side as a K8s CronJob.
+type Job struct {
+	Schedule                   string   `yaml:"schedule"`
+	Build                      any      `yaml:"build,omitempty"`
+	Image                      any      `yaml:"image,omitempty"`
+	Command                    []string `yaml:"command,omitempty"`
+	Environment                any      `yaml:"environment,omitempty"`
+	CPU                        any      `yaml:"cpu,omitempty"`
+	Memory                     any      `yaml:"memory,omitempty"`
+	SuccessfulJobsHistoryLimit *int     `yaml:"successful_jobs_history_limit,omitempty"`
+	FailedJobsHistoryLimit     *int     `yaml:"failed_jobs_history_limit,omitempty"`
+}
+
 // Volume is a per-service persistent volume entry.
 type Volume struct {
 	Path   string `yaml:"path"`
@@ -121,6 +193,7 @@ var (
 	serviceNameRe = regexp.MustCompile(`^[a-z][a-z0-9-]{0,29}$`)
 	imageWithTagRe = regexp.MustCompile(`^[^\s]+:[^\s/:@]+$`)
 	dnsLabelRe     = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	BuildArgNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 )
 
 var (
@@ -163,9 +236,13 @@ func ParseAndValidateBytes(data []byte) (*Manifest, error) {
 		return nil, &Error{Code: ErrCodeManifestInvalid, Path: "version",
 			Detail: fmt.Sprintf("unsupported manifest version %d (must be 1)", m.Version)}
 	}
-	if len(m.Services) == 0 {
+	if len(m.Services) == 0 && len(m.Jobs) == 0 && m.Workspace != nil {
+		return nil, &Error{Code: ErrCodeManifestInvalid, Path: "workspace",
+			Detail: "this is a workspace root and is not deployable itself; use `dibbla deploy --all`"}
+	}
+	if len(m.Services) == 0 && len(m.Jobs) == 0 {
 		return nil, &Error{Code: ErrCodeManifestInvalid, Path: "services",
-			Detail: "at least one service is required"}
+			Detail: "at least one service or job is required"}
 	}
 	for name, svc := range m.Services {
 		if err := validateServiceName(name); err != nil {
@@ -175,9 +252,125 @@ func ParseAndValidateBytes(data []byte) (*Manifest, error) {
 			return nil, err
 		}
 	}
+	for name, job := range m.Jobs {
+		if err := validateJob(name, job, m.Services); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateHooks(m.Hooks); err != nil {
+		return nil, err
+	}
+	if err := validateBuildArgs(m.BuildArgs); err != nil {
+		return nil, err
+	}
 	return &m, nil
 }
 
+// LoadHooks decodes only the hooks block from manifest bytes. It does not
+// run the full schema validation, so callers can pick up hooks before (and
+// independently of) ParseAndValidateBytes. A manifest without a hooks
+// block returns an empty, non-nil *Hooks.
+func LoadHooks(data []byte) (*Hooks, error) {
+	var m struct {
+		Hooks *Hooks `yaml:"hooks"`
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, &Error{Code: ErrCodeManifestInvalid, Path: "hooks", Detail: "yaml parse: " + err.Error()}
+	}
+	if err := validateHooks(m.Hooks); err != nil {
+		return nil, err
+	}
+	if m.Hooks == nil {
+		return &Hooks{}, nil
+	}
+	return m.Hooks, nil
+}
+
+// LoadArchiveSettings decodes only the archive block from manifest bytes.
+// A manifest without one returns zero-valued, non-nil settings.
+func LoadArchiveSettings(data []byte) (*ArchiveSettings, error) {
+	var m struct {
+		Archive *ArchiveSettings `yaml:"archive"`
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, &Error{Code: ErrCodeManifestInvalid, Path: "archive", Detail: "yaml parse: " + err.Error()}
+	}
+	if m.Archive == nil {
+		return &ArchiveSettings{}, nil
+	}
+	return m.Archive, nil
+}
+
+// LoadBuildArgs decodes only the build_args block from manifest bytes.
+// Returns nil (and no error) when the manifest has none.
+func LoadBuildArgs(data []byte) (map[string]string, error) {
+	var m struct {
+		BuildArgs map[string]string `yaml:"build_args"`
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, &Error{Code: ErrCodeManifestInvalid, Path: "build_args", Detail: "yaml parse: " + err.Error()}
+	}
+	if err := validateBuildArgs(m.BuildArgs); err != nil {
+		return nil, err
+	}
+	return m.BuildArgs, nil
+}
+
+func validateBuildArgs(args map[string]string) error {
+	for name := range args {
+		if !BuildArgNameRe.MatchString(name) {
+			return &Error{Code: ErrCodeManifestInvalid, Path: "build_args." + name,
+				Detail: "build arg names must be letters, digits and underscores, not starting with a digit"}
+		}
+	}
+	return nil
+}
+
+// LoadWorkspace decodes only the workspace block from manifest bytes.
+// Returns nil (and no error) when the manifest has no workspace block.
+func LoadWorkspace(data []byte) (*Workspace, error) {
+	var m struct {
+		Workspace *Workspace `yaml:"workspace"`
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, &Error{Code: ErrCodeManifestInvalid, Path: "workspace", Detail: "yaml parse: " + err.Error()}
+	}
+	if m.Workspace == nil {
+		return nil, nil
+	}
+	if len(m.Workspace.Apps) == 0 {
+		return nil, &Error{Code: ErrCodeManifestInvalid, Path: "workspace.apps",
+			Detail: "at least one app directory is required"}
+	}
+	for i, a := range m.Workspace.Apps {
+		clean := filepath.ToSlash(filepath.Clean(a))
+		if strings.TrimSpace(a) == "" || filepath.IsAbs(a) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return nil, &Error{Code: ErrCodeManifestInvalid, Path: fmt.Sprintf("workspace.apps[%d]", i),
+				Detail: fmt.Sprintf("app directory %q must be a relative path inside the workspace", a)}
+		}
+	}
+	return m.Workspace, nil
+}
+
+func validateHooks(h *Hooks) error {
+	if h == nil {
+		return nil
+	}
+	for i, c := range h.PreDeploy {
+		if strings.TrimSpace(c) == "" {
+			return &Error{Code: ErrCodeManifestInvalid, Path: fmt.Sprintf("hooks.pre_deploy[%d]", i),
+				Detail: "hook command must not be empty"}
+		}
+	}
+	for i, c := range h.PostDeploy {
+		if strings.TrimSpace(c) == "" {
+			return &Error{Code: ErrCodeManifestInvalid, Path: fmt.Sprintf("hooks.post_deploy[%d]", i),
+				Detail: "hook command must not be empty"}
+		}
+	}
+	return nil
+}
+
 func validateServiceName(name string) error {
 	if !serviceNameRe.MatchString(name) {
 		return &Error{Code: ErrCodeServiceNameInvalid, Path: "services." + name,
@@ -223,6 +416,36 @@ func validateService(name string, s *Service) error {
 	return nil
 }
 
+// validateJob checks one jobs: entry. Job names share the service-name
+// rules and namespace, so a job may not reuse a service's name.
+func validateJob(name string, j *Job, services map[string]*Service) error {
+	path := "jobs." + name
+	if !serviceNameRe.MatchString(name) {
+		return &Error{Code: ErrCodeServiceNameInvalid, Path: path,
+			Detail: fmt.Sprintf("job name %q does not match %s", name, serviceNameRe.String())}
+	}
+	if _, clash := services[name]; clash {
+		return &Error{Code: ErrCodeServiceNameInvalid, Path: path,
+			Detail: fmt.Sprintf("job %q has the same name as a service", name)}
+	}
+	if j == nil || strings.TrimSpace(j.Schedule) == "" {
+		return &Error{Code: ErrCodeScheduleInvalid, Path: path + ".schedule",
+			Detail: "schedule is required (standard 5-field cron, UTC)"}
+	}
+	if err := ValidateSchedule(j.Schedule); err != nil {
+		return &Error{Code: ErrCodeScheduleInvalid, Path: path + ".schedule", Detail: err.Error()}
+	}
+	if (j.Build != nil) == (j.Image != nil) {
+		return &Error{Code: ErrCodeManifestInvalid, Path: path,
+			Detail: "job must specify exactly one of `build` or `image`"}
+	}
+	if ref, ok := j.Image.(string); ok && !imageWithTagRe.MatchString(ref) {
+		return &Error{Code: ErrCodeManifestInvalid, Path: path + ".image",
+			Detail: fmt.Sprintf("image reference %q must include a tag", ref)}
+	}
+	return nil
+}
+
 func validateRoute(svc string, idx int, r Route) error {
 	path := fmt.Sprintf("services.%s.routes[%d]", svc, idx)
 	if r.Type == "" {
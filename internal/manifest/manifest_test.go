@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -406,3 +407,75 @@ services:
 		t.Errorf("expected 2 routes, got %d", len(m.Services["broker"].Routes))
 	}
 }
+
+func TestParseHooksStringAndListForms(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "dibbla.yaml", `
+version: 1
+services:
+  app: { build: . }
+hooks:
+  pre_deploy: npm run build
+  post_deploy:
+    - curl -fsS "$DIBBLA_DEPLOY_URL/healthz"
+    - npm run smoke
+`)
+	m, err := ParseAndValidate(p)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if len(m.Hooks.PreDeploy) != 1 || m.Hooks.PreDeploy[0] != "npm run build" {
+		t.Errorf("pre_deploy: %q", m.Hooks.PreDeploy)
+	}
+	if len(m.Hooks.PostDeploy) != 2 {
+		t.Errorf("post_deploy: %q", m.Hooks.PostDeploy)
+	}
+}
+
+func TestParseRejectsEmptyHookCommand(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "dibbla.yaml", `
+version: 1
+services:
+  app: { build: . }
+hooks:
+  pre_deploy: ["  "]
+`)
+	_, err := ParseAndValidate(p)
+	expectErrCode(t, err, ErrCodeManifestInvalid)
+}
+
+func TestLoadHooksWithoutBlockIsEmpty(t *testing.T) {
+	h, err := LoadHooks([]byte("version: 1\nservices:\n  app: { build: . }\n"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if h == nil || len(h.PreDeploy) != 0 || len(h.PostDeploy) != 0 {
+		t.Errorf("expected empty hooks, got %+v", h)
+	}
+}
+
+func TestLoadWorkspaceRejectsEscapingPath(t *testing.T) {
+	_, err := LoadWorkspace([]byte("workspace:\n  apps: [../other]\n"))
+	expectErrCode(t, err, ErrCodeManifestInvalid)
+}
+
+func TestParseWorkspaceRootNotDeployable(t *testing.T) {
+	_, err := ParseAndValidateBytes([]byte("version: 1\nworkspace:\n  apps: [web]\n"))
+	expectErrCode(t, err, ErrCodeManifestInvalid)
+	if !strings.Contains(err.Error(), "deploy --all") {
+		t.Errorf("expected --all hint, got %v", err)
+	}
+}
+
+func TestLoadBuildArgs(t *testing.T) {
+	args, err := LoadBuildArgs([]byte("version: 1\nbuild_args:\n  VERSION: \"1.2\"\n  NPM_TOKEN: abc\n"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if args["VERSION"] != "1.2" || args["NPM_TOKEN"] != "abc" {
+		t.Errorf("unexpected build args %v", args)
+	}
+	_, err = LoadBuildArgs([]byte("build_args:\n  1BAD: x\n"))
+	expectErrCode(t, err, ErrCodeManifestInvalid)
+}
@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cronField describes one column of a 5-field cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	// 7 is accepted as Sunday, matching Kubernetes CronJob.
+	{name: "day-of-week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var cronMacros = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true,
+	"@weekly": true, "@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// ValidateSchedule checks that expr is a standard 5-field cron expression
+// (minute hour day-of-month month day-of-week) or one of the @hourly /
+// @daily / ... macros. Timezones and seconds fields are not supported; the
+// server runs jobs in UTC.
+func ValidateSchedule(expr string) error {
+	expr = strings.TrimSpace(expr)
+	if cronMacros[strings.ToLower(expr)] {
+		return nil
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("schedule %q must have 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(fields))
+	}
+	for i, f := range fields {
+		if err := cronFields[i].validate(f); err != nil {
+			return fmt.Errorf("schedule %q: %w", expr, err)
+		}
+	}
+	return nil
+}
+
+func (c cronField) validate(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		rng, step, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return fmt.Errorf("%s: invalid step %q", c.name, step)
+			}
+		}
+		if rng == "*" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(rng, "-")
+		a, err := c.value(lo)
+		if err != nil {
+			return err
+		}
+		if isRange {
+			b, err := c.value(hi)
+			if err != nil {
+				return err
+			}
+			if a > b {
+				return fmt.Errorf("%s: range %q is reversed", c.name, rng)
+			}
+		}
+	}
+	return nil
+}
+
+func (c cronField) value(s string) (int, error) {
+	if v, ok := c.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value %q", c.name, s)
+	}
+	if n < c.min || n > c.max {
+		return 0, fmt.Errorf("%s: %d out of range %d-%d", c.name, n, c.min, c.max)
+	}
+	return n, nil
+}
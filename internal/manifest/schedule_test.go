@@ -0,0 +1,81 @@
+package manifest
+
+import "testing"
+
+func TestValidateSchedule(t *testing.T) {
+	cases := []struct {
+		expr string
+		ok   bool
+	}{
+		{"*/5 * * * *", true},
+		{"0 3 * * 1-5", true},
+		{"30 2 1,15 jan-jun sun", true},
+		{"@daily", true},
+		{"0 0 * * 7", true},
+		{"* * * *", false},
+		{"60 * * * *", false},
+		{"0 24 * * *", false},
+		{"*/0 * * * *", false},
+		{"5-1 * * * *", false},
+		{"0 0 * foo *", false},
+		{"@every 5m", false},
+	}
+	for _, c := range cases {
+		err := ValidateSchedule(c.expr)
+		if (err == nil) != c.ok {
+			t.Errorf("%q: got err=%v, want ok=%v", c.expr, err, c.ok)
+		}
+	}
+}
+
+func TestParseCronOnlyManifest(t *testing.T) {
+	m, err := ParseAndValidateBytes([]byte(`
+version: 1
+jobs:
+  daily-report:
+    schedule: "0 9 * * *"
+    image: alpine:3.20
+    command: [sh, -c, "/run-report.sh"]
+`))
+	if err != nil {
+		t.Fatalf("cron-only manifest rejected: %v", err)
+	}
+	if m.Jobs["daily-report"].Schedule != "0 9 * * *" {
+		t.Errorf("schedule not decoded: %+v", m.Jobs["daily-report"])
+	}
+}
+
+func TestParseRejectsBadJobSchedule(t *testing.T) {
+	_, err := ParseAndValidateBytes([]byte(`
+version: 1
+jobs:
+  report:
+    schedule: "every night"
+    build: .
+`))
+	expectErrCode(t, err, ErrCodeScheduleInvalid)
+}
+
+func TestParseRejectsJobWithoutSchedule(t *testing.T) {
+	_, err := ParseAndValidateBytes([]byte(`
+version: 1
+jobs:
+  report:
+    build: .
+`))
+	expectErrCode(t, err, ErrCodeScheduleInvalid)
+}
+
+func TestParseRejectsJobNamedLikeService(t *testing.T) {
+	_, err := ParseAndValidateBytes([]byte(`
+version: 1
+services:
+  web:
+    build: .
+jobs:
+  web:
+    schedule: "@hourly"
+    build: .
+`))
+	expectErrCode(t, err, ErrCodeServiceNameInvalid)
+}
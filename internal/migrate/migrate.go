@@ -0,0 +1,267 @@
+// Package migrate manages a directory of timestamped SQL migrations and
+// applies them to a database, recording applied versions in a
+// schema_migrations table.
+//
+// Migrations are pairs of files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, where version is a UTC timestamp
+// (YYYYMMDDHHMMSS) so files sort in creation order. The down file is
+// optional; a migration without one can't be rolled back.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+// DefaultDir is the migrations directory, relative to the project root.
+const DefaultDir = "migrations"
+
+// versionLayout formats the version of a new migration.
+const versionLayout = "20060102150405"
+
+var (
+	fileRe    = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	nonSlugRe = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Migration is one migration found on disk.
+type Migration struct {
+	Version  string
+	Name     string
+	UpPath   string
+	DownPath string // empty when there is no down file
+}
+
+// QueryFunc runs SQL against the target database, e.g. db.Query bound to
+// one database.
+type QueryFunc func(ctx context.Context, sql string) (*db.QueryResult, error)
+
+// Load returns the migrations in dir, oldest first. Files that don't look
+// like migrations are ignored. A missing dir holds no migrations.
+func Load(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+	byVersion := map[string]*Migration{}
+	for _, e := range entries {
+		m := fileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, name, kind := m[1], m[2], m[3]
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: name}
+			byVersion[version] = mig
+		} else if mig.Name != name {
+			return nil, fmt.Errorf("version %s is used by both %q and %q", version, mig.Name, name)
+		}
+		path := filepath.Join(dir, e.Name())
+		if kind == "up" {
+			mig.UpPath = path
+		} else {
+			mig.DownPath = path
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.UpPath == "" {
+			return nil, fmt.Errorf("migration %s_%s has no .up.sql file", mig.Version, mig.Name)
+		}
+		out = append(out, *mig)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// Create writes empty up and down files for a new migration called name
+// (lowercased, other characters than letters and digits become _),
+// versioned by now, and returns it.
+func Create(dir, name string, now time.Time) (Migration, error) {
+	slug := strings.Trim(nonSlugRe.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return Migration{}, fmt.Errorf("invalid migration name %q", name)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Migration{}, fmt.Errorf("create migrations directory: %w", err)
+	}
+	version := now.UTC().Format(versionLayout)
+	base := filepath.Join(dir, version+"_"+slug)
+	mig := Migration{Version: version, Name: slug, UpPath: base + ".up.sql", DownPath: base + ".down.sql"}
+	files := map[string]string{
+		mig.UpPath:   "-- " + slug + ": apply\n",
+		mig.DownPath: "-- " + slug + ": revert what the .up.sql file does\n",
+	}
+	for path, content := range files {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return Migration{}, fmt.Errorf("create %s: %w", path, err)
+		}
+		_, err = f.WriteString(content)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return Migration{}, fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	return mig, nil
+}
+
+const createTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version text PRIMARY KEY,
+	name text NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`
+
+// Applied creates the schema_migrations table if needed and returns the
+// applied versions, oldest first.
+func Applied(ctx context.Context, query QueryFunc) ([]string, error) {
+	if _, err := query(ctx, createTableSQL); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	res, err := query(ctx, "SELECT version FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	versions := make([]string, 0, len(res.Rows))
+	for _, row := range res.Rows {
+		if len(row) > 0 {
+			versions = append(versions, fmt.Sprint(row[0]))
+		}
+	}
+	return versions, nil
+}
+
+// Status is a migration's state on the database.
+type Status struct {
+	Migration
+	Applied bool
+	// Missing marks a version recorded as applied with no file on disk.
+	Missing bool
+}
+
+// GetStatus returns every migration on disk or applied, oldest first.
+func GetStatus(ctx context.Context, query QueryFunc, dir string) ([]Status, error) {
+	migs, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := Applied(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	isApplied := make(map[string]bool, len(applied))
+	for _, v := range applied {
+		isApplied[v] = true
+	}
+	onDisk := make(map[string]bool, len(migs))
+	out := make([]Status, 0, len(migs))
+	for _, m := range migs {
+		onDisk[m.Version] = true
+		out = append(out, Status{Migration: m, Applied: isApplied[m.Version]})
+	}
+	for _, v := range applied {
+		if !onDisk[v] {
+			out = append(out, Status{Migration: Migration{Version: v}, Applied: true, Missing: true})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// Up applies pending migrations oldest first, at most steps of them (all
+// when steps is 0). Each migration runs in one transaction with its
+// schema_migrations row. done is called after each one; on error the
+// migrations before the failing one stay applied.
+func Up(ctx context.Context, query QueryFunc, dir string, steps int, done func(Migration)) error {
+	st, err := GetStatus(ctx, query, dir)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for _, s := range st {
+		if s.Applied {
+			continue
+		}
+		if steps > 0 && n == steps {
+			break
+		}
+		body, err := os.ReadFile(s.UpPath)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", s.UpPath, err)
+		}
+		record := fmt.Sprintf("INSERT INTO schema_migrations (version, name) VALUES (%s, %s)", quote(s.Version), quote(s.Name))
+		if _, err := query(ctx, transaction(string(body), record)); err != nil {
+			return fmt.Errorf("apply %s_%s: %w", s.Version, s.Name, err)
+		}
+		n++
+		if done != nil {
+			done(s.Migration)
+		}
+	}
+	return nil
+}
+
+// Down rolls back the latest applied migrations, newest first, at most
+// steps of them (all when steps is 0). It stops with an error at a
+// migration that has no down file.
+func Down(ctx context.Context, query QueryFunc, dir string, steps int, done func(Migration)) error {
+	st, err := GetStatus(ctx, query, dir)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for i := len(st) - 1; i >= 0; i-- {
+		s := st[i]
+		if !s.Applied {
+			continue
+		}
+		if steps > 0 && n == steps {
+			break
+		}
+		if s.Missing {
+			return fmt.Errorf("applied migration %s has no files in %s", s.Version, dir)
+		}
+		if s.DownPath == "" {
+			return fmt.Errorf("migration %s_%s has no .down.sql file", s.Version, s.Name)
+		}
+		body, err := os.ReadFile(s.DownPath)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", s.DownPath, err)
+		}
+		record := "DELETE FROM schema_migrations WHERE version = " + quote(s.Version)
+		if _, err := query(ctx, transaction(string(body), record)); err != nil {
+			return fmt.Errorf("revert %s_%s: %w", s.Version, s.Name, err)
+		}
+		n++
+		if done != nil {
+			done(s.Migration)
+		}
+	}
+	return nil
+}
+
+// transaction wraps a migration body and its bookkeeping statement in one
+// transaction.
+func transaction(body, record string) string {
+	return "BEGIN;\n" + strings.TrimSpace(body) + "\n;\n" + record + ";\nCOMMIT;"
+}
+
+// quote is a SQL string literal for s.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,202 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/db"
+)
+
+// fakeDB tracks schema_migrations the way the SQL sent by this package
+// would change it, and records every migration body it runs.
+type fakeDB struct {
+	applied map[string]bool
+	ran     []string
+	failOn  string
+}
+
+var (
+	insertRe = regexp.MustCompile(`INSERT INTO schema_migrations \(version, name\) VALUES \('(\d+)'`)
+	deleteRe = regexp.MustCompile(`DELETE FROM schema_migrations WHERE version = '(\d+)'`)
+)
+
+func (f *fakeDB) query(_ context.Context, sql string) (*db.QueryResult, error) {
+	if f.failOn != "" && strings.Contains(sql, f.failOn) {
+		return nil, errors.New("syntax error")
+	}
+	switch {
+	case strings.HasPrefix(sql, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+		return &db.QueryResult{Command: "CREATE TABLE"}, nil
+	case strings.HasPrefix(sql, "SELECT version"):
+		var rows [][]any
+		for v := range f.applied {
+			rows = append(rows, []any{v})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0].(string) < rows[j][0].(string) })
+		return &db.QueryResult{Columns: []string{"version"}, Rows: rows}, nil
+	}
+	if !strings.HasPrefix(sql, "BEGIN;") || !strings.HasSuffix(sql, "COMMIT;") {
+		return nil, errors.New("migration not wrapped in a transaction")
+	}
+	f.ran = append(f.ran, sql)
+	if m := insertRe.FindStringSubmatch(sql); m != nil {
+		f.applied[m[1]] = true
+	} else if m := deleteRe.FindStringSubmatch(sql); m != nil {
+		delete(f.applied, m[1])
+	}
+	return &db.QueryResult{Command: "COMMIT"}, nil
+}
+
+func writeMigrations(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func threeMigrations(t *testing.T) string {
+	return writeMigrations(t, map[string]string{
+		"20260101000000_users.up.sql":     "CREATE TABLE users (id int)",
+		"20260101000000_users.down.sql":   "DROP TABLE users",
+		"20260102000000_posts.up.sql":     "CREATE TABLE posts (id int)",
+		"20260102000000_posts.down.sql":   "DROP TABLE posts",
+		"20260103000000_indexes.up.sql":   "CREATE INDEX posts_id ON posts (id)",
+		"20260103000000_indexes.down.sql": "DROP INDEX posts_id",
+		"README.md":                       "not a migration",
+	})
+}
+
+func TestLoad(t *testing.T) {
+	migs, err := Load(threeMigrations(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range migs {
+		got = append(got, m.Version+"_"+m.Name)
+	}
+	want := "20260101000000_users 20260102000000_posts 20260103000000_indexes"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Load = %v, want %s", got, want)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	if _, err := Load(writeMigrations(t, map[string]string{"1_a.down.sql": ""})); err == nil {
+		t.Error("down file without up: want error")
+	}
+	if _, err := Load(writeMigrations(t, map[string]string{"1_a.up.sql": "", "1_b.up.sql": ""})); err == nil {
+		t.Error("duplicate version: want error")
+	}
+	if migs, err := Load(filepath.Join(t.TempDir(), "missing")); err != nil || len(migs) != 0 {
+		t.Errorf("missing dir = %v, %v", migs, err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "migrations")
+	now := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	m, err := Create(dir, "Add Users table", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != "20261015093000" || m.Name != "add_users_table" {
+		t.Errorf("Create = %+v", m)
+	}
+	for _, p := range []string{m.UpPath, m.DownPath} {
+		if _, err := os.Stat(p); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := Create(dir, "add users table", now); err == nil {
+		t.Error("same version and name twice: want error")
+	}
+	if _, err := Create(dir, "!!!", now); err == nil {
+		t.Error("empty slug: want error")
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	dir := threeMigrations(t)
+	f := &fakeDB{applied: map[string]bool{}}
+	ctx := context.Background()
+
+	var done []string
+	record := func(m Migration) { done = append(done, m.Name) }
+	if err := Up(ctx, f.query, dir, 2, record); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(done, ",") != "users,posts" {
+		t.Errorf("up --steps 2 applied %v", done)
+	}
+	if !strings.Contains(f.ran[0], "CREATE TABLE users (id int)") {
+		t.Errorf("first migration sql = %q", f.ran[0])
+	}
+
+	done = nil
+	if err := Up(ctx, f.query, dir, 0, record); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(done, ",") != "indexes" {
+		t.Errorf("up applied %v", done)
+	}
+
+	done = nil
+	if err := Down(ctx, f.query, dir, 2, record); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(done, ",") != "indexes,posts" {
+		t.Errorf("down --steps 2 reverted %v", done)
+	}
+
+	st, err := GetStatus(ctx, f.query, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st[0].Applied || st[1].Applied || st[2].Applied {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+func TestUp_StopsAtFailure(t *testing.T) {
+	dir := threeMigrations(t)
+	f := &fakeDB{applied: map[string]bool{}, failOn: "CREATE TABLE posts"}
+	err := Up(context.Background(), f.query, dir, 0, nil)
+	if err == nil || !strings.Contains(err.Error(), "20260102000000_posts") {
+		t.Fatalf("err = %v", err)
+	}
+	if len(f.applied) != 1 || !f.applied["20260101000000"] {
+		t.Errorf("applied = %v", f.applied)
+	}
+}
+
+func TestDown_NeedsDownFile(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{"20260101000000_users.up.sql": "CREATE TABLE users (id int)"})
+	f := &fakeDB{applied: map[string]bool{"20260101000000": true}}
+	if err := Down(context.Background(), f.query, dir, 1, nil); err == nil || !strings.Contains(err.Error(), ".down.sql") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestGetStatus_MissingFile(t *testing.T) {
+	dir := threeMigrations(t)
+	f := &fakeDB{applied: map[string]bool{"20250101000000": true}}
+	st, err := GetStatus(context.Background(), f.query, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st) != 4 || !st[0].Missing || !st[0].Applied {
+		t.Errorf("status = %+v", st)
+	}
+}
@@ -0,0 +1,108 @@
+// Package notify posts deploy results to webhooks (Slack incoming
+// webhooks, or any endpoint that accepts a JSON POST).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Event names carried in DeployEvent.Event.
+const (
+	EventDeploySucceeded = "deploy.succeeded"
+	EventDeployFailed    = "deploy.failed"
+)
+
+// DeployEvent is the JSON payload posted after a deploy.
+type DeployEvent struct {
+	Event        string `json:"event"`
+	Alias        string `json:"alias"`
+	URL          string `json:"url,omitempty"`
+	Status       string `json:"status"`
+	DeploymentID string `json:"deployment_id,omitempty"`
+	DurationMs   int64  `json:"duration_ms"`
+	Error        string `json:"error,omitempty"`
+	// Text is a one-line summary; Slack renders it as the message.
+	Text string `json:"text"`
+}
+
+// NewDeployEvent builds the payload for a finished deploy. An empty errMsg
+// means it succeeded.
+func NewDeployEvent(alias, appURL, status, deploymentID, errMsg string, took time.Duration) DeployEvent {
+	ev := DeployEvent{
+		Event:        EventDeploySucceeded,
+		Alias:        alias,
+		URL:          appURL,
+		Status:       status,
+		DeploymentID: deploymentID,
+		DurationMs:   took.Milliseconds(),
+		Error:        errMsg,
+	}
+	took = took.Round(100 * time.Millisecond)
+	if errMsg != "" {
+		ev.Event = EventDeployFailed
+		if ev.Status == "" {
+			ev.Status = "failed"
+		}
+		ev.Text = fmt.Sprintf("Deploy of %s failed after %s: %s", alias, took, errMsg)
+		return ev
+	}
+	ev.Text = fmt.Sprintf("Deployed %s in %s", alias, took)
+	if appURL != "" {
+		ev.Text += ": " + appURL
+	}
+	return ev
+}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Send posts ev to webhook. Any 2xx response counts as delivered.
+func Send(ctx context.Context, webhook string, ev DeployEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SendAll posts ev to each webhook and reports failures on warn. Webhook
+// URLs often embed a secret, so only the host is printed.
+func SendAll(ctx context.Context, webhooks []string, ev DeployEvent, warn io.Writer) {
+	for _, w := range webhooks {
+		err := Send(ctx, w, ev)
+		if err == nil {
+			continue
+		}
+		// Transport errors quote the full URL; keep only the cause.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		host := w
+		if u, perr := url.Parse(w); perr == nil {
+			host = u.Host
+		}
+		fmt.Fprintf(warn, "note: deploy notification to %s failed: %v\n", host, err)
+	}
+}
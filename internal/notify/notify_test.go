@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDeployEvent(t *testing.T) {
+	ok := NewDeployEvent("api", "https://api.dibbla.com", "running", "dep_1", "", 1234*time.Millisecond)
+	if ok.Event != EventDeploySucceeded || ok.DurationMs != 1234 {
+		t.Errorf("success event = %+v", ok)
+	}
+	if ok.Text != "Deployed api in 1.2s: https://api.dibbla.com" {
+		t.Errorf("text = %q", ok.Text)
+	}
+
+	failed := NewDeployEvent("api", "", "", "", "build_failed: exit 1", 3*time.Second)
+	if failed.Event != EventDeployFailed || failed.Status != "failed" {
+		t.Errorf("failure event = %+v", failed)
+	}
+	if failed.Text != "Deploy of api failed after 3s: build_failed: exit 1" {
+		t.Errorf("text = %q", failed.Text)
+	}
+}
+
+func TestSendAllPostsJSON(t *testing.T) {
+	var got DeployEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	var warn bytes.Buffer
+	ev := NewDeployEvent("api", "https://api.dibbla.com", "running", "dep_1", "", time.Second)
+	SendAll(context.Background(), []string{srv.URL + "/hook"}, ev, &warn)
+	if warn.Len() != 0 {
+		t.Errorf("unexpected warning: %s", warn.String())
+	}
+	if got.Alias != "api" || got.URL != "https://api.dibbla.com" || got.Status != "running" || got.DurationMs != 1000 {
+		t.Errorf("payload = %+v", got)
+	}
+}
+
+func TestSendAllWarnsWithoutSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var warn bytes.Buffer
+	SendAll(context.Background(), []string{srv.URL + "/services/SECRET"}, NewDeployEvent("api", "", "", "", "", 0), &warn)
+	msg := warn.String()
+	if !strings.Contains(msg, "HTTP 404") {
+		t.Errorf("warning = %q, want HTTP 404", msg)
+	}
+	if strings.Contains(msg, "SECRET") {
+		t.Errorf("warning leaks the webhook path: %q", msg)
+	}
+}
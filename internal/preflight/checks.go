@@ -30,6 +30,21 @@ func CheckGo() bool {
 	return true
 }
 
+// CheckNode checks if Node.js is installed and prints the version
+// Returns true if Node.js is available, false otherwise (but allows continue)
+func CheckNode() bool {
+	cmd := exec.Command("node", "--version")
+	output, err := cmd.Output()
+	if err != nil {
+		fmt.Printf("  %s Node.js: not found (install from https://nodejs.org)\n", platform.Icon("⚠️", "[!]"))
+		return false
+	}
+
+	// Output is just the version, e.g. "v20.11.0"
+	fmt.Printf("  %s Node.js: %s\n", platform.Icon("✅", "[OK]"), strings.TrimSpace(string(output)))
+	return true
+}
+
 // CheckGit checks if Git is installed
 // Returns true if Git is available, false otherwise
 func CheckGit() bool {
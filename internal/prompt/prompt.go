@@ -2,6 +2,7 @@ package prompt
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -17,14 +18,26 @@ const (
 	HostingSelfHosted
 )
 
-// AskProjectName prompts the user for a project name
-func AskProjectName() string {
-	var name string
-	prompt := &survey.Input{
-		Message: "Project name:",
+// AskProjectName prompts the user for a project name until check accepts
+// it, offering check's suggestion for a name it rejects
+func AskProjectName(check func(name string) (suggestion string, err error)) string {
+	for {
+		var name string
+		prompt := &survey.Input{
+			Message: "Project name:",
+		}
+		survey.AskOne(prompt, &name, survey.WithValidator(survey.Required))
+		name = strings.TrimSpace(name)
+
+		suggestion, err := check(name)
+		if err == nil {
+			return name
+		}
+		fmt.Printf("  %s %v\n", platform.Icon("⚠️", "[!]"), err)
+		if suggestion != "" && AskConfirm(fmt.Sprintf("Use %q instead?", suggestion)) {
+			return suggestion
+		}
 	}
-	survey.AskOne(prompt, &name, survey.WithValidator(survey.Required))
-	return strings.TrimSpace(name)
 }
 
 // AskHostingType prompts the user to select between Dibbla Cloud and Self-Hosted
@@ -109,6 +122,43 @@ func AskIncludeFrontend() bool {
 	return include
 }
 
+// AskFeature asks whether to include an optional template feature
+func AskFeature(description string, def bool) bool {
+	var include bool
+	prompt := &survey.Confirm{
+		Message: "Include " + description + "?",
+		Default: def,
+	}
+	survey.AskOne(prompt, &include)
+	return include
+}
+
+// Extra is an optional capability offered by AskExtras.
+type Extra struct {
+	Name        string
+	Description string
+}
+
+// AskExtras asks which of the optional extras to add and returns the
+// names of those chosen
+func AskExtras(extras []Extra) []string {
+	options := make([]string, len(extras))
+	for i, e := range extras {
+		options[i] = e.Description
+	}
+	var picked []int
+	prompt := &survey.MultiSelect{
+		Message: "Add extras? (space to select, enter to continue)",
+		Options: options,
+	}
+	survey.AskOne(prompt, &picked)
+	names := make([]string, len(picked))
+	for i, p := range picked {
+		names[i] = extras[p].Name
+	}
+	return names
+}
+
 // AskConfirm asks a yes/no question with default yes
 func AskConfirm(message string) bool {
 	var confirm bool
@@ -119,3 +169,26 @@ func AskConfirm(message string) bool {
 	survey.AskOne(prompt, &confirm)
 	return confirm
 }
+
+// AskConfirmStderr is AskConfirm drawn on stderr, for commands whose
+// stdout is JSON or otherwise meant for another program.
+func AskConfirmStderr(message string) bool {
+	var confirm bool
+	prompt := &survey.Confirm{
+		Message: message,
+		Default: true,
+	}
+	survey.AskOne(prompt, &confirm, survey.WithStdio(os.Stdin, os.Stderr, os.Stderr))
+	return confirm
+}
+
+// AskTypedConfirm asks the user to type want to go ahead, for actions
+// that are hard to undo. It reports whether they typed it exactly.
+func AskTypedConfirm(message, want string) bool {
+	var answer string
+	prompt := &survey.Input{
+		Message: fmt.Sprintf("%s Type %q to continue:", message, want),
+	}
+	survey.AskOne(prompt, &answer)
+	return strings.TrimSpace(answer) == want
+}
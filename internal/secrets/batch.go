@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// SecretValue is one secret to set with SetSecrets.
+type SecretValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SetResult is the outcome of setting one secret. Error is set when it
+// failed; the others in the batch are unaffected.
+type SetResult struct {
+	Name    string `json:"name"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// setBatchResponse is the response of the batch endpoint.
+type setBatchResponse struct {
+	Results []SetResult `json:"results"`
+}
+
+// SetSecrets creates or updates several secrets in one scope (see
+// CreateSecret) with a single request, reporting each secret's outcome in
+// the order of values. Servers without the batch endpoint get one
+// CreateSecret call per secret instead. The error is for the batch as a
+// whole, e.g. the API being unreachable.
+func SetSecrets(ctx context.Context, apiURL, apiToken string, values []SecretValue, deploymentAlias, serviceName string) ([]SetResult, error) {
+	payload := map[string]any{"secrets": values}
+	if deploymentAlias != "" {
+		payload["deployment_alias"] = deploymentAlias
+	}
+	if serviceName != "" {
+		payload["service_name"] = serviceName
+	}
+	raw, _ := json.Marshal(payload)
+
+	client := &http.Client{Timeout: requestTimeout}
+	req, err := http.NewRequestWithContext(ctx, "POST", makeAPIURL(apiURL, "/api/deploy/secrets/batch", nil), bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusMultiStatus:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return setOneByOne(ctx, apiURL, apiToken, values, deploymentAlias, serviceName), nil
+	default:
+		return nil, parseError(body, resp.StatusCode)
+	}
+
+	var out setBatchResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	// Report every secret asked for, in order, even if the server left
+	// one out.
+	byName := make(map[string]SetResult, len(out.Results))
+	for _, r := range out.Results {
+		byName[r.Name] = r
+	}
+	results := make([]SetResult, len(values))
+	for i, v := range values {
+		r, ok := byName[v.Name]
+		if !ok {
+			r = SetResult{Name: v.Name, Error: "not in the server's response"}
+		}
+		results[i] = r
+	}
+	return results, nil
+}
+
+// setOneByOne is SetSecrets for servers without the batch endpoint.
+func setOneByOne(ctx context.Context, apiURL, apiToken string, values []SecretValue, deploymentAlias, serviceName string) []SetResult {
+	results := make([]SetResult, len(values))
+	for i, v := range values {
+		results[i].Name = v.Name
+		res, err := CreateSecret(ctx, apiURL, apiToken, v.Name, v.Value, deploymentAlias, serviceName)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Message = res.Message
+	}
+	return results
+}
@@ -0,0 +1,103 @@
+package secrets
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// DigestKeyHeader carries the key GetSecretDigests asks the server to
+// digest values with.
+const DigestKeyHeader = "X-Digest-Key"
+
+// digestsResponse is the response of the digests endpoint.
+type digestsResponse struct {
+	Digests map[string]string `json:"digests"`
+}
+
+// NewDigestKey returns a random key for one comparison. Digests made with
+// it can't be matched against values guessed later, unlike plain hashes.
+func NewDigestKey() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Digest is the digest the API reports for a secret value under key: the
+// hex HMAC-SHA256 of its bytes. Comparing digests tells whether two values
+// match without either being shown.
+func Digest(key, value string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetSecretDigests returns the Digest under key of every secret in a scope
+// (see ListSecrets), keyed by name. Values never leave the server; servers
+// without the digests endpoint get an error rather than being asked for
+// every value.
+func GetSecretDigests(ctx context.Context, apiURL, apiToken, key, deployment, service string) (map[string]string, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	req, err := http.NewRequestWithContext(ctx, "GET", makeAPIURL(apiURL, "/api/deploy/secrets/digests", scopeQuery(deployment, service)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(DigestKeyHeader, key)
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("the API server can't compare secret values (no digests endpoint); update it to use this command")
+	default:
+		return nil, parseError(body, resp.StatusCode)
+	}
+
+	var out digestsResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if out.Digests == nil {
+		out.Digests = map[string]string{}
+	}
+	return out.Digests, nil
+}
+
+// GetSecretValues returns the value of every secret in a scope (see
+// ListSecrets), keyed by name: one ListSecrets call and one GetSecret call
+// per secret.
+func GetSecretValues(ctx context.Context, apiURL, apiToken, deployment, service string) (map[string]string, error) {
+	list, err := ListSecrets(ctx, apiURL, apiToken, deployment, service)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(list.Secrets))
+	for _, s := range list.Secrets {
+		res, err := GetSecret(ctx, apiURL, apiToken, s.Name, deployment, service)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %w", s.Name, err)
+		}
+		values[s.Name] = res.Value
+	}
+	return values, nil
+}
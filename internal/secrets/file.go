@@ -0,0 +1,61 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// secretsFile is the layout of a declared secrets file:
+//
+//	secrets:
+//	  LOG_LEVEL: debug
+//	  API_KEY: ${API_KEY}
+type secretsFile struct {
+	Secrets map[string]string `yaml:"secrets"`
+}
+
+// envRefRe matches a ${NAME} reference to a local environment variable.
+var envRefRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ParseSecretsFile reads a declared secrets file, sorted by name. ${NAME}
+// in a value is replaced with the local environment variable NAME (looked
+// up with lookup), so the file can be committed without the values in it;
+// a reference to an unset variable is an error, as is an empty value.
+func ParseSecretsFile(data []byte, lookup func(string) (string, bool)) ([]SecretValue, error) {
+	var f secretsFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid secrets file: %w", err)
+	}
+	if len(f.Secrets) == 0 {
+		return nil, fmt.Errorf("the secrets file declares no secrets (expected a top-level 'secrets:' map)")
+	}
+
+	values := make([]SecretValue, 0, len(f.Secrets))
+	for name, raw := range f.Secrets {
+		var unset []string
+		value := envRefRe.ReplaceAllStringFunc(raw, func(ref string) string {
+			key := envRefRe.FindStringSubmatch(ref)[1]
+			v, ok := lookup(key)
+			if !ok {
+				unset = append(unset, key)
+			}
+			return v
+		})
+		if len(unset) > 0 {
+			return nil, fmt.Errorf("%s refers to unset environment variable(s) %s", name, strings.Join(unset, ", "))
+		}
+		if value == "" {
+			return nil, fmt.Errorf("the value of %s is empty", name)
+		}
+		values = append(values, SecretValue{Name: name, Value: value})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+	return values, nil
+}
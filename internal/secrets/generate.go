@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Value generators for GenerateValue.
+const (
+	GeneratorHex32  = "hex32"     // 32 random bytes, hex-encoded (64 characters)
+	GeneratorUUID   = "uuid"      // a random (version 4) UUID
+	GeneratorBase64 = "base64-48" // 48 random bytes, URL-safe base64 (64 characters)
+)
+
+// Generators lists the GenerateValue generators, default first.
+var Generators = []string{GeneratorHex32, GeneratorUUID, GeneratorBase64}
+
+// GenerateValue returns a new random secret value from generator, using
+// crypto/rand.
+func GenerateValue(generator string) (string, error) {
+	var n int
+	switch generator {
+	case GeneratorHex32:
+		n = 32
+	case GeneratorUUID:
+		n = 16
+	case GeneratorBase64:
+		n = 48
+	default:
+		return "", fmt.Errorf("unknown generator %q (expected one of %s)", generator, strings.Join(Generators, ", "))
+	}
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate a random value: %w", err)
+	}
+	switch generator {
+	case GeneratorUUID:
+		b[6] = b[6]&0x0f | 0x40 // version 4
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+		h := hex.EncodeToString(b)
+		return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+	case GeneratorBase64:
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	default:
+		return hex.EncodeToString(b), nil
+	}
+}
@@ -2,6 +2,7 @@ package secrets
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
 )
 
 const requestTimeout = 30 * time.Second
@@ -104,7 +107,7 @@ func parseError(body []byte, statusCode int) error {
 //   - deployment empty                  → org-global only
 //   - deployment non-empty, service ""  → deployment-wide entries (service_name='')
 //   - deployment non-empty, service X   → per-service entries for X
-func ListSecrets(apiURL, apiToken, deployment, service string) (*SecretsListResponse, error) {
+func ListSecrets(ctx context.Context, apiURL, apiToken, deployment, service string) (*SecretsListResponse, error) {
 	query := url.Values{}
 	if deployment != "" {
 		query.Set("deployment", deployment)
@@ -113,14 +116,14 @@ func ListSecrets(apiURL, apiToken, deployment, service string) (*SecretsListResp
 		query.Set("service", service)
 	}
 	client := &http.Client{Timeout: requestTimeout}
-	req, err := http.NewRequest("GET", makeAPIURL(apiURL, "/api/deploy/secrets", query), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", makeAPIURL(apiURL, "/api/deploy/secrets", query), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -145,7 +148,7 @@ func ListSecrets(apiURL, apiToken, deployment, service string) (*SecretsListResp
 // CreateSecret creates or updates a secret. deploymentAlias can be empty for a
 // global secret. serviceName scopes the secret to a single service in the
 // deployment (server requires deploymentAlias when serviceName is set).
-func CreateSecret(apiURL, apiToken, name, value, deploymentAlias, serviceName string) (*SecretCreateResponse, error) {
+func CreateSecret(ctx context.Context, apiURL, apiToken, name, value, deploymentAlias, serviceName string) (*SecretCreateResponse, error) {
 	payload := map[string]string{"name": name, "value": value}
 	if deploymentAlias != "" {
 		payload["deployment_alias"] = deploymentAlias
@@ -156,7 +159,7 @@ func CreateSecret(apiURL, apiToken, name, value, deploymentAlias, serviceName st
 	raw, _ := json.Marshal(payload)
 
 	client := &http.Client{Timeout: requestTimeout}
-	req, err := http.NewRequest("POST", makeAPIURL(apiURL, "/api/deploy/secrets", nil), bytes.NewReader(raw))
+	req, err := http.NewRequestWithContext(ctx, "POST", makeAPIURL(apiURL, "/api/deploy/secrets", nil), bytes.NewReader(raw))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -164,7 +167,7 @@ func CreateSecret(apiURL, apiToken, name, value, deploymentAlias, serviceName st
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -188,7 +191,7 @@ func CreateSecret(apiURL, apiToken, name, value, deploymentAlias, serviceName st
 
 // GetSecret returns a secret by name. deployment can be empty for a global
 // secret. service scopes to a per-service entry within the deployment.
-func GetSecret(apiURL, apiToken, name, deployment, service string) (*SecretResponse, error) {
+func GetSecret(ctx context.Context, apiURL, apiToken, name, deployment, service string) (*SecretResponse, error) {
 	query := url.Values{}
 	if deployment != "" {
 		query.Set("deployment", deployment)
@@ -197,14 +200,14 @@ func GetSecret(apiURL, apiToken, name, deployment, service string) (*SecretRespo
 		query.Set("service", service)
 	}
 	client := &http.Client{Timeout: requestTimeout}
-	req, err := http.NewRequest("GET", makeAPIURL(apiURL, "/api/deploy/secrets/"+url.PathEscape(name), query), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", makeAPIURL(apiURL, "/api/deploy/secrets/"+url.PathEscape(name), query), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
@@ -228,7 +231,7 @@ func GetSecret(apiURL, apiToken, name, deployment, service string) (*SecretRespo
 
 // DeleteSecret deletes a secret by name. deployment can be empty for a global
 // secret. service scopes the delete to a per-service entry within the deployment.
-func DeleteSecret(apiURL, apiToken, name, deployment, service string) (*DeleteResponse, error) {
+func DeleteSecret(ctx context.Context, apiURL, apiToken, name, deployment, service string) (*DeleteResponse, error) {
 	query := url.Values{}
 	if deployment != "" {
 		query.Set("deployment", deployment)
@@ -237,14 +240,14 @@ func DeleteSecret(apiURL, apiToken, name, deployment, service string) (*DeleteRe
 		query.Set("service", service)
 	}
 	client := &http.Client{Timeout: requestTimeout}
-	req, err := http.NewRequest("DELETE", makeAPIURL(apiURL, "/api/deploy/secrets/"+url.PathEscape(name), query), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", makeAPIURL(apiURL, "/api/deploy/secrets/"+url.PathEscape(name), query), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := apiclient.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make API request: %w", err)
 	}
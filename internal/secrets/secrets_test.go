@@ -1,10 +1,12 @@
 package secrets
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -44,7 +46,7 @@ func TestListSecrets_ForwardsServiceParam(t *testing.T) {
 		Secrets: []SecretListItem{{Name: "API_KEY", DeploymentAlias: "myapp", ServiceName: "web"}},
 		Total:   1,
 	})
-	out, err := ListSecrets(rs.srv.URL, "tok", "myapp", "web")
+	out, err := ListSecrets(context.Background(), rs.srv.URL, "tok", "myapp", "web")
 	if err != nil {
 		t.Fatalf("list: %v", err)
 	}
@@ -61,7 +63,7 @@ func TestListSecrets_ForwardsServiceParam(t *testing.T) {
 
 func TestListSecrets_OmitsServiceWhenEmpty(t *testing.T) {
 	rs := newRecorder(t, http.StatusOK, SecretsListResponse{Total: 0})
-	if _, err := ListSecrets(rs.srv.URL, "tok", "myapp", ""); err != nil {
+	if _, err := ListSecrets(context.Background(), rs.srv.URL, "tok", "myapp", ""); err != nil {
 		t.Fatalf("list: %v", err)
 	}
 	if strings.Contains(rs.query, "service=") {
@@ -74,7 +76,7 @@ func TestCreateSecret_ForwardsServiceField(t *testing.T) {
 		Status: "success", Message: "Secret created successfully",
 		Secret: SecretResponse{Name: "TOKEN", DeploymentAlias: "myapp", ServiceName: "web"},
 	})
-	out, err := CreateSecret(rs.srv.URL, "tok", "TOKEN", "v", "myapp", "web")
+	out, err := CreateSecret(context.Background(), rs.srv.URL, "tok", "TOKEN", "v", "myapp", "web")
 	if err != nil {
 		t.Fatalf("create: %v", err)
 	}
@@ -97,7 +99,7 @@ func TestCreateSecret_OmitsServiceWhenEmpty(t *testing.T) {
 	rs := newRecorder(t, http.StatusCreated, SecretCreateResponse{
 		Status: "success", Secret: SecretResponse{Name: "TOKEN"},
 	})
-	if _, err := CreateSecret(rs.srv.URL, "tok", "TOKEN", "v", "myapp", ""); err != nil {
+	if _, err := CreateSecret(context.Background(), rs.srv.URL, "tok", "TOKEN", "v", "myapp", ""); err != nil {
 		t.Fatalf("create: %v", err)
 	}
 	var body map[string]string
@@ -113,7 +115,7 @@ func TestGetSecret_ForwardsServiceParam(t *testing.T) {
 	rs := newRecorder(t, http.StatusOK, SecretResponse{
 		Name: "TOKEN", Value: "xxx", DeploymentAlias: "myapp", ServiceName: "web",
 	})
-	out, err := GetSecret(rs.srv.URL, "tok", "TOKEN", "myapp", "web")
+	out, err := GetSecret(context.Background(), rs.srv.URL, "tok", "TOKEN", "myapp", "web")
 	if err != nil {
 		t.Fatalf("get: %v", err)
 	}
@@ -127,7 +129,7 @@ func TestGetSecret_ForwardsServiceParam(t *testing.T) {
 
 func TestDeleteSecret_ForwardsServiceParam(t *testing.T) {
 	rs := newRecorder(t, http.StatusOK, DeleteResponse{Status: "success", Message: "ok"})
-	if _, err := DeleteSecret(rs.srv.URL, "tok", "TOKEN", "myapp", "web"); err != nil {
+	if _, err := DeleteSecret(context.Background(), rs.srv.URL, "tok", "TOKEN", "myapp", "web"); err != nil {
 		t.Fatalf("delete: %v", err)
 	}
 	if !strings.Contains(rs.query, "service=web") {
@@ -143,7 +145,7 @@ func TestDeleteSecret_400Surfaced(t *testing.T) {
 		Status: "error",
 		Error:  APIError{Code: "VALIDATION_FAILED", Message: "service requires deployment"},
 	})
-	_, err := DeleteSecret(rs.srv.URL, "tok", "TOKEN", "", "web")
+	_, err := DeleteSecret(context.Background(), rs.srv.URL, "tok", "TOKEN", "", "web")
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -151,3 +153,173 @@ func TestDeleteSecret_400Surfaced(t *testing.T) {
 		t.Errorf("missing code: %v", err)
 	}
 }
+
+func TestSetSecrets_Batch(t *testing.T) {
+	rs := newRecorder(t, http.StatusMultiStatus, map[string]any{"results": []SetResult{
+		{Name: "B", Error: "VALIDATION_ERROR: value too long"},
+		{Name: "A", Message: "Secret created"},
+	}})
+	values := []SecretValue{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}, {Name: "C", Value: "3"}}
+	got, err := SetSecrets(context.Background(), rs.srv.URL, "tok", values, "myapp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.path != "/api/deploy/secrets/batch" {
+		t.Errorf("path = %s", rs.path)
+	}
+	var sent map[string]any
+	json.Unmarshal(rs.body, &sent)
+	if sent["deployment_alias"] != "myapp" || len(sent["secrets"].([]any)) != 3 {
+		t.Errorf("body = %s", rs.body)
+	}
+	if len(got) != 3 || got[0].Message != "Secret created" || got[1].Error == "" || got[2].Name != "C" || got[2].Error == "" {
+		t.Errorf("results = %+v", got)
+	}
+}
+
+func TestSetSecrets_FallsBackWithoutBatchEndpoint(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/api/deploy/secrets/batch" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["name"] == "BAD" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: APIError{Code: "INVALID_NAME", Message: "bad name"}})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SecretCreateResponse{Message: "Secret " + body["name"] + " created"})
+	}))
+	defer srv.Close()
+
+	got, err := SetSecrets(context.Background(), srv.URL, "tok", []SecretValue{{Name: "A", Value: "1"}, {Name: "BAD", Value: "2"}}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(paths, ",") != "/api/deploy/secrets/batch,/api/deploy/secrets,/api/deploy/secrets" {
+		t.Errorf("paths = %q", paths)
+	}
+	if got[0].Message != "Secret A created" || !strings.Contains(got[1].Error, "INVALID_NAME") {
+		t.Errorf("results = %+v", got)
+	}
+}
+
+func TestGetSecretHistory_ForwardsScope(t *testing.T) {
+	rs := newRecorder(t, http.StatusOK, SecretHistoryResponse{Name: "API_KEY", Versions: []SecretVersion{{Version: 2, Current: true}, {Version: 1}}})
+	got, err := GetSecretHistory(context.Background(), rs.srv.URL, "tok", "API_KEY", "myapp", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.method != "GET" || rs.path != "/api/deploy/secrets/API_KEY/versions" || rs.query != "deployment=myapp&service=web" {
+		t.Errorf("request = %s %s?%s", rs.method, rs.path, rs.query)
+	}
+	if len(got.Versions) != 2 || !got.Versions[0].Current {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestRollbackSecret_SendsVersion(t *testing.T) {
+	rs := newRecorder(t, http.StatusOK, SecretCreateResponse{Status: "success", Message: "Rolled back to version 1"})
+	if _, err := RollbackSecret(context.Background(), rs.srv.URL, "tok", "API_KEY", 1, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if rs.method != "POST" || rs.path != "/api/deploy/secrets/API_KEY/rollback" || string(rs.body) != `{"version":1}` {
+		t.Errorf("request = %s %s %s", rs.method, rs.path, rs.body)
+	}
+}
+
+func TestGetSecretDigests(t *testing.T) {
+	var gotKey, gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey, gotPath, gotQuery = r.Header.Get(DigestKeyHeader), r.URL.Path, r.URL.RawQuery
+		json.NewEncoder(w).Encode(map[string]any{"digests": map[string]string{"API_KEY": Digest(gotKey, "s3cret")}})
+	}))
+	defer srv.Close()
+
+	key := NewDigestKey()
+	got, err := GetSecretDigests(context.Background(), srv.URL, "tok", key, "myapp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/api/deploy/secrets/digests" || gotQuery != "deployment=myapp" || gotKey != key {
+		t.Errorf("request = %s?%s key %q", gotPath, gotQuery, gotKey)
+	}
+	if got["API_KEY"] != Digest(key, "s3cret") {
+		t.Errorf("got %v", got)
+	}
+	if Digest(NewDigestKey(), "s3cret") == got["API_KEY"] {
+		t.Error("digests under different keys match")
+	}
+}
+
+func TestGetSecretDigests_NoValuesWithoutDigestsEndpoint(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if _, err := GetSecretDigests(context.Background(), srv.URL, "tok", NewDigestKey(), "", ""); err == nil || !strings.Contains(err.Error(), "no digests endpoint") {
+		t.Fatalf("err = %v", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("requests = %q, want only the digests endpoint", paths)
+	}
+}
+
+func TestParseSecretsFile(t *testing.T) {
+	env := map[string]string{"API_KEY": "k-123", "HOST": "db"}
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+
+	got, err := ParseSecretsFile([]byte("secrets:\n  PORT: 5432\n  API_KEY: ${API_KEY}\n  URL: postgres://${HOST}:5432/app\n"), lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SecretValue{{Name: "API_KEY", Value: "k-123"}, {Name: "PORT", Value: "5432"}, {Name: "URL", Value: "postgres://db:5432/app"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{
+		"secrets:\n  TOKEN: ${MISSING}\n",
+		"secrets:\n  EMPTY: \"\"\n",
+		"secret:\n  A: b\n",
+		"secrets: {}\n",
+		"secrets: [a, b]\n",
+	} {
+		if _, err := ParseSecretsFile([]byte(bad), lookup); err == nil {
+			t.Errorf("%q: want error", bad)
+		}
+	}
+}
+
+func TestGenerateValue(t *testing.T) {
+	for gen, re := range map[string]string{
+		GeneratorHex32:  `^[0-9a-f]{64}$`,
+		GeneratorUUID:   `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		GeneratorBase64: `^[A-Za-z0-9_-]{64}$`,
+	} {
+		a, err := GenerateValue(gen)
+		if err != nil {
+			t.Fatalf("%s: %v", gen, err)
+		}
+		b, _ := GenerateValue(gen)
+		if !regexp.MustCompile(re).MatchString(a) || a == b {
+			t.Errorf("%s: got %q and %q", gen, a, b)
+		}
+	}
+	if _, err := GenerateValue("hex16"); err == nil {
+		t.Error("unknown generator: want error")
+	}
+}
@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/dibbla-agents/dibbla-cli/internal/apiclient"
+)
+
+// SecretVersion is one value a secret has had. Values are never listed;
+// roll back to a version to use it again.
+type SecretVersion struct {
+	Version   int    `json:"version"`
+	Action    string `json:"action"` // set or rollback
+	CreatedAt string `json:"created_at"`
+	CreatedBy string `json:"created_by,omitempty"`
+	// RolledBackFrom is the version a rollback restored.
+	RolledBackFrom int  `json:"rolled_back_from,omitempty"`
+	Current        bool `json:"current,omitempty"`
+}
+
+// SecretHistoryResponse is the response for a secret's history, newest
+// version first.
+type SecretHistoryResponse struct {
+	Name            string          `json:"name"`
+	DeploymentAlias string          `json:"deployment_alias"`
+	ServiceName     string          `json:"service_name,omitempty"`
+	Versions        []SecretVersion `json:"versions"`
+}
+
+// scopeQuery is the query selecting a secret's deployment and service
+// scope, as for GetSecret.
+func scopeQuery(deployment, service string) url.Values {
+	query := url.Values{}
+	if deployment != "" {
+		query.Set("deployment", deployment)
+	}
+	if service != "" {
+		query.Set("service", service)
+	}
+	return query
+}
+
+// GetSecretHistory lists the versions of a secret in a scope (see
+// GetSecret).
+func GetSecretHistory(ctx context.Context, apiURL, apiToken, name, deployment, service string) (*SecretHistoryResponse, error) {
+	var out SecretHistoryResponse
+	path := "/api/deploy/secrets/" + url.PathEscape(name) + "/versions"
+	if err := versionsRequest(ctx, apiURL, apiToken, "GET", path, scopeQuery(deployment, service), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RollbackSecret makes an earlier version's value the secret's current one.
+// This adds a new version, so the rollback can be undone in turn.
+func RollbackSecret(ctx context.Context, apiURL, apiToken, name string, version int, deployment, service string) (*SecretCreateResponse, error) {
+	var out SecretCreateResponse
+	path := "/api/deploy/secrets/" + url.PathEscape(name) + "/rollback"
+	body := map[string]int{"version": version}
+	if err := versionsRequest(ctx, apiURL, apiToken, "POST", path, scopeQuery(deployment, service), body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// versionsRequest sends a JSON request about secret versions and decodes
+// the 200 response into out.
+func versionsRequest(ctx context.Context, apiURL, apiToken, method, path string, query url.Values, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		raw, _ := json.Marshal(body)
+		reqBody = bytes.NewReader(raw)
+	}
+	client := &http.Client{Timeout: requestTimeout}
+	req, err := http.NewRequestWithContext(ctx, method, makeAPIURL(apiURL, path, query), reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiclient.Do(client, req)
+	if err != nil {
+		return fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return parseError(respBody, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
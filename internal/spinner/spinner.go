@@ -5,13 +5,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dibbla-agents/dibbla-cli/internal/interrupt"
 	"github.com/dibbla-agents/dibbla-cli/internal/platform"
 )
 
 // Start begins a spinner animation with the given message and optional ANSI
 // color code (e.g. "\033[32m" for green, "" for no color).
 // In CI environments it prints the message once with no animation.
-// Returns a stop function that must be called to end the spinner.
+// Returns a stop function that must be called to end the spinner; it also
+// runs if the command is interrupted, so Ctrl-C doesn't leave a frame behind.
 func Start(message string, color string) func() {
 	if platform.IsCI() {
 		fmt.Printf("%s...\n", message)
@@ -19,9 +21,11 @@ func Start(message string, color string) func() {
 	}
 
 	done := make(chan struct{})
+	finished := make(chan struct{})
 	var once sync.Once
 
 	go func() {
+		defer close(finished)
 		if platform.SupportsUnicode() {
 			frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 			i := 0
@@ -57,7 +61,14 @@ func Start(message string, color string) func() {
 		}
 	}()
 
-	return func() {
-		once.Do(func() { close(done) })
+	var remove func()
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+			remove()
+		})
 	}
+	remove = interrupt.OnCleanup(stop)
+	return stop
 }
@@ -27,6 +27,13 @@ type Template struct {
 	TemplatePath string `json:"template_path"`
 	IconURL      string `json:"icon_url,omitempty"`
 	ReadmeURL    string `json:"readme_url,omitempty"`
+	// Languages are the main languages of the template, e.g. "go".
+	Languages []string `json:"languages,omitempty"`
+	// UpdatedAt is when the template last changed (RFC 3339 or YYYY-MM-DD).
+	UpdatedAt string `json:"updated_at,omitempty"`
+	// Community marks templates maintained outside Dibbla; the rest are
+	// official.
+	Community bool `json:"community,omitempty"`
 }
 
 // ManifestURL returns the effective manifest URL (env override wins).
@@ -0,0 +1,139 @@
+// Package tunnel carries TCP connections over the deploy API's WebSocket
+// endpoints, for app port-forwards, database tunnels and exec sessions.
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// errorResponse is the deploy API's error body.
+type errorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Dial opens a WebSocket to the deploy API endpoint at path
+// (relative to /api/deploy), turning a refused handshake into the usual
+// "API error (CODE): message" error.
+func Dial(ctx context.Context, apiURL, path string, q url.Values, apiToken string) (*websocket.Conn, error) {
+	u, err := url.Parse(strings.TrimSuffix(apiURL, "/") + "/api/deploy" + path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api url: %w", err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	u.RawQuery = q.Encode()
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+apiToken)
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			var errResp errorResponse
+			if json.Unmarshal(body, &errResp) == nil && errResp.Error.Code != "" {
+				return nil, fmt.Errorf("API error (%s): %s", errResp.Error.Code, errResp.Error.Message)
+			}
+			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	return conn, nil
+}
+
+// Forward accepts connections on ln until ctx is cancelled and pipes each
+// one through its own WebSocket to path (relative to /api/deploy) with
+// query q. Per-connection failures are reported on notice and don't stop
+// the listener. ln is closed on return.
+func Forward(ctx context.Context, apiURL, apiToken, path string, q url.Values, ln net.Listener, notice io.Writer) error {
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	defer stop()
+	defer ln.Close()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		local, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer local.Close()
+			remote, err := Dial(ctx, apiURL, path, q, apiToken)
+			if err != nil {
+				fmt.Fprintf(notice, "tunnel: %s: %v\n", local.RemoteAddr(), err)
+				return
+			}
+			defer remote.Close()
+			pipe(ctx, local, remote)
+		}()
+	}
+}
+
+// pipe copies bytes between a TCP connection and a WebSocket of
+// binary messages until either side closes or ctx is cancelled.
+func pipe(ctx context.Context, local net.Conn, remote *websocket.Conn) {
+	done := make(chan struct{}, 2)
+	stop := context.AfterFunc(ctx, func() {
+		local.Close()
+		remote.Close()
+	})
+	defer stop()
+
+	go func() {
+		defer func() { done <- struct{}{} }()
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := local.Read(buf)
+			if n > 0 {
+				if remote.WriteMessage(websocket.BinaryMessage, buf[:n]) != nil {
+					return
+				}
+			}
+			if err != nil {
+				_ = remote.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+		}
+	}()
+	go func() {
+		defer func() { done <- struct{}{} }()
+		for {
+			_, msg, err := remote.ReadMessage()
+			if err != nil {
+				return
+			}
+			if _, err := local.Write(msg); err != nil {
+				return
+			}
+		}
+	}()
+	// Once either direction ends the tunnel is done; closing both ends
+	// unblocks the other copier.
+	<-done
+	local.Close()
+	remote.Close()
+	<-done
+}